@@ -0,0 +1,63 @@
+package v1
+
+// --- admin API ---
+
+// AdminSession is a session with the engine state and statistics operators need
+type AdminSession struct {
+	Session
+	LevelCompletionState string     `json:"level_completion"`
+	Mode                 string     `json:"mode"`
+	StateVersion         uint64     `json:"state_version"`
+	ValidationEnabled    bool       `json:"validation_enabled"`
+	TurnHistoryEnabled   bool       `json:"turn_history_enabled"`
+	Corrupted            string     `json:"corrupted,omitempty"` // why the session's game state can't be trusted, if it can't
+	Checkpoints          int        `json:"checkpoints"`
+	Stats                AdminStats `json:"stats"`
+}
+
+type AdminStats struct {
+	Turns           int `json:"turns"`
+	EnemiesDefeated int `json:"enemies_defeated"`
+	DamageTaken     int `json:"damage_taken"`
+	SecretsFound    int `json:"secrets_found"`
+}
+
+type AdminListSessionsResponse struct {
+	Sessions []AdminSession `json:"sessions"`
+}
+
+// CacheStateResponse reports how full the server's level cache is
+type CacheStateResponse struct {
+	Enabled  bool `json:"enabled"`
+	Levels   int  `json:"levels"`   // levels cached
+	Capacity int  `json:"capacity"` // levels the cache holds before dropping the least recently used
+}
+
+type SetValidationRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+type SetValidationResponse struct {
+	SessionID         string `json:"session_id"`
+	ValidationEnabled bool   `json:"validation_enabled"`
+}
+
+type SetTurnHistoryRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+type SetTurnHistoryResponse struct {
+	SessionID          string `json:"session_id"`
+	TurnHistoryEnabled bool   `json:"turn_history_enabled"`
+}
+
+// RewindRequest names the turn to rewind a session to, 0 being the start of the game
+type RewindRequest struct {
+	Turn *int `json:"turn" binding:"required"`
+}
+
+type RewindResponse struct {
+	SessionID       string `json:"session_id"`
+	Turn            int    `json:"turn"`
+	EngineStateInfo `json:"engine_state"`
+}
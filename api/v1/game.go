@@ -1,47 +1,197 @@
 package v1
 
 import (
-	"adventure-engine/internal/engine"
+	"adventure-engine/internal/narrator"
+	"adventure-engine/internal/parser"
+	"adventure-engine/pkg/engine"
+	"adventure-engine/pkg/loader"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 )
 
 // --- engine state ---
 
+// ErrorResponse is returned when the engine rejects an action.
+// ErrorCode is a machine-readable reason such as not_found, locked or wrong_mode,
+// DidYouMean lists the candidates when a name matched more than one item or door,
+// and Action is the parsed action for free text commands.
+type ErrorResponse struct {
+	Error      string         `json:"error"`
+	ErrorCode  string         `json:"error_code"`
+	DidYouMean []string       `json:"did_you_mean,omitempty"`
+	Action     *CommandAction `json:"action,omitempty"`
+}
+
+// StaleStateResponse is returned with 409 when a request's If-Match header names
+// a state version other than the current one.
+type StaleStateResponse struct {
+	Error        string `json:"error"`
+	StateVersion uint64 `json:"state_version"`
+}
+
 type FightingEnemy struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	ImageRef    string `json:"image_ref,omitempty"`
 	HP          int    `json:"hp"`
 }
 
 type EngineStateInfo struct {
-	LevelCompletionState string         `json:"level_completion"`
-	Mode                 string         `json:"mode"`
-	CurrentLevel         string         `json:"current_level"`
-	CurrentFloor         string         `json:"current_floor"`
-	CurrentRoom          string         `json:"current_room"`
-	PlayerHealth         string         `json:"player_health"`
-	FightingEnemy        *FightingEnemy `json:"fighting_enemy,omitempty"`
-	Notification         string         `json:"notification,omitempty"`
-	OutroNarrative       string         `json:"outro_narrative,omitempty"`
+	LevelCompletionState string           `json:"level_completion"`
+	Mode                 string           `json:"mode"`
+	CurrentLevel         string           `json:"current_level"`
+	CurrentFloor         string           `json:"current_floor"`
+	CurrentRoom          string           `json:"current_room"`
+	PlayerHealth         string           `json:"player_health"`
+	FightingEnemy        *FightingEnemy   `json:"fighting_enemy,omitempty"`
+	Notification         string           `json:"notification,omitempty"`  // the most important of the notifications
+	Notifications        []string         `json:"notifications,omitempty"` // every state change the action caused, in the order they happened
+	OutroNarrative       string           `json:"outro_narrative,omitempty"`
+	FailureNarrative     string           `json:"failure_narrative,omitempty"` // set once the level has failed
+	PostMortem           *PostMortemInfo  `json:"postmortem,omitempty"`        // set once the level has failed
+	Score                *ScoreInfo       `json:"score,omitempty"`
+	Stats                *StatsInfo       `json:"stats,omitempty"`
+	Victory              *VictoryInfo     `json:"victory,omitempty"` // set once the level is complete
+	Objectives           []ObjectiveInfo  `json:"objectives,omitempty"`
+	StateVersion         uint64           `json:"state_version"`
+	Player               string           `json:"player,omitempty"`
+	NextPlayer           string           `json:"next_player,omitempty"`
+	Ambient              string           `json:"ambient,omitempty"`
+	SoundCues            []SoundCue       `json:"sound_cues,omitempty"`
+	Breath               *BreathInfo      `json:"breath,omitempty"`       // set while the player is in an airless room
+	RespawnRoom          string           `json:"respawn_room,omitempty"` // where the player respawns should they die, in respawn sessions
+	CanRespawn           bool             `json:"can_respawn,omitempty"`  // the level has failed, but the player can respawn at a save point
+	AllowedActions       []string         `json:"allowed_actions"`        // the actions the player can take now, named as their endpoints
+	Weapons              []WeaponInfo     `json:"weapons,omitempty"`      // in combat, the weapons the player can fight with
+	Armor                []ArmorInfo      `json:"armor,omitempty"`        // the armor the player wears
+	Exploration          *ExplorationInfo `json:"exploration,omitempty"`  // how much of the level has been explored
+}
+
+// ExplorationInfo is how much of the level the players have explored, for completion meters.
+type ExplorationInfo struct {
+	RoomsExplored int `json:"rooms_explored"`
+	Rooms         int `json:"rooms"`
+	Percent       int `json:"percent"` // of the rooms explored, rounded down
+	SecretsFound  int `json:"secrets_found"`
+	Secrets       int `json:"secrets"` // secret items and hidden doors, found or not
+}
+
+// WeaponInfo is a weapon the player can fight with, fists included.
+type WeaponInfo struct {
+	Name     string `json:"name"`
+	UsesAmmo bool   `json:"uses_ammo,omitempty"`
+	Ammo     int    `json:"ammo,omitempty"`   // rounds left, for weapons that use ammo
+	Thrown   int    `json:"thrown,omitempty"` // how many are left to throw, for thrown weapons
+}
+
+// ArmorInfo is a piece of armor the player wears.
+type ArmorInfo struct {
+	Name       string  `json:"name"`
+	Slot       string  `json:"slot"`
+	Block      float64 `json:"block"`                // chance of stopping a hit
+	Durability int     `json:"durability,omitempty"` // hits left before it breaks, omitted for armor that never does
+}
+
+// BreathInfo tells how many more actions the player can take in airless rooms before they drown.
+type BreathInfo struct {
+	Left     int `json:"left"`
+	Capacity int `json:"capacity"`
+}
+
+// SoundCue is a sound for clients with audio to play in response to an action.
+// Event is the action that played it, or "enter" for a room the player entered.
+type SoundCue struct {
+	Sound  string `json:"sound"`
+	Source string `json:"source"` // the item or room playing the sound
+	Event  string `json:"event"`
 }
 
 // --- session management ---
 
+// CreateSessionRequest creates a session on a level, given either as Level or as the
+// LevelName of a bundled level. If CallbackURL is set, the server posts a WebhookNotification to it whenever an action
+// changes the engine state. Narration adds prose narration to action responses. Language selects one of the level's
+// languages for its text, which defaults to the level's own.
 type CreateSessionRequest struct {
-	Level json.RawMessage `json:"level"`
+	Level       json.RawMessage `json:"level,omitempty"`
+	LevelName   string          `json:"level_name,omitempty"`
+	Seed        *uint64         `json:"seed,omitempty"`
+	TurnPolicy  string          `json:"turn_policy,omitempty" binding:"omitempty,oneof=free round_robin"`
+	DeathPolicy string          `json:"death_policy,omitempty" binding:"omitempty,oneof=permadeath respawn"`
+	Combat      string          `json:"combat,omitempty" binding:"omitempty,oneof=probabilistic deterministic opposed"` // overrides the level's combat model
+	CallbackURL string          `json:"callback_url,omitempty" binding:"omitempty,url"`
+	Narration   bool            `json:"narration,omitempty"`
+	Language    string          `json:"language,omitempty"`
 }
 
 type CreateSessionResponse struct {
 	SessionID      string `json:"session_id"`
+	Seed           uint64 `json:"seed"`
+	Theme          string `json:"theme,omitempty"`
 	IntroNarrative string `json:"intro_narrative,omitempty"`
 }
 
+// GenerateSessionRequest creates a session on a procedurally generated level.
+// Difficulty selects preset parameters, which the optional fields override.
+type GenerateSessionRequest struct {
+	Seed        *uint64 `json:"seed,omitempty"`
+	Difficulty  string  `json:"difficulty,omitempty" binding:"omitempty,oneof=easy normal hard"`
+	Rooms       *int    `json:"rooms,omitempty"`
+	Locks       *int    `json:"locks,omitempty"`
+	Enemies     *int    `json:"enemies,omitempty"`
+	EnemyHP     *int    `json:"enemy_hp,omitempty"`
+	TurnPolicy  string  `json:"turn_policy,omitempty" binding:"omitempty,oneof=free round_robin"`
+	DeathPolicy string  `json:"death_policy,omitempty" binding:"omitempty,oneof=permadeath respawn"`
+	Combat      string  `json:"combat,omitempty" binding:"omitempty,oneof=probabilistic deterministic opposed"` // overrides the level's combat model
+	CallbackURL string  `json:"callback_url,omitempty" binding:"omitempty,url"`
+	Narration   bool    `json:"narration,omitempty"`
+	Language    string  `json:"language,omitempty"`
+}
+
+type GenerateSessionResponse struct {
+	CreateSessionResponse
+	Level json.RawMessage `json:"level"`
+}
+
 type Session struct {
 	ID        string `json:"id"`
 	LevelName string `json:"level_name"`
+	Theme     string `json:"theme,omitempty"`
+	Language  string `json:"language"`
+	Owner     string `json:"owner,omitempty"`
 	CreatedAt string `json:"created_at"`
 }
 
+// WebhookNotification is posted to a session's callback URL when an action changes the engine
+// state, once for each change. Notification is one of level_complete, level_failed, respawned,
+// enter_combat, exit_combat and secret_discovered.
+type WebhookNotification struct {
+	SessionID    string          `json:"session_id"`
+	Notification string          `json:"notification"`
+	SentAt       string          `json:"sent_at"`
+	EngineState  EngineStateInfo `json:"engine_state"`
+}
+
+type SetNarrationRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+type SetNarrationResponse struct {
+	SessionID        string `json:"session_id"`
+	NarrationEnabled bool   `json:"narration_enabled"`
+}
+
+type SetLanguageRequest struct {
+	Language string `json:"language" binding:"required"`
+}
+
+type SetLanguageResponse struct {
+	SessionID string `json:"session_id"`
+	Language  string `json:"language"`
+}
+
 type ListSessionsResponse struct {
 	Sessions []Session `json:"sessions"`
 }
@@ -60,13 +210,263 @@ type DebugResponse struct {
 	Debug   json.RawMessage `json:"debug"`
 }
 
+type ExportLevelResponse struct {
+	Level json.RawMessage `json:"level"`
+}
+
+type ScoreResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	Score           ScoreInfo `json:"score"`
+}
+
+type StatsResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	Stats           StatsInfo `json:"stats"`
+}
+
+type PostMortemResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	PostMortem      PostMortemInfo `json:"postmortem"`
+}
+
+// DiffResponse is what changed since an earlier state version, as the acting player sees it
+type DiffResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	Since           uint64          `json:"since"`
+	Rooms           []RoomItemsDiff `json:"rooms,omitempty"` // rooms whose items changed
+	Inventory       ItemsDiff       `json:"inventory"`
+	DoorsUnlocked   []string        `json:"doors_unlocked,omitempty"`
+	DoorsLocked     []string        `json:"doors_locked,omitempty"`
+	DoorsRevealed   []string        `json:"doors_revealed,omitempty"`
+	Health          *HealthChange   `json:"health,omitempty"`
+	PreviousRoom    string          `json:"previous_room,omitempty"` // set if the player has moved
+}
+
+type ItemsDiff struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+type RoomItemsDiff struct {
+	RoomName string `json:"room_name"`
+	ItemsDiff
+}
+
+type HealthChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type ObjectivesResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	Objectives      []ObjectiveInfo `json:"objectives"`
+}
+
+type ObjectiveInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+}
+
+type RecipesResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	Recipes         []RecipeInfo `json:"recipes"`
+}
+
+type RecipeInfo struct {
+	InputNames  []string `json:"input_names"`
+	FixtureName string   `json:"fixture_name,omitempty"`
+	OutputName  string   `json:"output_name,omitempty"` // empty until the recipe is discovered
+	Discovered  bool     `json:"discovered"`
+}
+
+type ScoreInfo struct {
+	Score           int         `json:"score"`
+	Final           bool        `json:"final"`
+	TurnsTaken      int         `json:"turns_taken"`
+	EnemiesDefeated int         `json:"enemies_defeated"`
+	DamageTaken     int         `json:"damage_taken"`
+	SecretsFound    int         `json:"secrets_found"`
+	Badges          []BadgeInfo `json:"badges,omitempty"`
+}
+
+// StatsInfo holds the statistics for a session. Actions and room turns only count actions
+// that take a turn.
+type StatsInfo struct {
+	TurnsTaken      int            `json:"turns_taken"`
+	Actions         map[string]int `json:"actions"`      // action name -> times taken
+	DamageDealt     int            `json:"damage_dealt"` // rounds won in battle
+	DamageTaken     int            `json:"damage_taken"`
+	EnemiesDefeated int            `json:"enemies_defeated"`
+	EnemiesSpared   int            `json:"enemies_spared"` // intimidated or bribed into leaving
+	SecretsFound    int            `json:"secrets_found"`
+	RoomsVisited    int            `json:"rooms_visited"`
+	ItemsTaken      int            `json:"items_taken"`
+	RoomTurns       map[string]int `json:"room_turns"` // room name -> turns spent in the room
+}
+
+// VictoryInfo lists what the player left undone on completing the level.
+type VictoryInfo struct {
+	SecretsMissed   []string `json:"secrets_missed,omitempty"`
+	EnemiesAlive    []string `json:"enemies_alive,omitempty"`
+	RoomsUnexplored []string `json:"rooms_unexplored,omitempty"`
+	ItemsNotFound   []string `json:"items_not_found,omitempty"` // portable items never taken, other than secrets
+}
+
+// PostMortemInfo tells how the player died, for game over screens.
+type PostMortemInfo struct {
+	Cause         string       `json:"cause"`                // killed_by_enemy, suffocated or trapped
+	EnemyName     string       `json:"enemy_name,omitempty"` // the enemy that killed the player
+	ItemName      string       `json:"item_name,omitempty"`  // the trapped item that killed the player
+	Room          string       `json:"room"`
+	LastActions   []ActionInfo `json:"last_actions"`   // the last actions taken, oldest first, up to ten
+	RoomsExplored int          `json:"rooms_explored"` // percentage of the level's rooms visited
+}
+
+// ActionInfo is an action a player took, with the names they gave it.
+type ActionInfo struct {
+	Name string   `json:"name"`
+	Args []string `json:"args,omitempty"`
+}
+
+type BadgeInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// --- level library ---
+
+type ListLevelsResponse struct {
+	Levels []LevelSummary `json:"levels"`
+}
+
+// LevelSummary describes a level in the library, either bundled with the server or uploaded
+// VerbAliases are the words the level's commands may use besides the built in verbs.
+type LevelSummary struct {
+	Name           string            `json:"name"`
+	Theme          string            `json:"theme,omitempty"`
+	IntroNarrative string            `json:"intro_narrative,omitempty"`
+	VerbAliases    map[string]string `json:"verb_aliases,omitempty"`
+	Bundled        bool              `json:"bundled,omitempty"`
+	Owner          string            `json:"owner,omitempty"`
+	UpdatedAt      string            `json:"updated_at,omitempty"`
+}
+
+type LeaderboardResponse struct {
+	Level string           `json:"level"`
+	Runs  []LeaderboardRun `json:"runs"`
+}
+
+// LeaderboardRun is a completed run of a level, ranked by score, then turns, then duration
+type LeaderboardRun struct {
+	Rank            int     `json:"rank"`
+	Player          string  `json:"player,omitempty"`
+	SessionID       string  `json:"session_id"`
+	Turns           int     `json:"turns"`
+	Score           int     `json:"score"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	CompletedAt     string  `json:"completed_at"`
+}
+
+type GetLevelResponse struct {
+	LevelSummary `json:"summary"`
+	Level        json.RawMessage `json:"level"`
+}
+
+type PutLevelRequest struct {
+	Level json.RawMessage `json:"level" binding:"required"`
+}
+
+// PutLevelResponse is returned when a level is stored. Levels with validation errors are
+// rejected with a ValidateLevelResponse instead.
+type PutLevelResponse struct {
+	LevelSummary `json:"summary"`
+	Warnings     []LevelDiagnostic `json:"warnings"`
+}
+
+type DeleteLevelResponse struct {
+	Name string `json:"name"`
+}
+
+// --- level validation ---
+
+type ValidateLevelRequest struct {
+	Level json.RawMessage `json:"level"`
+}
+
+type ValidateLevelResponse struct {
+	Valid    bool              `json:"valid"`
+	Errors   []LevelDiagnostic `json:"errors"`
+	Warnings []LevelDiagnostic `json:"warnings"`
+}
+
+type LevelDiagnostic struct {
+	Severity string `json:"severity"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+}
+
+// --- checkpoints ---
+
+type Checkpoint struct {
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+}
+
+type CreateCheckpointRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type CreateCheckpointResponse struct {
+	Checkpoint `json:"checkpoint"`
+}
+
+type ListCheckpointsResponse struct {
+	Checkpoints []Checkpoint `json:"checkpoints"`
+}
+
+type RestoreCheckpointResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	Restored        string `json:"restored_checkpoint"`
+}
+
+// RespawnResponse describes the save point room a player who died is back in.
+type RespawnResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	RoomInfo        RoomInfo `json:"room_info"`
+}
+
+// --- players ---
+
+type AddPlayerRequest struct {
+	ID string `json:"id" binding:"required"`
+}
+
+type PlayersResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	Players         []PlayerInfo `json:"players"`
+}
+
+type PlayerInfo struct {
+	ID            string `json:"id"`
+	CurrentFloor  string `json:"current_floor"`
+	CurrentRoom   string `json:"current_room"`
+	Health        string `json:"health"`
+	Mode          string `json:"mode"`
+	InventorySize int    `json:"inventory_size"`
+	HasTurn       bool   `json:"has_turn,omitempty"`
+}
+
 // --- game actions ---
+// Action responses carry a prose narration of the result when the session has narration on.
 
 type ObserveRequest struct{}
 
 type ObserveResponse struct {
 	EngineStateInfo `json:"engine_state"`
+	Narration       string   `json:"narration,omitempty"`
 	RoomInfo        RoomInfo `json:"room_info,omitempty"`
+	NextCursor      string   `json:"next_cursor,omitempty"` // the cursor of the next page of items, if the items were paged
 }
 
 type InspectRequest struct {
@@ -75,8 +475,10 @@ type InspectRequest struct {
 
 type InspectResponse struct {
 	EngineStateInfo `json:"engine_state"`
+	Narration       string    `json:"narration,omitempty"`
 	ItemInfo        *ItemInfo `json:"item_info,omitempty"`
 	DoorInfo        *DoorInfo `json:"door_info,omitempty"`
+	LearnedRecipes  []string  `json:"learned_recipes,omitempty"` // outputs of the recipes the item taught
 }
 
 type UncoverRequest struct {
@@ -85,6 +487,7 @@ type UncoverRequest struct {
 
 type UncoverResponse struct {
 	EngineStateInfo `json:"engine_state"`
+	Narration       string   `json:"narration,omitempty"`
 	RevealedItem    ItemInfo `json:"revealed_item"`
 }
 
@@ -95,7 +498,9 @@ type UnlockRequest struct {
 
 type UnlockResponse struct {
 	EngineStateInfo `json:"engine_state"`
-	Unlocked        bool `json:"unlocked"`
+	Narration       string `json:"narration,omitempty"`
+	Unlocked        bool   `json:"unlocked"`
+	Jammed          bool   `json:"jammed,omitempty"` // a wrong code jammed the keypad for good
 }
 
 type SearchRequest struct {
@@ -104,35 +509,87 @@ type SearchRequest struct {
 
 type SearchResponse struct {
 	EngineStateInfo `json:"engine_state"`
+	Narration       string    `json:"narration,omitempty"`
 	ContainedItem   *ItemInfo `json:"contained_item,omitempty"`
 	IsEmpty         bool      `json:"is_empty,omitempty"`
 	Unlocked        bool      `json:"unlocked,omitempty"`
 }
 
+type InterrogateRequest struct {
+	EnemyName string `json:"enemy_name" binding:"required"`
+}
+
+// InterrogateResponse is the response to questioning an enemy that surrendered.
+type InterrogateResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	Narration       string `json:"narration,omitempty"`
+	EnemyName       string `json:"enemy_name"`
+	Answer          string `json:"answer,omitempty"` // empty if the enemy has nothing to say
+}
+
 type TakeRequest struct {
 	TargetName string `json:"target_name" binding:"required"`
+	Quantity   int    `json:"quantity,omitempty"` // how many of a stack to take, all of it if omitted
 }
 
 type TakeResponse struct {
 	EngineStateInfo `json:"engine_state"`
-	TakenItem       ItemInfo `json:"added_to_inventory"`
+	Narration       string    `json:"narration,omitempty"`
+	TakenItem       ItemInfo  `json:"added_to_inventory"`
+	Trap            *TrapInfo `json:"trap,omitempty"` // the trap the item sprang, if it had one
 }
 
 type InventoryRequest struct{}
 
 type InventoryResponse struct {
 	EngineStateInfo `json:"engine_state"`
+	Narration       string      `json:"narration,omitempty"`
 	Inventory       []ItemInfo  `json:"inventory"`
 	Ammo            []AmmoCount `json:"ammo"`
 }
 
+// StatusResponse tells how the player is doing.
+type StatusResponse struct {
+	EngineStateInfo   `json:"engine_state"`
+	Narration         string     `json:"narration,omitempty"`
+	Health            string     `json:"health"`
+	HealthDescription string     `json:"health_description"`
+	Effects           []string   `json:"effects"`         // in_combat, holding_breath and out_of_breath
+	Weapon            string     `json:"weapon"`          // the weapon at hand, fists if the player has none
+	Armor             []string   `json:"armor,omitempty"` // the armor the player wears
+	Carrying          int        `json:"carrying"`        // items carried, counting each in a stack
+	Breath            BreathInfo `json:"breath"`
+}
+
 type HealRequest struct {
 	HealthItemName string `json:"health_item_name" binding:"required"`
 }
 
 type HealResponse struct {
 	EngineStateInfo `json:"engine_state"`
+	Narration       string    `json:"narration,omitempty"`
+	HealthState     string    `json:"player_health"`
+	Breathed        bool      `json:"breathed,omitempty"`     // the item was an air supply the player breathed from
+	ChargesLeft     int       `json:"charges_left,omitempty"` // for an item with charges, omitted once it is used up
+	MaxCharges      int       `json:"max_charges,omitempty"`  // omitted for items without charges
+	Cured           bool      `json:"cured,omitempty"`        // healing cured the player of poison
+	Trap            *TrapInfo `json:"trap,omitempty"`         // the trap the item sprang, if it had one
+}
+
+// WaitRequest is how many turns to wait or rest for.
+type WaitRequest struct {
+	Turns int `json:"turns,omitempty" binding:"omitempty,min=1,max=10"` // 1 if omitted
+}
+
+// WaitResponse is how waiting or resting went.
+type WaitResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	Narration       string `json:"narration,omitempty"`
+	Turns           int    `json:"turns"` // fewer than asked for if something cut the wait short
+	Rested          bool   `json:"rested,omitempty"`
 	HealthState     string `json:"player_health"`
+	Healed          int    `json:"healed,omitempty"`    // steps of health recovered by resting
+	Encounter       string `json:"encounter,omitempty"` // the wandering enemy that came upon the resting player
 }
 
 type TraverseRequest struct {
@@ -141,32 +598,134 @@ type TraverseRequest struct {
 
 type TraverseResponse struct {
 	EngineStateInfo `json:"engine_state"`
+	Narration       string     `json:"narration,omitempty"`
 	EnteredRoom     RoomInfo   `json:"entered_room"`
 	ChangedFloor    *FloorInfo `json:"changed_floor,omitempty"`
 	Unlatched       bool       `json:"unlatched_door,omitempty"`
 	Unlocked        bool       `json:"unlocked_door,omitempty"`
 }
 
+type ListenRequest struct {
+	Door string `json:"door_or_direction" binding:"required"`
+}
+
+// ListenResponse is what the player hears behind a door. Movement means something is there;
+// only barred doors let the player make out the enemy.
+type ListenResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	Narration       string `json:"narration,omitempty"`
+	DoorName        string `json:"door_name"`
+	Ambient         string `json:"ambient,omitempty"`
+	Movement        bool   `json:"movement"`
+	EnemyName       string `json:"enemy_name,omitempty"`
+}
+
+type PeekRequest struct {
+	Door string `json:"door_or_direction" binding:"required"`
+}
+
+type PeekResponse struct {
+	EngineStateInfo  `json:"engine_state"`
+	Narration        string `json:"narration,omitempty"`
+	DoorName         string `json:"door_name"`
+	RoomName         string `json:"room_name"`
+	RoomDescription  string `json:"room_description"`
+	EnemyName        string `json:"enemy_name,omitempty"`
+	EnemyDescription string `json:"enemy_description,omitempty"`
+}
+
+type LatchRequest struct {
+	Door string `json:"door_or_direction" binding:"required"`
+}
+
+type LatchResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	Narration       string `json:"narration,omitempty"`
+	DoorName        string `json:"door_name"`
+	Latched         bool   `json:"latched"`
+}
+
 type BattleRequest struct {
 	WeaponName string `json:"weapon_name" binding:"required"`
 }
 
 type BattleResponse struct {
 	EngineStateInfo `json:"engine_state"`
-	EnemyName       string `json:"enemy_name"`
-	WonRound        bool   `json:"won_round"`
-	EnemyAlive      bool   `json:"enemy_alive"`
-	PlayerAlive     bool   `json:"player_alive"`
+	Narration       string            `json:"narration,omitempty"`
+	EnemyName       string            `json:"enemy_name"`
+	WonRound        bool              `json:"won_round"`
+	EnemyAlive      bool              `json:"enemy_alive"`
+	PlayerAlive     bool              `json:"player_alive"`
+	BrokenItem      string            `json:"broken_item,omitempty"` // the weapon, if the round wore it out
+	Scrap           *ItemInfo         `json:"scrap,omitempty"`       // added to the inventory in place of the broken weapon
+	Thrown          bool              `json:"thrown,omitempty"`      // the weapon was thrown and used up
+	Critical        bool              `json:"critical,omitempty"`    // the round won was a critical hit, costing the enemy extra hp
+	Fumbled         bool              `json:"fumbled,omitempty"`     // the player dropped the weapon, which lies in the room
+	Narrative       string            `json:"narrative,omitempty"`   // the weapon's narrative for the critical hit or fumble
+	Pity            bool              `json:"pity,omitempty"`        // the round was won because the player had lost too many in a row
+	Surrendered     bool              `json:"surrendered,omitempty"` // the enemy gave up, and can now be searched and interrogated
+	AlsoHit         []string          `json:"also_hit,omitempty"`    // other enemies in the room caught in the blast
+	AlsoKilled      []string          `json:"also_killed,omitempty"` // those of them the blast killed
+	StartedFire     bool              `json:"started_fire,omitempty"`
+	Absorbed        string            `json:"absorbed,omitempty"`     // the armor that stopped the enemy's hit
+	BrokenArmor     string            `json:"broken_armor,omitempty"` // the armor, if stopping the hit broke it
+	ArmorScrap      *ItemInfo         `json:"armor_scrap,omitempty"`  // added to the inventory in place of the broken armor
+	CombatLog       []CombatRoundInfo `json:"combat_log"`             // the rounds of the fight so far, this one last
+}
+
+// CombatRoundInfo is a round of battle, for recapping a fight.
+type CombatRoundInfo struct {
+	Round        int    `json:"round"`
+	Weapon       string `json:"weapon"`
+	WonRound     bool   `json:"won_round"`
+	Critical     bool   `json:"critical,omitempty"`
+	Damage       int    `json:"damage"`   // hp the enemy lost
+	EnemyHP      int    `json:"enemy_hp"` // hp the enemy had left
+	AmmoSpent    int    `json:"ammo_spent,omitempty"`
+	HealthBefore string `json:"health_before"`
+	HealthAfter  string `json:"health_after"`
+	Absorbed     string `json:"absorbed,omitempty"` // the armor that stopped the enemy's hit
+}
+
+// CombatLogResponse is the rounds of the current fight, or of the last one once it is over.
+type CombatLogResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	EnemyName       string            `json:"enemy_name"`
+	Rounds          []CombatRoundInfo `json:"rounds"`
+}
+
+type BribeRequest struct {
+	ItemName string `json:"item_name" binding:"required"`
+}
+
+// ResolveResponse is the response to intimidating, bribing or sneaking past an enemy.
+type ResolveResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	Narration       string    `json:"narration,omitempty"`
+	EnemyName       string    `json:"enemy_name"`
+	Approach        string    `json:"approach"`             // intimidate, bribe or sneak
+	Succeeded       bool      `json:"succeeded"`            // the fight is over
+	BribeItem       string    `json:"bribe_item,omitempty"` // handed over to the enemy
+	PlayerAlive     bool      `json:"player_alive"`
+	Absorbed        string    `json:"absorbed,omitempty"`     // the armor that stopped the enemy's hit when the attempt failed
+	BrokenArmor     string    `json:"broken_armor,omitempty"` // the armor, if stopping the hit broke it
+	ArmorScrap      *ItemInfo `json:"armor_scrap,omitempty"`  // added to the inventory in place of the broken armor
 }
 
+// CombineRequest names the items to combine, either two as item_a_name and item_b_name,
+// or two to four as item_names.
 type CombineRequest struct {
-	InputItemAName string `json:"item_a_name" binding:"required"`
-	InputItemBName string `json:"item_b_name" binding:"required"`
+	InputItemAName string   `json:"item_a_name"`
+	InputItemBName string   `json:"item_b_name"`
+	ItemNames      []string `json:"item_names"`
 }
 
 type CombineResponse struct {
 	EngineStateInfo `json:"engine_state"`
-	CraftedItem     ItemInfo `json:"crafted_item"`
+	Narration       string     `json:"narration,omitempty"`
+	CraftedItem     ItemInfo   `json:"crafted_item"`
+	Byproducts      []ItemInfo `json:"byproducts,omitempty"` // added to the inventory alongside the crafted item
+	NewRecipe       bool       `json:"new_recipe"`           // the recipe was discovered by trying it
 }
 
 type UseRequest struct {
@@ -176,82 +735,232 @@ type UseRequest struct {
 
 type UseResponse struct {
 	EngineStateInfo     `json:"engine_state"`
-	AcceptedItem        bool      `json:"accepted_item"`
-	ProducedItem        *ItemInfo `json:"produced_item,omitempty"`
-	CompletionNarrative string    `json:"fixture_complete_narrative,omitempty"`
+	Narration           string       `json:"narration,omitempty"`
+	AcceptedItem        bool         `json:"accepted_item"`
+	ProducedItem        *ItemInfo    `json:"produced_item,omitempty"`
+	CompletionNarrative string       `json:"fixture_complete_narrative,omitempty"`
+	InsertNarrative     string       `json:"insert_narrative,omitempty"`
+	StageNarrative      string       `json:"stage_narrative,omitempty"` // the item finished a stage of the fixture
+	MissingItems        []string     `json:"missing_items,omitempty"`   // still needed for the current stage, if the fixture names them
+	MissingCount        int          `json:"missing_count,omitempty"`   // still needed in all, unless the fixture hides them
+	BrokenItem          string       `json:"broken_item,omitempty"`     // the used tool, if it wore out
+	Scrap               *ItemInfo    `json:"scrap,omitempty"`           // added to the inventory in place of the broken tool
+	ChargesLeft         int          `json:"charges_left,omitempty"`    // for a used item with charges, omitted once it is used up
+	MaxCharges          int          `json:"max_charges,omitempty"`     // omitted for items without charges
+	Effects             []EffectInfo `json:"effects,omitempty"`         // what completing the fixture did to the level
+	Trap                *TrapInfo    `json:"trap,omitempty"`            // the trap the used item sprang, if it had one
+}
+
+// EffectInfo describes an effect of completing a fixture or springing a trap.
+type EffectInfo struct {
+	Effect string `json:"effect"`           // unlock, reveal_door, end_combat, complete_level or power, or for traps damage, poison or enter_combat
+	Target string `json:"target,omitempty"` // the door, container, enemy, travel node or trapped item acted on
+}
+
+// TrapInfo describes a trap an item sprang on the player.
+type TrapInfo struct {
+	ItemName    string       `json:"item_name"`
+	Narrative   string       `json:"narrative,omitempty"`
+	Effects     []EffectInfo `json:"effects"`
+	HealthState string       `json:"player_health"` // once the trap had sprung
+}
+
+type MoveRequest struct {
+	ItemName  string `json:"item_name" binding:"required"`
+	Direction string `json:"direction,omitempty"` // such as push, pull or left; any way the furniture goes if omitted
+}
+
+type MoveResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	Narration       string    `json:"narration,omitempty"`
+	Narrative       string    `json:"narrative,omitempty"`
+	RevealedItem    *ItemInfo `json:"revealed_item,omitempty"`
+	RevealedDoor    string    `json:"revealed_door,omitempty"`
+}
+
+type TravelRequest struct {
+	NodeName string `json:"node_name" binding:"required"` // the destination node, or its room
+}
+
+type TravelResponse struct {
+	EngineStateInfo `json:"engine_state"`
+	Narration       string     `json:"narration,omitempty"`
+	FromNode        string     `json:"from_node"`
+	ToNode          string     `json:"to_node"`
+	EnteredRoom     RoomInfo   `json:"entered_room"`
+	ChangedFloor    *FloorInfo `json:"changed_floor,omitempty"`
+}
+
+type CommandRequest struct {
+	Text string `json:"text" binding:"required"`
+}
+
+// CommandResponse contains the action a free text command was parsed into and the
+// response of the matching action endpoint.
+type CommandResponse struct {
+	Action CommandAction `json:"action"`
+	Result any           `json:"result"`
+}
+
+type CommandAction struct {
+	Verb      string   `json:"verb"`
+	Target    string   `json:"target,omitempty"`
+	Item      string   `json:"item,omitempty"`
+	Items     []string `json:"items,omitempty"`     // every item a combine combines
+	Direction string   `json:"direction,omitempty"` // the way furniture is moved
 }
 
 type ContextRequest struct{}
 
 type ContextResponse struct {
 	EngineStateInfo `json:"engine_state"`
-	RoomInfo        RoomInfo   `json:"room_info"`
-	Inventory       []ItemInfo `json:"inventory"`
+	RoomInfo        RoomInfo     `json:"room_info"`
+	Inventory       []ItemInfo   `json:"inventory"`
+	Ammo            []AmmoCount  `json:"ammo,omitempty"`
+	Objectives      []string     `json:"objectives"`
+	MinimapData     *MinimapData `json:"minimap_data,omitempty"` // only with full verbosity
 }
 
 type MinimapRequest struct{}
 
+// MinimapNoteRequest writes a note on a room or door on the minimap. An empty note rubs out
+// the one there.
+type MinimapNoteRequest struct {
+	RoomName string `json:"room_name,omitempty" binding:"required_without=DoorName,excluded_with=DoorName"`
+	DoorName string `json:"door_name,omitempty" binding:"required_without=RoomName"`
+	Note     string `json:"note" binding:"max=280"`
+}
+
 type MinimapResponse struct {
 	EngineStateInfo `json:"engine_state"`
 	MinimapData     MinimapData `json:"minimap_data"`
 }
 
 type MinimapData struct {
-	Doors       []MinimapDoorInfo `json:"doors"`
-	Rooms       []MinimapRoomInfo `json:"rooms"`
-	CurrentRoom string            `json:"current_room"`
+	Floors       []MinimapFloorInfo `json:"floors"`
+	CurrentFloor string             `json:"current_floor"`
+	CurrentRoom  string             `json:"current_room"`
+	TravelNodes  []TravelNodeInfo   `json:"travel_nodes,omitempty"` // discovered fast travel points
+}
+
+type MinimapFloorInfo struct {
+	Name    string            `json:"name"`
+	Current bool              `json:"current"`
+	Visited bool              `json:"visited"`
+	Rooms   []MinimapRoomInfo `json:"rooms"`
+	Doors   []MinimapDoorInfo `json:"doors"` // stairwells are listed on both floors they connect
 }
 
 type MinimapDoorInfo struct {
-	Name   string `json:"name"`
-	Locked *bool  `json:"locked"`
-	Hidden bool   `json:"hidden"`
+	Name         string `json:"name"`
+	RoomA        string `json:"room_a,omitempty"`    // omitted until visited
+	RoomB        string `json:"room_b,omitempty"`    // omitted until visited
+	Direction    string `json:"direction,omitempty"` // from room_a to room_b
+	Locked       *bool  `json:"locked"`
+	Hidden       bool   `json:"hidden"`
+	Traversed    bool   `json:"traversed"`
+	Stairwell    bool   `json:"stairwell,omitempty"`
+	LeadsToFloor string `json:"leads_to_floor,omitempty"` // for stairwells, once revealed
+	Note         string `json:"note,omitempty"`           // written by the players
 }
 
 type MinimapRoomInfo struct {
-	Name   string `json:"name"`
-	Hidden bool   `json:"hidden"`
+	Name     string        `json:"name"`
+	Hidden   bool          `json:"hidden"`
+	Adjacent bool          `json:"adjacent,omitempty"`
+	Position *RoomPosition `json:"position,omitempty"`
+	Icons    []string      `json:"icons,omitempty"` // enemy, locked_door, save_point, travel_node
+	Note     string        `json:"note,omitempty"`  // written by the players
+}
+
+// RoomPosition is a room's cell on its floor's grid.
+// X grows to the east and Y grows to the south.
+type RoomPosition struct {
+	X int `json:"x"`
+	Y int `json:"y"`
 }
 
 type ItemInfo struct {
-	Name         string `json:"name"`
-	Description  string `json:"description"`
-	Location     string `json:"location,omitempty"`
-	IsPortable   bool   `json:"is_portable,omitempty"`
-	IsKey        bool   `json:"is_key,omitempty"`
-	IsWeapon     bool   `json:"is_weapon,omitempty"`
-	IsContainer  bool   `json:"is_container,omitempty"`
-	IsConcealer  bool   `json:"conceals_something,omitempty"`
-	IsAmmoBox    bool   `json:"is_ammo_box,omitempty"`
-	IsHealthItem bool   `json:"is_health_item,omitempty"`
-	HasKeyLock   bool   `json:"has_key_lock,omitempty"`
-	HasCodeLock  bool   `json:"has_code_lock,omitempty"`
-	IsLocked     bool   `json:"is_locked,omitempty"`
-	Contains     string `json:"contains,omitempty"`
-	Details      string `json:"details,omitempty"`
-	IsFixture    bool   `json:"is_fixture,omitempty"`
+	Name          string           `json:"name"`
+	ID            string           `json:"id"`                    // targets the item in actions, even where other items share its name
+	Description   string           `json:"description,omitempty"` // omitted in brief contexts
+	Location      string           `json:"location,omitempty"`
+	ImageRef      string           `json:"image_ref,omitempty"` // art for graphical clients, omitted in brief contexts
+	IsPortable    bool             `json:"is_portable,omitempty"`
+	IsKey         bool             `json:"is_key,omitempty"`
+	IsWeapon      bool             `json:"is_weapon,omitempty"`
+	IsThrown      bool             `json:"is_thrown,omitempty"` // a weapon used up when thrown, like a grenade
+	ArmorSlot     string           `json:"armor_slot,omitempty"`
+	IsWorn        bool             `json:"is_worn,omitempty"` // armor the player wears
+	IsContainer   bool             `json:"is_container,omitempty"`
+	IsConcealer   bool             `json:"conceals_something,omitempty"`
+	IsMoveable    bool             `json:"is_moveable,omitempty"` // furniture that can be moved, until it has been
+	IsAmmoBox     bool             `json:"is_ammo_box,omitempty"`
+	IsHealthItem  bool             `json:"is_health_item,omitempty"`
+	IsAirSupply   bool             `json:"is_air_supply,omitempty"`
+	IsQuestItem   bool             `json:"is_quest_item,omitempty"` // can't be given away, crafted or dropped
+	Quantity      int              `json:"quantity,omitempty"`      // how many there are of a stack of items, omitted for single items
+	HasKeyLock    bool             `json:"has_key_lock,omitempty"`
+	HasCodeLock   bool             `json:"has_code_lock,omitempty"`
+	IsLocked      bool             `json:"is_locked,omitempty"`
+	IsJammed      bool             `json:"is_jammed,omitempty"`
+	AttemptsLeft  int              `json:"attempts_left,omitempty"` // wrong codes the keypad takes before it jams
+	Contains      string           `json:"contains,omitempty"`
+	Details       string           `json:"details,omitempty"`
+	IsFixture     bool             `json:"is_fixture,omitempty"`
+	Durability    int              `json:"durability,omitempty"` // uses left before a weapon or tool that wears out breaks
+	MaxDurability int              `json:"max_durability,omitempty"`
+	Charges       int              `json:"charges,omitempty"` // uses left before an item with several uses is used up
+	MaxCharges    int              `json:"max_charges,omitempty"`
+	IsBroken      bool             `json:"is_broken,omitempty"`
+	SubLocation   *SubLocationInfo `json:"sub_location,omitempty"` // where in the room the item is, omitted for nowhere in particular
+}
+
+// SubLocationInfo is a spot within a room that items can be at, such as the desk in "on the desk".
+type SubLocationInfo struct {
+	Name        string `json:"name"`
+	Preposition string `json:"preposition"`
 }
 
 // DoorInfo is a door as seen from a specific room, a "materialized" door.
 // The location is relative to the room from which it is observed, and comes from the
 // "connections" field in the room object in the level definition.
 type DoorInfo struct {
-	Name        string `json:"name"`
-	Location    string `json:"location,omitempty"`
-	Description string `json:"description,omitempty"`
-	IsLocked    bool   `json:"is_locked,omitempty"`
-	HasKeyLock  bool   `json:"has_key_lock,omitempty"`
-	HasCodeLock bool   `json:"has_code_lock,omitempty"`
-	RoomName    string `json:"room_name,omitempty"`
-	IsLatched   bool   `json:"is_locked_from_the_other_side,omitempty"`
-	LeadsTo     string `json:"leads_to,omitempty"`
+	Name         string `json:"name"`
+	Location     string `json:"location,omitempty"`
+	Description  string `json:"description,omitempty"`
+	IsLocked     bool   `json:"is_locked,omitempty"`
+	HasKeyLock   bool   `json:"has_key_lock,omitempty"`
+	HasCodeLock  bool   `json:"has_code_lock,omitempty"`
+	IsJammed     bool   `json:"is_jammed,omitempty"`
+	AttemptsLeft int    `json:"attempts_left,omitempty"` // wrong codes the keypad takes before it jams
+	RoomName     string `json:"room_name,omitempty"`
+	IsLatched    bool   `json:"is_locked_from_the_other_side,omitempty"`
+	IsBarred     bool   `json:"is_barred,omitempty"`
+	IsOneWay     bool   `json:"is_one_way,omitempty"`
+	CanLatch     bool   `json:"can_latch,omitempty"`
+	LeadsTo      string `json:"leads_to,omitempty"`
 }
 
 type RoomInfo struct {
-	RoomName        string     `json:"name"`
-	RoomDescription string     `json:"description"`
-	VisibleItems    []ItemInfo `json:"visible_items"`
-	Doors           []DoorInfo `json:"connections"`
+	RoomName        string          `json:"name"`
+	RoomDescription string          `json:"description"`
+	ImageRef        string          `json:"image_ref,omitempty"`
+	VisibleItems    []ItemInfo      `json:"visible_items"`
+	Doors           []DoorInfo      `json:"connections"`
+	TravelNode      *TravelNodeInfo `json:"travel_node,omitempty"`
+	IsAirless       bool            `json:"is_airless,omitempty"` // the player holds their breath here
+	IsBurning       bool            `json:"is_burning,omitempty"` // something thrown set the room on fire
+}
+
+// TravelNodeInfo is a fast travel point, such as an elevator or a vent.
+type TravelNodeInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Network     string `json:"network,omitempty"`
+	RoomName    string `json:"room_name"`
+	FloorName   string `json:"floor_name"`
+	Powered     bool   `json:"powered"`
 }
 
 type FloorInfo struct {
@@ -259,9 +968,10 @@ type FloorInfo struct {
 	Description string `json:"description"`
 }
 
+// AmmoCount is the rounds the player carries of a type of ammo, shared by the weapons that fire it.
 type AmmoCount struct {
-	WeaponName string `json:"weapon_name"`
-	AmmoCount  int    `json:"ammo_count"`
+	AmmoType  string `json:"ammo_type"`
+	AmmoCount int    `json:"ammo_count"`
 }
 
 // --- helpers to translate engine results to API responses ---
@@ -282,13 +992,18 @@ func EngineResultToResponseObserve(result *engine.ObserveResult) *ObserveRespons
 
 	observeResponse := &ObserveResponse{
 		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		NextCursor:      result.Result.NextCursor,
 	}
 	if result.EngineStateInfo.Mode == engine.Investigation {
 		observeResponse.RoomInfo = RoomInfo{
 			RoomName:        result.Result.RoomName,
 			RoomDescription: result.Result.RoomDescription,
+			ImageRef:        result.Result.RoomImageRef,
 			VisibleItems:    items,
 			Doors:           doors,
+			TravelNode:      getResponseTravelNodeInfo(result.Result.TravelNode),
+			IsAirless:       result.Result.Airless,
+			IsBurning:       result.Result.Burning,
 		}
 	}
 	return observeResponse
@@ -305,6 +1020,7 @@ func EngineResultToResponseInspect(result *engine.InspectResult) *InspectRespons
 			response.ItemInfo.IsPortable = true
 		}
 		response.ItemInfo.Details = result.Result.ItemInspection.Detail
+		response.LearnedRecipes = result.Result.ItemInspection.LearnedRecipes
 	}
 	if result.Result.DoorInspection != nil {
 		response.DoorInfo = getResponseDoorInfo(&result.Result.DoorInspection.DoorInfo)
@@ -328,6 +1044,7 @@ func EngineResultToResponseUnlock(result *engine.UnlockResult) *UnlockResponse {
 	return &UnlockResponse{
 		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
 		Unlocked:        result.Result.Unlocked,
+		Jammed:          result.Result.Jammed,
 	}
 }
 
@@ -346,6 +1063,15 @@ func EngineResultToResponseSearch(result *engine.SearchResult) *SearchResponse {
 	return searchResponse
 }
 
+// engineResultToResponseInterrogate translates an engine.InterrogateResult to an InterrogateResponse
+func EngineResultToResponseInterrogate(result *engine.InterrogateResult) *InterrogateResponse {
+	return &InterrogateResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		EnemyName:       result.Result.EnemyName,
+		Answer:          result.Result.Answer,
+	}
+}
+
 // engineResultToResponseTake translates an engine.TakeResult to a TakeResponse
 func EngineResultToResponseTake(result *engine.TakeResult) *TakeResponse {
 	taken_item := getResponseItemInfo(&result.Result.ItemInfo)
@@ -353,6 +1079,7 @@ func EngineResultToResponseTake(result *engine.TakeResult) *TakeResponse {
 	return &TakeResponse{
 		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
 		TakenItem:       *taken_item,
+		Trap:            getResponseTrapInfo(result.Result.Trap),
 	}
 }
 
@@ -362,17 +1089,29 @@ func EngineResultToResponseInventory(result *engine.InventoryResult) *InventoryR
 	for i, item := range result.Result.Items {
 		inventory[i] = ItemInfo{
 			Name:         item.Name,
+			ID:           item.ID,
 			Description:  item.Description,
+			ImageRef:     item.ImageRef,
 			IsWeapon:     item.IsWeapon,
+			IsThrown:     item.IsThrown,
+			ArmorSlot:    item.ArmorSlot,
+			IsWorn:       item.IsWorn,
 			IsHealthItem: item.IsHealthItem,
+			IsAirSupply:  item.IsAirSupply,
+			IsQuestItem:  item.IsQuestItem,
+			Charges:      item.Charges,
+			MaxCharges:   item.MaxCharges,
+		}
+		if item.Quantity > 1 {
+			inventory[i].Quantity = item.Quantity
 		}
 		inventory[i].Location = ""
 	}
 	ammo := make([]AmmoCount, len(result.Result.Ammo))
 	for i, ammoCount := range result.Result.Ammo {
 		ammo[i] = AmmoCount{
-			WeaponName: ammoCount.WeaponName,
-			AmmoCount:  ammoCount.AmmoCount,
+			AmmoType:  ammoCount.AmmoType,
+			AmmoCount: ammoCount.AmmoCount,
 		}
 	}
 	return &InventoryResponse{
@@ -387,6 +1126,23 @@ func EngineResultToResponseHeal(result *engine.HealResult) *HealResponse {
 	return &HealResponse{
 		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
 		HealthState:     string(result.Result.Health),
+		Breathed:        result.Result.Breathed,
+		ChargesLeft:     result.Result.ChargesLeft,
+		MaxCharges:      result.Result.MaxCharges,
+		Cured:           result.Result.Cured,
+		Trap:            getResponseTrapInfo(result.Result.Trap),
+	}
+}
+
+// engineResultToResponseWait translates an engine.WaitResult to a WaitResponse
+func EngineResultToResponseWait(result *engine.WaitResult) *WaitResponse {
+	return &WaitResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		Turns:           result.Result.Turns,
+		Rested:          result.Result.Rested,
+		HealthState:     string(result.Result.Health),
+		Healed:          result.Result.Healed,
+		Encounter:       result.Result.Encounter,
 	}
 }
 
@@ -409,8 +1165,12 @@ func EngineResultToResponseTraverse(result *engine.TraverseResult) *TraverseResp
 		EnteredRoom: RoomInfo{
 			RoomName:        result.Result.EnteredRoom.RoomName,
 			RoomDescription: result.Result.EnteredRoom.RoomDescription,
+			ImageRef:        result.Result.EnteredRoom.RoomImageRef,
 			VisibleItems:    items,
 			Doors:           doors,
+			TravelNode:      getResponseTravelNodeInfo(result.Result.EnteredRoom.TravelNode),
+			IsAirless:       result.Result.EnteredRoom.Airless,
+			IsBurning:       result.Result.EnteredRoom.Burning,
 		},
 		Unlatched: result.Result.Unlatched,
 		Unlocked:  result.Result.Unlocked,
@@ -424,23 +1184,144 @@ func EngineResultToResponseTraverse(result *engine.TraverseResult) *TraverseResp
 	return traverseResponse
 }
 
+// engineResultToResponseListen translates an engine.ListenResult to a ListenResponse
+func EngineResultToResponseListen(result *engine.ListenResult) *ListenResponse {
+	return &ListenResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		DoorName:        result.Result.DoorName,
+		Ambient:         result.Result.Ambient,
+		Movement:        result.Result.Movement,
+		EnemyName:       result.Result.EnemyName,
+	}
+}
+
+// engineResultToResponsePeek translates an engine.PeekResult to a PeekResponse
+func EngineResultToResponsePeek(result *engine.PeekResult) *PeekResponse {
+	return &PeekResponse{
+		EngineStateInfo:  *getResponseEngineStateInfo(&result.EngineStateInfo),
+		DoorName:         result.Result.DoorName,
+		RoomName:         result.Result.RoomName,
+		RoomDescription:  result.Result.RoomDescription,
+		EnemyName:        result.Result.EnemyName,
+		EnemyDescription: result.Result.EnemyDescription,
+	}
+}
+
+// engineResultToResponseLatch translates an engine.LatchResult to a LatchResponse
+func EngineResultToResponseLatch(result *engine.LatchResult) *LatchResponse {
+	return &LatchResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		DoorName:        result.Result.DoorName,
+		Latched:         result.Result.Latched,
+	}
+}
+
+// EngineResultToResponseStatus translates an engine.StatusResult to a StatusResponse
+func EngineResultToResponseStatus(result *engine.StatusResult) *StatusResponse {
+	effects := result.Result.Effects
+	if effects == nil {
+		effects = []string{}
+	}
+	return &StatusResponse{
+		EngineStateInfo:   *getResponseEngineStateInfo(&result.EngineStateInfo),
+		Health:            string(result.Result.Health),
+		HealthDescription: result.Result.HealthDescription,
+		Effects:           effects,
+		Weapon:            result.Result.Weapon,
+		Armor:             result.Result.Armor,
+		Carrying:          result.Result.Carrying,
+		Breath:            BreathInfo(result.Result.Breath),
+	}
+}
+
 // engineResultToResponseBattle translates an engine.BattleResult to a BattleResponse
 func EngineResultToResponseBattle(result *engine.BattleResult) *BattleResponse {
-	return &BattleResponse{
+	battleResponse := &BattleResponse{
 		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
 		EnemyName:       result.Result.EnemyName,
 		WonRound:        result.Result.WonRound,
 		EnemyAlive:      result.Result.EnemyAlive,
 		PlayerAlive:     result.Result.PlayerAlive,
+		BrokenItem:      result.Result.BrokenItem,
+		Thrown:          result.Result.Thrown,
+		Critical:        result.Result.Critical,
+		Fumbled:         result.Result.Fumbled,
+		Narrative:       result.Result.Narrative,
+		Pity:            result.Result.Pity,
+		Surrendered:     result.Result.Surrendered,
+		AlsoHit:         result.Result.AlsoHit,
+		AlsoKilled:      result.Result.AlsoKilled,
+		StartedFire:     result.Result.StartedFire,
+		Absorbed:        result.Result.Absorbed,
+		BrokenArmor:     result.Result.BrokenArmor,
+	}
+	if result.Result.Scrap != nil {
+		battleResponse.Scrap = getResponseItemInfo(result.Result.Scrap)
 	}
+	if result.Result.ArmorScrap != nil {
+		battleResponse.ArmorScrap = getResponseItemInfo(result.Result.ArmorScrap)
+	}
+	battleResponse.CombatLog = getResponseCombatRounds(result.Result.CombatLog)
+	return battleResponse
+}
+
+// EngineResultToResponseCombatLog translates an engine.CombatLogResult to a CombatLogResponse
+func EngineResultToResponseCombatLog(result *engine.CombatLogResult) *CombatLogResponse {
+	return &CombatLogResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		EnemyName:       result.Result.EnemyName,
+		Rounds:          getResponseCombatRounds(result.Result.Rounds),
+	}
+}
+
+func getResponseCombatRounds(rounds []engine.CombatRound) []CombatRoundInfo {
+	infos := make([]CombatRoundInfo, 0, len(rounds))
+	for _, round := range rounds {
+		infos = append(infos, CombatRoundInfo{
+			Round:        round.Round,
+			Weapon:       round.Weapon,
+			WonRound:     round.WonRound,
+			Critical:     round.Critical,
+			Damage:       round.Damage,
+			EnemyHP:      round.EnemyHP,
+			AmmoSpent:    round.AmmoSpent,
+			HealthBefore: string(round.HealthBefore),
+			HealthAfter:  string(round.HealthAfter),
+			Absorbed:     round.Absorbed,
+		})
+	}
+	return infos
+}
+
+// engineResultToResponseResolve translates an engine.ResolveResult to a ResolveResponse
+func EngineResultToResponseResolve(result *engine.ResolveResult) *ResolveResponse {
+	resolveResponse := &ResolveResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		EnemyName:       result.Result.EnemyName,
+		Approach:        result.Result.Approach,
+		Succeeded:       result.Result.Succeeded,
+		BribeItem:       result.Result.BribeItem,
+		PlayerAlive:     result.Result.PlayerAlive,
+		Absorbed:        result.Result.Absorbed,
+		BrokenArmor:     result.Result.BrokenArmor,
+	}
+	if result.Result.ArmorScrap != nil {
+		resolveResponse.ArmorScrap = getResponseItemInfo(result.Result.ArmorScrap)
+	}
+	return resolveResponse
 }
 
 // engineResultToResponseCombine translates an engine.CombineResult to a CombineResponse
 func EngineResultToResponseCombine(result *engine.CombineResult) *CombineResponse {
-	return &CombineResponse{
+	combineResponse := &CombineResponse{
 		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
 		CraftedItem:     *getResponseItemInfo(&result.Result.CraftedItem),
+		NewRecipe:       result.Result.NewRecipe,
+	}
+	for _, byproduct := range result.Result.Byproducts {
+		combineResponse.Byproducts = append(combineResponse.Byproducts, *getResponseItemInfo(&byproduct))
 	}
+	return combineResponse
 }
 
 // engineResultToResponseUse translates an engine.UseResult to a UseResponse
@@ -449,69 +1330,610 @@ func EngineResultToResponseUse(result *engine.UseResult) *UseResponse {
 		EngineStateInfo:     *getResponseEngineStateInfo(&result.EngineStateInfo),
 		AcceptedItem:        true,
 		CompletionNarrative: result.Result.CompletionNarrative,
+		InsertNarrative:     result.Result.InsertNarrative,
+		StageNarrative:      result.Result.StageNarrative,
+		MissingItems:        result.Result.MissingItems,
+		MissingCount:        result.Result.MissingCount,
+		BrokenItem:          result.Result.BrokenItem,
+		ChargesLeft:         result.Result.ChargesLeft,
+		MaxCharges:          result.Result.MaxCharges,
+		Trap:                getResponseTrapInfo(result.Result.Trap),
 	}
 	if result.Result.ProducedItem != nil {
 		useResponse.ProducedItem = getResponseItemInfo(result.Result.ProducedItem)
 	}
+	if result.Result.Scrap != nil {
+		useResponse.Scrap = getResponseItemInfo(result.Result.Scrap)
+	}
+	for _, effect := range result.Result.Effects {
+		useResponse.Effects = append(useResponse.Effects, EffectInfo{Effect: effect.Effect, Target: effect.Target})
+	}
 	return useResponse
 }
 
+func getResponseTrapInfo(trap *engine.TrapInfo) *TrapInfo {
+	if trap == nil {
+		return nil
+	}
+	info := &TrapInfo{
+		ItemName:    trap.ItemName,
+		Narrative:   trap.Narrative,
+		Effects:     make([]EffectInfo, 0, len(trap.Effects)),
+		HealthState: string(trap.Health),
+	}
+	for _, effect := range trap.Effects {
+		info.Effects = append(info.Effects, EffectInfo{Effect: effect.Effect, Target: effect.Target})
+	}
+	return info
+}
+
+// EngineResultToResponseMove translates an engine.MoveResult to a MoveResponse
+func EngineResultToResponseMove(result *engine.MoveResult) *MoveResponse {
+	moveResponse := &MoveResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		Narrative:       result.Result.Narrative,
+		RevealedDoor:    result.Result.RevealedDoor,
+	}
+	if result.Result.RevealedItem != nil {
+		moveResponse.RevealedItem = getResponseItemInfo(result.Result.RevealedItem)
+		moveResponse.RevealedItem.Location = ""
+	}
+	return moveResponse
+}
+
+// EngineResultToResponseTravel translates an engine.TravelResult to a TravelResponse
+func EngineResultToResponseTravel(result *engine.TravelResult) *TravelResponse {
+	items := make([]ItemInfo, len(result.Result.EnteredRoom.VisibleItems))
+	for i, item := range result.Result.EnteredRoom.VisibleItems {
+		items[i] = *getResponseItemInfo(&item)
+		if item.IsPortable {
+			items[i].IsPortable = true
+		}
+	}
+	doors := make([]DoorInfo, len(result.Result.EnteredRoom.Doors))
+	for i, door := range result.Result.EnteredRoom.Doors {
+		doors[i] = *getResponseDoorInfo(&door)
+	}
+
+	travelResponse := &TravelResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		FromNode:        result.Result.FromNode,
+		ToNode:          result.Result.ToNode,
+		EnteredRoom: RoomInfo{
+			RoomName:        result.Result.EnteredRoom.RoomName,
+			RoomDescription: result.Result.EnteredRoom.RoomDescription,
+			ImageRef:        result.Result.EnteredRoom.RoomImageRef,
+			VisibleItems:    items,
+			Doors:           doors,
+			TravelNode:      getResponseTravelNodeInfo(result.Result.EnteredRoom.TravelNode),
+			IsAirless:       result.Result.EnteredRoom.Airless,
+			IsBurning:       result.Result.EnteredRoom.Burning,
+		},
+	}
+	if result.Result.ChangedFloor != nil {
+		travelResponse.ChangedFloor = &FloorInfo{
+			Name:        result.Result.ChangedFloor.Name,
+			Description: result.Result.ChangedFloor.Description,
+		}
+	}
+	return travelResponse
+}
+
+func getResponseTravelNodeInfo(node *engine.TravelNodeInfo) *TravelNodeInfo {
+	if node == nil {
+		return nil
+	}
+	return &TravelNodeInfo{
+		Name:        node.Name,
+		Description: node.Description,
+		Network:     node.Network,
+		RoomName:    node.RoomName,
+		FloorName:   node.FloorName,
+		Powered:     node.Powered,
+	}
+}
+
 // engineResultToResponseMinimap translates an engine.MinimapResult to a MinimapResponse
 func EngineResultToResponseMinimap(result *engine.MinimapResult) *MinimapResponse {
 	minimapData := MinimapData{
-		CurrentRoom: result.Result.CurrentRoom,
+		CurrentFloor: result.Result.CurrentFloor,
+		CurrentRoom:  result.Result.CurrentRoom,
+	}
+	for _, floor := range result.Result.Floors {
+		minimapData.Floors = append(minimapData.Floors, getResponseMinimapFloorInfo(&floor))
 	}
-	for _, door := range result.Result.Doors {
-		minimapData.Doors = append(minimapData.Doors, MinimapDoorInfo{
-			Name:   door.Name,
-			Locked: door.Locked,
-			Hidden: door.Hidden,
+	for _, node := range result.Result.TravelNodes {
+		minimapData.TravelNodes = append(minimapData.TravelNodes, *getResponseTravelNodeInfo(&node))
+	}
+	return &MinimapResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		MinimapData:     minimapData,
+	}
+}
+
+func getResponseMinimapFloorInfo(floor *engine.MinimapFloorInfo) MinimapFloorInfo {
+	floorInfo := MinimapFloorInfo{
+		Name:    floor.Name,
+		Current: floor.Current,
+		Visited: floor.Visited,
+		Rooms:   []MinimapRoomInfo{},
+		Doors:   []MinimapDoorInfo{},
+	}
+	for _, door := range floor.Doors {
+		floorInfo.Doors = append(floorInfo.Doors, MinimapDoorInfo{
+			Name:         door.Name,
+			RoomA:        door.RoomA,
+			RoomB:        door.RoomB,
+			Direction:    string(door.Direction),
+			Locked:       door.Locked,
+			Hidden:       door.Hidden,
+			Traversed:    door.Traversed,
+			Stairwell:    door.Stairwell,
+			LeadsToFloor: door.LeadsToFloor,
+			Note:         door.Note,
 		})
 	}
+	for _, room := range floor.Rooms {
+		roomInfo := MinimapRoomInfo{
+			Name:     room.Name,
+			Hidden:   room.Hidden,
+			Adjacent: room.Adjacent,
+			Note:     room.Note,
+		}
+		for _, icon := range room.Icons {
+			roomInfo.Icons = append(roomInfo.Icons, string(icon))
+		}
+		if room.Position != nil {
+			roomInfo.Position = &RoomPosition{X: room.Position.X, Y: room.Position.Y}
+		}
+		floorInfo.Rooms = append(floorInfo.Rooms, roomInfo)
+	}
+	return floorInfo
+}
+
+// engineResultToResponseScore translates an engine.ScoreResult to a ScoreResponse
+func EngineResultToResponseScore(result *engine.ScoreResult) *ScoreResponse {
+	return &ScoreResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		Score:           *getResponseScoreInfo(&result.Result),
+	}
+}
+
+// EngineResultToResponseStats translates an engine.StatsResult to a StatsResponse
+func EngineResultToResponseStats(result *engine.StatsResult) *StatsResponse {
+	return &StatsResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		Stats:           *getResponseStatsInfo(&result.Result),
+	}
+}
+
+// EngineResultToResponsePostMortem translates an engine.PostMortemResult to a PostMortemResponse
+func EngineResultToResponsePostMortem(result *engine.PostMortemResult) *PostMortemResponse {
+	return &PostMortemResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		PostMortem:      *getResponsePostMortemInfo(&result.Result),
+	}
+}
+
+func getResponsePostMortemInfo(postMortem *engine.PostMortem) *PostMortemInfo {
+	info := &PostMortemInfo{
+		Cause:         postMortem.Cause,
+		EnemyName:     postMortem.EnemyName,
+		ItemName:      postMortem.ItemName,
+		Room:          postMortem.Room,
+		LastActions:   make([]ActionInfo, 0, len(postMortem.LastActions)),
+		RoomsExplored: postMortem.RoomsExplored,
+	}
+	for _, action := range postMortem.LastActions {
+		info.LastActions = append(info.LastActions, ActionInfo(action))
+	}
+	return info
+}
+
+// EngineResultToResponseDiff translates an engine.DiffResult to a DiffResponse
+func EngineResultToResponseDiff(result *engine.DiffResult) *DiffResponse {
+	response := &DiffResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		Since:           result.Result.Since,
+		Inventory:       ItemsDiff(result.Result.Inventory),
+		DoorsUnlocked:   result.Result.DoorsUnlocked,
+		DoorsLocked:     result.Result.DoorsLocked,
+		DoorsRevealed:   result.Result.DoorsRevealed,
+		PreviousRoom:    result.Result.PreviousRoom,
+	}
 	for _, room := range result.Result.Rooms {
-		minimapData.Rooms = append(minimapData.Rooms, MinimapRoomInfo{
-			Name:   room.Name,
-			Hidden: room.Hidden,
+		response.Rooms = append(response.Rooms, RoomItemsDiff{RoomName: room.RoomName, ItemsDiff: ItemsDiff(room.ItemsDiff)})
+	}
+	if health := result.Result.Health; health != nil {
+		response.Health = &HealthChange{From: string(health.From), To: string(health.To)}
+	}
+	return response
+}
+
+func EngineResultToResponseObjectives(result *engine.ObjectivesResult) *ObjectivesResponse {
+	return &ObjectivesResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		Objectives:      getResponseObjectiveInfo(result.Result),
+	}
+}
+
+// EngineResultToResponseRecipes translates an engine.RecipesResult to a RecipesResponse
+func EngineResultToResponseRecipes(result *engine.RecipesResult) *RecipesResponse {
+	recipes := make([]RecipeInfo, 0, len(result.Result))
+	for _, recipe := range result.Result {
+		recipes = append(recipes, RecipeInfo{
+			InputNames:  recipe.InputNames,
+			FixtureName: recipe.FixtureName,
+			OutputName:  recipe.OutputName,
+			Discovered:  recipe.Discovered,
 		})
 	}
-	return &MinimapResponse{
+	return &RecipesResponse{
 		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
-		MinimapData:     minimapData,
+		Recipes:         recipes,
+	}
+}
+
+// EngineResultToResponsePlayers translates an engine.PlayersResult to a PlayersResponse
+func EngineResultToResponsePlayers(result *engine.PlayersResult) *PlayersResponse {
+	players := make([]PlayerInfo, 0, len(result.Players))
+	for _, player := range result.Players {
+		players = append(players, PlayerInfo{
+			ID:            player.ID,
+			CurrentFloor:  player.CurrentFloor,
+			CurrentRoom:   player.CurrentRoom,
+			Health:        string(player.Health),
+			Mode:          string(player.Mode),
+			InventorySize: player.InventorySize,
+			HasTurn:       player.HasTurn,
+		})
+	}
+	return &PlayersResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		Players:         players,
 	}
 }
 
-func EngineResultToResponseContext(observeResult *engine.ObserveResult, inventoryResult *engine.InventoryResult) *ContextResponse {
-	return &ContextResponse{
-		EngineStateInfo: *getResponseEngineStateInfo(&observeResult.EngineStateInfo),
-		RoomInfo:        EngineResultToResponseObserve(observeResult).RoomInfo,
-		Inventory:       EngineResultToResponseInventory(inventoryResult).Inventory,
+// engineResultToResponseRestore translates an engine.RestoreResult to a RestoreCheckpointResponse
+func EngineResultToResponseRestore(checkpointName string, result *engine.RestoreResult) *RestoreCheckpointResponse {
+	return &RestoreCheckpointResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		Restored:        checkpointName,
+	}
+}
+
+// EngineResultToResponseRewind translates an engine.RestoreResult from rewinding a session to a RewindResponse
+func EngineResultToResponseRewind(sessionID string, turn int, result *engine.RestoreResult) *RewindResponse {
+	return &RewindResponse{
+		SessionID:       sessionID,
+		Turn:            turn,
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+	}
+}
+
+// EngineResultToResponseRespawn translates an engine.RespawnResult to a RespawnResponse
+func EngineResultToResponseRespawn(result *engine.RespawnResult) *RespawnResponse {
+	observeResponse := EngineResultToResponseObserve(&engine.ObserveResult{
+		EngineStateInfo: result.EngineStateInfo,
+		Result:          result.Result,
+	})
+	return &RespawnResponse{
+		EngineStateInfo: observeResponse.EngineStateInfo,
+		RoomInfo:        observeResponse.RoomInfo,
+	}
+}
+
+// DiagnosticsToResponseWarnings translates the warnings of a stored level
+func DiagnosticsToResponseWarnings(diagnostics loader.Diagnostics) []LevelDiagnostic {
+	return getResponseLevelDiagnostics(diagnostics.Warnings())
+}
+
+func DiagnosticsToResponseValidate(diagnostics loader.Diagnostics) *ValidateLevelResponse {
+	return &ValidateLevelResponse{
+		Valid:    !diagnostics.HasErrors(),
+		Errors:   getResponseLevelDiagnostics(diagnostics.Errors()),
+		Warnings: getResponseLevelDiagnostics(diagnostics.Warnings()),
+	}
+}
+
+// EngineResultToResponseContext translates an engine.ContextResult to a ContextResponse
+func EngineResultToResponseContext(result *engine.ContextResult) *ContextResponse {
+	observeResponse := EngineResultToResponseObserve(&engine.ObserveResult{
+		EngineStateInfo: result.EngineStateInfo,
+		Result:          result.Result.Room,
+	})
+	inventoryResponse := EngineResultToResponseInventory(&engine.InventoryResult{
+		EngineStateInfo: result.EngineStateInfo,
+		Result:          result.Result.Inventory,
+	})
+
+	response := &ContextResponse{
+		EngineStateInfo: *getResponseEngineStateInfo(&result.EngineStateInfo),
+		RoomInfo:        observeResponse.RoomInfo,
+		Inventory:       inventoryResponse.Inventory,
+		Ammo:            inventoryResponse.Ammo,
+		Objectives:      []string{},
+	}
+	for _, objective := range result.Result.Objectives {
+		response.Objectives = append(response.Objectives, objective.Description)
+	}
+	if result.Result.Minimap != nil {
+		response.MinimapData = &EngineResultToResponseMinimap(&engine.MinimapResult{
+			EngineStateInfo: result.EngineStateInfo,
+			Result:          *result.Result.Minimap,
+		}).MinimapData
+	}
+	return response
+}
+
+func EngineErrorToResponse(err error) *ErrorResponse {
+	response := &ErrorResponse{
+		Error:     err.Error(),
+		ErrorCode: string(engine.ErrorCodeOf(err)),
+	}
+	var ambiguous *engine.AmbiguousNameError
+	if errors.As(err, &ambiguous) {
+		response.DidYouMean = ambiguous.Candidates
+	}
+	return response
+}
+
+func ParserActionToResponse(action *parser.Action) CommandAction {
+	commandAction := CommandAction{
+		Verb:      string(action.Verb),
+		Target:    action.Target,
+		Item:      action.Item,
+		Direction: action.Direction,
+	}
+	if action.Verb == parser.VerbCombine {
+		commandAction.Items = action.Items()
+	}
+	return commandAction
+}
+
+// RunAction runs a parsed action on an engine under ctx and translates the result to the response
+// of the matching action endpoint, also returning the response's engine state.
+// The response is narrated unless n is nil.
+func RunAction(ctx context.Context, e *engine.Engine, action *parser.Action, n *narrator.Session) (any, EngineStateInfo, error) {
+	switch action.Verb {
+	case parser.VerbObserve:
+		result, err := e.Observe(ctx, engine.ObserveFilter{})
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseObserve(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbInspect:
+		result, err := e.Inspect(ctx, action.Target)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseInspect(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbUncover:
+		result, err := e.Uncover(ctx, action.Target)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseUncover(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbMove:
+		result, err := e.Move(ctx, action.Target, action.Direction)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseMove(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbTravel:
+		result, err := e.Travel(ctx, action.Target)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseTravel(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbUnlock:
+		result, err := e.Unlock(ctx, action.Item, action.Target)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseUnlock(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbSearch:
+		result, err := e.Search(ctx, action.Target)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseSearch(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbInterrogate:
+		result, err := e.Interrogate(ctx, action.Target)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseInterrogate(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbTake:
+		result, err := e.TakeQuantity(ctx, action.Target, action.Quantity)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseTake(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbInventory:
+		result, err := e.Inventory(ctx)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseInventory(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbStatus:
+		result, err := e.Status(ctx)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseStatus(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbHeal:
+		result, err := e.Heal(ctx, action.Item)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseHeal(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbWait, parser.VerbRest:
+		turns := max(action.Quantity, 1)
+		var result *engine.WaitResult
+		var err error
+		if action.Verb == parser.VerbRest {
+			result, err = e.Rest(ctx, turns)
+		} else {
+			result, err = e.Wait(ctx, turns)
+		}
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseWait(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbTraverse:
+		result, err := e.Traverse(ctx, action.Target)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseTraverse(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbListen:
+		result, err := e.Listen(ctx, action.Target)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseListen(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbPeek:
+		result, err := e.Peek(ctx, action.Target)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponsePeek(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbLatch:
+		result, err := e.Latch(ctx, action.Target)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseLatch(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbBattle:
+		result, err := e.Battle(ctx, action.Item)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseBattle(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbIntimidate, parser.VerbBribe, parser.VerbSneak:
+		var result *engine.ResolveResult
+		var err error
+		switch action.Verb {
+		case parser.VerbIntimidate:
+			result, err = e.Intimidate(ctx)
+		case parser.VerbBribe:
+			result, err = e.Bribe(ctx, action.Item)
+		default:
+			result, err = e.Sneak(ctx)
+		}
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseResolve(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbCombine:
+		result, err := e.Combine(ctx, action.Items()...)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseCombine(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbUse:
+		result, err := e.Use(ctx, action.Item, action.Target)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseUse(result)
+		response.Narration = n.Narrate(*action, result, response)
+		return response, response.EngineStateInfo, nil
+	case parser.VerbMinimap:
+		result, err := e.Minimap(ctx)
+		if err != nil {
+			return nil, EngineStateInfo{}, err
+		}
+		response := EngineResultToResponseMinimap(result)
+		return response, response.EngineStateInfo, nil
 	}
+	return nil, EngineStateInfo{}, fmt.Errorf("unsupported action %s", action.Verb)
 }
 
 // --- private helpers ---
 
 func getResponseItemInfo(item *engine.ItemInfo) *ItemInfo {
 	itemInfo := &ItemInfo{
-		Name:         item.Name,
-		Description:  item.Description,
-		Location:     item.Location,
-		IsKey:        item.IsKey,
-		IsWeapon:     item.IsWeapon,
-		IsContainer:  item.IsContainer,
-		IsConcealer:  item.IsConcealer,
-		IsAmmoBox:    item.IsAmmoBox,
-		IsHealthItem: item.IsHealthItem,
-		HasKeyLock:   item.HasKeyLock,
-		HasCodeLock:  item.HasCodeLock,
-		IsLocked:     item.IsLocked,
-		Contains:     item.Contains,
-		IsFixture:    item.IsFixture,
+		Name:          item.Name,
+		ID:            item.ID,
+		Description:   item.Description,
+		Location:      item.Location,
+		ImageRef:      item.ImageRef,
+		IsKey:         item.IsKey,
+		IsWeapon:      item.IsWeapon,
+		IsThrown:      item.IsThrown,
+		ArmorSlot:     item.ArmorSlot,
+		IsWorn:        item.IsWorn,
+		IsContainer:   item.IsContainer,
+		IsConcealer:   item.IsConcealer,
+		IsMoveable:    item.IsMoveable && !item.IsMoved,
+		IsAmmoBox:     item.IsAmmoBox,
+		IsHealthItem:  item.IsHealthItem,
+		IsAirSupply:   item.IsAirSupply,
+		IsQuestItem:   item.IsQuestItem,
+		HasKeyLock:    item.HasKeyLock,
+		HasCodeLock:   item.HasCodeLock,
+		IsLocked:      item.IsLocked,
+		IsJammed:      item.IsJammed,
+		AttemptsLeft:  item.AttemptsLeft,
+		Contains:      item.Contains,
+		IsFixture:     item.IsFixture,
+		Durability:    item.Durability,
+		MaxDurability: item.MaxDurability,
+		Charges:       item.Charges,
+		MaxCharges:    item.MaxCharges,
+		IsBroken:      item.IsBroken,
 	}
 
 	// Suppress irrelevant information in final response
+	if item.Quantity > 1 {
+		itemInfo.Quantity = item.Quantity
+	}
 	if !item.IsLocked {
 		itemInfo.HasKeyLock = false
 		itemInfo.HasCodeLock = false
+		itemInfo.AttemptsLeft = 0
 	}
 	if item.IsSearched {
 		itemInfo.IsContainer = false
@@ -522,26 +1944,35 @@ func getResponseItemInfo(item *engine.ItemInfo) *ItemInfo {
 	if item.IsConcealer && item.IsUncovered {
 		itemInfo.IsConcealer = false
 	}
+	if item.SubLocation.Name != "" {
+		itemInfo.SubLocation = &SubLocationInfo{Name: item.SubLocation.Name, Preposition: item.SubLocation.Preposition}
+	}
 
 	return itemInfo
 }
 
 func getResponseDoorInfo(door *engine.DoorInfo) *DoorInfo {
 	doorInfo := &DoorInfo{
-		Name:        door.Name,
-		Description: door.Description,
-		Location:    door.Location,
-		IsLocked:    door.IsLocked,
-		HasKeyLock:  door.HasKeyLock,
-		HasCodeLock: door.HasCodeLock,
-		IsLatched:   door.IsLatched,
-		LeadsTo:     door.LeadsTo,
+		Name:         door.Name,
+		Description:  door.Description,
+		Location:     door.Location,
+		IsLocked:     door.IsLocked,
+		HasKeyLock:   door.HasKeyLock,
+		HasCodeLock:  door.HasCodeLock,
+		IsJammed:     door.IsJammed,
+		AttemptsLeft: door.AttemptsLeft,
+		IsLatched:    door.IsLatched,
+		IsBarred:     door.IsBarred,
+		IsOneWay:     door.IsOneWay,
+		CanLatch:     door.CanLatch,
+		LeadsTo:      door.LeadsTo,
 	}
 
 	// Suppress irrelevant information in final response
 	if !door.IsLocked {
 		doorInfo.HasKeyLock = false
 		doorInfo.HasCodeLock = false
+		doorInfo.AttemptsLeft = 0
 	}
 	return doorInfo
 }
@@ -555,16 +1986,127 @@ func getResponseEngineStateInfo(engineState *engine.EngineStateInfo) *EngineStat
 		CurrentFloor:         engineState.CurrentFloor.Name,
 		CurrentRoom:          engineState.CurrentRoom.Name,
 		OutroNarrative:       engineState.OutroNarrative,
+		FailureNarrative:     engineState.FailureNarrative,
+		StateVersion:         engineState.StateVersion,
+		Player:               engineState.Player,
+		NextPlayer:           engineState.NextPlayer,
+		Ambient:              engineState.Ambient,
+		RespawnRoom:          engineState.RespawnRoom,
+		CanRespawn:           engineState.CanRespawn,
+		AllowedActions:       engineState.AllowedActions,
+	}
+	if engineState.AllowedActions == nil {
+		engineStateInfo.AllowedActions = []string{}
+	}
+	for _, weapon := range engineState.Weapons {
+		engineStateInfo.Weapons = append(engineStateInfo.Weapons, WeaponInfo(weapon))
+	}
+	for _, armor := range engineState.Armor {
+		engineStateInfo.Armor = append(engineStateInfo.Armor, ArmorInfo(armor))
+	}
+	if engineState.Exploration != nil {
+		exploration := ExplorationInfo(*engineState.Exploration)
+		engineStateInfo.Exploration = &exploration
+	}
+	if engineState.Breath != nil {
+		engineStateInfo.Breath = &BreathInfo{
+			Left:     engineState.Breath.Left,
+			Capacity: engineState.Breath.Capacity,
+		}
 	}
 	if engineState.FightingEnemy != nil {
 		engineStateInfo.FightingEnemy = &FightingEnemy{
 			Name:        engineState.FightingEnemy.BaseEntity.Name,
 			Description: engineState.FightingEnemy.BaseEntity.Description,
+			ImageRef:    engineState.FightingEnemy.BaseEntity.ImageRef,
 			HP:          engineState.FightingEnemy.HP,
 		}
 	}
 	if engineState.EngineStateChangeNotification != nil {
 		engineStateInfo.Notification = string(*engineState.EngineStateChangeNotification)
 	}
+	for _, notification := range engineState.Notifications {
+		engineStateInfo.Notifications = append(engineStateInfo.Notifications, string(notification))
+	}
+	if engineState.Score != nil {
+		engineStateInfo.Score = getResponseScoreInfo(engineState.Score)
+	}
+	if engineState.Stats != nil {
+		engineStateInfo.Stats = getResponseStatsInfo(engineState.Stats)
+	}
+	if engineState.Victory != nil {
+		victory := VictoryInfo(*engineState.Victory)
+		engineStateInfo.Victory = &victory
+	}
+	if engineState.PostMortem != nil {
+		engineStateInfo.PostMortem = getResponsePostMortemInfo(engineState.PostMortem)
+	}
+	if len(engineState.Objectives) > 0 {
+		engineStateInfo.Objectives = getResponseObjectiveInfo(engineState.Objectives)
+	}
+	for _, cue := range engineState.SoundCues {
+		engineStateInfo.SoundCues = append(engineStateInfo.SoundCues, SoundCue{
+			Sound:  cue.Sound,
+			Source: cue.Source,
+			Event:  string(cue.Event),
+		})
+	}
 	return engineStateInfo
 }
+
+func getResponseObjectiveInfo(objectives []engine.ObjectiveInfo) []ObjectiveInfo {
+	result := make([]ObjectiveInfo, 0, len(objectives))
+	for _, objective := range objectives {
+		result = append(result, ObjectiveInfo{
+			Name:        objective.Name,
+			Description: objective.Description,
+			Status:      string(objective.Status),
+		})
+	}
+	return result
+}
+
+func getResponseScoreInfo(summary *engine.ScoreSummary) *ScoreInfo {
+	scoreInfo := &ScoreInfo{
+		Score:           summary.Score,
+		Final:           summary.Final,
+		TurnsTaken:      summary.Stats.Turns,
+		EnemiesDefeated: summary.Stats.EnemiesDefeated,
+		DamageTaken:     summary.Stats.DamageTaken,
+		SecretsFound:    summary.Stats.SecretsFound,
+	}
+	for _, badge := range summary.Badges {
+		scoreInfo.Badges = append(scoreInfo.Badges, BadgeInfo{
+			Name:        badge.Name,
+			Description: badge.Description,
+		})
+	}
+	return scoreInfo
+}
+
+func getResponseStatsInfo(summary *engine.StatsSummary) *StatsInfo {
+	return &StatsInfo{
+		TurnsTaken:      summary.Stats.Turns,
+		Actions:         summary.Actions,
+		DamageDealt:     summary.DamageDealt,
+		DamageTaken:     summary.Stats.DamageTaken,
+		EnemiesDefeated: summary.Stats.EnemiesDefeated,
+		EnemiesSpared:   summary.EnemiesSpared,
+		SecretsFound:    summary.Stats.SecretsFound,
+		RoomsVisited:    summary.RoomsVisited,
+		ItemsTaken:      summary.ItemsTaken,
+		RoomTurns:       summary.RoomTurns,
+	}
+}
+
+func getResponseLevelDiagnostics(diagnostics loader.Diagnostics) []LevelDiagnostic {
+	result := make([]LevelDiagnostic, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		result = append(result, LevelDiagnostic{
+			Severity: string(diagnostic.Severity),
+			Path:     diagnostic.Path,
+			Message:  diagnostic.Message,
+		})
+	}
+	return result
+}
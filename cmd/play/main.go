@@ -0,0 +1,282 @@
+// Command play runs a level in the terminal without the server, for level authors.
+//
+// Usage:
+//
+//	go run ./cmd/play [-seed N] [-narrate] level.json
+//
+// Commands are free text, such as "take the key" or "go north", plus a few meta commands:
+// help, history, !! and !N to repeat commands, undo, reload and quit.
+// With -narrate, results are told as prose instead of printed as YAML.
+// For line editing and arrow key history, run it under rlwrap.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+
+	v1 "adventure-engine/api/v1"
+	"adventure-engine/internal/narrator"
+	"adventure-engine/internal/parser"
+	"adventure-engine/pkg/engine"
+	"adventure-engine/pkg/loader"
+
+	"gopkg.in/yaml.v3"
+)
+
+const helpText = `Type what you want to do, for example:
+  look, inspect the desk, take the key, unlock the door with the key,
+  go north, attack with the pipe, use the crowbar on the plank, map
+Meta commands:
+  help      show this help
+  history   list the commands entered so far
+  !!        repeat the last command
+  !N        repeat command N from the history
+  undo      take back the last command
+  reload    reload the level file and start over
+  quit      exit`
+
+// game is a level being played
+type game struct {
+	filename string
+	seed     uint64
+	narrate  bool
+	engine   *engine.Engine
+	narrator *narrator.Session  // nil unless narrating
+	undo     []*engine.Snapshot // snapshots taken before each command
+	history  []string
+	out      io.Writer
+}
+
+func main() {
+	seed := flag.Uint64("seed", 0, "seed for loot placement and combat rolls (random if 0)")
+	narrate := flag.Bool("narrate", false, "tell results as prose instead of printing them as YAML")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-seed N] [-narrate] level.json|level.yaml\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	g := &game{filename: flag.Arg(0), seed: *seed, narrate: *narrate, out: os.Stdout}
+	if g.seed == 0 {
+		g.seed = rand.Uint64N(1 << 53)
+	}
+	if err := g.load(); err != nil {
+		log.Fatal(err)
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Fprint(g.out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(g.out)
+			return
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if quit := g.handle(line); quit {
+			return
+		}
+	}
+}
+
+// load loads the level file and starts playing it, printing any warnings about the level
+func (g *game) load() error {
+	data, err := loader.ReadLevelFile(g.filename)
+	if err != nil {
+		return err
+	}
+	level, warnings, err := loader.LoadGameWithDiagnostics(data, g.seed)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(g.out, "warning: %s: %s\n", warning.Path, warning.Message)
+	}
+
+	g.engine = engine.NewEngine(level)
+	g.engine.Rng = engine.NewSeededRng(g.seed)
+	if g.narrate {
+		g.narrator = narrator.NewSession(narrator.Templates{}, level.Theme, 0)
+	}
+	g.undo = nil
+	fmt.Fprintf(g.out, "%s (seed %d)\n", level.Name, g.seed)
+	if level.IntroNarrative != "" {
+		fmt.Fprintf(g.out, "\n%s\n", level.IntroNarrative)
+	}
+	g.run("look")
+	return nil
+}
+
+// handle runs a line of input, returning true if the player quit
+func (g *game) handle(line string) bool {
+	// Repeat commands from the history
+	if line == "!!" || strings.HasPrefix(line, "!") {
+		index := len(g.history)
+		if line != "!!" {
+			n, err := strconv.Atoi(line[1:])
+			if err != nil || n < 1 || n > len(g.history) {
+				fmt.Fprintf(g.out, "no command %s in the history\n", line[1:])
+				return false
+			}
+			index = n
+		}
+		if index == 0 {
+			fmt.Fprintln(g.out, "no commands yet")
+			return false
+		}
+		line = g.history[index-1]
+		fmt.Fprintln(g.out, line)
+	}
+
+	switch line {
+	case "quit", "exit":
+		return true
+	case "help", "?":
+		fmt.Fprintln(g.out, helpText)
+		return false
+	case "history":
+		for i, command := range g.history {
+			fmt.Fprintf(g.out, "%4d  %s\n", i+1, command)
+		}
+		return false
+	case "reload":
+		if err := g.load(); err != nil {
+			fmt.Fprintf(g.out, "failed to reload, still playing the old level: %v\n", err)
+		}
+		return false
+	case "undo":
+		if len(g.undo) == 0 {
+			fmt.Fprintln(g.out, "nothing to undo")
+			return false
+		}
+		snapshot := g.undo[len(g.undo)-1]
+		g.undo = g.undo[:len(g.undo)-1]
+		if _, err := g.engine.Restore(snapshot); err != nil {
+			fmt.Fprintf(g.out, "failed to undo: %v\n", err)
+			return false
+		}
+		g.run("look")
+		return false
+	}
+
+	g.history = append(g.history, line)
+	g.run(line)
+	return false
+}
+
+// run parses and runs a command, printing the result
+func (g *game) run(line string) {
+	action, err := parser.Parse(parser.ExpandAliases(line, g.engine.Level.VerbAliases), g.engine.ReferableNames())
+	if err != nil {
+		fmt.Fprintln(g.out, err)
+		return
+	}
+
+	snapshot := g.engine.Snapshot()
+	version := g.engine.StateVersion
+	response, state, err := v1.RunAction(context.Background(), g.engine, action, g.narrator)
+	if err != nil {
+		errorResponse := v1.EngineErrorToResponse(err)
+		fmt.Fprintln(g.out, errorResponse.Error)
+		return
+	}
+	if g.engine.StateVersion != version {
+		g.undo = append(g.undo, snapshot)
+	}
+
+	if err := g.printResponse(response); err != nil {
+		fmt.Fprintf(g.out, "failed to print result: %v\n", err)
+	}
+	g.printState(state)
+}
+
+// printResponse prints an action's narration, or the response as YAML leaving out the engine state
+func (g *game) printResponse(response any) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	delete(fields, "engine_state")
+	if narration, ok := fields["narration"].(string); ok {
+		fmt.Fprintln(g.out, narration)
+		return nil
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	encoder := yaml.NewEncoder(g.out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(fields); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// printState prints a status line, and the ending once the level is over
+func (g *game) printState(state v1.EngineStateInfo) {
+	for _, notification := range state.Notifications {
+		fmt.Fprintf(g.out, "** %s **\n", strings.ReplaceAll(notification, "_", " "))
+	}
+	status := []string{state.CurrentRoom, "health " + state.PlayerHealth}
+	if state.FightingEnemy != nil {
+		status = append(status, "fighting "+state.FightingEnemy.Name)
+	}
+	fmt.Fprintf(g.out, "-- %s --\n", strings.Join(status, " | "))
+
+	switch engine.LevelCompletionState(state.LevelCompletionState) {
+	case engine.LevelCompletionStateComplete:
+		if state.OutroNarrative != "" {
+			fmt.Fprintf(g.out, "\n%s\n", state.OutroNarrative)
+		}
+		if state.Score != nil {
+			fmt.Fprintf(g.out, "\nlevel complete, score %d in %d turns\n", state.Score.Score, state.Score.TurnsTaken)
+		}
+		if victory := state.Victory; victory != nil {
+			for _, missed := range []struct {
+				what  string
+				names []string
+			}{
+				{"secrets missed", victory.SecretsMissed},
+				{"enemies left alive", victory.EnemiesAlive},
+				{"rooms unexplored", victory.RoomsUnexplored},
+				{"items never found", victory.ItemsNotFound},
+			} {
+				if len(missed.names) > 0 {
+					fmt.Fprintf(g.out, "%s: %s\n", missed.what, strings.Join(missed.names, ", "))
+				}
+			}
+		}
+		fmt.Fprintln(g.out, "type undo, reload or quit")
+	case engine.LevelCompletionStateFailed:
+		if state.FailureNarrative != "" {
+			fmt.Fprintf(g.out, "\n%s\n", state.FailureNarrative)
+		}
+		if death := state.PostMortem; death != nil {
+			cause := strings.ReplaceAll(death.Cause, "_", " ")
+			if death.EnemyName != "" {
+				cause += " " + death.EnemyName
+			}
+			fmt.Fprintf(g.out, "\n%s in the %s, %d%% of the level explored\n", cause, death.Room, death.RoomsExplored)
+		}
+		fmt.Fprintln(g.out, "you died -- type undo, reload or quit")
+	}
+}
@@ -1,10 +1,17 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 
+	"adventure-engine/internal/leaderboard"
+	"adventure-engine/internal/narrator"
 	"adventure-engine/internal/server"
+	"adventure-engine/pkg/loader"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,8 +20,68 @@ func main() {
 	// Create Gin router
 	r := gin.Default()
 
+	// Load API keys, given as name:key or name:key:admin separated by commas
+	var config server.Config
+	var err error
+	config.APIKeys, err = server.ParseAPIKeys(os.Getenv("SAGA_API_KEYS"))
+	if err != nil {
+		log.Fatal("Invalid SAGA_API_KEYS:", err)
+	}
+	if len(config.APIKeys) == 0 {
+		log.Println("SAGA_API_KEYS is not set, authentication is disabled")
+	}
+
+	// Load rate limits, given as requests per second with an optional burst, e.g. 5:10
+	config.IPRateLimit, err = server.ParseRateLimit(os.Getenv("SAGA_IP_RATE_LIMIT"))
+	if err != nil {
+		log.Fatal("Invalid SAGA_IP_RATE_LIMIT:", err)
+	}
+	config.SessionRateLimit, err = server.ParseRateLimit(os.Getenv("SAGA_SESSION_RATE_LIMIT"))
+	if err != nil {
+		log.Fatal("Invalid SAGA_SESSION_RATE_LIMIT:", err)
+	}
+
+	// Load the narrator for sessions with narration on
+	config.Narrator, err = loadNarrator()
+	if err != nil {
+		log.Fatal("Invalid SAGA_NARRATOR settings:", err)
+	}
+	config.NarrationTimeout = narrationTimeout
+	if timeout := os.Getenv("SAGA_NARRATOR_TIMEOUT"); timeout != "" {
+		config.NarrationTimeout, err = time.ParseDuration(timeout)
+		if err != nil {
+			log.Fatal("Invalid SAGA_NARRATOR_TIMEOUT:", err)
+		}
+	}
+
+	// Load the time each game action may take, if actions are cut off
+	if timeout := os.Getenv("SAGA_ACTION_TIMEOUT"); timeout != "" {
+		config.ActionTimeout, err = time.ParseDuration(timeout)
+		if err != nil {
+			log.Fatal("Invalid SAGA_ACTION_TIMEOUT:", err)
+		}
+	}
+
+	// Load the leaderboard store, if leaderboards are on
+	config.Leaderboard, err = loadLeaderboard()
+	if err != nil {
+		log.Fatal("Invalid SAGA_LEADERBOARD settings:", err)
+	}
+
+	// Cache loaded levels, so sessions on the same level document skip loading it
+	levelCacheSize := defaultLevelCacheSize
+	if size := os.Getenv("SAGA_LEVEL_CACHE_SIZE"); size != "" {
+		levelCacheSize, err = strconv.Atoi(size)
+		if err != nil || levelCacheSize < 0 {
+			log.Fatal("Invalid SAGA_LEVEL_CACHE_SIZE: expected a number of levels, 0 to turn caching off")
+		}
+	}
+	if levelCacheSize > 0 {
+		config.LevelCache = loader.NewCache(levelCacheSize)
+	}
+
 	// Setup routes
-	server.SetupRoutes(r)
+	server.NewServer(config).SetupRoutes(r)
 
 	// Start server
 	log.Println("Starting Saga Engine server on :8080")
@@ -22,3 +89,70 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// defaultLevelCacheSize is how many loaded levels are cached unless SAGA_LEVEL_CACHE_SIZE says otherwise
+const defaultLevelCacheSize = 128
+
+// narrationTimeout is how long a model may take to narrate an action before templates are used
+const narrationTimeout = 10 * time.Second
+
+// narrationCacheSize is how many model narrations are cached
+const narrationCacheSize = 1024
+
+// loadNarrator returns the narrator named by SAGA_NARRATOR: templates (the default), openai,
+// anthropic or local, a model served by Ollama or another server speaking the OpenAI API.
+// Model narrators take their model from SAGA_NARRATOR_MODEL, their API key from
+// SAGA_NARRATOR_API_KEY and can be pointed at another endpoint with SAGA_NARRATOR_URL.
+func loadNarrator() (narrator.Narrator, error) {
+	kind := os.Getenv("SAGA_NARRATOR")
+	if kind == "" || kind == "templates" {
+		return nil, nil
+	}
+	model := os.Getenv("SAGA_NARRATOR_MODEL")
+	if model == "" {
+		return nil, fmt.Errorf("SAGA_NARRATOR_MODEL is required for the %s narrator", kind)
+	}
+	url := os.Getenv("SAGA_NARRATOR_URL")
+	key := os.Getenv("SAGA_NARRATOR_API_KEY")
+
+	var n narrator.Narrator
+	switch kind {
+	case "openai":
+		n = &narrator.OpenAI{BaseURL: url, APIKey: key, Model: model}
+	case "anthropic":
+		n = &narrator.Anthropic{BaseURL: url, APIKey: key, Model: model}
+	case "local":
+		if url == "" {
+			url = narrator.OllamaBaseURL
+		}
+		n = &narrator.OpenAI{BaseURL: url, APIKey: key, Model: model}
+	default:
+		return nil, fmt.Errorf("unknown narrator %q, expected templates, openai, anthropic or local", kind)
+	}
+	log.Printf("Narrating with the %s narrator, model %s", kind, model)
+	return narrator.Cached(n, narrationCacheSize), nil
+}
+
+// defaultLeaderboardPath is where the file leaderboard keeps its runs unless SAGA_LEADERBOARD_PATH says otherwise
+const defaultLeaderboardPath = "leaderboard.jsonl"
+
+// loadLeaderboard returns the leaderboard store named by SAGA_LEADERBOARD: off (the default),
+// memory, or file, which keeps runs across restarts in the file at SAGA_LEADERBOARD_PATH.
+func loadLeaderboard() (leaderboard.Store, error) {
+	switch kind := os.Getenv("SAGA_LEADERBOARD"); kind {
+	case "", "off":
+		return nil, nil
+	case "memory":
+		log.Println("Keeping leaderboards in memory")
+		return leaderboard.NewMemory(), nil
+	case "file":
+		path := os.Getenv("SAGA_LEADERBOARD_PATH")
+		if path == "" {
+			path = defaultLeaderboardPath
+		}
+		log.Printf("Keeping leaderboards in %s", path)
+		return leaderboard.OpenFile(path)
+	default:
+		return nil, fmt.Errorf("unknown leaderboard store %q, expected off, memory or file", kind)
+	}
+}
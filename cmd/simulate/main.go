@@ -0,0 +1,165 @@
+// Command simulate balance-tests a level by having a bot play it many times.
+//
+// Usage:
+//
+//	go run ./cmd/simulate [-policy greedy] [-episodes 100] level.json
+//	go run ./cmd/simulate -policy scripted -script walkthrough.txt level.json
+//	go run ./cmd/simulate -generate normal -episodes 50
+//
+// The report gives the solve rate, the average turns taken to solve the level and what
+// killed the bot. With -generate, every episode plays a new generated level.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"adventure-engine/internal/procgen"
+	"adventure-engine/internal/sim"
+	"adventure-engine/pkg/loader"
+	"adventure-engine/pkg/world"
+)
+
+func main() {
+	policyName := flag.String("policy", "greedy", "bot policy: random, greedy or scripted")
+	script := flag.String("script", "", "file of commands for the scripted policy, one per line")
+	episodes := flag.Int("episodes", 100, "number of episodes to play")
+	maxActions := flag.Int("max-actions", 500, "actions per episode before giving up")
+	seed := flag.Uint64("seed", 1, "seed of the first episode; episode i uses seed+i")
+	generate := flag.String("generate", "", "play generated levels of this difficulty (easy, normal or hard) instead of a level file")
+	jsonOutput := flag.Bool("json", false, "print the report and episodes as JSON")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] level.json|level.yaml\n       %s [flags] -generate difficulty\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	newLevel, err := levelFunc(*generate, flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	newPolicy, err := policyFunc(*policyName, *script)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	report, results, err := sim.Run(newLevel, newPolicy, sim.Config{
+		Episodes:   *episodes,
+		MaxActions: *maxActions,
+		Seed:       *seed,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(map[string]any{"report": report, "episodes": results}); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	printReport(report)
+}
+
+// levelFunc returns the level to play: the level file, or a generated level per episode
+func levelFunc(difficulty string, args []string) (sim.LevelFunc, error) {
+	if difficulty != "" {
+		if len(args) != 0 {
+			return nil, fmt.Errorf("give either a level file or -generate, not both")
+		}
+		return func(seed uint64) (*world.Level, error) {
+			params, err := procgen.DefaultParams(procgen.Difficulty(difficulty), seed)
+			if err != nil {
+				return nil, err
+			}
+			return procgen.Generate(params)
+		}, nil
+	}
+
+	if len(args) != 1 {
+		return nil, fmt.Errorf("give one level file")
+	}
+	data, err := loader.ReadLevelFile(args[0])
+	if err != nil {
+		return nil, err
+	}
+	// Load once up front so a broken level fails before any episode is played
+	if _, err := loader.LoadGameWithSeed(data, 0); err != nil {
+		return nil, err
+	}
+	return func(seed uint64) (*world.Level, error) {
+		return loader.LoadGameWithSeed(data, seed)
+	}, nil
+}
+
+// policyFunc returns the named bot policy
+func policyFunc(name string, script string) (sim.PolicyFunc, error) {
+	switch name {
+	case "random":
+		return func(seed uint64) (sim.Policy, error) { return sim.NewRandomPolicy(seed), nil }, nil
+	case "greedy":
+		return func(seed uint64) (sim.Policy, error) { return sim.NewGreedyPolicy(seed), nil }, nil
+	case "scripted":
+		if script == "" {
+			return nil, fmt.Errorf("the scripted policy needs -script")
+		}
+		commands, err := readScript(script)
+		if err != nil {
+			return nil, err
+		}
+		return func(seed uint64) (sim.Policy, error) { return sim.NewScriptedPolicy(commands), nil }, nil
+	}
+	return nil, fmt.Errorf("unknown policy %q, expected random, greedy or scripted", name)
+}
+
+// readScript reads commands from a file, skipping blank lines and # comments
+func readScript(filename string) ([]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var commands []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		commands = append(commands, line)
+	}
+	return commands, scanner.Err()
+}
+
+func printReport(report *sim.Report) {
+	fmt.Printf("episodes:       %d\n", report.Episodes)
+	fmt.Printf("solve rate:     %.1f%%\n", report.SolveRate*100)
+	if report.Outcomes[sim.OutcomeSolved] > 0 {
+		fmt.Printf("average turns:  %.1f (solved episodes)\n", report.AverageTurns)
+	}
+	fmt.Println("outcomes:")
+	for _, outcome := range []sim.Outcome{sim.OutcomeSolved, sim.OutcomeDied, sim.OutcomeStuck, sim.OutcomeTimedOut} {
+		fmt.Printf("  %-10s %d\n", outcome, report.Outcomes[outcome])
+	}
+	if len(report.DeathCauses) > 0 {
+		causes := report.SortedDeathCauses()
+		width := 0
+		for _, cause := range causes {
+			width = max(width, len(cause))
+		}
+		fmt.Println("death causes:")
+		for _, cause := range causes {
+			fmt.Printf("  %-*s  %d\n", width, cause, report.DeathCauses[cause])
+		}
+	}
+}
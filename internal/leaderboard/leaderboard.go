@@ -0,0 +1,136 @@
+// Package leaderboard records completed runs of levels, so that playtests and demos can
+// compare runs of the same level.
+package leaderboard
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Run is a completed run of a level.
+type Run struct {
+	Level       string        `json:"level"`
+	Player      string        `json:"player,omitempty"` // name of the principal that played the run, if known
+	SessionID   string        `json:"session_id"`
+	Turns       int           `json:"turns"`
+	Score       int           `json:"score"`
+	Duration    time.Duration `json:"duration"` // time from the session's creation to completing the level
+	CompletedAt time.Time     `json:"completed_at"`
+}
+
+// Store keeps completed runs. Stores must be safe for concurrent use.
+type Store interface {
+	// Record adds a completed run.
+	Record(run Run) error
+	// Top returns the n best runs of a level, best first. All runs are returned if n is 0.
+	Top(level string, n int) ([]Run, error)
+}
+
+// Better returns true if run a ranks above run b: the higher score wins, then the run with
+// fewer turns, then the faster run.
+func Better(a, b Run) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.Turns != b.Turns {
+		return a.Turns < b.Turns
+	}
+	return a.Duration < b.Duration
+}
+
+// Memory is a store that keeps runs in memory, so they are lost when the server stops.
+type Memory struct {
+	runs map[string][]Run // level name -> runs, best first
+	mu   sync.RWMutex
+}
+
+// NewMemory creates an empty in-memory store.
+func NewMemory() *Memory {
+	return &Memory{runs: make(map[string][]Run)}
+}
+
+func (m *Memory) Record(run Run) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	runs := m.runs[run.Level]
+	i := sort.Search(len(runs), func(i int) bool { return Better(run, runs[i]) })
+	m.runs[run.Level] = append(runs[:i], append([]Run{run}, runs[i:]...)...)
+	return nil
+}
+
+func (m *Memory) Top(level string, n int) ([]Run, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	runs := m.runs[level]
+	if n > 0 && n < len(runs) {
+		runs = runs[:n]
+	}
+	return append([]Run{}, runs...), nil
+}
+
+// File is a store that appends runs to a file, one JSON object per line, so they survive
+// restarts. The runs are read back into memory when the file is opened.
+type File struct {
+	path   string
+	memory *Memory
+	mu     sync.Mutex
+}
+
+// OpenFile opens the store kept in the file at path, creating the file on the first run
+// recorded if it does not exist.
+func OpenFile(path string) (*File, error) {
+	store := &File{path: path, memory: NewMemory()}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		store.memory.Record(run)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *File) Record(run Run) error {
+	line, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return s.memory.Record(run)
+}
+
+func (s *File) Top(level string, n int) ([]Run, error) {
+	return s.memory.Top(level, n)
+}
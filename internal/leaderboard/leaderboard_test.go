@@ -0,0 +1,71 @@
+package leaderboard
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func recordRuns(t *testing.T, store Store) {
+	t.Helper()
+	runs := []Run{
+		{Level: "crypt", SessionID: "a", Score: 80, Turns: 20, Duration: time.Minute},
+		{Level: "crypt", SessionID: "b", Score: 95, Turns: 12, Duration: 2 * time.Minute},
+		{Level: "crypt", SessionID: "c", Score: 80, Turns: 20, Duration: 30 * time.Second},
+		{Level: "crypt", SessionID: "d", Score: 80, Turns: 15, Duration: 5 * time.Minute},
+		{Level: "tower", SessionID: "e", Score: 100, Turns: 10, Duration: time.Minute},
+	}
+	for _, run := range runs {
+		if err := store.Record(run); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+}
+
+func sessionIDs(runs []Run) []string {
+	var ids []string
+	for _, run := range runs {
+		ids = append(ids, run.SessionID)
+	}
+	return ids
+}
+
+func TestMemory_Top(t *testing.T) {
+	store := NewMemory()
+	recordRuns(t, store)
+
+	top, err := store.Top("crypt", 3)
+	if err != nil {
+		t.Fatalf("Top failed: %v", err)
+	}
+	if ids := sessionIDs(top); len(ids) != 3 || ids[0] != "b" || ids[1] != "d" || ids[2] != "c" {
+		t.Errorf("Expected runs b, d and c, got %v", ids)
+	}
+	if all, _ := store.Top("crypt", 0); len(all) != 4 {
+		t.Errorf("Expected all 4 crypt runs, got %d", len(all))
+	}
+	if none, _ := store.Top("cellar", 10); len(none) != 0 {
+		t.Errorf("Expected no runs for an unplayed level, got %v", none)
+	}
+}
+
+func TestFile_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.jsonl")
+	store, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	recordRuns(t, store)
+
+	reopened, err := OpenFile(path)
+	if err != nil {
+		t.Fatalf("Reopening failed: %v", err)
+	}
+	top, err := reopened.Top("crypt", 1)
+	if err != nil {
+		t.Fatalf("Top failed: %v", err)
+	}
+	if len(top) != 1 || top[0].SessionID != "b" || top[0].Duration != 2*time.Minute {
+		t.Errorf("Expected run b to be read back, got %+v", top)
+	}
+}
@@ -0,0 +1,83 @@
+// Package library holds the levels bundled with the server, so clients can start a game by
+// level name instead of uploading the level.
+package library
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+
+	"adventure-engine/pkg/loader"
+)
+
+//go:embed levels/*.json
+var files embed.FS
+
+// Level is a bundled level in loader format.
+type Level struct {
+	Name           string
+	Theme          string
+	IntroNarrative string
+	VerbAliases    map[string]string
+	Data           json.RawMessage
+}
+
+// levels holds the bundled levels sorted by name.
+var levels = mustReadLevels()
+
+// List returns the bundled levels sorted by name.
+func List() []Level {
+	return levels
+}
+
+// Get returns the bundled level with the given name.
+func Get(name string) (Level, bool) {
+	for _, level := range levels {
+		if level.Name == name {
+			return level, true
+		}
+	}
+	return Level{}, false
+}
+
+func mustReadLevels() []Level {
+	levels, err := readLevels()
+	if err != nil {
+		panic(err)
+	}
+	return levels
+}
+
+func readLevels() ([]Level, error) {
+	entries, err := files.ReadDir("levels")
+	if err != nil {
+		return nil, err
+	}
+	var levels []Level
+	names := make(map[string]bool)
+	for _, entry := range entries {
+		data, err := files.ReadFile(path.Join("levels", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var header loader.GameData
+		if err := json.Unmarshal(data, &header); err != nil {
+			return nil, fmt.Errorf("bundled level %s: %w", entry.Name(), err)
+		}
+		if names[header.Name] {
+			return nil, fmt.Errorf("bundled level %s: duplicate level name %s", entry.Name(), header.Name)
+		}
+		names[header.Name] = true
+		levels = append(levels, Level{
+			Name:           header.Name,
+			Theme:          header.Theme,
+			IntroNarrative: header.IntroNarrative,
+			VerbAliases:    header.VerbAliases,
+			Data:           data,
+		})
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Name < levels[j].Name })
+	return levels, nil
+}
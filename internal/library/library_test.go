@@ -0,0 +1,35 @@
+package library
+
+import (
+	"testing"
+
+	"adventure-engine/pkg/loader"
+)
+
+func TestBundledLevelsLoad(t *testing.T) {
+	if len(List()) == 0 {
+		t.Fatal("Expected bundled levels")
+	}
+	for _, level := range List() {
+		diagnostics := loader.ValidateLevel(level.Data)
+		if diagnostics.HasErrors() {
+			t.Errorf("Bundled level %s is invalid: %v", level.Name, diagnostics.Errors())
+		}
+		if len(diagnostics.Warnings()) > 0 {
+			t.Errorf("Bundled level %s has warnings: %v", level.Name, diagnostics.Warnings())
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	level, ok := Get("demo puzzle")
+	if !ok {
+		t.Fatal("Expected the demo puzzle to be bundled")
+	}
+	if _, err := loader.LoadGame(level.Data); err != nil {
+		t.Errorf("Failed to load demo puzzle: %v", err)
+	}
+	if _, ok := Get("no such level"); ok {
+		t.Error("Expected no level for an unknown name")
+	}
+}
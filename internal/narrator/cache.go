@@ -0,0 +1,65 @@
+package narrator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+)
+
+// cached is a narrator that remembers its narrations
+type cached struct {
+	narrator Narrator
+	size     int
+	entries  map[[sha256.Size]byte]string
+	order    [][sha256.Size]byte // keys oldest first, for eviction
+	mu       sync.Mutex
+}
+
+// Cached wraps a narrator so that it is asked only once for the same narration, such as
+// looking around an unchanged room again. Narrations are keyed by the level theme, the action
+// and its response, but not the history. The size most recent narrations are kept,
+// and failed narrations are not cached.
+func Cached(narrator Narrator, size int) Narrator {
+	return &cached{
+		narrator: narrator,
+		size:     size,
+		entries:  make(map[[sha256.Size]byte]string),
+	}
+}
+
+func (c *cached) Narrate(ctx context.Context, request Request) (string, error) {
+	data, err := json.Marshal(struct {
+		Theme    string
+		Action   string
+		Response any
+	}{request.Theme, request.Action, request.Response})
+	if err != nil {
+		return c.narrator.Narrate(ctx, request)
+	}
+	key := sha256.Sum256(data)
+
+	c.mu.Lock()
+	narration, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok {
+		return narration, nil
+	}
+
+	narration, err = c.narrator.Narrate(ctx, request)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.entries[key] = narration
+		c.order = append(c.order, key)
+		if len(c.order) > c.size {
+			delete(c.entries, c.order[0])
+			c.order = c.order[1:]
+		}
+	}
+	return narration, nil
+}
@@ -0,0 +1,182 @@
+package narrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Default endpoints of the model APIs
+const (
+	OpenAIBaseURL    = "https://api.openai.com/v1"
+	AnthropicBaseURL = "https://api.anthropic.com/v1"
+	OllamaBaseURL    = "http://localhost:11434/v1" // local models served by Ollama
+)
+
+// anthropicVersion is the version of the Anthropic messages API the adapter speaks
+const anthropicVersion = "2023-06-01"
+
+// maxTokens limits the length of a model's narration
+const maxTokens = 300
+
+const systemPrompt = `You are the game master of a text adventure, narrating the results of the player's actions.
+Write in the second person and the present tense, in at most four sentences.
+Use only the facts in the result: do not invent items, exits, enemies or outcomes, and do not give hints.
+Keep item and door names as they are, so the player can refer to them.`
+
+// prompt returns the system prompt and the user message asking a model to narrate a request
+func prompt(request Request) (string, string, error) {
+	system := systemPrompt
+	if request.Theme != "" {
+		system += "\nThe setting is " + request.Theme + "; match its tone."
+	}
+
+	result, err := json.Marshal(request.Response)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal result: %w", err)
+	}
+	var message strings.Builder
+	if len(request.History) > 0 {
+		message.WriteString("Recent actions:\n")
+		for _, exchange := range request.History {
+			fmt.Fprintf(&message, "> %s\n%s\n", exchange.Action, exchange.Narration)
+		}
+		message.WriteString("\n")
+	}
+	fmt.Fprintf(&message, "The player's action: %s\nThe result, as JSON: %s\n\nNarrate the result.", request.Action, result)
+	return system, message.String(), nil
+}
+
+// OpenAI narrates with a model behind a chat completions API. Besides OpenAI's own, this is
+// the API local model servers such as Ollama and llama.cpp speak.
+type OpenAI struct {
+	BaseURL string // defaults to OpenAIBaseURL
+	APIKey  string // may be empty for local servers
+	Model   string
+	Client  *http.Client // defaults to http.DefaultClient
+}
+
+func (o *OpenAI) Narrate(ctx context.Context, request Request) (string, error) {
+	system, message, err := prompt(request)
+	if err != nil {
+		return "", err
+	}
+	body := map[string]any{
+		"model":      o.Model,
+		"max_tokens": maxTokens,
+		"messages": []map[string]string{
+			{"role": "system", "content": system},
+			{"role": "user", "content": message},
+		},
+	}
+	headers := map[string]string{}
+	if o.APIKey != "" {
+		headers["Authorization"] = "Bearer " + o.APIKey
+	}
+
+	var response struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := post(ctx, o.Client, orDefault(o.BaseURL, OpenAIBaseURL)+"/chat/completions", headers, body, &response); err != nil {
+		return "", err
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("model returned no choices")
+	}
+	return strings.TrimSpace(response.Choices[0].Message.Content), nil
+}
+
+// Anthropic narrates with a model behind Anthropic's messages API.
+type Anthropic struct {
+	BaseURL string // defaults to AnthropicBaseURL
+	APIKey  string
+	Model   string
+	Client  *http.Client // defaults to http.DefaultClient
+}
+
+func (a *Anthropic) Narrate(ctx context.Context, request Request) (string, error) {
+	system, message, err := prompt(request)
+	if err != nil {
+		return "", err
+	}
+	body := map[string]any{
+		"model":      a.Model,
+		"max_tokens": maxTokens,
+		"system":     system,
+		"messages": []map[string]string{
+			{"role": "user", "content": message},
+		},
+	}
+	headers := map[string]string{
+		"x-api-key":         a.APIKey,
+		"anthropic-version": anthropicVersion,
+	}
+
+	var response struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := post(ctx, a.Client, orDefault(a.BaseURL, AnthropicBaseURL)+"/messages", headers, body, &response); err != nil {
+		return "", err
+	}
+	var text strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("model returned no text")
+	}
+	return strings.TrimSpace(text.String()), nil
+}
+
+// post sends a JSON request to a model API and decodes the JSON response
+func post(ctx context.Context, client *http.Client, url string, headers map[string]string, body any, response any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("model API responded %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("failed to decode model API response: %w", err)
+	}
+	return nil
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
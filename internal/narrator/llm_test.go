@@ -0,0 +1,100 @@
+package narrator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var testRequest = Request{
+	Theme:    "survival horror",
+	Action:   "take iron key",
+	Response: map[string]any{"added_to_inventory": map[string]string{"name": "iron key"}},
+	History:  []Exchange{{Action: "search safe", Narration: "You find a mysterious iron key."}},
+}
+
+// modelServer serves a model API, checking each request before answering with response
+func modelServer(t *testing.T, path string, check func(r *http.Request, body map[string]any), response string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			t.Errorf("Expected a request to %s, got %s", path, r.URL.Path)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		check(r, body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenAI(t *testing.T) {
+	server := modelServer(t, "/v1/chat/completions", func(r *http.Request, body map[string]any) {
+		if r.Header.Get("Authorization") != "Bearer sk-test" {
+			t.Errorf("Expected the API key as a bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		if body["model"] != "test-model" {
+			t.Errorf("Expected model test-model, got %v", body["model"])
+		}
+		messages := body["messages"].([]any)
+		system := messages[0].(map[string]any)["content"].(string)
+		user := messages[1].(map[string]any)["content"].(string)
+		if !strings.Contains(system, "survival horror") {
+			t.Errorf("Expected the theme in the system prompt, got %q", system)
+		}
+		for _, want := range []string{"> search safe", "take iron key", `"added_to_inventory"`} {
+			if !strings.Contains(user, want) {
+				t.Errorf("Expected %q in the prompt, got %q", want, user)
+			}
+		}
+	}, `{"choices": [{"message": {"role": "assistant", "content": " The key is ice cold. "}}]}`)
+
+	narrator := &OpenAI{BaseURL: server.URL + "/v1", APIKey: "sk-test", Model: "test-model"}
+	narration, err := narrator.Narrate(context.Background(), testRequest)
+	if err != nil {
+		t.Fatalf("Narrate failed: %v", err)
+	}
+	if narration != "The key is ice cold." {
+		t.Errorf("Unexpected narration %q", narration)
+	}
+}
+
+func TestAnthropic(t *testing.T) {
+	server := modelServer(t, "/v1/messages", func(r *http.Request, body map[string]any) {
+		if r.Header.Get("x-api-key") != "test-key" || r.Header.Get("anthropic-version") == "" {
+			t.Errorf("Expected the API key and version headers, got %v", r.Header)
+		}
+		if !strings.Contains(body["system"].(string), "game master") {
+			t.Errorf("Expected the system prompt, got %v", body["system"])
+		}
+	}, `{"content": [{"type": "text", "text": "The key is ice cold."}]}`)
+
+	narrator := &Anthropic{BaseURL: server.URL + "/v1", APIKey: "test-key", Model: "test-model"}
+	narration, err := narrator.Narrate(context.Background(), testRequest)
+	if err != nil {
+		t.Fatalf("Narrate failed: %v", err)
+	}
+	if narration != "The key is ice cold." {
+		t.Errorf("Unexpected narration %q", narration)
+	}
+}
+
+func TestModelAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "rate limited"}`, http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	narrator := &OpenAI{BaseURL: server.URL, Model: "test-model"}
+	_, err := narrator.Narrate(context.Background(), testRequest)
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("Expected the API's status in the error, got %v", err)
+	}
+}
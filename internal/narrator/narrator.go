@@ -0,0 +1,90 @@
+// Package narrator turns action results into prose, for clients that display text
+// directly rather than describing the structured results themselves.
+//
+// A Narrator is pluggable: Templates narrates from built-in templates chosen by the level's
+// theme (system_prompt_theme), while OpenAI and Anthropic have a language model act as game
+// master. Model narrators are given the structured result and the session's recent history,
+// and can be wrapped with Cached. A Session narrates one game session's actions, keeping its
+// history and falling back to templates when a model fails or times out.
+package narrator
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"adventure-engine/internal/parser"
+)
+
+// Narrator tells the result of an action as prose.
+type Narrator interface {
+	Narrate(ctx context.Context, request Request) (string, error)
+}
+
+// Request is an action to narrate.
+type Request struct {
+	Theme    string     // the level's system_prompt_theme
+	Action   string     // what the player did, such as "unlock safe with 2468"
+	Result   any        // the engine result, such as *engine.TakeResult
+	Response any        // the result as returned to clients, for narrators that pass it to a model
+	History  []Exchange // the session's recent actions, oldest first
+}
+
+// Exchange is a narrated action.
+type Exchange struct {
+	Action    string `json:"action"`
+	Narration string `json:"narration"`
+}
+
+// HistorySize is how many recent actions a narrator is given
+const HistorySize = 8
+
+// Session narrates the actions of one game session.
+// A nil Session narrates nothing, so callers can leave narration off by not creating one.
+// Sessions are not safe for concurrent use; callers hold the game session's lock.
+type Session struct {
+	narrator Narrator
+	theme    string
+	timeout  time.Duration
+	history  []Exchange
+}
+
+// NewSession returns a session narrating a level with the given theme.
+// Each narration is cancelled after timeout, unless timeout is 0.
+func NewSession(narrator Narrator, theme string, timeout time.Duration) *Session {
+	return &Session{narrator: narrator, theme: theme, timeout: timeout}
+}
+
+// Narrate narrates the result of an action, given both as the engine result and as the
+// response returned to clients. If the narrator fails, the result is narrated from templates
+// instead, so narration never fails an action.
+func (s *Session) Narrate(action parser.Action, result any, response any) string {
+	if s == nil {
+		return ""
+	}
+	request := Request{
+		Theme:    s.theme,
+		Action:   action.String(),
+		Result:   result,
+		Response: response,
+		History:  s.history,
+	}
+
+	ctx := context.Background()
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	narration, err := s.narrator.Narrate(ctx, request)
+	if err != nil {
+		log.Printf("narrator failed, falling back to templates: %v", err)
+		narration, _ = Templates{}.Narrate(ctx, request)
+	}
+
+	s.history = append(s.history, Exchange{Action: request.Action, Narration: narration})
+	if len(s.history) > HistorySize {
+		s.history = s.history[len(s.history)-HistorySize:]
+	}
+	return narration
+}
@@ -0,0 +1,112 @@
+package narrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"adventure-engine/internal/parser"
+)
+
+// fakeNarrator records the requests it is given
+type fakeNarrator struct {
+	requests []Request
+	err      error
+	delay    time.Duration
+}
+
+func (f *fakeNarrator) Narrate(ctx context.Context, request Request) (string, error) {
+	f.requests = append(f.requests, request)
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	if f.err != nil {
+		return "", f.err
+	}
+	return fmt.Sprintf("narration %d", len(f.requests)), nil
+}
+
+func TestSession_History(t *testing.T) {
+	fake := &fakeNarrator{}
+	session := NewSession(fake, "survival horror", 0)
+	for i := 0; i < HistorySize+2; i++ {
+		session.Narrate(parser.Action{Verb: parser.VerbTake, Target: fmt.Sprintf("item %d", i)}, nil, nil)
+	}
+
+	last := fake.requests[len(fake.requests)-1]
+	if last.Theme != "survival horror" || last.Action != fmt.Sprintf("take item %d", HistorySize+1) {
+		t.Errorf("Unexpected request %+v", last)
+	}
+	if len(last.History) != HistorySize {
+		t.Fatalf("Expected %d exchanges of history, got %d", HistorySize, len(last.History))
+	}
+	if last.History[0].Action != "take item 1" || last.History[HistorySize-1].Narration != fmt.Sprintf("narration %d", HistorySize+1) {
+		t.Errorf("Expected the most recent exchanges, oldest first, got %+v", last.History)
+	}
+}
+
+func TestSession_FallsBackToTemplates(t *testing.T) {
+	e := newDemoEngine(t)
+	result, err := e.Traverse(context.Background(), "left")
+	if err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	action := parser.Action{Verb: parser.VerbTraverse, Target: "left"}
+
+	failing := NewSession(&fakeNarrator{err: errors.New("model unavailable")}, e.Level.Theme, 0)
+	if narration := failing.Narrate(action, result, nil); narration != narrate(t, e.Level.Theme, result) {
+		t.Errorf("Expected template narration when the narrator fails, got %q", narration)
+	}
+
+	slow := NewSession(&fakeNarrator{delay: time.Second}, e.Level.Theme, 10*time.Millisecond)
+	start := time.Now()
+	if narration := slow.Narrate(action, result, nil); narration != narrate(t, e.Level.Theme, result) {
+		t.Errorf("Expected template narration when the narrator times out, got %q", narration)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("Expected the narration to be cut off at the timeout")
+	}
+
+	var off *Session
+	if narration := off.Narrate(action, result, nil); narration != "" {
+		t.Errorf("Expected no narration from a nil session, got %q", narration)
+	}
+}
+
+func TestCached(t *testing.T) {
+	fake := &fakeNarrator{}
+	narrator := Cached(fake, 2)
+	ctx := context.Background()
+	look := Request{Action: "look around", Response: map[string]string{"room": "office"}}
+
+	first, _ := narrator.Narrate(ctx, look)
+	withHistory := look
+	withHistory.History = []Exchange{{Action: "go ahead", Narration: "You enter the office."}}
+	second, _ := narrator.Narrate(ctx, withHistory)
+	if first != second || len(fake.requests) != 1 {
+		t.Errorf("Expected the same narration from the cache, got %q and %q after %d requests", first, second, len(fake.requests))
+	}
+
+	// Two other narrations evict the first
+	narrator.Narrate(ctx, Request{Action: "inventory"})
+	narrator.Narrate(ctx, Request{Action: "map"})
+	narrator.Narrate(ctx, look)
+	if len(fake.requests) != 4 {
+		t.Errorf("Expected the evicted narration to be asked for again, got %d requests", len(fake.requests))
+	}
+
+	fake.err = errors.New("model unavailable")
+	if _, err := narrator.Narrate(ctx, Request{Action: "take key"}); err == nil {
+		t.Fatal("Expected the narrator's error")
+	}
+	fake.err = nil
+	if narration, err := narrator.Narrate(ctx, Request{Action: "take key"}); err != nil || narration == "" {
+		t.Errorf("Expected failed narrations not to be cached, got %q, %v", narration, err)
+	}
+}
@@ -0,0 +1,768 @@
+package narrator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"adventure-engine/pkg/engine"
+	"adventure-engine/pkg/world"
+)
+
+// Style is a set of templates for narrating a kind of setting.
+type Style string
+
+const (
+	StylePlain   Style = "plain"
+	StyleHorror  Style = "horror"
+	StyleSciFi   Style = "sci_fi"
+	StyleFantasy Style = "fantasy"
+)
+
+// styleKeywords picks a style from words in a level's theme, checked in order
+var styleKeywords = []struct {
+	style    Style
+	keywords []string
+}{
+	{StyleHorror, []string{"horror", "zombie", "haunted", "dread", "gothic"}},
+	{StyleSciFi, []string{"sci-fi", "science fiction", "space", "cyberpunk", "station", "future"}},
+	{StyleFantasy, []string{"fantasy", "dungeon", "medieval", "magic", "castle"}},
+}
+
+// StyleForTheme returns the style that suits a level theme, or StylePlain if none does.
+func StyleForTheme(theme string) Style {
+	theme = strings.ToLower(theme)
+	for _, candidate := range styleKeywords {
+		for _, keyword := range candidate.keywords {
+			if strings.Contains(theme, keyword) {
+				return candidate.style
+			}
+		}
+	}
+	return StylePlain
+}
+
+// templates holds the sentences that differ between styles.
+// Templates with a verb take the name of the room or enemy they are about.
+type templates struct {
+	enterRoom   string
+	unlock      string
+	jammed      string // takes the name of the door or container whose keypad jammed
+	searchEmpty string
+	hit         string
+	missed      string
+	killed      string
+	playerDied  string
+	respawned   string // takes the name of the room the player respawns in
+	ambush      string // takes the enemy's description
+	healed      string
+	breathed    string // the player breathes from an air supply
+	movement    string // takes the name of the door something is heard behind
+	secretFound string
+}
+
+var styles = map[Style]templates{
+	StylePlain: {
+		enterRoom:   "You enter the %s.",
+		unlock:      "The lock opens.",
+		jammed:      "The keypad on the %s buzzes and goes dark. It won't take another code.",
+		searchEmpty: "You search the %s but find nothing.",
+		hit:         "You hit the %s.",
+		missed:      "The %s hits you.",
+		killed:      "You defeat the %s.",
+		playerDied:  "You have died.",
+		respawned:   "You have died. You come to in the %s, without anything you carried.",
+		ambush:      "%s attacks!",
+		healed:      "You tend to your wounds.",
+		breathed:    "You take a deep breath.",
+		movement:    "You hear something moving behind the %s.",
+		secretFound: "You have discovered a secret passage.",
+	},
+	StyleHorror: {
+		enterRoom:   "You edge into the %s.",
+		unlock:      "The lock gives way with a dull click.",
+		jammed:      "The keypad on the %s sputters and dies. Whatever it guarded stays shut.",
+		searchEmpty: "You search the %s, but there is nothing inside but dust.",
+		hit:         "You strike the %s and it reels back.",
+		missed:      "The %s lashes out and catches you.",
+		killed:      "The %s collapses and does not get up.",
+		playerDied:  "Your vision blurs, and everything goes dark.",
+		respawned:   "Everything goes dark, until you jolt awake in the %s with empty hands.",
+		ambush:      "Something moves in the shadows. %s lurches toward you!",
+		healed:      "You patch yourself up with trembling hands.",
+		breathed:    "You gulp down stale, metallic air.",
+		movement:    "Something shuffles and scrapes behind the %s.",
+		secretFound: "A way you were never meant to find lies open before you.",
+	},
+	StyleSciFi: {
+		enterRoom:   "You cycle through into the %s.",
+		unlock:      "The lock cycles open with a hiss.",
+		jammed:      "Lockout engaged: the keypad on the %s rejects all further input.",
+		searchEmpty: "You scan the %s. It is empty.",
+		hit:         "Your strike lands and the %s staggers.",
+		missed:      "The %s hits back, and warnings flash across your visor.",
+		killed:      "The %s goes still.",
+		playerDied:  "Your life signs flatline.",
+		respawned:   "Your life signs flatline. Backup restored in the %s. Inventory not recovered.",
+		ambush:      "Proximity alert: %s closes in!",
+		healed:      "You apply the treatment and your vitals steady.",
+		breathed:    "You draw on the reserve and your oxygen readout climbs.",
+		movement:    "Your audio sensors pick up movement behind the %s.",
+		secretFound: "Map updated: concealed passage detected.",
+	},
+	StyleFantasy: {
+		enterRoom:   "You pass into the %s.",
+		unlock:      "The lock turns with a satisfying clunk.",
+		jammed:      "The mechanism of the %s seizes with a grinding crunch.",
+		searchEmpty: "You search the %s, but it holds nothing of value.",
+		hit:         "Your blow strikes true against the %s.",
+		missed:      "The %s deals you a painful blow.",
+		killed:      "The %s falls, vanquished.",
+		playerDied:  "Your tale ends here.",
+		respawned:   "You fall, but your tale is not over: you rise again in the %s, stripped of your belongings.",
+		ambush:      "Beware! %s bars your way!",
+		healed:      "You feel your strength return.",
+		breathed:    "You fill your lungs, and the panic ebbs.",
+		movement:    "You hear something stirring beyond the %s.",
+		secretFound: "A secret way lies revealed!",
+	},
+}
+
+// Templates narrates results with the templates of the style that suits the level's theme.
+// It needs no model, so it is the default narrator and the fallback when a model fails.
+type Templates struct{}
+
+func (Templates) Narrate(ctx context.Context, request Request) (string, error) {
+	return styles[StyleForTheme(request.Theme)].narrate(request.Result), nil
+}
+
+// narrate describes an engine action result, or returns "" for results it does not narrate
+func (t templates) narrate(result any) string {
+	var sentences []string
+	var state engine.EngineStateInfo
+	switch r := result.(type) {
+	case *engine.ObserveResult:
+		sentences = t.room(r.Result.RoomName, r.Result.RoomDescription, r.Result.VisibleItems, r.Result.Doors)
+		sentences = append(sentences, travelNode(r.Result.TravelNode)...)
+		sentences = append(sentences, fire(r.Result.Burning)...)
+		state = r.EngineStateInfo
+	case *engine.InspectResult:
+		sentences = t.inspect(r)
+		state = r.EngineStateInfo
+	case *engine.UncoverResult:
+		sentences = []string{fmt.Sprintf("You move the %s aside, revealing %s.", r.Result.Name, describe(r.Result.RevealedItem))}
+		state = r.EngineStateInfo
+	case *engine.MoveResult:
+		sentences = move(r)
+		state = r.EngineStateInfo
+	case *engine.UnlockResult:
+		if r.Result.Unlocked {
+			sentences = []string{t.unlock}
+		}
+		if r.Result.Jammed {
+			sentences = []string{fmt.Sprintf(t.jammed, r.Result.Target)}
+		}
+		state = r.EngineStateInfo
+	case *engine.SearchResult:
+		if r.Result.Unlocked {
+			sentences = append(sentences, "You unlock it with your key.")
+		}
+		if r.Result.ContainedItemInfo != nil {
+			sentences = append(sentences, fmt.Sprintf("You search the %s and find %s.", r.Result.ContainerName, describe(*r.Result.ContainedItemInfo)))
+		} else {
+			sentences = append(sentences, fmt.Sprintf(t.searchEmpty, r.Result.ContainerName))
+		}
+		state = r.EngineStateInfo
+	case *engine.InterrogateResult:
+		if r.Result.Answer != "" {
+			sentences = []string{fmt.Sprintf("The %s talks: %q", r.Result.EnemyName, r.Result.Answer)}
+		} else {
+			sentences = []string{fmt.Sprintf("The %s has nothing to tell you.", r.Result.EnemyName)}
+		}
+		state = r.EngineStateInfo
+	case *engine.TakeResult:
+		sentences = []string{fmt.Sprintf("You take the %s%s.", r.Result.ItemInfo.Name, quantity(r.Result.ItemInfo))}
+		sentences = append(sentences, trap(r.Result.Trap)...)
+		state = r.EngineStateInfo
+	case *engine.InventoryResult:
+		sentences = inventory(r)
+		state = r.EngineStateInfo
+	case *engine.StatusResult:
+		sentences = status(r)
+		state = r.EngineStateInfo
+	case *engine.HealResult:
+		if r.Result.Breathed {
+			sentences = []string{t.breathed}
+		} else {
+			sentences = []string{t.healed, health(r.Result.Health)}
+		}
+		if r.Result.Cured {
+			sentences = append(sentences, "The poison leaves your body.")
+		}
+		sentences = append(sentences, chargesLeft(r.Result.ChargesLeft, r.Result.MaxCharges)...)
+		sentences = append(sentences, trap(r.Result.Trap)...)
+		state = r.EngineStateInfo
+	case *engine.WaitResult:
+		sentences = wait(r)
+		state = r.EngineStateInfo
+	case *engine.TraverseResult:
+		sentences = t.traverse(r)
+		state = r.EngineStateInfo
+	case *engine.TravelResult:
+		sentences = t.travel(r)
+		state = r.EngineStateInfo
+	case *engine.ListenResult:
+		sentences = t.listen(r)
+		state = r.EngineStateInfo
+	case *engine.PeekResult:
+		sentences = peek(r)
+		state = r.EngineStateInfo
+	case *engine.LatchResult:
+		sentences = []string{fmt.Sprintf("You latch the %s.", r.Result.DoorName)}
+		state = r.EngineStateInfo
+	case *engine.BattleResult:
+		sentences = t.battle(r)
+		state = r.EngineStateInfo
+	case *engine.ResolveResult:
+		sentences = t.resolve(r)
+		state = r.EngineStateInfo
+	case *engine.CombineResult:
+		sentences = []string{fmt.Sprintf("You combine them into %s.", describe(r.Result.CraftedItem))}
+		if len(r.Result.Byproducts) > 0 {
+			byproducts := make([]string, len(r.Result.Byproducts))
+			for i, byproduct := range r.Result.Byproducts {
+				byproducts[i] = describe(byproduct)
+			}
+			sentences = append(sentences, fmt.Sprintf("You are left with %s.", list(byproducts)))
+		}
+		state = r.EngineStateInfo
+	case *engine.UseResult:
+		sentences = use(r)
+		state = r.EngineStateInfo
+	default:
+		return ""
+	}
+	sentences = append(sentences, breath(state.Breath)...)
+	sentences = append(sentences, t.stateChange(state)...)
+	return strings.Join(sentences, " ")
+}
+
+// room describes a room, its items and its doors
+func (t templates) room(name, description string, items []engine.ItemInfo, doors []engine.DoorInfo) []string {
+	sentences := []string{fmt.Sprintf("You are in the %s: %s.", name, strings.TrimSuffix(description, "."))}
+	// Items are grouped by where in the room they are, in the order the room lists them, which
+	// puts those at no sub-location in particular first
+	var places []world.SubLocation
+	described := make(map[world.SubLocation][]string)
+	for _, item := range items {
+		place := item.SubLocation
+		if _, ok := described[place]; !ok {
+			places = append(places, place)
+		}
+		var notes []string
+		if item.Quantity > 1 {
+			notes = append(notes, fmt.Sprintf("x%d", item.Quantity))
+		}
+		if item.Location != "" && item.Location != place.Phrase() {
+			notes = append(notes, item.Location)
+		}
+		phrase := itemDescription(item)
+		if len(notes) > 0 {
+			phrase += " (" + strings.Join(notes, ", ") + ")"
+		}
+		described[place] = append(described[place], phrase)
+	}
+	for _, place := range places {
+		if place.Name == "" {
+			sentences = append(sentences, fmt.Sprintf("You see %s.", list(described[place])))
+		} else {
+			sentences = append(sentences, fmt.Sprintf("%s you see %s.", capitalize(place.Phrase()), list(described[place])))
+		}
+	}
+	if len(doors) > 0 {
+		described := make([]string, len(doors))
+		for i, door := range doors {
+			described[i] = "the " + door.Name
+			if door.Location != "" {
+				described[i] += " " + direction(door.Location)
+			}
+			if door.IsLocked {
+				described[i] += ", which is locked"
+			}
+		}
+		sentences = append(sentences, fmt.Sprintf("There is %s.", list(described)))
+	}
+	return sentences
+}
+
+func (t templates) inspect(r *engine.InspectResult) []string {
+	if item := r.Result.ItemInspection; item != nil {
+		sentences := []string{fmt.Sprintf("You look closely at the %s: %s.", item.Name, strings.TrimSuffix(itemDescription(item.ItemInfo), "."))}
+		if item.Detail != "" {
+			sentences = append(sentences, detail(item.Detail))
+		}
+		if item.IsLocked {
+			sentences = append(sentences, "It is locked.")
+		}
+		for _, recipe := range item.LearnedRecipes {
+			sentences = append(sentences, fmt.Sprintf("You learn how to make the %s.", recipe))
+		}
+		return sentences
+	}
+	if door := r.Result.DoorInspection; door != nil {
+		sentences := []string{fmt.Sprintf("You look closely at the %s.", door.Name)}
+		if door.Description != "" {
+			sentences = append(sentences, capitalize(strings.TrimSuffix(door.Description, "."))+".")
+		}
+		if door.IsLocked && door.HasCodeLock {
+			sentences = append(sentences, "It is locked with a keypad.")
+		} else if door.IsLocked {
+			sentences = append(sentences, "It is locked.")
+		}
+		if door.IsOneWay {
+			sentences = append(sentences, "Once through it, there is no way back.")
+		}
+		return sentences
+	}
+	return nil
+}
+
+func inventory(r *engine.InventoryResult) []string {
+	if len(r.Result.Items) == 0 {
+		return []string{"You are carrying nothing."}
+	}
+	names := make([]string, len(r.Result.Items))
+	for i, item := range r.Result.Items {
+		names[i] = "the " + item.Name + quantity(item)
+		if item.IsWorn {
+			names[i] += " (worn)"
+		}
+		if item.MaxCharges > 0 {
+			names[i] += fmt.Sprintf(" (%d of %d uses left)", item.Charges, item.MaxCharges)
+		}
+	}
+	sentences := []string{fmt.Sprintf("You are carrying %s.", list(names))}
+	for _, ammo := range r.Result.Ammo {
+		sentences = append(sentences, fmt.Sprintf("You have %d rounds of %s ammo.", ammo.AmmoCount, ammo.AmmoType))
+	}
+	return sentences
+}
+
+func status(r *engine.StatusResult) []string {
+	sentences := []string{health(r.Result.Health)}
+	if r.Result.Weapon == "fists" {
+		sentences = append(sentences, "You have nothing to fight with but your fists.")
+	} else {
+		sentences = append(sentences, fmt.Sprintf("You have the %s at hand.", r.Result.Weapon))
+	}
+	if len(r.Result.Armor) > 0 {
+		sentences = append(sentences, fmt.Sprintf("You are wearing the %s.", list(r.Result.Armor)))
+	}
+	if slices.Contains(r.Result.Effects, engine.StatusPoisoned) {
+		sentences = append(sentences, "You are poisoned.")
+	}
+	return sentences
+}
+
+func wait(r *engine.WaitResult) []string {
+	var sentences []string
+	switch {
+	case r.Result.Rested && r.Result.Turns == 1:
+		sentences = []string{"You rest for a moment."}
+	case r.Result.Rested:
+		sentences = []string{fmt.Sprintf("You rest for %d turns.", r.Result.Turns)}
+	case r.Result.Turns == 1:
+		sentences = []string{"Time passes."}
+	default:
+		sentences = []string{fmt.Sprintf("You wait for %d turns.", r.Result.Turns)}
+	}
+	if r.Result.Healed > 0 {
+		sentences = append(sentences, health(r.Result.Health))
+	}
+	if r.Result.Encounter != "" {
+		sentences = append(sentences, "Your rest is cut short.")
+	}
+	return sentences
+}
+
+func (t templates) traverse(r *engine.TraverseResult) []string {
+	var sentences []string
+	if r.Result.Unlocked {
+		sentences = append(sentences, "You unlock the door with your key.")
+	}
+	if r.Result.Unlatched {
+		sentences = append(sentences, "You unlatch the door.")
+	}
+	if r.Result.ChangedFloor != nil {
+		sentences = append(sentences, fmt.Sprintf("You make your way to the %s.", r.Result.ChangedFloor.Name))
+	}
+	room := r.Result.EnteredRoom
+	sentences = append(sentences, fmt.Sprintf(t.enterRoom, room.RoomName))
+	sentences = append(sentences, t.room(room.RoomName, room.RoomDescription, room.VisibleItems, room.Doors)[1:]...)
+	sentences = append(sentences, travelNode(room.TravelNode)...)
+	return append(sentences, fire(room.Burning)...)
+}
+
+func (t templates) travel(r *engine.TravelResult) []string {
+	sentences := []string{fmt.Sprintf("You take the %s to the %s.", r.Result.FromNode, r.Result.ToNode)}
+	if r.Result.ChangedFloor != nil {
+		sentences = append(sentences, fmt.Sprintf("You arrive on the %s.", r.Result.ChangedFloor.Name))
+	}
+	room := r.Result.EnteredRoom
+	sentences = append(sentences, fmt.Sprintf(t.enterRoom, room.RoomName))
+	return append(sentences, t.room(room.RoomName, room.RoomDescription, room.VisibleItems, room.Doors)[1:]...)
+}
+
+// fire describes a room that is on fire
+func fire(burning bool) []string {
+	if !burning {
+		return nil
+	}
+	return []string{"Flames lick at the walls."}
+}
+
+// travelNode describes the fast travel point in a room, if there is one
+func travelNode(node *engine.TravelNodeInfo) []string {
+	if node == nil {
+		return nil
+	}
+	sentences := []string{fmt.Sprintf("The %s here can take you elsewhere.", node.Name)}
+	if !node.Powered {
+		sentences = append(sentences, "It has no power.")
+	}
+	return sentences
+}
+
+func (t templates) listen(r *engine.ListenResult) []string {
+	sentences := []string{fmt.Sprintf("You press your ear to the %s.", r.Result.DoorName)}
+	switch {
+	case r.Result.EnemyName != "":
+		sentences = append(sentences, fmt.Sprintf("Through the bars you make out the %s.", r.Result.EnemyName))
+	case r.Result.Movement:
+		sentences = append(sentences, fmt.Sprintf(t.movement, r.Result.DoorName))
+	default:
+		sentences = append(sentences, "You hear nothing moving.")
+	}
+	return sentences
+}
+
+func peek(r *engine.PeekResult) []string {
+	sentences := []string{fmt.Sprintf("Through the %s you see the %s: %s.", r.Result.DoorName, r.Result.RoomName, strings.TrimSuffix(r.Result.RoomDescription, "."))}
+	if r.Result.EnemyDescription != "" {
+		sentences = append(sentences, fmt.Sprintf("Inside is %s.", strings.TrimSuffix(r.Result.EnemyDescription, ".")))
+	} else if r.Result.EnemyName != "" {
+		sentences = append(sentences, fmt.Sprintf("The %s is inside.", r.Result.EnemyName))
+	}
+	return sentences
+}
+
+func (t templates) battle(r *engine.BattleResult) []string {
+	var sentences []string
+	switch {
+	case r.Result.WonRound:
+		sentences = append(sentences, fmt.Sprintf(t.hit, r.Result.EnemyName))
+	case r.Result.Absorbed != "":
+		sentences = append(sentences, fmt.Sprintf("The %s lunges at you, but your %s takes the blow.", r.Result.EnemyName, r.Result.Absorbed))
+	default:
+		sentences = append(sentences, fmt.Sprintf(t.missed, r.Result.EnemyName))
+	}
+	switch {
+	case r.Result.Narrative != "":
+		sentences = append(sentences, capitalize(strings.TrimSuffix(r.Result.Narrative, "."))+".")
+	case r.Result.Critical:
+		sentences = append(sentences, "It is a critical hit.")
+	case r.Result.Fumbled:
+		sentences = append(sentences, "Your weapon slips from your grasp and clatters to the floor.")
+	}
+	if !r.Result.EnemyAlive {
+		sentences = append(sentences, fmt.Sprintf(t.killed, r.Result.EnemyName))
+	}
+	if r.Result.Surrendered {
+		sentences = append(sentences, fmt.Sprintf("The %s drops to its knees and surrenders.", r.Result.EnemyName))
+	}
+	if len(r.Result.AlsoHit) > 0 {
+		sentences = append(sentences, fmt.Sprintf("The blast also catches the %s.", list(r.Result.AlsoHit)))
+	}
+	for _, enemyName := range r.Result.AlsoKilled {
+		sentences = append(sentences, fmt.Sprintf(t.killed, enemyName))
+	}
+	if r.Result.StartedFire {
+		sentences = append(sentences, "The room catches fire.")
+	}
+	if r.Result.BrokenItem != "" {
+		sentences = append(sentences, broke(r.Result.BrokenItem, r.Result.Scrap))
+	}
+	if r.Result.BrokenArmor != "" {
+		sentences = append(sentences, broke(r.Result.BrokenArmor, r.Result.ArmorScrap))
+	}
+	if r.Result.PlayerAlive && !r.Result.WonRound && r.Result.Absorbed == "" {
+		sentences = append(sentences, health(r.EngineStateInfo.PlayerHealth))
+	}
+	return sentences
+}
+
+func (t templates) resolve(r *engine.ResolveResult) []string {
+	enemy := r.Result.EnemyName
+	var sentences []string
+	switch {
+	case r.Result.Approach == "bribe":
+		return []string{fmt.Sprintf("You hand the %s to the %s, and it lets you be.", r.Result.BribeItem, enemy)}
+	case r.Result.Approach == "intimidate" && r.Result.Succeeded:
+		return []string{fmt.Sprintf("The %s backs away and slinks off.", enemy)}
+	case r.Result.Approach == "sneak" && r.Result.Succeeded:
+		return []string{fmt.Sprintf("You slip away from the %s unseen.", enemy)}
+	case r.Result.Approach == "intimidate":
+		sentences = append(sentences, fmt.Sprintf("The %s is not impressed.", enemy))
+	default:
+		sentences = append(sentences, fmt.Sprintf("The %s spots you.", enemy))
+	}
+	if r.Result.Absorbed != "" {
+		sentences = append(sentences, fmt.Sprintf("Your %s takes the blow.", r.Result.Absorbed))
+	} else {
+		sentences = append(sentences, fmt.Sprintf(t.missed, enemy))
+	}
+	if r.Result.BrokenArmor != "" {
+		sentences = append(sentences, broke(r.Result.BrokenArmor, r.Result.ArmorScrap))
+	}
+	if r.Result.PlayerAlive && r.Result.Absorbed == "" {
+		sentences = append(sentences, health(r.EngineStateInfo.PlayerHealth))
+	}
+	return sentences
+}
+
+func use(r *engine.UseResult) []string {
+	sentences := []string{fmt.Sprintf("You use the %s on the %s.", r.Result.UsedItemName, r.Result.FixtureName)}
+	for _, narrative := range []string{r.Result.InsertNarrative, r.Result.StageNarrative} {
+		if narrative != "" {
+			sentences = append(sentences, capitalize(strings.TrimSuffix(narrative, "."))+".")
+		}
+	}
+	switch {
+	case len(r.Result.MissingItems) > 0:
+		sentences = append(sentences, fmt.Sprintf("It still needs the %s.", list(r.Result.MissingItems)))
+	case r.Result.MissingCount == 1:
+		sentences = append(sentences, "It still needs one more thing.")
+	case r.Result.MissingCount > 1:
+		sentences = append(sentences, fmt.Sprintf("It still needs %d more things.", r.Result.MissingCount))
+	}
+	if r.Result.ProducedItem != nil {
+		sentences = append(sentences, fmt.Sprintf("You receive %s.", describe(*r.Result.ProducedItem)))
+	}
+	if r.Result.BrokenItem != "" {
+		sentences = append(sentences, broke(r.Result.BrokenItem, r.Result.Scrap))
+	}
+	sentences = append(sentences, chargesLeft(r.Result.ChargesLeft, r.Result.MaxCharges)...)
+	sentences = append(sentences, trap(r.Result.Trap)...)
+	if r.Result.CompletionNarrative != "" {
+		sentences = append(sentences, capitalize(r.Result.CompletionNarrative))
+	}
+	for _, effect := range r.Result.Effects {
+		switch world.EffectType(effect.Effect) {
+		case world.EffectUnlock:
+			sentences = append(sentences, fmt.Sprintf("Somewhere, the %s unlocks.", effect.Target))
+		case world.EffectRevealDoor:
+			sentences = append(sentences, fmt.Sprintf("A hidden %s is revealed.", effect.Target))
+		case world.EffectEndCombat:
+			sentences = append(sentences, fmt.Sprintf("The %s is driven off.", effect.Target))
+		case world.EffectPower:
+			sentences = append(sentences, fmt.Sprintf("Somewhere, the %s hums to life.", effect.Target))
+		}
+	}
+	return sentences
+}
+
+// move describes moving furniture and what was behind it
+func move(r *engine.MoveResult) []string {
+	var sentences []string
+	switch r.Result.Direction {
+	case "":
+		sentences = append(sentences, fmt.Sprintf("You move the %s.", r.Result.Name))
+	case "push", "pull":
+		sentences = append(sentences, fmt.Sprintf("You %s the %s.", r.Result.Direction, r.Result.Name))
+	default:
+		sentences = append(sentences, fmt.Sprintf("You move the %s %s.", r.Result.Name, r.Result.Direction))
+	}
+	if r.Result.Narrative != "" {
+		sentences = append(sentences, capitalize(strings.TrimSuffix(r.Result.Narrative, "."))+".")
+	}
+	if r.Result.RevealedItem != nil {
+		sentences = append(sentences, fmt.Sprintf("Behind it you find %s.", describe(*r.Result.RevealedItem)))
+	}
+	if r.Result.RevealedDoor != "" {
+		sentences = append(sentences, fmt.Sprintf("It was hiding the %s.", r.Result.RevealedDoor))
+	}
+	return sentences
+}
+
+// broke tells the player a weapon or tool wore out, and what it left behind
+func broke(name string, scrap *engine.ItemInfo) string {
+	if scrap == nil {
+		return fmt.Sprintf("Your %s breaks.", name)
+	}
+	return fmt.Sprintf("Your %s snaps, leaving you with %s.", name, describe(*scrap))
+}
+
+// stateChange narrates what an action set off, such as an enemy appearing or the level ending
+func (t templates) stateChange(state engine.EngineStateInfo) []string {
+	var sentences []string
+	for _, notification := range state.Notifications {
+		switch notification {
+		case engine.EngineStateChangeEnterCombat:
+			if state.FightingEnemy != nil {
+				enemy := state.FightingEnemy.Description
+				if enemy == "" {
+					enemy = "the " + state.FightingEnemy.Name
+				}
+				sentences = append(sentences, fmt.Sprintf(t.ambush, capitalize(enemy)))
+			}
+		case engine.EngineStateChangeLevelFailed:
+			if state.FailureNarrative != "" {
+				sentences = append(sentences, capitalize(state.FailureNarrative))
+			} else {
+				sentences = append(sentences, t.playerDied)
+			}
+		case engine.EngineStateChangeRespawned:
+			if state.CurrentRoom != nil {
+				sentences = append(sentences, fmt.Sprintf(t.respawned, state.CurrentRoom.Name))
+			}
+		case engine.EngineStateChangeSecretFound:
+			sentences = append(sentences, t.secretFound)
+		case engine.EngineStateChangeLevelComplete:
+			if state.OutroNarrative != "" {
+				sentences = append(sentences, capitalize(state.OutroNarrative))
+			} else {
+				sentences = append(sentences, "You have completed the level.")
+			}
+		}
+	}
+	return sentences
+}
+
+// describe returns an item's description, or its name if it has none, with how many there are
+// of a stack
+func describe(item engine.ItemInfo) string {
+	return itemDescription(item) + quantity(item)
+}
+
+// itemDescription returns an item's description, or its name if it has none
+func itemDescription(item engine.ItemInfo) string {
+	if item.Description != "" {
+		return item.Description
+	}
+	return "the " + item.Name
+}
+
+// quantity phrases how many there are of a stack of items, such as " (x3)", and is empty for
+// single items
+func quantity(item engine.ItemInfo) string {
+	if item.Quantity > 1 {
+		return fmt.Sprintf(" (x%d)", item.Quantity)
+	}
+	return ""
+}
+
+// chargesLeft tells how many uses an item with charges has left after using it, and nothing
+// for items without charges
+func chargesLeft(left int, total int) []string {
+	switch {
+	case total == 0:
+		return nil
+	case left == 0:
+		return []string{"That was the last of it."}
+	case left == 1:
+		return []string{"There is one use left in it."}
+	}
+	return []string{fmt.Sprintf("There are %d uses left in it.", left)}
+}
+
+// trap tells of a trap an item sprang: its narrative, or a warning if it has none, then what it
+// did to the player. Enemies it sets on the player are told of as ambushes.
+func trap(info *engine.TrapInfo) []string {
+	if info == nil {
+		return nil
+	}
+	sentences := []string{fmt.Sprintf("The %s was trapped!", info.ItemName)}
+	if info.Narrative != "" {
+		sentences = []string{capitalize(strings.TrimSuffix(info.Narrative, ".")) + "."}
+	}
+	hurt := false
+	for _, effect := range info.Effects {
+		switch world.EffectType(effect.Effect) {
+		case world.EffectDamage:
+			hurt = true
+		case world.EffectPoison:
+			sentences = append(sentences, "Poison seeps into your blood.")
+		}
+	}
+	if hurt && info.Health != world.HealthDead {
+		sentences = append(sentences, health(info.Health))
+	}
+	return sentences
+}
+
+// detail renders an item's detail, quoting text written on the item
+func detail(text string) string {
+	if written, ok := strings.CutPrefix(text, "<text>"); ok {
+		return fmt.Sprintf("It reads: %q", strings.TrimSuffix(written, "</text>"))
+	}
+	return capitalize(strings.TrimSuffix(text, ".")) + "."
+}
+
+// direction phrases a door's location, such as "to the left" or "behind you"
+func direction(location string) string {
+	switch location {
+	case "ahead", "up", "down":
+		return location
+	case "back":
+		return "behind you"
+	case "left", "right":
+		return "to the " + location
+	}
+	if world.Direction(location).IsValid() {
+		return "to the " + location
+	}
+	return "by the " + location
+}
+
+func health(state world.HealthState) string {
+	switch state {
+	case world.HealthFine:
+		return "You feel fine."
+	case world.HealthHurt:
+		return "You are hurt."
+	case world.HealthCrit:
+		return "You are badly hurt."
+	}
+	return ""
+}
+
+// breath warns the player as they run out of breath in an airless room
+func breath(info *engine.BreathInfo) []string {
+	switch {
+	case info == nil:
+		return nil
+	case info.Left == 0:
+		return []string{"You can't hold your breath any longer."}
+	case info.Left == 1:
+		return []string{"Your lungs are burning."}
+	case info.Left == info.Capacity-1:
+		return []string{"You hold your breath."}
+	}
+	return nil
+}
+
+// list joins phrases as "a, b and c"
+func list(phrases []string) string {
+	if len(phrases) == 1 {
+		return phrases[0]
+	}
+	return strings.Join(phrases[:len(phrases)-1], ", ") + " and " + phrases[len(phrases)-1]
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
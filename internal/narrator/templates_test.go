@@ -0,0 +1,459 @@
+package narrator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"adventure-engine/pkg/engine"
+	"adventure-engine/pkg/loader"
+	"adventure-engine/pkg/world"
+)
+
+func newDemoEngine(t *testing.T) *engine.Engine {
+	t.Helper()
+	level, err := loader.LoadGameFromFile("../testdata/demo.json")
+	if err != nil {
+		t.Fatalf("Failed to load demo game: %v", err)
+	}
+	e := engine.NewEngine(level)
+	e.Rng = &engine.FakeRng{Value: 0}
+	return e
+}
+
+// narrate narrates a result from templates
+func narrate(t *testing.T, theme string, result any) string {
+	t.Helper()
+	narration, err := Templates{}.Narrate(context.Background(), Request{Theme: theme, Result: result})
+	if err != nil {
+		t.Fatalf("Narrate failed: %v", err)
+	}
+	return narration
+}
+
+func TestStyleForTheme(t *testing.T) {
+	tests := map[string]Style{
+		"survival horror":        StyleHorror,
+		"Derelict Space Station": StyleSciFi,
+		"high fantasy dungeon":   StyleFantasy,
+		"cozy mystery":           StylePlain,
+		"":                       StylePlain,
+	}
+	for theme, want := range tests {
+		if got := StyleForTheme(theme); got != want {
+			t.Errorf("StyleForTheme(%q) = %s, want %s", theme, got, want)
+		}
+	}
+}
+
+func TestTemplates_Demo(t *testing.T) {
+	e := newDemoEngine(t)
+
+	observe, err := e.Observe(context.Background(), engine.ObserveFilter{})
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	narration := narrate(t, e.Level.Theme, observe)
+	for _, want := range []string{"You are in the waiting room", "an unopened energy drink", "the storage room door to the left", "the office door ahead"} {
+		if !strings.Contains(narration, want) {
+			t.Errorf("Expected the room narration to contain %q, got %q", want, narration)
+		}
+	}
+
+	if _, err := e.Uncover(context.Background(), "tattered grey hoodie"); err != nil {
+		t.Fatalf("Uncover failed: %v", err)
+	}
+	inspect, err := e.Inspect(context.Background(), "ominous note")
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if narration := narrate(t, e.Level.Theme, inspect); !strings.Contains(narration, `It reads: "got to get away from that thing..."`) {
+		t.Errorf("Expected the note's text to be quoted, got %q", narration)
+	}
+
+	traverse, err := e.Traverse(context.Background(), "left")
+	if err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if narration := narrate(t, e.Level.Theme, traverse); !strings.HasPrefix(narration, "You edge into the storage room.") {
+		t.Errorf("Expected horror narration entering the storage room, got %q", narration)
+	}
+	for _, step := range []func() error{
+		func() error { _, err := e.Uncover(context.Background(), "dark green tarp"); return err },
+		func() error { _, err := e.Unlock(context.Background(), "2468", "safe"); return err },
+		func() error { _, err := e.Search(context.Background(), "safe"); return err },
+	} {
+		if err := step(); err != nil {
+			t.Fatalf("Setup step failed: %v", err)
+		}
+	}
+
+	take, err := e.Take(context.Background(), "iron key")
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if narration := narrate(t, e.Level.Theme, take); narration != "You take the iron key. Something moves in the shadows. A wailing zombie lurches toward you!" {
+		t.Errorf("Unexpected narration for the ambush: %q", narration)
+	}
+
+	battle, err := e.Battle(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if narration := narrate(t, e.Level.Theme, battle); narration != "You strike the zombie and it reels back. The zombie collapses and does not get up." {
+		t.Errorf("Unexpected narration for the battle: %q", narration)
+	}
+}
+
+func TestTemplates_PlainStyle(t *testing.T) {
+	e := newDemoEngine(t)
+	result, err := e.Traverse(context.Background(), "left")
+	if err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if narration := narrate(t, "", result); !strings.HasPrefix(narration, "You enter the storage room.") {
+		t.Errorf("Expected plain narration, got %q", narration)
+	}
+	if narration := narrate(t, "", "not a result"); narration != "" {
+		t.Errorf("Expected no narration for an unknown result, got %q", narration)
+	}
+}
+
+func TestTemplates_Listen(t *testing.T) {
+	listen := &engine.ListenResult{}
+	listen.Result.DoorName = "oak door"
+	listen.Result.Movement = true
+	if narration := narrate(t, "survival horror", listen); narration != "You press your ear to the oak door. Something shuffles and scrapes behind the oak door." {
+		t.Errorf("Unexpected narration for listening: %q", narration)
+	}
+
+	listen.Result.EnemyName = "guard"
+	if narration := narrate(t, "", listen); !strings.HasSuffix(narration, "Through the bars you make out the guard.") {
+		t.Errorf("Expected the enemy to be made out, got %q", narration)
+	}
+}
+
+func TestTemplates_Jammed(t *testing.T) {
+	unlock := &engine.UnlockResult{}
+	unlock.Result.Target = "vault door"
+	unlock.Result.Jammed = true
+	if narration := narrate(t, "", unlock); narration != "The keypad on the vault door buzzes and goes dark. It won't take another code." {
+		t.Errorf("Unexpected narration for a jammed keypad: %q", narration)
+	}
+}
+
+func TestTemplates_BrokenTool(t *testing.T) {
+	use := &engine.UseResult{}
+	use.Result.UsedItemName = "crowbar"
+	use.Result.FixtureName = "crate"
+	use.Result.BrokenItem = "crowbar"
+	use.Result.Scrap = &engine.ItemInfo{Name: "bent bar", Description: "a bent bar"}
+	if narration := narrate(t, "", use); narration != "You use the crowbar on the crate. Your crowbar snaps, leaving you with a bent bar." {
+		t.Errorf("Unexpected narration for a broken tool: %q", narration)
+	}
+}
+
+func TestTemplates_Byproducts(t *testing.T) {
+	combine := &engine.CombineResult{}
+	combine.Result.CraftedItem = engine.ItemInfo{Name: "grapple", Description: "a grapple"}
+	combine.Result.Byproducts = []engine.ItemInfo{{Name: "empty bottle", Description: "an empty bottle"}}
+	if narration := narrate(t, "", combine); narration != "You combine them into a grapple. You are left with an empty bottle." {
+		t.Errorf("Unexpected narration for byproducts: %q", narration)
+	}
+}
+
+func TestTemplates_FixtureProgress(t *testing.T) {
+	use := &engine.UseResult{}
+	use.Result.UsedItemName = "fuse"
+	use.Result.FixtureName = "generator"
+	use.Result.InsertNarrative = "the fuse clicks into place"
+	use.Result.MissingItems = []string{"crank", "fuel can"}
+	use.Result.MissingCount = 2
+	if narration := narrate(t, "", use); narration != "You use the fuse on the generator. The fuse clicks into place. It still needs the crank and fuel can." {
+		t.Errorf("Unexpected narration for named missing items: %q", narration)
+	}
+
+	use.Result.MissingItems = nil
+	if narration := narrate(t, "", use); narration != "You use the fuse on the generator. The fuse clicks into place. It still needs 2 more things." {
+		t.Errorf("Unexpected narration for counted missing items: %q", narration)
+	}
+}
+
+func TestTemplates_Move(t *testing.T) {
+	move := &engine.MoveResult{}
+	move.Result.Name = "bookcase"
+	move.Result.Direction = "push"
+	move.Result.Narrative = "it grinds across the floor"
+	move.Result.RevealedDoor = "passage"
+	if narration := narrate(t, "", move); narration != "You push the bookcase. It grinds across the floor. It was hiding the passage." {
+		t.Errorf("Unexpected narration for pushing: %q", narration)
+	}
+	secretFound := engine.EngineStateChangeSecretFound
+	move.EngineStateInfo.EngineStateChangeNotification = &secretFound
+	move.EngineStateInfo.Notifications = []engine.EngineStateChangeNotification{secretFound}
+	if narration := narrate(t, "", move); !strings.HasSuffix(narration, "It was hiding the passage. You have discovered a secret passage.") {
+		t.Errorf("Unexpected narration for discovering a secret: %q", narration)
+	}
+	move.EngineStateInfo.EngineStateChangeNotification = nil
+	move.EngineStateInfo.Notifications = nil
+
+	move.Result.Direction = "left"
+	move.Result.Narrative = ""
+	move.Result.RevealedDoor = ""
+	move.Result.RevealedItem = &engine.ItemInfo{Name: "letter", Description: "an old letter"}
+	if narration := narrate(t, "", move); narration != "You move the bookcase left. Behind it you find an old letter." {
+		t.Errorf("Unexpected narration for moving: %q", narration)
+	}
+}
+
+func TestTemplates_Travel(t *testing.T) {
+	travel := &engine.TravelResult{}
+	travel.Result.FromNode = "lobby lift"
+	travel.Result.ToNode = "roof lift"
+	travel.Result.ChangedFloor = &engine.FloorInfo{Name: "roof"}
+	travel.Result.EnteredRoom.RoomName = "helipad"
+	travel.Result.EnteredRoom.RoomDescription = "a windswept helipad"
+	if narration := narrate(t, "", travel); narration != "You take the lobby lift to the roof lift. You arrive on the roof. You enter the helipad." {
+		t.Errorf("Unexpected narration for travelling: %q", narration)
+	}
+
+	observe := &engine.ObserveResult{}
+	observe.Result.RoomName = "lobby"
+	observe.Result.RoomDescription = "a lobby"
+	observe.Result.TravelNode = &engine.TravelNodeInfo{Name: "lobby lift"}
+	if narration := narrate(t, "", observe); !strings.HasSuffix(narration, "The lobby lift here can take you elsewhere. It has no power.") {
+		t.Errorf("Unexpected narration for an unpowered travel node: %q", narration)
+	}
+}
+
+func TestTemplates_Breath(t *testing.T) {
+	heal := &engine.HealResult{}
+	heal.Result.Breathed = true
+	heal.EngineStateInfo.Breath = &engine.BreathInfo{Left: 4, Capacity: 5}
+	if narration := narrate(t, "", heal); narration != "You take a deep breath. You hold your breath." {
+		t.Errorf("Unexpected narration for breathing from an air supply: %q", narration)
+	}
+
+	inspect := &engine.InspectResult{}
+	inspect.Result.ItemInspection = &engine.ItemInspection{ItemInfo: engine.ItemInfo{Name: "pebble"}}
+	inspect.EngineStateInfo.Breath = &engine.BreathInfo{Left: 0, Capacity: 5}
+	if narration := narrate(t, "", inspect); !strings.HasSuffix(narration, "You can't hold your breath any longer.") {
+		t.Errorf("Unexpected narration for running out of breath: %q", narration)
+	}
+}
+
+func TestTemplates_Respawn(t *testing.T) {
+	battle := &engine.BattleResult{}
+	battle.Result.EnemyName = "troll"
+	battle.Result.EnemyAlive = true
+	respawned := engine.EngineStateChangeRespawned
+	battle.EngineStateInfo.EngineStateChangeNotification = &respawned
+	battle.EngineStateInfo.Notifications = []engine.EngineStateChangeNotification{respawned}
+	battle.EngineStateInfo.CurrentRoom = &world.Room{BaseEntity: world.BaseEntity{Name: "hall"}}
+	if narration := narrate(t, "", battle); narration != "The troll hits you. You have died. You come to in the hall, without anything you carried." {
+		t.Errorf("Unexpected narration for respawning: %q", narration)
+	}
+}
+
+func TestTemplates_FailureNarrative(t *testing.T) {
+	battle := &engine.BattleResult{}
+	battle.Result.EnemyName = "troll"
+	battle.Result.EnemyAlive = true
+	battle.EngineStateInfo.Notifications = []engine.EngineStateChangeNotification{engine.EngineStateChangeLevelFailed}
+	if narration := narrate(t, "", battle); narration != "The troll hits you. You have died." {
+		t.Errorf("Unexpected narration for dying: %q", narration)
+	}
+	battle.EngineStateInfo.FailureNarrative = "the troll drags you into its cave."
+	if narration := narrate(t, "", battle); narration != "The troll hits you. The troll drags you into its cave." {
+		t.Errorf("Expected the level's failure narrative, got %q", narration)
+	}
+}
+
+func TestTemplates_Thrown(t *testing.T) {
+	battle := &engine.BattleResult{}
+	battle.Result.EnemyName = "ghoul"
+	battle.Result.WonRound = true
+	battle.Result.EnemyAlive = true
+	battle.Result.PlayerAlive = true
+	battle.Result.Thrown = true
+	battle.Result.AlsoHit = []string{"rat", "bat"}
+	battle.Result.AlsoKilled = []string{"rat"}
+	battle.Result.StartedFire = true
+	if narration := narrate(t, "", battle); narration != "You hit the ghoul. The blast also catches the rat and bat. You defeat the rat. The room catches fire." {
+		t.Errorf("Unexpected narration for a thrown weapon: %q", narration)
+	}
+}
+
+func TestTemplates_Armor(t *testing.T) {
+	battle := &engine.BattleResult{}
+	battle.Result.EnemyName = "rat"
+	battle.Result.EnemyAlive = true
+	battle.Result.PlayerAlive = true
+	battle.Result.Absorbed = "helmet"
+	battle.Result.BrokenArmor = "helmet"
+	if narration := narrate(t, "", battle); narration != "The rat lunges at you, but your helmet takes the blow. Your helmet breaks." {
+		t.Errorf("Unexpected narration for armor stopping a hit: %q", narration)
+	}
+}
+
+func TestTemplates_Resolve(t *testing.T) {
+	bribe := &engine.ResolveResult{}
+	bribe.Result.EnemyName = "troll"
+	bribe.Result.Approach = "bribe"
+	bribe.Result.Succeeded = true
+	bribe.Result.BribeItem = "gold coin"
+	bribe.Result.PlayerAlive = true
+	if narration := narrate(t, "", bribe); narration != "You hand the gold coin to the troll, and it lets you be." {
+		t.Errorf("Unexpected narration for a bribe: %q", narration)
+	}
+
+	sneak := &engine.ResolveResult{}
+	sneak.Result.EnemyName = "troll"
+	sneak.Result.Approach = "sneak"
+	sneak.Result.PlayerAlive = true
+	sneak.Result.Absorbed = "helmet"
+	if narration := narrate(t, "", sneak); narration != "The troll spots you. Your helmet takes the blow." {
+		t.Errorf("Unexpected narration for a failed sneak: %q", narration)
+	}
+}
+
+func TestTemplates_Surrender(t *testing.T) {
+	battle := &engine.BattleResult{}
+	battle.Result.EnemyName = "bandit"
+	battle.Result.WonRound = true
+	battle.Result.EnemyAlive = true
+	battle.Result.PlayerAlive = true
+	battle.Result.Surrendered = true
+	if narration := narrate(t, "", battle); !strings.HasSuffix(narration, "The bandit drops to its knees and surrenders.") {
+		t.Errorf("Unexpected narration for a surrender: %q", narration)
+	}
+
+	interrogate := &engine.InterrogateResult{}
+	interrogate.Result.EnemyName = "bandit"
+	interrogate.Result.Answer = "The vault code is 4512."
+	if narration := narrate(t, "", interrogate); narration != `The bandit talks: "The vault code is 4512."` {
+		t.Errorf("Unexpected narration for an interrogation: %q", narration)
+	}
+	interrogate.Result.Answer = ""
+	if narration := narrate(t, "", interrogate); narration != "The bandit has nothing to tell you." {
+		t.Errorf("Unexpected narration for a silent enemy: %q", narration)
+	}
+}
+
+func TestTemplates_FixtureEffects(t *testing.T) {
+	use := &engine.UseResult{}
+	use.Result.UsedItemName = "crank"
+	use.Result.FixtureName = "winch"
+	use.Result.IsComplete = true
+	use.Result.CompletionNarrative = "The winch groans."
+	use.Result.Effects = []engine.EffectInfo{{Effect: "unlock", Target: "vault door"}, {Effect: "reveal_door", Target: "trapdoor"}}
+	if narration := narrate(t, "", use); narration != "You use the crank on the winch. The winch groans. Somewhere, the vault door unlocks. A hidden trapdoor is revealed." {
+		t.Errorf("Unexpected narration for fixture effects: %q", narration)
+	}
+}
+
+func TestTemplates_SeveralNotifications(t *testing.T) {
+	use := &engine.UseResult{}
+	use.Result.UsedItemName = "crank"
+	use.Result.FixtureName = "winch"
+	use.Result.IsComplete = true
+	use.EngineStateInfo.Notifications = []engine.EngineStateChangeNotification{engine.EngineStateChangeSecretFound, engine.EngineStateChangeEnterCombat}
+	use.EngineStateInfo.FightingEnemy = &world.Enemy{BaseEntity: world.BaseEntity{Name: "ghoul"}}
+	if narration := narrate(t, "", use); narration != "You use the crank on the winch. You have discovered a secret passage. The ghoul attacks!" {
+		t.Errorf("Expected every notification to be narrated in order, got %q", narration)
+	}
+}
+
+func TestTemplates_CriticalHit(t *testing.T) {
+	battle := &engine.BattleResult{}
+	battle.Result.EnemyName = "troll"
+	battle.Result.EnemyAlive = true
+	battle.Result.WonRound = true
+	battle.Result.Critical = true
+	if narration := narrate(t, "", battle); !strings.HasSuffix(narration, "It is a critical hit.") {
+		t.Errorf("Unexpected narration for a critical hit: %q", narration)
+	}
+	battle.Result.Narrative = "the axe bites deep."
+	if narration := narrate(t, "", battle); !strings.HasSuffix(narration, "The axe bites deep.") {
+		t.Errorf("Expected the weapon's narrative, got %q", narration)
+	}
+}
+
+func TestTemplates_Status(t *testing.T) {
+	status := &engine.StatusResult{}
+	status.Result.Health = world.HealthHurt
+	status.Result.Weapon = "axe"
+	status.Result.Armor = []string{"helmet"}
+	if narration := narrate(t, "", status); narration != "You are hurt. You have the axe at hand. You are wearing the helmet." {
+		t.Errorf("Unexpected narration for the status: %q", narration)
+	}
+}
+
+func TestTemplates_Rest(t *testing.T) {
+	rest := &engine.WaitResult{}
+	rest.Result.Turns = 4
+	rest.Result.Rested = true
+	rest.Result.Health = world.HealthHurt
+	rest.Result.Healed = 1
+	rest.Result.Encounter = "wolf"
+	rest.EngineStateInfo.Notifications = []engine.EngineStateChangeNotification{engine.EngineStateChangeEnterCombat}
+	rest.EngineStateInfo.FightingEnemy = &world.Enemy{BaseEntity: world.BaseEntity{Name: "wolf", Description: "a grey wolf"}}
+	if narration := narrate(t, "", rest); !strings.HasPrefix(narration, "You rest for 4 turns. You are hurt. Your rest is cut short. A grey wolf") {
+		t.Errorf("Unexpected narration for the rest: %q", narration)
+	}
+}
+
+func TestTemplates_SubLocations(t *testing.T) {
+	desk := world.SubLocation{Name: "desk", Preposition: "on"}
+	observe := &engine.ObserveResult{}
+	observe.Result.RoomName = "study"
+	observe.Result.RoomDescription = "a quiet study"
+	observe.Result.VisibleItems = []engine.ItemInfo{
+		{Name: "rug", Description: "a rug"},
+		{Name: "pen", Description: "a pen", Location: "on the desk", SubLocation: desk},
+		{Name: "inkwell", Description: "an inkwell", Location: "at the back", SubLocation: desk},
+	}
+	want := "You are in the study: a quiet study. You see a rug. On the desk you see a pen and an inkwell (at the back)."
+	if narration := narrate(t, "", observe); narration != want {
+		t.Errorf("Unexpected narration for the room: %q", narration)
+	}
+}
+
+func TestTemplates_Charges(t *testing.T) {
+	heal := &engine.HealResult{}
+	heal.Result.Health = world.HealthFine
+	heal.Result.ChargesLeft = 2
+	heal.Result.MaxCharges = 3
+	if narration := narrate(t, "plain", heal); !strings.HasSuffix(narration, "There are 2 uses left in it.") {
+		t.Errorf("Unexpected narration for the heal: %q", narration)
+	}
+
+	inventory := &engine.InventoryResult{}
+	inventory.Result.Items = []engine.ItemInfo{{Name: "medkit", Charges: 1, MaxCharges: 3}}
+	if narration := narrate(t, "", inventory); narration != "You are carrying the medkit (1 of 3 uses left)." {
+		t.Errorf("Unexpected narration for the inventory: %q", narration)
+	}
+}
+
+func TestTemplates_Traps(t *testing.T) {
+	take := &engine.TakeResult{}
+	take.Result.ItemInfo = engine.ItemInfo{Name: "idol"}
+	take.Result.Trap = &engine.TrapInfo{
+		ItemName:  "idol",
+		Narrative: "darts fly from the walls",
+		Effects:   []engine.EffectInfo{{Effect: "damage", Target: "idol"}, {Effect: "poison", Target: "idol"}},
+		Health:    world.HealthHurt,
+	}
+	want := "You take the idol. Darts fly from the walls. Poison seeps into your blood. You are hurt."
+	if narration := narrate(t, "", take); narration != want {
+		t.Errorf("Unexpected narration for the trap: %q", narration)
+	}
+
+	heal := &engine.HealResult{}
+	heal.Result.Health = world.HealthFine
+	heal.Result.Cured = true
+	heal.Result.Trap = &engine.TrapInfo{ItemName: "salve", Health: world.HealthFine}
+	if narration := narrate(t, "plain", heal); !strings.HasSuffix(narration, "The poison leaves your body. The salve was trapped!") {
+		t.Errorf("Unexpected narration for the heal: %q", narration)
+	}
+}
@@ -0,0 +1,38 @@
+package parser
+
+import "strings"
+
+// ExpandAliases rewrites the start of a command using a level's verb aliases, which map words
+// in the level's vocabulary to the command they stand for: with "pry" mapped to
+// "use crowbar on", "pry the crate" becomes "use crowbar on the crate". The longest alias the
+// command starts with is used, and aliases take precedence over the built in verbs, so a
+// level can give a word such as "open" a meaning of its own. Aliases are matched like
+// commands, ignoring case and surrounding punctuation, and are expanded once: an expansion
+// that starts with another alias is not expanded again. Commands that start with no alias are
+// returned unchanged.
+func ExpandAliases(input string, aliases map[string]string) string {
+	fields := strings.Fields(input)
+	words := tokenize(input)
+	if len(words) != len(fields) {
+		// Fields made only of punctuation are dropped by tokenize, keep the words in step
+		// with the fields they came from
+		words = make([]string, len(fields))
+		for i, field := range fields {
+			words[i] = strings.Join(tokenize(field), " ")
+		}
+	}
+
+	matched, expansion := 0, ""
+	for alias, command := range aliases {
+		aliasWords := tokenize(alias)
+		n := len(aliasWords)
+		if n == 0 || n <= matched || n > len(words) || strings.Join(words[:n], " ") != strings.Join(aliasWords, " ") {
+			continue
+		}
+		matched, expansion = n, command
+	}
+	if matched == 0 {
+		return input
+	}
+	return strings.Join(append([]string{expansion}, fields[matched:]...), " ")
+}
@@ -0,0 +1,633 @@
+// Package parser turns free text commands such as "take the brass key from the desk"
+// into engine actions.
+//
+// Parsing is deliberately simple: the first one or two words select a verb, filler words
+// are dropped, prepositions split the rest into the names the action needs, and each name
+// is resolved against the names the player can currently refer to.
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Verb is the engine action a command maps to.
+type Verb string
+
+const (
+	VerbObserve   Verb = "observe"
+	VerbInspect   Verb = "inspect"
+	VerbUncover   Verb = "uncover"
+	VerbUnlock    Verb = "unlock"
+	VerbSearch    Verb = "search"
+	VerbTake      Verb = "take"
+	VerbInventory Verb = "inventory"
+	VerbStatus    Verb = "status"
+	VerbHeal      Verb = "heal"
+	VerbWait      Verb = "wait"
+	VerbRest      Verb = "rest"
+	VerbTraverse  Verb = "traverse"
+	VerbBattle    Verb = "battle"
+	VerbCombine   Verb = "combine"
+	VerbUse       Verb = "use"
+	VerbMinimap   Verb = "minimap"
+	VerbListen    Verb = "listen"
+	VerbPeek      Verb = "peek"
+	VerbLatch     Verb = "latch"
+	VerbMove      Verb = "move"
+	VerbTravel    Verb = "travel"
+
+	VerbInterrogate Verb = "interrogate"
+
+	VerbIntimidate Verb = "intimidate"
+	VerbBribe      Verb = "bribe"
+	VerbSneak      Verb = "sneak"
+)
+
+// Action is a parsed command.
+// Target is the item, door or direction acted on, and Item is the item acted with:
+// the key or code for unlock, the health item for heal, the weapon for battle, the bribe for
+// bribe, the first item for combine and the item used for use.
+// MoreItems holds the third and fourth items of a bigger combine, and Direction the way
+// furniture is moved, if the command says. Quantity is how many of a stack to take, or 0 for
+// all of it, and how many turns to wait or rest for, or 0 for one.
+type Action struct {
+	Verb      Verb
+	Target    string
+	Item      string
+	MoreItems [2]string
+	Direction string
+	Quantity  int
+}
+
+// CombineAction returns the combine action for two to four items.
+func CombineAction(items []string) Action {
+	action := Action{Verb: VerbCombine}
+	for i, item := range items {
+		switch i {
+		case 0:
+			action.Item = item
+		case 1:
+			action.Target = item
+		default:
+			action.MoreItems[i-2] = item
+		}
+	}
+	return action
+}
+
+// Items returns the items a combine action combines.
+func (a Action) Items() []string {
+	items := []string{a.Item, a.Target}
+	for _, item := range a.MoreItems {
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// String returns the action as a command, such as "unlock oak door with iron key".
+func (a Action) String() string {
+	switch a.Verb {
+	case VerbObserve:
+		return "look around"
+	case VerbInventory:
+		return "inventory"
+	case VerbStatus:
+		return "status"
+	case VerbMinimap:
+		return "map"
+	case VerbUnlock:
+		return "unlock " + a.Target + " with " + a.Item
+	case VerbHeal:
+		return "heal with " + a.Item
+	case VerbWait, VerbRest:
+		if a.Quantity > 1 {
+			return fmt.Sprintf("%s %d turns", a.Verb, a.Quantity)
+		}
+		return string(a.Verb)
+	case VerbTraverse:
+		return "go " + a.Target
+	case VerbListen:
+		return "listen at " + a.Target
+	case VerbPeek:
+		return "peek through " + a.Target
+	case VerbBattle:
+		if a.Item == "" {
+			return "attack"
+		}
+		return "attack with " + a.Item
+	case VerbIntimidate:
+		return "intimidate"
+	case VerbBribe:
+		return "bribe with " + a.Item
+	case VerbSneak:
+		return "sneak past"
+	case VerbCombine:
+		items := a.Items()
+		return "combine " + strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+	case VerbUse:
+		return "use " + a.Item + " on " + a.Target
+	case VerbMove:
+		switch a.Direction {
+		case "":
+			return "move " + a.Target
+		case "push", "pull":
+			return a.Direction + " " + a.Target
+		}
+		return "move " + a.Target + " " + a.Direction
+	case VerbTravel:
+		return "travel to " + a.Target
+	case VerbTake:
+		if a.Quantity > 0 {
+			return fmt.Sprintf("take %d %s", a.Quantity, a.Target)
+		}
+	}
+	return string(a.Verb) + " " + a.Target
+}
+
+// verbs maps the words that start a command to their verb.
+// Two word phrases are matched before single words.
+var verbs = map[string]Verb{
+	"look":        VerbObserve,
+	"look around": VerbObserve,
+	"l":           VerbObserve,
+	"observe":     VerbObserve,
+
+	"inspect": VerbInspect,
+	"examine": VerbInspect,
+	"x":       VerbInspect,
+	"look at": VerbInspect,
+	"check":   VerbInspect,
+	"read":    VerbInspect,
+
+	"uncover":     VerbUncover,
+	"lift":        VerbUncover,
+	"reveal":      VerbUncover,
+	"look under":  VerbUncover,
+	"look behind": VerbUncover,
+
+	"unlock": VerbUnlock,
+
+	"interrogate": VerbInterrogate,
+	"question":    VerbInterrogate,
+	"talk to":     VerbInterrogate,
+
+	"search":      VerbSearch,
+	"open":        VerbSearch,
+	"look in":     VerbSearch,
+	"look inside": VerbSearch,
+
+	"take":    VerbTake,
+	"get":     VerbTake,
+	"grab":    VerbTake,
+	"pick up": VerbTake,
+
+	"inventory": VerbInventory,
+	"inv":       VerbInventory,
+	"i":         VerbInventory,
+
+	"status":         VerbStatus,
+	"health":         VerbStatus,
+	"diagnose":       VerbStatus,
+	"examine self":   VerbStatus,
+	"examine myself": VerbStatus,
+	"how am":         VerbStatus, // as in "how am I doing?"
+
+	"heal":  VerbHeal,
+	"drink": VerbHeal,
+	"eat":   VerbHeal,
+
+	"breathe":      VerbHeal,
+	"breathe from": VerbHeal,
+
+	"wait":  VerbWait,
+	"z":     VerbWait,
+	"rest":  VerbRest,
+	"sleep": VerbRest,
+
+	"go":    VerbTraverse,
+	"walk":  VerbTraverse,
+	"run":   VerbTraverse,
+	"enter": VerbTraverse,
+	"climb": VerbTraverse,
+
+	"battle": VerbBattle,
+	"attack": VerbBattle,
+	"fight":  VerbBattle,
+	"shoot":  VerbBattle,
+	"hit":    VerbBattle,
+	"strike": VerbBattle,
+	"throw":  VerbBattle,
+	"toss":   VerbBattle,
+	"lob":    VerbBattle,
+
+	"intimidate": VerbIntimidate,
+	"threaten":   VerbIntimidate,
+	"scare":      VerbIntimidate,
+	"bribe":      VerbBribe,
+	"pay":        VerbBribe,
+	"offer":      VerbBribe,
+	"sneak":      VerbSneak,
+	"sneak past": VerbSneak,
+	"slip past":  VerbSneak,
+	"creep past": VerbSneak,
+
+	"combine": VerbCombine,
+	"attach":  VerbCombine,
+
+	"use":    VerbUse,
+	"put":    VerbUse,
+	"insert": VerbUse,
+	"place":  VerbUse,
+
+	"map":     VerbMinimap,
+	"minimap": VerbMinimap,
+
+	"listen": VerbListen,
+
+	"peek":         VerbPeek,
+	"peer":         VerbPeek,
+	"look through": VerbPeek,
+
+	"latch": VerbLatch,
+	"bolt":  VerbLatch,
+	"bar":   VerbLatch,
+
+	"move":  VerbMove,
+	"slide": VerbMove,
+	"push":  VerbMove,
+	"shove": VerbMove,
+	"pull":  VerbMove,
+	"drag":  VerbMove,
+
+	"travel": VerbTravel,
+	"ride":   VerbTravel,
+}
+
+// moveVerbs maps the words for moving furniture that imply a direction to that direction.
+var moveVerbs = map[string]string{
+	"push": "push", "shove": "push",
+	"pull": "pull", "drag": "pull",
+}
+
+// moveDirections maps the words that can end a move command to the direction furniture is
+// moved in. Words that give no particular direction map to "".
+var moveDirections = map[string]string{
+	"left": "left", "right": "right",
+	"forward": "forward", "back": "back", "backward": "back", "backwards": "back",
+	"north": "north", "south": "south", "east": "east", "west": "west",
+	"aside": "", "away": "", "over": "",
+}
+
+// directions maps direction words and their abbreviations to the direction names doors use.
+var directions = map[string]string{
+	"north": "north", "n": "north",
+	"south": "south", "s": "south",
+	"east": "east", "e": "east",
+	"west": "west", "w": "west",
+	"up": "up", "u": "up",
+	"down": "down", "d": "down",
+}
+
+// fillers are words dropped from names.
+var fillers = map[string]bool{
+	"the": true, "a": true, "an": true, "my": true, "some": true, "this": true, "that": true,
+}
+
+// Prepositions that separate the names in a command.
+var (
+	withWords = []string{"with", "using"}
+	onWords   = []string{"on", "onto", "in", "into", "to", "with"}
+	fromWords = []string{"from", "off", "out"}
+	toWords   = []string{"to", "through", "into", "towards"}
+	joinWords = []string{"and", "with", "to"}
+	doorWords = []string{"at", "to", "through", "into", "behind"}
+	atWords   = []string{"at"}
+)
+
+// throwVerbs are the battle verbs that name the weapon before the enemy, as in "throw the
+// grenade at the zombie".
+var throwVerbs = []string{"throw", "toss", "lob"}
+
+// Parse parses a command. Names in the command are resolved against names, the names of
+// everything the player can currently refer to; unresolved names are passed through as
+// written so the engine can report them, and codes can be entered.
+func Parse(input string, names []string) (*Action, error) {
+	words := tokenize(input)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("say something to do")
+	}
+
+	// A bare direction is a move
+	if direction, ok := directions[words[0]]; ok && len(words) == 1 {
+		return &Action{Verb: VerbTraverse, Target: direction}, nil
+	}
+
+	verb, rest, ok := matchVerb(words)
+	if !ok {
+		return nil, fmt.Errorf("I don't know how to %s", words[0])
+	}
+	action := &Action{Verb: verb}
+	switch verb {
+	case VerbObserve, VerbInventory, VerbStatus, VerbMinimap:
+		return action, nil
+
+	case VerbInspect, VerbUncover, VerbSearch, VerbTake, VerbInterrogate:
+		if verb == VerbSearch && words[0] == "open" && containsAny(rest, withWords) {
+			// "open the door with the key" is an unlock
+			action.Verb = VerbUnlock
+			return parseTwoNames(action, rest, withWords, names, false)
+		}
+		if verb == VerbTake {
+			rest, _ = splitAt(rest, fromWords)
+			// A number takes some of a stack, as in "take 2 of the bandages"
+			if len(rest) > 1 {
+				if quantity, err := strconv.Atoi(rest[0]); err == nil && quantity > 0 {
+					action.Quantity, rest = quantity, rest[1:]
+					if rest[0] == "of" {
+						rest = rest[1:]
+					}
+				}
+			}
+		}
+		return parseOneName(action, rest, names, &action.Target)
+
+	case VerbWait, VerbRest:
+		return parseTurns(action, rest)
+
+	case VerbHeal:
+		if len(rest) > 0 && containsAny(rest[:1], withWords) {
+			rest = rest[1:]
+		}
+		return parseOneName(action, rest, names, &action.Item)
+
+	case VerbTraverse, VerbListen, VerbPeek, VerbLatch:
+		prepositions := toWords
+		if verb != VerbTraverse {
+			prepositions = doorWords
+		}
+		if len(rest) > 0 && containsAny(rest[:1], prepositions) {
+			rest = rest[1:]
+		}
+		if len(rest) == 1 {
+			if direction, ok := directions[rest[0]]; ok {
+				action.Target = direction
+				return action, nil
+			}
+		}
+		return parseOneName(action, rest, names, &action.Target)
+
+	case VerbTravel:
+		// Only the destination matters, as in "ride the lift to the lobby"
+		if _, destination := splitAt(rest, toWords); destination != nil {
+			rest = destination
+		}
+		return parseOneName(action, rest, names, &action.Target)
+
+	case VerbBattle:
+		// The enemy being fought is implied, only the weapon matters
+		if slices.Contains(throwVerbs, words[0]) {
+			rest, _ = splitAt(rest, atWords)
+		} else if _, weapon := splitAt(rest, withWords); weapon != nil {
+			rest = weapon
+		}
+		return parseOneName(action, rest, names, &action.Item)
+
+	case VerbIntimidate, VerbSneak:
+		// The enemy being got past is implied
+		return action, nil
+
+	case VerbBribe:
+		// Only the bribe matters, as in "bribe the troll with the gold" or "offer the gold to the troll"
+		if _, bribe := splitAt(rest, withWords); bribe != nil {
+			rest = bribe
+		} else {
+			rest, _ = splitAt(rest, []string{"to"})
+		}
+		return parseOneName(action, rest, names, &action.Item)
+
+	case VerbUnlock:
+		return parseTwoNames(action, rest, withWords, names, false)
+
+	case VerbCombine:
+		// Commas separate items like "and" does, as in "combine rope, hook and pole"
+		_, rest, _ = matchVerb(tokenize(strings.ReplaceAll(input, ",", " and ")))
+		return parseCombine(action, rest, names)
+
+	case VerbUse:
+		return parseTwoNames(action, rest, onWords, names, true)
+
+	case VerbMove:
+		if direction, ok := directions[strings.Join(rest, " ")]; ok && moveVerbs[words[0]] == "" {
+			// "move north" is a step, not furniture
+			return &Action{Verb: VerbTraverse, Target: direction}, nil
+		}
+		action.Direction = moveVerbs[words[0]]
+		if last := len(rest) - 1; last > 0 && !isKnown(rest, names) {
+			if direction, ok := moveDirections[rest[last]]; ok {
+				rest = rest[:last]
+				if action.Direction == "" {
+					action.Direction = direction
+				}
+			}
+		}
+		return parseOneName(action, rest, names, &action.Target)
+	}
+	return nil, fmt.Errorf("I don't know how to %s", words[0])
+}
+
+// parseOneName parses the whole of rest as a single name.
+func parseOneName(action *Action, rest []string, names []string, name *string) (*Action, error) {
+	if len(dropFillers(rest)) == 0 {
+		return nil, fmt.Errorf("%s what?", action.Verb)
+	}
+	resolved, err := Resolve(joinName(rest), names)
+	if err != nil {
+		return nil, err
+	}
+	*name = resolved
+	return action, nil
+}
+
+// parseTurns parses how long to wait or rest for, as in "wait" or "rest for 3 turns".
+func parseTurns(action *Action, rest []string) (*Action, error) {
+	if len(rest) > 0 && rest[0] == "for" {
+		rest = rest[1:]
+	}
+	if len(rest) > 1 && (rest[len(rest)-1] == "turns" || rest[len(rest)-1] == "turn") {
+		rest = rest[:len(rest)-1]
+	}
+	switch len(rest) {
+	case 0:
+		return action, nil
+	case 1:
+		if turns, err := strconv.Atoi(rest[0]); err == nil && turns > 0 {
+			action.Quantity = turns
+			return action, nil
+		}
+	}
+	return nil, fmt.Errorf("%s for how many turns?", action.Verb)
+}
+
+// parseTwoNames parses "<first> <separator> <second>". For unlock the first name is the
+// target and the second the key or code, otherwise the first name is the item.
+// Names may contain separator words, so the split where both names are known is preferred.
+func parseTwoNames(action *Action, rest []string, separators []string, names []string, itemFirst bool) (*Action, error) {
+	var first, second []string
+	split := false
+	for i, word := range rest {
+		if !slices.Contains(separators, word) {
+			continue
+		}
+		if !split {
+			first, second, split = rest[:i], rest[i+1:], true
+		}
+		if isKnown(rest[:i], names) && isKnown(rest[i+1:], names) {
+			first, second = rest[:i], rest[i+1:]
+			break
+		}
+	}
+	if len(dropFillers(first)) == 0 || len(dropFillers(second)) == 0 {
+		return nil, fmt.Errorf("%s what %s what?", action.Verb, separators[0])
+	}
+
+	firstName, err := Resolve(joinName(first), names)
+	if err != nil {
+		return nil, err
+	}
+	secondName, err := Resolve(joinName(second), names)
+	if err != nil {
+		return nil, err
+	}
+	if itemFirst {
+		action.Item, action.Target = firstName, secondName
+	} else {
+		action.Target, action.Item = firstName, secondName
+	}
+	return action, nil
+}
+
+// parseCombine parses "<item> and <item>", with up to four items. Names may contain
+// separator words, so the split into the fewest names that are all known is preferred,
+// and otherwise the command is split at every separator.
+func parseCombine(action *Action, rest []string, names []string) (*Action, error) {
+	var parts [][]string
+	for n := 2; n <= len(action.MoreItems)+2 && parts == nil; n++ {
+		parts = splitKnown(rest, joinWords, names, n)
+	}
+	if parts == nil {
+		parts = splitAll(rest, joinWords)
+	}
+	if len(parts) < 2 || slices.ContainsFunc(parts, func(part []string) bool { return len(dropFillers(part)) == 0 }) {
+		return nil, fmt.Errorf("%s what and what?", action.Verb)
+	}
+	if len(parts) > len(action.MoreItems)+2 {
+		return nil, fmt.Errorf("you can only combine up to %d things at once", len(action.MoreItems)+2)
+	}
+
+	items := make([]string, len(parts))
+	for i, part := range parts {
+		name, err := Resolve(joinName(part), names)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = name
+	}
+	*action = CombineAction(items)
+	return action, nil
+}
+
+// splitKnown splits words at separators into n names that are all known.
+// Returns nil if there is no such split.
+func splitKnown(words []string, separators []string, names []string, n int) [][]string {
+	if n == 1 {
+		if isKnown(words, names) {
+			return [][]string{words}
+		}
+		return nil
+	}
+	for i, word := range words {
+		if !slices.Contains(separators, word) || !isKnown(words[:i], names) {
+			continue
+		}
+		if tail := splitKnown(words[i+1:], separators, names, n-1); tail != nil {
+			return append([][]string{words[:i]}, tail...)
+		}
+	}
+	return nil
+}
+
+// splitAll splits words at every separator.
+func splitAll(words []string, separators []string) [][]string {
+	var parts [][]string
+	for {
+		part, tail := splitAt(words, separators)
+		parts = append(parts, part)
+		if tail == nil {
+			return parts
+		}
+		words = tail
+	}
+}
+
+// matchVerb matches the verb at the start of a command, returning the remaining words.
+func matchVerb(words []string) (Verb, []string, bool) {
+	if len(words) >= 2 {
+		if verb, ok := verbs[words[0]+" "+words[1]]; ok {
+			return verb, words[2:], true
+		}
+	}
+	verb, ok := verbs[words[0]]
+	return verb, words[1:], ok
+}
+
+// splitAt splits words at the first separator, which is dropped.
+// The second part is nil if there is no separator.
+func splitAt(words []string, separators []string) ([]string, []string) {
+	for i, word := range words {
+		if slices.Contains(separators, word) {
+			return words[:i], words[i+1:]
+		}
+	}
+	return words, nil
+}
+
+func containsAny(words []string, candidates []string) bool {
+	for _, word := range words {
+		if slices.Contains(candidates, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenize lower-cases a command and splits it into words, trimming surrounding punctuation.
+func tokenize(input string) []string {
+	var words []string
+	for _, field := range strings.Fields(strings.ToLower(input)) {
+		word := strings.Trim(field, `.,!?;:"'`)
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// joinName joins the words of a name, dropping fillers.
+func joinName(words []string) string {
+	return strings.Join(dropFillers(words), " ")
+}
+
+func dropFillers(words []string) []string {
+	var result []string
+	for _, word := range words {
+		if !fillers[word] {
+			result = append(result, word)
+		}
+	}
+	return result
+}
@@ -0,0 +1,209 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+var testNames = []string{
+	"brass key", "desk", "iron key", "oak door", "north", "to the right",
+	"first aid kit", "pistol", "jar with lid", "shelf", "rotting zombie", "service lift",
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Action
+	}{
+		{"look", Action{Verb: VerbObserve}},
+		{"Look around.", Action{Verb: VerbObserve}},
+		{"i", Action{Verb: VerbInventory}},
+		{"map", Action{Verb: VerbMinimap}},
+		{"How am I doing?", Action{Verb: VerbStatus}},
+		{"examine myself", Action{Verb: VerbStatus}},
+		{"z", Action{Verb: VerbWait}},
+		{"wait 3", Action{Verb: VerbWait, Quantity: 3}},
+		{"rest for 5 turns", Action{Verb: VerbRest, Quantity: 5}},
+		{"take the brass key from the desk", Action{Verb: VerbTake, Target: "brass key"}},
+		{"pick up brass", Action{Verb: VerbTake, Target: "brass key"}},
+		{"take 2 first aid kits", Action{Verb: VerbTake, Target: "first aid kit", Quantity: 2}},
+		{"take 3 of the brass keys from the desk", Action{Verb: VerbTake, Target: "brass key", Quantity: 3}},
+		{"examine the shelv", Action{Verb: VerbInspect, Target: "shelf"}},
+		{"look at the oak door", Action{Verb: VerbInspect, Target: "oak door"}},
+		{"open the desk", Action{Verb: VerbSearch, Target: "desk"}},
+		{"open the oak door with the iron key", Action{Verb: VerbUnlock, Target: "oak door", Item: "iron key"}},
+		{"unlock oak door with 2468", Action{Verb: VerbUnlock, Target: "oak door", Item: "2468"}},
+		{"drink the first aid kit", Action{Verb: VerbHeal, Item: "first aid kit"}},
+		{"breathe from the air tank", Action{Verb: VerbHeal, Item: "air tank"}},
+		{"heal with kit", Action{Verb: VerbHeal, Item: "first aid kit"}},
+		{"n", Action{Verb: VerbTraverse, Target: "north"}},
+		{"go west", Action{Verb: VerbTraverse, Target: "west"}},
+		{"go through the oak door", Action{Verb: VerbTraverse, Target: "oak door"}},
+		{"go right", Action{Verb: VerbTraverse, Target: "to the right"}},
+		{"listen at the oak door", Action{Verb: VerbListen, Target: "oak door"}},
+		{"listen north", Action{Verb: VerbListen, Target: "north"}},
+		{"look through the oak door", Action{Verb: VerbPeek, Target: "oak door"}},
+		{"peek w", Action{Verb: VerbPeek, Target: "west"}},
+		{"bolt the oak door", Action{Verb: VerbLatch, Target: "oak door"}},
+		{"attack the zombie with the pistol", Action{Verb: VerbBattle, Item: "pistol"}},
+		{"shoot pistol", Action{Verb: VerbBattle, Item: "pistol"}},
+		{"throw the pistol at the zombie", Action{Verb: VerbBattle, Item: "pistol"}},
+		{"threaten the zombie", Action{Verb: VerbIntimidate}},
+		{"bribe the zombie with the brass key", Action{Verb: VerbBribe, Item: "brass key"}},
+		{"offer the brass key to the zombie", Action{Verb: VerbBribe, Item: "brass key"}},
+		{"sneak past the zombie", Action{Verb: VerbSneak}},
+		{"question the zombie", Action{Verb: VerbInterrogate, Target: "rotting zombie"}},
+		{"talk to rotting zombie", Action{Verb: VerbInterrogate, Target: "rotting zombie"}},
+		{"search the zombie", Action{Verb: VerbSearch, Target: "rotting zombie"}},
+		{"combine brass key and iron key", Action{Verb: VerbCombine, Item: "brass key", Target: "iron key"}},
+		{"combine brass key, iron key and pistol", Action{Verb: VerbCombine, Item: "brass key", Target: "iron key", MoreItems: [2]string{"pistol"}}},
+		{"combine pistol and jar with lid and brass", Action{Verb: VerbCombine, Item: "pistol", Target: "jar with lid", MoreItems: [2]string{"brass key"}}},
+		{"use the jar with lid on the shelf", Action{Verb: VerbUse, Item: "jar with lid", Target: "shelf"}},
+		{"put the brass key in the desk", Action{Verb: VerbUse, Item: "brass key", Target: "desk"}},
+		{"push the shelf", Action{Verb: VerbMove, Target: "shelf", Direction: "push"}},
+		{"drag the desk aside", Action{Verb: VerbMove, Target: "desk", Direction: "pull"}},
+		{"slide the shelf left", Action{Verb: VerbMove, Target: "shelf", Direction: "left"}},
+		{"move the desk", Action{Verb: VerbMove, Target: "desk"}},
+		{"move north", Action{Verb: VerbTraverse, Target: "north"}},
+		{"travel to the service lift", Action{Verb: VerbTravel, Target: "service lift"}},
+		{"ride the elevator to the service lift", Action{Verb: VerbTravel, Target: "service lift"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			action, err := Parse(tt.input, testNames)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if *action != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, *action)
+			}
+		})
+	}
+}
+
+func TestAction_String(t *testing.T) {
+	actions := []Action{
+		{Verb: VerbObserve},
+		{Verb: VerbInventory},
+		{Verb: VerbStatus},
+		{Verb: VerbTake, Target: "brass key"},
+		{Verb: VerbTake, Target: "first aid kit", Quantity: 2},
+		{Verb: VerbSearch, Target: "desk"},
+		{Verb: VerbUnlock, Target: "oak door", Item: "iron key"},
+		{Verb: VerbHeal, Item: "first aid kit"},
+		{Verb: VerbWait},
+		{Verb: VerbRest, Quantity: 3},
+		{Verb: VerbTraverse, Target: "north"},
+		{Verb: VerbListen, Target: "oak door"},
+		{Verb: VerbPeek, Target: "north"},
+		{Verb: VerbLatch, Target: "oak door"},
+		{Verb: VerbBattle, Item: "pistol"},
+		{Verb: VerbCombine, Item: "brass key", Target: "iron key"},
+		{Verb: VerbCombine, Item: "pistol", Target: "jar with lid", MoreItems: [2]string{"brass key", "iron key"}},
+		{Verb: VerbUse, Item: "jar with lid", Target: "shelf"},
+		{Verb: VerbMove, Target: "shelf", Direction: "pull"},
+		{Verb: VerbMove, Target: "desk", Direction: "right"},
+		{Verb: VerbTravel, Target: "service lift"},
+	}
+	for _, action := range actions {
+		parsed, err := Parse(action.String(), testNames)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", action.String(), err)
+			continue
+		}
+		if *parsed != action {
+			t.Errorf("Expected %q to parse back to %+v, got %+v", action.String(), action, *parsed)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+	}{
+		{"", "say something"},
+		{"dance wildly", "I don't know how to dance"},
+		{"take", "take what?"},
+		{"sleep until dawn", "rest for how many turns?"},
+		{"take the", "take what?"},
+		{"use brass key", "use what on what?"},
+		{"combine pistol", "combine what and what?"},
+		{"combine pistol, desk, shelf, brass key and iron key", "up to 4 things"},
+		{"take key", "which do you mean, the brass key or the iron key?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input, testNames)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Expected error containing %q, got: %v", tt.message, err)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		phrase   string
+		names    []string
+		expected string
+	}{
+		{"Brass Key", []string{"brass key"}, "brass key"},
+		{"key", []string{"key", "brass key"}, "key"},
+		{"brss key", []string{"brass key", "iron key"}, "brass key"},
+		{"lamp", []string{"lamp", "lamps"}, "lamp"},
+		{"lantern", []string{"brass key"}, "lantern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phrase, func(t *testing.T) {
+			resolved, err := Resolve(tt.phrase, tt.names)
+			if err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+			if resolved != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, resolved)
+			}
+		})
+	}
+}
+
+func TestExpandAliases(t *testing.T) {
+	aliases := map[string]string{
+		"pry":      "use crowbar on",
+		"pry open": "use pistol on",
+		"smash":    "attack with",
+		"open":     "inspect",
+	}
+	tests := []struct {
+		input    string
+		expected Action
+	}{
+		{"pry the desk", Action{Verb: VerbUse, Item: "crowbar", Target: "desk"}},
+		{"Pry open the desk!", Action{Verb: VerbUse, Item: "pistol", Target: "desk"}},
+		{"smash pistol", Action{Verb: VerbBattle, Item: "pistol"}},
+		{"open the desk", Action{Verb: VerbInspect, Target: "desk"}},
+		{"take the prybar", Action{Verb: VerbTake, Target: "prybar"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			action, err := Parse(ExpandAliases(tt.input, aliases), testNames)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if *action != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, *action)
+			}
+		})
+	}
+
+	if expanded := ExpandAliases("pry", nil); expanded != "pry" {
+		t.Errorf("Expected commands to be left alone without aliases, got %q", expanded)
+	}
+}
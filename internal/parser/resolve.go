@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Resolve resolves a name as the player wrote it to one of names.
+//
+// A name matches if it is the same ignoring case, or if every word the player wrote is one
+// of its words, allowing a typo in longer words: "key" and "brss key" both resolve to
+// "brass key". Matches without typos are preferred. A name matching nothing is returned as
+// written, and a name matching more than one name is an error.
+func Resolve(phrase string, names []string) (string, error) {
+	matches, exact := match(phrase, names)
+	switch len(matches) {
+	case 0:
+		return phrase, nil
+	case 1:
+		return matches[0], nil
+	}
+	if len(exact) == 1 {
+		return exact[0], nil
+	}
+	if len(exact) > 1 {
+		matches = exact
+	}
+	return "", fmt.Errorf("which do you mean, the %s?", strings.Join(matches, " or the "))
+}
+
+// isKnown reports whether a phrase resolves to exactly one of names.
+func isKnown(words []string, names []string) bool {
+	matches, exact := match(joinName(words), names)
+	return len(matches) == 1 || len(exact) == 1
+}
+
+// match returns the names a phrase matches, and those it matches without typos.
+func match(phrase string, names []string) (matches []string, exact []string) {
+	phrase = strings.ToLower(strings.TrimSpace(phrase))
+	if phrase == "" {
+		return nil, nil
+	}
+	for _, name := range names {
+		if strings.ToLower(name) == phrase {
+			return []string{name}, []string{name}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		typos, ok := matchWords(strings.Fields(phrase), strings.Fields(strings.ToLower(name)))
+		if !ok {
+			continue
+		}
+		matches = append(matches, name)
+		if typos == 0 {
+			exact = append(exact, name)
+		}
+	}
+	return matches, exact
+}
+
+// matchWords reports whether every word of a phrase is one of a name's words, and how many
+// of them needed a typo allowance.
+func matchWords(phraseWords []string, nameWords []string) (int, bool) {
+	typos := 0
+	for _, word := range phraseWords {
+		found, typo := false, false
+		for _, nameWord := range nameWords {
+			if word == nameWord {
+				found, typo = true, false
+				break
+			}
+			if len(word) >= 4 && editDistance(word, nameWord) <= 1 {
+				found, typo = true, true
+			}
+		}
+		if !found {
+			return 0, false
+		}
+		if typo {
+			typos++
+		}
+	}
+	return typos, true
+}
+
+// editDistance returns the Levenshtein distance between two words.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
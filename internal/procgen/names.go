@@ -0,0 +1,15 @@
+package procgen
+
+// Word lists used to name generated rooms, items and enemies.
+var (
+	roomAdjectives      = []string{"dusty", "flooded", "cold", "narrow", "abandoned", "dim", "musty", "ruined", "silent", "cramped", "echoing", "scorched"}
+	roomNouns           = []string{"hallway", "cellar", "office", "storeroom", "kitchen", "library", "laboratory", "chapel", "workshop", "ward", "gallery", "boiler room"}
+	keyAdjectives       = []string{"brass", "iron", "copper", "silver", "rusty", "bone", "glass", "bent", "tarnished", "heavy"}
+	containerAdjectives = []string{"old", "battered", "wooden", "metal", "dented", "mouldy", "overturned"}
+	containerNouns      = []string{"chest", "crate", "drawer", "cabinet", "locker", "toolbox"}
+	concealerNouns      = []string{"rug", "tarp", "pile of rubble", "mattress", "sack"}
+	enemyAdjectives     = []string{"rotting", "feral", "hollow-eyed", "hulking", "twitching", "pale"}
+	enemyNouns          = []string{"zombie", "ghoul", "hound", "cultist", "crawler"}
+	weaponAdjectives    = []string{"rusty", "heavy", "chipped", "sturdy"}
+	weaponNouns         = []string{"crowbar", "pipe wrench", "machete", "fire axe"}
+)
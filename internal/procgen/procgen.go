@@ -0,0 +1,382 @@
+// Package procgen generates playable levels from a seed and difficulty parameters.
+//
+// Levels are generated as loader documents and loaded through the loader,
+// so every generated level passes the same structural, reachability and
+// solvability validation as hand-written levels.
+package procgen
+
+import (
+	"adventure-engine/pkg/loader"
+	"adventure-engine/pkg/world"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+)
+
+// Difficulty selects preset generation parameters.
+type Difficulty string
+
+const (
+	DifficultyEasy   Difficulty = "easy"
+	DifficultyNormal Difficulty = "normal"
+	DifficultyHard   Difficulty = "hard"
+)
+
+const (
+	MaxRooms   = 40
+	MaxEnemies = 20
+	MaxEnemyHP = 10
+)
+
+// Params controls the shape of a generated level.
+type Params struct {
+	Seed    uint64
+	Rooms   int // total number of rooms, at least 2
+	Locks   int // number of locked doors, each with its own key, preferably on the way to the exit
+	Enemies int // number of enemies, each ambushing the player in a different room
+	EnemyHP int // hit points of each enemy
+	Weapons bool
+}
+
+// DefaultParams returns the preset parameters for a difficulty.
+func DefaultParams(difficulty Difficulty, seed uint64) (Params, error) {
+	switch difficulty {
+	case DifficultyEasy:
+		return Params{Seed: seed, Rooms: 5, Locks: 1, Enemies: 1, EnemyHP: 1, Weapons: true}, nil
+	case DifficultyNormal, "":
+		return Params{Seed: seed, Rooms: 8, Locks: 2, Enemies: 2, EnemyHP: 2, Weapons: true}, nil
+	case DifficultyHard:
+		return Params{Seed: seed, Rooms: 12, Locks: 4, Enemies: 4, EnemyHP: 3, Weapons: false}, nil
+	}
+	return Params{}, fmt.Errorf("unknown difficulty %s", difficulty)
+}
+
+// Validate checks that the parameters describe a level that can be generated.
+func (p Params) Validate() error {
+	if p.Rooms < 2 || p.Rooms > MaxRooms {
+		return fmt.Errorf("rooms must be between 2 and %d", MaxRooms)
+	}
+	if p.Locks < 0 || p.Locks > p.Rooms-1 {
+		return fmt.Errorf("locks must be between 0 and the number of rooms minus one")
+	}
+	if p.Enemies < 0 || p.Enemies > MaxEnemies || p.Enemies > p.Rooms-1 {
+		return fmt.Errorf("enemies must be between 0 and %d, and fewer than the number of rooms", MaxEnemies)
+	}
+	if p.Enemies > 0 && (p.EnemyHP < 1 || p.EnemyHP > MaxEnemyHP) {
+		return fmt.Errorf("enemy hp must be between 1 and %d", MaxEnemyHP)
+	}
+	return nil
+}
+
+// Generate generates a level and loads it.
+func Generate(params Params) (*world.Level, error) {
+	data, err := GenerateDocument(params)
+	if err != nil {
+		return nil, err
+	}
+	return loader.LoadGameWithSeed(data, params.Seed)
+}
+
+// GenerateDocument generates a level in loader format.
+// The document is validated by the loader before it is returned.
+func GenerateDocument(params Params) (json.RawMessage, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		params:    params,
+		rng:       rand.New(rand.NewPCG(params.Seed, params.Seed)),
+		usedNames: make(map[string]bool),
+		occupied:  make(map[world.Position]bool),
+	}
+	data, err := json.Marshal(g.generate())
+	if err != nil {
+		return nil, err
+	}
+
+	// Reuse the loader's validation so that only playable levels are returned
+	if _, _, err := loader.LoadGameWithDiagnostics(data, params.Seed); err != nil {
+		return nil, fmt.Errorf("generated level is invalid: %w", err)
+	}
+	return data, nil
+}
+
+// directions used for connections, all on a single floor
+var directions = []world.Direction{world.DirectionNorth, world.DirectionEast, world.DirectionSouth, world.DirectionWest}
+
+type generator struct {
+	params    Params
+	rng       *rand.Rand
+	usedNames map[string]bool
+	rooms     []*loader.RoomData
+	parent    []int // index of the room each room was reached from, -1 for the start
+	depth     []int
+	positions []world.Position
+	occupied  map[world.Position]bool
+	doorTo    []int // index into doors of the door leading into each room, -1 for the start
+	doors     []loader.DoorData
+	enemies   []loader.EnemyData
+}
+
+func (g *generator) generate() *loader.GameData {
+	g.generateRooms()
+
+	// The exit is the room furthest from the start
+	exit := 0
+	for i := range g.rooms {
+		if g.depth[i] > g.depth[exit] {
+			exit = i
+		}
+	}
+
+	g.placeLocks(exit)
+	g.placeEnemies()
+	g.placeSupplies()
+
+	floor := loader.FloorData{Name: "ground floor", Description: "the ground floor"}
+	for _, room := range g.rooms {
+		floor.Rooms = append(floor.Rooms, *room)
+	}
+	return &loader.GameData{
+		Name:          fmt.Sprintf("generated level %d", g.params.Seed),
+		SchemaVersion: loader.CurrentSchemaVersion,
+		Theme:         "survival horror",
+		WinCondition: &loader.EventData{
+			Event:    string(world.EventRoomEntered),
+			RoomName: g.rooms[exit].Name,
+		},
+		Floors:         []loader.FloorData{floor},
+		DoorData:       g.doors,
+		Enemies:        g.enemies,
+		IntroNarrative: "you wake up somewhere unfamiliar. find a way out.",
+		OutroNarrative: "you made it out.",
+	}
+}
+
+// generateRooms connects the rooms as a random tree rooted at the start room,
+// laid out on a grid so that every door leads to a neighboring cell.
+func (g *generator) generateRooms() {
+	for i := range g.params.Rooms {
+		name := g.uniqueName(roomAdjectives, roomNouns)
+		g.rooms = append(g.rooms, &loader.RoomData{
+			Name:        name,
+			Description: "a " + name,
+		})
+		if i == 0 {
+			g.place(world.Position{})
+			g.parent = append(g.parent, -1)
+			g.depth = append(g.depth, 0)
+			g.doorTo = append(g.doorTo, -1)
+			continue
+		}
+
+		// Attach to an earlier room that still has a free neighboring cell
+		var parent int
+		var direction world.Direction
+		for {
+			parent = g.rng.IntN(i)
+			if free := g.freeDirections(parent); len(free) > 0 {
+				direction = free[g.rng.IntN(len(free))]
+				break
+			}
+		}
+
+		doorName := fmt.Sprintf("%s door", name)
+		g.doors = append(g.doors, loader.DoorData{
+			Name:  doorName,
+			RoomA: g.rooms[parent].Name,
+			RoomB: name,
+		})
+		g.rooms[parent].Connections = append(g.rooms[parent].Connections, loader.ConnectionData{
+			Direction: string(direction),
+			DoorName:  doorName,
+		})
+		g.rooms[i].Connections = append(g.rooms[i].Connections, loader.ConnectionData{
+			Direction: string(direction.Opposite()),
+			DoorName:  doorName,
+		})
+		g.place(g.positions[parent].Step(direction))
+		g.parent = append(g.parent, parent)
+		g.depth = append(g.depth, g.depth[parent]+1)
+		g.doorTo = append(g.doorTo, len(g.doors)-1)
+	}
+}
+
+// place positions the most recently added room.
+func (g *generator) place(position world.Position) {
+	g.positions = append(g.positions, position)
+	g.occupied[position] = true
+	g.rooms[len(g.rooms)-1].Position = &loader.PositionData{X: position.X, Y: position.Y}
+}
+
+// freeDirections returns the directions from a room that lead to unoccupied cells.
+func (g *generator) freeDirections(room int) []world.Direction {
+	var free []world.Direction
+	for _, direction := range directions {
+		if !g.occupied[g.positions[room].Step(direction)] {
+			free = append(free, direction)
+		}
+	}
+	return free
+}
+
+// placeLocks locks doors on the path to the exit, and side doors if the path is too short,
+// hiding each key in a room that can be reached with the keys found before it.
+func (g *generator) placeLocks(exit int) {
+	// Path from the start to the exit, as the rooms entered in order
+	var path []int
+	onPath := make(map[int]bool)
+	for room := exit; room != 0; room = g.parent[room] {
+		path = append([]int{room}, path...)
+		onPath[room] = true
+	}
+
+	// Rooms entered through a locked door
+	var locked []int
+	for _, i := range pickSorted(g.rng, len(path), min(g.params.Locks, len(path))) {
+		locked = append(locked, path[i])
+	}
+	if extra := g.params.Locks - len(locked); extra > 0 {
+		var side []int
+		for room := 1; room < len(g.rooms); room++ {
+			if !onPath[room] {
+				side = append(side, room)
+			}
+		}
+		for _, i := range pickSorted(g.rng, len(side), extra) {
+			locked = append(locked, side[i])
+		}
+	}
+	sort.SliceStable(locked, func(i, j int) bool { return g.depth[locked[i]] < g.depth[locked[j]] })
+	lockedRooms := make(map[int]bool)
+	for _, room := range locked {
+		lockedRooms[room] = true
+	}
+
+	// Lock in order of depth, so each key is reachable with the keys placed before it
+	for _, room := range locked {
+		door := &g.doors[g.doorTo[room]]
+		keyName := g.uniqueName(keyAdjectives, []string{"key"})
+		door.Locked = true
+		door.RequiredKeyName = keyName
+
+		// The key can be anywhere not behind this or a later lock
+		var candidates []int
+		for i := range g.rooms {
+			if !g.isBehind(i, lockedRooms) {
+				candidates = append(candidates, i)
+			}
+		}
+		g.hideItem(candidates[g.rng.IntN(len(candidates))], loader.ItemData{
+			Name:        keyName,
+			Description: "a " + keyName,
+			Key:         true,
+		})
+		delete(lockedRooms, room)
+	}
+}
+
+// isBehind returns true if reaching the room requires going through the door into
+// any room in lockedRooms.
+func (g *generator) isBehind(room int, lockedRooms map[int]bool) bool {
+	for ; room != -1; room = g.parent[room] {
+		if lockedRooms[room] {
+			return true
+		}
+	}
+	return false
+}
+
+// hideItem places an item in a room, either in plain sight, in a container or under a concealer.
+func (g *generator) hideItem(room int, item loader.ItemData) {
+	item.Portable = true
+	switch g.rng.IntN(3) {
+	case 0:
+		g.rooms[room].Items = append(g.rooms[room].Items, item)
+	case 1:
+		name := g.uniqueName(containerAdjectives, containerNouns)
+		g.rooms[room].Items = append(g.rooms[room].Items, loader.ItemData{
+			Name:        name,
+			Description: "a " + name,
+			Contains:    &loader.ContainerContents{Item: &item},
+		})
+	case 2:
+		name := g.uniqueName(containerAdjectives, concealerNouns)
+		g.rooms[room].Items = append(g.rooms[room].Items, loader.ItemData{
+			Name:        name,
+			Description: "a " + name,
+			Conceals:    &item,
+		})
+	}
+}
+
+// placeEnemies has each enemy ambush the player when entering a different room.
+func (g *generator) placeEnemies() {
+	for _, i := range pickSorted(g.rng, len(g.rooms)-1, g.params.Enemies) {
+		room := g.rooms[i+1] // never the start room
+		name := g.uniqueName(enemyAdjectives, enemyNouns)
+		g.enemies = append(g.enemies, loader.EnemyData{
+			Name:        name,
+			Description: "a " + name,
+			HP:          g.params.EnemyHP,
+			Room:        room.Name,
+			Trigger: &loader.TriggerData{
+				Event:    string(world.EventRoomEntered),
+				RoomName: room.Name,
+			},
+		})
+	}
+}
+
+// placeSupplies puts a health item in the start room and, if enabled, a weapon.
+func (g *generator) placeSupplies() {
+	if g.params.Enemies == 0 {
+		return
+	}
+	g.rooms[0].Items = append(g.rooms[0].Items, loader.ItemData{
+		Name:         "bandage",
+		Description:  "a roll of bandage",
+		Portable:     true,
+		HealthEffect: string(world.HealthBoostWeak),
+	})
+	if g.params.Weapons {
+		name := g.uniqueName(weaponAdjectives, weaponNouns)
+		g.hideItem(0, loader.ItemData{
+			Name:         name,
+			Description:  "a " + name,
+			WeaponDamage: 0.7,
+		})
+	}
+}
+
+// uniqueName returns a random "adjective noun" name not used before in the level.
+func (g *generator) uniqueName(adjectives []string, nouns []string) string {
+	for attempt := 0; ; attempt++ {
+		name := adjectives[g.rng.IntN(len(adjectives))] + " " + nouns[g.rng.IntN(len(nouns))]
+		if attempt > 100 {
+			name = fmt.Sprintf("%s %d", name, attempt)
+		}
+		if !g.usedNames[name] {
+			g.usedNames[name] = true
+			return name
+		}
+	}
+}
+
+// pickSorted returns k distinct random indices in [0, n), sorted.
+func pickSorted(rng *rand.Rand, n int, k int) []int {
+	perm := rng.Perm(n)[:k]
+	picked := make([]bool, n)
+	for _, i := range perm {
+		picked[i] = true
+	}
+	var sorted []int
+	for i, ok := range picked {
+		if ok {
+			sorted = append(sorted, i)
+		}
+	}
+	return sorted
+}
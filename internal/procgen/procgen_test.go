@@ -0,0 +1,101 @@
+package procgen
+
+import (
+	"bytes"
+	"testing"
+
+	"adventure-engine/pkg/loader"
+	"adventure-engine/pkg/world"
+)
+
+func TestGenerate_Solvable(t *testing.T) {
+	for _, difficulty := range []Difficulty{DifficultyEasy, DifficultyNormal, DifficultyHard} {
+		for seed := range uint64(50) {
+			params, err := DefaultParams(difficulty, seed)
+			if err != nil {
+				t.Fatalf("Failed to get params: %v", err)
+			}
+			data, err := GenerateDocument(params)
+			if err != nil {
+				t.Fatalf("Failed to generate %s level with seed %d: %v", difficulty, seed, err)
+			}
+			level, diagnostics, err := loader.LoadGameWithDiagnostics(data, seed)
+			if err != nil {
+				t.Fatalf("Failed to load %s level with seed %d: %v", difficulty, seed, err)
+			}
+			for _, warning := range diagnostics.Warnings() {
+				t.Errorf("Unexpected warning for %s level with seed %d at %s: %s", difficulty, seed, warning.Path, warning.Message)
+			}
+
+			rooms, locked := 0, 0
+			for _, floor := range level.Floors {
+				rooms += len(floor.Rooms)
+			}
+			for _, door := range level.Doors {
+				if door.Lock != nil && door.Lock.Locked {
+					locked++
+				}
+			}
+			if rooms != params.Rooms {
+				t.Errorf("Expected %d rooms, got %d", params.Rooms, rooms)
+			}
+			if locked != params.Locks {
+				t.Errorf("Expected %d locked doors, got %d", params.Locks, locked)
+			}
+			if len(level.Enemies) != params.Enemies {
+				t.Errorf("Expected %d enemies, got %d", params.Enemies, len(level.Enemies))
+			}
+			if level.WinCondition.Event != world.EventRoomEntered {
+				t.Errorf("Expected room_entered win condition, got %s", level.WinCondition.Event)
+			}
+		}
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	params := Params{Seed: 7, Rooms: 10, Locks: 3, Enemies: 2, EnemyHP: 2}
+	first, err := GenerateDocument(params)
+	if err != nil {
+		t.Fatalf("Failed to generate level: %v", err)
+	}
+	again, err := GenerateDocument(params)
+	if err != nil {
+		t.Fatalf("Failed to generate level: %v", err)
+	}
+	if !bytes.Equal(first, again) {
+		t.Error("Expected the same seed to generate the same level")
+	}
+
+	params.Seed = 8
+	other, err := GenerateDocument(params)
+	if err != nil {
+		t.Fatalf("Failed to generate level: %v", err)
+	}
+	if bytes.Equal(first, other) {
+		t.Error("Expected different seeds to generate different levels")
+	}
+}
+
+func TestParams_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Params
+	}{
+		{"too few rooms", Params{Rooms: 1}},
+		{"too many rooms", Params{Rooms: MaxRooms + 1}},
+		{"too many locks", Params{Rooms: 3, Locks: 3}},
+		{"too many enemies", Params{Rooms: 3, Enemies: 3, EnemyHP: 1}},
+		{"enemies without hp", Params{Rooms: 3, Enemies: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Generate(tt.params); err == nil {
+				t.Error("Expected invalid params to be rejected")
+			}
+		})
+	}
+
+	if _, err := DefaultParams("impossible", 0); err == nil {
+		t.Error("Expected unknown difficulty to be rejected")
+	}
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	v1 "adventure-engine/api/v1"
+	"adventure-engine/pkg/engine"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requireAdmin rejects requests from principals without the admin scope with 403
+// Must run after authenticate.
+func requireAdmin(c *gin.Context) {
+	if !principalOf(c).Admin {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin scope required"})
+		return
+	}
+	c.Next()
+}
+
+// adminListSessions returns every active session with its engine state and statistics, oldest first
+func (srv *Server) adminListSessions(c *gin.Context) {
+	srv.sessions.mu.RLock()
+	sessions := make([]v1.AdminSession, 0, len(srv.sessions.sessions))
+	for _, s := range srv.sessions.sessions {
+		s.mu.RLock()
+		e := s.Engine
+		session := v1.AdminSession{
+			Session: v1.Session{
+				ID:        s.ID,
+				LevelName: s.LevelName,
+				Theme:     s.Theme,
+				Language:  s.language(),
+				Owner:     s.Owner,
+				CreatedAt: s.CreatedAt.Format(time.RFC3339),
+			},
+			LevelCompletionState: string(e.LevelCompletionState),
+			Mode:                 string(e.Mode),
+			StateVersion:         e.StateVersion,
+			ValidationEnabled:    !e.ValidationDisabled,
+			TurnHistoryEnabled:   e.TurnHistory,
+			Checkpoints:          len(s.Checkpoints),
+			Stats: v1.AdminStats{
+				Turns:           e.Stats.Turns,
+				EnemiesDefeated: e.Stats.EnemiesDefeated,
+				DamageTaken:     e.Stats.DamageTaken,
+				SecretsFound:    e.Stats.SecretsFound,
+			},
+		}
+		if e.Corrupted != nil {
+			session.Corrupted = e.Corrupted.Error()
+		}
+		sessions = append(sessions, session)
+		s.mu.RUnlock()
+	}
+	srv.sessions.mu.RUnlock()
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].CreatedAt == sessions[j].CreatedAt {
+			return sessions[i].ID < sessions[j].ID
+		}
+		return sessions[i].CreatedAt < sessions[j].CreatedAt
+	})
+	c.JSON(http.StatusOK, v1.AdminListSessionsResponse{Sessions: sessions})
+}
+
+// setValidation turns engine state validation on or off for a session
+// Disabling validation lets operators poke at sessions whose state has become inconsistent.
+func (srv *Server) setValidation(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.SetValidationRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SetValidationRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	if *requestBody.Enabled {
+		s.Engine.EnableValidation()
+	} else {
+		s.Engine.DisableValidation()
+	}
+	s.mu.Unlock()
+
+	c.JSON(http.StatusOK, v1.SetValidationResponse{
+		SessionID:         sid,
+		ValidationEnabled: *requestBody.Enabled,
+	})
+}
+
+// setTurnHistory turns remembering turns to rewind to on or off for a session
+// It is off by default, as every turn remembered costs a copy of the game state.
+func (srv *Server) setTurnHistory(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.SetTurnHistoryRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SetTurnHistoryRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	if *requestBody.Enabled {
+		s.Engine.EnableTurnHistory()
+	} else {
+		s.Engine.DisableTurnHistory()
+	}
+	s.mu.Unlock()
+
+	c.JSON(http.StatusOK, v1.SetTurnHistoryResponse{
+		SessionID:          sid,
+		TurnHistoryEnabled: *requestBody.Enabled,
+	})
+}
+
+// rewind restores a session to the game state as of an earlier turn, for investigating how it
+// got into a state it shouldn't be in. Sessions with turn history on remember their last 64 turns.
+func (srv *Server) rewind(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.RewindRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid RewindRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	result, err := s.Engine.Rewind(*requestBody.Turn)
+	s.mu.Unlock()
+	if errors.Is(err, engine.ErrVersionExpired) {
+		c.JSON(http.StatusGone, v1.EngineErrorToResponse(err))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1.EngineErrorToResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, v1.EngineResultToResponseRewind(sid, *requestBody.Turn, result))
+}
+
+// getCacheState reports how many levels the level cache holds and how many it can hold
+func (srv *Server) getCacheState(c *gin.Context) {
+	if srv.levelCache == nil {
+		c.JSON(http.StatusOK, v1.CacheStateResponse{})
+		return
+	}
+	c.JSON(http.StatusOK, v1.CacheStateResponse{
+		Enabled:  true,
+		Levels:   srv.levelCache.Len(),
+		Capacity: srv.levelCache.Size(),
+	})
+}
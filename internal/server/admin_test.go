@@ -0,0 +1,116 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "adventure-engine/api/v1"
+	"adventure-engine/pkg/loader"
+)
+
+var testAPIKeys = []APIKey{
+	{Key: "player-key", Principal: Principal{Name: "player"}},
+	{Key: "admin-key", Principal: Principal{Name: "admin", Admin: true}},
+}
+
+func serve(t *testing.T, srv *Server, method, path, key string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var reader *bytes.Reader
+	if body == nil {
+		reader = bytes.NewReader(nil)
+	} else {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("Failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	w := httptest.NewRecorder()
+	newTestRouter(srv).ServeHTTP(w, req)
+	return w
+}
+
+func TestGetCacheState(t *testing.T) {
+	srv := NewServer(Config{APIKeys: testAPIKeys, LevelCache: loader.NewCache(8)})
+
+	if w := serve(t, srv, http.MethodGet, "/admin/v1/cache", "player-key", nil); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a player key, got %d", w.Code)
+	}
+
+	w := serve(t, srv, http.MethodPost, "/api/v1/sessions", "player-key", v1.CreateSessionRequest{LevelName: "demo puzzle"})
+	if w.Code != http.StatusOK && w.Code != http.StatusCreated {
+		t.Fatalf("Failed to create session: %d %s", w.Code, w.Body.String())
+	}
+
+	w = serve(t, srv, http.MethodGet, "/admin/v1/cache", "admin-key", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp v1.CacheStateResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !resp.Enabled || resp.Levels != 1 || resp.Capacity != 8 {
+		t.Errorf("Expected an enabled cache holding 1 of 8 levels, got %+v", resp)
+	}
+}
+
+func TestGetCacheState_NoCache(t *testing.T) {
+	srv := NewServer(Config{APIKeys: testAPIKeys})
+	w := serve(t, srv, http.MethodGet, "/admin/v1/cache", "admin-key", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp v1.CacheStateResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Enabled || resp.Levels != 0 || resp.Capacity != 0 {
+		t.Errorf("Expected no cache, got %+v", resp)
+	}
+}
+
+func TestRewind_TurnHistory(t *testing.T) {
+	srv := NewServer(Config{APIKeys: testAPIKeys})
+	w := serve(t, srv, http.MethodPost, "/api/v1/sessions", "player-key", v1.CreateSessionRequest{LevelName: "demo puzzle"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Failed to create session: %d %s", w.Code, w.Body.String())
+	}
+	var session v1.CreateSessionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &session); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	path := "/api/v1/sessions/" + session.SessionID
+	adminPath := "/admin/v1/sessions/" + session.SessionID
+
+	if w := serve(t, srv, http.MethodPost, path+"/wait", "player-key", v1.WaitRequest{}); w.Code != http.StatusOK {
+		t.Fatalf("Wait failed: %d %s", w.Code, w.Body.String())
+	}
+	turn := 0
+	if w := serve(t, srv, http.MethodPost, adminPath+"/debug/rewind", "admin-key", v1.RewindRequest{Turn: &turn}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected rewinding without turn history to fail with 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	enabled := true
+	if w := serve(t, srv, http.MethodPut, adminPath+"/history", "player-key", v1.SetTurnHistoryRequest{Enabled: &enabled}); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a player key, got %d", w.Code)
+	}
+	if w := serve(t, srv, http.MethodPut, adminPath+"/history", "admin-key", v1.SetTurnHistoryRequest{Enabled: &enabled}); w.Code != http.StatusOK {
+		t.Fatalf("Failed to turn turn history on: %d %s", w.Code, w.Body.String())
+	}
+	if w := serve(t, srv, http.MethodPost, path+"/wait", "player-key", v1.WaitRequest{}); w.Code != http.StatusOK {
+		t.Fatalf("Wait failed: %d %s", w.Code, w.Body.String())
+	}
+	turn = 1
+	if w := serve(t, srv, http.MethodPost, adminPath+"/debug/rewind", "admin-key", v1.RewindRequest{Turn: &turn}); w.Code != http.StatusOK {
+		t.Errorf("Expected to rewind with turn history on, got %d: %s", w.Code, w.Body.String())
+	}
+}
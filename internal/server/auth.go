@@ -0,0 +1,106 @@
+package server
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Principal is the authenticated caller of a request
+// Sessions are owned by the principal that created them; admins can see and act on every session
+type Principal struct {
+	Name  string
+	Admin bool
+}
+
+// APIKey grants the holder of Key the identity of Principal
+type APIKey struct {
+	Key       string
+	Principal Principal
+}
+
+// principalKey is the gin context key the authenticated principal is stored under
+const principalKey = "principal"
+
+// ParseAPIKeys parses a comma separated list of API keys of the form name:key or name:key:admin
+func ParseAPIKeys(spec string) ([]APIKey, error) {
+	var keys []APIKey
+	names := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid API key entry %q, expected name:key or name:key:admin", entry)
+		}
+		if len(parts) == 3 && parts[2] != "admin" {
+			return nil, fmt.Errorf("invalid scope %q for API key %s, only admin is supported", parts[2], parts[0])
+		}
+		if names[parts[0]] {
+			return nil, fmt.Errorf("duplicate API key name %s", parts[0])
+		}
+		names[parts[0]] = true
+		keys = append(keys, APIKey{
+			Key:       parts[1],
+			Principal: Principal{Name: parts[0], Admin: len(parts) == 3},
+		})
+	}
+	return keys, nil
+}
+
+// authenticate returns middleware that rejects requests without a valid API key with 401
+// The key is read from an "Authorization: Bearer <key>" or "X-API-Key: <key>" header.
+// With no keys configured authentication is disabled and every caller is an anonymous admin.
+func authenticate(keys []APIKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(keys) == 0 {
+			c.Set(principalKey, Principal{Admin: true})
+			c.Next()
+			return
+		}
+
+		presented := c.GetHeader("X-API-Key")
+		if auth := c.GetHeader("Authorization"); presented == "" && auth != "" {
+			scheme, token, ok := strings.Cut(auth, " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid Authorization header, expected a bearer token"})
+				return
+			}
+			presented = strings.TrimSpace(token)
+		}
+		if presented == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing API key"})
+			return
+		}
+
+		// Every key is compared in constant time so timing does not reveal how much of a key matched
+		var principal *Principal
+		for i := range keys {
+			if subtle.ConstantTimeCompare([]byte(presented), []byte(keys[i].Key)) == 1 {
+				principal = &keys[i].Principal
+			}
+		}
+		if principal == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid API key"})
+			return
+		}
+		c.Set(principalKey, *principal)
+		c.Next()
+	}
+}
+
+// principalOf returns the principal authenticated for a request
+func principalOf(c *gin.Context) Principal {
+	principal, _ := c.MustGet(principalKey).(Principal)
+	return principal
+}
+
+// canAccess reports whether a principal may see and act on a session
+func canAccess(principal Principal, s *GameSession) bool {
+	return principal.Admin || s.Owner == principal.Name
+}
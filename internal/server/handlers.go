@@ -1,14 +1,25 @@
 package server
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	v1 "adventure-engine/api/v1"
-	"adventure-engine/internal/engine"
-	"adventure-engine/internal/loader"
+	"adventure-engine/internal/narrator"
+	"adventure-engine/internal/parser"
+	"adventure-engine/internal/procgen"
+	"adventure-engine/pkg/engine"
+	"adventure-engine/pkg/loader"
+	"adventure-engine/pkg/world"
 
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/gin-gonic/gin"
@@ -18,30 +29,58 @@ import (
 // GameSession represents a single game session
 // Per-session mutexes synchronizes access to live game state
 type GameSession struct {
-	ID        string
-	LevelName string
+	ID          string
+	LevelName   string
+	Theme       string // the level's system_prompt_theme, for clients to style themselves
+	CreatedAt   time.Time
+	Seed        uint64 // seeds loot placement and combat rolls, so a session can be replayed
+	Owner       string // name of the principal that created the session
+	CallbackURL string // URL state change notifications are posted to, if any
+	Engine      *engine.Engine
+	Narration   *narrator.Session      // nil unless the session has narration on
+	Checkpoints map[string]*Checkpoint // checkpoint name -> checkpoint
+	webhook     *webhook               // nil without a callback URL
+	runRecorded bool                   // true once the completed run is on the leaderboard
+	mu          sync.RWMutex
+}
+
+// sessionOptions are the settings a session is created with
+type sessionOptions struct {
+	Owner       string
+	TurnPolicy  engine.TurnPolicy  // empty keeps the engine's default
+	DeathPolicy engine.DeathPolicy // empty keeps the engine's default
+	Combat      world.CombatModel  // empty keeps the level's combat model
+	CallbackURL string
+	Narration   bool
+	Language    string // empty keeps the level's own language
+}
+
+// Checkpoint is a named snapshot of a session's game state
+type Checkpoint struct {
+	Name      string
 	CreatedAt time.Time
-	Engine    *engine.Engine
-	mu        sync.RWMutex
+	Snapshot  *engine.Snapshot
 }
 
 // SessionStore holds all active game sessions
-// Global mutex synchronizes access to sessions map
+// Store mutex synchronizes access to sessions map
 type SessionStore struct {
 	sessions map[string]*GameSession
 	mu       sync.RWMutex
 }
 
-// Global session store
-var sessionStore = &SessionStore{
-	sessions: make(map[string]*GameSession),
+// NewSessionStore returns an empty session store
+func NewSessionStore() *SessionStore {
+	return &SessionStore{sessions: make(map[string]*GameSession)}
 }
 
-func safeGetSessionFromStore(sid string, c *gin.Context) *GameSession {
-	sessionStore.mu.RLock()
-	s, ok := sessionStore.sessions[sid]
-	sessionStore.mu.RUnlock()
-	if !ok {
+// safeGetSessionFromStore looks up a session the caller may access, responding 404 if there is none
+// Sessions owned by someone else are reported as not found so their IDs cannot be probed
+func (srv *Server) safeGetSessionFromStore(sid string, c *gin.Context) *GameSession {
+	srv.sessions.mu.RLock()
+	s, ok := srv.sessions.sessions[sid]
+	srv.sessions.mu.RUnlock()
+	if !ok || !canAccess(principalOf(c), s) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
 		return nil
 	}
@@ -51,58 +90,218 @@ func safeGetSessionFromStore(sid string, c *gin.Context) *GameSession {
 // --- session management ---
 
 // createSession creates a new game session, loading the level from the request body
-func createSession(c *gin.Context) {
+// or by name from the uploaded or bundled levels
+func (srv *Server) createSession(c *gin.Context) {
 	var req v1.CreateSessionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "details": err.Error()})
 		return
 	}
 
-	level, err := loader.LoadGame(req.Level)
+	// Seeds are kept below 2^53 so that they survive JSON clients using floating point numbers
+	seed := rand.Uint64N(1 << 53)
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	if (len(req.Level) > 0) == (req.LevelName != "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "details": "give either level or level_name"})
+		return
+	}
+	data := req.Level
+	if req.LevelName != "" {
+		var ok bool
+		data, ok = srv.findLevel(req.LevelName)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "level not found"})
+			return
+		}
+	}
+
+	level, err := srv.loadLevel(data, seed)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to load level", "details": err.Error()})
 		return
 	}
+	if req.Language != "" && !level.HasLanguage(req.Language) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "details": "the level is not available in " + req.Language})
+		return
+	}
+
+	session := srv.storeNewSession(level, seed, sessionOptions{
+		Owner:       principalOf(c).Name,
+		TurnPolicy:  engine.TurnPolicy(req.TurnPolicy),
+		DeathPolicy: engine.DeathPolicy(req.DeathPolicy),
+		Combat:      world.CombatModel(req.Combat),
+		CallbackURL: req.CallbackURL,
+		Narration:   req.Narration,
+		Language:    req.Language,
+	})
+	c.JSON(http.StatusOK, v1.CreateSessionResponse{
+		SessionID:      session.ID,
+		Seed:           seed,
+		Theme:          level.Theme,
+		IntroNarrative: level.Localize(level.IntroNarrative, req.Language),
+	})
+}
+
+// generateSession creates a new game session on a procedurally generated level
+func (srv *Server) generateSession(c *gin.Context) {
+	var req v1.GenerateSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "details": err.Error()})
+		return
+	}
+
+	seed := rand.Uint64N(1 << 53)
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	params, err := procgen.DefaultParams(procgen.Difficulty(req.Difficulty), seed)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "details": err.Error()})
+		return
+	}
+	if req.Rooms != nil {
+		params.Rooms = *req.Rooms
+	}
+	if req.Locks != nil {
+		params.Locks = *req.Locks
+	}
+	if req.Enemies != nil {
+		params.Enemies = *req.Enemies
+	}
+	if req.EnemyHP != nil {
+		params.EnemyHP = *req.EnemyHP
+	}
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid generation parameters", "details": err.Error()})
+		return
+	}
+
+	data, err := procgen.GenerateDocument(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate level", "details": err.Error()})
+		return
+	}
+	level, err := srv.loadLevel(data, seed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load generated level", "details": err.Error()})
+		return
+	}
+
+	session := srv.storeNewSession(level, seed, sessionOptions{
+		Owner:       principalOf(c).Name,
+		TurnPolicy:  engine.TurnPolicy(req.TurnPolicy),
+		DeathPolicy: engine.DeathPolicy(req.DeathPolicy),
+		Combat:      world.CombatModel(req.Combat),
+		CallbackURL: req.CallbackURL,
+		Narration:   req.Narration,
+		Language:    req.Language,
+	})
+	c.JSON(http.StatusOK, v1.GenerateSessionResponse{
+		CreateSessionResponse: v1.CreateSessionResponse{
+			SessionID:      session.ID,
+			Seed:           seed,
+			Theme:          level.Theme,
+			IntroNarrative: level.Localize(level.IntroNarrative, req.Language),
+		},
+		Level: data,
+	})
+}
 
+// storeNewSession creates a session playing the level with a seeded engine and stores it
+func (srv *Server) storeNewSession(level *world.Level, seed uint64, options sessionOptions) *GameSession {
 	sid := uuid.New().String()
 	session := &GameSession{
-		ID:        sid,
-		LevelName: level.Name,
-		CreatedAt: time.Now(),
-		Engine:    engine.NewEngine(level),
+		ID:          sid,
+		LevelName:   level.Name,
+		Theme:       level.Theme,
+		CreatedAt:   srv.now(),
+		Seed:        seed,
+		Owner:       options.Owner,
+		CallbackURL: options.CallbackURL,
+		Engine:      engine.NewEngine(level),
+		Checkpoints: make(map[string]*Checkpoint),
+	}
+	session.Engine.Rng = engine.NewSeededRng(seed)
+	if options.TurnPolicy != "" {
+		session.Engine.TurnPolicy = options.TurnPolicy
+	}
+	if options.DeathPolicy != "" {
+		session.Engine.DeathPolicy = options.DeathPolicy
+	}
+	if options.Combat != "" {
+		session.Engine.Combat = engine.NewCombatResolver(options.Combat)
+	}
+	session.Engine.Language = options.Language
+	session.Engine.Plugins = srv.plugins
+	if options.Narration {
+		session.Narration = narrator.NewSession(srv.narrator, level.Theme, srv.narrationTimeout)
+	}
+	if options.CallbackURL != "" {
+		session.webhook = newWebhook(options.CallbackURL, srv.webhookClient, srv.logger)
 	}
 
-	sessionStore.mu.Lock()
-	sessionStore.sessions[sid] = session
-	sessionStore.mu.Unlock()
+	srv.sessions.mu.Lock()
+	srv.sessions.sessions[sid] = session
+	srv.sessions.mu.Unlock()
+	return session
+}
 
-	c.JSON(http.StatusOK, v1.CreateSessionResponse{
-		SessionID:      sid,
-		IntroNarrative: level.IntroNarrative,
-	})
+// validateLevel runs the loader's validation passes on a level without creating a session
+// The report is returned with 200 even when the level is invalid
+func (srv *Server) validateLevel(c *gin.Context) {
+	var req v1.ValidateLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "details": err.Error()})
+		return
+	}
+
+	diagnostics := loader.ValidateLevel(req.Level)
+	c.JSON(http.StatusOK, v1.DiagnosticsToResponseValidate(diagnostics))
+}
+
+// getLevelSchema returns the JSON Schema describing the level format
+func (srv *Server) getLevelSchema(c *gin.Context) {
+	c.Data(http.StatusOK, "application/schema+json", loader.JSONSchema())
 }
 
-// listSessions returns metadata about all active sessions
-func listSessions(c *gin.Context) {
-	sessionStore.mu.RLock()
-	sessions := make([]v1.Session, 0, len(sessionStore.sessions))
-	for _, s := range sessionStore.sessions {
+// listSessions returns metadata about the active sessions the caller may access, oldest first
+func (srv *Server) listSessions(c *gin.Context) {
+	principal := principalOf(c)
+	srv.sessions.mu.RLock()
+	sessions := make([]v1.Session, 0, len(srv.sessions.sessions))
+	for _, s := range srv.sessions.sessions {
+		if !canAccess(principal, s) {
+			continue
+		}
 		s.mu.RLock()
 		sessions = append(sessions, v1.Session{
 			ID:        s.ID,
 			LevelName: s.LevelName,
+			Theme:     s.Theme,
+			Language:  s.language(),
+			Owner:     s.Owner,
 			CreatedAt: s.CreatedAt.Format(time.RFC3339),
 		})
 		s.mu.RUnlock()
 	}
-	sessionStore.mu.RUnlock()
+	srv.sessions.mu.RUnlock()
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].CreatedAt == sessions[j].CreatedAt {
+			return sessions[i].ID < sessions[j].ID
+		}
+		return sessions[i].CreatedAt < sessions[j].CreatedAt
+	})
 	c.JSON(http.StatusOK, v1.ListSessionsResponse{Sessions: sessions})
 }
 
 // getSession returns metadata and live engine state for a session
-func getSession(c *gin.Context) {
+func (srv *Server) getSession(c *gin.Context) {
 	sid := c.Param("sid")
-	s := safeGetSessionFromStore(sid, c)
+	s := srv.safeGetSessionFromStore(sid, c)
 	if s == nil {
 		return
 	}
@@ -112,224 +311,1132 @@ func getSession(c *gin.Context) {
 		Session: v1.Session{
 			ID:        s.ID,
 			LevelName: s.LevelName,
+			Theme:     s.Theme,
+			Language:  s.language(),
+			Owner:     s.Owner,
 			CreatedAt: s.CreatedAt.Format(time.RFC3339),
 		},
 		EngineStateInfo: v1.EngineStateInfo{
 			LevelCompletionState: string(s.Engine.LevelCompletionState),
 			Mode:                 string(s.Engine.Mode),
+			StateVersion:         s.Engine.StateVersion,
+		},
+	}
+	s.mu.RUnlock()
+	c.JSON(http.StatusOK, resp)
+}
+
+// deleteSession deletes a game session
+// Note: this just deletes the reference -- it should be GC'd eventually
+func (srv *Server) deleteSession(c *gin.Context) {
+	sid := c.Param("sid")
+	srv.sessions.mu.Lock()
+	s, ok := srv.sessions.sessions[sid]
+	if !ok || !canAccess(principalOf(c), s) {
+		srv.sessions.mu.Unlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+	delete(srv.sessions.sessions, sid)
+	srv.sessions.mu.Unlock()
+	// An action may still be running on the session and notifying its webhook, so the
+	// webhook is only closed under the session lock.
+	s.mu.Lock()
+	if s.webhook != nil {
+		s.webhook.close()
+		s.webhook = nil
+	}
+	s.mu.Unlock()
+	c.JSON(http.StatusOK, v1.DeleteSessionResponse{SessionID: sid})
+}
+
+// getDebug returns detailed debug information for a game session
+func (srv *Server) getDebug(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+	s.mu.RLock()
+	debugResult, err := s.Engine.Debug()
+	s.mu.RUnlock()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get debug info", "details": err.Error()})
+		return
+	}
+	debugJSON, err := json.Marshal(debugResult)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to marshal debug info", "details": err.Error()})
+		return
+	}
+	resp := v1.DebugResponse{
+		Session: v1.Session{
+			ID:        s.ID,
+			LevelName: s.LevelName,
+			Theme:     s.Theme,
+			Language:  s.language(),
+			Owner:     s.Owner,
+			CreatedAt: s.CreatedAt.Format(time.RFC3339),
+		},
+		Debug: debugJSON,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// getScore returns the statistics, score and earned badges for a game session
+func (srv *Server) getScore(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+	s.mu.RLock()
+	result, err := s.Engine.Score()
+	s.mu.RUnlock()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get score", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, v1.EngineResultToResponseScore(result))
+}
+
+// getStats returns the play statistics for a game session
+func (srv *Server) getStats(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+	s.mu.RLock()
+	result, err := s.Engine.Statistics()
+	s.mu.RUnlock()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get stats", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, v1.EngineResultToResponseStats(result))
+}
+
+// getPostMortem returns how the player died in a game session whose level has failed
+func (srv *Server) getPostMortem(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+	s.mu.RLock()
+	result, err := s.Engine.PostMortem()
+	s.mu.RUnlock()
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, v1.EngineResultToResponsePostMortem(result))
+}
+
+// getCombatLog returns the rounds of a game session's current fight, or of its last one.
+func (srv *Server) getCombatLog(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+	s.mu.RLock()
+	result, err := s.Engine.CombatLog()
+	s.mu.RUnlock()
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, v1.EngineResultToResponseCombatLog(result))
+}
+
+// getDiff returns what changed in a game session since the state version in ?since.
+// Versions too old for the session to remember get 410, and clients should fetch the context.
+func (srv *Server) getDiff(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+	since, err := strconv.ParseUint(c.Query("since"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since", "details": "since must be a state version"})
+		return
+	}
+	s.mu.RLock()
+	result, err := s.Engine.Diff(since)
+	s.mu.RUnlock()
+	if errors.Is(err, engine.ErrVersionExpired) {
+		c.JSON(http.StatusGone, v1.EngineErrorToResponse(err))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, v1.EngineErrorToResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, v1.EngineResultToResponseDiff(result))
+}
+
+// getObjectives returns the objectives revealed so far in a game session
+func (srv *Server) getObjectives(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+	s.mu.RLock()
+	result, err := s.Engine.Objectives()
+	s.mu.RUnlock()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get objectives", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, v1.EngineResultToResponseObjectives(result))
+}
+
+// getRecipes returns the crafting recipes the players have discovered.
+// With ?include_undiscovered=true the other recipes are listed too, without their outputs.
+func (srv *Server) getRecipes(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+	includeUndiscovered := false
+	if value := c.Query("include_undiscovered"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid include_undiscovered", "details": err.Error()})
+			return
+		}
+		includeUndiscovered = parsed
+	}
+	s.mu.RLock()
+	result, err := s.Engine.Recipes(includeUndiscovered)
+	s.mu.RUnlock()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get recipes", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, v1.EngineResultToResponseRecipes(result))
+}
+
+// exportLevel returns the current game state of a session as a level in loader format
+func (srv *Server) exportLevel(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+	s.mu.RLock()
+	level, err := s.Engine.ExportLevel()
+	s.mu.RUnlock()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export level", "details": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, v1.ExportLevelResponse{Level: level})
+}
+
+// --- checkpoints ---
+
+// maxCheckpoints bounds how many checkpoints a session keeps, each a copy of the game state
+const maxCheckpoints = 16
+
+// createCheckpoint saves the current game state under a name, replacing any
+// existing checkpoint with the same name
+// A session with maxCheckpoints checkpoints can only replace them, not add more.
+func (srv *Server) createCheckpoint(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.CreateCheckpointRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CreateCheckpointRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	if _, ok := s.Checkpoints[requestBody.Name]; !ok && len(s.Checkpoints) >= maxCheckpoints {
+		s.mu.Unlock()
+		c.JSON(http.StatusConflict, gin.H{"error": "too many checkpoints", "details": fmt.Sprintf("a session keeps at most %d checkpoints; reuse a name to replace one", maxCheckpoints)})
+		return
+	}
+	checkpoint := &Checkpoint{
+		Name:      requestBody.Name,
+		CreatedAt: srv.now(),
+		Snapshot:  s.Engine.Snapshot(),
+	}
+	s.Checkpoints[checkpoint.Name] = checkpoint
+	s.mu.Unlock()
+
+	c.JSON(http.StatusOK, v1.CreateCheckpointResponse{
+		Checkpoint: v1.Checkpoint{
+			Name:      checkpoint.Name,
+			CreatedAt: checkpoint.CreatedAt.Format(time.RFC3339),
 		},
+	})
+}
+
+// listCheckpoints returns the checkpoints saved for a session, oldest first
+func (srv *Server) listCheckpoints(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	s.mu.RLock()
+	checkpoints := make([]v1.Checkpoint, 0, len(s.Checkpoints))
+	for _, checkpoint := range s.Checkpoints {
+		checkpoints = append(checkpoints, v1.Checkpoint{
+			Name:      checkpoint.Name,
+			CreatedAt: checkpoint.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	s.mu.RUnlock()
+	sort.Slice(checkpoints, func(i, j int) bool {
+		if checkpoints[i].CreatedAt == checkpoints[j].CreatedAt {
+			return checkpoints[i].Name < checkpoints[j].Name
+		}
+		return checkpoints[i].CreatedAt < checkpoints[j].CreatedAt
+	})
+
+	c.JSON(http.StatusOK, v1.ListCheckpointsResponse{Checkpoints: checkpoints})
+}
+
+// restoreCheckpoint restores the game state saved under a checkpoint name
+// The checkpoint is kept, so it can be restored again later
+func (srv *Server) restoreCheckpoint(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+
+	checkpoint, ok := s.Checkpoints[c.Param("name")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "checkpoint not found"})
+		return
+	}
+
+	result, err := s.Engine.Restore(checkpoint.Snapshot)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore checkpoint", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.EngineResultToResponseRestore(checkpoint.Name, result))
+}
+
+// respawn takes a player who died back to the last save point room entered, restoring the
+// game state recorded then
+func (srv *Server) respawn(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+
+	result, err := s.Engine.Respawn()
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.EngineResultToResponseRespawn(result))
+}
+
+// observeFilter reads an observation's filter from the query, rejecting the request with 400
+// if the query is invalid.
+func observeFilter(c *gin.Context) (engine.ObserveFilter, bool) {
+	filter := engine.ObserveFilter{
+		NamePrefix: c.Query("prefix"),
+		Cursor:     c.Query("cursor"),
+	}
+	flags := []struct {
+		param string
+		only  *bool
+	}{{"portable", &filter.Portable}, {"containers", &filter.Containers}}
+	for _, flag := range flags {
+		if value := c.Query(flag.param); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + flag.param, "details": err.Error()})
+				return filter, false
+			}
+			*flag.only = parsed
+		}
+	}
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxObservePageSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit", "details": fmt.Sprintf("limit must be a number from 1 to %d", maxObservePageSize)})
+			return filter, false
+		}
+		filter.Limit = parsed
+	}
+	return filter, true
+}
+
+// engineErrorStatus returns the HTTP status for an action the engine failed: 500 if the session's
+// game state is corrupted, and 422 for any other reason
+func engineErrorStatus(err error) int {
+	if errors.Is(err, engine.ErrCorrupted) {
+		return http.StatusInternalServerError
+	}
+	return http.StatusUnprocessableEntity
+}
+
+// checkStateVersion rejects a request with 409 if its If-Match header names a state version
+// other than the session's current one, so clients cannot act on outdated state.
+// Requests without If-Match are always accepted. Must be called with the session locked.
+func checkStateVersion(c *gin.Context, s *GameSession) bool {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" || ifMatch == "*" {
+		return true
+	}
+	version, err := strconv.ParseUint(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid If-Match header", "details": err.Error()})
+		return false
+	}
+	if version != s.Engine.StateVersion {
+		c.JSON(http.StatusConflict, v1.StaleStateResponse{
+			Error:        "state version mismatch",
+			StateVersion: s.Engine.StateVersion,
+		})
+		return false
+	}
+	return true
+}
+
+// --- players ---
+
+// listPlayers returns the players of a multiplayer session
+func (srv *Server) listPlayers(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	result := s.Engine.ListPlayers()
+	s.mu.Unlock()
+	c.JSON(http.StatusOK, v1.EngineResultToResponsePlayers(result))
+}
+
+// addPlayer adds a player to a session, making it a multiplayer session
+// The session's original player is "host"
+func (srv *Server) addPlayer(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.AddPlayerRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid AddPlayerRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+
+	result, err := s.Engine.AddPlayer(requestBody.ID)
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, v1.EngineResultToResponsePlayers(result))
+}
+
+// actAsPlayer makes the player named in the route the one the engine acts for, or the host
+// on routes without a player. Responds 404 for unknown players. Must be called with the session locked.
+func actAsPlayer(c *gin.Context, s *GameSession) bool {
+	pid := c.Param("pid")
+	if pid == "" {
+		pid = engine.HostPlayerID
+	}
+	if err := s.Engine.SwitchPlayer(pid); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+		return false
+	}
+	return true
+}
+
+// actionContext returns the context a game action runs under: with an action timeout, the
+// request's, so actions stop when their client goes away, cut off after the timeout. Without one,
+// actions run to the end under a context that never ends, which spares the engine from copying the
+// game state before every action to roll back to.
+func (srv *Server) actionContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	ctx := c.Request.Context()
+	if srv.config.ActionTimeout <= 0 {
+		return context.WithoutCancel(ctx), func() {}
+	}
+	return context.WithTimeout(ctx, srv.config.ActionTimeout)
+}
+
+// --- game actions ---
+//
+// Actions are served both per session, acting for the host, and per player under
+// /sessions/:sid/players/:pid/actions.
+//
+// Note on return codes for game actions:
+// Failed validation in handler: 400 bad request
+// If-Match header naming a stale state version: 409 conflict
+// Unknown player: 404 not found
+// Engine returned error: 422 unprocessable entity, with did_you_mean set for ambiguous names,
+// error_code not_your_turn for players acting out of turn and error_code interrupted for actions
+// cut off by the action timeout or a disconnecting client
+// Engine did not return error: 200 ok
+
+// maxObservePageSize bounds how many items an observation lists per page
+const maxObservePageSize = 100
+
+// observe handles observe action requests
+// ?portable=true and ?containers=true only list portable items and containers, ?prefix only
+// items whose names start with it, and ?limit pages the items, with ?cursor set to the previous
+// page's next_cursor for the pages after the first.
+func (srv *Server) observe(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+	filter, ok := observeFilter(c)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Observe(ctx, filter)
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+
+	response := v1.EngineResultToResponseObserve(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbObserve}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
+}
+
+// inspect handles inspect action requests
+func (srv *Server) inspect(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.InspectRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid InspectRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Inspect(ctx, requestBody.TargetName)
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+
+	response := v1.EngineResultToResponseInspect(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbInspect, Target: requestBody.TargetName}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
+}
+
+// uncover handles uncover action requests
+func (srv *Server) uncover(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.InspectRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UncoverRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Uncover(ctx, requestBody.TargetName)
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+
+	response := v1.EngineResultToResponseUncover(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbUncover, Target: requestBody.TargetName}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
+}
+
+// unlock handles unlock action requests
+func (srv *Server) unlock(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.UnlockRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UnlockRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Unlock(ctx, requestBody.KeyOrCode, requestBody.TargetName)
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+
+	response := v1.EngineResultToResponseUnlock(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbUnlock, Target: requestBody.TargetName, Item: requestBody.KeyOrCode}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
+}
+
+// search handles search action requests
+func (srv *Server) search(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.SearchRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SearchRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Search(ctx, requestBody.TargetName)
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+
+	response := v1.EngineResultToResponseSearch(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbSearch, Target: requestBody.TargetName}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
+}
+
+// interrogate handles interrogate action requests
+func (srv *Server) interrogate(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.InterrogateRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid InterrogateRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Interrogate(ctx, requestBody.EnemyName)
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+
+	response := v1.EngineResultToResponseInterrogate(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbInterrogate, Target: requestBody.EnemyName}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
+}
+
+// take handles take action requests
+func (srv *Server) take(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.TakeRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid TakeRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.TakeQuantity(ctx, requestBody.TargetName, requestBody.Quantity)
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+
+	response := v1.EngineResultToResponseTake(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbTake, Target: requestBody.TargetName, Quantity: requestBody.Quantity}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
+}
+
+// inventory handles inventory action requests
+func (srv *Server) inventory(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Inventory(ctx)
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+
+	response := v1.EngineResultToResponseInventory(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbInventory}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
+}
+
+// status handles status action requests
+func (srv *Server) status(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
 	}
-	s.mu.RUnlock()
-	c.JSON(http.StatusOK, resp)
-}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
 
-// deleteSession deletes a game session
-// Note: this just deletes the reference -- it should be GC'd eventually
-func deleteSession(c *gin.Context) {
-	sid := c.Param("sid")
-	sessionStore.mu.Lock()
-	_, ok := sessionStore.sessions[sid]
-	if !ok {
-		sessionStore.mu.Unlock()
-		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+	result, err := s.Engine.Status(ctx)
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
 		return
 	}
-	delete(sessionStore.sessions, sid)
-	sessionStore.mu.Unlock()
-	c.JSON(http.StatusOK, v1.DeleteSessionResponse{SessionID: sid})
+
+	response := v1.EngineResultToResponseStatus(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbStatus}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
 }
 
-// getDebug returns detailed debug information for a game session
-func getDebug(c *gin.Context) {
+// heal handles heal action requests
+func (srv *Server) heal(c *gin.Context) {
 	sid := c.Param("sid")
-	s := safeGetSessionFromStore(sid, c)
+	s := srv.safeGetSessionFromStore(sid, c)
 	if s == nil {
 		return
 	}
-	s.mu.RLock()
-	debugResult, err := s.Engine.Debug()
-	s.mu.RUnlock()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get debug info", "details": err.Error()})
+
+	var requestBody v1.HealRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid HealRequest", "details": err.Error()})
 		return
 	}
-	debugJSON, err := json.Marshal(debugResult)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to marshal debug info", "details": err.Error()})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
 		return
 	}
-	resp := v1.DebugResponse{
-		Session: v1.Session{
-			ID:        s.ID,
-			LevelName: s.LevelName,
-			CreatedAt: s.CreatedAt.Format(time.RFC3339),
-		},
-		Debug: debugJSON,
+	if !actAsPlayer(c, s) {
+		return
 	}
-	c.JSON(http.StatusOK, resp)
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Heal(ctx, requestBody.HealthItemName)
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+
+	response := v1.EngineResultToResponseHeal(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbHeal, Item: requestBody.HealthItemName}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
 }
 
-// --- game actions ---
-//
-// Note on return codes for game actions:
-// Failed validation in handler: 400 bad request
-// Engine returned error: 422 unprocessable entity
-// Engine did not return error: 200 ok
+// wait handles wait action requests
+func (srv *Server) wait(c *gin.Context) {
+	srv.waitOrRest(c, parser.VerbWait)
+}
 
-// observe handles observe action requests
-func observe(c *gin.Context) {
+// rest handles rest action requests
+func (srv *Server) rest(c *gin.Context) {
+	srv.waitOrRest(c, parser.VerbRest)
+}
+
+// waitOrRest handles wait and rest action requests, which differ only in the action run
+func (srv *Server) waitOrRest(c *gin.Context, verb parser.Verb) {
 	sid := c.Param("sid")
-	s := safeGetSessionFromStore(sid, c)
+	s := srv.safeGetSessionFromStore(sid, c)
 	if s == nil {
 		return
 	}
 
+	var requestBody v1.WaitRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid WaitRequest", "details": err.Error()})
+		return
+	}
+	turns := max(requestBody.Turns, 1)
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	result, err := s.Engine.Observe()
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	var result *engine.WaitResult
+	var err error
+	if verb == parser.VerbRest {
+		result, err = s.Engine.Rest(ctx, turns)
+	} else {
+		result, err = s.Engine.Wait(ctx, turns)
+	}
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, v1.EngineResultToResponseObserve(result))
+	response := v1.EngineResultToResponseWait(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: verb, Quantity: requestBody.Turns}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
 }
 
-// inspect handles inspect action requests
-func inspect(c *gin.Context) {
+// traverse handles traverse action requests
+func (srv *Server) traverse(c *gin.Context) {
 	sid := c.Param("sid")
-	s := safeGetSessionFromStore(sid, c)
+	s := srv.safeGetSessionFromStore(sid, c)
 	if s == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
 		return
 	}
 
-	var requestBody v1.InspectRequest
+	var requestBody v1.TraverseRequest
 	if err := c.BindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid InspectRequest", "details": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid TraverseRequest", "details": err.Error()})
 		return
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	result, err := s.Engine.Inspect(requestBody.TargetName)
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Traverse(ctx, requestBody.Destination)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, v1.EngineResultToResponseInspect(result))
+	response := v1.EngineResultToResponseTraverse(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbTraverse, Target: requestBody.Destination}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
 }
 
-// uncover handles uncover action requests
-func uncover(c *gin.Context) {
+// listen handles listen action requests
+func (srv *Server) listen(c *gin.Context) {
 	sid := c.Param("sid")
-	s := safeGetSessionFromStore(sid, c)
+	s := srv.safeGetSessionFromStore(sid, c)
 	if s == nil {
 		return
 	}
 
-	var requestBody v1.InspectRequest
+	var requestBody v1.ListenRequest
 	if err := c.BindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UncoverRequest", "details": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ListenRequest", "details": err.Error()})
 		return
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	result, err := s.Engine.Uncover(requestBody.TargetName)
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Listen(ctx, requestBody.Door)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, v1.EngineResultToResponseUncover(result))
+	response := v1.EngineResultToResponseListen(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbListen, Target: requestBody.Door}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
 }
 
-// unlock handles unlock action requests
-func unlock(c *gin.Context) {
+// peek handles peek action requests
+func (srv *Server) peek(c *gin.Context) {
 	sid := c.Param("sid")
-	s := safeGetSessionFromStore(sid, c)
+	s := srv.safeGetSessionFromStore(sid, c)
 	if s == nil {
 		return
 	}
 
-	var requestBody v1.UnlockRequest
+	var requestBody v1.PeekRequest
 	if err := c.BindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UnlockRequest", "details": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid PeekRequest", "details": err.Error()})
 		return
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	result, err := s.Engine.Unlock(requestBody.KeyOrCode, requestBody.TargetName)
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Peek(ctx, requestBody.Door)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, v1.EngineResultToResponseUnlock(result))
+	response := v1.EngineResultToResponsePeek(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbPeek, Target: requestBody.Door}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
 }
 
-// search handles search action requests
-func search(c *gin.Context) {
+// latch handles latch action requests
+func (srv *Server) latch(c *gin.Context) {
 	sid := c.Param("sid")
-	s := safeGetSessionFromStore(sid, c)
+	s := srv.safeGetSessionFromStore(sid, c)
 	if s == nil {
 		return
 	}
 
-	var requestBody v1.SearchRequest
+	var requestBody v1.LatchRequest
 	if err := c.BindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SearchRequest", "details": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid LatchRequest", "details": err.Error()})
 		return
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	result, err := s.Engine.Search(requestBody.TargetName)
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Latch(ctx, requestBody.Door)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, v1.EngineResultToResponseSearch(result))
+	response := v1.EngineResultToResponseLatch(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbLatch, Target: requestBody.Door}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
 }
 
-// take handles take action requests
-func take(c *gin.Context) {
+// battle handles battle action requests
+func (srv *Server) battle(c *gin.Context) {
 	sid := c.Param("sid")
-	s := safeGetSessionFromStore(sid, c)
+	s := srv.safeGetSessionFromStore(sid, c)
 	if s == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
 		return
 	}
 
-	var requestBody v1.TakeRequest
+	var requestBody v1.BattleRequest
 	if err := c.BindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid TakeRequest", "details": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid BattleRequest", "details": err.Error()})
 		return
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	result, err := s.Engine.Take(requestBody.TargetName)
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Battle(ctx, requestBody.WeaponName)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, v1.EngineResultToResponseTake(result))
+	response := v1.EngineResultToResponseBattle(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbBattle, Item: requestBody.WeaponName}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
 }
 
-// inventory handles inventory action requests
-func inventory(c *gin.Context) {
+// intimidate handles intimidate action requests
+func (srv *Server) intimidate(c *gin.Context) {
 	sid := c.Param("sid")
-	s := safeGetSessionFromStore(sid, c)
+	s := srv.safeGetSessionFromStore(sid, c)
 	if s == nil {
 		return
 	}
@@ -337,159 +1444,260 @@ func inventory(c *gin.Context) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	result, err := s.Engine.Inventory()
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Intimidate(ctx)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, v1.EngineResultToResponseInventory(result))
+	response := v1.EngineResultToResponseResolve(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbIntimidate}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
 }
 
-// heal handles heal action requests
-func heal(c *gin.Context) {
+// bribe handles bribe action requests
+func (srv *Server) bribe(c *gin.Context) {
 	sid := c.Param("sid")
-	s := safeGetSessionFromStore(sid, c)
+	s := srv.safeGetSessionFromStore(sid, c)
 	if s == nil {
 		return
 	}
 
-	var requestBody v1.HealRequest
+	var requestBody v1.BribeRequest
 	if err := c.BindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid HealRequest", "details": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid BribeRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Bribe(ctx, requestBody.ItemName)
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+
+	response := v1.EngineResultToResponseResolve(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbBribe, Item: requestBody.ItemName}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
+}
+
+// sneak handles sneak action requests
+func (srv *Server) sneak(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
 		return
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	result, err := s.Engine.Heal(requestBody.HealthItemName)
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Sneak(ctx)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, v1.EngineResultToResponseHeal(result))
+	response := v1.EngineResultToResponseResolve(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbSneak}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
 }
 
-// traverse handles traverse action requests
-func traverse(c *gin.Context) {
+// combine handles combine action requests
+func (srv *Server) combine(c *gin.Context) {
 	sid := c.Param("sid")
-	s := safeGetSessionFromStore(sid, c)
+	s := srv.safeGetSessionFromStore(sid, c)
 	if s == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
 		return
 	}
 
-	var requestBody v1.TraverseRequest
+	var requestBody v1.CombineRequest
 	if err := c.BindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid TraverseRequest", "details": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CombineRequest", "details": err.Error()})
 		return
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	traverseResult, err := s.Engine.Traverse(requestBody.Destination)
-	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	if !checkStateVersion(c, s) {
 		return
 	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
 
-	// Observe the room after entering and use this as the response
+	itemNames := requestBody.ItemNames
+	if len(itemNames) == 0 {
+		itemNames = []string{requestBody.InputItemAName, requestBody.InputItemBName}
+	}
+	result, err := s.Engine.Combine(ctx, itemNames...)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, v1.EngineResultToResponseTraverse(traverseResult))
+	response := v1.EngineResultToResponseCombine(result)
+	response.Narration = s.Narration.Narrate(parser.CombineAction(itemNames), result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
 }
 
-// battle handles battle action requests
-func battle(c *gin.Context) {
+// use handles use action requests
+func (srv *Server) use(c *gin.Context) {
 	sid := c.Param("sid")
-	s := safeGetSessionFromStore(sid, c)
+	s := srv.safeGetSessionFromStore(sid, c)
 	if s == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
 		return
 	}
 
-	var requestBody v1.BattleRequest
+	var requestBody v1.UseRequest
 	if err := c.BindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid BattleRequest", "details": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UseRequest", "details": err.Error()})
 		return
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	result, err := s.Engine.Battle(requestBody.WeaponName)
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Use(ctx, requestBody.ItemName, requestBody.TargetName)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, v1.EngineResultToResponseBattle(result))
+	response := v1.EngineResultToResponseUse(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbUse, Item: requestBody.ItemName, Target: requestBody.TargetName}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
 }
 
-// combine handles combine action requests
-func combine(c *gin.Context) {
+// move handles move action requests
+func (srv *Server) move(c *gin.Context) {
 	sid := c.Param("sid")
-	s := safeGetSessionFromStore(sid, c)
+	s := srv.safeGetSessionFromStore(sid, c)
 	if s == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
 		return
 	}
 
-	var requestBody v1.CombineRequest
+	var requestBody v1.MoveRequest
 	if err := c.BindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CombineRequest", "details": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid MoveRequest", "details": err.Error()})
 		return
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	result, err := s.Engine.Combine(requestBody.InputItemAName, requestBody.InputItemBName)
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Move(ctx, requestBody.ItemName, requestBody.Direction)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, v1.EngineResultToResponseCombine(result))
+	response := v1.EngineResultToResponseMove(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbMove, Target: requestBody.ItemName, Direction: requestBody.Direction}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
 }
 
-// use handles use action requests
-func use(c *gin.Context) {
+// travel handles fast travel requests
+func (srv *Server) travel(c *gin.Context) {
 	sid := c.Param("sid")
-	s := safeGetSessionFromStore(sid, c)
+	s := srv.safeGetSessionFromStore(sid, c)
 	if s == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
 		return
 	}
 
-	var requestBody v1.UseRequest
+	var requestBody v1.TravelRequest
 	if err := c.BindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UseRequest", "details": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid TravelRequest", "details": err.Error()})
 		return
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	result, err := s.Engine.Use(requestBody.ItemName, requestBody.TargetName)
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	result, err := s.Engine.Travel(ctx, requestBody.NodeName)
 	if err != nil {
-		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, v1.EngineResultToResponseUse(result))
+	response := v1.EngineResultToResponseTravel(result)
+	response.Narration = s.Narration.Narrate(parser.Action{Verb: parser.VerbTravel, Target: requestBody.NodeName}, result, response)
+	srv.notifyStateChange(s, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
 }
 
-// context returns the current room context and inventory
-func context(c *gin.Context) {
+// context returns the current room, inventory, objectives and minimap in one call
+// The verbosity query parameter (brief or full, default full) controls how much detail is included
+func (srv *Server) context(c *gin.Context) {
 	sid := c.Param("sid")
-	session := safeGetSessionFromStore(sid, c)
+	session := srv.safeGetSessionFromStore(sid, c)
 	if session == nil {
 		return
 	}
@@ -497,26 +1705,28 @@ func context(c *gin.Context) {
 	session.mu.Lock()
 	defer session.mu.Unlock()
 
-	observeResult, err := session.Engine.Observe()
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to observe", "details": err.Error()})
+	if !actAsPlayer(c, session) {
 		return
 	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
 
-	inventoryResult, err := session.Engine.Inventory()
+	verbosity := engine.ContextVerbosity(c.DefaultQuery("verbosity", string(engine.ContextFull)))
+	contextResult, err := session.Engine.Context(ctx, verbosity)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to get inventory", "details": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to get context", "details": err.Error()})
 		return
 	}
 
-	response := v1.EngineResultToResponseContext(observeResult, inventoryResult)
+	response := v1.EngineResultToResponseContext(contextResult)
+	srv.notifyStateChange(session, response.EngineStateInfo)
 	c.JSON(http.StatusOK, response)
 }
 
-// minimap returns minimap data for the current floor
-func minimap(c *gin.Context) {
+// minimap returns minimap data for every floor
+func (srv *Server) minimap(c *gin.Context) {
 	sid := c.Param("sid")
-	session := safeGetSessionFromStore(sid, c)
+	session := srv.safeGetSessionFromStore(sid, c)
 	if session == nil {
 		return
 	}
@@ -524,12 +1734,122 @@ func minimap(c *gin.Context) {
 	session.mu.Lock()
 	defer session.mu.Unlock()
 
-	minimapResult, err := session.Engine.Minimap()
+	if !actAsPlayer(c, session) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	minimapResult, err := session.Engine.Minimap(ctx)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to get minimap", "details": err.Error()})
 		return
 	}
 
 	response := v1.EngineResultToResponseMinimap(minimapResult)
+	srv.notifyStateChange(session, response.EngineStateInfo)
+	c.JSON(http.StatusOK, response)
+}
+
+// annotateMinimap writes a note on a room or door on the minimap, and returns the minimap
+func (srv *Server) annotateMinimap(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.MinimapNoteRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid MinimapNoteRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	var result *engine.MinimapResult
+	var err error
+	if requestBody.RoomName != "" {
+		result, err = s.Engine.AnnotateRoom(ctx, requestBody.RoomName, requestBody.Note)
+	} else {
+		result, err = s.Engine.AnnotateDoor(ctx, requestBody.DoorName, requestBody.Note)
+	}
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+
+	response := v1.EngineResultToResponseMinimap(result)
+	srv.notifyStateChange(s, response.EngineStateInfo)
 	c.JSON(http.StatusOK, response)
 }
+
+// command parses a free text command, after expanding the level's verb aliases, and runs the
+// game action it maps to
+// Commands that cannot be parsed are bad requests, like invalid bodies for the other actions
+func (srv *Server) command(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.CommandRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CommandRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !checkStateVersion(c, s) {
+		return
+	}
+	if !actAsPlayer(c, s) {
+		return
+	}
+	ctx, cancel := srv.actionContext(c)
+	defer cancel()
+
+	action, err := parser.Parse(parser.ExpandAliases(requestBody.Text, s.Engine.Level.VerbAliases), s.Engine.ReferableNames())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := srv.runAction(ctx, s, action)
+	if err != nil {
+		response := v1.EngineErrorToResponse(err)
+		commandAction := v1.ParserActionToResponse(action)
+		response.Action = &commandAction
+		c.JSON(engineErrorStatus(err), response)
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.CommandResponse{
+		Action: v1.ParserActionToResponse(action),
+		Result: result,
+	})
+}
+
+// runAction runs a parsed action on the session's engine, notifying the session's webhook
+// of any state change
+func (srv *Server) runAction(ctx context.Context, s *GameSession, action *parser.Action) (any, error) {
+	response, state, err := v1.RunAction(ctx, s.Engine, action, s.Narration)
+	if err != nil {
+		return nil, err
+	}
+	srv.notifyStateChange(s, state)
+	return response, nil
+}
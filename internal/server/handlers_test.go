@@ -0,0 +1,62 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "adventure-engine/api/v1"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestActionContext(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+
+	// Without a timeout, actions run under a context that never ends, so the engine doesn't
+	// copy the game state to roll back to
+	ctx, cancel := NewServer(Config{}).actionContext(c)
+	cancel()
+	if ctx.Done() != nil {
+		t.Error("Expected a context that never ends without an action timeout")
+	}
+
+	ctx, cancel = NewServer(Config{ActionTimeout: time.Second}).actionContext(c)
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("Expected the action timeout to set a deadline")
+	}
+}
+
+func TestCreateCheckpoint_Limit(t *testing.T) {
+	srv := NewServer(Config{})
+	w := serve(t, srv, http.MethodPost, "/api/v1/sessions", "", v1.CreateSessionRequest{LevelName: "demo puzzle"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Failed to create session: %d %s", w.Code, w.Body.String())
+	}
+	var session v1.CreateSessionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &session); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	path := "/api/v1/sessions/" + session.SessionID + "/checkpoints"
+
+	for i := range maxCheckpoints {
+		if w := serve(t, srv, http.MethodPost, path, "", v1.CreateCheckpointRequest{Name: fmt.Sprintf("save %d", i)}); w.Code != http.StatusOK {
+			t.Fatalf("Failed to create checkpoint %d: %d %s", i, w.Code, w.Body.String())
+		}
+	}
+	if w := serve(t, srv, http.MethodPost, path, "", v1.CreateCheckpointRequest{Name: "one too many"}); w.Code != http.StatusConflict {
+		t.Errorf("Expected a checkpoint beyond the limit to be rejected with 409, got %d: %s", w.Code, w.Body.String())
+	}
+	// Checkpoints can still be replaced
+	if w := serve(t, srv, http.MethodPost, path, "", v1.CreateCheckpointRequest{Name: "save 0"}); w.Code != http.StatusOK {
+		t.Errorf("Expected to replace a checkpoint, got %d: %s", w.Code, w.Body.String())
+	}
+	if n := len(srv.sessions.sessions[session.SessionID].Checkpoints); n != maxCheckpoints {
+		t.Errorf("Expected %d checkpoints, got %d", maxCheckpoints, n)
+	}
+}
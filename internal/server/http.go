@@ -1,35 +1,243 @@
 package server
 
 import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"adventure-engine/internal/leaderboard"
+	"adventure-engine/internal/narrator"
+	"adventure-engine/pkg/engine"
+	"adventure-engine/pkg/loader"
+	"adventure-engine/pkg/world"
+
 	"github.com/gin-gonic/gin"
 )
 
+// Config holds the server's access controls, narration settings and dependencies
+// Dependencies left unset get defaults, so the zero Config serves an empty server.
+type Config struct {
+	// APIKeys are the keys accepted by the server; with none, authentication is disabled
+	APIKeys []APIKey
+	// IPRateLimit limits the requests each client IP can make
+	IPRateLimit RateLimit
+	// SessionRateLimit limits the requests made on each session, whoever makes them
+	SessionRateLimit RateLimit
+	// Narrator narrates sessions with narration on; nil narrates from templates
+	Narrator narrator.Narrator
+	// NarrationTimeout bounds each narration, after which templates are used; 0 is no limit
+	NarrationTimeout time.Duration
+	// Leaderboard records completed runs and serves the leaderboards; nil turns leaderboards off
+	Leaderboard leaderboard.Store
+	// Plugins add custom mechanics to the engine of every session, for servers built on this one
+	Plugins []engine.Plugin
+	// ActionTimeout bounds each game action, after which it is rolled back and fails; 0 is no limit
+	ActionTimeout time.Duration
+	// Sessions holds the game sessions; nil starts with no sessions
+	Sessions *SessionStore
+	// Levels holds the uploaded levels; nil starts with none uploaded
+	Levels *LevelStore
+	// LoadLevel loads the levels sessions are created on; nil uses LevelCache, or
+	// loader.LoadGameWithSeed without one
+	LoadLevel LevelLoader
+	// LevelCache loads each level document once and is reported on by the admin API; nil caches nothing
+	LevelCache *loader.Cache
+	// Clock tells the time sessions, checkpoints, runs and rate limits are stamped with; nil uses time.Now
+	Clock func() time.Time
+	// Logger logs failures that cannot be reported to a client, such as undelivered webhooks;
+	// nil uses the standard logger
+	Logger *log.Logger
+}
+
+// LevelLoader loads a level document, placing its loot with the seed
+type LevelLoader func(data json.RawMessage, seed uint64) (*world.Level, error)
+
+// Server serves the API from its own sessions, levels and settings, so several servers
+// can run side by side in one process
+type Server struct {
+	config           Config
+	sessions         *SessionStore
+	levels           *LevelStore
+	loadLevel        LevelLoader
+	levelCache       *loader.Cache // nil when levels aren't cached
+	now              func() time.Time
+	logger           *log.Logger
+	narrator         narrator.Narrator
+	narrationTimeout time.Duration
+	leaderboard      leaderboard.Store // nil when leaderboards are off
+	plugins          []engine.Plugin
+	webhookClient    *http.Client
+}
+
+// NewServer returns a server with the config's dependencies, defaulting those left unset
+func NewServer(config Config) *Server {
+	srv := &Server{
+		config:           config,
+		sessions:         config.Sessions,
+		levels:           config.Levels,
+		loadLevel:        config.LoadLevel,
+		levelCache:       config.LevelCache,
+		now:              config.Clock,
+		logger:           config.Logger,
+		narrator:         config.Narrator,
+		narrationTimeout: config.NarrationTimeout,
+		leaderboard:      config.Leaderboard,
+		plugins:          config.Plugins,
+		webhookClient:    &http.Client{Timeout: webhookTimeout},
+	}
+	if srv.sessions == nil {
+		srv.sessions = NewSessionStore()
+	}
+	if srv.levels == nil {
+		srv.levels = NewLevelStore()
+	}
+	if srv.loadLevel == nil && srv.levelCache != nil {
+		srv.loadLevel = srv.levelCache.Load
+	}
+	if srv.loadLevel == nil {
+		srv.loadLevel = loader.LoadGameWithSeed
+	}
+	if srv.now == nil {
+		srv.now = time.Now
+	}
+	if srv.logger == nil {
+		srv.logger = log.Default()
+	}
+	if srv.narrator == nil {
+		srv.narrator = narrator.Templates{}
+	}
+	return srv
+}
+
 // SetupRoutes configures all the API routes for the multitenant server
-func SetupRoutes(r *gin.Engine) {
-	v1 := r.Group("api/v1")
+func (srv *Server) SetupRoutes(r *gin.Engine) {
+	config := srv.config
+	v1 := r.Group("api/v1",
+		srv.limitRate(config.IPRateLimit, "client", func(c *gin.Context) string { return c.ClientIP() }),
+		authenticate(config.APIKeys),
+		srv.recoverSession,
+	)
 	{
-		v1.POST("/sessions", createSession)
-		v1.GET("/sessions", listSessions)
-		v1.GET("/sessions/:sid", getSession)
-		v1.GET("/sessions/:sid/debug", getDebug)
-		v1.DELETE("/sessions/:sid", deleteSession)
+		v1.POST("/sessions", srv.createSession)
+		v1.POST("/sessions/generate", srv.generateSession)
+		v1.GET("/sessions", srv.listSessions)
+		v1.GET("/sessions/:sid", srv.getSession)
+		v1.GET("/sessions/:sid/debug", srv.getDebug)
+		v1.GET("/sessions/:sid/score", srv.getScore)
+		v1.GET("/sessions/:sid/stats", srv.getStats)
+		v1.GET("/sessions/:sid/postmortem", srv.getPostMortem)
+		v1.GET("/sessions/:sid/combat-log", srv.getCombatLog)
+		v1.GET("/sessions/:sid/diff", srv.getDiff)
+		v1.GET("/sessions/:sid/objectives", srv.getObjectives)
+		v1.GET("/sessions/:sid/recipes", srv.getRecipes)
+		v1.GET("/sessions/:sid/export", srv.exportLevel)
+		v1.DELETE("/sessions/:sid", srv.deleteSession)
+		v1.PUT("/sessions/:sid/narration", srv.setNarration)
+		v1.PUT("/sessions/:sid/language", srv.setLanguage)
+		v1.GET("/levels", srv.listLevels)
+		v1.GET("/levels/schema", srv.getLevelSchema)
+		v1.GET("/levels/:name", srv.getLevel)
+		v1.POST("/levels/:name", srv.createLevel)
+		v1.PUT("/levels/:name", srv.putLevel)
+		v1.DELETE("/levels/:name", srv.deleteLevel)
+		v1.POST("/levels/validate", srv.validateLevel)
+		if srv.leaderboard != nil {
+			v1.GET("/leaderboards/:level", srv.getLeaderboard)
+		}
 
-		sess := v1.Group("/sessions/:sid")
+		sess := v1.Group("/sessions/:sid",
+			srv.limitRate(config.SessionRateLimit, "session", func(c *gin.Context) string { return c.Param("sid") }),
+		)
 		{
-			sess.POST("/observe", observe)
-			sess.POST("/inspect", inspect)
-			sess.POST("/uncover", uncover)
-			sess.POST("/unlock", unlock)
-			sess.POST("/search", search)
-			sess.POST("/take", take)
-			sess.POST("/inventory", inventory)
-			sess.POST("/heal", heal)
-			sess.POST("/traverse", traverse)
-			sess.POST("/battle", battle)
-			sess.POST("/combine", combine)
-			sess.POST("/use", use)
-			sess.POST("/context", context)
-			sess.POST("/minimap", minimap)
+			sess.POST("/observe", srv.observe)
+			sess.POST("/inspect", srv.inspect)
+			sess.POST("/uncover", srv.uncover)
+			sess.POST("/unlock", srv.unlock)
+			sess.POST("/search", srv.search)
+			sess.POST("/interrogate", srv.interrogate)
+			sess.POST("/take", srv.take)
+			sess.POST("/inventory", srv.inventory)
+			sess.POST("/status", srv.status)
+			sess.POST("/heal", srv.heal)
+			sess.POST("/wait", srv.wait)
+			sess.POST("/rest", srv.rest)
+			sess.POST("/traverse", srv.traverse)
+			sess.POST("/listen", srv.listen)
+			sess.POST("/peek", srv.peek)
+			sess.POST("/latch", srv.latch)
+			sess.POST("/battle", srv.battle)
+			sess.POST("/intimidate", srv.intimidate)
+			sess.POST("/bribe", srv.bribe)
+			sess.POST("/sneak", srv.sneak)
+			sess.POST("/combine", srv.combine)
+			sess.POST("/use", srv.use)
+			sess.POST("/move", srv.move)
+			sess.POST("/travel", srv.travel)
+			sess.POST("/context", srv.context)
+			sess.POST("/minimap", srv.minimap)
+			sess.POST("/minimap/notes", srv.annotateMinimap)
+			sess.POST("/command", srv.command)
+
+			sess.GET("/checkpoints", srv.listCheckpoints)
+			sess.POST("/checkpoints", srv.createCheckpoint)
+			sess.POST("/checkpoints/:name/restore", srv.restoreCheckpoint)
+			sess.POST("/respawn", srv.respawn)
+
+			sess.GET("/players", srv.listPlayers)
+			sess.POST("/players", srv.addPlayer)
+
+			player := sess.Group("/players/:pid/actions")
+			{
+				player.POST("/observe", srv.observe)
+				player.POST("/inspect", srv.inspect)
+				player.POST("/uncover", srv.uncover)
+				player.POST("/unlock", srv.unlock)
+				player.POST("/search", srv.search)
+				player.POST("/interrogate", srv.interrogate)
+				player.POST("/take", srv.take)
+				player.POST("/inventory", srv.inventory)
+				player.POST("/status", srv.status)
+				player.POST("/heal", srv.heal)
+				player.POST("/wait", srv.wait)
+				player.POST("/rest", srv.rest)
+				player.POST("/traverse", srv.traverse)
+				player.POST("/listen", srv.listen)
+				player.POST("/peek", srv.peek)
+				player.POST("/latch", srv.latch)
+				player.POST("/battle", srv.battle)
+				player.POST("/intimidate", srv.intimidate)
+				player.POST("/bribe", srv.bribe)
+				player.POST("/sneak", srv.sneak)
+				player.POST("/combine", srv.combine)
+				player.POST("/use", srv.use)
+				player.POST("/move", srv.move)
+				player.POST("/travel", srv.travel)
+				player.POST("/context", srv.context)
+				player.POST("/minimap", srv.minimap)
+				player.POST("/minimap/notes", srv.annotateMinimap)
+				player.POST("/command", srv.command)
+			}
 		}
 	}
+
+	// The admin API needs an admin key, so it is only served when authentication is enabled
+	if len(config.APIKeys) == 0 {
+		return
+	}
+	admin := r.Group("admin/v1",
+		srv.limitRate(config.IPRateLimit, "client", func(c *gin.Context) string { return c.ClientIP() }),
+		authenticate(config.APIKeys),
+		requireAdmin,
+		srv.recoverSession,
+	)
+	{
+		admin.GET("/sessions", srv.adminListSessions)
+		admin.DELETE("/sessions/:sid", srv.deleteSession)
+		admin.GET("/sessions/:sid/debug", srv.getDebug)
+		admin.PUT("/sessions/:sid/validation", srv.setValidation)
+		admin.PUT("/sessions/:sid/history", srv.setTurnHistory)
+		admin.POST("/sessions/:sid/debug/rewind", srv.rewind)
+		admin.GET("/cache", srv.getCacheState)
+	}
 }
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"net/http"
+
+	v1 "adventure-engine/api/v1"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setLanguage switches the language a session's level text is given in
+func (srv *Server) setLanguage(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.SetLanguageRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SetLanguageRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	err := s.Engine.SetLanguage(requestBody.Language)
+	s.mu.Unlock()
+	if err != nil {
+		c.JSON(engineErrorStatus(err), v1.EngineErrorToResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.SetLanguageResponse{
+		SessionID: sid,
+		Language:  requestBody.Language,
+	})
+}
+
+// language returns the language the session's level text is given in
+func (s *GameSession) language() string {
+	if s.Engine.Language != "" {
+		return s.Engine.Language
+	}
+	return s.Engine.Level.Languages()[0]
+}
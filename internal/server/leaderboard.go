@@ -0,0 +1,75 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	v1 "adventure-engine/api/v1"
+	"adventure-engine/internal/leaderboard"
+	"adventure-engine/pkg/engine"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultLeaderboardSize and maxLeaderboardSize bound how many runs a leaderboard lists
+const (
+	defaultLeaderboardSize = 10
+	maxLeaderboardSize     = 100
+)
+
+// recordRun adds the session's run to the leaderboard the first time it completes its level.
+// Must be called with the session locked.
+func (srv *Server) recordRun(s *GameSession, state v1.EngineStateInfo) {
+	if srv.leaderboard == nil || s.runRecorded || state.Notification != string(engine.EngineStateChangeLevelComplete) || state.Score == nil {
+		return
+	}
+	s.runRecorded = true
+	now := srv.now()
+	err := srv.leaderboard.Record(leaderboard.Run{
+		Level:       s.LevelName,
+		Player:      s.Owner,
+		SessionID:   s.ID,
+		Turns:       state.Score.TurnsTaken,
+		Score:       state.Score.Score,
+		Duration:    now.Sub(s.CreatedAt),
+		CompletedAt: now,
+	})
+	if err != nil {
+		srv.logger.Printf("failed to record the run of session %s on the leaderboard: %v", s.ID, err)
+	}
+}
+
+// getLeaderboard returns the best completed runs of a level.
+// ?limit sets how many runs are listed, 10 by default and at most 100.
+func (srv *Server) getLeaderboard(c *gin.Context) {
+	level := c.Param("level")
+	limit := defaultLeaderboardSize
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxLeaderboardSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit", "details": fmt.Sprintf("limit must be a number from 1 to %d", maxLeaderboardSize)})
+			return
+		}
+		limit = parsed
+	}
+	runs, err := srv.leaderboard.Top(level, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get leaderboard", "details": err.Error()})
+		return
+	}
+	response := v1.LeaderboardResponse{Level: level, Runs: make([]v1.LeaderboardRun, 0, len(runs))}
+	for i, run := range runs {
+		response.Runs = append(response.Runs, v1.LeaderboardRun{
+			Rank:            i + 1,
+			Player:          run.Player,
+			SessionID:       run.SessionID,
+			Turns:           run.Turns,
+			Score:           run.Score,
+			DurationSeconds: run.Duration.Seconds(),
+			CompletedAt:     run.CompletedAt.Format(time.RFC3339),
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
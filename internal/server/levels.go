@@ -0,0 +1,205 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "adventure-engine/api/v1"
+	"adventure-engine/internal/library"
+	"adventure-engine/pkg/loader"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StoredLevel is a level uploaded to the server, so sessions can be created by level name
+type StoredLevel struct {
+	Name           string
+	Theme          string
+	IntroNarrative string
+	VerbAliases    map[string]string
+	Owner          string // name of the principal that uploaded the level
+	UpdatedAt      time.Time
+	Data           json.RawMessage
+}
+
+// LevelStore holds the uploaded levels
+// Uploaded levels can be used by everyone, but only changed by their owner or an admin.
+type LevelStore struct {
+	levels map[string]*StoredLevel
+	mu     sync.RWMutex
+}
+
+// NewLevelStore returns an empty level store
+func NewLevelStore() *LevelStore {
+	return &LevelStore{levels: make(map[string]*StoredLevel)}
+}
+
+// reservedLevelNames cannot be used for uploaded levels, as they are routes of their own
+var reservedLevelNames = map[string]bool{"schema": true, "validate": true}
+
+// findLevel returns the data of an uploaded or bundled level
+func (srv *Server) findLevel(name string) (json.RawMessage, bool) {
+	srv.levels.mu.RLock()
+	stored, ok := srv.levels.levels[name]
+	srv.levels.mu.RUnlock()
+	if ok {
+		return stored.Data, true
+	}
+	bundled, ok := library.Get(name)
+	return bundled.Data, ok
+}
+
+// listLevels returns the bundled and uploaded levels, sorted by name
+func (srv *Server) listLevels(c *gin.Context) {
+	levels := make([]v1.LevelSummary, 0, len(library.List()))
+	for _, level := range library.List() {
+		levels = append(levels, bundledLevelSummary(level))
+	}
+	srv.levels.mu.RLock()
+	for _, level := range srv.levels.levels {
+		levels = append(levels, storedLevelSummary(level))
+	}
+	srv.levels.mu.RUnlock()
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Name < levels[j].Name })
+	c.JSON(http.StatusOK, v1.ListLevelsResponse{Levels: levels})
+}
+
+// getLevel returns a bundled or uploaded level
+func (srv *Server) getLevel(c *gin.Context) {
+	name := c.Param("name")
+	srv.levels.mu.RLock()
+	stored, ok := srv.levels.levels[name]
+	srv.levels.mu.RUnlock()
+	if ok {
+		c.JSON(http.StatusOK, v1.GetLevelResponse{LevelSummary: storedLevelSummary(stored), Level: stored.Data})
+		return
+	}
+	if bundled, ok := library.Get(name); ok {
+		c.JSON(http.StatusOK, v1.GetLevelResponse{LevelSummary: bundledLevelSummary(bundled), Level: bundled.Data})
+		return
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "level not found"})
+}
+
+// createLevel uploads a new level, responding 409 if the name is taken
+func (srv *Server) createLevel(c *gin.Context) {
+	srv.storeLevel(c, false)
+}
+
+// putLevel uploads a level, replacing the level of the same name if the caller owns it
+func (srv *Server) putLevel(c *gin.Context) {
+	srv.storeLevel(c, true)
+}
+
+// storeLevel validates an uploaded level and stores it under the name in the route
+// Levels with validation errors are rejected with 400 and the validation report.
+func (srv *Server) storeLevel(c *gin.Context, replace bool) {
+	name := c.Param("name")
+	if reservedLevelNames[name] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid level name", "details": "the name " + name + " is reserved"})
+		return
+	}
+	if _, ok := library.Get(name); ok {
+		c.JSON(http.StatusConflict, gin.H{"error": "a bundled level has this name"})
+		return
+	}
+
+	var req v1.PutLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "details": err.Error()})
+		return
+	}
+	diagnostics := loader.ValidateLevel(req.Level)
+	if diagnostics.HasErrors() {
+		c.JSON(http.StatusBadRequest, v1.DiagnosticsToResponseValidate(diagnostics))
+		return
+	}
+	var header loader.GameData
+	if err := json.Unmarshal(req.Level, &header); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid level", "details": err.Error()})
+		return
+	}
+
+	principal := principalOf(c)
+	srv.levels.mu.Lock()
+	existing, exists := srv.levels.levels[name]
+	if exists && !replace {
+		srv.levels.mu.Unlock()
+		c.JSON(http.StatusConflict, gin.H{"error": "level already exists"})
+		return
+	}
+	if exists && !canChangeLevel(principal, existing) {
+		srv.levels.mu.Unlock()
+		c.JSON(http.StatusForbidden, gin.H{"error": "level belongs to someone else"})
+		return
+	}
+	stored := &StoredLevel{
+		Name:           name,
+		Theme:          header.Theme,
+		IntroNarrative: header.IntroNarrative,
+		VerbAliases:    header.VerbAliases,
+		Owner:          principal.Name,
+		UpdatedAt:      srv.now(),
+		Data:           req.Level,
+	}
+	if exists {
+		stored.Owner = existing.Owner
+	}
+	srv.levels.levels[name] = stored
+	srv.levels.mu.Unlock()
+
+	c.JSON(http.StatusOK, v1.PutLevelResponse{
+		LevelSummary: storedLevelSummary(stored),
+		Warnings:     v1.DiagnosticsToResponseWarnings(diagnostics),
+	})
+}
+
+// deleteLevel deletes an uploaded level
+// Sessions already playing the level are not affected.
+func (srv *Server) deleteLevel(c *gin.Context) {
+	name := c.Param("name")
+	srv.levels.mu.Lock()
+	stored, ok := srv.levels.levels[name]
+	if !ok {
+		srv.levels.mu.Unlock()
+		c.JSON(http.StatusNotFound, gin.H{"error": "level not found"})
+		return
+	}
+	if !canChangeLevel(principalOf(c), stored) {
+		srv.levels.mu.Unlock()
+		c.JSON(http.StatusForbidden, gin.H{"error": "level belongs to someone else"})
+		return
+	}
+	delete(srv.levels.levels, name)
+	srv.levels.mu.Unlock()
+	c.JSON(http.StatusOK, v1.DeleteLevelResponse{Name: name})
+}
+
+// canChangeLevel reports whether a principal may replace or delete an uploaded level
+func canChangeLevel(principal Principal, level *StoredLevel) bool {
+	return principal.Admin || level.Owner == principal.Name
+}
+
+func bundledLevelSummary(level library.Level) v1.LevelSummary {
+	return v1.LevelSummary{
+		Name:           level.Name,
+		Theme:          level.Theme,
+		IntroNarrative: level.IntroNarrative,
+		VerbAliases:    level.VerbAliases,
+		Bundled:        true,
+	}
+}
+
+func storedLevelSummary(level *StoredLevel) v1.LevelSummary {
+	return v1.LevelSummary{
+		Name:           level.Name,
+		Theme:          level.Theme,
+		IntroNarrative: level.IntroNarrative,
+		VerbAliases:    level.VerbAliases,
+		Owner:          level.Owner,
+		UpdatedAt:      level.UpdatedAt.Format(time.RFC3339),
+	}
+}
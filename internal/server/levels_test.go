@@ -0,0 +1,47 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "adventure-engine/api/v1"
+	"adventure-engine/internal/library"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRouter(srv *Server) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	srv.SetupRoutes(r)
+	return r
+}
+
+func TestStoreLevel_ReservedNames(t *testing.T) {
+	level, ok := library.Get("demo puzzle")
+	if !ok {
+		t.Fatal("Expected the demo puzzle to be bundled")
+	}
+	body, err := json.Marshal(v1.PutLevelRequest{Level: level.Data})
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+
+	srv := NewServer(Config{})
+	r := newTestRouter(srv)
+	for _, name := range []string{"schema", "validate"} {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/levels/"+name, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected level named %s to be rejected with 400, got %d: %s", name, w.Code, w.Body.String())
+		}
+		if _, ok := srv.levels.levels[name]; ok {
+			t.Errorf("Expected no level to be stored as %s", name)
+		}
+	}
+}
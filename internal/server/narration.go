@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+
+	v1 "adventure-engine/api/v1"
+	"adventure-engine/internal/narrator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setNarration turns narration on or off for a session
+// Turning it back on starts a fresh narration history.
+func (srv *Server) setNarration(c *gin.Context) {
+	sid := c.Param("sid")
+	s := srv.safeGetSessionFromStore(sid, c)
+	if s == nil {
+		return
+	}
+
+	var requestBody v1.SetNarrationRequest
+	if err := c.BindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SetNarrationRequest", "details": err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	if !*requestBody.Enabled {
+		s.Narration = nil
+	} else if s.Narration == nil {
+		s.Narration = narrator.NewSession(srv.narrator, s.Theme, srv.narrationTimeout)
+	}
+	s.mu.Unlock()
+
+	c.JSON(http.StatusOK, v1.SetNarrationResponse{
+		SessionID:        sid,
+		NarrationEnabled: *requestBody.Enabled,
+	})
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit allows PerSecond requests per second on average, with bursts of up to Burst requests
+// The zero value disables limiting.
+type RateLimit struct {
+	PerSecond float64
+	Burst     int
+}
+
+// Enabled reports whether the limit restricts anything
+func (l RateLimit) Enabled() bool {
+	return l.PerSecond > 0
+}
+
+// ParseRateLimit parses a rate limit of the form rate or rate:burst, where rate is in requests
+// per second. The burst defaults to the rate rounded up. An empty string disables limiting.
+func ParseRateLimit(spec string) (RateLimit, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return RateLimit{}, nil
+	}
+	rateSpec, burstSpec, hasBurst := strings.Cut(spec, ":")
+	perSecond, err := strconv.ParseFloat(rateSpec, 64)
+	if err != nil || perSecond <= 0 || math.IsInf(perSecond, 0) {
+		return RateLimit{}, fmt.Errorf("invalid rate %q, expected a positive number of requests per second", rateSpec)
+	}
+	limit := RateLimit{PerSecond: perSecond, Burst: int(math.Ceil(perSecond))}
+	if hasBurst {
+		burst, err := strconv.Atoi(burstSpec)
+		if err != nil || burst < 1 {
+			return RateLimit{}, fmt.Errorf("invalid burst %q, expected a positive integer", burstSpec)
+		}
+		limit.Burst = burst
+	}
+	return limit, nil
+}
+
+// bucket is a token bucket holding up to Burst tokens, refilled at PerSecond tokens per second
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter keeps a token bucket for every key that has made requests recently
+type rateLimiter struct {
+	limit   RateLimit
+	buckets map[string]*bucket
+	calls   int
+	mu      sync.Mutex
+}
+
+// pruneEvery is how many calls a rate limiter handles between sweeps for idle buckets
+const pruneEvery = 1024
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// allow takes a token from the key's bucket, returning how long to wait for one if it is empty
+func (l *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if l.calls%pruneEvery == 0 {
+		l.prune(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(l.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*l.limit.PerSecond)
+	b.last = now
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.limit.PerSecond * float64(time.Second))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// prune drops buckets that have refilled completely, as they are the same as new ones
+func (l *rateLimiter) prune(now time.Time) {
+	full := time.Duration(float64(l.limit.Burst) / l.limit.PerSecond * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= full {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// limitRate returns middleware that rejects requests with 429 once the requests sharing a key
+// exceed the limit. Rejected requests carry a Retry-After header in whole seconds.
+func (srv *Server) limitRate(limit RateLimit, scope string, keyOf func(c *gin.Context) string) gin.HandlerFunc {
+	if !limit.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+	limiter := newRateLimiter(limit)
+	return func(c *gin.Context) {
+		allowed, wait := limiter.allow(keyOf(c), srv.now())
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": fmt.Sprintf("too many requests for this %s", scope)})
+			return
+		}
+		c.Next()
+	}
+}
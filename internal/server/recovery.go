@@ -0,0 +1,58 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	v1 "adventure-engine/api/v1"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recoverSession turns a panic while serving a request into a 500, logging it with the session
+// the request was for. The session is marked corrupted, so that its later actions fail with 500
+// instead of panicking again, while every other session carries on.
+func (srv *Server) recoverSession(c *gin.Context) {
+	defer func() {
+		cause := recover()
+		if cause == nil {
+			return
+		}
+		sid := c.Param("sid")
+		srv.logger.Printf("panic serving %s %s for session %q: %v\n%s", c.Request.Method, c.Request.URL.Path, sid, cause, debug.Stack())
+		err := srv.markCorrupted(sid, fmt.Sprintf("panic: %v", cause))
+		if c.Writer.Written() {
+			c.Abort()
+			return
+		}
+		if err == nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, v1.EngineErrorToResponse(err))
+	}()
+	c.Next()
+}
+
+// markCorrupted marks the engine of a session corrupted after a panic, and returns the error its
+// actions fail with from then on. Returns nil if there is no such session, or if the panic left
+// it locked, which a handler that locks without deferring the unlock does.
+func (srv *Server) markCorrupted(sid, reason string) error {
+	if sid == "" {
+		return nil
+	}
+	srv.sessions.mu.RLock()
+	s, ok := srv.sessions.sessions[sid]
+	srv.sessions.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	if !s.mu.TryLock() {
+		srv.logger.Printf("session %s is still locked after the panic and was not marked corrupted", sid)
+		return nil
+	}
+	defer s.mu.Unlock()
+	s.Engine.MarkCorrupted(reason)
+	return s.Engine.Corrupted
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	v1 "adventure-engine/api/v1"
+)
+
+// webhookQueueSize is how many notifications a session can have waiting for delivery
+// Notifications beyond it are dropped rather than blocking game actions.
+const webhookQueueSize = 64
+
+// webhookAttempts is how many times delivery of a notification is tried
+const webhookAttempts = 3
+
+// webhookTimeout bounds each attempt to post a notification
+const webhookTimeout = 5 * time.Second
+
+// webhook delivers a session's state change notifications to its callback URL
+// Notifications are posted one at a time, in the order they happened.
+type webhook struct {
+	url    string
+	queue  chan v1.WebhookNotification
+	client *http.Client
+	logger *log.Logger
+}
+
+func newWebhook(url string, client *http.Client, logger *log.Logger) *webhook {
+	w := &webhook{
+		url:    url,
+		queue:  make(chan v1.WebhookNotification, webhookQueueSize),
+		client: client,
+		logger: logger,
+	}
+	go w.run()
+	return w
+}
+
+// notify queues a notification for delivery without waiting for it to be sent
+func (w *webhook) notify(notification v1.WebhookNotification) {
+	select {
+	case w.queue <- notification:
+	default:
+		w.logger.Printf("webhook queue for session %s is full, dropping %s notification", notification.SessionID, notification.Notification)
+	}
+}
+
+// close stops delivery once the queued notifications have been sent
+func (w *webhook) close() {
+	close(w.queue)
+}
+
+func (w *webhook) run() {
+	for notification := range w.queue {
+		body, err := json.Marshal(notification)
+		if err != nil {
+			w.logger.Printf("failed to marshal webhook notification for session %s: %v", notification.SessionID, err)
+			continue
+		}
+		for attempt := 1; attempt <= webhookAttempts; attempt++ {
+			err = w.post(body)
+			if err == nil {
+				break
+			}
+			if attempt < webhookAttempts {
+				time.Sleep(time.Duration(attempt) * time.Second)
+			}
+		}
+		if err != nil {
+			w.logger.Printf("failed to deliver webhook notification for session %s to %s: %v", notification.SessionID, w.url, err)
+		}
+	}
+}
+
+func (w *webhook) post(body []byte) error {
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("callback responded %s", resp.Status)
+	}
+	return nil
+}
+
+// notifyStateChange sends the session's webhook a notification for each way an action changed the
+// engine state, such as entering combat or completing the level, and puts completed runs on the
+// leaderboard. Must be called with the session locked.
+func (srv *Server) notifyStateChange(s *GameSession, state v1.EngineStateInfo) {
+	srv.recordRun(s, state)
+	if s.webhook == nil {
+		return
+	}
+	sentAt := srv.now().Format(time.RFC3339)
+	for _, notification := range state.Notifications {
+		s.webhook.notify(v1.WebhookNotification{
+			SessionID:    s.ID,
+			Notification: notification,
+			SentAt:       sentAt,
+			EngineState:  state,
+		})
+	}
+}
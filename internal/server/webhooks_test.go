@@ -0,0 +1,121 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	v1 "adventure-engine/api/v1"
+	"adventure-engine/pkg/engine"
+)
+
+// webhookReceiver starts a callback server and returns its URL and the notifications it receives.
+func webhookReceiver(t *testing.T) (string, <-chan v1.WebhookNotification) {
+	t.Helper()
+	notifications := make(chan v1.WebhookNotification, webhookQueueSize)
+	callback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification v1.WebhookNotification
+		if err := json.NewDecoder(r.Body).Decode(&notification); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		notifications <- notification
+	}))
+	t.Cleanup(callback.Close)
+	return callback.URL, notifications
+}
+
+// createWebhookSession starts a session on a test level that notifies callbackURL.
+// Returns the session's API path.
+func createWebhookSession(t *testing.T, srv *Server, levelFile string, callbackURL string) string {
+	t.Helper()
+	data, err := os.ReadFile("../testdata/" + levelFile)
+	if err != nil {
+		t.Fatalf("Failed to read test level: %v", err)
+	}
+	w := serve(t, srv, http.MethodPost, "/api/v1/sessions", "", v1.CreateSessionRequest{Level: data, CallbackURL: callbackURL})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Failed to create session: %d %s", w.Code, w.Body.String())
+	}
+	var resp v1.CreateSessionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return "/api/v1/sessions/" + resp.SessionID
+}
+
+// expectNotification waits for the webhook to be sent a notification, skipping the ones before it.
+func expectNotification(t *testing.T, notifications <-chan v1.WebhookNotification, expected engine.EngineStateChangeNotification) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case notification := <-notifications:
+			if notification.Notification == string(expected) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("Expected a %s notification", expected)
+		}
+	}
+}
+
+func TestWebhook_DrowningOnInspect(t *testing.T) {
+	url, notifications := webhookReceiver(t)
+	srv := NewServer(Config{})
+	path := createWebhookSession(t, srv, "flooded.json", url)
+
+	if w := serve(t, srv, http.MethodPost, path+"/traverse", "", v1.TraverseRequest{Destination: "down"}); w.Code != http.StatusOK {
+		t.Fatalf("Traverse failed: %d %s", w.Code, w.Body.String())
+	}
+	// Inspecting takes a turn underwater, and the second one drowns the player
+	for range 2 {
+		if w := serve(t, srv, http.MethodPost, path+"/inspect", "", v1.InspectRequest{TargetName: "pebble"}); w.Code != http.StatusOK {
+			t.Fatalf("Inspect failed: %d %s", w.Code, w.Body.String())
+		}
+	}
+	expectNotification(t, notifications, engine.EngineStateChangeLevelFailed)
+}
+
+func TestWebhook_JammedLockStartsCombat(t *testing.T) {
+	url, notifications := webhookReceiver(t)
+	srv := NewServer(Config{})
+	path := createWebhookSession(t, srv, "jamming.json", url)
+
+	if w := serve(t, srv, http.MethodPost, path+"/unlock", "", v1.UnlockRequest{KeyOrCode: "1111", TargetName: "vault door"}); w.Code == http.StatusOK {
+		t.Fatalf("Expected the wrong code to fail, got %s", w.Body.String())
+	}
+	// The last wrong code jams the keypad, which sets the guard on the player
+	if w := serve(t, srv, http.MethodPost, path+"/unlock", "", v1.UnlockRequest{KeyOrCode: "2222", TargetName: "vault door"}); w.Code != http.StatusOK {
+		t.Fatalf("Unlock failed: %d %s", w.Code, w.Body.String())
+	}
+	expectNotification(t, notifications, engine.EngineStateChangeEnterCombat)
+}
+
+func TestWebhook_PoisonDeathOnHeal(t *testing.T) {
+	url, notifications := webhookReceiver(t)
+	srv := NewServer(Config{})
+	path := createWebhookSession(t, srv, "poisoned_diver.json", url)
+
+	steps := []struct {
+		action string
+		body   any
+	}{
+		{"/take", v1.TakeRequest{TargetName: "air tank"}},
+		{"/traverse", v1.TraverseRequest{Destination: "down"}},
+		// The jewel box leaves the player critically hurt and poisoned
+		{"/take", v1.TakeRequest{TargetName: "jewel box"}},
+		{"/inspect", v1.InspectRequest{TargetName: "jewel box"}},
+		// Breathing from the tank doesn't cure the poison, which finishes the player off
+		{"/heal", v1.HealRequest{HealthItemName: "air tank"}},
+	}
+	for _, step := range steps {
+		if w := serve(t, srv, http.MethodPost, path+step.action, "", step.body); w.Code != http.StatusOK {
+			t.Fatalf("%s failed: %d %s", step.action, w.Code, w.Body.String())
+		}
+	}
+	expectNotification(t, notifications, engine.EngineStateChangeLevelFailed)
+}
@@ -0,0 +1,276 @@
+package sim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+
+	"adventure-engine/internal/parser"
+	"adventure-engine/pkg/engine"
+	"adventure-engine/pkg/world"
+)
+
+// codePattern matches numbers that might open code locks, such as a code written on a note
+var codePattern = regexp.MustCompile(`\b\d{3,8}\b`)
+
+// memory is what a bot remembers while playing an episode
+type memory struct {
+	codes    []string               // numbers seen in action results, in the order they were seen
+	failed   map[parser.Action]bool // actions that failed since the last progress
+	done     map[parser.Action]bool // actions that are not worth repeating once they succeeded
+	doorUses map[string]int         // times each door was traversed
+}
+
+func newMemory() *memory {
+	return &memory{
+		failed:   make(map[parser.Action]bool),
+		done:     make(map[parser.Action]bool),
+		doorUses: make(map[string]int),
+	}
+}
+
+// record remembers how an action went.
+// Progress, such as taking an item or unlocking a door, clears the failed actions,
+// as they may succeed now.
+func (m *memory) record(action *parser.Action, response any, err error) {
+	if err != nil {
+		m.failed[*action] = true
+		return
+	}
+	switch action.Verb {
+	case parser.VerbTraverse:
+		m.doorUses[action.Target]++
+	case parser.VerbTake, parser.VerbUnlock, parser.VerbUncover, parser.VerbMove, parser.VerbSearch, parser.VerbUse, parser.VerbCombine:
+		m.done[*action] = true
+		clear(m.failed)
+	case parser.VerbInspect:
+		m.done[*action] = true
+	}
+	m.rememberCodes(response)
+}
+
+// rememberCodes remembers the numbers in an action's response, leaving out the engine state
+func (m *memory) rememberCodes(response any) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return
+	}
+	delete(fields, "engine_state")
+	for _, field := range fields {
+		for _, code := range codePattern.FindAllString(string(field), -1) {
+			if !slices.Contains(m.codes, code) {
+				m.codes = append(m.codes, code)
+			}
+		}
+	}
+}
+
+// candidate is an action a bot could take, ranked by how promising it is (lower is better)
+type candidate struct {
+	action parser.Action
+	rank   int
+}
+
+// Candidate ranks, used by the greedy policy
+const (
+	rankHeal = iota
+	rankBattle
+	rankUnlock
+	rankTake
+	rankExplore // search, uncover and move furniture
+	rankUse
+	rankCombine
+	rankInspect
+	rankTraverse
+)
+
+// candidates lists the actions worth trying in the current state, leaving out the ones
+// that failed or are done
+func (m *memory) candidates(e *engine.Engine) ([]candidate, error) {
+	inventory, err := e.Inventory(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	items := inventory.Result.Items
+
+	var candidates []candidate
+	add := func(rank int, verb parser.Verb, target, item string) {
+		action := parser.Action{Verb: verb, Target: target, Item: item}
+		if !m.failed[action] && !m.done[action] {
+			candidates = append(candidates, candidate{action: action, rank: rank})
+		}
+	}
+
+	if e.Player.Health != world.HealthFine {
+		for _, item := range items {
+			if item.IsHealthItem {
+				add(rankHeal, parser.VerbHeal, "", item.Name)
+			}
+		}
+	}
+
+	if e.Mode == engine.Combat {
+		for _, item := range items {
+			if item.IsWeapon {
+				add(rankBattle, parser.VerbBattle, "", item.Name)
+			}
+		}
+		add(rankBattle+1, parser.VerbBattle, "", "")
+		return candidates, nil
+	}
+
+	observation, err := e.Observe(context.Background(), engine.ObserveFilter{})
+	if err != nil {
+		return nil, err
+	}
+	addUnlocks := func(target string, hasKeyLock, hasCodeLock bool) {
+		if hasKeyLock {
+			for _, item := range items {
+				if item.IsKey {
+					add(rankUnlock, parser.VerbUnlock, target, item.Name)
+				}
+			}
+		}
+		if hasCodeLock {
+			for _, code := range m.codes {
+				add(rankUnlock, parser.VerbUnlock, target, code)
+			}
+		}
+	}
+
+	for _, item := range observation.Result.VisibleItems {
+		switch {
+		case item.IsContainer && item.IsLocked:
+			addUnlocks(item.Name, item.HasKeyLock, item.HasCodeLock)
+		case item.IsContainer && !item.IsSearched:
+			add(rankExplore, parser.VerbSearch, item.Name, "")
+		case item.IsContainer && item.Contains != "":
+			add(rankTake, parser.VerbTake, item.Contains, "")
+		}
+		if item.IsConcealer && !item.IsUncovered {
+			add(rankExplore, parser.VerbUncover, item.Name, "")
+		}
+		if item.IsMoveable && !item.IsMoved {
+			add(rankExplore, parser.VerbMove, item.Name, "")
+		}
+		if item.IsPortable && !item.IsConcealer {
+			add(rankTake, parser.VerbTake, item.Name, "")
+		}
+		if item.IsFixture {
+			for _, held := range items {
+				add(rankUse, parser.VerbUse, item.Name, held.Name)
+			}
+		}
+		add(rankInspect, parser.VerbInspect, item.Name, "")
+	}
+
+	for i, a := range items {
+		add(rankInspect, parser.VerbInspect, a.Name, "")
+		for _, b := range items[i+1:] {
+			add(rankCombine, parser.VerbCombine, b.Name, a.Name)
+		}
+	}
+
+	for _, door := range observation.Result.Doors {
+		if door.IsLocked {
+			addUnlocks(door.Name, door.HasKeyLock, door.HasCodeLock)
+			continue
+		}
+		// Doors used less often rank higher, so the bot explores before it backtracks
+		add(rankTraverse+m.doorUses[door.Name], parser.VerbTraverse, door.Name, "")
+	}
+	return candidates, nil
+}
+
+// randomPolicy takes a random action among the ones worth trying
+type randomPolicy struct {
+	memory *memory
+	rand   *rand.Rand
+}
+
+// NewRandomPolicy returns a policy that plays like someone trying things at random
+func NewRandomPolicy(seed uint64) Policy {
+	return &randomPolicy{memory: newMemory(), rand: rand.New(rand.NewPCG(seed, seed))}
+}
+
+func (p *randomPolicy) Next(e *engine.Engine) (*parser.Action, error) {
+	candidates, err := p.memory.candidates(e)
+	if err != nil || len(candidates) == 0 {
+		return nil, err
+	}
+	action := candidates[p.rand.IntN(len(candidates))].action
+	return &action, nil
+}
+
+func (p *randomPolicy) Result(action *parser.Action, response any, err error) {
+	p.memory.record(action, response, err)
+}
+
+// greedyPolicy takes the most promising action, seeking out keys and codes:
+// it picks up everything, opens every lock it can and only moves on when the room is exhausted
+type greedyPolicy struct {
+	memory *memory
+	rand   *rand.Rand // breaks ties between equally promising actions
+}
+
+// NewGreedyPolicy returns a policy that plays like a methodical key-seeker
+func NewGreedyPolicy(seed uint64) Policy {
+	return &greedyPolicy{memory: newMemory(), rand: rand.New(rand.NewPCG(seed, seed))}
+}
+
+func (p *greedyPolicy) Next(e *engine.Engine) (*parser.Action, error) {
+	candidates, err := p.memory.candidates(e)
+	if err != nil || len(candidates) == 0 {
+		return nil, err
+	}
+	best := candidates[0].rank
+	for _, c := range candidates {
+		best = min(best, c.rank)
+	}
+	var top []parser.Action
+	for _, c := range candidates {
+		if c.rank == best {
+			top = append(top, c.action)
+		}
+	}
+	action := top[p.rand.IntN(len(top))]
+	return &action, nil
+}
+
+func (p *greedyPolicy) Result(action *parser.Action, response any, err error) {
+	p.memory.record(action, response, err)
+}
+
+// scriptedPolicy plays a fixed list of commands, such as a level's intended walkthrough
+type scriptedPolicy struct {
+	commands []string
+	next     int
+}
+
+// NewScriptedPolicy returns a policy that plays commands in order, then stops.
+// Commands are free text, as typed by a player.
+func NewScriptedPolicy(commands []string) Policy {
+	return &scriptedPolicy{commands: commands}
+}
+
+func (p *scriptedPolicy) Next(e *engine.Engine) (*parser.Action, error) {
+	if p.next >= len(p.commands) {
+		return nil, nil
+	}
+	command := p.commands[p.next]
+	p.next++
+	action, err := parser.Parse(command, e.ReferableNames())
+	if err != nil {
+		return nil, fmt.Errorf("command %d %q: %w", p.next, command, err)
+	}
+	return action, nil
+}
+
+func (p *scriptedPolicy) Result(action *parser.Action, response any, err error) {}
@@ -0,0 +1,180 @@
+// Package sim plays levels headlessly with bot policies, to balance-test levels at scale.
+//
+// Each episode plays a fresh copy of a level until the policy solves it, dies, runs out of
+// things to try or reaches the action limit. The outcomes of all episodes are summarised
+// in a Report.
+package sim
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "adventure-engine/api/v1"
+	"adventure-engine/internal/parser"
+	"adventure-engine/pkg/engine"
+	"adventure-engine/pkg/world"
+)
+
+// Policy chooses the actions of a simulated player.
+// A policy plays a single episode; a new one is created for every episode.
+type Policy interface {
+	// Next returns the next action to take, or nil to stop playing.
+	Next(e *engine.Engine) (*parser.Action, error)
+	// Result tells the policy how the action it chose went: the action endpoint's response
+	// if it succeeded, or the engine's error.
+	Result(action *parser.Action, response any, err error)
+}
+
+// Outcome is how an episode ended.
+type Outcome string
+
+const (
+	OutcomeSolved   Outcome = "solved"
+	OutcomeDied     Outcome = "died"
+	OutcomeStuck    Outcome = "stuck"     // the policy had nothing left to try
+	OutcomeTimedOut Outcome = "timed_out" // the action limit was reached
+)
+
+// Config controls a simulation run.
+type Config struct {
+	Episodes   int
+	MaxActions int    // actions per episode, including failed ones
+	Seed       uint64 // episode i is played with seed Seed+i
+}
+
+// Episode is the result of playing a level once.
+type Episode struct {
+	Seed       uint64  `json:"seed"`
+	Outcome    Outcome `json:"outcome"`
+	Turns      int     `json:"turns"`
+	Actions    int     `json:"actions"`
+	DeathCause string  `json:"death_cause,omitempty"` // set when the player died
+}
+
+// Report summarises the episodes of a simulation run.
+type Report struct {
+	Episodes     int             `json:"episodes"`
+	Outcomes     map[Outcome]int `json:"outcomes"`
+	SolveRate    float64         `json:"solve_rate"`
+	AverageTurns float64         `json:"average_turns"` // over solved episodes
+	DeathCauses  map[string]int  `json:"death_causes"`  // cause -> deaths
+}
+
+// LevelFunc returns the level to play with a seed, which seeds loot placement or generation.
+type LevelFunc func(seed uint64) (*world.Level, error)
+
+// PolicyFunc returns a new policy seeded for an episode.
+type PolicyFunc func(seed uint64) (Policy, error)
+
+// Run plays config.Episodes episodes and reports their outcomes.
+func Run(newLevel LevelFunc, newPolicy PolicyFunc, config Config) (*Report, []Episode, error) {
+	if config.Episodes < 1 {
+		return nil, nil, fmt.Errorf("episodes must be at least 1")
+	}
+	if config.MaxActions < 1 {
+		return nil, nil, fmt.Errorf("max actions must be at least 1")
+	}
+
+	episodes := make([]Episode, 0, config.Episodes)
+	for i := 0; i < config.Episodes; i++ {
+		seed := config.Seed + uint64(i)
+		level, err := newLevel(seed)
+		if err != nil {
+			return nil, nil, fmt.Errorf("episode %d: %w", i+1, err)
+		}
+		policy, err := newPolicy(seed)
+		if err != nil {
+			return nil, nil, fmt.Errorf("episode %d: %w", i+1, err)
+		}
+		episode, err := Play(level, policy, seed, config.MaxActions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("episode %d: %w", i+1, err)
+		}
+		episodes = append(episodes, *episode)
+	}
+	return Summarize(episodes), episodes, nil
+}
+
+// Play plays a single episode of a level.
+// An error is returned only if the policy fails, not when its actions do.
+func Play(level *world.Level, policy Policy, seed uint64, maxActions int) (*Episode, error) {
+	e := engine.NewEngine(level)
+	e.Rng = engine.NewSeededRng(seed)
+	episode := &Episode{Seed: seed, Outcome: OutcomeTimedOut}
+
+	for episode.Actions < maxActions {
+		action, err := policy.Next(e)
+		if err != nil {
+			return nil, err
+		}
+		if action == nil {
+			episode.Outcome = OutcomeStuck
+			break
+		}
+		var enemy string
+		if e.FightingEnemy != nil {
+			enemy = e.FightingEnemy.Name
+		}
+
+		response, _, err := v1.RunAction(context.Background(), e, action, nil)
+		episode.Actions++
+		policy.Result(action, response, err)
+
+		if e.LevelCompletionState == engine.LevelCompletionStateComplete {
+			episode.Outcome = OutcomeSolved
+			break
+		}
+		if e.LevelCompletionState == engine.LevelCompletionStateFailed {
+			episode.Outcome = OutcomeDied
+			episode.DeathCause = "unknown"
+			if enemy != "" {
+				episode.DeathCause = "killed by " + enemy
+			}
+			break
+		}
+	}
+	episode.Turns = e.Stats.Turns
+	return episode, nil
+}
+
+// Summarize reports the outcomes of episodes.
+func Summarize(episodes []Episode) *Report {
+	report := &Report{
+		Episodes:    len(episodes),
+		Outcomes:    make(map[Outcome]int),
+		DeathCauses: make(map[string]int),
+	}
+	solvedTurns := 0
+	for _, episode := range episodes {
+		report.Outcomes[episode.Outcome]++
+		if episode.Outcome == OutcomeSolved {
+			solvedTurns += episode.Turns
+		}
+		if episode.DeathCause != "" {
+			report.DeathCauses[episode.DeathCause]++
+		}
+	}
+	if solved := report.Outcomes[OutcomeSolved]; solved > 0 {
+		report.AverageTurns = float64(solvedTurns) / float64(solved)
+	}
+	if report.Episodes > 0 {
+		report.SolveRate = float64(report.Outcomes[OutcomeSolved]) / float64(report.Episodes)
+	}
+	return report
+}
+
+// SortedDeathCauses returns the death causes, most frequent first.
+func (r *Report) SortedDeathCauses() []string {
+	causes := make([]string, 0, len(r.DeathCauses))
+	for cause := range r.DeathCauses {
+		causes = append(causes, cause)
+	}
+	sort.Slice(causes, func(i, j int) bool {
+		if r.DeathCauses[causes[i]] != r.DeathCauses[causes[j]] {
+			return r.DeathCauses[causes[i]] > r.DeathCauses[causes[j]]
+		}
+		return causes[i] < causes[j]
+	})
+	return causes
+}
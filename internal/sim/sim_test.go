@@ -0,0 +1,119 @@
+package sim
+
+import (
+	"testing"
+
+	"adventure-engine/pkg/loader"
+	"adventure-engine/pkg/world"
+)
+
+// demoWalkthrough solves the demo level
+var demoWalkthrough = []string{
+	"uncover the hoodie",
+	"take the energy drink",
+	"go ahead",
+	"search the desk",
+	"take the pistol",
+	"search the cardboard box",
+	"take the pistol ammo",
+	"go back",
+	"go left",
+	"uncover the tarp",
+	"unlock the safe with 2468",
+	"search the safe",
+	"take the iron key",
+	"attack with the pistol",
+	"attack with the pistol",
+	"attack with the pistol",
+	"go back",
+	"unlock the metal stairwell door with the iron key",
+	"go right",
+}
+
+func levelFromFile(t *testing.T, filename string) LevelFunc {
+	t.Helper()
+	data, err := loader.ReadLevelFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", filename, err)
+	}
+	return func(seed uint64) (*world.Level, error) {
+		return loader.LoadGameWithSeed(data, seed)
+	}
+}
+
+func TestRun_ScriptedWalkthrough(t *testing.T) {
+	newPolicy := func(seed uint64) (Policy, error) { return NewScriptedPolicy(demoWalkthrough), nil }
+	report, episodes, err := Run(levelFromFile(t, "../testdata/demo.json"), newPolicy, Config{Episodes: 5, MaxActions: 100, Seed: 1})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(episodes) != 5 {
+		t.Fatalf("Expected 5 episodes, got %d", len(episodes))
+	}
+	if report.SolveRate != 1 {
+		t.Errorf("Expected the walkthrough to always solve the demo, got outcomes %v", report.Outcomes)
+	}
+	if report.AverageTurns < float64(len(demoWalkthrough)-3) {
+		t.Errorf("Expected about %d turns, got %.1f", len(demoWalkthrough), report.AverageTurns)
+	}
+	if episodes[0].Seed != 1 || episodes[4].Seed != 5 {
+		t.Errorf("Expected episodes seeded 1 to 5, got %d to %d", episodes[0].Seed, episodes[4].Seed)
+	}
+}
+
+func TestRun_ScriptedInvalidCommand(t *testing.T) {
+	newPolicy := func(seed uint64) (Policy, error) { return NewScriptedPolicy([]string{"dance wildly"}), nil }
+	if _, _, err := Run(levelFromFile(t, "../testdata/demo.json"), newPolicy, Config{Episodes: 1, MaxActions: 10}); err == nil {
+		t.Error("Expected an error for a command that does not parse")
+	}
+}
+
+func TestRun_GreedySolvesFixtureLevel(t *testing.T) {
+	newPolicy := func(seed uint64) (Policy, error) { return NewGreedyPolicy(seed), nil }
+	report, _, err := Run(levelFromFile(t, "../testdata/fixture.json"), newPolicy, Config{Episodes: 10, MaxActions: 200, Seed: 1})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.SolveRate != 1 {
+		t.Errorf("Expected the greedy policy to solve the fixture level, got outcomes %v", report.Outcomes)
+	}
+}
+
+func TestRun_RandomIsDeterministic(t *testing.T) {
+	newPolicy := func(seed uint64) (Policy, error) { return NewRandomPolicy(seed), nil }
+	config := Config{Episodes: 10, MaxActions: 200, Seed: 7}
+	_, first, err := Run(levelFromFile(t, "../testdata/enter_room_win.json"), newPolicy, config)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	_, second, err := Run(levelFromFile(t, "../testdata/enter_room_win.json"), newPolicy, config)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("Expected episode %d to replay the same, got %+v and %+v", i+1, first[i], second[i])
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	report := Summarize([]Episode{
+		{Outcome: OutcomeSolved, Turns: 10},
+		{Outcome: OutcomeSolved, Turns: 20},
+		{Outcome: OutcomeDied, Turns: 5, DeathCause: "killed by zombie"},
+		{Outcome: OutcomeDied, Turns: 7, DeathCause: "killed by hound"},
+		{Outcome: OutcomeDied, Turns: 3, DeathCause: "killed by zombie"},
+		{Outcome: OutcomeTimedOut, Turns: 50},
+	})
+	if report.SolveRate != 2.0/6 {
+		t.Errorf("Expected solve rate 1/3, got %v", report.SolveRate)
+	}
+	if report.AverageTurns != 15 {
+		t.Errorf("Expected 15 average turns over solved episodes, got %v", report.AverageTurns)
+	}
+	causes := report.SortedDeathCauses()
+	if len(causes) != 2 || causes[0] != "killed by zombie" || report.DeathCauses["killed by zombie"] != 2 {
+		t.Errorf("Expected zombie deaths first, got %v %v", causes, report.DeathCauses)
+	}
+}
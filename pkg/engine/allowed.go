@@ -0,0 +1,103 @@
+package engine
+
+import "adventure-engine/pkg/world"
+
+// Actions that can be taken in each mode, named as in Action.
+// Looking around is allowed whenever the level is still being played, and whoever's turn it is.
+var (
+	lookActions          = []string{"observe", "inventory", "status", "context", "minimap"}
+	investigationActions = []string{"inspect", "uncover", "unlock", "search", "interrogate", "take", "traverse", "combine", "use", "latch", "listen", "peek", "move", "travel", "wait", "rest"}
+	combatActions        = []string{"battle", "intimidate", "bribe", "sneak"}
+)
+
+// fistsWeaponName is the weapon a player without one fights with.
+const fistsWeaponName = "fists"
+
+// WeaponInfo is a weapon the player can fight with.
+type WeaponInfo struct {
+	Name     string
+	UsesAmmo bool
+	Ammo     int // rounds left of its type, for weapons that use ammo
+	Thrown   int // how many the player has left to throw, for thrown weapons
+}
+
+// allowedActions returns the actions the active player can take, in the order of the lists
+// above. Actions that need something the player doesn't have are left out: healing without
+// anything to heal with or at full health, travelling away from a travel point, questioning
+// with no enemy that surrendered around, and getting past an enemy in a way it can't be got
+// past, or with no bribe it wants, and resting where it isn't safe. Plugins may still refuse an
+// action that is allowed.
+func (e *Engine) allowedActions() []string {
+	if e.checkLevelComplete() != nil {
+		return nil
+	}
+	allowed := append([]string(nil), lookActions...)
+	if e.checkTurn() != nil {
+		return allowed
+	}
+	if e.canHeal() {
+		allowed = append(allowed, "heal")
+	}
+	switch e.Mode {
+	case Investigation:
+		for _, action := range investigationActions {
+			switch {
+			case action == "travel" && e.CurrentRoom.TravelNode == nil:
+			case action == "interrogate" && e.surrenderedEnemies() == nil:
+			case action == "rest" && !e.canRest():
+			default:
+				allowed = append(allowed, action)
+			}
+		}
+	case Combat:
+		enemy := e.FightingEnemy
+		for _, action := range combatActions {
+			switch {
+			case action == approachIntimidate && enemy.Intimidate == 0:
+			case action == approachSneak && enemy.Sneak == 0:
+			case action == approachBribe && !e.canBribe():
+			default:
+				allowed = append(allowed, action)
+			}
+		}
+	}
+	return allowed
+}
+
+// canHeal returns true if the player has a health item and is hurt or poisoned, or has an air
+// supply.
+func (e *Engine) canHeal() bool {
+	for _, item := range e.Player.Inventory {
+		if item.IsHealthItem() && (e.Player.Health != world.HealthFine || e.Player.PoisonedBy != "") || item.IsAirSupply() {
+			return true
+		}
+	}
+	return false
+}
+
+// usableWeapons returns the weapons the player can fight with in combat, starting with their
+// fists: the weapons in their inventory that are not broken and, if they use ammo, are loaded.
+// Returns nil outside combat.
+func (e *Engine) usableWeapons() []WeaponInfo {
+	if e.Mode != Combat || e.FightingEnemy == nil {
+		return nil
+	}
+	weapons := []WeaponInfo{{Name: fistsWeaponName}}
+	for _, item := range e.Player.Inventory {
+		if !item.IsWeapon() || item.IsBroken() {
+			continue
+		}
+		weapon := WeaponInfo{Name: item.Name, UsesAmmo: item.Weapon.UsesAmmo()}
+		if item.Weapon.IsThrown() {
+			weapon.Thrown = item.Count()
+		}
+		if weapon.UsesAmmo {
+			weapon.Ammo = e.Player.Ammo[item.AmmoType()]
+			if weapon.Ammo == 0 {
+				continue
+			}
+		}
+		weapons = append(weapons, weapon)
+	}
+	return weapons
+}
@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAllowedActions(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "durability.json"))
+
+	state := engine.getEngineStateInfo()
+	expected := []string{"observe", "inventory", "status", "context", "minimap", "inspect", "uncover", "unlock", "search", "take", "traverse", "combine", "use", "latch", "listen", "peek", "move", "wait", "rest"}
+	if !slices.Equal(state.AllowedActions, expected) || state.Weapons != nil {
+		t.Errorf("Expected investigation actions without travel or healing, got %v and weapons %v", state.AllowedActions, state.Weapons)
+	}
+
+	// Taking the knife sets the rat on the player
+	take, err := engine.Take(ctx, "knife")
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	expected = []string{"observe", "inventory", "status", "context", "minimap", "battle"}
+	if !slices.Equal(take.EngineStateInfo.AllowedActions, expected) {
+		t.Errorf("Expected to only be able to fight, got %v", take.EngineStateInfo.AllowedActions)
+	}
+	weapons := []WeaponInfo{{Name: "fists"}, {Name: "knife"}}
+	if !slices.Equal(take.EngineStateInfo.Weapons, weapons) {
+		t.Errorf("Expected to fight with fists or the knife, got %v", take.EngineStateInfo.Weapons)
+	}
+
+	engine.LevelCompletionState = LevelCompletionStateComplete
+	if state := engine.getEngineStateInfo(); state.AllowedActions != nil {
+		t.Errorf("Expected nothing to be allowed once the level is over, got %v", state.AllowedActions)
+	}
+}
+
+func TestAllowedActions_NotYourTurn(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "durability.json"))
+	engine.TurnPolicy = TurnsRoundRobin
+	if _, err := engine.AddPlayer("bob"); err != nil {
+		t.Fatalf("AddPlayer failed: %v", err)
+	}
+	if err := engine.SwitchPlayer("bob"); err != nil {
+		t.Fatalf("SwitchPlayer failed: %v", err)
+	}
+
+	if state := engine.getEngineStateInfo(); !slices.Equal(state.AllowedActions, lookActions) {
+		t.Errorf("Expected bob to only look around out of turn, got %v", state.AllowedActions)
+	}
+}
@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSharedAmmo(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "shared_ammo.json"))
+
+	for _, name := range []string{"pistol", "smg", "rounds", "flare gun"} {
+		if _, err := engine.Take(ctx, name); err != nil {
+			t.Fatalf("Take %s failed: %v", name, err)
+		}
+	}
+	inventory, err := engine.Inventory(ctx)
+	if err != nil {
+		t.Fatalf("Inventory failed: %v", err)
+	}
+	expected := []AmmoCount{{AmmoType: "9mm", AmmoCount: 6}, {AmmoType: "flare gun", AmmoCount: 1}}
+	if !slices.Equal(inventory.Result.Ammo, expected) {
+		t.Fatalf("Expected 6 shared 9mm rounds and the flare gun's own, got %v", inventory.Result.Ammo)
+	}
+
+	// Both 9mm weapons fire from the same rounds
+	smg, _ := engine.Player.GetItem("smg")
+	if err := engine.Player.FireWeapon(smg.AmmoType()); err != nil {
+		t.Fatalf("FireWeapon failed: %v", err)
+	}
+	if engine.Player.Ammo["9mm"] != 5 {
+		t.Errorf("Expected 5 9mm rounds left, got %d", engine.Player.Ammo["9mm"])
+	}
+}
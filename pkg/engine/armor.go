@@ -0,0 +1,54 @@
+package engine
+
+import "adventure-engine/pkg/world"
+
+// ArmorInfo is a piece of armor the player wears.
+type ArmorInfo struct {
+	Name       string
+	Slot       string
+	Block      float64 // chance of stopping a hit
+	Durability int     // hits it can still absorb, 0 for armor that never breaks
+}
+
+// wornArmor returns the armor the active player wears, or nil if they wear none.
+func (e *Engine) wornArmor() []ArmorInfo {
+	var armor []ArmorInfo
+	for _, item := range e.Player.WornArmor() {
+		info := ArmorInfo{Name: item.Name, Slot: item.Armor.Slot, Block: item.Armor.Block}
+		if item.Durability != nil {
+			info.Durability = item.Durability.Remaining()
+		}
+		armor = append(armor, info)
+	}
+	return armor
+}
+
+// enemyStrikes has the enemy the player is fighting hit them, unless their armor stops it.
+// Returns the armor that stopped the hit, if any, and the armor it broke with the scrap left.
+func (e *Engine) enemyStrikes() (string, string, *ItemInfo) {
+	armor, broke, scrap := e.absorbHit()
+	switch {
+	case armor == nil:
+		e.Player.InflictDamage()
+		e.Stats.DamageTaken++
+		return "", "", nil
+	case broke:
+		return armor.Name, armor.Name, scrap
+	}
+	return armor.Name, "", nil
+}
+
+// absorbHit gives each piece of armor the player wears, in turn, a chance to stop a hit meant
+// for them. The piece that stops it wears down, and may break.
+// Returns the piece that took the hit, or nil if it got through, and whether that broke it
+// with the scrap it left.
+func (e *Engine) absorbHit() (*world.Item, bool, *ItemInfo) {
+	for _, armor := range e.Player.WornArmor() {
+		if !e.chance("the "+armor.Name+" blocks the hit", armor.Armor.Block) {
+			continue
+		}
+		broke, scrap := e.wearItem(armor)
+		return armor, broke, scrap
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"slices"
+	"testing"
+)
+
+func TestBattle_Armor(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "armor.json"))
+	// Every round is lost, and only armor that always blocks stops the rat
+	engine.Rng = &FakeRng{Value: 0.7}
+
+	for _, name := range []string{"vest", "helmet", "cap"} {
+		if _, err := engine.Take(ctx, name); err != nil {
+			t.Fatalf("Take %s failed: %v", name, err)
+		}
+	}
+	armor := []ArmorInfo{{Name: "vest", Slot: "body", Block: 0.5}, {Name: "helmet", Slot: "head", Block: 1, Durability: 2}}
+	if state := engine.getEngineStateInfo(); !slices.Equal(state.Armor, armor) {
+		t.Fatalf("Expected the vest and the first helmet taken to be worn, got %v", state.Armor)
+	}
+
+	for round := 1; round <= 2; round++ {
+		battle, err := engine.Battle(ctx, "")
+		if err != nil {
+			t.Fatalf("Battle failed: %v", err)
+		}
+		if battle.Result.Absorbed != "helmet" || battle.Result.PlayerAlive != true {
+			t.Fatalf("Expected the helmet to stop the rat in round %d, got %+v", round, battle.Result)
+		}
+		if broke := battle.Result.BrokenArmor == "helmet"; broke != (round == 2) {
+			t.Errorf("Expected the helmet to break in round 2, got %+v in round %d", battle.Result, round)
+		}
+	}
+
+	// The cap goes on in the broken helmet's place
+	battle, err := engine.Battle(ctx, "")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if battle.Result.Absorbed != "cap" || engine.Player.Health != world.HealthFine || engine.Stats.DamageTaken != 0 {
+		t.Errorf("Expected the cap to stop the rat, got %+v and health %s", battle.Result, engine.Player.Health)
+	}
+	inventory, err := engine.inventoryInternal()
+	if err != nil {
+		t.Fatalf("Inventory failed: %v", err)
+	}
+	for _, item := range inventory.Items {
+		if item.IsWorn != (item.Name != "helmet") || item.ArmorSlot == "" {
+			t.Errorf("Expected only the broken helmet to be off, got %+v", item)
+		}
+	}
+
+	// Without armor that always blocks, hits get through
+	engine.Player.RemoveItem("cap")
+	if battle, err := engine.Battle(ctx, ""); err != nil || battle.Result.Absorbed != "" || engine.Player.Health != world.HealthHurt {
+		t.Errorf("Expected the rat to hurt the player through the vest, got %+v and %v", battle, err)
+	}
+}
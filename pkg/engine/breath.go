@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+)
+
+// BreathInfo tells how much longer the player can hold their breath in an airless room.
+type BreathInfo struct {
+	Left     int // actions the player can still end in airless rooms before they drown
+	Capacity int // actions a full breath lasts
+}
+
+// breathe updates the active player's breath at the end of a turn. A turn ended in a room
+// with air refills it; one ended in an airless room uses it up, and once it runs out the
+// player drowns.
+func (e *Engine) breathe() {
+	if !e.CurrentRoom.Airless {
+		e.Player.BreathHeld = 0
+		return
+	}
+	if !e.Player.IsAlive() || e.LevelCompletionState != LevelCompletionStateInProgress {
+		return
+	}
+	e.Player.BreathHeld++
+	if e.Player.BreathHeld <= e.Level.GetBreath() {
+		return
+	}
+	e.Player.Health = world.HealthDead
+	e.publish(&world.Event{Event: world.EventPlayerKilled})
+}
+
+// breathInfo returns the active player's breath, or nil if they are not in an airless room.
+func (e *Engine) breathInfo() *BreathInfo {
+	if !e.CurrentRoom.Airless || !e.Player.IsAlive() {
+		return nil
+	}
+	capacity := e.Level.GetBreath()
+	return &BreathInfo{
+		Left:     max(capacity-e.Player.BreathHeld, 0),
+		Capacity: capacity,
+	}
+}
+
+// breatheFrom refills the player's breath from an air supply in their inventory, using it up.
+func (e *Engine) breatheFrom(airSupply *world.Item) (*healResultInternal, error) {
+	if !e.CurrentRoom.Airless {
+		return nil, world.Errorf(ErrInvalidTarget, "there is air to breathe here, save the %s", airSupply.Name)
+	}
+	e.Player.BreathHeld = 0
+	chargesLeft := e.useUpItem(airSupply)
+	e.playSound(airSupply.Name, airSupply.SoundCues, world.SoundHeal)
+	trap := e.springTrap(airSupply, world.TrapOnUse)
+	return &healResultInternal{
+		Health:      e.Player.Health,
+		Breathed:    true,
+		ChargesLeft: chargesLeft,
+		MaxCharges:  maxCharges(airSupply),
+		Trap:        trap,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBreath_Drowning(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "flooded.json"))
+
+	traverse, err := engine.Traverse(ctx, "down")
+	if err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if breath := traverse.EngineStateInfo.Breath; breath == nil || *breath != (BreathInfo{Left: 1, Capacity: 2}) {
+		t.Fatalf("Expected a breath to be used up by entering the tunnel, got %+v", breath)
+	}
+	inspect, err := engine.Inspect(ctx, "pebble")
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if inspect.EngineStateInfo.Breath.Left != 0 || inspect.EngineStateInfo.EngineStateChangeNotification != nil {
+		t.Fatalf("Expected the player to be out of breath but alive, got %+v", inspect.EngineStateInfo)
+	}
+
+	take, err := engine.Take(ctx, "pebble")
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if notification := take.EngineStateInfo.EngineStateChangeNotification; notification == nil || *notification != EngineStateChangeLevelFailed {
+		t.Errorf("Expected the player to drown, got %v", notification)
+	}
+	if engine.LevelCompletionState != LevelCompletionStateFailed || engine.Player.IsAlive() {
+		t.Errorf("Expected the level to be failed, got %v", engine.LevelCompletionState)
+	}
+	if _, err := engine.Observe(ctx, ObserveFilter{}); !errors.Is(err, ErrLevelOver) {
+		t.Errorf("Expected the level to be over, got %v", err)
+	}
+}
+
+func TestBreath_Refill(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "flooded.json"))
+
+	if _, err := engine.Take(ctx, "air tank"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if _, err := engine.Heal(ctx, "air tank"); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("Expected the air tank to be saved where there is air, got %v", err)
+	}
+	if _, err := engine.Traverse(ctx, "down"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if _, err := engine.Inspect(ctx, "pebble"); err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+
+	heal, err := engine.Heal(ctx, "air tank")
+	if err != nil {
+		t.Fatalf("Heal failed: %v", err)
+	}
+	if !heal.Result.Breathed || heal.EngineStateInfo.Breath.Left != 1 {
+		t.Errorf("Expected the air tank to refill the player's breath, got %+v and %+v", heal.Result, heal.EngineStateInfo.Breath)
+	}
+	if _, err := engine.Player.GetItem("air tank"); err == nil {
+		t.Error("Expected the air tank to be used up")
+	}
+
+	traverse, err := engine.Traverse(ctx, "ahead")
+	if err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if traverse.EngineStateInfo.Breath != nil || engine.Player.BreathHeld != 0 {
+		t.Errorf("Expected the air pocket to refill the player's breath, got %+v", traverse.EngineStateInfo.Breath)
+	}
+	if traverse.Result.EnteredRoom.Airless {
+		t.Error("Expected the air pocket not to be airless")
+	}
+}
@@ -0,0 +1,28 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+)
+
+// useUpItem uses an item in the player's inventory once: it spends one of the item's charges,
+// if it has charges, or else uses up one of its stack. An item is gone once the last of its
+// charges or of its stack is.
+// Returns the charges left, 0 for items without charges.
+func (e *Engine) useUpItem(item *world.Item) int {
+	if item.Charges == nil {
+		e.Player.ConsumeItem(item.Ref())
+		return 0
+	}
+	if item.Charges.Spend() {
+		e.Player.RemoveItem(item.Ref())
+	}
+	return item.Charges.Remaining()
+}
+
+// maxCharges returns the charges an item has when full, 0 for items without charges.
+func maxCharges(item *world.Item) int {
+	if item.Charges == nil {
+		return 0
+	}
+	return item.Charges.Max
+}
@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"testing"
+)
+
+func loadChargesLevel(t *testing.T) *Engine {
+	t.Helper()
+	engine := NewEngine(loadTestLevel(t, "charges.json"))
+	for _, name := range []string{"medkit", "battery"} {
+		if _, err := engine.Take(ctx, name); err != nil {
+			t.Fatalf("Take failed: %v", err)
+		}
+	}
+	return engine
+}
+
+func TestHeal_SpendsCharges(t *testing.T) {
+	engine := loadChargesLevel(t)
+	inventory, err := engine.inventoryInternal()
+	if err != nil {
+		t.Fatalf("Inventory failed: %v", err)
+	}
+	if medkit := inventory.Items[0]; medkit.Charges != 2 || medkit.MaxCharges != 3 {
+		t.Errorf("Expected the medkit to have 2 of 3 charges left, got %+v", medkit)
+	}
+
+	engine.Player.Health = world.HealthCrit
+	heal, err := engine.Heal(ctx, "medkit")
+	if err != nil {
+		t.Fatalf("Heal failed: %v", err)
+	}
+	if heal.Result.ChargesLeft != 1 || heal.Result.MaxCharges != 3 {
+		t.Errorf("Expected a charge left on the medkit, got %+v", heal.Result)
+	}
+	if _, err := engine.Player.GetItem("medkit"); err != nil {
+		t.Errorf("Expected the medkit to be kept while it has charges: %v", err)
+	}
+
+	heal, err = engine.Heal(ctx, "medkit")
+	if err != nil {
+		t.Fatalf("Heal failed: %v", err)
+	}
+	if heal.Result.ChargesLeft != 0 || engine.Player.Health != world.HealthFine {
+		t.Errorf("Expected the last charge to heal the player, got %+v", heal.Result)
+	}
+	if _, err := engine.Player.GetItem("medkit"); err == nil {
+		t.Error("Expected the medkit to be gone with its last charge")
+	}
+}
+
+func TestUse_SpendsCharges(t *testing.T) {
+	engine := loadChargesLevel(t)
+	use, err := engine.Use(ctx, "battery", "radio")
+	if err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if use.Result.ChargesLeft != 1 || use.Result.MaxCharges != 2 {
+		t.Errorf("Expected a charge left in the battery, got %+v", use.Result)
+	}
+	if _, err := engine.Use(ctx, "battery", "lamp"); err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if _, err := engine.Player.GetItem("battery"); err == nil {
+		t.Error("Expected the battery to be gone with its last charge")
+	}
+}
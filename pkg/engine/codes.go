@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"errors"
+
+	"adventure-engine/pkg/world"
+)
+
+// learnCodes records the codes of the level's keypads that a text the player has read spells out.
+func (e *Engine) learnCodes(texts ...string) {
+	for _, lock := range e.codeLocks() {
+		for _, text := range texts {
+			if world.RevealsCode(text, lock.Code) {
+				e.LearnedCodes[lock.Code] = true
+			}
+		}
+	}
+}
+
+// codeLocks returns the keypads on the level's doors and on the containers in its rooms and
+// in the players' inventories.
+func (e *Engine) codeLocks() []*world.Lock {
+	var locks []*world.Lock
+	for _, door := range e.Level.Doors {
+		if door.HasCodeLock() {
+			locks = append(locks, door.Lock)
+		}
+	}
+	for _, item := range e.levelItems() {
+		if item.IsContainer() && item.Container.HasCodeLock() {
+			locks = append(locks, item.Container.Locked)
+		}
+	}
+	return locks
+}
+
+// levelItems returns the items in the level's rooms, in the players' inventories and carried
+// by enemies, along with the items they conceal or contain.
+func (e *Engine) levelItems() []*world.Item {
+	var items []*world.Item
+	addItem := func(item *world.Item) {
+		for ; item != nil; item = hiddenItem(item) {
+			items = append(items, item)
+		}
+	}
+	for _, floor := range e.Level.Floors {
+		for _, room := range floor.Rooms {
+			for _, item := range room.Items {
+				addItem(item)
+			}
+		}
+	}
+	for _, item := range e.Player.Inventory {
+		addItem(item)
+	}
+	for _, enemy := range e.Level.Enemies {
+		addItem(enemy.Carries)
+	}
+	for _, state := range e.Players {
+		if state.ID != e.ActivePlayer && state.Player != nil {
+			for _, item := range state.Player.Inventory {
+				addItem(item)
+			}
+		}
+	}
+	return items
+}
+
+// hiddenItem returns the item concealed, contained or hidden behind by an item, if any.
+func hiddenItem(item *world.Item) *world.Item {
+	if item.IsConcealer() && item.Concealer.Hidden != nil {
+		return item.Concealer.Hidden
+	}
+	if item.IsContainer() {
+		return item.Container.Contains
+	}
+	if item.IsMoveable() {
+		return item.Moveable.Reveals
+	}
+	return nil
+}
+
+// unlockWithCode opens a keypad, which only takes its code once it has been learned if the
+// lock requires it. An unlearned code is turned down like a wrong one, so guessing gives
+// nothing away. Returns true instead of an error if a wrong code jammed the keypad.
+func (e *Engine) unlockWithCode(lock *world.Lock, code string) (bool, error) {
+	var err error
+	if lock.RequireLearned && lock.Locked && !lock.Jammed && !e.LearnedCodes[code] {
+		err = lock.RejectCode()
+	} else {
+		err = lock.UnlockWithCode(code)
+	}
+	if errors.Is(err, ErrWrongCode) && lock.Jammed {
+		return true, nil
+	}
+	return false, err
+}
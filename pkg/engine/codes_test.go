@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnlock_RequiresLearnedCode(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "codes.json"))
+
+	// The right code is turned down like a wrong one until the player has read it
+	if _, err := engine.Unlock(ctx, "2468", "vault door"); !errors.Is(err, ErrWrongCode) {
+		t.Fatalf("Expected ErrWrongCode for an unlearned code, got %v", err)
+	}
+	if _, err := engine.Unlock(ctx, "1357", "safe"); !errors.Is(err, ErrWrongCode) {
+		t.Fatalf("Expected ErrWrongCode for an unlearned code, got %v", err)
+	}
+
+	if _, err := engine.Inspect(ctx, "note"); err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if !engine.LearnedCodes["2468"] || engine.LearnedCodes["1357"] {
+		t.Errorf("Expected only the vault code to be learned, got %v", engine.LearnedCodes)
+	}
+	if _, err := engine.Unlock(ctx, "2468", "vault door"); err != nil {
+		t.Fatalf("Unlock failed after learning the code: %v", err)
+	}
+	if _, err := engine.Unlock(ctx, "1357", "safe"); !errors.Is(err, ErrWrongCode) {
+		t.Errorf("Expected ErrWrongCode for the safe's unlearned code, got %v", err)
+	}
+
+	// Codes read in another room open locks anywhere
+	if _, err := engine.Traverse(ctx, "north"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if _, err := engine.Inspect(ctx, "ledger"); err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if _, err := engine.Traverse(ctx, "south"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if _, err := engine.Unlock(ctx, "1357", "safe"); err != nil {
+		t.Errorf("Unlock failed after learning the code: %v", err)
+	}
+}
+
+func TestLearnCodes_WholeWordsOnly(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "codes.json"))
+
+	engine.learnCodes("serial number 24680", "extension 113570")
+	if len(engine.LearnedCodes) != 0 {
+		t.Errorf("Expected codes inside longer numbers not to be learned, got %v", engine.LearnedCodes)
+	}
+	engine.learnCodes("(2468)")
+	if !engine.LearnedCodes["2468"] {
+		t.Error("Expected a code between punctuation to be learned")
+	}
+}
+
+func TestUnlock_JamsAfterMaxAttempts(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "jamming.json"))
+
+	if _, err := engine.Unlock(ctx, "1111", "vault door"); !errors.Is(err, ErrWrongCode) {
+		t.Fatalf("Expected ErrWrongCode, got %v", err)
+	}
+	if left := engine.Level.GetDoor("vault door").Lock.AttemptsLeft(); left != 1 {
+		t.Errorf("Expected 1 attempt left, got %d", left)
+	}
+
+	// The last wrong code jams the keypad and sounds the alarm
+	unlock, err := engine.Unlock(ctx, "2222", "vault door")
+	if err != nil {
+		t.Fatalf("Expected the jamming attempt to succeed as an action, got %v", err)
+	}
+	if !unlock.Result.Jammed || unlock.Result.Unlocked {
+		t.Errorf("Expected the keypad to jam, got %+v", unlock.Result)
+	}
+	if notification := unlock.EngineStateInfo.EngineStateChangeNotification; notification == nil || *notification != EngineStateChangeEnterCombat {
+		t.Errorf("Expected the lockout to trigger combat, got %v", notification)
+	}
+	if engine.FightingEnemy == nil || engine.FightingEnemy.Name != "guard" {
+		t.Errorf("Expected to fight the guard, got %v", engine.FightingEnemy)
+	}
+
+	// Even the right code no longer opens it
+	engine.Mode, engine.FightingEnemy = Investigation, nil
+	if _, err := engine.Unlock(ctx, "2468", "vault door"); !errors.Is(err, ErrJammed) {
+		t.Errorf("Expected ErrJammed, got %v", err)
+	}
+}
+
+func TestUnlock_JammedContainer(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "jamming.json"))
+
+	inspect, err := engine.Inspect(ctx, "safe")
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if inspect.Result.ItemInspection.AttemptsLeft != 1 {
+		t.Errorf("Expected 1 attempt left, got %d", inspect.Result.ItemInspection.AttemptsLeft)
+	}
+	unlock, err := engine.Unlock(ctx, "0000", "safe")
+	if err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if !unlock.Result.Jammed || unlock.EngineStateInfo.EngineStateChangeNotification != nil {
+		t.Errorf("Expected the safe to jam without triggering anything, got %+v", unlock)
+	}
+	inspect, err = engine.Inspect(ctx, "safe")
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if !inspect.Result.ItemInspection.IsJammed || inspect.Result.ItemInspection.AttemptsLeft != 0 {
+		t.Errorf("Expected the safe to show as jammed, got %+v", inspect.Result.ItemInspection.ItemInfo)
+	}
+}
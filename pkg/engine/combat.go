@@ -0,0 +1,112 @@
+package engine
+
+import "adventure-engine/pkg/world"
+
+// CombatResolver decides who wins a round of battle, so that levels and sessions can pick how
+// fights play out without changing how a round is fought.
+type CombatResolver interface {
+	// WinsRound returns true if the player wins a round against the enemy they are fighting,
+	// striking with a weapon that deals the given damage.
+	WinsRound(e *Engine, damage float64) bool
+}
+
+// NewCombatResolver returns the resolver for a combat model, the probabilistic one for an
+// empty or unknown model.
+func NewCombatResolver(model world.CombatModel) CombatResolver {
+	switch model {
+	case world.CombatDeterministic:
+		return DeterministicCombat{}
+	case world.CombatOpposed:
+		return OpposedCombat{}
+	default:
+		return ProbabilisticCombat{}
+	}
+}
+
+// ProbabilisticCombat wins a round with the weapon's damage as the chance.
+type ProbabilisticCombat struct{}
+
+func (ProbabilisticCombat) WinsRound(e *Engine, damage float64) bool {
+	return e.chance("the player wins the round against the "+e.FightingEnemy.Name, damage)
+}
+
+// DeterministicCombat adds up the damage of the rounds of a fight, and wins a round each time
+// the total reaches 1. Fists win every other round, and a weapon dealing 1 damage every round.
+type DeterministicCombat struct{}
+
+func (DeterministicCombat) WinsRound(e *Engine, damage float64) bool {
+	e.CombatMomentum += damage
+	won := e.CombatMomentum >= 1
+	if won {
+		e.CombatMomentum--
+	}
+	e.debugf(DebugLogRoll, "the player wins the round against the %s: momentum %.3f, %t", e.FightingEnemy.Name, e.CombatMomentum, won)
+	return won
+}
+
+// enemyDamage is the damage an enemy's roll is weighted by in opposed combat, that of a
+// player's fists, so that fighting barehanded is an even fight.
+const enemyDamage = 0.5
+
+// OpposedCombat rolls for the player, weighted by the weapon's damage, and for the enemy,
+// weighted by enemyDamage. The player wins the round with the higher roll, and loses ties.
+type OpposedCombat struct{}
+
+func (OpposedCombat) WinsRound(e *Engine, damage float64) bool {
+	player := e.Rng.Float64() * damage
+	enemy := e.Rng.Float64() * enemyDamage
+	won := player > enemy
+	e.debugf(DebugLogRoll, "the player wins the round against the %s: rolled %.3f against %.3f, %t", e.FightingEnemy.Name, player, enemy, won)
+	return won
+}
+
+// pityOdds is how unlikely the rounds a player has lost in a row may get before they win the
+// next regardless of the combat model.
+const pityOdds = 0.01
+
+// winsRound decides whether the player wins a round with a weapon dealing the given damage.
+// A player whose losing streak in the fight has become less likely than pityOdds, reckoned as
+// if each round were won with the weapon's damage as the chance, wins the round instead, so
+// that a strong weapon never misses absurdly often. Returns whether the round was won, and
+// whether it was won out of pity.
+func (e *Engine) winsRound(damage float64) (won, pity bool) {
+	if e.LossStreakOdds <= pityOdds {
+		e.debugf(DebugLogRoll, "the player wins the round against the %s: lost rounds in a row at odds of %.4f", e.FightingEnemy.Name, e.LossStreakOdds)
+		won, pity = true, true
+	} else {
+		won = e.Combat.WinsRound(e, damage)
+	}
+	if won {
+		e.LossStreakOdds = 1
+	} else {
+		e.LossStreakOdds *= max(1-damage, 0)
+	}
+	return won, pity
+}
+
+// criticalHit rolls for whether a round won with a weapon is a critical hit. Fists and weapons
+// without critical hits never land one.
+func (e *Engine) criticalHit(weapon *world.Item) bool {
+	if weapon == nil || weapon.Weapon.Critical == nil || weapon.Weapon.Critical.Chance == 0 {
+		return false
+	}
+	return e.chance("the "+weapon.Name+" lands a critical hit", weapon.Weapon.Critical.Chance)
+}
+
+// fumble rolls for whether the player fumbles a weapon in a round they lost, and if they do
+// drops it in the current room, where it lies until they take it back. Quest items are never
+// dropped. Returns true if the weapon was fumbled.
+func (e *Engine) fumble(weapon *world.Item) bool {
+	if weapon == nil || weapon.Weapon.Critical == nil || weapon.Weapon.Critical.Fumble == 0 || weapon.Weapon.IsThrown() || weapon.QuestItem {
+		return false
+	}
+	if !e.chance("the player fumbles the "+weapon.Name, weapon.Weapon.Critical.Fumble) {
+		return false
+	}
+	if _, err := e.Player.RemoveItem(weapon.Ref()); err != nil {
+		return false
+	}
+	weapon.Location, weapon.SubLocation = "", ""
+	e.CurrentRoom.Items = append(e.CurrentRoom.Items, weapon)
+	return true
+}
@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"testing"
+
+	"adventure-engine/pkg/world"
+)
+
+// loadArmoryLevel loads a level with an axe that lands critical hits and a club that is easily
+// fumbled, and starts a fight with a troll.
+func loadArmoryLevel(t *testing.T) *Engine {
+	t.Helper()
+	engine := NewEngine(loadTestLevel(t, "armory.json"))
+	for _, item := range []string{"axe", "club", "bell"} {
+		if _, err := engine.Take(ctx, item); err != nil {
+			t.Fatalf("Take failed: %v", err)
+		}
+	}
+	return engine
+}
+
+func TestCombat_Deterministic(t *testing.T) {
+	engine := loadCampLevel(t)
+	engine.Combat = NewCombatResolver(world.CombatDeterministic)
+	// Fists deal half damage, so win every other round whatever the rolls
+	for i, expected := range []bool{false, true, false} {
+		result, err := engine.battleInternal("fists")
+		if err != nil {
+			t.Fatalf("Battle failed: %v", err)
+		}
+		if result.WonRound != expected {
+			t.Errorf("Expected round %d won to be %v", i+1, expected)
+		}
+	}
+}
+
+func TestCombat_Opposed(t *testing.T) {
+	engine := loadCampLevel(t)
+	engine.Combat = NewCombatResolver(world.CombatOpposed)
+	// A roll low enough to win any round under the probabilistic model, but fists are only as
+	// strong as the bandit and the player loses ties
+	engine.Rng = &FakeRng{Value: 0.1}
+	result, err := engine.battleInternal("fists")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if result.WonRound {
+		t.Error("Expected the player to lose an even roll")
+	}
+}
+
+func TestCombat_DefaultsToProbabilistic(t *testing.T) {
+	if _, ok := NewCombatResolver("").(ProbabilisticCombat); !ok {
+		t.Error("Expected levels without a combat model to use probabilistic combat")
+	}
+	engine := loadCampLevel(t)
+	if _, ok := engine.Combat.(ProbabilisticCombat); !ok {
+		t.Errorf("Expected the engine to use probabilistic combat, got %T", engine.Combat)
+	}
+}
+
+func TestCombat_CriticalHit(t *testing.T) {
+	engine := loadArmoryLevel(t)
+	engine.Rng = &FakeRng{Value: 0.1}
+	result, err := engine.battleInternal("axe")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if !result.Critical || result.Narrative != "The axe bites deep." {
+		t.Errorf("Expected a critical hit with the axe's narrative, got %+v", result)
+	}
+	if troll := engine.Level.GetEnemy("troll"); troll.HP != 3 {
+		t.Errorf("Expected the critical hit to cost the troll 2 hp, it has %d left", troll.HP)
+	}
+}
+
+func TestCombat_Fumble(t *testing.T) {
+	engine := loadArmoryLevel(t)
+	engine.Rng = &FakeRng{Value: 0.5}
+	result, err := engine.battleInternal("club")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if !result.Fumbled || result.Critical {
+		t.Errorf("Expected the club to be fumbled, got %+v", result)
+	}
+	if _, err := engine.CurrentRoom.GetItem("club"); err != nil || len(engine.Player.Inventory) != 2 {
+		t.Error("Expected the fumbled club to lie in the armory")
+	}
+	if _, err := engine.battleInternal("club"); err == nil {
+		t.Error("Expected fighting with the dropped club to fail")
+	}
+}
+
+func TestCombat_Pity(t *testing.T) {
+	engine := loadArmoryLevel(t)
+	// An axe misses one time in ten, so two misses in a row are as unlikely as the engine allows
+	engine.Rng = &FakeRng{Value: 0.95}
+	for i, expected := range []bool{false, false, true} {
+		result, err := engine.battleInternal("axe")
+		if err != nil {
+			t.Fatalf("Battle failed: %v", err)
+		}
+		if result.WonRound != expected || result.Pity != expected {
+			t.Errorf("Expected round %d won out of pity to be %v, got %+v", i+1, expected, result)
+		}
+	}
+	if engine.LossStreakOdds != 1 {
+		t.Errorf("Expected winning a round to end the losing streak, got odds of %v", engine.LossStreakOdds)
+	}
+}
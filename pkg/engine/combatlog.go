@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"slices"
+
+	"adventure-engine/pkg/world"
+)
+
+// CombatRound is a round of battle, as the combat log recalls it.
+type CombatRound struct {
+	Round        int    // 1 for the first round of the fight
+	Weapon       string // fistsWeaponName for rounds fought barehanded
+	WonRound     bool
+	Critical     bool
+	Damage       int // hit points the enemy lost
+	EnemyHP      int // hit points the enemy had left after the round
+	AmmoSpent    int
+	HealthBefore world.HealthState
+	HealthAfter  world.HealthState
+	Absorbed     string // the armor that stopped the enemy's hit, if any
+}
+
+// CombatLog is the rounds of the player's current fight, or of their last one once it is over,
+// so that the fight can be recapped rather than only its latest round.
+type CombatLog struct {
+	EnemyName string
+	Rounds    []CombatRound
+}
+
+// clone returns a copy of the combat log, nil for none.
+func (l *CombatLog) clone() *CombatLog {
+	if l == nil {
+		return nil
+	}
+	return &CombatLog{EnemyName: l.EnemyName, Rounds: slices.Clone(l.Rounds)}
+}
+
+type CombatLogResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          CombatLog
+}
+
+// CombatLog returns the rounds of the current fight, or of the last one once it is over.
+// Fails with ErrNotFound before the player has fought.
+func (e *Engine) CombatLog() (*CombatLogResult, error) {
+	if e.Fight == nil {
+		return nil, world.Errorf(ErrNotFound, "there has been no fight yet")
+	}
+	return &CombatLogResult{
+		EngineStateInfo: *e.getEngineStateInfo(),
+		Result:          *e.Fight.clone(),
+	}, nil
+}
+
+// startFight begins a new combat log for a fight with an enemy.
+func (e *Engine) startFight(enemy *world.Enemy) {
+	e.Fight = &CombatLog{EnemyName: enemy.Name}
+}
+
+// logRound adds a round fought with a weapon, nil for fists, to the combat log, given the
+// enemy's hit points and the player's health before it.
+// Returns the rounds of the fight so far.
+func (e *Engine) logRound(weapon *world.Item, result *battleResultInternal, enemyHP int, health world.HealthState) []CombatRound {
+	if e.Fight == nil || e.Fight.EnemyName != result.EnemyName {
+		e.startFight(e.FightingEnemy)
+	}
+	round := CombatRound{
+		Round:        len(e.Fight.Rounds) + 1,
+		Weapon:       fistsWeaponName,
+		WonRound:     result.WonRound,
+		Critical:     result.Critical,
+		Damage:       enemyHP - e.FightingEnemy.HP,
+		EnemyHP:      e.FightingEnemy.HP,
+		HealthBefore: health,
+		HealthAfter:  e.Player.Health,
+		Absorbed:     result.Absorbed,
+	}
+	if weapon != nil {
+		round.Weapon = weapon.Name
+		if weapon.Weapon.UsesAmmo() {
+			round.AmmoSpent = 1
+		}
+	}
+	e.Fight.Rounds = append(e.Fight.Rounds, round)
+	return slices.Clone(e.Fight.Rounds)
+}
@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"adventure-engine/pkg/world"
+)
+
+func TestCombatLog(t *testing.T) {
+	engine := loadArmoryLevel(t)
+	if _, err := engine.CombatLog(); err != nil {
+		t.Fatalf("Expected the fight with the troll to be logged, got %v", err)
+	}
+
+	engine.Rng = &FakeRng{Value: 0.1}
+	if _, err := engine.Battle(ctx, "axe"); err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	engine.Rng = &FakeRng{Value: 0.95}
+	result, err := engine.Battle(ctx, "fists")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	expected := []CombatRound{
+		{Round: 1, Weapon: "axe", WonRound: true, Critical: true, Damage: 2, EnemyHP: 3, HealthBefore: world.HealthFine, HealthAfter: world.HealthFine},
+		{Round: 2, Weapon: "fists", EnemyHP: 3, HealthBefore: world.HealthFine, HealthAfter: world.HealthHurt},
+	}
+	if len(result.Result.CombatLog) != len(expected) {
+		t.Fatalf("Expected %d rounds in the battle response, got %+v", len(expected), result.Result.CombatLog)
+	}
+	for i, round := range result.Result.CombatLog {
+		if round != expected[i] {
+			t.Errorf("Expected round %d to be %+v, got %+v", i+1, expected[i], round)
+		}
+	}
+
+	log, err := engine.CombatLog()
+	if err != nil {
+		t.Fatalf("CombatLog failed: %v", err)
+	}
+	if log.Result.EnemyName != "troll" || len(log.Result.Rounds) != 2 {
+		t.Errorf("Expected the two rounds against the troll, got %+v", log.Result)
+	}
+}
+
+func TestCombatLog_NoFight(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "flooded.json"))
+	if _, err := engine.CombatLog(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected no combat log before a fight, got %v", err)
+	}
+}
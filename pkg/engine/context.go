@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"context"
+	"fmt"
+)
+
+// ContextVerbosity controls how much detail a context includes.
+type ContextVerbosity string
+
+const (
+	// ContextBrief omits item and door descriptions and the minimap.
+	ContextBrief ContextVerbosity = "brief"
+	// ContextFull includes everything.
+	ContextFull ContextVerbosity = "full"
+)
+
+// Objective is a goal the player is currently working towards.
+type Objective struct {
+	Description string
+}
+
+// contextResultInternal is everything a client needs to know about the player's situation.
+type contextResultInternal struct {
+	Room       observeResultInternal
+	Inventory  inventoryResultInternal
+	Minimap    *minimapResultInternal // nil for brief contexts
+	Objectives []Objective
+}
+
+type ContextResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          contextResultInternal
+}
+
+// Context returns the current room, inventory, minimap and active objectives in a single call.
+// Like Observe, this marks the current room as visited.
+func (e *Engine) Context(ctx context.Context, verbosity ContextVerbosity) (*ContextResult, error) {
+	return act(e, ctx, "context", func() (*ContextResult, error) {
+		if verbosity != ContextBrief && verbosity != ContextFull {
+			return nil, world.Errorf(ErrInvalidArgument, "invalid verbosity %s", verbosity)
+		}
+		if !e.ValidationDisabled {
+			if err := e.validateEngineState(); err != nil {
+				return nil, err
+			}
+		}
+
+		room, err := e.observeInternal(ObserveFilter{})
+		if err != nil {
+			return nil, err
+		}
+		inventory, err := e.inventoryInternal()
+		if err != nil {
+			return nil, err
+		}
+		result := contextResultInternal{
+			Room:       *room,
+			Inventory:  *inventory,
+			Objectives: e.activeObjectives(),
+		}
+
+		if verbosity == ContextFull {
+			minimap, err := e.minimapInternal()
+			if err != nil {
+				return nil, err
+			}
+			result.Minimap = minimap
+		} else {
+			result.Room.RoomImageRef = ""
+			for i := range result.Room.VisibleItems {
+				result.Room.VisibleItems[i].Description = ""
+				result.Room.VisibleItems[i].Location = ""
+				result.Room.VisibleItems[i].ImageRef = ""
+			}
+			for i := range result.Room.Doors {
+				result.Room.Doors[i].Description = ""
+			}
+			for i := range result.Inventory.Items {
+				result.Inventory.Items[i].Description = ""
+				result.Inventory.Items[i].ImageRef = ""
+			}
+		}
+
+		return &ContextResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          result,
+		}, nil
+	})
+}
+
+// activeObjectives returns the goals the player is currently working towards: the fight in
+// progress, then the level's active authored objectives if it has any, or else incomplete
+// fixtures in visited rooms and the level's win condition.
+func (e *Engine) activeObjectives() []Objective {
+	if e.LevelCompletionState != LevelCompletionStateInProgress {
+		return nil
+	}
+
+	var objectives []Objective
+	if e.Mode == Combat && e.FightingEnemy != nil {
+		objectives = append(objectives, Objective{Description: fmt.Sprintf("defeat the %s", e.FightingEnemy.Name)})
+	}
+
+	if len(e.Level.Objectives) > 0 {
+		for _, objective := range e.visibleObjectives() {
+			if objective.Status == ObjectiveActive {
+				objectives = append(objectives, Objective{Description: e.localize(objective.Description)})
+			}
+		}
+		return objectives
+	}
+
+	for _, floor := range e.Level.Floors {
+		for _, room := range floor.Rooms {
+			if !room.Visited && room != e.CurrentRoom {
+				continue
+			}
+			for _, item := range room.Items {
+				if item.Fixture != nil && !item.Fixture.IsComplete() {
+					objectives = append(objectives, Objective{Description: fmt.Sprintf("complete the %s in the %s", item.Name, room.Name)})
+				}
+			}
+		}
+	}
+
+	if win := e.Level.WinCondition; win != nil {
+		switch win.Event {
+		case world.EventRoomEntered:
+			objectives = append(objectives, Objective{Description: fmt.Sprintf("reach the %s", win.RoomName)})
+		case world.EventEnemyKilled:
+			if e.FightingEnemy == nil || e.FightingEnemy.Name != win.EnemyName {
+				objectives = append(objectives, Objective{Description: fmt.Sprintf("defeat the %s", win.EnemyName)})
+			}
+		}
+	}
+	return objectives
+}
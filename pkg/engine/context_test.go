@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"adventure-engine/pkg/loader"
+	"slices"
+	"testing"
+)
+
+func TestContext_Verbosity(t *testing.T) {
+	level, err := loader.LoadGameFromFile("../../internal/testdata/fixture.json")
+	if err != nil {
+		t.Fatalf("Failed to load level: %v", err)
+	}
+	engine := NewEngine(level)
+	if _, err := engine.Take(ctx, "fish hook"); err != nil {
+		t.Fatalf("Take fish hook failed: %v", err)
+	}
+
+	full, err := engine.Context(ctx, ContextFull)
+	if err != nil {
+		t.Fatalf("Context failed: %v", err)
+	}
+	if full.Result.Room.RoomName != "bathroom" || full.Result.Room.RoomImageRef != "rooms/bathroom.png" {
+		t.Errorf("Expected bathroom with its image, got %s and %q", full.Result.Room.RoomName, full.Result.Room.RoomImageRef)
+	}
+	if len(full.Result.Inventory.Items) != 1 || full.Result.Inventory.Items[0].Description == "" || full.Result.Inventory.Items[0].ImageRef == "" {
+		t.Errorf("Expected fish hook with description and image in inventory, got %+v", full.Result.Inventory.Items)
+	}
+	if full.Result.Minimap == nil {
+		t.Error("Expected minimap in full context")
+	}
+	for _, item := range full.Result.Room.VisibleItems {
+		if item.Description == "" {
+			t.Errorf("Expected description for %s in full context", item.Name)
+		}
+	}
+
+	brief, err := engine.Context(ctx, ContextBrief)
+	if err != nil {
+		t.Fatalf("Context failed: %v", err)
+	}
+	if brief.Result.Minimap != nil {
+		t.Error("Expected no minimap in brief context")
+	}
+	if len(brief.Result.Room.VisibleItems) != len(full.Result.Room.VisibleItems) {
+		t.Errorf("Expected brief context to list the same items")
+	}
+	for _, item := range brief.Result.Room.VisibleItems {
+		if item.Description != "" || item.Location != "" {
+			t.Errorf("Expected no description or location for %s in brief context", item.Name)
+		}
+	}
+	if brief.Result.Inventory.Items[0].Description != "" || brief.Result.Inventory.Items[0].ImageRef != "" {
+		t.Error("Expected no inventory descriptions or images in brief context")
+	}
+	if brief.Result.Room.RoomImageRef != "" {
+		t.Error("Expected no room image in brief context")
+	}
+
+	if _, err := engine.Context(ctx, "verbose"); err == nil {
+		t.Error("Expected invalid verbosity to be rejected")
+	}
+}
+
+func TestContext_Objectives(t *testing.T) {
+	level, err := loader.LoadGameFromFile("../../internal/testdata/fixture.json")
+	if err != nil {
+		t.Fatalf("Failed to load level: %v", err)
+	}
+	engine := NewEngine(level)
+
+	objectives := func() []string {
+		result, err := engine.Context(ctx, ContextBrief)
+		if err != nil {
+			t.Fatalf("Context failed: %v", err)
+		}
+		var descriptions []string
+		for _, objective := range result.Result.Objectives {
+			descriptions = append(descriptions, objective.Description)
+		}
+		return descriptions
+	}
+
+	expected := []string{"complete the bathtub drain in the bathroom", "reach the balcony"}
+	if got := objectives(); !slices.Equal(got, expected) {
+		t.Errorf("Expected objectives %v, got %v", expected, got)
+	}
+
+	// Completing the fixture removes its objective
+	for _, name := range []string{"fish hook", "dental floss"} {
+		if _, err := engine.Take(ctx, name); err != nil {
+			t.Fatalf("Take %s failed: %v", name, err)
+		}
+	}
+	if _, err := engine.Combine(ctx, "fish hook", "dental floss"); err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if _, err := engine.Use(ctx, "retrieval tool", "bathtub drain"); err != nil {
+		t.Fatalf("Use retrieval tool failed: %v", err)
+	}
+	if got := objectives(); !slices.Equal(got, []string{"reach the balcony"}) {
+		t.Errorf("Expected only the win condition objective, got %v", got)
+	}
+}
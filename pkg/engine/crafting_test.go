@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func loadCraftingLevel(t *testing.T) *Engine {
+	t.Helper()
+	engine := NewEngine(loadTestLevel(t, "recipes.json"))
+	for _, name := range []string{"rope", "hook", "pole", "oil"} {
+		if _, err := engine.Take(ctx, name); err != nil {
+			t.Fatalf("Take %s failed: %v", name, err)
+		}
+	}
+	return engine
+}
+
+func TestCombine_Recipe(t *testing.T) {
+	engine := loadCraftingLevel(t)
+
+	if _, err := engine.Combine(ctx, "rope", "hook", "pole"); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("Expected a partial recipe to be refused, got %v", err)
+	}
+	if _, err := engine.Combine(ctx, "oil", "pole", "hook", "rope"); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("Expected combining away from the workbench to be refused, got %v", err)
+	}
+
+	if _, err := engine.Traverse(ctx, "east"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	result, err := engine.Combine(ctx, "oil", "pole", "hook", "rope")
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if result.Result.CraftedItem.Name != "grapple" || len(result.Result.Byproducts) != 1 || result.Result.Byproducts[0].Name != "empty bottle" {
+		t.Errorf("Expected a grapple and an empty bottle, got %+v", result.Result)
+	}
+	if len(engine.Player.Inventory) != 2 {
+		t.Errorf("Expected the inputs to be used up, got %d items", len(engine.Player.Inventory))
+	}
+}
+
+func TestCombine_InvalidItemCounts(t *testing.T) {
+	engine := loadCraftingLevel(t)
+
+	tests := [][]string{
+		{"rope"},
+		{"rope", "hook", "pole", "oil", "rope"},
+		{"rope", "rope"},
+	}
+	for _, items := range tests {
+		if _, err := engine.Combine(ctx, items...); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("Expected combining %v to be an invalid argument, got %v", items, err)
+		}
+	}
+	if len(engine.Player.Inventory) != 4 {
+		t.Errorf("Expected the inventory to be untouched, got %d items", len(engine.Player.Inventory))
+	}
+}
+
+func TestRecipes_Discovery(t *testing.T) {
+	engine := loadCraftingLevel(t)
+
+	recipes, err := engine.Recipes(false)
+	if err != nil {
+		t.Fatalf("Recipes failed: %v", err)
+	}
+	if len(recipes.Result) != 1 || recipes.Result[0].OutputName != "oily hook" {
+		t.Fatalf("Expected only the known recipe, got %+v", recipes.Result)
+	}
+	recipes, _ = engine.Recipes(true)
+	if len(recipes.Result) != 3 || recipes.Result[0].Discovered || recipes.Result[0].OutputName != "" || len(recipes.Result[0].InputNames) != 4 {
+		t.Fatalf("Expected undiscovered recipes to hide their outputs, got %+v", recipes.Result)
+	}
+
+	inspect, err := engine.Inspect(ctx, "pole")
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if learned := inspect.Result.ItemInspection.LearnedRecipes; len(learned) != 1 || learned[0] != "ladder" {
+		t.Errorf("Expected the pole to teach the ladder, got %v", learned)
+	}
+	if inspect, _ = engine.Inspect(ctx, "pole"); len(inspect.Result.ItemInspection.LearnedRecipes) != 0 {
+		t.Errorf("Expected the ladder to be taught once, got %v", inspect.Result.ItemInspection.LearnedRecipes)
+	}
+
+	if _, err := engine.Traverse(ctx, "east"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	combine, err := engine.Combine(ctx, "rope", "hook", "pole", "oil")
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if !combine.Result.NewRecipe {
+		t.Error("Expected the grapple recipe to be discovered by trying it")
+	}
+	recipes, _ = engine.Recipes(false)
+	if len(recipes.Result) != 3 || recipes.Result[0].OutputName != "grapple" {
+		t.Errorf("Expected every recipe to be known, got %+v", recipes.Result)
+	}
+}
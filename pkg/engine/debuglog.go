@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"adventure-engine/pkg/world"
+)
+
+// maxDebugLog is how many entries an engine keeps in its debug log.
+const maxDebugLog = 256
+
+// Kinds of debug log entry.
+const (
+	DebugLogTrigger    = "trigger"    // a trigger matched an event, or was passed over
+	DebugLogEffect     = "effect"     // an effect ran
+	DebugLogRoll       = "roll"       // a random number decided something
+	DebugLogValidation = "validation" // an action was allowed to run or refused
+)
+
+// DebugLogEntry is a decision the engine made while playing, kept so that how the game came to be
+// as it is can be worked out after the fact, such as why a fight started.
+type DebugLogEntry struct {
+	Turn         int    // turns taken when the decision was made
+	StateVersion uint64 // the state version the decision was made at
+	Kind         string
+	Message      string
+}
+
+// debugf adds an entry to the debug log, forgetting the oldest once more than maxDebugLog are
+// kept.
+func (e *Engine) debugf(kind, format string, args ...any) {
+	e.debugLog = append(e.debugLog, DebugLogEntry{
+		Turn:         e.Stats.Turns,
+		StateVersion: e.StateVersion,
+		Kind:         kind,
+		Message:      fmt.Sprintf(format, args...),
+	})
+	if excess := len(e.debugLog) - maxDebugLog; excess > 0 {
+		e.debugLog = slices.Delete(e.debugLog, 0, excess)
+	}
+}
+
+// chance draws a random number for something that happens with a chance from 0 to 1, logging
+// the draw. Returns true if it happens.
+func (e *Engine) chance(what string, chance float64) bool {
+	roll := e.Rng.Float64()
+	e.debugf(DebugLogRoll, "%s: rolled %.3f against %.3f, %t", what, roll, chance, roll < chance)
+	return roll < chance
+}
+
+// checkAction logs whether the validation of an action let it run, and passes on its error.
+func (e *Engine) checkAction(action Action, err error) error {
+	if err != nil {
+		e.debugf(DebugLogValidation, "%s refused: %v", describeAction(action), err)
+	} else {
+		e.debugf(DebugLogValidation, "%s allowed", describeAction(action))
+	}
+	return err
+}
+
+// describeAction names an action and its arguments, such as "take idol".
+func describeAction(action Action) string {
+	return strings.Join(append([]string{action.Name}, action.Args...), " ")
+}
+
+// describeEffect names an effect and what it acts on, such as "enter_combat troll".
+func describeEffect(effect *world.Effect) string {
+	subject := effect.EnemyName + effect.TargetName
+	if subject == "" {
+		return string(effect.EffectType)
+	}
+	return string(effect.EffectType) + " " + subject
+}
@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestDebugLog_WhyCombatStarted(t *testing.T) {
+	engine := loadCampLevel(t)
+	if _, err := engine.Traverse(ctx, "north"); err == nil {
+		t.Fatal("Expected traversing mid-fight to fail")
+	}
+	if _, err := engine.Battle(ctx, "fists"); err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+
+	debug, err := engine.Debug()
+	if err != nil {
+		t.Fatalf("Debug failed: %v", err)
+	}
+	var entries []string
+	for _, entry := range debug.Log {
+		entries = append(entries, fmt.Sprintf("%d %s: %s", entry.Turn, entry.Kind, entry.Message))
+	}
+	expected := []string{
+		"0 validation: take lantern allowed",
+		"1 trigger: enter_combat bandit matched on item_taken lantern",
+		"1 effect: enter_combat bandit runs in the camp",
+		"1 validation: traverse north refused: cannot perform this action in combat mode",
+		"1 validation: battle fists allowed",
+		"1 roll: the player wins the round against the bandit: rolled 0.100 against 0.500, true",
+	}
+	if len(entries) < len(expected) || !slices.Equal(entries[:len(expected)], expected) {
+		t.Errorf("Expected the log to tell how the fight started, got %q", entries)
+	}
+}
+
+func TestDebugLog_KeepsTheLatest(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "flooded.json"))
+	for i := range maxDebugLog + 2 {
+		engine.debugf(DebugLogEffect, "%d", i)
+	}
+	if len(engine.debugLog) != maxDebugLog || engine.debugLog[0].Message != "2" {
+		t.Errorf("Expected only the latest %d entries, got %d starting with %q", maxDebugLog, len(engine.debugLog), engine.debugLog[0].Message)
+	}
+}
@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+)
+
+// roomDescription returns the description of a room as the player sees it now: the first of its
+// conditional descriptions that holds, its initial description on the first visit, or its description.
+func (e *Engine) roomDescription(room *world.Room) string {
+	if description, ok := e.conditionalDescription(room); ok {
+		return description
+	}
+	if !room.Visited && room.InitialDescription != "" {
+		return room.InitialDescription
+	}
+	return room.Description
+}
+
+// conditionalDescription returns the first of a room's conditional descriptions that holds, if any.
+func (e *Engine) conditionalDescription(room *world.Room) (string, bool) {
+	for _, description := range room.ConditionalDescriptions {
+		if e.conditionHolds(description.When) {
+			return description.Description, true
+		}
+	}
+	return "", false
+}
+
+// conditionHolds returns true if the world is in the state a description condition asks for.
+func (e *Engine) conditionHolds(when world.DescriptionCondition) bool {
+	if when.ItemTaken != "" && !e.TakenItems[when.ItemTaken] {
+		return false
+	}
+	if when.EnemyKilled != "" {
+		enemy := e.Level.GetEnemy(when.EnemyKilled)
+		if enemy == nil || enemy.IsHostile() {
+			return false
+		}
+	}
+	if when.DoorUnlocked != "" {
+		door := e.Level.GetDoor(when.DoorUnlocked)
+		if door == nil || door.IsLocked() {
+			return false
+		}
+	}
+	return e.Stats.Turns >= when.MinTurns
+}
+
+// settleDescriptions rewrites a room's conditional descriptions for a level starting from the current
+// state. The parts of conditions that already hold are dropped and turn counts restart. A description
+// whose condition then holds outright becomes the room's description, and those after it are dropped.
+func (e *Engine) settleDescriptions(room *world.Room) {
+	var descriptions []*world.ConditionalDescription
+	for _, description := range room.ConditionalDescriptions {
+		when := description.When
+		if when.ItemTaken != "" && e.TakenItems[when.ItemTaken] {
+			when.ItemTaken = ""
+		}
+		if when.EnemyKilled != "" && e.conditionHolds(world.DescriptionCondition{EnemyKilled: when.EnemyKilled}) {
+			when.EnemyKilled = ""
+		}
+		if when.DoorUnlocked != "" && e.conditionHolds(world.DescriptionCondition{DoorUnlocked: when.DoorUnlocked}) {
+			when.DoorUnlocked = ""
+		}
+		when.MinTurns = max(when.MinTurns-e.Stats.Turns, 0)
+		if when == (world.DescriptionCondition{}) {
+			room.Description = description.Description
+			break
+		}
+		descriptions = append(descriptions, &world.ConditionalDescription{When: when, Description: description.Description})
+	}
+	room.ConditionalDescriptions = descriptions
+}
@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"adventure-engine/pkg/loader"
+	"testing"
+)
+
+func observeDescription(t *testing.T, engine *Engine) string {
+	t.Helper()
+	observe, err := engine.Observe(ctx, ObserveFilter{})
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	return observe.Result.RoomDescription
+}
+
+func TestConditionalDescriptions(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "descriptions.json"))
+
+	if description := observeDescription(t, engine); description != "you step into a quiet hall" {
+		t.Errorf("Expected the initial description, got %q", description)
+	}
+	if description := observeDescription(t, engine); description != "a quiet hall" {
+		t.Errorf("Expected the description, got %q", description)
+	}
+
+	if _, err := engine.Take(ctx, "iron key"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if _, err := engine.Unlock(ctx, "iron key", "iron door"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if description := observeDescription(t, engine); description != "a draft blows through the hall" {
+		t.Errorf("Expected the description for the unlocked door, got %q", description)
+	}
+
+	// Earlier descriptions win over later ones
+	if _, err := engine.Take(ctx, "idol"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if description := observeDescription(t, engine); description != "the hall shakes, as if angered" {
+		t.Errorf("Expected the description for the taken idol, got %q", description)
+	}
+
+	engine.Level.GetEnemy("ghoul").HP = 0
+	if description := observeDescription(t, engine); description != "the hall is still now" {
+		t.Errorf("Expected the description once every condition holds, got %q", description)
+	}
+}
+
+func TestConditionalDescriptions_Turns(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "descriptions.json"))
+	engine.Observe(ctx, ObserveFilter{})
+
+	for engine.Stats.Turns < 4 {
+		if _, err := engine.Inspect(ctx, "pebble"); err != nil {
+			t.Fatalf("Inspect failed: %v", err)
+		}
+	}
+	if description := observeDescription(t, engine); description != "a quiet hall" {
+		t.Errorf("Expected the description before the fifth turn, got %q", description)
+	}
+	if _, err := engine.Inspect(ctx, "pebble"); err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if description := observeDescription(t, engine); description != "dust settles in the hall" {
+		t.Errorf("Expected the description after five turns, got %q", description)
+	}
+}
+
+func TestExportLevel_SettlesConditionalDescriptions(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "descriptions.json"))
+	engine.Observe(ctx, ObserveFilter{})
+	engine.Inspect(ctx, "pebble")
+	if _, err := engine.Take(ctx, "iron key"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+
+	data, err := engine.ExportLevel()
+	if err != nil {
+		t.Fatalf("ExportLevel failed: %v", err)
+	}
+	level, err := loader.LoadGame(data)
+	if err != nil {
+		t.Fatalf("Failed to load exported level: %v\n%s", err, data)
+	}
+	descriptions := level.GetRoom("main floor", "hall").ConditionalDescriptions
+	if len(descriptions) != 4 || descriptions[3].When.MinTurns != 3 {
+		t.Fatalf("Expected the turn count to restart, got %+v", descriptions)
+	}
+
+	if _, err := engine.Unlock(ctx, "iron key", "iron door"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	data, err = engine.ExportLevel()
+	if err != nil {
+		t.Fatalf("ExportLevel failed: %v", err)
+	}
+	level, err = loader.LoadGame(data)
+	if err != nil {
+		t.Fatalf("Failed to load exported level: %v\n%s", err, data)
+	}
+	hall := level.GetRoom("main floor", "hall")
+	if hall.Description != "a draft blows through the hall" || len(hall.ConditionalDescriptions) != 2 {
+		t.Errorf("Expected the unlocked door's description to become the hall's, got %q and %+v", hall.Description, hall.ConditionalDescriptions)
+	}
+}
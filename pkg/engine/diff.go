@@ -0,0 +1,197 @@
+package engine
+
+import (
+	"slices"
+
+	"adventure-engine/pkg/world"
+)
+
+// maxVersionHistory is how many past state versions an engine remembers to diff against.
+const maxVersionHistory = 64
+
+// versionRecord is what a diff compares of the game state at one state version: the items in
+// each room, the doors and every player's health, room and inventory.
+type versionRecord struct {
+	version uint64
+	rooms   map[string][]string // room name -> names of the items in the room, in room order
+	doors   map[string]doorRecord
+	players map[string]playerRecord // player ID -> player, HostPlayerID in single player sessions
+}
+
+type doorRecord struct {
+	locked bool
+	hidden bool
+}
+
+type playerRecord struct {
+	health    world.HealthState
+	room      string
+	inventory []string
+}
+
+type DiffResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          diffResultInternal
+}
+
+// diffResultInternal is what changed in the game since an earlier state version, as seen by
+// the active player. Lists are empty if nothing of the kind changed.
+type diffResultInternal struct {
+	Since         uint64
+	Rooms         []RoomItemsDiff // rooms whose items changed, in level order
+	Inventory     ItemsDiff
+	DoorsUnlocked []string
+	DoorsLocked   []string
+	DoorsRevealed []string      // secret doors found since
+	Health        *HealthChange // nil if the player's health is unchanged
+	PreviousRoom  string        // the room the player was in, empty if they are still there
+}
+
+// ItemsDiff lists the items added to and removed from a room or an inventory, by name.
+type ItemsDiff struct {
+	Added   []string
+	Removed []string
+}
+
+// RoomItemsDiff is how the items in a room changed.
+type RoomItemsDiff struct {
+	RoomName string
+	ItemsDiff
+}
+
+// HealthChange is how the player's health changed.
+type HealthChange struct {
+	From world.HealthState
+	To   world.HealthState
+}
+
+// Diff returns what changed in the game since an earlier state version, so that clients can
+// keep up with the game without fetching the whole context after every turn. Changes are
+// reported as the active player sees them: their own health, room and inventory, and the items
+// and doors of the level. Engines remember the last maxVersionHistory versions; diffing against
+// an older one fails with ErrVersionExpired, and the client should fetch the context instead.
+// Allowed in all modes and after the level has ended.
+func (e *Engine) Diff(since uint64) (*DiffResult, error) {
+	if since > e.StateVersion {
+		return nil, world.Errorf(ErrInvalidArgument, "state version %d is ahead of the game, which is at %d", since, e.StateVersion)
+	}
+	now := e.recordVersion()
+	then := now
+	if since != e.StateVersion {
+		i := slices.IndexFunc(e.versions, func(record *versionRecord) bool { return record.version == since })
+		if i < 0 {
+			return nil, world.Errorf(ErrVersionExpired, "state version %d is too old to diff against", since)
+		}
+		then = e.versions[i]
+	}
+	return &DiffResult{
+		EngineStateInfo: *e.getEngineStateInfo(),
+		Result:          e.diff(then, now),
+	}, nil
+}
+
+// rememberVersion records the game state at the current state version, unless it already has.
+// Called before anything changes the state, so that the record holds the state as clients
+// last saw it at that version.
+func (e *Engine) rememberVersion() {
+	if n := len(e.versions); n > 0 && e.versions[n-1].version == e.StateVersion {
+		return
+	}
+	e.versions = append(e.versions, e.recordVersion())
+	if len(e.versions) > maxVersionHistory {
+		e.versions = slices.Delete(e.versions, 0, len(e.versions)-maxVersionHistory)
+	}
+}
+
+// recordVersion returns a record of the current game state.
+func (e *Engine) recordVersion() *versionRecord {
+	record := &versionRecord{
+		version: e.StateVersion,
+		rooms:   make(map[string][]string),
+		doors:   make(map[string]doorRecord, len(e.Level.Doors)),
+		players: make(map[string]playerRecord),
+	}
+	for _, floor := range e.Level.Floors {
+		for _, room := range floor.Rooms {
+			record.rooms[room.Name] = itemNames(room.Items)
+		}
+	}
+	for _, door := range e.Level.Doors {
+		record.doors[door.Name] = doorRecord{locked: door.IsLocked(), hidden: door.Hidden}
+	}
+	record.players[e.ActivePlayer] = playerRecord{health: e.Player.Health, room: e.CurrentRoom.Name, inventory: itemNames(e.Player.Inventory)}
+	for _, state := range e.Players {
+		if state.ID != e.ActivePlayer {
+			record.players[state.ID] = playerRecord{health: state.Player.Health, room: state.CurrentRoom.Name, inventory: itemNames(state.Player.Inventory)}
+		}
+	}
+	return record
+}
+
+// diff compares two records as the active player sees them.
+func (e *Engine) diff(then *versionRecord, now *versionRecord) diffResultInternal {
+	result := diffResultInternal{Since: then.version}
+	for _, floor := range e.Level.Floors {
+		for _, room := range floor.Rooms {
+			if items := diffItems(then.rooms[room.Name], now.rooms[room.Name]); items.Added != nil || items.Removed != nil {
+				result.Rooms = append(result.Rooms, RoomItemsDiff{RoomName: room.Name, ItemsDiff: items})
+			}
+		}
+	}
+	for _, door := range e.Level.Doors {
+		before, after := then.doors[door.Name], now.doors[door.Name]
+		if after.hidden {
+			continue
+		}
+		if before.hidden {
+			result.DoorsRevealed = append(result.DoorsRevealed, door.Name)
+		}
+		switch {
+		case before.locked && !after.locked:
+			result.DoorsUnlocked = append(result.DoorsUnlocked, door.Name)
+		case !before.locked && after.locked:
+			result.DoorsLocked = append(result.DoorsLocked, door.Name)
+		}
+	}
+
+	// Players who joined since are compared with the player they started as
+	player := now.players[e.ActivePlayer]
+	before, ok := then.players[e.ActivePlayer]
+	if !ok {
+		before = playerRecord{health: world.HealthState(world.HealthFine), room: e.Level.Floors[0].Rooms[0].Name}
+	}
+	result.Inventory = diffItems(before.inventory, player.inventory)
+	if before.health != player.health {
+		result.Health = &HealthChange{From: before.health, To: player.health}
+	}
+	if before.room != player.room {
+		result.PreviousRoom = before.room
+	}
+	return result
+}
+
+// diffItems returns the names in after that are not in before, and the other way round.
+// Names are counted, so one of two items with the same name going is seen.
+func diffItems(before []string, after []string) ItemsDiff {
+	var diff ItemsDiff
+	remaining := slices.Clone(before)
+	for _, name := range after {
+		if i := slices.Index(remaining, name); i >= 0 {
+			remaining = slices.Delete(remaining, i, i+1)
+		} else {
+			diff.Added = append(diff.Added, name)
+		}
+	}
+	if len(remaining) > 0 {
+		diff.Removed = remaining
+	}
+	return diff
+}
+
+func itemNames(items []*world.Item) []string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+	return names
+}
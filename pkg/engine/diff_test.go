@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "interrupt.json"))
+	if _, err := engine.Take(ctx, "lamp"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if _, err := engine.Traverse(ctx, "north"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+
+	diff, err := engine.Diff(0)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	rooms := []RoomItemsDiff{{RoomName: "hall", ItemsDiff: ItemsDiff{Removed: []string{"lamp"}}}}
+	if !slices.EqualFunc(diff.Result.Rooms, rooms, func(a, b RoomItemsDiff) bool {
+		return a.RoomName == b.RoomName && slices.Equal(a.Added, b.Added) && slices.Equal(a.Removed, b.Removed)
+	}) {
+		t.Errorf("Expected the lamp to be gone from the hall, got %+v", diff.Result.Rooms)
+	}
+	if !slices.Equal(diff.Result.Inventory.Added, []string{"lamp"}) || diff.Result.Inventory.Removed != nil {
+		t.Errorf("Expected the lamp in the inventory, got %+v", diff.Result.Inventory)
+	}
+	if diff.Result.PreviousRoom != "hall" || diff.Result.Health != nil {
+		t.Errorf("Expected to have come from the hall unhurt, got %q and %+v", diff.Result.PreviousRoom, diff.Result.Health)
+	}
+
+	diff, err = engine.Diff(1)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if diff.Result.Rooms != nil || diff.Result.Inventory.Added != nil || diff.Result.PreviousRoom != "hall" {
+		t.Errorf("Expected only the move since version 1, got %+v", diff.Result)
+	}
+
+	diff, err = engine.Diff(engine.StateVersion)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if diff.Result.Rooms != nil || diff.Result.Inventory.Added != nil || diff.Result.PreviousRoom != "" {
+		t.Errorf("Expected nothing to have changed since the current version, got %+v", diff.Result)
+	}
+	if _, err := engine.Diff(engine.StateVersion + 1); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Expected a version ahead of the game to be rejected, got %v", err)
+	}
+}
+
+func TestDiff_Restore(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "interrupt.json"))
+	snapshot := engine.Snapshot()
+	if _, err := engine.Take(ctx, "lamp"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	taken := engine.StateVersion
+	if _, err := engine.Restore(snapshot); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	diff, err := engine.Diff(taken)
+	if err != nil {
+		t.Fatalf("Expected versions from before the restore to be remembered, got %v", err)
+	}
+	if len(diff.Result.Rooms) != 1 || !slices.Equal(diff.Result.Rooms[0].Added, []string{"lamp"}) || !slices.Equal(diff.Result.Inventory.Removed, []string{"lamp"}) {
+		t.Errorf("Expected the lamp to be back in the hall, got %+v", diff.Result)
+	}
+}
+
+func TestDiff_Expired(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "interrupt.json"))
+	for i := range maxVersionHistory + 1 {
+		direction := "north"
+		if i%2 == 1 {
+			direction = "south"
+		}
+		if _, err := engine.Traverse(ctx, direction); err != nil {
+			t.Fatalf("Traverse failed: %v", err)
+		}
+	}
+
+	if _, err := engine.Diff(0); !errors.Is(err, ErrVersionExpired) || ErrorCodeOf(err) != ErrorCodeVersionExpired {
+		t.Errorf("Expected the first version to be forgotten, got %v", err)
+	}
+	if _, err := engine.Diff(engine.StateVersion - maxVersionHistory + 1); err != nil {
+		t.Errorf("Expected the last %d versions to be remembered, got %v", maxVersionHistory, err)
+	}
+}
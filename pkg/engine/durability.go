@@ -0,0 +1,31 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+)
+
+// wearItem wears down a weapon or tool the player used, if it can wear out.
+// An item that breaks gives way to its scrap in the inventory, if it leaves any.
+// Returns true if the item broke, and the scrap it left.
+func (e *Engine) wearItem(item *world.Item) (bool, *ItemInfo) {
+	if item.Durability == nil || !item.Durability.Wear() {
+		return false, nil
+	}
+	scrap := item.Durability.Scrap
+	if scrap == nil {
+		return true, nil
+	}
+	e.Player.RemoveItem(item.Ref())
+	e.Player.Inventory = append(e.Player.Inventory, scrap)
+	scrapInfo := e.createItemInfo(scrap)
+	scrapInfo.Location = "inventory"
+	return true, &scrapInfo
+}
+
+// checkNotBroken returns an error if an item is too worn out to use.
+func checkNotBroken(item *world.Item) error {
+	if item.IsBroken() {
+		return world.Errorf(ErrBroken, "the %s is broken", item.Name)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBattle_WeaponWearsOut(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "durability.json"))
+	engine.Rng = &FakeRng{Value: 0.1}
+
+	if _, err := engine.Take(ctx, "knife"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	battle, err := engine.Battle(ctx, "knife")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if battle.Result.BrokenItem != "" {
+		t.Errorf("Expected the knife to last a round, got %+v", battle.Result)
+	}
+	inventory, err := engine.inventoryInternal()
+	if err != nil {
+		t.Fatalf("Inventory failed: %v", err)
+	}
+	if knife := inventory.Items[0]; knife.Durability != 1 || knife.MaxDurability != 2 || knife.IsBroken {
+		t.Errorf("Expected the knife to have 1 of 2 uses left, got %+v", knife)
+	}
+
+	battle, err = engine.Battle(ctx, "knife")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if battle.Result.BrokenItem != "knife" || battle.Result.Scrap != nil {
+		t.Errorf("Expected the knife to break without scrap, got %+v", battle.Result)
+	}
+	if _, err := engine.Battle(ctx, "knife"); !errors.Is(err, ErrBroken) {
+		t.Errorf("Expected ErrBroken fighting with a broken knife, got %v", err)
+	}
+	if _, err := engine.Battle(ctx, "fists"); err != nil {
+		t.Errorf("Expected to fight on with fists, got %v", err)
+	}
+}
+
+func TestUse_ToolWearsOutIntoScrap(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "durability.json"))
+	for _, name := range []string{"crowbar", "rope"} {
+		if _, err := engine.Take(ctx, name); err != nil {
+			t.Fatalf("Take failed: %v", err)
+		}
+	}
+
+	// A tool that wears out is kept after use
+	use, err := engine.Use(ctx, "crowbar", "crate")
+	if err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if use.Result.BrokenItem != "" || !use.Result.IsComplete {
+		t.Errorf("Expected the crate to open and the crowbar to hold, got %+v", use.Result)
+	}
+	if _, err := engine.Player.GetItem("crowbar"); err != nil {
+		t.Fatalf("Expected the crowbar to stay in the inventory: %v", err)
+	}
+
+	use, err = engine.Use(ctx, "crowbar", "vent")
+	if err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if use.Result.BrokenItem != "crowbar" || use.Result.Scrap == nil || use.Result.Scrap.Name != "bent bar" {
+		t.Fatalf("Expected the crowbar to snap into a bent bar, got %+v", use.Result)
+	}
+	if _, err := engine.Player.GetItem("crowbar"); err == nil {
+		t.Error("Expected the crowbar to be gone from the inventory")
+	}
+
+	// The scrap can be combined like any other item
+	combine, err := engine.Combine(ctx, "bent bar", "rope")
+	if err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if combine.Result.CraftedItem.Name != "grapple" {
+		t.Errorf("Expected a grapple, got %+v", combine.Result.CraftedItem)
+	}
+}
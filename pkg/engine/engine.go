@@ -0,0 +1,2895 @@
+// Package engine plays a level: it takes player actions such as Take, Traverse and Battle,
+// enforces the rules, and returns each action's result with the engine state. Create an engine
+// for a level loaded by the loader package with NewEngine. Failed actions return errors wrapping
+// one of the Err kinds, and Plugins add mechanics without changing the engine.
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"context"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+)
+
+// Engine contains all live game state and logic for a single level.
+type Engine struct {
+	Level                *world.Level
+	Player               *world.Player
+	CurrentFloor         *world.Floor
+	CurrentRoom          *world.Room
+	FightingEnemy        *world.Enemy
+	Rng                  Rng
+	LevelCompletionState LevelCompletionState
+	Mode                 Mode
+	ValidationDisabled   bool
+	TurnHistory          bool                        // whether recent turns are remembered for Rewind, which costs a snapshot a turn
+	Corrupted            error                       // why the game state can't be trusted any more, once it can't
+	MinimapData          map[string]*MinimapDoorInfo // door name -> minimap info
+	Stats                Stats
+	Telemetry            Telemetry
+	FoundSecrets         map[string]bool            // secret item name -> found
+	TakenItems           map[string]bool            // item name -> taken by a player at some point
+	LearnedCodes         map[string]bool            // keypad code -> read by a player in a note or narrative
+	KnownRecipes         map[string]bool            // recipe output item name -> discovered by a player
+	RevealedDoors        map[string]bool            // hidden door name -> revealed during play
+	EnemySightings       map[string]string          // enemy name -> room where the player encountered it
+	ObjectiveStatuses    map[string]ObjectiveStatus // objective name -> status, once changed by an event
+	RoomNotes            map[string]string          // room name -> the players' note on it on the minimap
+	DoorNotes            map[string]string          // door name -> the players' note on it on the minimap
+	StateVersion         uint64                     // incremented whenever an action changes the game state
+	Language             string                     // language of the level's text in results, empty for the level's own
+	Checkpoint           *Snapshot                  // taken when a player last entered a save point room, to respawn at after dying
+	Death                *PostMortem                // how the player died, once the level has failed
+	Fight                *CombatLog                 // the rounds of the current fight, or of the last one once it is over
+	CombatMomentum       float64                    // damage dealt in the fight so far towards the next round won, under deterministic combat
+	LossStreakOdds       float64                    // chance of losing the rounds the player has lost in a row in the fight, 1 once they win one
+	// Multiplayer sessions keep every player in Players, in join order. The active player's
+	// state is held in Player, CurrentFloor, CurrentRoom, FightingEnemy and Mode above.
+	Players      []*PlayerState
+	ActivePlayer string
+	TurnPolicy   TurnPolicy
+	DeathPolicy  DeathPolicy
+	Combat       CombatResolver // decides who wins rounds of battle, set from the level's combat model
+	NextPlayer   int            // index in Players of the player whose turn it is under round robin turns
+	// Plugins add custom mechanics, called in order; see Plugin.
+	Plugins []Plugin
+
+	soundCues     []SoundCue                      // played by the last action, handed out with the engine state info
+	notifications []EngineStateChangeNotification // raised by the last action, in the order they happened
+	ctx           context.Context                 // context of the action in progress; nil outside actions
+	interruption  error                           // why the action in progress was cut short, if it was
+	eventChain    []world.Event                   // events being handled, outermost first
+	triggerLoop   []world.Event                   // the events of the last trigger loop stopped, for debugging
+	versions      []*versionRecord                // the game state at recent state versions, oldest first, for Diff
+	turns         []*turnRecord                   // the game state as of recent turns, oldest first, for Rewind
+	debugLog      []DebugLogEntry                 // the engine's recent decisions, oldest first, for Debug
+}
+
+// NewEngine creates a new engine for a level.
+// The engine plays its own copy of the level, so a loaded level is never changed by play and can
+// start any number of engines.
+func NewEngine(
+	level *world.Level,
+) *Engine {
+	level = level.Clone()
+	engine := &Engine{
+		Level: level,
+		Player: &world.Player{
+			Inventory: make([]*world.Item, 0),
+			Health:    world.HealthState(world.HealthFine),
+			Ammo:      make(map[string]int),
+		},
+		CurrentFloor:         level.Floors[0],
+		CurrentRoom:          level.GetRoom(level.Floors[0].Name, level.Floors[0].Rooms[0].Name),
+		FightingEnemy:        nil,
+		Rng:                  &DefaultRng{},
+		LevelCompletionState: LevelCompletionStateInProgress,
+		Mode:                 Investigation,
+		ValidationDisabled:   false,
+		MinimapData:          make(map[string]*MinimapDoorInfo),
+		Telemetry:            newTelemetry(),
+		FoundSecrets:         make(map[string]bool),
+		TakenItems:           make(map[string]bool),
+		LearnedCodes:         make(map[string]bool),
+		KnownRecipes:         make(map[string]bool),
+		RevealedDoors:        make(map[string]bool),
+		EnemySightings:       make(map[string]string),
+		ObjectiveStatuses:    make(map[string]ObjectiveStatus),
+		RoomNotes:            make(map[string]string),
+		DoorNotes:            make(map[string]string),
+		ActivePlayer:         HostPlayerID,
+		TurnPolicy:           TurnsFree,
+		DeathPolicy:          DeathPermadeath,
+		Combat:               NewCombatResolver(level.Combat),
+		LossStreakOdds:       1,
+	}
+
+	engine.initializeMinimapData()
+
+	return engine
+}
+
+// Current "mode" of the game.
+// Investigation is the default mode.
+// Certain events (e.g. entering a room) may trigger a switch to Combat.
+type Mode string
+
+const (
+	Investigation Mode = "investigation"
+	Combat        Mode = "combat"
+)
+
+// LevelCompletionState indicates the completion state of the current level.
+type LevelCompletionState string
+
+const (
+	LevelCompletionStateInProgress LevelCompletionState = "in_progress"
+	LevelCompletionStateComplete   LevelCompletionState = "complete"
+	LevelCompletionStateFailed     LevelCompletionState = "failed"
+)
+
+// EngineStateChangeNotification indicates a state change in the engine.
+type EngineStateChangeNotification string
+
+const (
+	EngineStateChangeLevelComplete EngineStateChangeNotification = "level_complete"
+	EngineStateChangeLevelFailed   EngineStateChangeNotification = "level_failed"
+	EngineStateChangeEnterCombat   EngineStateChangeNotification = "enter_combat"
+	EngineStateChangeExitCombat    EngineStateChangeNotification = "exit_combat"
+	EngineStateChangeSecretFound   EngineStateChangeNotification = "secret_discovered"
+	EngineStateChangeRespawned     EngineStateChangeNotification = "respawned"
+)
+
+// stateChangePriority orders the state change notifications from most to least important.
+// When an action causes several, the most important is reported on its own for clients that
+// only handle one.
+var stateChangePriority = []EngineStateChangeNotification{
+	EngineStateChangeLevelComplete,
+	EngineStateChangeLevelFailed,
+	EngineStateChangeRespawned,
+	EngineStateChangeEnterCombat,
+	EngineStateChangeExitCombat,
+	EngineStateChangeSecretFound,
+}
+
+// mostImportantNotification returns the most important notification raised by the action in
+// progress, or nil if it raised none.
+func (e *Engine) mostImportantNotification() *EngineStateChangeNotification {
+	if len(e.notifications) == 0 {
+		return nil
+	}
+	stateChange := slices.MinFunc(e.notifications, func(a, b EngineStateChangeNotification) int {
+		return slices.Index(stateChangePriority, a) - slices.Index(stateChangePriority, b)
+	})
+	return &stateChange
+}
+
+// runEffect runs a triggered effect.
+// Returns a state change notification if applicable.
+func (e *Engine) runEffect(effect *world.Effect) *EngineStateChangeNotification {
+	e.debugf(DebugLogEffect, "%s runs in the %s", describeEffect(effect), e.CurrentRoom.Name)
+	switch effect.EffectType {
+	case world.EffectEnterCombat:
+		if enemy := e.Level.GetEnemy(effect.EnemyName); enemy != nil && !enemy.IsHostile() {
+			// Already killed by a blast aimed at another enemy, or surrendered
+			e.debugf(DebugLogEffect, "%s does nothing: the %s is %s", describeEffect(effect), enemy.Name, enemy.State())
+			return nil
+		}
+		e.Mode = Combat
+		e.FightingEnemy = e.Level.GetEnemy(effect.EnemyName)
+		e.CombatMomentum = 0
+		e.LossStreakOdds = 1
+		e.startFight(e.FightingEnemy)
+		e.EnemySightings[effect.EnemyName] = e.CurrentRoom.Name
+		stateChange := EngineStateChangeEnterCombat
+		return &stateChange
+	case world.EffectEndCombat:
+		// The enemy is driven off: its triggers no longer fire
+		e.Level.Triggers = slices.DeleteFunc(slices.Clone(e.Level.Triggers), func(trigger *world.Trigger) bool {
+			return trigger.EffectType == world.EffectEnterCombat && trigger.Effect.EnemyName == effect.EnemyName
+		})
+		if e.Mode == Combat && e.FightingEnemy != nil && e.FightingEnemy.Name == effect.EnemyName {
+			e.Mode = Investigation
+			e.FightingEnemy = nil
+			stateChange := EngineStateChangeExitCombat
+			return &stateChange
+		}
+	case world.EffectUnlock:
+		if e.Level.HasDoor(effect.TargetName) {
+			door := e.Level.GetDoor(effect.TargetName)
+			if door.Lock != nil {
+				door.Lock.Locked = false
+				door.Lock.Jammed = false
+			}
+			if door.Latch != nil {
+				door.Latch.Locked = false
+			}
+			if info, exists := e.MinimapData[door.Name]; exists && !info.Hidden {
+				e.updateMinimapForDoor(door.Name, false)
+			}
+			return nil
+		}
+		for _, item := range e.levelItems() {
+			if item.Name == effect.TargetName && item.IsContainer() && item.Container.HasLock() {
+				item.Container.Locked.Locked = false
+				item.Container.Locked.Jammed = false
+			}
+		}
+	case world.EffectRevealDoor:
+		return e.revealDoor(effect.TargetName)
+	case world.EffectPower:
+		for _, room := range e.Level.TravelNodes() {
+			if room.TravelNode.Name == effect.TargetName {
+				room.TravelNode.Unpowered = false
+			}
+		}
+	case world.EffectCompleteLevel:
+		e.LevelCompletionState = LevelCompletionStateComplete
+		stateChange := EngineStateChangeLevelComplete
+		return &stateChange
+	case world.EffectDamage:
+		e.hurtPlayer(effect.TargetName)
+	case world.EffectPoison:
+		e.poison(effect.TargetName)
+	default:
+		if effect.IsCustom() {
+			return e.runCustomEffect(effect)
+		}
+	}
+	return nil
+}
+
+// runFixtureEffects runs the effects of a fixture completed in the current room.
+func (e *Engine) runFixtureEffects(event *world.Event) {
+	fixture, err := e.CurrentRoom.GetItem(event.FixtureName)
+	if err != nil || !fixture.IsFixture() {
+		return
+	}
+	for i := range fixture.Fixture.OnComplete {
+		e.notify(e.runEffect(&fixture.Fixture.OnComplete[i]))
+	}
+}
+
+// revealDoor reveals a hidden door, which counts as discovering a secret.
+// Returns a secret discovered notification, or nil if the door was not hidden.
+func (e *Engine) revealDoor(doorName string) *EngineStateChangeNotification {
+	if !e.Level.HasDoor(doorName) || !e.Level.GetDoor(doorName).Hidden {
+		return nil
+	}
+	e.Level.GetDoor(doorName).Hidden = false
+	e.RevealedDoors[doorName] = true
+	e.Stats.SecretsFound++
+	e.updateMinimapDataForCurrenRoom()
+	stateChange := EngineStateChangeSecretFound
+	return &stateChange
+}
+
+// processTriggers runs the effects of the triggers an event matches. Only the first enemy
+// it sets off attacks.
+func (e *Engine) processTriggers(event *world.Event) {
+	for _, trigger := range e.Level.Triggers {
+		if trigger.Event.Event != event.Event {
+			continue
+		}
+		matched := false
+		switch trigger.Event.Event {
+		case world.EventItemTaken:
+			matched = trigger.Event.ItemName == event.ItemName
+		case world.EventRoomEntered:
+			matched = trigger.Event.RoomName == event.RoomName
+		case world.EventFixture:
+			matched = trigger.Event.FixtureName == event.FixtureName
+		case world.EventLockJammed:
+			matched = trigger.Event.LockName == event.LockName
+		case world.EventEnemyKilled:
+			matched = trigger.Event.EnemyName == event.EnemyName
+		}
+		if !matched {
+			continue
+		}
+		if trigger.EffectType == world.EffectEnterCombat && e.Mode == Combat {
+			e.debugf(DebugLogTrigger, "%s passed over on %s: already in combat", describeEffect(&trigger.Effect), describeEvent(event))
+			continue
+		}
+		e.debugf(DebugLogTrigger, "%s matched on %s", describeEffect(&trigger.Effect), describeEvent(event))
+		e.notify(e.runEffect(&trigger.Effect))
+	}
+}
+
+// processWinCondition completes the level if an event matches the win condition. A room
+// the player is ambushed in on entering is not reached until they win the fight.
+func (e *Engine) processWinCondition(event *world.Event) {
+	if e.Level.WinCondition == nil || e.Level.WinCondition.Event != event.Event {
+		return
+	}
+	switch event.Event {
+	case world.EventRoomEntered:
+		if e.Level.WinCondition.RoomName != event.RoomName || e.Mode == Combat {
+			return
+		}
+	case world.EventEnemyKilled:
+		if e.Level.WinCondition.EnemyName != event.EnemyName {
+			return
+		}
+	default:
+		return
+	}
+	e.LevelCompletionState = LevelCompletionStateComplete
+	stateChange := EngineStateChangeLevelComplete
+	e.notify(&stateChange)
+}
+
+// handleEnemyKilled ends the fight with the enemy the player killed. Enemies killed by a blast
+// aimed at another leave the fight going.
+func (e *Engine) handleEnemyKilled(event *world.Event) {
+	if e.FightingEnemy != nil && e.FightingEnemy.Name != event.EnemyName {
+		return
+	}
+	e.Mode = Investigation
+	e.FightingEnemy = nil
+	stateChange := EngineStateChangeExitCombat
+	e.notify(&stateChange)
+}
+
+// handlePlayerKilled fails the level when the player is killed, unless the session respawns
+// players.
+func (e *Engine) handlePlayerKilled(event *world.Event) {
+	if e.DeathPolicy == DeathRespawn {
+		e.notify(e.respawn())
+		return
+	}
+	e.recordDeath(event)
+	e.LevelCompletionState = LevelCompletionStateFailed
+	stateChange := EngineStateChangeLevelFailed
+	e.notify(&stateChange)
+}
+
+// EngineStateInfo contains general engine state info.
+type EngineStateInfo struct {
+	LevelCompletionState          LevelCompletionState
+	CurrentLevel                  *world.Level
+	CurrentFloor                  *world.Floor
+	CurrentRoom                   *world.Room
+	Mode                          Mode
+	PlayerHealth                  world.HealthState
+	EngineStateChangeNotification *EngineStateChangeNotification  // the most important of the notifications
+	Notifications                 []EngineStateChangeNotification // every state change the action caused, in the order they happened
+	FightingEnemy                 *world.Enemy
+	OutroNarrative                string
+	FailureNarrative              string          // set once the level has failed
+	PostMortem                    *PostMortem     // set once the level has failed
+	Score                         *ScoreSummary   // set once the level is complete
+	Stats                         *StatsSummary   // set once the level is complete
+	Victory                       *VictorySummary // what the player left undone, set once the level is complete
+	Objectives                    []ObjectiveInfo // authored objectives revealed so far
+	StateVersion                  uint64
+	Player                        string       // ID of the acting player, set in multiplayer sessions
+	NextPlayer                    string       // ID of the player whose turn it is, set under round robin turns
+	Ambient                       string       // the current room's ambient sound
+	SoundCues                     []SoundCue   // sounds played by the action, in the order they happened
+	Breath                        *BreathInfo  // set while the player is in an airless room
+	RespawnRoom                   string       // the room the player respawns in should they die, set if the session respawns players
+	CanRespawn                    bool         // true once the level has failed if Respawn can take the player back to a save point
+	AllowedActions                []string     // the actions the player can take now; empty once the level is over
+	Weapons                       []WeaponInfo // in combat, the weapons the player can fight with
+	Armor                         []ArmorInfo  // the armor the player wears
+	Exploration                   *ExplorationInfo
+}
+
+// --- public wrapper results ---
+
+type ObserveResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          observeResultInternal
+}
+
+type InspectResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          inspectResultInternal
+}
+
+type UncoverResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          uncoverResultInternal
+}
+
+type UnlockResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          unlockResultInternal
+}
+
+type SearchResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          searchResultInternal
+}
+
+type TakeResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          takeResultInternal
+}
+
+type InventoryResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          inventoryResultInternal
+}
+
+type HealResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          healResultInternal
+}
+
+type TraverseResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          traverseResultInternal
+}
+
+type BattleResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          battleResultInternal
+}
+
+type CombineResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          combineResultInternal
+}
+
+type UseResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          useResultInternal
+}
+
+type MinimapResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          minimapResultInternal
+}
+
+// getEngineStateInfo returns the current engine state info.
+func (e *Engine) getEngineStateInfo() *EngineStateInfo {
+	engineStateInfo := EngineStateInfo{
+		LevelCompletionState:          e.LevelCompletionState,
+		Mode:                          e.Mode,
+		CurrentLevel:                  e.Level,
+		CurrentFloor:                  e.CurrentFloor,
+		CurrentRoom:                   e.CurrentRoom,
+		PlayerHealth:                  e.Player.Health,
+		Objectives:                    e.visibleObjectives(),
+		StateVersion:                  e.StateVersion,
+		NextPlayer:                    e.nextPlayerID(),
+		Ambient:                       e.CurrentRoom.Ambient,
+		SoundCues:                     e.soundCues,
+		Breath:                        e.breathInfo(),
+		AllowedActions:                e.allowedActions(),
+		Weapons:                       e.usableWeapons(),
+		Armor:                         e.wornArmor(),
+		Exploration:                   e.explorationInfo(),
+		EngineStateChangeNotification: e.mostImportantNotification(),
+		Notifications:                 e.notifications,
+	}
+	if e.FightingEnemy != nil {
+		enemy := *e.FightingEnemy
+		enemy.Description = e.localize(enemy.Description)
+		engineStateInfo.FightingEnemy = &enemy
+	}
+	if len(e.Players) > 0 {
+		engineStateInfo.Player = e.ActivePlayer
+	}
+	if e.DeathPolicy == DeathRespawn {
+		_, respawnRoom := e.respawnRoom()
+		engineStateInfo.RespawnRoom = respawnRoom.Name
+	}
+	engineStateInfo.CanRespawn = e.LevelCompletionState == LevelCompletionStateFailed && e.Checkpoint != nil
+	if e.LevelCompletionState == LevelCompletionStateFailed {
+		engineStateInfo.FailureNarrative = e.localize(e.Level.FailureNarrative)
+		engineStateInfo.PostMortem = e.Death
+	}
+	if e.LevelCompletionState == LevelCompletionStateComplete {
+		engineStateInfo.OutroNarrative = e.localize(e.Level.OutroNarrative)
+		engineStateInfo.Score = e.computeScore()
+		engineStateInfo.Stats = e.computeStats()
+		engineStateInfo.Victory = e.victorySummary()
+	}
+	return &engineStateInfo
+}
+
+// checkInvariants returns an ErrCorrupted error if the game state is one the engine should never
+// have got into, marking the engine corrupted. A corrupted engine fails every validated action
+// with the same error until a snapshot from before the corruption is restored.
+func (e *Engine) checkInvariants() error {
+	switch {
+	case e.Corrupted != nil:
+		return e.Corrupted
+	case e.Mode == Combat && e.FightingEnemy == nil:
+		return e.corrupt("cannot be in combat mode without a fighting enemy")
+	case e.Mode == Investigation && e.FightingEnemy != nil:
+		return e.corrupt("cannot be in investigation mode while fighting an enemy")
+	case !e.Player.IsAlive() && e.LevelCompletionState != LevelCompletionStateFailed:
+		return e.corrupt("level completion state must be failed when the player is dead")
+	}
+	return nil
+}
+
+// corrupt marks the engine corrupted by a game state that should be impossible.
+// Returns the error the engine fails actions with from then on.
+func (e *Engine) corrupt(format string, args ...any) error {
+	e.Corrupted = world.Errorf(ErrCorrupted, format, args...)
+	return e.Corrupted
+}
+
+// MarkCorrupted marks the engine corrupted by something that went wrong outside the engine's
+// own checks, such as a panic while it was playing an action.
+func (e *Engine) MarkCorrupted(reason string) {
+	e.corrupt("%s", reason)
+}
+
+func (e *Engine) checkLevelComplete() error {
+	if e.LevelCompletionState == LevelCompletionStateComplete {
+		return world.Errorf(ErrLevelOver, "level is already complete")
+	}
+	if e.Player.Health == world.HealthDead {
+		return world.Errorf(ErrLevelOver, "player is dead")
+	}
+	return nil
+}
+
+func (e *Engine) ensureCombatMode() error {
+	if e.Mode != Combat {
+		return world.Errorf(ErrWrongMode, "cannot perform this action in investigation mode")
+	}
+	return nil
+}
+
+func (e *Engine) ensureInvestigationMode() error {
+	if e.Mode != Investigation {
+		return world.Errorf(ErrWrongMode, "cannot perform this action in combat mode")
+	}
+	return nil
+}
+
+// --- allowed actions validation ---
+//
+// These functions validate the engine state for specific actions.
+// Certain actions are only allowed in certain modes.
+//
+// The following actions are allowed in all modes:
+// - Observe
+// - Inventory
+// - Heal
+//
+// The following actions are allowed in investigation mode:
+// - Inspect
+// - Uncover
+// - Unlock
+// - Search
+// - Take
+// - Traverse
+// - Combine
+// - Listen
+// - Peek
+// - Latch
+//
+// The following actions are allowed in combat mode:
+// - Battle
+// - Intimidate
+// - Bribe
+// - Sneak
+
+// validateEngineState validates the engine state for all actions.
+func (e *Engine) validateEngineState() error {
+	if err := e.checkInvariants(); err != nil {
+		return err
+	}
+	if err := e.checkLevelComplete(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateTurn validates the engine state for actions that take a turn.
+func (e *Engine) validateTurn() error {
+	if err := e.validateEngineState(); err != nil {
+		return err
+	}
+	return e.checkTurn()
+}
+
+// validateEngineStateForTurn validates the engine state for actions that take a turn in any mode.
+// Once the action is allowed, the plugins may still refuse it.
+func (e *Engine) validateEngineStateForTurn(action Action) error {
+	err := e.validateTurn()
+	if err == nil {
+		err = e.beforeAction(action)
+	}
+	return e.checkAction(action, err)
+}
+
+// validateEngineStateForInvestigationActions validates the engine state for investigation actions.
+func (e *Engine) validateEngineStateForInvestigationActions(action Action) error {
+	err := e.validateTurn()
+	if err == nil {
+		err = e.ensureInvestigationMode()
+	}
+	if err == nil {
+		err = e.beforeAction(action)
+	}
+	return e.checkAction(action, err)
+}
+
+// validateEngineStateForCombatActions validates the engine state for combat actions.
+func (e *Engine) validateEngineStateForCombatActions(action Action) error {
+	err := e.validateTurn()
+	if err == nil {
+		err = e.ensureCombatMode()
+	}
+	if err == nil {
+		err = e.beforeAction(action)
+	}
+	return e.checkAction(action, err)
+}
+
+// --- public wrapper methods ---
+// These wrappers add event handling to the underlying internal methods, and run them under
+// the caller's context.
+// For the time being, not all internal methods generate events.
+
+// Observe observes the current room, listing the items the filter lets through.
+// Returns an ObserveResult and engine state info.
+func (e *Engine) Observe(ctx context.Context, filter ObserveFilter) (*ObserveResult, error) {
+	return act(e, ctx, "observe", func() (*ObserveResult, error) {
+		if !e.ValidationDisabled {
+			if err := e.validateEngineState(); err != nil {
+				return nil, err
+			}
+		}
+		observeResult, err := e.observeInternal(filter)
+		if err != nil {
+			return nil, err
+		}
+		return &ObserveResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *observeResult,
+		}, nil
+	})
+}
+
+// Inspect inspects an item or door by name.
+// Returns an InspectResult and engine state info.
+func (e *Engine) Inspect(ctx context.Context, name string) (*InspectResult, error) {
+	return act(e, ctx, "inspect", func() (*InspectResult, error) {
+		action := Action{Name: "inspect", Args: []string{name}}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		inspectResult, err := e.inspectInternal(name)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		return &InspectResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *inspectResult,
+		}, nil
+	})
+}
+
+// Uncover uncovers an item by name.
+// Returns an UncoverResult and engine state info.
+func (e *Engine) Uncover(ctx context.Context, name string) (*UncoverResult, error) {
+	return act(e, ctx, "uncover", func() (*UncoverResult, error) {
+		action := Action{Name: "uncover", Args: []string{name}}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		uncoverResult, err := e.uncoverInternal(name)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		return &UncoverResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *uncoverResult,
+		}, nil
+	})
+}
+
+// Unlock unlocks a door by name.
+// A wrong code that jams a keypad is not an error: it handles the lockout event, possibly
+// triggering a state change.
+// Returns an UnlockResult and engine state info with state change notifications, if applicable.
+func (e *Engine) Unlock(ctx context.Context, keyNameOrCode string, targetName string) (*UnlockResult, error) {
+	return act(e, ctx, "unlock", func() (*UnlockResult, error) {
+		action := Action{Name: "unlock", Args: []string{keyNameOrCode, targetName}}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		unlockResult, err := e.unlockInternal(keyNameOrCode, targetName)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		if unlockResult.Jammed {
+			e.publish(&world.Event{
+				Event:    world.EventLockJammed,
+				LockName: unlockResult.Target,
+			})
+		}
+		return &UnlockResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *unlockResult,
+		}, nil
+	})
+}
+
+// Search searches a container, or an enemy that has surrendered, by name.
+// Returns a SearchResult and engine state info.
+func (e *Engine) Search(ctx context.Context, name string) (*SearchResult, error) {
+	return act(e, ctx, "search", func() (*SearchResult, error) {
+		action := Action{Name: "search", Args: []string{name}}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		searchResult, err := e.searchInternal(name)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		return &SearchResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *searchResult,
+		}, nil
+	})
+}
+
+// Take takes an item by name, all of it if it is a stack.
+// Publishes the event, possibly triggering state changes.
+// Returns a TakeResult and engine state info with state change notifications, if applicable.
+func (e *Engine) Take(ctx context.Context, name string) (*TakeResult, error) {
+	return e.TakeQuantity(ctx, name, 0)
+}
+
+// TakeQuantity takes some of a stack of items, such as 2 of 5 coins, leaving the rest where
+// they are. A quantity of 0 takes the whole stack.
+func (e *Engine) TakeQuantity(ctx context.Context, name string, quantity int) (*TakeResult, error) {
+	return act(e, ctx, "take", func() (*TakeResult, error) {
+		action := Action{Name: "take", Args: []string{name}}
+		if quantity != 0 {
+			action.Args = append(action.Args, strconv.Itoa(quantity))
+		}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		takeResult, err := e.takeInternal(name, quantity)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		e.publish(&world.Event{
+			Event:    world.EventItemTaken,
+			ItemName: takeResult.ItemInfo.Name,
+		})
+		return &TakeResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *takeResult,
+		}, nil
+	})
+}
+
+// Inventory returns the player's inventory.
+// Returns an InventoryResult and engine state info.
+func (e *Engine) Inventory(ctx context.Context) (*InventoryResult, error) {
+	return act(e, ctx, "inventory", func() (*InventoryResult, error) {
+		if !e.ValidationDisabled {
+			if err := e.validateEngineState(); err != nil {
+				return nil, err
+			}
+		}
+		inventoryResult, err := e.inventoryInternal()
+		if err != nil {
+			return nil, err
+		}
+		return &InventoryResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *inventoryResult,
+		}, nil
+	})
+}
+
+// Heal heals the player with a health item, or lets them breathe from an air supply, by name.
+// Returns a HealResult and engine state info.
+func (e *Engine) Heal(ctx context.Context, name string) (*HealResult, error) {
+	return act(e, ctx, "heal", func() (*HealResult, error) {
+		action := Action{Name: "heal", Args: []string{name}}
+		if err := e.validateEngineStateForTurn(action); err != nil {
+			return nil, err
+		}
+		healResult, err := e.healInternal(name)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		return &HealResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *healResult,
+		}, nil
+	})
+}
+
+// Traverse traverses to a destination room.
+// Publishes the event, possibly triggering state changes.
+// Returns a TraverseResult and engine state info with state change notifications, if applicable.
+func (e *Engine) Traverse(ctx context.Context, destination string) (*TraverseResult, error) {
+	return act(e, ctx, "traverse", func() (*TraverseResult, error) {
+		action := Action{Name: "traverse", Args: []string{destination}}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		traverseResult, err := e.traverseInternal(destination)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		e.publish(&world.Event{
+			Event:    world.EventRoomEntered,
+			RoomName: traverseResult.EnteredRoom.RoomName,
+		})
+		return &TraverseResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *traverseResult,
+		}, nil
+	})
+}
+
+// Battle battles an enemy.
+// Publishes the event, possibly triggering state changes.
+// Returns a BattleResult and engine state info with state change notifications, if applicable.
+func (e *Engine) Battle(ctx context.Context, weaponName string) (*BattleResult, error) {
+	return act(e, ctx, "battle", func() (*BattleResult, error) {
+		action := Action{Name: "battle", Args: []string{weaponName}}
+		if err := e.validateEngineStateForCombatActions(action); err != nil {
+			return nil, err
+		}
+		battleResult, err := e.battleInternal(weaponName)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		for _, enemyName := range battleResult.AlsoKilled {
+			e.publish(&world.Event{
+				Event:     world.EventEnemyKilled,
+				EnemyName: enemyName,
+			})
+		}
+		if !battleResult.EnemyAlive || battleResult.Surrendered {
+			e.publish(&world.Event{
+				Event:     world.EventEnemyKilled,
+				EnemyName: battleResult.EnemyName,
+			})
+		}
+		if !battleResult.PlayerAlive {
+			e.publish(&world.Event{
+				Event:     world.EventPlayerKilled,
+				EnemyName: battleResult.EnemyName,
+			})
+		}
+		return &BattleResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *battleResult,
+		}, nil
+	})
+}
+
+// Combine crafts a new item by combining two to four input items.
+// Returns a CombineResult and engine state info.
+func (e *Engine) Combine(ctx context.Context, inputItemNames ...string) (*CombineResult, error) {
+	return act(e, ctx, "combine", func() (*CombineResult, error) {
+		action := Action{Name: "combine", Args: inputItemNames}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		if len(inputItemNames) < 2 || len(inputItemNames) > world.MaxComboInputs {
+			return nil, world.Errorf(ErrInvalidArgument, "combine takes 2 to %d items, not %d", world.MaxComboInputs, len(inputItemNames))
+		}
+		combineResult, err := e.combineInternal(inputItemNames...)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		return &CombineResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *combineResult,
+		}, nil
+	})
+}
+
+func (e *Engine) Use(ctx context.Context, itemName string, targetName string) (*UseResult, error) {
+	return act(e, ctx, "use", func() (*UseResult, error) {
+		action := Action{Name: "use", Args: []string{itemName, targetName}}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		useResult, err := e.useInternal(itemName, targetName)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		if useResult.IsComplete {
+			e.publish(&world.Event{
+				Event:       world.EventFixture,
+				FixtureName: useResult.FixtureName,
+			})
+		}
+		return &UseResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *useResult,
+		}, nil
+	})
+}
+
+// Minimap returns minimap data for the current floor.
+// Returns a MinimapResult with engine state info.
+func (e *Engine) Minimap(ctx context.Context) (*MinimapResult, error) {
+	return act(e, ctx, "minimap", func() (*MinimapResult, error) {
+		if !e.ValidationDisabled {
+			if err := e.validateEngineState(); err != nil {
+				return nil, err
+			}
+		}
+
+		minimapResult, err := e.minimapInternal()
+		if err != nil {
+			return nil, err
+		}
+
+		return &MinimapResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *minimapResult,
+		}, nil
+	})
+}
+
+// --- internal helpers ---
+
+func (e *Engine) isItemInInventory(itemName string) bool {
+	for _, item := range e.Player.Inventory {
+		if item.Name == itemName {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// Set doors to visible in the minimap for the current room
+func (e *Engine) updateMinimapDataForCurrenRoom() {
+	for _, conn := range e.visibleConnections(e.CurrentRoom) {
+		e.MinimapData[conn.DoorName].Hidden = false
+	}
+}
+
+// Set all doors to hidden with unknown lock state
+func (e *Engine) initializeMinimapData() {
+	for _, door := range e.Level.Doors {
+		e.MinimapData[door.Name] = &MinimapDoorInfo{
+			Name:      door.Name,
+			RoomA:     door.RoomA,
+			RoomB:     door.RoomB,
+			Direction: e.Level.DoorDirection(door),
+			Locked:    nil,
+			Hidden:    true,
+		}
+	}
+	e.updateMinimapDataForCurrenRoom()
+}
+
+// updateMinimapForDoor updates the minimap data for a specific door
+func (e *Engine) updateMinimapForDoor(doorName string, locked bool) {
+	if info, exists := e.MinimapData[doorName]; exists {
+		info.Locked = &locked
+		info.Hidden = false
+	}
+}
+
+// ItemInfo contains the basic information about an item, excluding detail.
+type ItemInfo struct {
+	Name         string
+	ID           string // what actions can target the item by, even where others share its name
+	Description  string
+	Location     string
+	ImageRef     string
+	IsPortable   bool
+	IsContainer  bool
+	IsConcealer  bool
+	IsKey        bool
+	IsAmmoBox    bool
+	IsWeapon     bool
+	IsThrown     bool // a weapon used up when thrown, like a grenade
+	ArmorSlot    string
+	IsWorn       bool // armor the player wears
+	IsHealthItem bool
+	IsAirSupply  bool
+	IsFixture    bool
+	IsMoveable   bool
+	IsQuestItem  bool              // an item the player can't lose, as the level can't be won without it
+	Quantity     int               // how many of the item there are, more than 1 for a stack
+	SubLocation  world.SubLocation // where in the room the item is, zero for nowhere in particular
+
+	// Container-specific fields
+	HasKeyLock   bool
+	HasCodeLock  bool
+	IsLocked     bool
+	IsJammed     bool
+	AttemptsLeft int // wrong codes the keypad takes before it jams, 0 for no limit
+	IsSearched   bool
+	Contains     string
+
+	// Concealer-specific fields
+	IsUncovered bool
+
+	// Moveable-specific fields
+	IsMoved bool
+
+	// Fields for weapons and tools that wear out
+	Durability    int // uses left before it breaks
+	MaxDurability int // 0 if the item never wears out
+	IsBroken      bool
+
+	// Fields for items with several uses
+	Charges    int // uses left before it is used up
+	MaxCharges int // 0 if the item has no charges
+}
+
+type DoorInfo struct {
+	Name         string
+	Description  string
+	Location     string
+	HasKeyLock   bool
+	HasCodeLock  bool
+	IsLocked     bool
+	IsJammed     bool
+	AttemptsLeft int // wrong codes the keypad takes before it jams, 0 for no limit
+	IsStairwell  bool
+	IsBarred     bool
+	IsOneWay     bool // can only be gone through from one side, and not back
+	IsLatched    bool // latched from the other side
+	CanLatch     bool // the player can latch it from this side
+	LeadsTo      string
+}
+
+type FloorInfo struct {
+	Name        string
+	Description string
+}
+
+// ItemInspection contains the details of an inspected item.
+type ItemInspection struct {
+	ItemInfo
+	Detail         string
+	LearnedRecipes []string // outputs of the recipes the item taught
+}
+
+// DoorInspection contains the details of an inspected door.
+type DoorInspection struct {
+	DoorInfo
+}
+
+// AmmoCount contains the rounds of a type of ammo, displayed with inventory.
+type AmmoCount struct {
+	AmmoType  string
+	AmmoCount int
+}
+
+// createItemInfo creates an ItemInfo from a world item.
+func (e *Engine) createItemInfo(item *world.Item) ItemInfo {
+	result := ItemInfo{
+		Name:         item.Name,
+		ID:           item.Ref(),
+		Description:  e.localize(item.Description),
+		Location:     item.Location,
+		ImageRef:     item.ImageRef,
+		IsPortable:   item.IsPortable(),
+		IsContainer:  item.IsContainer(),
+		IsConcealer:  item.IsConcealer(),
+		IsKey:        item.IsKey(),
+		IsAmmoBox:    item.IsAmmoBox(),
+		IsWeapon:     item.IsWeapon(),
+		IsThrown:     item.IsWeapon() && item.Weapon.IsThrown(),
+		IsHealthItem: item.IsHealthItem(),
+		IsAirSupply:  item.IsAirSupply(),
+		IsFixture:    item.IsFixture(),
+		IsMoveable:   item.IsMoveable(),
+		IsQuestItem:  item.QuestItem,
+		Quantity:     item.Count(),
+		IsUncovered:  item.IsConcealer() && item.Concealer.Uncovered,
+		IsMoved:      item.IsMoveable() && item.Moveable.Moved,
+		IsBroken:     item.IsBroken(),
+	}
+	if item.SubLocation != "" {
+		result.SubLocation, _ = e.CurrentRoom.GetSubLocation(item.SubLocation)
+	}
+	if item.Charges != nil {
+		result.Charges = item.Charges.Remaining()
+		result.MaxCharges = item.Charges.Max
+	}
+	if item.Durability != nil {
+		result.Durability = item.Durability.Remaining()
+		result.MaxDurability = item.Durability.Max
+	}
+	if item.IsArmor() {
+		result.ArmorSlot = item.Armor.Slot
+		result.IsWorn = slices.Contains(e.Player.WornArmor(), item)
+	}
+
+	if item.IsContainer() {
+		result.HasKeyLock = item.Container.HasKeyLock()
+		result.HasCodeLock = item.Container.HasCodeLock()
+		result.IsLocked = item.Container.IsLocked()
+		result.IsJammed = item.Container.IsLocked() && item.Container.Locked.Jammed
+		result.AttemptsLeft = item.Container.Locked.AttemptsLeft()
+		if item.Container.Searched {
+			// Show a container's contents if it has been searched already
+			result.IsSearched = true
+			if !item.Container.IsEmpty() {
+				result.Contains = item.Container.Contains.Name
+			}
+		}
+	}
+	return result
+}
+
+// createDoorInfo creates an DoorInfo from a world door.
+func (e *Engine) createDoorInfo(door *world.Door) DoorInfo {
+	result := DoorInfo{
+		Name:        door.Name,
+		IsStairwell: door.Stairwell,
+		IsBarred:    door.Barred,
+		IsOneWay:    door.OneWay,
+		CanLatch:    door.CanLatch(e.CurrentRoom.Name),
+	}
+
+	// Get the connection from the current room to get room-specific description
+	if conn, err := e.CurrentRoom.GetConnection(door.Name); err == nil {
+		result.Description = e.localize(conn.Description)
+	}
+
+	// Only show lock and latch information if the door has been tried
+	if door.Tried {
+		result.HasKeyLock = door.HasKeyLock()
+		result.HasCodeLock = door.HasCodeLock()
+		result.IsLocked = door.IsLocked()
+		result.IsJammed = door.IsLocked() && door.Lock.Jammed
+		result.AttemptsLeft = door.Lock.AttemptsLeft()
+		result.IsLatched = door.IsLatched() && !door.CanUnlatch(e.CurrentRoom.Name)
+	}
+
+	if door.Traversed {
+		if e.CurrentRoom.Name == door.RoomA {
+			result.LeadsTo = door.RoomB
+		} else {
+			result.LeadsTo = door.RoomA
+		}
+	}
+
+	return result
+}
+
+type itemInRoomContainer struct {
+	ContainedItem  *world.Item
+	ContainingItem *world.Item
+}
+
+// findItemInRoomContainer finds an item by name in a searched container in the current room,
+// following containers nested in searched containers down to any depth.
+func (e *Engine) findItemInRoomContainer(name string) (*itemInRoomContainer, error) {
+	for _, item := range e.CurrentRoom.Items {
+		for container := item; container.IsContainer() && container.Container.Searched && !container.Container.IsEmpty(); container = container.Container.Contains {
+			if container.Container.Contains.Is(name) {
+				return &itemInRoomContainer{
+					ContainedItem:  container.Container.Contains,
+					ContainingItem: container,
+				}, nil
+			}
+		}
+	}
+	return nil, world.Errorf(ErrNotFound, "you don't see a %s here", name)
+}
+
+// findRoomItem finds an item by name in the current room or in one of its searched containers.
+func (e *Engine) findRoomItem(name string) (*world.Item, error) {
+	if item, err := e.CurrentRoom.GetItem(name); err == nil {
+		return item, nil
+	}
+	if result, err := e.findItemInRoomContainer(name); err == nil {
+		return result.ContainedItem, nil
+	}
+	return nil, world.Errorf(ErrNotFound, "you don't see a %s here", name)
+}
+
+// findItem finds an item by name.
+func (e *Engine) findItem(name string) (*world.Item, error) {
+	name, err := e.resolveItemName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the player's inventory first.
+	item, err := e.Player.GetItem(name)
+	if err == nil {
+		return item, nil
+	}
+
+	// If not found in inventory, check the current room.
+	item, err = e.CurrentRoom.GetItem(name)
+	if err == nil {
+		return item, nil
+	}
+
+	// If not found in room, check open containers in the room that have been searched.
+	result, err := e.findItemInRoomContainer(name)
+	if err == nil {
+		return result.ContainedItem, nil
+	}
+
+	return nil, world.Errorf(ErrNotFound, "you don't see a %s here", name)
+}
+
+// findDoorByName finds a door by name.
+func (e *Engine) findDoorByName(name string) (*world.Door, error) {
+	name, err := e.resolveDoorName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// First find the connection in the current room
+	conn, err := e.CurrentRoom.GetConnection(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Then find the actual door in the level, which may still be hidden
+	door := e.Level.GetDoor(conn.DoorName)
+	if door.Hidden {
+		return nil, world.Errorf(ErrNotFound, "no door named %s in this room", name)
+	}
+	return door, nil
+}
+
+func (e *Engine) useHealthItem(healthItem *world.Item) (world.HealthState, error) {
+	switch healthItem.HealthItem.HealthEffect {
+	case world.HealthBoostWeak:
+		e.Player.IncreaseHealth()
+	case world.HealthBoostStrong:
+		e.Player.Health = world.HealthState(world.HealthFine)
+	default:
+		return "", e.corrupt("the %s has an invalid health effect %q", healthItem.Name, healthItem.HealthItem.HealthEffect)
+	}
+	return e.Player.Health, nil
+}
+
+// validateKey validates that the item is in inventory and is a key.
+func (e *Engine) validateKey(keyName string) error {
+	key, err := e.Player.GetItem(keyName)
+	if err != nil {
+		return err
+	}
+	if !key.IsKey() {
+		return world.Errorf(ErrInvalidTarget, "the %s is not a key", keyName)
+	}
+	return nil
+}
+
+// findDoor finds a door of the current room by name or, failing that, by location or direction.
+func (e *Engine) findDoor(nameOrLocation string) (*world.Door, error) {
+	door, err := e.findDoorByName(nameOrLocation)
+	if isAmbiguous(err) {
+		return nil, err
+	}
+	if err != nil {
+		door, err = e.findDoorByLocation(nameOrLocation)
+		if err != nil {
+			return nil, world.Errorf(ErrNotFound, "no door named '%s' or no door to the '%s'", nameOrLocation, nameOrLocation)
+		}
+	}
+	return door, nil
+}
+
+// roomBehind returns the room on the other side of a door of the current room, and its floor.
+// Stairwell doors can lead to other floors, regular doors stay on the same floor.
+func (e *Engine) roomBehind(door *world.Door) (*world.Floor, *world.Room, error) {
+	roomName := door.RoomA
+	if door.RoomA == e.CurrentRoom.Name {
+		roomName = door.RoomB
+	}
+	if !door.Stairwell {
+		return e.CurrentFloor, e.Level.GetRoom(e.CurrentFloor.Name, roomName), nil
+	}
+	if floor, room := e.Level.FindRoom(roomName); room != nil {
+		return floor, room, nil
+	}
+	return nil, nil, e.corrupt("destination room %s not found on any floor", roomName)
+}
+
+// findDoorByLocation finds a door by location (e.g., "left", "ahead", "back", "right") or direction (e.g., "north").
+func (e *Engine) findDoorByLocation(location string) (*world.Door, error) {
+	for _, conn := range e.visibleConnections(e.CurrentRoom) {
+		if conn.Location == location || conn.Direction != "" && string(conn.Direction) == location {
+			// Find the actual door in the level
+			return e.Level.GetDoor(conn.DoorName), nil
+		}
+	}
+	return nil, world.Errorf(ErrNotFound, "no door to the %s", location)
+}
+
+// visibleConnections returns the connections of a room whose doors are not hidden.
+func (e *Engine) visibleConnections(room *world.Room) []*world.Connection {
+	var connections []*world.Connection
+	for _, conn := range room.Connections {
+		if !e.Level.GetDoor(conn.DoorName).Hidden {
+			connections = append(connections, conn)
+		}
+	}
+	return connections
+}
+
+// --- internal results ---
+
+// observeResultInternal is the result of observing the current room.
+type observeResultInternal struct {
+	RoomName        string
+	RoomDescription string
+	RoomImageRef    string
+	VisibleItems    []ItemInfo
+	Doors           []DoorInfo
+	TravelNode      *TravelNodeInfo // the room's fast travel point, if any
+	Airless         bool            // true if there is no air to breathe in the room
+	Burning         bool            // true if the room is on fire
+	NextCursor      string          // the cursor of the next page of items, empty on the last page
+}
+
+// inspectResultInternal contains the details of an inspected item or door.
+type inspectResultInternal struct {
+	*ItemInspection
+	*DoorInspection
+}
+
+// uncoverResultInternal is the result of uncovering a concealed item.
+type uncoverResultInternal struct {
+	Name         string
+	RevealedItem ItemInfo
+}
+
+// unlockResultInternal is the result of unlocking a container or door.
+type unlockResultInternal struct {
+	Target   string // the door or container
+	Unlocked bool
+	Jammed   bool // a wrong code jammed the keypad
+}
+
+// searchResultInternal is the result of searching a container.
+type searchResultInternal struct {
+	ContainerName     string
+	ContainedItemInfo *ItemInfo
+	Unlocked          bool
+}
+
+// takeResultInternal is the result of taking an item.
+type takeResultInternal struct {
+	ItemInfo ItemInfo
+	Trap     *TrapInfo // the trap the item sprang, if it had one
+}
+
+// inventoryResultInternal is the result of getting the player's inventory.
+type inventoryResultInternal struct {
+	Items []ItemInfo
+	Ammo  []AmmoCount
+}
+
+// healResultInternal is the result of healing the player.
+type healResultInternal struct {
+	Health      world.HealthState
+	Breathed    bool      // true if the player breathed from an air supply rather than healing
+	ChargesLeft int       // charges left on the item, for items with charges
+	MaxCharges  int       // 0 if the item has no charges
+	Cured       bool      // true if healing cured the player of poison
+	Trap        *TrapInfo // the trap the item sprang, if it had one
+}
+
+// traverseResultInternal is the result of traversing between rooms.
+type traverseResultInternal struct {
+	EnteredRoom  observeResultInternal
+	ChangedFloor *FloorInfo
+	Unlatched    bool
+	Unlocked     bool
+}
+
+// battleResultInternal is the result of battling an enemy.
+type battleResultInternal struct {
+	EnemyName   string
+	WonRound    bool
+	EnemyAlive  bool
+	PlayerAlive bool
+	BrokenItem  string        // the weapon, if the round wore it out
+	Scrap       *ItemInfo     // what the broken weapon left behind, if anything
+	Thrown      bool          // true if the weapon was thrown and used up
+	Critical    bool          // true if the round won was a critical hit, costing the enemy extra hit points
+	Fumbled     bool          // true if the player dropped the weapon, which now lies in the room
+	Narrative   string        // the weapon's narrative for the critical hit or fumble, if it has one
+	Pity        bool          // true if the round was won because the player had lost too many in a row
+	CombatLog   []CombatRound // the rounds of the fight so far, this one last
+	Surrendered bool          // true if the round broke the enemy's morale and it gave up
+	Absorbed    string        // the armor that stopped the enemy's hit, if any
+	BrokenArmor string        // the armor, if stopping the hit broke it
+	ArmorScrap  *ItemInfo     // what the broken armor left behind, if anything
+	AlsoHit     []string      // other enemies in the room caught in the blast of an area weapon
+	AlsoKilled  []string      // those of them the blast killed
+	StartedFire bool          // true if the throw set the room on fire
+}
+
+// combineResultInternal is the result of combining items.
+type combineResultInternal struct {
+	CraftedItem ItemInfo
+	Byproducts  []ItemInfo
+	NewRecipe   bool // the recipe was discovered by trying it
+}
+
+type useResultInternal struct {
+	FixtureName         string
+	UsedItemName        string
+	ProducedItem        *ItemInfo
+	IsComplete          bool
+	CompletionNarrative string
+	InsertNarrative     string       // told for the accepted item
+	StageNarrative      string       // told if the item finished a stage of the fixture
+	MissingItems        []string     // items the fixture still needs for its current stage, if it names them
+	MissingCount        int          // items the fixture still needs in all, unless it hides them
+	BrokenItem          string       // the used tool, if it wore out
+	Scrap               *ItemInfo    // what the broken tool left behind, if anything
+	ChargesLeft         int          // charges left on the used item, for items with charges
+	MaxCharges          int          // 0 if the used item has no charges
+	Effects             []EffectInfo // what completing the fixture did to the level
+	Trap                *TrapInfo    // the trap the used item sprang, if it had one
+}
+
+// EffectInfo describes an effect of completing a fixture: the effect type and the door,
+// container or enemy it acted on.
+type EffectInfo struct {
+	Effect string
+	Target string
+}
+
+type minimapResultInternal struct {
+	Floors       []MinimapFloorInfo
+	CurrentFloor string
+	CurrentRoom  string
+	TravelNodes  []TravelNodeInfo // discovered fast travel points
+}
+
+// --- internal methods ---
+
+// Observe returns the current room's name, description, and visible items and doors.
+// The filter picks the items listed.
+func (e *Engine) observeInternal(filter ObserveFilter) (*observeResultInternal, error) {
+	items, nextCursor, err := filter.page(itemsBySubLocation(e.CurrentRoom))
+	if err != nil {
+		return nil, err
+	}
+	result := &observeResultInternal{
+		RoomName:        e.CurrentRoom.Name,
+		RoomDescription: e.localize(e.roomDescription(e.CurrentRoom)),
+		RoomImageRef:    e.CurrentRoom.ImageRef,
+		Airless:         e.CurrentRoom.Airless,
+		Burning:         e.CurrentRoom.Burning,
+		NextCursor:      nextCursor,
+	}
+
+	for _, item := range items {
+		result.VisibleItems = append(result.VisibleItems, e.createItemInfo(item))
+	}
+
+	for _, conn := range e.visibleConnections(e.CurrentRoom) {
+		// Find the actual door in the level
+		door := e.Level.GetDoor(conn.DoorName)
+		doorInfo := e.createDoorInfo(door)
+		doorInfo.Location = conn.Location
+		result.Doors = append(result.Doors, doorInfo)
+	}
+
+	if e.CurrentRoom.TravelNode != nil {
+		travelNode := e.createTravelNodeInfo(e.CurrentFloor, e.CurrentRoom)
+		result.TravelNode = &travelNode
+	}
+
+	// Mark room as visited at the end of observation
+	e.CurrentRoom.Visited = true
+
+	return result, nil
+}
+
+// Inspect inspects an item or door by name.
+// Note: this is mainly intended for use on items, but we handle doors just in case.
+func (e *Engine) inspectInternal(name string) (*inspectResultInternal, error) {
+	name, err := e.resolveItemOrDoorName(name)
+	if err != nil {
+		return nil, err
+	}
+	door, err := e.findDoorByName(name)
+	if err == nil {
+		return &inspectResultInternal{
+			DoorInspection: &DoorInspection{
+				DoorInfo: e.createDoorInfo(door),
+			},
+		}, nil
+	}
+	item, err := e.findItem(name)
+	if err == nil {
+		e.playSound(item.Name, item.SoundCues, world.SoundInspect)
+		e.learnCodes(item.Description, item.Detail)
+		return &inspectResultInternal{
+			ItemInspection: &ItemInspection{
+				ItemInfo:       e.createItemInfo(item),
+				Detail:         e.localize(item.Detail),
+				LearnedRecipes: e.learnRecipes(item.Name),
+			},
+		}, nil
+	}
+	return nil, err
+}
+
+// Uncover reveals something concealed.
+func (e *Engine) uncoverInternal(name string) (*uncoverResultInternal, error) {
+	name, err := e.resolveItemName(name)
+	if err != nil {
+		return nil, err
+	}
+	concealer, err := e.findRoomItem(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if the item is a concealer and that it is not already uncovered
+	if !concealer.IsConcealer() {
+		return nil, world.Errorf(ErrInvalidTarget, "the %s cannot conceal anything", name)
+	}
+	if concealer.Concealer.Uncovered {
+		return nil, world.Errorf(ErrAlreadyUncovered, "the %s cannot conceal anything", name)
+	}
+
+	// Reveal the concealed item
+	revealedItem, err := concealer.Concealer.Reveal()
+	if err != nil {
+		return nil, err
+	}
+
+	// Move the revealed item to the current room, at the concealer's sub-location
+	revealedItem.SubLocation = concealer.SubLocation
+	e.CurrentRoom.Items = append(e.CurrentRoom.Items, revealedItem)
+	e.recordSecretFound(revealedItem)
+	e.playSound(concealer.Name, concealer.SoundCues, world.SoundUncover)
+
+	return &uncoverResultInternal{
+		Name:         concealer.Name,
+		RevealedItem: e.createItemInfo(revealedItem),
+	}, nil
+}
+
+// Unlocks a container or door with a key or code.
+func (e *Engine) unlockInternal(keyNameOrCode string, targetName string) (*unlockResultInternal, error) {
+	keyNameOrCode, err := e.resolveItemName(keyNameOrCode)
+	if err != nil {
+		return nil, err
+	}
+	targetName, err = e.resolveItemOrDoorName(targetName)
+	if err != nil {
+		return nil, err
+	}
+	// Locks take a key by its name, whichever of the keys sharing it the player picked
+	if key, err := e.Player.GetItem(keyNameOrCode); err == nil {
+		keyNameOrCode = key.Name
+	}
+
+	// Try to unlock a container.
+	if item, err := e.findRoomItem(targetName); err == nil {
+		if !item.IsContainer() {
+			return nil, world.Errorf(ErrInvalidTarget, "the %s is not a container", targetName)
+		}
+		if item.Container.HasCodeLock() {
+			jammed, err := e.unlockWithCode(item.Container.Locked, keyNameOrCode)
+			if err != nil {
+				return nil, err
+			}
+			if jammed {
+				return &unlockResultInternal{Target: item.Name, Jammed: true}, nil
+			}
+		} else {
+			err := e.validateKey(keyNameOrCode)
+			if err != nil {
+				return nil, err
+			}
+			err = item.Container.UnlockWithKey(keyNameOrCode)
+			if err != nil {
+				return nil, err
+			}
+			// Remove the key from inventory after successful use
+			key, _ := e.Player.ConsumeItem(keyNameOrCode)
+			e.playSound(key.Name, key.SoundCues, world.SoundUnlock)
+		}
+		e.playSound(item.Name, item.SoundCues, world.SoundUnlock)
+		return &unlockResultInternal{Target: item.Name, Unlocked: true}, nil
+	}
+
+	// Try to unlock a door.
+	if door, err := e.findDoorByName(targetName); err == nil {
+		if door.HasCodeLock() {
+			jammed, err := e.unlockWithCode(door.Lock, keyNameOrCode)
+			if err != nil {
+				return nil, err
+			}
+			if jammed {
+				return &unlockResultInternal{Target: door.Name, Jammed: true}, nil
+			}
+		} else {
+			err := e.validateKey(keyNameOrCode)
+			if err != nil {
+				return nil, err
+			}
+			err = door.UnlockWithKey(keyNameOrCode)
+			if err != nil {
+				return nil, err
+			}
+			// Remove the key from inventory after successful use
+			key, _ := e.Player.ConsumeItem(keyNameOrCode)
+			e.playSound(key.Name, key.SoundCues, world.SoundUnlock)
+		}
+		e.updateMinimapForDoor(door.Name, false)
+		return &unlockResultInternal{Target: door.Name, Unlocked: true}, nil
+	}
+
+	return nil, world.Errorf(ErrNotFound, "you don't see a %s here", targetName)
+}
+
+// Search searches a container or a surrendered enemy in the current room.
+func (e *Engine) searchInternal(name string) (*searchResultInternal, error) {
+	name, err := resolveName(name, append(e.itemEntities(), e.enemyEntities()...))
+	if err != nil {
+		return nil, err
+	}
+	if enemy := e.surrenderedEnemy(name); enemy != nil {
+		return e.searchEnemy(enemy), nil
+	}
+	container, err := e.findRoomItem(name)
+	if err != nil {
+		return nil, err
+	}
+	unlocked := false
+
+	if !container.IsContainer() {
+		return nil, world.Errorf(ErrInvalidTarget, "the %s is not a container", name)
+	}
+
+	if container.Container.IsLocked() && container.Container.HasKeyLock() {
+		if e.isItemInInventory(container.Container.Locked.KeyName) {
+			_, err := e.unlockInternal(container.Container.Locked.KeyName, container.Name)
+			if err != nil {
+				return nil, e.corrupt("the key to the %s doesn't unlock it: %v", container.Name, err)
+			}
+			unlocked = true
+		}
+	}
+
+	// containedItem may be nil if the container is empty
+	containedItem, err := container.Container.Search()
+	if err != nil {
+		return nil, err
+	}
+
+	e.playSound(container.Name, container.SoundCues, world.SoundSearch)
+	searchResult := &searchResultInternal{
+		ContainerName: container.Name,
+		Unlocked:      unlocked,
+	}
+
+	if containedItem != nil {
+		e.recordSecretFound(containedItem)
+		itemInfo := e.createItemInfo(containedItem)
+		searchResult.ContainedItemInfo = &itemInfo
+	}
+
+	return searchResult, nil
+}
+
+// Take takes an item from the current room.
+func (e *Engine) takeInternal(name string, quantity int) (*takeResultInternal, error) {
+	name, err := e.resolveItemName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Try to take from the room
+	if item, err := e.CurrentRoom.GetItem(name); err == nil {
+		// Special handling for items that conceal another item: "redirect" to uncover.
+		// The concealer stays where it is; only the item it was hiding is revealed.
+		if item.IsConcealer() && !item.Concealer.Uncovered {
+			uncoverResult, err := e.uncoverInternal(name)
+			if err != nil {
+				return nil, err
+			}
+			return &takeResultInternal{ItemInfo: uncoverResult.RevealedItem}, nil
+		}
+		if !item.IsPortable() {
+			return nil, world.Errorf(ErrInvalidTarget, "you cannot take the %s", name)
+		}
+		taken, err := takeFromStack(item, quantity)
+		if err != nil {
+			return nil, err
+		}
+		e.recordSecretFound(item)
+		e.playSound(item.Name, item.SoundCues, world.SoundTake)
+		// Remove the item from the room when taken (except concealers, handled above), unless
+		// some of its stack is left
+		if taken == item {
+			e.CurrentRoom.RemoveItem(item.Ref())
+		}
+		taken.SubLocation = ""
+		trap := e.springTrap(taken, world.TrapOnTake)
+		// Handle ammo and weapon ammo transfer
+		if handled := e.handleAmmoTransfer(taken); handled {
+			// Ammo boxes are consumed, weapons stay in inventory
+			if taken.IsAmmoBox() {
+				return &takeResultInternal{ItemInfo: e.createItemInfo(taken), Trap: trap}, nil
+			}
+		}
+		e.Player.AddItem(taken)
+		return &takeResultInternal{ItemInfo: e.createItemInfo(taken), Trap: trap}, nil
+	}
+
+	// Try to take from a searched container.
+	if itemContainer, err := e.findItemInRoomContainer(name); err == nil {
+		item := itemContainer.ContainedItem
+		// As in the room, taking a concealer uncovers what it hides
+		if item.IsConcealer() && !item.Concealer.Uncovered {
+			uncoverResult, err := e.uncoverInternal(name)
+			if err != nil {
+				return nil, err
+			}
+			return &takeResultInternal{ItemInfo: uncoverResult.RevealedItem}, nil
+		}
+		if !item.IsPortable() {
+			return nil, world.Errorf(ErrInvalidTarget, "you cannot take the %s", name)
+		}
+		taken, err := takeFromStack(item, quantity)
+		if err != nil {
+			return nil, err
+		}
+		if taken == item {
+			if _, err := itemContainer.ContainingItem.Container.RemoveItem(); err != nil {
+				return nil, err
+			}
+		}
+		e.playSound(taken.Name, taken.SoundCues, world.SoundTake)
+		trap := e.springTrap(taken, world.TrapOnTake)
+		// Handle ammo and weapon ammo transfer
+		if handled := e.handleAmmoTransfer(taken); handled {
+			// Ammo boxes are consumed, weapons stay in inventory
+			if taken.IsAmmoBox() {
+				return &takeResultInternal{ItemInfo: e.createItemInfo(taken), Trap: trap}, nil
+			}
+		}
+		e.Player.AddItem(taken)
+		return &takeResultInternal{ItemInfo: e.createItemInfo(taken), Trap: trap}, nil
+	}
+
+	return nil, world.Errorf(ErrNotFound, "you don't see a %s here", name)
+}
+
+// takeFromStack returns the items taken from a stack: quantity of them, or the whole stack
+// if quantity is 0.
+func takeFromStack(item *world.Item, quantity int) (*world.Item, error) {
+	if quantity == 0 {
+		return item, nil
+	}
+	if quantity < 0 || quantity > item.Count() {
+		return nil, world.Errorf(ErrInvalidArgument, "there are only %d of the %s here", item.Count(), item.Name)
+	}
+	return item.SplitStack(quantity)
+}
+
+// handleAmmoTransfer handles transferring ammo from items to the player's ammo count.
+// Returns true if the item was an ammo box (which should be consumed).
+func (e *Engine) handleAmmoTransfer(item *world.Item) bool {
+	// Handle ammo boxes: add to ammo count and consume the item
+	if item.IsAmmoBox() {
+		e.Player.Ammo[item.AmmoType()] += item.AmmoBox.Ammo.Quantity * item.Count()
+		return true
+	}
+	// Handle weapons with ammo: transfer ammo to the player's rounds of its type and clear weapon ammo
+	if item.IsWeapon() && item.Weapon.UsesAmmo() {
+		e.Player.Ammo[item.AmmoType()] += item.Weapon.Ammo.Quantity
+		item.Weapon.Ammo.Quantity = 0 // Clear the weapon's ammo
+	}
+	return false
+}
+
+// Inventory returns the player's inventory, with ammo sorted by type.
+func (e *Engine) inventoryInternal() (*inventoryResultInternal, error) {
+	result := &inventoryResultInternal{}
+	for _, item := range e.Player.Inventory {
+		result.Items = append(result.Items, e.createItemInfo(item))
+	}
+	for _, ammoType := range sortedKeys(e.Player.Ammo) {
+		result.Ammo = append(result.Ammo, AmmoCount{
+			AmmoType:  ammoType,
+			AmmoCount: e.Player.Ammo[ammoType],
+		})
+	}
+	return result, nil
+}
+
+// Heal heals the player with a health item.
+func (e *Engine) healInternal(healthItemName string) (*healResultInternal, error) {
+	healthItemName, err := e.resolveItemName(healthItemName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the health item in the player's inventory.
+	healthItem, err := e.Player.GetItem(healthItemName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use a health item to heal the player.
+	if healthItem.IsHealthItem() {
+		if e.Player.Health == world.HealthFine && e.Player.PoisonedBy == "" {
+			return nil, world.Errorf(ErrFullHealth, "you are already at full health")
+		}
+		health, err := e.useHealthItem(healthItem)
+		if err != nil {
+			return nil, err
+		}
+		cured := e.curePoison()
+		chargesLeft := e.useUpItem(healthItem)
+		e.playSound(healthItem.Name, healthItem.SoundCues, world.SoundHeal)
+		result := &healResultInternal{
+			Health:      health,
+			ChargesLeft: chargesLeft,
+			MaxCharges:  maxCharges(healthItem),
+			Cured:       cured,
+		}
+		if result.Trap = e.springTrap(healthItem, world.TrapOnUse); result.Trap != nil {
+			result.Health = e.Player.Health
+		}
+		return result, nil
+	}
+
+	// Breathe from an air supply.
+	if healthItem.IsAirSupply() {
+		return e.breatheFrom(healthItem)
+	}
+
+	return nil, world.Errorf(ErrInvalidTarget, "the %s is not a health item", healthItemName)
+}
+
+// Traverse moves the player to a destination room if reachable and unlocked.
+// Destination can be either a door name or a location (e.g., "left", "ahead", "back", "right").
+func (e *Engine) traverseInternal(destination string) (*traverseResultInternal, error) {
+	unlocked := false
+	door, err := e.findDoor(destination)
+	if err != nil {
+		return nil, err
+	}
+	if !door.CanTraverseFrom(e.CurrentRoom.Name) {
+		return nil, world.Errorf(ErrOneWay, "the %s only leads the other way", door.Name)
+	}
+
+	// Mark the door as tried before checking locks
+	door.Tried = true
+
+	// Check if the door is locked.
+	if door.IsLocked() {
+		e.updateMinimapForDoor(door.Name, true)
+		if door.HasKeyLock() {
+			if e.isItemInInventory(door.Lock.KeyName) {
+				_, err := e.unlockInternal(door.Lock.KeyName, door.Name)
+				if err != nil {
+					return nil, e.corrupt("the key to the %s doesn't unlock it: %v", door.Name, err)
+				}
+				unlocked = true
+			} else {
+				return nil, world.Errorf(ErrLocked, "the %s is locked", door.Name)
+			}
+		}
+		if door.HasCodeLock() {
+			return nil, world.Errorf(ErrLocked, "the %s is locked, it requires a code", door.Name)
+		}
+	}
+
+	// Check if the door is latched.
+	// NOTE: latching is not currently supported in the minimap
+	var unlatched bool
+	if door.IsLatched() {
+		// Check if we can unlatch from this side
+		if door.CanUnlatch(e.CurrentRoom.Name) {
+			// We can unlatch it, so do so
+			door.Unlatch()
+			unlatched = true
+		} else {
+			// We can't unlatch from this side
+			return nil, world.Errorf(ErrLatched, "this door is latched from the other side")
+		}
+	}
+
+	destinationFloor, destinationRoom, err := e.roomBehind(door)
+	if err != nil {
+		return nil, err
+	}
+
+	// Move to the destination room and floor
+	e.CurrentRoom = destinationRoom
+	e.CurrentFloor = destinationFloor
+
+	// Mark the door as traversed and update the minimap
+	if !door.Traversed {
+		door.Traversed = true
+		e.updateMinimapDataForCurrenRoom()
+		e.updateMinimapForDoor(door.Name, false)
+	}
+
+	e.playSound(destinationRoom.Name, destinationRoom.SoundCues, world.SoundEnter)
+
+	// Get the observation result for the entered room (without event handling)
+	enteredRoomObs, err := e.observeInternal(ObserveFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &traverseResultInternal{
+		EnteredRoom: *enteredRoomObs,
+		Unlatched:   unlatched,
+		Unlocked:    unlocked,
+	}
+
+	// Populate the changed floor info if we used a stairwell
+	if door.Stairwell {
+		result.ChangedFloor = &FloorInfo{
+			Name:        destinationFloor.Name,
+			Description: e.localize(destinationFloor.Description),
+		}
+	}
+
+	return result, nil
+}
+
+// Battle simulates a round of combat between the player and the enemy.
+func (e *Engine) battleInternal(weaponName string) (*battleResultInternal, error) {
+	var weaponDamage float64
+
+	if e.FightingEnemy == nil {
+		return nil, world.Errorf(ErrWrongMode, "there is no enemy to fight")
+	}
+
+	var weapon *world.Item
+	if weaponName == "" || weaponName == fistsWeaponName || weaponName == "hands" {
+		weaponDamage = 0.5
+	} else {
+		weaponName, err := e.resolveItemName(weaponName)
+		if err != nil {
+			return nil, err
+		}
+		weapon, err = e.Player.GetItem(weaponName)
+		if err != nil {
+			return nil, err
+		}
+		if !weapon.IsWeapon() {
+			return nil, world.Errorf(ErrInvalidTarget, "the %s is not a weapon", weaponName)
+		}
+		if err := checkNotBroken(weapon); err != nil {
+			return nil, err
+		}
+		if weapon.Weapon.UsesAmmo() {
+			err := e.Player.FireWeapon(weapon.AmmoType())
+			if err != nil {
+				return nil, err
+			}
+		}
+		weaponDamage = weapon.Weapon.Damage
+		e.playSound(weapon.Name, weapon.SoundCues, world.SoundBattle)
+	}
+
+	enemyHP, health := e.FightingEnemy.HP, e.Player.Health
+	result := &battleResultInternal{EnemyName: e.FightingEnemy.Name}
+	result.WonRound, result.Pity = e.winsRound(weaponDamage)
+	if result.WonRound {
+		hits := 1
+		if e.criticalHit(weapon) {
+			result.Critical = true
+			result.Narrative = e.localize(weapon.Weapon.Critical.Narrative)
+			hits += weapon.Weapon.Critical.Damage
+		}
+		for range hits {
+			if !e.FightingEnemy.IsHostile() {
+				break
+			}
+			e.FightingEnemy.InflictDamage()
+			e.Telemetry.DamageDealt++
+			if e.FightingEnemy.ShouldSurrender() {
+				e.surrender(e.FightingEnemy)
+				result.Surrendered = true
+			}
+		}
+	} else {
+		result.Absorbed, result.BrokenArmor, result.ArmorScrap = e.enemyStrikes()
+	}
+	result.EnemyAlive = e.FightingEnemy.IsAlive()
+	result.PlayerAlive = e.Player.IsAlive()
+	switch {
+	case weapon != nil && weapon.Weapon.IsThrown():
+		e.throwWeapon(weapon, result)
+	case weapon != nil:
+		if broke, scrap := e.wearItem(weapon); broke {
+			result.BrokenItem, result.Scrap = weapon.Name, scrap
+		}
+	}
+	if !result.WonRound && result.PlayerAlive && result.BrokenItem == "" && e.fumble(weapon) {
+		result.Fumbled = true
+		result.Narrative = e.localize(weapon.Weapon.Critical.FumbleNarrative)
+	}
+	result.CombatLog = e.logRound(weapon, result, enemyHP, health)
+	return result, nil
+}
+
+// throwWeapon uses up a thrown weapon after a round fought with it. An area weapon that hit
+// also hurts every other living enemy lurking in the room, and an incendiary one sets the room
+// on fire whether it hit or not.
+func (e *Engine) throwWeapon(weapon *world.Item, result *battleResultInternal) {
+	e.Player.ConsumeItem(weapon.Ref())
+	result.Thrown = true
+	if weapon.Weapon.Thrown.Area && result.WonRound {
+		for _, enemy := range e.Level.Enemies {
+			if enemy == e.FightingEnemy || enemy.Room != e.CurrentRoom.Name || !enemy.IsHostile() {
+				continue
+			}
+			enemy.InflictDamage()
+			e.Telemetry.DamageDealt++
+			result.AlsoHit = append(result.AlsoHit, enemy.Name)
+			if !enemy.IsAlive() {
+				result.AlsoKilled = append(result.AlsoKilled, enemy.Name)
+			}
+		}
+	}
+	if weapon.Weapon.Thrown.StartsFire && !e.CurrentRoom.Burning {
+		e.CurrentRoom.Burning = true
+		result.StartedFire = true
+	}
+}
+
+// Combine crafts a new item by combining input items.
+func (e *Engine) combineInternal(inputItemNames ...string) (*combineResultInternal, error) {
+	names := make([]string, len(inputItemNames))
+	inputItems := make([]*world.Item, len(inputItemNames))
+	for i, inputItemName := range inputItemNames {
+		name, err := e.resolveItemName(inputItemName)
+		if err != nil {
+			return nil, err
+		}
+
+		// Verify every item is in the player's inventory
+		inputItem, err := e.Player.GetItem(name)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(inputItems[:i], inputItem) {
+			return nil, world.Errorf(ErrInvalidArgument, "the %s can only be combined once", name)
+		}
+		if inputItem.QuestItem {
+			return nil, world.Errorf(ErrQuestItem, "you can't risk using up the %s", inputItem.Name)
+		}
+		names[i], inputItems[i] = inputItem.Name, inputItem
+	}
+
+	combo, err := e.Level.CombineItems(names...)
+	if err != nil {
+		return nil, err
+	}
+	if combo.FixtureName != "" {
+		if fixture, err := e.CurrentRoom.GetItem(combo.FixtureName); err != nil || !fixture.IsFixture() {
+			return nil, world.Errorf(ErrInvalidTarget, "you need the %s to combine these", combo.FixtureName)
+		}
+	}
+	for _, inputItem := range inputItems {
+		e.Player.ConsumeItem(inputItem.Ref())
+		e.playSound(inputItem.Name, inputItem.SoundCues, world.SoundCombine)
+	}
+	e.Player.Inventory = append(e.Player.Inventory, combo.OutputItem)
+	result := &combineResultInternal{
+		CraftedItem: e.createItemInfo(combo.OutputItem),
+		NewRecipe:   !e.recipeKnown(combo),
+	}
+	e.KnownRecipes[combo.OutputItem.Name] = true
+	for _, byproduct := range combo.Byproducts {
+		e.Player.Inventory = append(e.Player.Inventory, byproduct)
+		result.Byproducts = append(result.Byproducts, e.createItemInfo(byproduct))
+	}
+	return result, nil
+}
+
+func (e *Engine) useInternal(itemName string, targetName string) (*useResultInternal, error) {
+	itemName, err := e.resolveItemName(itemName)
+	if err != nil {
+		return nil, err
+	}
+	targetName, err = e.resolveItemName(targetName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify the item is in the player's inventory
+	item, err := e.Player.GetItem(itemName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the target fixture in the current room
+	targetFixture, err := e.CurrentRoom.GetItem(targetName)
+	if err != nil {
+		return nil, world.Errorf(ErrNotFound, "fixture %s not found in current room", targetName)
+	}
+
+	if !targetFixture.IsFixture() {
+		return nil, world.Errorf(ErrInvalidTarget, "%s is not a fixture", targetName)
+	}
+	if err := checkNotBroken(item); err != nil {
+		return nil, err
+	}
+
+	// Use the item on the fixture
+	result, err := targetFixture.Fixture.UseItem(item.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove the used item from player's inventory, unless it is a tool that only wears down
+	var brokenItem string
+	var scrap *ItemInfo
+	var chargesLeft int
+	if item.Durability != nil {
+		if broke, scrapInfo := e.wearItem(item); broke {
+			brokenItem, scrap = item.Name, scrapInfo
+		}
+	} else {
+		chargesLeft = e.useUpItem(item)
+	}
+	e.playSound(item.Name, item.SoundCues, world.SoundUse)
+	e.playSound(targetFixture.Name, targetFixture.SoundCues, world.SoundUse)
+	trap := e.springTrap(item, world.TrapOnUse)
+
+	// If the fixture produced an item, add it to player's inventory
+	var producedItemInfo *ItemInfo
+	if result.Item != nil {
+		e.Player.Inventory = append(e.Player.Inventory, result.Item)
+		itemInfo := e.createItemInfo(result.Item)
+		itemInfo.Location = "inventory" // Override location since it's now in inventory
+		producedItemInfo = &itemInfo
+	}
+
+	useResult := useResultInternal{
+		FixtureName:     targetFixture.Name,
+		UsedItemName:    item.Name,
+		ProducedItem:    producedItemInfo,
+		IsComplete:      targetFixture.Fixture.IsComplete(),
+		InsertNarrative: e.localize(result.InsertNarrative),
+		StageNarrative:  e.localize(result.StageNarrative),
+		BrokenItem:      brokenItem,
+		Scrap:           scrap,
+		ChargesLeft:     chargesLeft,
+		MaxCharges:      maxCharges(item),
+		Trap:            trap,
+	}
+	e.learnCodes(result.InsertNarrative, result.StageNarrative)
+
+	if useResult.IsComplete {
+		useResult.CompletionNarrative = e.localize(targetFixture.Fixture.CompletionNarrative)
+		e.learnCodes(targetFixture.Fixture.CompletionNarrative)
+		for _, effect := range targetFixture.Fixture.OnComplete {
+			useResult.Effects = append(useResult.Effects, EffectInfo{
+				Effect: string(effect.EffectType),
+				Target: effect.TargetName + effect.EnemyName,
+			})
+		}
+	} else {
+		switch targetFixture.Fixture.RevealMissing {
+		case world.MissingItemsHidden:
+		case world.MissingItemsCount:
+			useResult.MissingCount = targetFixture.Fixture.MissingCount()
+		default:
+			useResult.MissingItems = targetFixture.Fixture.MissingItems()
+			useResult.MissingCount = targetFixture.Fixture.MissingCount()
+		}
+	}
+
+	return &useResult, nil
+}
+
+// minimapInternal returns minimap data for every floor.
+// Rooms connected by a door are only revealed once they have been visited, so the map
+// fills in as the player explores.
+func (e *Engine) minimapInternal() (*minimapResultInternal, error) {
+	result := &minimapResultInternal{
+		CurrentFloor: e.CurrentFloor.Name,
+		CurrentRoom:  e.CurrentRoom.Name,
+		TravelNodes:  e.discoveredTravelNodes(),
+	}
+
+	// Rooms count as visited once observed, or once the player has been through one of their doors
+	visited := make(map[string]bool)
+	roomFloors := make(map[string]string) // room name -> floor name
+	for _, floor := range e.Level.Floors {
+		for _, room := range floor.Rooms {
+			visited[room.Name] = room.Visited
+			roomFloors[room.Name] = floor.Name
+		}
+	}
+	for _, door := range e.Level.Doors {
+		if door.Traversed {
+			visited[door.RoomA] = true
+			visited[door.RoomB] = true
+		}
+	}
+	visited[e.CurrentRoom.Name] = true
+
+	// Build door info once, sorted by name
+	var doors []MinimapDoorInfo
+	adjacent := make(map[string]bool)
+	for _, doorName := range sortedKeys(e.MinimapData) {
+		doorInfo := e.MinimapData[doorName]
+		door := e.Level.GetDoor(doorName)
+		info := MinimapDoorInfo{
+			Name:      doorName,
+			Locked:    doorInfo.Locked,
+			Hidden:    doorInfo.Hidden,
+			Traversed: door.Traversed,
+			Stairwell: door.Stairwell,
+			Note:      e.DoorNotes[doorName],
+		}
+		if visited[doorInfo.RoomA] || info.Traversed {
+			info.RoomA = doorInfo.RoomA
+		}
+		if visited[doorInfo.RoomB] || info.Traversed {
+			info.RoomB = doorInfo.RoomB
+		}
+		if info.RoomA != "" || info.RoomB != "" {
+			info.Direction = doorInfo.Direction
+		}
+		doors = append(doors, info)
+
+		// Unvisited rooms behind doors of visited rooms are adjacent
+		if !doorInfo.Hidden {
+			adjacent[doorInfo.RoomA] = !visited[doorInfo.RoomA]
+			adjacent[doorInfo.RoomB] = !visited[doorInfo.RoomB]
+		}
+	}
+
+	for _, floor := range e.Level.Floors {
+		floorInfo := MinimapFloorInfo{
+			Name:    floor.Name,
+			Current: floor == e.CurrentFloor,
+		}
+
+		positions := e.roomPositions(floor)
+		for _, room := range floor.Rooms {
+			info := MinimapRoomInfo{
+				Name:     room.Name,
+				Hidden:   !visited[room.Name],
+				Adjacent: adjacent[room.Name],
+				Note:     e.RoomNotes[room.Name],
+			}
+			if position, ok := positions[room.Name]; ok {
+				info.Position = &position
+			}
+			if visited[room.Name] {
+				info.Icons = e.roomIcons(room)
+				floorInfo.Visited = true
+			}
+			floorInfo.Rooms = append(floorInfo.Rooms, info)
+		}
+
+		// Stairwells are listed on both floors they connect
+		for _, info := range doors {
+			door := e.MinimapData[info.Name]
+			floorA, floorB := roomFloors[door.RoomA], roomFloors[door.RoomB]
+			if floorA != floor.Name && floorB != floor.Name {
+				continue
+			}
+			if floorA != floorB {
+				if floorA == floor.Name && info.RoomB != "" {
+					info.LeadsToFloor = floorB
+				} else if floorB == floor.Name && info.RoomA != "" {
+					info.LeadsToFloor = floorA
+				}
+			}
+			floorInfo.Doors = append(floorInfo.Doors, info)
+		}
+
+		result.Floors = append(result.Floors, floorInfo)
+	}
+
+	return result, nil
+}
+
+// roomIcons returns the minimap icons for a visited room.
+func (e *Engine) roomIcons(room *world.Room) []MinimapIcon {
+	var icons []MinimapIcon
+	for _, enemyName := range sortedKeys(e.EnemySightings) {
+		if e.EnemySightings[enemyName] == room.Name && e.Level.GetEnemy(enemyName).IsHostile() {
+			icons = append(icons, MinimapIconEnemy)
+			break
+		}
+	}
+	for _, conn := range room.Connections {
+		if info, exists := e.MinimapData[conn.DoorName]; exists && info.Locked != nil && *info.Locked {
+			icons = append(icons, MinimapIconLockedDoor)
+			break
+		}
+	}
+	if room.SavePoint {
+		icons = append(icons, MinimapIconSavePoint)
+	}
+	if room.TravelNode != nil {
+		icons = append(icons, MinimapIconTravelNode)
+	}
+	return icons
+}
+
+func (e *Engine) DisableValidation() {
+	e.ValidationDisabled = true
+}
+
+func (e *Engine) EnableValidation() {
+	e.ValidationDisabled = false
+}
+
+// --- RNG ---
+
+type Rng interface {
+	Float64() float64
+}
+
+type DefaultRng struct{}
+
+func (g *DefaultRng) Float64() float64 { return rand.Float64() }
+
+// SeededRng is a deterministic Rng, so that a game can be replayed from its seed.
+type SeededRng struct{ rand *rand.Rand }
+
+func NewSeededRng(seed uint64) *SeededRng {
+	return &SeededRng{rand: rand.New(rand.NewPCG(seed, seed))}
+}
+
+func (g *SeededRng) Float64() float64 { return g.rand.Float64() }
+
+type FakeRng struct{ Value float64 }
+
+func (g *FakeRng) Float64() float64       { return g.Value }
+func (g *FakeRng) SetValue(value float64) { g.Value = value }
+
+// --- minimap structures ---
+
+// MinimapDoorInfo contains minimap information about a door
+type MinimapDoorInfo struct {
+	Name         string          // door name
+	RoomA        string          // room on one side of the door, empty until visited
+	RoomB        string          // room on the other side of the door, empty until visited
+	Direction    world.Direction // direction from room A to room B, empty if unknown or the level doesn't specify one
+	Locked       *bool           // nil if unknown, true/false if known
+	Hidden       bool            // true if the door should be hidden on minimap
+	Traversed    bool            // true once the player has gone through the door
+	Stairwell    bool            // true if the door is a stairwell
+	LeadsToFloor string          // for stairwells, the floor on the other side once revealed
+	Note         string          // the players' note on the door, if they wrote one
+}
+
+// MinimapFloorInfo contains minimap information about a floor
+type MinimapFloorInfo struct {
+	Name    string
+	Current bool // true if the player is on this floor
+	Visited bool // true if any room on the floor has been visited
+	Rooms   []MinimapRoomInfo
+	Doors   []MinimapDoorInfo // doors with a room on this floor
+}
+
+// MinimapIcon marks something of note in a room on the minimap.
+type MinimapIcon string
+
+const (
+	MinimapIconEnemy      MinimapIcon = "enemy"       // a living enemy was encountered here
+	MinimapIconLockedDoor MinimapIcon = "locked_door" // a door out of the room is known to be locked
+	MinimapIconSavePoint  MinimapIcon = "save_point"  // the level marks the room as a save point
+	MinimapIconTravelNode MinimapIcon = "travel_node" // the room has a fast travel point
+)
+
+// MinimapRoomInfo contains minimap information about a room
+type MinimapRoomInfo struct {
+	Name     string
+	Hidden   bool            // true until the room is visited
+	Adjacent bool            // true if the room is unvisited but behind a door of a visited room
+	Position *world.Position // nil if the room can't be placed on the floor's grid
+	Icons    []MinimapIcon   // only for visited rooms
+	Note     string          // the players' note on the room, if they wrote one
+}
+
+// --- debug structures ---
+
+// DebugItemInfo contains complete debug information about an item.
+type DebugItemInfo struct {
+	Name         string
+	ID           string
+	Description  string
+	Location     string
+	Detail       string
+	IsPortable   bool
+	IsContainer  bool
+	IsConcealer  bool
+	IsKey        bool
+	IsAmmoBox    bool
+	IsWeapon     bool
+	IsHealthItem bool
+
+	// Container-specific fields
+	HasKeyLock  bool
+	HasCodeLock bool
+	IsLocked    bool
+	IsSearched  bool
+	Contains    *DebugItemInfo // Nested item info if container has contents
+
+	// Weapon-specific fields
+	WeaponDamage float64
+	UsesAmmo     bool
+	AmmoQuantity int
+
+	// AmmoBox-specific fields
+	AmmoType  string
+	AmmoCount int
+
+	// HealthItem-specific fields
+	HealthEffect string
+
+	// Concealer-specific fields
+	IsUncovered bool
+	HiddenItem  *DebugItemInfo // Nested item info if concealer has hidden item
+}
+
+// DebugDoorInfo contains complete debug information about a door.
+type DebugDoorInfo struct {
+	Name        string
+	Location    string
+	RoomA       string
+	RoomB       string
+	HasKeyLock  bool
+	HasCodeLock bool
+	IsLocked    bool
+	KeyName     string
+	Code        string
+}
+
+// DebugEnemyInfo contains complete debug information about an enemy.
+type DebugEnemyInfo struct {
+	Name        string
+	Description string
+	Room        string
+	HP          int
+	IsAlive     bool
+	State       world.EnemyState
+}
+
+// DebugRoomInfo contains complete debug information about a room.
+type DebugRoomInfo struct {
+	Name        string
+	Description string
+	Items       []DebugItemInfo
+	Doors       []DebugDoorInfo
+	IsCurrent   bool
+}
+
+// DebugPlayerInfo contains complete debug information about the player.
+type DebugPlayerInfo struct {
+	Health    string
+	IsAlive   bool
+	Inventory []DebugItemInfo
+	Ammo      map[string]int
+}
+
+// DebugEngineState contains complete debug information about the engine state.
+type DebugEngineState struct {
+	LevelCompletionState string
+	Mode                 string
+	FightingEnemy        *DebugEnemyInfo
+	CurrentRoom          string
+	Corrupted            string // why the game state can't be trusted, if it can't
+}
+
+// DebugResult contains the complete debug information for the engine.
+type DebugResult struct {
+	EngineStateInfo EngineStateInfo
+	EngineState     DebugEngineState
+	Player          DebugPlayerInfo
+	Rooms           []DebugRoomInfo
+	Enemies         []DebugEnemyInfo
+	Triggers        []DebugTriggerInfo
+	WinCondition    *DebugEventInfo
+	TriggerLoop     []string        // the events of the last trigger loop stopped, outermost first
+	Log             []DebugLogEntry // the engine's recent decisions, oldest first
+}
+
+// PrettyPrint formats the debug result in a readable way.
+func (d *DebugResult) PrettyPrint() string {
+	var result string
+
+	// Engine State
+	result += "=== ENGINE STATE ===\n"
+	result += fmt.Sprintf("Mode: %s\n", d.EngineState.Mode)
+	result += fmt.Sprintf("Level Completion: %s\n", d.EngineState.LevelCompletionState)
+	result += fmt.Sprintf("Current Room: %s\n", d.EngineState.CurrentRoom)
+	if d.EngineState.FightingEnemy != nil {
+		result += fmt.Sprintf("Fighting Enemy: %s (HP: %d, Alive: %t)\n",
+			d.EngineState.FightingEnemy.Name,
+			d.EngineState.FightingEnemy.HP,
+			d.EngineState.FightingEnemy.IsAlive)
+	} else {
+		result += "Fighting Enemy: None\n"
+	}
+	if d.EngineState.Corrupted != "" {
+		result += fmt.Sprintf("Corrupted: %s\n", d.EngineState.Corrupted)
+	}
+	result += "\n"
+
+	// Player State
+	result += "=== PLAYER STATE ===\n"
+	result += fmt.Sprintf("Health: %s\n", d.Player.Health)
+	result += fmt.Sprintf("Alive: %t\n", d.Player.IsAlive)
+	result += fmt.Sprintf("Inventory Items: %d\n", len(d.Player.Inventory))
+	for i, item := range d.Player.Inventory {
+		result += fmt.Sprintf("  %d. %s (%s)\n", i+1, item.Name, item.Description)
+	}
+	result += fmt.Sprintf("Ammo Types: %d\n", len(d.Player.Ammo))
+	for _, ammoType := range sortedKeys(d.Player.Ammo) {
+		result += fmt.Sprintf("  %s: %d\n", ammoType, d.Player.Ammo[ammoType])
+	}
+	result += "\n"
+
+	// Rooms
+	result += "=== ROOMS ===\n"
+	for _, room := range d.Rooms {
+		current := ""
+		if room.IsCurrent {
+			current = " (CURRENT)"
+		}
+		result += fmt.Sprintf("Room: %s%s\n", room.Name, current)
+		result += fmt.Sprintf("  Description: %s\n", room.Description)
+		result += fmt.Sprintf("  Items: %d\n", len(room.Items))
+		for i, item := range room.Items {
+			result += fmt.Sprintf("    %d. %s (%s)\n", i+1, item.Name, item.Description)
+			if item.IsContainer {
+				result += fmt.Sprintf("      Container: Searched=%t, Locked=%t\n", item.IsSearched, item.IsLocked)
+				if item.Contains != nil {
+					result += fmt.Sprintf("      Contains: %s (%s)\n", item.Contains.Name, item.Contains.Description)
+				}
+			}
+			if item.IsConcealer {
+				result += fmt.Sprintf("      Concealer: Uncovered=%t\n", item.IsUncovered)
+				if item.HiddenItem != nil {
+					result += fmt.Sprintf("      Hidden: %s (%s)\n", item.HiddenItem.Name, item.HiddenItem.Description)
+				}
+			}
+			if item.IsWeapon {
+				result += fmt.Sprintf("      Weapon: Damage=%.2f, UsesAmmo=%t\n", item.WeaponDamage, item.UsesAmmo)
+			}
+			if item.IsAmmoBox {
+				result += fmt.Sprintf("      AmmoBox: %s (%d rounds)\n", item.AmmoType, item.AmmoCount)
+			}
+			if item.IsHealthItem {
+				result += fmt.Sprintf("      HealthItem: %s\n", item.HealthEffect)
+			}
+		}
+		result += fmt.Sprintf("  Doors: %d\n", len(room.Doors))
+		for i, door := range room.Doors {
+			result += fmt.Sprintf("    %d. %s (%s) -> %s\n", i+1, door.Name, door.Location, door.RoomB)
+			if door.HasKeyLock || door.HasCodeLock {
+				result += fmt.Sprintf("      Locked: %t, KeyLock: %t, CodeLock: %t\n", door.IsLocked, door.HasKeyLock, door.HasCodeLock)
+			} else {
+				result += fmt.Sprintf("      Lock status: unknown (not tried)\n")
+			}
+		}
+		result += "\n"
+	}
+
+	// Enemies
+	result += "=== ENEMIES ===\n"
+	for i, enemy := range d.Enemies {
+		result += fmt.Sprintf("%d. %s (%s)\n", i+1, enemy.Name, enemy.Description)
+		result += fmt.Sprintf("   Room: %s, HP: %d, Alive: %t, State: %s\n", enemy.Room, enemy.HP, enemy.IsAlive, enemy.State)
+	}
+	result += "\n"
+
+	// Triggers
+	result += "=== TRIGGERS ===\n"
+	for i, trigger := range d.Triggers {
+		result += fmt.Sprintf("%d. Event: %s (%s)\n", i+1, trigger.EventType, trigger.EventName)
+		result += fmt.Sprintf("   Effect: %s -> %s\n", trigger.EffectType, trigger.EnemyName)
+	}
+	result += "\n"
+
+	// Win Condition
+	result += "=== WIN CONDITION ===\n"
+	if d.WinCondition != nil {
+		result += fmt.Sprintf("Event: %s\n", d.WinCondition.EventType)
+		if d.WinCondition.RoomName != "" {
+			result += fmt.Sprintf("Room: %s\n", d.WinCondition.RoomName)
+		}
+		if d.WinCondition.ItemName != "" {
+			result += fmt.Sprintf("Item: %s\n", d.WinCondition.ItemName)
+		}
+	} else {
+		result += "None\n"
+	}
+
+	// Trigger Loop
+	if len(d.TriggerLoop) > 0 {
+		result += "\n=== LAST TRIGGER LOOP ===\n"
+		for i, event := range d.TriggerLoop {
+			result += fmt.Sprintf("%d. %s\n", i+1, event)
+		}
+	}
+
+	// Debug Log
+	if len(d.Log) > 0 {
+		result += "\n=== DEBUG LOG ===\n"
+		for _, entry := range d.Log {
+			result += fmt.Sprintf("[turn %d, v%d] %s: %s\n", entry.Turn, entry.StateVersion, entry.Kind, entry.Message)
+		}
+	}
+
+	return result
+}
+
+// DebugTriggerInfo contains debug information about a trigger.
+type DebugTriggerInfo struct {
+	EventType  string
+	EventName  string
+	EffectType string
+	EnemyName  string
+}
+
+// DebugEventInfo contains debug information about an event.
+type DebugEventInfo struct {
+	EventType string
+	RoomName  string
+	ItemName  string
+}
+
+// createDebugItemInfo creates a DebugItemInfo from a world item.
+func (e *Engine) createDebugItemInfo(item *world.Item) DebugItemInfo {
+	result := DebugItemInfo{
+		Name:         item.Name,
+		ID:           item.Ref(),
+		Description:  item.Description,
+		Location:     item.Location,
+		Detail:       item.Detail,
+		IsPortable:   item.IsPortable(),
+		IsContainer:  item.IsContainer(),
+		IsConcealer:  item.IsConcealer(),
+		IsKey:        item.IsKey(),
+		IsAmmoBox:    item.IsAmmoBox(),
+		IsWeapon:     item.IsWeapon(),
+		IsHealthItem: item.IsHealthItem(),
+	}
+
+	if item.IsContainer() {
+		result.HasKeyLock = item.Container.HasKeyLock()
+		result.HasCodeLock = item.Container.HasCodeLock()
+		result.IsLocked = item.Container.IsLocked()
+		result.IsSearched = item.Container.Searched
+		if !item.Container.IsEmpty() {
+			result.Contains = e.createDebugItemInfoPtr(item.Container.Contains)
+		}
+	}
+
+	if item.IsWeapon() {
+		result.WeaponDamage = item.Weapon.Damage
+		result.UsesAmmo = item.Weapon.UsesAmmo()
+		if item.Weapon.Ammo != nil {
+			result.AmmoQuantity = item.Weapon.Ammo.Quantity
+		}
+	}
+
+	if item.IsAmmoBox() {
+		result.AmmoType = item.AmmoBox.AmmoType
+		result.AmmoCount = item.AmmoBox.Ammo.Quantity
+	}
+
+	if item.IsHealthItem() {
+		switch item.HealthItem.HealthEffect {
+		case world.HealthBoostWeak:
+			result.HealthEffect = "weak"
+		case world.HealthBoostStrong:
+			result.HealthEffect = "strong"
+		}
+	}
+
+	if item.IsConcealer() {
+		result.IsUncovered = item.Concealer.Uncovered
+		if item.Concealer.Hidden != nil {
+			result.HiddenItem = e.createDebugItemInfoPtr(item.Concealer.Hidden)
+		}
+	}
+
+	return result
+}
+
+// createDebugItemInfoPtr creates a pointer to DebugItemInfo from a world item.
+func (e *Engine) createDebugItemInfoPtr(item *world.Item) *DebugItemInfo {
+	if item == nil {
+		return nil
+	}
+	info := e.createDebugItemInfo(item)
+	return &info
+}
+
+// createDebugDoorInfo creates a DebugDoorInfo from a world door.
+func (e *Engine) createDebugDoorInfo(door *world.Door, location string) DebugDoorInfo {
+	result := DebugDoorInfo{
+		Name:     door.Name,
+		Location: location,
+		RoomA:    door.RoomA,
+		RoomB:    door.RoomB,
+	}
+
+	// Only show lock information if the door has been tried
+	if door.Tried {
+		result.HasKeyLock = door.HasKeyLock()
+		result.HasCodeLock = door.HasCodeLock()
+		result.IsLocked = door.IsLocked()
+		if door.Lock != nil {
+			result.KeyName = door.Lock.KeyName
+			result.Code = door.Lock.Code
+		}
+	}
+
+	return result
+}
+
+// createDebugEnemyInfo creates a DebugEnemyInfo from a world enemy.
+func (e *Engine) createDebugEnemyInfo(enemy *world.Enemy) DebugEnemyInfo {
+	return DebugEnemyInfo{
+		Name:        enemy.Name,
+		Description: enemy.Description,
+		HP:          enemy.HP,
+		IsAlive:     enemy.IsAlive(),
+		State:       enemy.State(),
+	}
+}
+
+// createDebugRoomInfo creates a DebugRoomInfo from a world room.
+func (e *Engine) createDebugRoomInfo(room *world.Room, isCurrent bool) DebugRoomInfo {
+	result := DebugRoomInfo{
+		Name:        room.Name,
+		Description: room.Description,
+		IsCurrent:   isCurrent,
+	}
+
+	// Add items
+	for _, item := range room.Items {
+		result.Items = append(result.Items, e.createDebugItemInfo(item))
+	}
+
+	// Add doors
+	for _, conn := range room.Connections {
+		// Find the actual door in the level
+		door := e.Level.GetDoor(conn.DoorName)
+		result.Doors = append(result.Doors, e.createDebugDoorInfo(door, conn.Location))
+	}
+
+	return result
+}
+
+// createDebugPlayerInfo creates a DebugPlayerInfo from the player.
+func (e *Engine) createDebugPlayerInfo() DebugPlayerInfo {
+	result := DebugPlayerInfo{
+		Health:  string(e.Player.Health),
+		IsAlive: e.Player.IsAlive(),
+		Ammo:    make(map[string]int),
+	}
+
+	// Copy ammo map
+	for weapon, count := range e.Player.Ammo {
+		result.Ammo[weapon] = count
+	}
+
+	// Add inventory items
+	for _, item := range e.Player.Inventory {
+		result.Inventory = append(result.Inventory, e.createDebugItemInfo(item))
+	}
+
+	return result
+}
+
+// createDebugTriggerInfo creates a DebugTriggerInfo from a world trigger.
+func (e *Engine) createDebugTriggerInfo(trigger *world.Trigger) DebugTriggerInfo {
+	return DebugTriggerInfo{
+		EventType:  string(trigger.Event.Event),
+		EventName:  trigger.Event.RoomName + trigger.Event.ItemName + trigger.Event.LockName, // Combine for display
+		EffectType: string(trigger.Effect.EffectType),
+		EnemyName:  trigger.Effect.EnemyName,
+	}
+}
+
+// createDebugEventInfo creates a DebugEventInfo from a world event.
+func (e *Engine) createDebugEventInfo(event *world.Event) *DebugEventInfo {
+	if event == nil {
+		return nil
+	}
+	return &DebugEventInfo{
+		EventType: string(event.Event),
+		RoomName:  event.RoomName,
+		ItemName:  event.ItemName,
+	}
+}
+
+// Debug returns complete debug information about the engine state.
+func (e *Engine) Debug() (*DebugResult, error) {
+	result := &DebugResult{
+		EngineStateInfo: *e.getEngineStateInfo(),
+		EngineState: DebugEngineState{
+			LevelCompletionState: string(e.LevelCompletionState),
+			Mode:                 string(e.Mode),
+			CurrentRoom:          e.CurrentRoom.Name,
+		},
+		Player: e.createDebugPlayerInfo(),
+	}
+	if e.Corrupted != nil {
+		result.EngineState.Corrupted = e.Corrupted.Error()
+	}
+
+	// Add fighting enemy if in combat
+	if e.FightingEnemy != nil {
+		result.EngineState.FightingEnemy = e.createDebugEnemyInfoPtr(e.FightingEnemy)
+	}
+
+	// Add rooms
+	for _, room := range e.CurrentFloor.Rooms {
+		result.Rooms = append(result.Rooms, e.createDebugRoomInfo(room, room == e.CurrentRoom))
+	}
+
+	// Add enemies
+	for _, enemy := range e.Level.Enemies {
+		result.Enemies = append(result.Enemies, e.createDebugEnemyInfo(enemy))
+	}
+
+	// Add triggers
+	for _, trigger := range e.Level.Triggers {
+		result.Triggers = append(result.Triggers, e.createDebugTriggerInfo(trigger))
+	}
+
+	// Add win condition
+	result.WinCondition = e.createDebugEventInfo(e.Level.WinCondition)
+
+	// Add the last trigger loop stopped
+	for i := range e.triggerLoop {
+		result.TriggerLoop = append(result.TriggerLoop, describeEvent(&e.triggerLoop[i]))
+	}
+	result.Log = slices.Clone(e.debugLog)
+
+	return result, nil
+}
+
+// createDebugEnemyInfoPtr creates a pointer to DebugEnemyInfo from a world enemy.
+func (e *Engine) createDebugEnemyInfoPtr(enemy *world.Enemy) *DebugEnemyInfo {
+	if enemy == nil {
+		return nil
+	}
+	info := e.createDebugEnemyInfo(enemy)
+	return &info
+}
@@ -1,15 +1,43 @@
 package engine
 
 import (
-	"adventure-engine/internal/loader"
-	"adventure-engine/internal/world"
+	"adventure-engine/pkg/loader"
+	"adventure-engine/pkg/world"
+	"context"
+	"errors"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 )
 
 var debugFlag bool
 
+// ctx is the context the tests run actions under
+var ctx = context.Background()
+
+// engineItem returns the engine's copy of an item of the level it was created with.
+func engineItem(t *testing.T, engine *Engine, name string) *world.Item {
+	t.Helper()
+	for _, item := range engine.levelItems() {
+		if item.Name == name {
+			return item
+		}
+	}
+	t.Fatalf("no item named %s in the engine's level", name)
+	return nil
+}
+
+// loadTestLevel loads a level from the test data directory.
+func loadTestLevel(t *testing.T, name string) *world.Level {
+	t.Helper()
+	level, err := loader.LoadGameFromFile("../../internal/testdata/" + name)
+	if err != nil {
+		t.Fatalf("Failed to load level %s: %v", name, err)
+	}
+	return level
+}
+
 func init() {
 	debugFlag = os.Getenv("DEBUG") == "true"
 }
@@ -54,6 +82,8 @@ func TestInspectInternal(t *testing.T) {
 		Doors:        []*world.Door{testDoor},
 		WinCondition: nil,
 	})
+	// The engine plays a copy of the level
+	room = engine.CurrentRoom
 
 	// Test inspecting an item
 	result, err := engine.inspectInternal("test_item")
@@ -209,9 +239,13 @@ func TestObserve_Visibility(t *testing.T) {
 		}},
 		WinCondition: nil,
 	})
+	// The engine plays a copy of the level
+	room = engine.CurrentRoom
+	sheet = engineItem(t, engine, "sheet")
+	box = engineItem(t, engine, "box")
 
 	// Initial observe: should see regular item, sheet, and box, but not coin or gem
-	obs, err := engine.observeInternal()
+	obs, err := engine.observeInternal(ObserveFilter{})
 	if err != nil {
 		t.Errorf("Observe failed: %v", err)
 	}
@@ -246,7 +280,7 @@ func TestObserve_Visibility(t *testing.T) {
 	}
 	room.Items = append(room.Items, revealed)
 
-	obs, err = engine.observeInternal()
+	obs, err = engine.observeInternal(ObserveFilter{})
 	if err != nil {
 		t.Errorf("Observe failed: %v", err)
 	}
@@ -269,7 +303,7 @@ func TestObserve_Visibility(t *testing.T) {
 		room.Items = append(room.Items, found)
 	}
 
-	obs, err = engine.observeInternal()
+	obs, err = engine.observeInternal(ObserveFilter{})
 	if err != nil {
 		t.Errorf("Observe failed: %v", err)
 	}
@@ -325,6 +359,9 @@ func TestUncoverInternal(t *testing.T) {
 		}},
 		WinCondition: nil,
 	})
+	// The engine plays a copy of the level
+	room = engine.CurrentRoom
+	rug = engineItem(t, engine, "rug")
 
 	// Uncover the rug
 	result, err := engine.uncoverInternal("rug")
@@ -407,6 +444,8 @@ func TestSearchInternal(t *testing.T) {
 		}},
 		WinCondition: nil,
 	})
+	// The engine plays a copy of the level
+	box = engineItem(t, engine, "box")
 
 	// Search the box
 	result, err := engine.searchInternal("box")
@@ -487,6 +526,8 @@ func TestSearch_LockedContainer(t *testing.T) {
 		}},
 		WinCondition: nil,
 	})
+	// The engine plays a copy of the level
+	safe = engineItem(t, engine, "safe")
 
 	// Attempt to search the locked safe (should fail)
 	_, err := engine.searchInternal("safe")
@@ -575,7 +616,7 @@ func TestTakeInternal(t *testing.T) {
 	})
 
 	// Take the coin from the room
-	result, err := engine.takeInternal("coin")
+	result, err := engine.takeInternal("coin", 0)
 	if err != nil {
 		t.Errorf("Take failed: %v", err)
 	}
@@ -587,13 +628,13 @@ func TestTakeInternal(t *testing.T) {
 	}
 
 	// Try to take the rock (not portable)
-	_, err = engine.takeInternal("rock")
+	_, err = engine.takeInternal("rock", 0)
 	if err == nil {
 		t.Error("Expected error when taking non-portable item, got nil")
 	}
 
 	// Take the key from the box (container)
-	result, err = engine.takeInternal("key")
+	result, err = engine.takeInternal("key", 0)
 	if err != nil {
 		t.Errorf("Take from container failed: %v", err)
 	}
@@ -605,7 +646,7 @@ func TestTakeInternal(t *testing.T) {
 	}
 
 	// Try to take an item that doesn't exist
-	_, err = engine.takeInternal("banana")
+	_, err = engine.takeInternal("banana", 0)
 	if err == nil {
 		t.Error("Expected error when taking non-existent item, got nil")
 	}
@@ -759,6 +800,27 @@ func TestTraverseInternal(t *testing.T) {
 	}
 }
 
+func TestTraverse_OneWay(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "one_way.json"))
+
+	if _, err := engine.Traverse(ctx, "south"); err != nil {
+		t.Fatalf("Expected to drop into the cavern, got %v", err)
+	}
+	observe, err := engine.Observe(ctx, ObserveFilter{})
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if len(observe.Result.Doors) != 1 || !observe.Result.Doors[0].IsOneWay {
+		t.Errorf("Expected the drop to be shown as one-way, got %+v", observe.Result.Doors)
+	}
+	if _, err := engine.Traverse(ctx, "north"); !errors.Is(err, ErrOneWay) {
+		t.Errorf("Expected the drop not to be climbed back up, got %v", err)
+	}
+	if engine.CurrentRoom.Name != "cavern" {
+		t.Errorf("Expected to stay in the cavern, got %s", engine.CurrentRoom.Name)
+	}
+}
+
 func TestTake_UncoverConcealer(t *testing.T) {
 	// Create a hidden item
 	hidden := &world.Item{
@@ -802,9 +864,12 @@ func TestTake_UncoverConcealer(t *testing.T) {
 		}},
 		WinCondition: nil,
 	})
+	// The engine plays a copy of the level
+	room = engine.CurrentRoom
+	rug = engineItem(t, engine, "rug")
 
 	// Take the rug (should trigger uncover and return the hidden item)
-	result, err := engine.takeInternal("rug")
+	result, err := engine.takeInternal("rug", 0)
 	if err != nil {
 		t.Errorf("Take failed: %v", err)
 	}
@@ -825,9 +890,16 @@ func TestTake_UncoverConcealer(t *testing.T) {
 	if !found {
 		t.Error("Expected hidden_gem to be in the room after uncovering via take")
 	}
+	// The rug itself is not taken
+	if _, err := room.GetItem("rug"); err != nil {
+		t.Error("Expected rug to stay in the room after uncovering via take")
+	}
+	if len(engine.Player.Inventory) != 0 {
+		t.Errorf("Expected nothing in inventory after uncovering via take, got %d items", len(engine.Player.Inventory))
+	}
 
 	// Try to take the rug again (should fail since it's already uncovered)
-	_, err = engine.takeInternal("rug")
+	_, err = engine.takeInternal("rug", 0)
 	if err == nil {
 		t.Error("Expected error when taking a concealer that is already uncovered, got nil")
 	} else if err.Error() != "you cannot take the rug" {
@@ -860,8 +932,8 @@ func TestTake_AmmoBox(t *testing.T) {
 		Location: "room",
 		Detail:   "Contains 2 shells.",
 		AmmoBox: &world.AmmoBox{
-			WeaponName: "shotgun",
-			Ammo:       &world.Ammo{Quantity: 2},
+			AmmoType: "shotgun",
+			Ammo:     &world.Ammo{Quantity: 2},
 		},
 		Portable: &world.Portable{},
 	}
@@ -886,7 +958,7 @@ func TestTake_AmmoBox(t *testing.T) {
 	engine.CurrentRoom = room
 
 	// Take the shotgun (should add to inventory, but no ammo)
-	_, err := engine.Take("shotgun")
+	_, err := engine.Take(ctx, "shotgun")
 	if err != nil {
 		t.Fatalf("Take shotgun failed: %v", err)
 	}
@@ -898,7 +970,7 @@ func TestTake_AmmoBox(t *testing.T) {
 	}
 
 	// Take the ammo box (should add 2 rounds to shotgun ammo)
-	_, err = engine.Take("shotgun_ammo_box")
+	_, err = engine.Take(ctx, "shotgun_ammo_box")
 	if err != nil {
 		t.Fatalf("Take ammo box failed: %v", err)
 	}
@@ -907,6 +979,25 @@ func TestTake_AmmoBox(t *testing.T) {
 	}
 }
 
+func TestInventory_AmmoSortedByWeapon(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "interrupt.json"))
+	engine.Player.Ammo = map[string]int{"shotgun": 2, "crossbow": 5, "magnum": 0, "handgun": 12}
+
+	for range 10 {
+		inventory, err := engine.Inventory(ctx)
+		if err != nil {
+			t.Fatalf("Inventory failed: %v", err)
+		}
+		var weapons []string
+		for _, ammo := range inventory.Result.Ammo {
+			weapons = append(weapons, ammo.AmmoType)
+		}
+		if !slices.Equal(weapons, []string{"crossbow", "handgun", "magnum", "shotgun"}) {
+			t.Fatalf("Expected ammo sorted by weapon, got %v", weapons)
+		}
+	}
+}
+
 func TestUnlockInternal(t *testing.T) {
 	// Create a key for testing
 	key := &world.Item{
@@ -1008,6 +1099,11 @@ func TestUnlockInternal(t *testing.T) {
 		Doors:        []*world.Door{lockedDoor, codeDoor},
 		WinCondition: nil,
 	})
+	// The engine plays a copy of the level
+	lockedBox = engineItem(t, engine, "locked_box")
+	lockedSafe = engineItem(t, engine, "locked_safe")
+	lockedDoor = engine.Level.GetDoor("locked_door")
+	codeDoor = engine.Level.GetDoor("code_door")
 
 	// Add the key to player's inventory
 
@@ -1678,7 +1774,7 @@ func TestEventHandling_LevelCompletionOnRoomEntry(t *testing.T) {
 	engine.CurrentRoom = room1
 
 	// Traverse to Room2 (should NOT trigger win condition)
-	result, err := engine.Traverse("door1")
+	result, err := engine.Traverse(ctx, "door1")
 	if err != nil {
 		t.Fatalf("Traverse to Room2 failed: %v", err)
 	}
@@ -1690,7 +1786,7 @@ func TestEventHandling_LevelCompletionOnRoomEntry(t *testing.T) {
 	}
 
 	// Traverse to Room3 (should trigger win condition)
-	result, err = engine.Traverse("door2")
+	result, err = engine.Traverse(ctx, "door2")
 	if err != nil {
 		t.Fatalf("Traverse to Room3 failed: %v", err)
 	}
@@ -1771,7 +1867,7 @@ func TestEventHandling_EnterCombatOnTakeGem(t *testing.T) {
 	engine.CurrentRoom = room
 
 	// Take the rock (should NOT trigger combat)
-	result, err := engine.Take("rock")
+	result, err := engine.Take(ctx, "rock")
 	if err != nil {
 		t.Fatalf("Take rock failed: %v", err)
 	}
@@ -1783,7 +1879,7 @@ func TestEventHandling_EnterCombatOnTakeGem(t *testing.T) {
 	}
 
 	// Take the gem (should trigger combat)
-	result, err = engine.Take("gem")
+	result, err = engine.Take(ctx, "gem")
 	if err != nil {
 		t.Fatalf("Take gem failed: %v", err)
 	}
@@ -1909,7 +2005,7 @@ func TestEventHandling_CombinedEvents(t *testing.T) {
 	engine.CurrentRoom = startRoom
 
 	// 1. Search the chest
-	searchResult, err := engine.Search("chest")
+	searchResult, err := engine.Search(ctx, "chest")
 	if err != nil {
 		t.Fatalf("Search chest failed: %v", err)
 	}
@@ -1918,7 +2014,7 @@ func TestEventHandling_CombinedEvents(t *testing.T) {
 	}
 
 	// 2. Take the key (should trigger combat)
-	takeResult, err := engine.Take("key")
+	takeResult, err := engine.Take(ctx, "key")
 	if err != nil {
 		t.Fatalf("Take key failed: %v", err)
 	}
@@ -1940,7 +2036,7 @@ func TestEventHandling_CombinedEvents(t *testing.T) {
 	fakeRng := &FakeRng{}
 	fakeRng.SetValue(0.0) // Always win
 	engine.Rng = fakeRng
-	battleResult, err := engine.Battle("") // Use fists
+	battleResult, err := engine.Battle(ctx, "") // Use fists
 	if err != nil {
 		t.Fatalf("Battle failed: %v", err)
 	}
@@ -1958,7 +2054,7 @@ func TestEventHandling_CombinedEvents(t *testing.T) {
 	}
 
 	// 4. Unlock the door with the key
-	unlockResult, err := engine.Unlock("key", "treasure_door")
+	unlockResult, err := engine.Unlock(ctx, "key", "treasure_door")
 	if err != nil {
 		t.Fatalf("Unlock door failed: %v", err)
 	}
@@ -1967,7 +2063,7 @@ func TestEventHandling_CombinedEvents(t *testing.T) {
 	}
 
 	// 5. Traverse to the treasure room (should trigger win notification)
-	traverseResult, err := engine.Traverse("treasure_door")
+	traverseResult, err := engine.Traverse(ctx, "treasure_door")
 	if err != nil {
 		t.Fatalf("Traverse to treasure room failed: %v", err)
 	}
@@ -2007,8 +2103,8 @@ func TestFireWeapon_WithAndWithoutAmmo(t *testing.T) {
 		Location: "room",
 		Detail:   "Contains 3 rounds.",
 		AmmoBox: &world.AmmoBox{
-			WeaponName: "pistol",
-			Ammo:       &world.Ammo{Quantity: 3},
+			AmmoType: "pistol",
+			Ammo:     &world.Ammo{Quantity: 3},
 		},
 		Portable: &world.Portable{},
 	}
@@ -2033,7 +2129,7 @@ func TestFireWeapon_WithAndWithoutAmmo(t *testing.T) {
 	engine.CurrentRoom = room
 
 	// Take the pistol
-	_, err := engine.Take("pistol")
+	_, err := engine.Take(ctx, "pistol")
 	if err != nil {
 		t.Fatalf("Take pistol failed: %v", err)
 	}
@@ -2048,7 +2144,7 @@ func TestFireWeapon_WithAndWithoutAmmo(t *testing.T) {
 	}
 
 	// Take the ammo box (should add 3 rounds)
-	_, err = engine.Take("pistol_ammo_box")
+	_, err = engine.Take(ctx, "pistol_ammo_box")
 	if err != nil {
 		t.Fatalf("Take ammo box failed: %v", err)
 	}
@@ -2097,7 +2193,7 @@ func TestTake_RemovesItemFromRoom(t *testing.T) {
 	engine.CurrentRoom = room
 
 	// Take the item
-	_, err := engine.Take("coin")
+	_, err := engine.Take(ctx, "coin")
 	if err != nil {
 		t.Fatalf("Take coin failed: %v", err)
 	}
@@ -2110,6 +2206,34 @@ func TestTake_RemovesItemFromRoom(t *testing.T) {
 	}
 }
 
+func TestValidation_CorruptedState(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "flooded.json"))
+	sound := engine.Snapshot()
+
+	// Combat with no one to fight can't happen in play
+	engine.Mode = Combat
+	_, err := engine.Inspect(ctx, "air tank")
+	if !errors.Is(err, ErrCorrupted) || ErrorCodeOf(err) != ErrorCodeCorrupted {
+		t.Fatalf("Expected the broken invariant to be reported, got %v", err)
+	}
+	engine.Mode = Investigation
+	if _, err := engine.Inspect(ctx, "air tank"); !errors.Is(err, ErrCorrupted) {
+		t.Errorf("Expected the engine to stay corrupted, got %v", err)
+	}
+	engine.DisableValidation()
+	if _, err := engine.Observe(ctx, ObserveFilter{}); err != nil {
+		t.Errorf("Expected to look around a corrupted engine with validation off, got %v", err)
+	}
+	engine.EnableValidation()
+
+	if _, err := engine.Restore(sound); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if _, err := engine.Inspect(ctx, "air tank"); err != nil {
+		t.Errorf("Expected restoring a sound snapshot to clear the corruption, got %v", err)
+	}
+}
+
 func TestValidation_ActionNotAllowedInMode(t *testing.T) {
 	// Create a simple room setup
 	room := &world.Room{
@@ -2132,7 +2256,7 @@ func TestValidation_ActionNotAllowedInMode(t *testing.T) {
 	engine.CurrentRoom = room
 
 	// Test 1: Cannot battle while in investigation mode
-	_, err := engine.Battle("")
+	_, err := engine.Battle(ctx, "")
 	if err == nil {
 		t.Error("Expected error when trying to battle in investigation mode, got nil")
 	} else if err.Error() != "cannot perform this action in investigation mode" {
@@ -2151,7 +2275,7 @@ func TestValidation_ActionNotAllowedInMode(t *testing.T) {
 	engine.FightingEnemy = enemy
 	engine.Mode = Combat
 
-	_, err = engine.Traverse("door1")
+	_, err = engine.Traverse(ctx, "door1")
 	if err == nil {
 		t.Error("Expected error when trying to traverse in combat mode, got nil")
 	} else if err.Error() != "cannot perform this action in combat mode" {
@@ -2164,7 +2288,7 @@ func TestValidation_ActionNotAllowedInMode(t *testing.T) {
 	engine.Mode = Investigation // Reset to investigation mode
 	engine.FightingEnemy = nil  // Clear the fighting enemy
 
-	_, err = engine.Observe()
+	_, err = engine.Observe(ctx, ObserveFilter{})
 	if err == nil {
 		t.Error("Expected error when trying to observe after level completion, got nil")
 	} else if err.Error() != "level is already complete" {
@@ -2291,7 +2415,7 @@ func TestEventHandling_CombatEntryExitMultipleEnemies(t *testing.T) {
 	engine.Rng = fakeRng
 
 	// Test 1: Enter Room2 (should trigger combat with goblin)
-	result, err := engine.Traverse("door1")
+	result, err := engine.Traverse(ctx, "door1")
 	if err != nil {
 		t.Fatalf("Traverse to Room2 failed: %v", err)
 	}
@@ -2310,7 +2434,7 @@ func TestEventHandling_CombatEntryExitMultipleEnemies(t *testing.T) {
 
 	// Test 2: Defeat the goblin (should exit combat)
 	fakeRng.SetValue(0.0) // Always win
-	battleResult, err := engine.Battle("")
+	battleResult, err := engine.Battle(ctx, "")
 	if err != nil {
 		t.Fatalf("Battle with goblin failed: %v", err)
 	}
@@ -2328,7 +2452,7 @@ func TestEventHandling_CombatEntryExitMultipleEnemies(t *testing.T) {
 	}
 
 	// Test 3: Enter Room3 (should trigger combat with skeleton)
-	result, err = engine.Traverse("door2")
+	result, err = engine.Traverse(ctx, "door2")
 	if err != nil {
 		t.Fatalf("Traverse to Room3 failed: %v", err)
 	}
@@ -2347,7 +2471,7 @@ func TestEventHandling_CombatEntryExitMultipleEnemies(t *testing.T) {
 
 	// Test 4: Defeat the skeleton (should exit combat)
 	fakeRng.SetValue(0.0) // Always win
-	battleResult, err = engine.Battle("")
+	battleResult, err = engine.Battle(ctx, "")
 	if err != nil {
 		t.Fatalf("Battle with skeleton failed: %v", err)
 	}
@@ -2410,7 +2534,6 @@ func TestDebug_SimpleRoomSetup(t *testing.T) {
 		}},
 		WinCondition: nil,
 	})
-	engine.CurrentRoom = room
 
 	// Get debug information
 	debug, err := engine.Debug()
@@ -2626,7 +2749,7 @@ func TestDebug_SearchChestAndTakeGem(t *testing.T) {
 	}
 
 	// Search the chest
-	_, err = engine.Search("chest")
+	_, err = engine.Search(ctx, "chest")
 	if err != nil {
 		t.Fatalf("Search failed: %v", err)
 	}
@@ -2641,7 +2764,7 @@ func TestDebug_SearchChestAndTakeGem(t *testing.T) {
 	}
 
 	// Take the gem
-	_, err = engine.Take("gem")
+	_, err = engine.Take(ctx, "gem")
 	if err != nil {
 		t.Fatalf("Take failed: %v", err)
 	}
@@ -2693,7 +2816,7 @@ func TestWeaponAmmo_StartWithOneBullet(t *testing.T) {
 	})
 
 	// Take the weapon
-	takeResult, err := engine.Take("pistol")
+	takeResult, err := engine.Take(ctx, "pistol")
 	if err != nil {
 		t.Fatalf("Take pistol failed: %v", err)
 	}
@@ -2732,7 +2855,7 @@ func TestWeaponAmmo_StartWithOneBullet(t *testing.T) {
 
 func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	// Load the demo puzzle game
-	level, err := loader.LoadGameFromFile("../testdata/demo.json")
+	level, err := loader.LoadGameFromFile("../../internal/testdata/demo.json")
 	if err != nil {
 		t.Fatalf("Failed to load demo game: %v", err)
 	}
@@ -2741,7 +2864,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	engine := NewEngine(level)
 
 	// 1. Observe the room and pretty print the result
-	observeResult, err := engine.Observe()
+	observeResult, err := engine.Observe(ctx, ObserveFilter{})
 	if err != nil {
 		t.Fatalf("Observe failed: %v", err)
 	}
@@ -2777,7 +2900,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// 2. Uncover the hoodie
-	uncoverResult, err := engine.Uncover("tattered grey hoodie")
+	uncoverResult, err := engine.Uncover(ctx, "tattered grey hoodie")
 	if err != nil {
 		t.Fatalf("Uncover hoodie failed: %v", err)
 	}
@@ -2791,7 +2914,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// 3. Inspect the note
-	inspectResult, err := engine.Inspect("ominous note")
+	inspectResult, err := engine.Inspect(ctx, "ominous note")
 	if err != nil {
 		t.Fatalf("Inspect note failed: %v", err)
 	}
@@ -2809,7 +2932,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// 4. Go north to the office
-	traverseResult, err := engine.Traverse("office door")
+	traverseResult, err := engine.Traverse(ctx, "office door")
 	if err != nil {
 		t.Fatalf("Traverse to office failed: %v", err)
 	}
@@ -2823,7 +2946,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// Observe the office
-	observeResult, err = engine.Observe()
+	observeResult, err = engine.Observe(ctx, ObserveFilter{})
 	if err != nil {
 		t.Fatalf("Observe office failed: %v", err)
 	}
@@ -2857,13 +2980,13 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// 5. Search the desk drawer
-	searchResult, err := engine.Search("desk")
+	searchResult, err := engine.Search(ctx, "desk")
 	if err != nil {
 		t.Fatalf("Search desk failed: %v", err)
 	}
 
 	// Observe the office again
-	observeResult, err = engine.Observe()
+	observeResult, err = engine.Observe(ctx, ObserveFilter{})
 	if err != nil {
 		t.Fatalf("Observe office failed: %v", err)
 	}
@@ -2893,7 +3016,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// 6. Take the pistol
-	takeResult, err := engine.Take("pistol")
+	takeResult, err := engine.Take(ctx, "pistol")
 	if err != nil {
 		t.Fatalf("Take pistol failed: %v", err)
 	}
@@ -2903,7 +3026,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// Verify pistol is in inventory
-	inventoryResult, err := engine.Inventory()
+	inventoryResult, err := engine.Inventory(ctx)
 	if err != nil {
 		t.Fatalf("Inventory failed: %v", err)
 	}
@@ -2924,7 +3047,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// 6b. Search the cardboard box
-	searchResult, err = engine.Search("cardboard box")
+	searchResult, err = engine.Search(ctx, "cardboard box")
 	if err != nil {
 		t.Fatalf("Search cardboard box failed: %v", err)
 	}
@@ -2942,7 +3065,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// 6c. Take the pistol ammo
-	takeResult, err = engine.Take("pistol ammo")
+	takeResult, err = engine.Take(ctx, "pistol ammo")
 	if err != nil {
 		t.Fatalf("Take pistol ammo failed: %v", err)
 	}
@@ -2956,7 +3079,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// 7. Go back to the first room
-	traverseResult, err = engine.Traverse("office door")
+	traverseResult, err = engine.Traverse(ctx, "office door")
 	if err != nil {
 		t.Fatalf("Traverse back to waiting room failed: %v", err)
 	}
@@ -2966,7 +3089,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// 8. Go left to storage room
-	traverseResult, err = engine.Traverse("left")
+	traverseResult, err = engine.Traverse(ctx, "left")
 	if err != nil {
 		t.Fatalf("Traverse to storage room failed: %v", err)
 	}
@@ -2976,7 +3099,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// Observe the storage room
-	observeResult, err = engine.Observe()
+	observeResult, err = engine.Observe(ctx, ObserveFilter{})
 	if err != nil {
 		t.Fatalf("Observe storage room failed: %v", err)
 	}
@@ -3011,7 +3134,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// 9. Uncover the tarp
-	uncoverResult, err = engine.Uncover("dark green tarp")
+	uncoverResult, err = engine.Uncover(ctx, "dark green tarp")
 	if err != nil {
 		t.Fatalf("Uncover tarp failed: %v", err)
 	}
@@ -3025,7 +3148,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// Observe the storage room after uncovering the safe
-	observeResult, err = engine.Observe()
+	observeResult, err = engine.Observe(ctx, ObserveFilter{})
 	if err != nil {
 		t.Fatalf("Observe storage room after uncovering failed: %v", err)
 	}
@@ -3042,7 +3165,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// 10. Enter a wrong code in the safe
-	_, err = engine.Unlock("1234", "safe")
+	_, err = engine.Unlock(ctx, "1234", "safe")
 	if err == nil {
 		t.Error("Expected error when entering wrong code, got nil")
 	}
@@ -3052,13 +3175,13 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// 10b. try searching the safe
-	_, err = engine.Search("safe")
+	_, err = engine.Search(ctx, "safe")
 	if err == nil {
 		t.Fatalf("Expected error when searching locked safe, got nil")
 	}
 
 	// 11. Enter the correct code
-	unlockResult, err := engine.Unlock("2468", "safe")
+	unlockResult, err := engine.Unlock(ctx, "2468", "safe")
 	if err != nil {
 		t.Fatalf("Unlock safe with correct code failed: %v", err)
 	}
@@ -3071,7 +3194,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 		t.Logf("Safe unlocked successfully")
 	}
 
-	observeResult, err = engine.Observe()
+	observeResult, err = engine.Observe(ctx, ObserveFilter{})
 	if err != nil {
 		t.Fatalf("Observe safe after unlocking failed: %v", err)
 	}
@@ -3088,9 +3211,9 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// 12. Search the safe
-	searchResult, _ = engine.Search("safe")
+	searchResult, _ = engine.Search(ctx, "safe")
 
-	observeResult, err = engine.Observe()
+	observeResult, err = engine.Observe(ctx, ObserveFilter{})
 	if err != nil {
 		t.Fatalf("Observe safe after unlocking failed: %v", err)
 	}
@@ -3123,7 +3246,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// 13. Take the iron key (this should trigger combat!)
-	takeResult, err = engine.Take("iron key")
+	takeResult, err = engine.Take(ctx, "iron key")
 	if err != nil {
 		t.Fatalf("Take iron key failed: %v", err)
 	}
@@ -3160,7 +3283,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 		t.Logf("Before battle - pistol ammo: %d", engine.Player.Ammo["pistol"])
 	}
 
-	battleResult, err := engine.Battle("pistol")
+	battleResult, err := engine.Battle(ctx, "pistol")
 	if err != nil {
 		t.Fatalf("Battle with pistol failed: %v", err)
 	}
@@ -3196,13 +3319,13 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 
 	// 15. Use the key to complete the level
 	// First, go back to waiting room
-	_, err = engine.Traverse("storage room door")
+	_, err = engine.Traverse(ctx, "storage room door")
 	if err != nil {
 		t.Fatalf("Traverse back to waiting room failed: %v", err)
 	}
 
 	// Unlock the metal stairwell door
-	unlockResult, err = engine.Unlock("iron key", "metal stairwell door")
+	unlockResult, err = engine.Unlock(ctx, "iron key", "metal stairwell door")
 	if err != nil {
 		t.Fatalf("Unlock metal stairwell door failed: %v", err)
 	}
@@ -3216,7 +3339,7 @@ func TestIntegration_DemoPuzzleComplete(t *testing.T) {
 	}
 
 	// Traverse to the stairwell (this should trigger win condition!)
-	traverseResult, err = engine.Traverse("metal stairwell door")
+	traverseResult, err = engine.Traverse(ctx, "metal stairwell door")
 	if err != nil {
 		t.Fatalf("Traverse to stairwell failed: %v", err)
 	}
@@ -3699,7 +3822,7 @@ func TestFloors_MultiFloorTraversal(t *testing.T) {
 	}
 
 	// Test observing the first room
-	obs, err := engine.observeInternal()
+	obs, err := engine.observeInternal(ObserveFilter{})
 	if err != nil {
 		t.Fatalf("Failed to observe first room: %v", err)
 	}
@@ -3736,7 +3859,7 @@ func TestFloors_MultiFloorTraversal(t *testing.T) {
 	}
 
 	// Test observing the second room
-	obs, err = engine.observeInternal()
+	obs, err = engine.observeInternal(ObserveFilter{})
 	if err != nil {
 		t.Fatalf("Failed to observe second room: %v", err)
 	}
@@ -3769,7 +3892,7 @@ func TestFloors_MultiFloorTraversal(t *testing.T) {
 	}
 
 	// Test observing the roof room
-	obs, err = engine.observeInternal()
+	obs, err = engine.observeInternal(ObserveFilter{})
 	if err != nil {
 		t.Fatalf("Failed to observe roof room: %v", err)
 	}
@@ -3870,7 +3993,7 @@ func TestObserve_DoorLockStatusHiddenUntilTried(t *testing.T) {
 	})
 
 	// Observe the room before trying the door
-	result, err := engine.Observe()
+	result, err := engine.Observe(ctx, ObserveFilter{})
 	if err != nil {
 		t.Fatalf("Failed to observe room: %v", err)
 	}
@@ -3900,13 +4023,13 @@ func TestObserve_DoorLockStatusHiddenUntilTried(t *testing.T) {
 	}
 
 	// Now try to traverse the door (this should fail due to lock, but set Tried=true)
-	_, err = engine.Traverse("north")
+	_, err = engine.Traverse(ctx, "north")
 	if err == nil {
 		t.Fatal("Expected traversal to fail due to locked door")
 	}
 
 	// Observe the room again after trying the door
-	result, err = engine.Observe()
+	result, err = engine.Observe(ctx, ObserveFilter{})
 	if err != nil {
 		t.Fatalf("Failed to observe room after trying door: %v", err)
 	}
@@ -3935,3 +4058,192 @@ func TestObserve_DoorLockStatusHiddenUntilTried(t *testing.T) {
 		t.Error("Expected IsLatched to be false")
 	}
 }
+
+func TestMinimap_Directions(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "minimap_directions.json"))
+
+	minimap, err := engine.Minimap(ctx)
+	if err != nil {
+		t.Fatalf("Minimap failed: %v", err)
+	}
+	if len(minimap.Result.Floors[0].Doors) != 1 {
+		t.Fatalf("Expected 1 door, got %d", len(minimap.Result.Floors[0].Doors))
+	}
+	// The unvisited garden is not revealed yet, but the door's direction is
+	door := minimap.Result.Floors[0].Doors[0]
+	if door.RoomA != "" || door.RoomB != "hall" {
+		t.Errorf("Expected only the hall side of the door to be revealed, got %q to %q", door.RoomA, door.RoomB)
+	}
+	if door.Direction != world.DirectionWest {
+		t.Errorf("Expected direction from garden to hall to be west, got %q", door.Direction)
+	}
+
+	// Doors can be traversed by direction as well as by location
+	if _, err := engine.Traverse(ctx, "east"); err != nil {
+		t.Fatalf("Failed to traverse east: %v", err)
+	}
+	if engine.CurrentRoom.Name != "garden" {
+		t.Errorf("Expected to be in garden, got %s", engine.CurrentRoom.Name)
+	}
+
+	minimap, err = engine.Minimap(ctx)
+	if err != nil {
+		t.Fatalf("Minimap failed: %v", err)
+	}
+	door = minimap.Result.Floors[0].Doors[0]
+	if door.RoomA != "garden" || door.RoomB != "hall" || !door.Traversed {
+		t.Errorf("Expected traversed door from garden to hall, got %+v", door)
+	}
+}
+
+func TestMinimap_RoomPositions(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "room_positions.json"))
+
+	minimap, err := engine.Minimap(ctx)
+	if err != nil {
+		t.Fatalf("Minimap failed: %v", err)
+	}
+
+	// Rooms without a position are laid out from the garden by following directions
+	expected := map[string]*world.Position{
+		"hall":    {X: 4, Y: 5},
+		"garden":  {X: 5, Y: 5},
+		"kitchen": {X: 4, Y: 6},
+		"closet":  nil,
+	}
+	for _, room := range minimap.Result.Floors[0].Rooms {
+		want := expected[room.Name]
+		switch {
+		case want == nil && room.Position != nil:
+			t.Errorf("Expected %s to have no position, got %+v", room.Name, *room.Position)
+		case want != nil && (room.Position == nil || *room.Position != *want):
+			t.Errorf("Expected %s at %+v, got %+v", room.Name, *want, room.Position)
+		}
+	}
+}
+
+func TestMinimap_FogOfWar(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "fog_of_war.json"))
+	engine.Rng = &FakeRng{Value: 0.0}
+
+	if _, err := engine.Traverse(ctx, "vault door"); err == nil {
+		t.Fatal("Expected vault door to be locked")
+	}
+	if _, err := engine.Traverse(ctx, "south"); err != nil {
+		t.Fatalf("Failed to traverse south: %v", err)
+	}
+
+	minimap, err := engine.Minimap(ctx)
+	if err != nil {
+		t.Fatalf("Minimap failed: %v", err)
+	}
+	rooms := make(map[string]MinimapRoomInfo)
+	for _, room := range minimap.Result.Floors[0].Rooms {
+		rooms[room.Name] = room
+	}
+	doors := make(map[string]MinimapDoorInfo)
+	for _, door := range minimap.Result.Floors[0].Doors {
+		doors[door.Name] = door
+	}
+
+	if rooms["hall"].Hidden || rooms["cellar"].Hidden {
+		t.Error("Expected visited rooms to be revealed")
+	}
+	if !rooms["tunnel"].Adjacent || !rooms["vault"].Adjacent {
+		t.Error("Expected rooms behind doors of visited rooms to be adjacent")
+	}
+	if !slices.Equal(rooms["hall"].Icons, []MinimapIcon{MinimapIconLockedDoor}) {
+		t.Errorf("Expected locked door icon in hall, got %v", rooms["hall"].Icons)
+	}
+	if !slices.Equal(rooms["cellar"].Icons, []MinimapIcon{MinimapIconEnemy, MinimapIconSavePoint}) {
+		t.Errorf("Expected enemy and save point icons in cellar, got %v", rooms["cellar"].Icons)
+	}
+	if rooms["tunnel"].Icons != nil {
+		t.Errorf("Expected no icons for unvisited tunnel, got %v", rooms["tunnel"].Icons)
+	}
+
+	if door := doors["cellar door"]; !door.Traversed || door.RoomA != "hall" || door.RoomB != "cellar" {
+		t.Errorf("Expected traversed cellar door from hall to cellar, got %+v", door)
+	}
+	if door := doors["tunnel door"]; door.Traversed || door.RoomA != "cellar" || door.RoomB != "" {
+		t.Errorf("Expected only the cellar side of the tunnel door, got %+v", door)
+	}
+
+	// Defeating the enemy clears its icon
+	if _, err := engine.Battle(ctx, ""); err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	minimap, err = engine.Minimap(ctx)
+	if err != nil {
+		t.Fatalf("Minimap failed: %v", err)
+	}
+	for _, room := range minimap.Result.Floors[0].Rooms {
+		if room.Name == "cellar" && !slices.Equal(room.Icons, []MinimapIcon{MinimapIconSavePoint}) {
+			t.Errorf("Expected only the save point icon after the fight, got %v", room.Icons)
+		}
+	}
+}
+
+func TestMinimap_Floors(t *testing.T) {
+	level, err := loader.LoadGameFromFile("../../internal/testdata/floors.json")
+	if err != nil {
+		t.Fatalf("Failed to load level: %v", err)
+	}
+	engine := NewEngine(level)
+	if _, err := engine.Traverse(ctx, "first floor stairwell door"); err != nil {
+		t.Fatalf("Failed to take the stairs: %v", err)
+	}
+
+	minimap, err := engine.Minimap(ctx)
+	if err != nil {
+		t.Fatalf("Minimap failed: %v", err)
+	}
+	if minimap.Result.CurrentFloor != "second floor" {
+		t.Errorf("Expected current floor to be second floor, got %s", minimap.Result.CurrentFloor)
+	}
+	if len(minimap.Result.Floors) != 3 {
+		t.Fatalf("Expected 3 floors, got %d", len(minimap.Result.Floors))
+	}
+
+	floors := make(map[string]MinimapFloorInfo)
+	for _, floor := range minimap.Result.Floors {
+		floors[floor.Name] = floor
+	}
+	if !floors["second floor"].Current || floors["first floor"].Current {
+		t.Error("Expected only the second floor to be current")
+	}
+	if !floors["first floor"].Visited || !floors["second floor"].Visited || floors["roof"].Visited {
+		t.Error("Expected the first and second floors to be visited, but not the roof")
+	}
+	if len(floors["second floor"].Rooms) != 2 {
+		t.Errorf("Expected 2 rooms on the second floor, got %d", len(floors["second floor"].Rooms))
+	}
+
+	// The stairwell is listed on both floors, pointing at the other one
+	for floorName, leadsTo := range map[string]string{"first floor": "second floor", "second floor": "first floor"} {
+		found := false
+		for _, door := range floors[floorName].Doors {
+			if door.Name == "first floor stairwell door" {
+				found = true
+				if !door.Stairwell || door.LeadsToFloor != leadsTo {
+					t.Errorf("Expected stairwell to %s on %s, got %+v", leadsTo, floorName, door)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Expected first floor stairwell door on %s", floorName)
+		}
+	}
+
+	// The stairs to the roof are seen, but the roof hasn't been revealed
+	for _, door := range floors["roof"].Doors {
+		if door.Name == "second floor stairwell door" && (door.RoomB != "" || door.LeadsToFloor != "second floor") {
+			t.Errorf("Expected stairwell from the unrevealed roof to the second floor, got %+v", door)
+		}
+	}
+	for _, door := range floors["second floor"].Doors {
+		if door.Name == "second floor stairwell door" && door.LeadsToFloor != "" {
+			t.Errorf("Expected the roof to stay unrevealed, got %+v", door)
+		}
+	}
+}
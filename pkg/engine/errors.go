@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"errors"
+)
+
+// Kinds of failure when the engine rejects an action.
+// Errors returned by actions wrap one of these, so callers can branch on the reason with
+// errors.Is. The kinds shared with the world are re-exported here.
+var (
+	ErrNotFound        = world.ErrNotFound
+	ErrInvalidTarget   = world.ErrInvalidTarget
+	ErrLocked          = world.ErrLocked
+	ErrNoLock          = world.ErrNoLock
+	ErrWrongKey        = world.ErrWrongKey
+	ErrWrongCode       = world.ErrWrongCode
+	ErrAlreadyUnlocked = world.ErrAlreadyUnlocked
+	ErrJammed          = world.ErrJammed
+	ErrNoAmmo          = world.ErrNoAmmo
+
+	ErrAmbiguousName    = errors.New("ambiguous name")
+	ErrLatched          = errors.New("latched")
+	ErrAlreadyUncovered = errors.New("already uncovered")
+	ErrFullHealth       = errors.New("full health")
+	ErrWrongMode        = errors.New("wrong mode")
+	ErrLevelOver        = errors.New("level over")
+	ErrInvalidArgument  = errors.New("invalid argument")
+	ErrNotYourTurn      = errors.New("not your turn")
+	ErrBroken           = errors.New("broken")
+	ErrAlreadyMoved     = errors.New("already moved")
+	ErrOneWay           = errors.New("one way")
+	ErrUnpowered        = errors.New("unpowered")
+	ErrQuestItem        = errors.New("quest item")      // the action would lose an item the level protects
+	ErrRefused          = errors.New("refused")         // a plugin refused the action
+	ErrInterrupted      = errors.New("interrupted")     // the action's context ended before it was done
+	ErrTriggerLoop      = errors.New("trigger loop")    // the events the action caused set each other off without end
+	ErrVersionExpired   = errors.New("version expired") // a state version too old to diff against
+	ErrCorrupted        = errors.New("corrupted")       // the game state broke one of the engine's invariants
+)
+
+// ErrorCode is a machine-readable reason for a failed action.
+type ErrorCode string
+
+const (
+	ErrorCodeNotFound         ErrorCode = "not_found"
+	ErrorCodeInvalidTarget    ErrorCode = "invalid_target"
+	ErrorCodeLocked           ErrorCode = "locked"
+	ErrorCodeNoLock           ErrorCode = "no_lock"
+	ErrorCodeWrongKey         ErrorCode = "wrong_key"
+	ErrorCodeWrongCode        ErrorCode = "wrong_code"
+	ErrorCodeAlreadyUnlocked  ErrorCode = "already_unlocked"
+	ErrorCodeJammed           ErrorCode = "jammed"
+	ErrorCodeNoAmmo           ErrorCode = "no_ammo"
+	ErrorCodeAmbiguousName    ErrorCode = "ambiguous_name"
+	ErrorCodeLatched          ErrorCode = "latched"
+	ErrorCodeAlreadyUncovered ErrorCode = "already_uncovered"
+	ErrorCodeFullHealth       ErrorCode = "full_health"
+	ErrorCodeWrongMode        ErrorCode = "wrong_mode"
+	ErrorCodeLevelOver        ErrorCode = "level_over"
+	ErrorCodeInvalidArgument  ErrorCode = "invalid_argument"
+	ErrorCodeNotYourTurn      ErrorCode = "not_your_turn"
+	ErrorCodeBroken           ErrorCode = "broken"
+	ErrorCodeAlreadyMoved     ErrorCode = "already_moved"
+	ErrorCodeOneWay           ErrorCode = "one_way"
+	ErrorCodeUnpowered        ErrorCode = "unpowered"
+	ErrorCodeQuestItem        ErrorCode = "quest_item"
+	ErrorCodeRefused          ErrorCode = "refused"
+	ErrorCodeInterrupted      ErrorCode = "interrupted"
+	ErrorCodeTriggerLoop      ErrorCode = "trigger_loop"
+	ErrorCodeVersionExpired   ErrorCode = "version_expired"
+	ErrorCodeCorrupted        ErrorCode = "corrupted"
+	ErrorCodeUnknown          ErrorCode = "unknown"
+)
+
+var errorCodes = []struct {
+	kind error
+	code ErrorCode
+}{
+	{ErrNotFound, ErrorCodeNotFound},
+	{ErrInvalidTarget, ErrorCodeInvalidTarget},
+	{ErrLocked, ErrorCodeLocked},
+	{ErrNoLock, ErrorCodeNoLock},
+	{ErrWrongKey, ErrorCodeWrongKey},
+	{ErrWrongCode, ErrorCodeWrongCode},
+	{ErrAlreadyUnlocked, ErrorCodeAlreadyUnlocked},
+	{ErrJammed, ErrorCodeJammed},
+	{ErrNoAmmo, ErrorCodeNoAmmo},
+	{ErrAmbiguousName, ErrorCodeAmbiguousName},
+	{ErrLatched, ErrorCodeLatched},
+	{ErrAlreadyUncovered, ErrorCodeAlreadyUncovered},
+	{ErrFullHealth, ErrorCodeFullHealth},
+	{ErrWrongMode, ErrorCodeWrongMode},
+	{ErrLevelOver, ErrorCodeLevelOver},
+	{ErrInvalidArgument, ErrorCodeInvalidArgument},
+	{ErrNotYourTurn, ErrorCodeNotYourTurn},
+	{ErrBroken, ErrorCodeBroken},
+	{ErrAlreadyMoved, ErrorCodeAlreadyMoved},
+	{ErrOneWay, ErrorCodeOneWay},
+	{ErrUnpowered, ErrorCodeUnpowered},
+	{ErrQuestItem, ErrorCodeQuestItem},
+	{ErrRefused, ErrorCodeRefused},
+	{ErrInterrupted, ErrorCodeInterrupted},
+	{ErrTriggerLoop, ErrorCodeTriggerLoop},
+	{ErrVersionExpired, ErrorCodeVersionExpired},
+	{ErrCorrupted, ErrorCodeCorrupted},
+}
+
+// ErrorCodeOf returns the code for an error returned by an action,
+// or ErrorCodeUnknown if the error has no kind.
+func ErrorCodeOf(err error) ErrorCode {
+	for _, errorCode := range errorCodes {
+		if errors.Is(err, errorCode.kind) {
+			return errorCode.code
+		}
+	}
+	return ErrorCodeUnknown
+}
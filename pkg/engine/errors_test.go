@@ -0,0 +1,85 @@
+package engine
+
+import (
+	"adventure-engine/pkg/loader"
+	"errors"
+	"testing"
+)
+
+func TestErrors_Codes(t *testing.T) {
+	tests := []struct {
+		name   string
+		action func(e *Engine) error
+		kind   error
+		code   ErrorCode
+	}{
+		{
+			name:   "unknown item",
+			action: func(e *Engine) error { _, err := e.Take(ctx, "lamp"); return err },
+			kind:   ErrNotFound,
+			code:   ErrorCodeNotFound,
+		},
+		{
+			name:   "fixed item",
+			action: func(e *Engine) error { _, err := e.Take(ctx, "bathtub"); return err },
+			kind:   ErrInvalidTarget,
+			code:   ErrorCodeInvalidTarget,
+		},
+		{
+			name:   "locked door",
+			action: func(e *Engine) error { _, err := e.Traverse(ctx, "bedroom door"); return err },
+			kind:   ErrLocked,
+			code:   ErrorCodeLocked,
+		},
+		{
+			name: "unlock with something that is not a key",
+			action: func(e *Engine) error {
+				if _, err := e.Take(ctx, "fish hook"); err != nil {
+					return err
+				}
+				_, err := e.Unlock(ctx, "fish hook", "bedroom door")
+				return err
+			},
+			kind: ErrInvalidTarget,
+			code: ErrorCodeInvalidTarget,
+		},
+		{
+			name:   "battle outside combat",
+			action: func(e *Engine) error { _, err := e.Battle(ctx, "fists"); return err },
+			kind:   ErrWrongMode,
+			code:   ErrorCodeWrongMode,
+		},
+		{
+			name:   "ambiguous name",
+			action: func(e *Engine) error { _, err := e.Inspect(ctx, "bath"); return err },
+			kind:   ErrAmbiguousName,
+			code:   ErrorCodeAmbiguousName,
+		},
+		{
+			name:   "invalid verbosity",
+			action: func(e *Engine) error { _, err := e.Context(ctx, "verbose"); return err },
+			kind:   ErrInvalidArgument,
+			code:   ErrorCodeInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, err := loader.LoadGameFromFile("../../internal/testdata/fixture.json")
+			if err != nil {
+				t.Fatalf("Failed to load level: %v", err)
+			}
+			err = tt.action(NewEngine(level))
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("Expected %v, got %v", tt.kind, err)
+			}
+			if code := ErrorCodeOf(err); code != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, code)
+			}
+		})
+	}
+
+	if code := ErrorCodeOf(errors.New("something else")); code != ErrorCodeUnknown {
+		t.Errorf("Expected unknown code for an untyped error, got %s", code)
+	}
+}
@@ -0,0 +1,132 @@
+package engine
+
+import (
+	"slices"
+	"strings"
+
+	"adventure-engine/pkg/world"
+)
+
+// eventSubscriber handles an event for one part of the engine, such as the triggers or the
+// win condition, raising state change notifications with notify.
+type eventSubscriber func(e *Engine, event *world.Event)
+
+// eventSubscribers maps each event to its subscribers, in the order they handle it.
+// Subscribers to every event are kept under the empty event type and handle events first.
+var eventSubscribers = map[world.EventType][]eventSubscriber{}
+
+// subscribe registers a subscriber to an event, or to every event if eventType is empty.
+func subscribe(eventType world.EventType, subscriber eventSubscriber) {
+	eventSubscribers[eventType] = append(eventSubscribers[eventType], subscriber)
+}
+
+func init() {
+	subscribe("", (*Engine).processObjectives)
+	subscribe(world.EventEnemyKilled, (*Engine).collectStats)
+	subscribe(world.EventEnemyKilled, (*Engine).handleEnemyKilled)
+	subscribe(world.EventEnemyKilled, (*Engine).processWinCondition)
+	subscribe(world.EventEnemyKilled, (*Engine).processTriggers)
+	subscribe(world.EventPlayerKilled, (*Engine).handlePlayerKilled)
+	subscribe(world.EventItemTaken, (*Engine).collectStats)
+	subscribe(world.EventItemTaken, (*Engine).processTriggers)
+	subscribe(world.EventFixture, (*Engine).runFixtureEffects)
+	subscribe(world.EventFixture, (*Engine).processTriggers)
+	subscribe(world.EventLockJammed, (*Engine).processTriggers)
+	subscribe(world.EventRoomEntered, (*Engine).processTriggers)
+	subscribe(world.EventRoomEntered, (*Engine).processWinCondition)
+	subscribe(world.EventRoomEntered, (*Engine).recordSavePoint)
+}
+
+// publish hands an event to its subscribers. Nothing more comes of an event once the level has ended, and nothing more comes of an
+// action the player died during but their death. Once the action's context has ended, nothing more comes of anything.
+func (e *Engine) publish(event *world.Event) {
+	if e.diedThisTurn() && event.Event != world.EventPlayerKilled {
+		return
+	}
+	if e.interrupted() {
+		return
+	}
+	if err := e.checkEventChain(event); err != nil {
+		e.interruption = err
+		return
+	}
+	e.eventChain = append(e.eventChain, *event)
+	defer func() { e.eventChain = e.eventChain[:len(e.eventChain)-1] }()
+	subscribers := slices.Concat(eventSubscribers[""], eventSubscribers[event.Event])
+	for _, subscriber := range subscribers {
+		if e.LevelCompletionState != LevelCompletionStateInProgress || e.interrupted() {
+			return
+		}
+		subscriber(e, event)
+	}
+}
+
+// Publish hands an event to the engine as if the action in progress had caused it, for custom
+// effects that cause events of their own, such as an item appearing in the player's hands.
+// Events published outside an action's effects are ignored.
+func (e *Engine) Publish(event world.Event) {
+	if len(e.eventChain) == 0 {
+		return
+	}
+	e.publish(&event)
+}
+
+// maxEventChain is how deep events may be caused by the handling of other events
+const maxEventChain = 16
+
+// checkEventChain stops a trigger loop: an event caused while an event like it is still being
+// handled, or caused too deep in a chain of events. The chain is kept for Debug.
+func (e *Engine) checkEventChain(event *world.Event) error {
+	looped := slices.ContainsFunc(e.eventChain, func(handling world.Event) bool { return handling.Matches(event) })
+	if !looped && len(e.eventChain) < maxEventChain {
+		return nil
+	}
+	e.triggerLoop = append(slices.Clone(e.eventChain), *event)
+	steps := make([]string, len(e.triggerLoop))
+	for i := range e.triggerLoop {
+		steps[i] = describeEvent(&e.triggerLoop[i])
+	}
+	if looped {
+		return world.Errorf(ErrTriggerLoop, "the level's triggers loop: %s", strings.Join(steps, " -> "))
+	}
+	return world.Errorf(ErrTriggerLoop, "the level's triggers chain more than %d events: %s", maxEventChain, strings.Join(steps, " -> "))
+}
+
+// describeEvent names an event and what it concerns, such as "item_taken idol".
+func describeEvent(event *world.Event) string {
+	subject := event.RoomName + event.ItemName + event.FixtureName + event.EnemyName + event.LockName
+	if subject == "" {
+		return string(event.Event)
+	}
+	return string(event.Event) + " " + subject
+}
+
+// notify records a state change notification raised by the action in progress, to be handed
+// out with its engine state info. A notification already raised is not repeated.
+func (e *Engine) notify(stateChange *EngineStateChangeNotification) {
+	if stateChange != nil && !slices.Contains(e.notifications, *stateChange) {
+		e.notifications = append(e.notifications, *stateChange)
+	}
+}
+
+// collectStats keeps the statistics events feed into.
+func (e *Engine) collectStats(event *world.Event) {
+	switch event.Event {
+	case world.EventEnemyKilled:
+		e.Stats.EnemiesDefeated++
+	case world.EventItemTaken:
+		e.TakenItems[event.ItemName] = true
+	}
+}
+
+// recordSavePoint makes a save point room the player enters their respawn room, and a checkpoint
+// if they entered it outside combat.
+func (e *Engine) recordSavePoint(event *world.Event) {
+	if !e.CurrentRoom.SavePoint {
+		return
+	}
+	e.Player.SavePoint = e.CurrentRoom.Name
+	if e.Mode == Investigation {
+		e.recordCheckpoint()
+	}
+}
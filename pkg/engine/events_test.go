@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPublish_EveryNotification(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "notifications.json"))
+	engine.Rng = &FakeRng{Value: 0.1}
+	if _, err := engine.Take(ctx, "dagger"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+
+	traverse, err := engine.Traverse(ctx, "east")
+	if err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if notifications := traverse.EngineStateInfo.Notifications; len(notifications) != 2 ||
+		!slices.Contains(notifications, EngineStateChangeEnterCombat) || !slices.Contains(notifications, EngineStateChangeSecretFound) {
+		t.Errorf("Expected the ambush and the revealed grate to be reported, got %v", notifications)
+	}
+	if notification := traverse.EngineStateInfo.EngineStateChangeNotification; notification == nil || *notification != EngineStateChangeEnterCombat {
+		t.Errorf("Expected entering combat to be the most important notification, got %v", notification)
+	}
+
+	battle, err := engine.Battle(ctx, "dagger")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	expected := []EngineStateChangeNotification{EngineStateChangeExitCombat, EngineStateChangeLevelComplete}
+	if !slices.Equal(battle.EngineStateInfo.Notifications, expected) {
+		t.Errorf("Expected %v, got %v", expected, battle.EngineStateInfo.Notifications)
+	}
+	if notification := battle.EngineStateInfo.EngineStateChangeNotification; notification == nil || *notification != EngineStateChangeLevelComplete {
+		t.Errorf("Expected completing the level to be the most important notification, got %v", notification)
+	}
+}
+
+func TestPublish_NotificationsSurviveReads(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "notifications.json"))
+	if _, err := engine.Traverse(ctx, "east"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+
+	// Reading the engine state between actions doesn't use up the last action's notifications
+	for range 2 {
+		score, err := engine.Score()
+		if err != nil {
+			t.Fatalf("Score failed: %v", err)
+		}
+		if !slices.Contains(score.EngineStateInfo.Notifications, EngineStateChangeEnterCombat) {
+			t.Errorf("Expected the ambush to still be reported, got %v", score.EngineStateInfo.Notifications)
+		}
+	}
+
+	status, err := engine.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if notifications := status.EngineStateInfo.Notifications; len(notifications) != 0 {
+		t.Errorf("Expected the next action to start with no notifications, got %v", notifications)
+	}
+}
+
+// echoPlugin runs custom:echo effects by setting off the fixture named by the effect's target
+// again, which loops when the effect belongs to that fixture.
+type echoPlugin struct{}
+
+func (echoPlugin) Name() string { return "echo" }
+
+func (echoPlugin) HandleEffect(e *Engine, effect *world.Effect) (*EngineStateChangeNotification, bool) {
+	if effect.EffectType != "custom:echo" {
+		return nil, false
+	}
+	e.Publish(world.Event{Event: world.EventFixture, FixtureName: effect.TargetName})
+	return nil, true
+}
+
+func TestPublish_TriggerLoop(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "trigger_loop.json"))
+	engine.Plugins = []Plugin{echoPlugin{}}
+
+	if _, err := engine.Take(ctx, "rope"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	_, err := engine.Use(ctx, "rope", "bell")
+	if !errors.Is(err, ErrTriggerLoop) || ErrorCodeOf(err) != ErrorCodeTriggerLoop {
+		t.Fatalf("Expected ringing the bell to loop, got %v", err)
+	}
+	if _, err := engine.Player.GetItem("rope"); err != nil || engine.Stats.Turns != 1 {
+		t.Errorf("Expected the use to be rolled back, got %d turns and rope error %v", engine.Stats.Turns, err)
+	}
+
+	debug, err := engine.Debug()
+	if err != nil {
+		t.Fatalf("Debug failed: %v", err)
+	}
+	if want := []string{"fixture_used bell", "fixture_used bell"}; !slices.Equal(debug.TriggerLoop, want) {
+		t.Errorf("Expected the debug trace to show the loop %v, got %v", want, debug.TriggerLoop)
+	}
+}
@@ -0,0 +1,41 @@
+package engine
+
+// ExplorationInfo is how much of the level the players have explored, for completion meters.
+type ExplorationInfo struct {
+	RoomsExplored int
+	Rooms         int
+	Percent       int // of the level's rooms explored, rounded down
+	SecretsFound  int
+	Secrets       int // secret items and hidden doors, found or not
+}
+
+// explorationInfo counts the rooms explored and the secrets found, out of those in the level.
+// Secrets still to be found are the secret items no player has found and the doors still hidden.
+func (e *Engine) explorationInfo() *ExplorationInfo {
+	info := &ExplorationInfo{SecretsFound: e.Stats.SecretsFound}
+	for _, floor := range e.Level.Floors {
+		for _, room := range floor.Rooms {
+			info.Rooms++
+			if e.isExplored(room) {
+				info.RoomsExplored++
+			}
+		}
+	}
+	if info.Rooms > 0 {
+		info.Percent = info.RoomsExplored * 100 / info.Rooms
+	}
+
+	unfound := make(map[string]bool)
+	for _, item := range e.levelItems() {
+		if item.Secret && !e.FoundSecrets[item.Ref()] {
+			unfound[item.Ref()] = true
+		}
+	}
+	info.Secrets = info.SecretsFound + len(unfound)
+	for _, door := range e.Level.Doors {
+		if door.Hidden {
+			info.Secrets++
+		}
+	}
+	return info
+}
@@ -0,0 +1,26 @@
+package engine
+
+import "testing"
+
+func TestExplorationInfo(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "moveable.json"))
+	expected := ExplorationInfo{RoomsExplored: 1, Rooms: 2, Percent: 50, SecretsFound: 0, Secrets: 2}
+	if exploration := engine.getEngineStateInfo().Exploration; *exploration != expected {
+		t.Errorf("Expected the study explored and the letter and passage to find, got %+v", exploration)
+	}
+
+	if _, err := engine.Move(ctx, "bookcase", "push"); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if _, err := engine.Take(ctx, "letter"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	traverse, err := engine.Traverse(ctx, "north")
+	if err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	expected = ExplorationInfo{RoomsExplored: 2, Rooms: 2, Percent: 100, SecretsFound: 2, Secrets: 2}
+	if exploration := traverse.EngineStateInfo.Exploration; *exploration != expected {
+		t.Errorf("Expected the whole level explored, got %+v", exploration)
+	}
+}
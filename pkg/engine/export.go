@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"adventure-engine/pkg/loader"
+	"adventure-engine/pkg/world"
+	"encoding/json"
+	"slices"
+)
+
+// ExportLevel serializes the current game state as a level in loader format,
+// so that a play-tested state can be saved as a new starting point.
+//
+// The exported level starts the player in the current room. Items in the inventory are
+// placed in the current room, with the player's ammo loaded back into carried weapons or,
+// for weapons not carried, into ammo boxes. In multiplayer sessions the active player's room
+// is the start, and other players' inventories are placed in the rooms they are in. Defeated enemies and their triggers are removed,
+// as are completed objectives. Enemies that surrendered count as defeated and leave what they still carry in the room they gave up in. Conditional room descriptions only keep the conditions that don't hold yet.
+// Player health, an ongoing fight and run statistics are not exported.
+func (e *Engine) ExportLevel() (json.RawMessage, error) {
+	state := e.clone()
+	level := state.Level
+
+	// Start in the current room
+	level.Floors = moveToFront(level.Floors, state.CurrentFloor)
+	state.CurrentFloor.Rooms = moveToFront(state.CurrentFloor.Rooms, state.CurrentRoom)
+
+	// Put the inventory back into the current room, and other players' inventories into theirs
+	dropInventory(state.CurrentRoom, state.Player)
+	for _, player := range state.Players {
+		if player.ID != state.ActivePlayer {
+			dropInventory(player.CurrentRoom, player.Player)
+		}
+	}
+
+	// Remove defeated enemies, unless the win condition still refers to them
+	for _, enemy := range level.Enemies {
+		if enemy.State() == world.EnemySurrendered && enemy.Carries != nil {
+			_, room := level.FindRoom(enemy.Room)
+			room.Items = append(room.Items, enemy.Search())
+		}
+	}
+	level.Enemies = slices.DeleteFunc(level.Enemies, func(enemy *world.Enemy) bool {
+		return !enemy.IsHostile() && !isWinConditionEnemy(level, enemy.Name)
+	})
+	level.Triggers = slices.DeleteFunc(level.Triggers, func(trigger *world.Trigger) bool {
+		return trigger.EffectType == world.EffectEnterCombat && !slices.ContainsFunc(level.Enemies, func(enemy *world.Enemy) bool {
+			return enemy.Name == trigger.Effect.EnemyName
+		})
+	})
+	level.Reindex()
+
+	// Drop completed objectives and show revealed ones from the start
+	var objectives []*world.Objective
+	for _, objective := range level.Objectives {
+		switch state.objectiveStatus(objective) {
+		case ObjectiveComplete:
+			continue
+		case ObjectiveActive:
+			revealed := *objective
+			revealed.RevealOn = nil
+			objective = &revealed
+		}
+		objectives = append(objectives, objective)
+	}
+	level.Objectives = objectives
+
+	// Recipes the players discovered are known from the start
+	for _, comboItem := range level.ComboItems {
+		comboItem.Known = state.recipeKnown(comboItem)
+	}
+
+	for _, floor := range level.Floors {
+		for _, room := range floor.Rooms {
+			state.settleDescriptions(room)
+		}
+	}
+
+	return json.MarshalIndent(loader.ExportLevel(level), "", "  ")
+}
+
+// moveToFront returns the slice with the element moved to the front, keeping the others in order.
+func moveToFront[T comparable](s []T, element T) []T {
+	i := slices.Index(s, element)
+	if i <= 0 {
+		return s
+	}
+	return append([]T{element}, slices.Delete(slices.Clone(s), i, i+1)...)
+}
+
+func isWinConditionEnemy(level *world.Level, enemyName string) bool {
+	return level.WinCondition != nil && level.WinCondition.Event == world.EventEnemyKilled &&
+		level.WinCondition.EnemyName == enemyName
+}
+
+func sortedAmmoTypes(ammo map[string]int) []string {
+	var ammoTypes []string
+	for ammoType, quantity := range ammo {
+		if quantity > 0 {
+			ammoTypes = append(ammoTypes, ammoType)
+		}
+	}
+	slices.Sort(ammoTypes)
+	return ammoTypes
+}
+
+// dropInventory places a player's inventory in a room, loading their ammo back into the first
+// carried weapon that fires it or, for ammo no carried weapon fires, into ammo boxes.
+func dropInventory(room *world.Room, player *world.Player) {
+	for _, item := range player.Inventory {
+		if item.IsWeapon() && item.Weapon.UsesAmmo() {
+			item.Weapon.Ammo.Quantity += player.Ammo[item.AmmoType()]
+			delete(player.Ammo, item.AmmoType())
+		}
+		room.Items = append(room.Items, item)
+	}
+	for _, ammoType := range sortedAmmoTypes(player.Ammo) {
+		room.Items = append(room.Items, &world.Item{
+			BaseEntity: world.BaseEntity{
+				Name:        ammoType + " ammo",
+				Description: "a box of " + ammoType + " ammo",
+			},
+			Portable: &world.Portable{},
+			AmmoBox: &world.AmmoBox{
+				AmmoType: ammoType,
+				Ammo:     &world.Ammo{Quantity: player.Ammo[ammoType]},
+			},
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"adventure-engine/pkg/loader"
+	"adventure-engine/pkg/world"
+	"testing"
+)
+
+func TestExportLevel_RoundTrip(t *testing.T) {
+	level, err := loader.LoadGameFromFile("../../internal/testdata/demo.json")
+	if err != nil {
+		t.Fatalf("Failed to load demo game: %v", err)
+	}
+	engine := NewEngine(level)
+
+	if _, err := engine.Take(ctx, "energy drink"); err != nil {
+		t.Fatalf("Take energy drink failed: %v", err)
+	}
+	if _, err := engine.Uncover(ctx, "tattered grey hoodie"); err != nil {
+		t.Fatalf("Uncover hoodie failed: %v", err)
+	}
+	if _, err := engine.Traverse(ctx, "storage room door"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+
+	data, err := engine.ExportLevel()
+	if err != nil {
+		t.Fatalf("ExportLevel failed: %v", err)
+	}
+	exported, err := loader.LoadGame(data)
+	if err != nil {
+		t.Fatalf("Failed to load exported level: %v\n%s", err, data)
+	}
+
+	// The exported level starts in the room the player was in, with the inventory dropped there
+	restarted := NewEngine(exported)
+	if restarted.CurrentRoom.Name != "storage room" {
+		t.Errorf("Expected to start in storage room, got %s", restarted.CurrentRoom.Name)
+	}
+	if _, err := restarted.CurrentRoom.GetItem("energy drink"); err != nil {
+		t.Errorf("Expected energy drink in the starting room: %v", err)
+	}
+
+	// The uncovered note stays where it was revealed, and the hoodie no longer hides anything
+	waitingRoom := exported.GetRoom(exported.Floors[0].Name, "waiting room")
+	if _, err := waitingRoom.GetItem("ominous note"); err != nil {
+		t.Errorf("Expected uncovered note in waiting room: %v", err)
+	}
+	hoodie, err := waitingRoom.GetItem("tattered grey hoodie")
+	if err != nil {
+		t.Fatalf("Expected hoodie in waiting room: %v", err)
+	}
+	if hoodie.IsConcealer() {
+		t.Error("Expected uncovered hoodie to be exported as a plain item")
+	}
+	if _, err := waitingRoom.GetItem("energy drink"); err == nil {
+		t.Error("Expected taken energy drink to be gone from the waiting room")
+	}
+
+	// The live engine is unaffected by the export
+	if engine.CurrentRoom.Name != "storage room" || len(engine.Player.Inventory) != 1 {
+		t.Error("Expected export to leave the engine state unchanged")
+	}
+}
+
+func TestExportLevel_UnlockedDoorAndAmmo(t *testing.T) {
+	key := &world.Item{
+		BaseEntity: world.BaseEntity{Name: "key", Description: "a key"},
+		Portable:   &world.Portable{},
+		Key:        &world.Key{},
+	}
+	pistol := &world.Item{
+		BaseEntity: world.BaseEntity{Name: "pistol", Description: "a pistol"},
+		Portable:   &world.Portable{},
+		Weapon:     &world.Weapon{Damage: 0.5, Ammo: &world.Ammo{Quantity: 3}},
+	}
+	room1 := &world.Room{
+		BaseEntity:  world.BaseEntity{Name: "room1", Description: "first room"},
+		Connections: []*world.Connection{{DoorName: "door"}},
+		Items:       []*world.Item{key, pistol},
+	}
+	room2 := &world.Room{
+		BaseEntity:  world.BaseEntity{Name: "room2", Description: "second room"},
+		Connections: []*world.Connection{{DoorName: "door"}},
+	}
+	engine := NewEngine(&world.Level{
+		Name:   "test",
+		Floors: []*world.Floor{{Name: "floor", Rooms: []*world.Room{room1, room2}}},
+		Doors: []*world.Door{{
+			Name:  "door",
+			RoomA: "room1",
+			RoomB: "room2",
+			Lock:  &world.Lock{Locked: true, KeyName: "key"},
+		}},
+	})
+
+	if _, err := engine.Take(ctx, "key"); err != nil {
+		t.Fatalf("Take key failed: %v", err)
+	}
+	if _, err := engine.Take(ctx, "pistol"); err != nil {
+		t.Fatalf("Take pistol failed: %v", err)
+	}
+	if _, err := engine.Unlock(ctx, "key", "door"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+
+	data, err := engine.ExportLevel()
+	if err != nil {
+		t.Fatalf("ExportLevel failed: %v", err)
+	}
+	exported, err := loader.LoadGame(data)
+	if err != nil {
+		t.Fatalf("Failed to load exported level: %v\n%s", err, data)
+	}
+
+	if exported.GetDoor("door").IsLocked() {
+		t.Error("Expected unlocked door to be exported unlocked")
+	}
+	exportedPistol, err := exported.Floors[0].Rooms[0].GetItem("pistol")
+	if err != nil {
+		t.Fatalf("Expected pistol in starting room: %v", err)
+	}
+	if !exportedPistol.Weapon.UsesAmmo() || exportedPistol.Weapon.Ammo.Quantity != 3 {
+		t.Errorf("Expected pistol to be exported with 3 rounds, got %+v", exportedPistol.Weapon)
+	}
+}
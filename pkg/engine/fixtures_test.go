@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func loadStagedFixtureLevel(t *testing.T, revealMissing string) *Engine {
+	t.Helper()
+	engine := NewEngine(loadTestLevel(t, "staged_fixture.json"))
+	engineItem(t, engine, "generator").Fixture.RevealMissing = world.MissingItemsHint(revealMissing)
+	for _, name := range []string{"fuse", "fuel can", "crank"} {
+		if _, err := engine.Take(ctx, name); err != nil {
+			t.Fatalf("Take %s failed: %v", name, err)
+		}
+	}
+	return engine
+}
+
+func TestUse_FixtureStages(t *testing.T) {
+	engine := loadStagedFixtureLevel(t, "")
+
+	if _, err := engine.Use(ctx, "crank", "generator"); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("Expected the crank to be refused before the generator is primed, got %v", err)
+	}
+
+	use, err := engine.Use(ctx, "fuse", "generator")
+	if err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if use.Result.InsertNarrative != "The fuse clicks into place" || use.Result.StageNarrative != "" {
+		t.Errorf("Expected the fuse's insert narrative only, got %+v", use.Result)
+	}
+	if !slices.Equal(use.Result.MissingItems, []string{"fuel can"}) || use.Result.MissingCount != 2 {
+		t.Errorf("Expected the fuel can to be missing from the stage and 2 items in all, got %v and %d", use.Result.MissingItems, use.Result.MissingCount)
+	}
+
+	use, err = engine.Use(ctx, "fuel can", "generator")
+	if err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if use.Result.StageNarrative != "The generator is primed" || !slices.Equal(use.Result.MissingItems, []string{"crank"}) {
+		t.Errorf("Expected the first stage to be done with the crank missing, got %+v", use.Result)
+	}
+
+	use, err = engine.Use(ctx, "crank", "generator")
+	if err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if !use.Result.IsComplete || use.Result.CompletionNarrative != "The generator roars to life" || use.Result.MissingItems != nil || use.Result.MissingCount != 0 {
+		t.Errorf("Expected the generator to be complete, got %+v", use.Result)
+	}
+}
+
+func TestUse_FixtureRevealMissing(t *testing.T) {
+	tests := []struct {
+		revealMissing string
+		items         []string
+		count         int
+	}{
+		{"items", []string{"fuel can"}, 2},
+		{"count", nil, 2},
+		{"none", nil, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.revealMissing, func(t *testing.T) {
+			engine := loadStagedFixtureLevel(t, test.revealMissing)
+			use, err := engine.Use(ctx, "fuse", "generator")
+			if err != nil {
+				t.Fatalf("Use failed: %v", err)
+			}
+			if !slices.Equal(use.Result.MissingItems, test.items) || use.Result.MissingCount != test.count {
+				t.Errorf("Expected %v and %d missing, got %v and %d", test.items, test.count, use.Result.MissingItems, use.Result.MissingCount)
+			}
+		})
+	}
+}
+
+func loadFixtureEffectsLevel(t *testing.T, name string) *Engine {
+	t.Helper()
+	engine := NewEngine(loadTestLevel(t, name))
+	if _, err := engine.Take(ctx, "crank"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	return engine
+}
+
+func TestUse_FixtureEffects(t *testing.T) {
+	engine := loadFixtureEffectsLevel(t, "fixture_effects.json")
+
+	if _, err := engine.Traverse(ctx, "north"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the hidden panel to be out of reach, got %v", err)
+	}
+	if observe, _ := engine.Observe(ctx, ObserveFilter{}); len(observe.Result.Doors) != 0 {
+		t.Errorf("Expected the hidden panel not to be seen, got %+v", observe.Result.Doors)
+	}
+
+	use, err := engine.Use(ctx, "crank", "winch")
+	if err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if len(use.Result.Effects) != 3 || use.Result.Effects[0] != (EffectInfo{Effect: "reveal_door", Target: "panel"}) {
+		t.Errorf("Expected the winch's three effects, got %+v", use.Result.Effects)
+	}
+	safe, _ := engine.CurrentRoom.GetItem("safe")
+	if safe.Container.IsLocked() {
+		t.Error("Expected the safe to be unlocked")
+	}
+
+	traverse, err := engine.Traverse(ctx, "north")
+	if err != nil {
+		t.Fatalf("Expected the revealed panel to be passable, got %v", err)
+	}
+	if traverse.EngineStateInfo.EngineStateChangeNotification != nil || engine.Mode == Combat {
+		t.Errorf("Expected the guard to have been driven off, got %v", traverse.EngineStateInfo.EngineStateChangeNotification)
+	}
+}
+
+func TestUse_FixtureCompletesLevel(t *testing.T) {
+	engine := loadFixtureEffectsLevel(t, "fixture_complete_level.json")
+
+	use, err := engine.Use(ctx, "crank", "winch")
+	if err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if notification := use.EngineStateInfo.EngineStateChangeNotification; notification == nil || *notification != EngineStateChangeLevelComplete {
+		t.Errorf("Expected the level to be complete, got %v", notification)
+	}
+	if engine.LevelCompletionState != LevelCompletionStateComplete {
+		t.Errorf("Expected the level to be complete, got %v", engine.LevelCompletionState)
+	}
+}
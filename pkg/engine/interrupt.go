@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"context"
+	"errors"
+
+	"adventure-engine/pkg/world"
+)
+
+// act runs a game action under a context, so that an action stuck in a pathological level
+// cannot hold up its caller forever. The engine looks at the context between the handlers of
+// the events the action publishes. An action is abandoned once the context has ended, or once
+// the events it caused loop: the game state is rolled back to what it was before the action and
+// the action fails with ErrInterrupted or ErrTriggerLoop. Rolling back costs a snapshot of the
+// game state before every action, which is only taken under contexts that can end or when a
+// plugin handles effects. Otherwise only the level's own triggers can cut an action short,
+// which is rare enough that such an action fails with ErrTriggerLoop without being rolled back.
+func act[R any](e *Engine, ctx context.Context, name string, run func() (*R, error)) (*R, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, interruptedError(name, err)
+	}
+	// The notifications and sound cues of the last action are kept until now, rather than being
+	// handed out once, so that reading the engine state between actions leaves the engine alone.
+	e.notifications, e.soundCues = nil, nil
+	e.rememberVersion()
+	e.rememberTurn()
+	var before *Engine
+	if ctx.Done() != nil || e.handlesEffects() {
+		before = e.clone()
+	}
+	e.ctx = ctx
+	result, err := run()
+	e.ctx = nil
+	cause := e.interruption
+	if cause == nil {
+		return result, err
+	}
+	e.interruption = nil
+	if before != nil {
+		triggerLoop, debugLog := e.triggerLoop, e.debugLog
+		*e = *before
+		e.triggerLoop, e.debugLog = triggerLoop, debugLog
+	}
+	if errors.Is(cause, ErrTriggerLoop) {
+		return nil, cause
+	}
+	return nil, interruptedError(name, cause)
+}
+
+// interrupted reports whether the action in progress has been cut short, by its context ending
+// or a trigger loop. Once it has, the action is rolled back however it carries on.
+func (e *Engine) interrupted() bool {
+	if e.interruption == nil && e.ctx != nil {
+		e.interruption = e.ctx.Err()
+	}
+	return e.interruption != nil
+}
+
+func interruptedError(name string, cause error) error {
+	return world.Errorf(ErrInterrupted, "%s was interrupted: %v", name, cause)
+}
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"adventure-engine/pkg/world"
+)
+
+// cancelPlugin ends the context of the action it watches once the action has taken its turn,
+// as a timeout firing part way through would.
+type cancelPlugin struct {
+	cancel context.CancelFunc
+}
+
+func (p *cancelPlugin) Name() string { return "canceller" }
+
+func (p *cancelPlugin) BeforeAction(e *Engine, action Action) error { return nil }
+
+func (p *cancelPlugin) AfterAction(e *Engine, action Action) { p.cancel() }
+
+func TestInterrupt_EndedContext(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "interrupt.json"))
+	ended, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err := engine.Take(ended, "lamp")
+	if !errors.Is(err, ErrInterrupted) || ErrorCodeOf(err) != ErrorCodeInterrupted {
+		t.Fatalf("Expected taking with an ended context to be interrupted, got %v", err)
+	}
+	if _, err := engine.Player.GetItem("lamp"); engine.Stats.Turns != 0 || err == nil {
+		t.Errorf("Expected an interrupted action to leave no trace, got %d turns and the lamp taken", engine.Stats.Turns)
+	}
+}
+
+func TestInterrupt_RollsBack(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "interrupt.json"))
+	running, cancel := context.WithCancel(ctx)
+	engine.Plugins = []Plugin{&cancelPlugin{cancel: cancel}}
+	version := engine.StateVersion
+
+	_, err := engine.Traverse(running, "north")
+	if !errors.Is(err, ErrInterrupted) {
+		t.Fatalf("Expected the traverse to be interrupted, got %v", err)
+	}
+	if engine.CurrentRoom.Name != "hall" || engine.Stats.Turns != 0 || engine.StateVersion != version {
+		t.Errorf("Expected the traverse to be rolled back, got room %s, %d turns and state version %d", engine.CurrentRoom.Name, engine.Stats.Turns, engine.StateVersion)
+	}
+
+	engine.Plugins = nil
+	if _, err := engine.Traverse(running, "north"); !errors.Is(err, ErrInterrupted) {
+		t.Fatalf("Expected an ended context to keep interrupting, got %v", err)
+	}
+	if _, err := engine.Traverse(ctx, "north"); err != nil {
+		t.Fatalf("Expected the engine to carry on after an interruption, got %v", err)
+	}
+	if engine.CurrentRoom.Name != "study" {
+		t.Errorf("Expected to be in the study, got %s", engine.CurrentRoom.Name)
+	}
+}
+
+func TestInterrupt_TriggerLoopWithoutSnapshot(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "interrupt.json"))
+
+	// An action under a context that never ends, whose events loop as a level's own triggers can
+	loop := func() (*struct{}, error) {
+		event := world.Event{Event: world.EventRoomEntered, RoomName: "hall"}
+		engine.eventChain = []world.Event{event}
+		engine.publish(&event)
+		engine.eventChain = nil
+		return &struct{}{}, nil
+	}
+	if _, err := act(engine, ctx, "loop", loop); !errors.Is(err, ErrTriggerLoop) {
+		t.Fatalf("Expected the loop to fail the action, got %v", err)
+	}
+	if engine.interruption != nil {
+		t.Errorf("Expected the interruption to be cleared, got %v", engine.interruption)
+	}
+	if _, err := engine.Traverse(ctx, "north"); err != nil {
+		t.Fatalf("Expected the engine to carry on after the loop, got %v", err)
+	}
+	if engine.CurrentRoom.Name != "study" {
+		t.Errorf("Expected to be in the study, got %s", engine.CurrentRoom.Name)
+	}
+}
@@ -0,0 +1,22 @@
+package engine
+
+import (
+	"strings"
+
+	"adventure-engine/pkg/world"
+)
+
+// SetLanguage sets the language the level's text is given in, which must be one of the level's languages.
+// Text the level has no translation for is given in the level's own language.
+func (e *Engine) SetLanguage(language string) error {
+	if !e.Level.HasLanguage(language) {
+		return world.Errorf(ErrInvalidArgument, "the level is not available in %s, only in %s", language, strings.Join(e.Level.Languages(), ", "))
+	}
+	e.Language = language
+	return nil
+}
+
+// localize returns a text of the level in the engine's language.
+func (e *Engine) localize(text string) string {
+	return e.Level.Localize(text, e.Language)
+}
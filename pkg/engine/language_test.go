@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetLanguage(t *testing.T) {
+	level := loadTestLevel(t, "language.json")
+	engine := NewEngine(level)
+
+	if err := engine.SetLanguage("fr"); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Expected ErrInvalidArgument for a language the level lacks, got %v", err)
+	}
+	if err := engine.SetLanguage("de"); err != nil {
+		t.Fatalf("SetLanguage failed: %v", err)
+	}
+
+	observe, err := engine.Observe(ctx, ObserveFilter{})
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if observe.Result.RoomDescription != "ein Flur" {
+		t.Errorf("Expected the German room description, got %q", observe.Result.RoomDescription)
+	}
+	descriptions := map[string]string{}
+	for _, item := range observe.Result.VisibleItems {
+		descriptions[item.Name] = item.Description
+	}
+	if descriptions["letter"] != "ein Brief" || descriptions["note"] != "a note" {
+		t.Errorf("Expected German text, or English without a translation, got %v", descriptions)
+	}
+
+	inspect, err := engine.Inspect(ctx, "letter")
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if inspect.Result.ItemInspection.Detail != "Triff mich um Mitternacht" {
+		t.Errorf("Expected the German detail, got %q", inspect.Result.ItemInspection.Detail)
+	}
+
+	// The level itself keeps its own text
+	if level.Floors[0].Rooms[0].Description != "a hall" {
+		t.Errorf("Expected the level to be unchanged, got %q", level.Floors[0].Rooms[0].Description)
+	}
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"context"
+
+	"adventure-engine/pkg/world"
+)
+
+type LatchResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          latchResultInternal
+}
+
+// latchResultInternal is the result of latching a door.
+type latchResultInternal struct {
+	DoorName string
+	Latched  bool
+}
+
+// Latch latches a door of the current room by name, location or direction.
+// Returns a LatchResult and engine state info.
+func (e *Engine) Latch(ctx context.Context, door string) (*LatchResult, error) {
+	return act(e, ctx, "latch", func() (*LatchResult, error) {
+		action := Action{Name: "latch", Args: []string{door}}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		latchResult, err := e.latchInternal(door)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		return &LatchResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *latchResult,
+		}, nil
+	})
+}
+
+// latchInternal bolts a door from the current room, so it can only be opened from this side.
+// Going back through the door unlatches it again.
+func (e *Engine) latchInternal(doorName string) (*latchResultInternal, error) {
+	door, err := e.findDoor(doorName)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case door.Latch == nil:
+		return nil, world.Errorf(ErrInvalidTarget, "the %s has no latch", door.Name)
+	case door.IsLatched():
+		return nil, world.Errorf(ErrInvalidTarget, "the %s is already latched", door.Name)
+	case !door.CanLatch(e.CurrentRoom.Name):
+		return nil, world.Errorf(ErrInvalidTarget, "the %s can only be latched from the other side", door.Name)
+	}
+	door.LatchFrom(e.CurrentRoom.Name)
+	return &latchResultInternal{
+		DoorName: door.Name,
+		Latched:  true,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLatch(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "bolt.json"))
+
+	if _, err := engine.Traverse(ctx, "north"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	latch, err := engine.Latch(ctx, "oak door")
+	if err != nil {
+		t.Fatalf("Latch failed: %v", err)
+	}
+	if !latch.Result.Latched || !engine.Level.GetDoor("oak door").IsLatched() {
+		t.Errorf("Expected the oak door to be latched, got %+v", latch.Result)
+	}
+	if _, err := engine.Latch(ctx, "south"); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("Expected ErrInvalidTarget latching a latched door, got %v", err)
+	}
+
+	// The player can go back through, unlatching the door
+	traverse, err := engine.Traverse(ctx, "south")
+	if err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if !traverse.Result.Unlatched {
+		t.Error("Expected going back through the door to unlatch it")
+	}
+
+	// Two-way latches work from either side
+	if _, err := engine.Latch(ctx, "north"); err != nil {
+		t.Fatalf("Latch failed: %v", err)
+	}
+	if _, err := engine.Traverse(ctx, "north"); err != nil {
+		t.Fatalf("Expected the door latched from this side to open, got %v", err)
+	}
+	engine.Level.GetDoor("oak door").LatchFrom("hall")
+	if _, err := engine.Traverse(ctx, "south"); !errors.Is(err, ErrLatched) {
+		t.Errorf("Expected ErrLatched going through a door latched from the other side, got %v", err)
+	}
+}
+
+func TestLatch_Errors(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "bolt.json"))
+
+	if _, err := engine.Latch(ctx, "arch"); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("Expected ErrInvalidTarget for a door without a latch, got %v", err)
+	}
+	if _, err := engine.Latch(ctx, "down"); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("Expected ErrInvalidTarget for a door latched from the other side, got %v", err)
+	}
+
+	observe, err := engine.Observe(ctx, ObserveFilter{})
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	for _, door := range observe.Result.Doors {
+		if door.CanLatch != (door.Name == "oak door") {
+			t.Errorf("Expected only the oak door to be latchable, got %+v", door)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+)
+
+// roomPositions returns the grid position of each room on a floor.
+// Rooms positioned by the level keep their position. The others are laid out by following
+// compass directions from positioned rooms, starting with the floor's first room at (0, 0)
+// if the level positions none. Rooms that can't be reached by compass directions, or that
+// would overlap another room, are left out.
+func (e *Engine) roomPositions(floor *world.Floor) map[string]world.Position {
+	positions := make(map[string]world.Position)
+	occupied := make(map[world.Position]bool)
+	rooms := make(map[string]*world.Room)
+	var queue []*world.Room
+
+	place := func(room *world.Room, position world.Position) {
+		positions[room.Name] = position
+		occupied[position] = true
+		queue = append(queue, room)
+	}
+
+	hasDirections := false
+	for _, room := range floor.Rooms {
+		rooms[room.Name] = room
+		if room.Position != nil {
+			place(room, *room.Position)
+		}
+		for _, conn := range room.Connections {
+			hasDirections = hasDirections || conn.Direction != ""
+		}
+	}
+	if len(queue) == 0 {
+		if !hasDirections || len(floor.Rooms) == 0 {
+			return positions
+		}
+		place(floor.Rooms[0], world.Position{})
+	}
+
+	for len(queue) > 0 {
+		room := queue[0]
+		queue = queue[1:]
+		for _, conn := range room.Connections {
+			if conn.Direction == "" || conn.Direction == world.DirectionUp || conn.Direction == world.DirectionDown {
+				continue
+			}
+			door := e.Level.GetDoor(conn.DoorName)
+			neighbor, onFloor := rooms[door.RoomA]
+			if door.RoomA == room.Name {
+				neighbor, onFloor = rooms[door.RoomB]
+			}
+			if !onFloor {
+				continue
+			}
+			if _, placed := positions[neighbor.Name]; placed {
+				continue
+			}
+			position := positions[room.Name].Step(conn.Direction)
+			if occupied[position] {
+				continue
+			}
+			place(neighbor, position)
+		}
+	}
+	return positions
+}
@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"context"
+
+	"adventure-engine/pkg/world"
+)
+
+type ListenResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          listenResultInternal
+}
+
+type PeekResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          peekResultInternal
+}
+
+// listenResultInternal is what the player hears through a door.
+type listenResultInternal struct {
+	DoorName  string
+	Ambient   string // the ambient sound of the room behind the door
+	Movement  bool   // true if something is moving behind the door
+	EnemyName string // the enemy making the noise, only made out through barred doors
+}
+
+// peekResultInternal is what the player sees through a barred door.
+type peekResultInternal struct {
+	DoorName         string
+	RoomName         string
+	RoomDescription  string
+	EnemyName        string
+	EnemyDescription string
+}
+
+// Listen listens at a door of the current room by name, location or direction.
+// Returns a ListenResult and engine state info.
+func (e *Engine) Listen(ctx context.Context, door string) (*ListenResult, error) {
+	return act(e, ctx, "listen", func() (*ListenResult, error) {
+		action := Action{Name: "listen", Args: []string{door}}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		listenResult, err := e.listenInternal(door)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		return &ListenResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *listenResult,
+		}, nil
+	})
+}
+
+// Peek looks through a barred door of the current room by name, location or direction.
+// Returns a PeekResult and engine state info.
+func (e *Engine) Peek(ctx context.Context, door string) (*PeekResult, error) {
+	return act(e, ctx, "peek", func() (*PeekResult, error) {
+		action := Action{Name: "peek", Args: []string{door}}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		peekResult, err := e.peekInternal(door)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		return &PeekResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *peekResult,
+		}, nil
+	})
+}
+
+// listenInternal listens for what is behind a door without opening it. Doors can be listened at
+// whether or not they are locked. Solid doors only let through that something is moving.
+func (e *Engine) listenInternal(doorName string) (*listenResultInternal, error) {
+	door, err := e.findDoor(doorName)
+	if err != nil {
+		return nil, err
+	}
+	_, room, err := e.roomBehind(door)
+	if err != nil {
+		return nil, err
+	}
+	result := &listenResultInternal{
+		DoorName: door.Name,
+		Ambient:  room.Ambient,
+	}
+	if enemy := e.lurkingEnemy(room); enemy != nil {
+		result.Movement = true
+		if door.Barred {
+			result.EnemyName = enemy.Name
+		}
+	}
+	return result, nil
+}
+
+// peekInternal looks into the room behind a barred door without opening it.
+// The room's items are too far away to make out.
+func (e *Engine) peekInternal(doorName string) (*peekResultInternal, error) {
+	door, err := e.findDoor(doorName)
+	if err != nil {
+		return nil, err
+	}
+	if !door.Barred {
+		return nil, world.Errorf(ErrInvalidTarget, "you can't see through the %s", door.Name)
+	}
+	_, room, err := e.roomBehind(door)
+	if err != nil {
+		return nil, err
+	}
+	description, ok := e.conditionalDescription(room)
+	if !ok {
+		description = room.Description
+	}
+	result := &peekResultInternal{
+		DoorName:        door.Name,
+		RoomName:        room.Name,
+		RoomDescription: e.localize(description),
+	}
+	if enemy := e.lurkingEnemy(room); enemy != nil {
+		result.EnemyName = enemy.Name
+		result.EnemyDescription = e.localize(enemy.Description)
+	}
+	return result, nil
+}
+
+// lurkingEnemy returns a hostile enemy that attacks when the player enters a room, if any.
+func (e *Engine) lurkingEnemy(room *world.Room) *world.Enemy {
+	for _, trigger := range e.Level.Triggers {
+		if trigger.Event.Event != world.EventRoomEntered || trigger.Event.RoomName != room.Name || trigger.EffectType != world.EffectEnterCombat {
+			continue
+		}
+		if enemy := e.Level.GetEnemy(trigger.Effect.EnemyName); enemy != nil && enemy.IsHostile() {
+			return enemy
+		}
+	}
+	return nil
+}
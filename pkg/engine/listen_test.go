@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestListen(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "listen.json"))
+
+	barred, err := engine.Listen(ctx, "north")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	if !barred.Result.Movement || barred.Result.EnemyName != "guard" || barred.Result.Ambient != "snoring" {
+		t.Errorf("Expected to hear the guard snoring through the bars, got %+v", barred.Result)
+	}
+
+	solid, err := engine.Listen(ctx, "oak door")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	if !solid.Result.Movement || solid.Result.EnemyName != "" {
+		t.Errorf("Expected to hear only movement through the solid door, got %+v", solid.Result)
+	}
+
+	engine.Level.GetEnemy("hound").HP = 0
+	solid, err = engine.Listen(ctx, "east")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	if solid.Result.Movement {
+		t.Errorf("Expected silence behind the door once the hound is dead, got %+v", solid.Result)
+	}
+	if engine.Stats.Turns != 3 {
+		t.Errorf("Expected listening to take a turn, got %d turns", engine.Stats.Turns)
+	}
+}
+
+func TestPeek(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "listen.json"))
+
+	peek, err := engine.Peek(ctx, "iron bars")
+	if err != nil {
+		t.Fatalf("Peek failed: %v", err)
+	}
+	if peek.Result.RoomName != "guard room" || peek.Result.RoomDescription != "a guard room" || peek.Result.EnemyDescription != "a sleeping guard" {
+		t.Errorf("Expected to see the guard room and the guard, got %+v", peek.Result)
+	}
+
+	engine.Level.GetEnemy("guard").HP = 0
+	peek, err = engine.Peek(ctx, "north")
+	if err != nil {
+		t.Fatalf("Peek failed: %v", err)
+	}
+	if peek.Result.RoomDescription != "an empty guard room" || peek.Result.EnemyName != "" {
+		t.Errorf("Expected the guard room's changed description and no guard, got %+v", peek.Result)
+	}
+
+	if _, err := engine.Peek(ctx, "oak door"); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("Expected ErrInvalidTarget peeking through a solid door, got %v", err)
+	}
+	if _, err := engine.Peek(ctx, "window"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing door, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"context"
+	"strings"
+
+	"adventure-engine/pkg/world"
+)
+
+// maxMapNoteLength is the longest note, in characters, that can be written on a room or door.
+const maxMapNoteLength = 280
+
+// AnnotateRoom writes a note on a room on the minimap, such as "code 2468 goes to the safe
+// here", replacing any note already on it. An empty note rubs the old one out. Only rooms the
+// minimap shows, those visited and those behind their doors, can be written on.
+// Notes are shared by every player and don't take a turn.
+// Returns the minimap with the note on it.
+func (e *Engine) AnnotateRoom(ctx context.Context, roomName string, note string) (*MinimapResult, error) {
+	return e.annotate(ctx, "room", roomName, note)
+}
+
+// AnnotateDoor writes a note on a door on the minimap, like AnnotateRoom. Only doors the
+// minimap shows can be written on.
+// Returns the minimap with the note on it.
+func (e *Engine) AnnotateDoor(ctx context.Context, doorName string, note string) (*MinimapResult, error) {
+	return e.annotate(ctx, "door", doorName, note)
+}
+
+func (e *Engine) annotate(ctx context.Context, kind string, name string, note string) (*MinimapResult, error) {
+	return act(e, ctx, "minimap", func() (*MinimapResult, error) {
+		if !e.ValidationDisabled {
+			if err := e.validateEngineState(); err != nil {
+				return nil, err
+			}
+		}
+		note = strings.TrimSpace(note)
+		if len([]rune(note)) > maxMapNoteLength {
+			return nil, world.Errorf(ErrInvalidArgument, "notes can be at most %d characters long", maxMapNoteLength)
+		}
+		minimap, err := e.minimapInternal()
+		if err != nil {
+			return nil, err
+		}
+		if !minimap.shows(kind, name) {
+			return nil, world.Errorf(ErrNotFound, "there is no %s called %s on your map", kind, name)
+		}
+		if e.RoomNotes == nil || e.DoorNotes == nil {
+			e.RoomNotes, e.DoorNotes = make(map[string]string), make(map[string]string)
+		}
+		notes := e.RoomNotes
+		if kind == "door" {
+			notes = e.DoorNotes
+		}
+		if note == "" {
+			delete(notes, name)
+		} else {
+			notes[name] = note
+		}
+		e.StateVersion++
+
+		minimap, err = e.minimapInternal()
+		if err != nil {
+			return nil, err
+		}
+		return &MinimapResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *minimap,
+		}, nil
+	})
+}
+
+// shows returns true if the minimap shows a room or door, by kind and name.
+func (m *minimapResultInternal) shows(kind string, name string) bool {
+	for _, floor := range m.Floors {
+		if kind == "room" {
+			for _, room := range floor.Rooms {
+				if room.Name == name && (!room.Hidden || room.Adjacent) {
+					return true
+				}
+			}
+			continue
+		}
+		for _, door := range floor.Doors {
+			if door.Name == name && !door.Hidden {
+				return true
+			}
+		}
+	}
+	return false
+}
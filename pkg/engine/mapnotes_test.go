@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAnnotateMap(t *testing.T) {
+	engine := loadCampLevel(t)
+	version := engine.StateVersion
+	if _, err := engine.AnnotateDoor(ctx, "vault door", "code 2468, the bandit says"); err != nil {
+		t.Fatalf("AnnotateDoor failed: %v", err)
+	}
+	result, err := engine.AnnotateRoom(ctx, "vault", " loot? ")
+	if err != nil {
+		t.Fatalf("AnnotateRoom failed: %v", err)
+	}
+	if engine.StateVersion != version+2 || engine.Stats.Turns != 1 {
+		t.Errorf("Expected notes to change the state without taking turns, got version %d and %d turns", engine.StateVersion, engine.Stats.Turns)
+	}
+	floor := result.Result.Floors[0]
+	if floor.Doors[0].Note != "code 2468, the bandit says" || floor.Rooms[1].Note != "loot?" || floor.Rooms[0].Note != "" {
+		t.Errorf("Expected the notes on the vault and its door, got %+v", floor)
+	}
+
+	if _, err := engine.AnnotateRoom(ctx, "vault", ""); err != nil {
+		t.Fatalf("AnnotateRoom failed: %v", err)
+	}
+	if _, ok := engine.RoomNotes["vault"]; ok {
+		t.Error("Expected an empty note to rub out the vault's note")
+	}
+}
+
+func TestAnnotateMap_Errors(t *testing.T) {
+	engine := loadCampLevel(t)
+	if _, err := engine.AnnotateRoom(ctx, "crypt", "spooky"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a room that isn't on the map to be not found, got %v", err)
+	}
+	if _, err := engine.AnnotateDoor(ctx, "vault", "a room"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a room to be no door, got %v", err)
+	}
+	if _, err := engine.AnnotateRoom(ctx, "camp", strings.Repeat("x", maxMapNoteLength+1)); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Expected a note that is too long to fail, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"context"
+
+	"adventure-engine/pkg/world"
+)
+
+// MoveResult carries a secret discovered notification if moving the furniture revealed a door.
+type MoveResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          moveResultInternal
+}
+
+// moveResultInternal is the result of moving a piece of furniture.
+type moveResultInternal struct {
+	Name         string
+	Direction    string
+	Narrative    string
+	RevealedItem *ItemInfo // the item that was behind the furniture, if any
+	RevealedDoor string    // the hidden door that was behind the furniture, if any
+}
+
+// Move pushes, pulls or slides a piece of furniture in the current room. Furniture only
+// moves once, and what was behind it stays revealed for good.
+// Returns a MoveResult and engine state info.
+func (e *Engine) Move(ctx context.Context, itemName string, direction string) (*MoveResult, error) {
+	return act(e, ctx, "move", func() (*MoveResult, error) {
+		action := Action{Name: "move", Args: []string{itemName, direction}}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		moveResult, err := e.moveInternal(itemName, direction)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		return &MoveResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *moveResult,
+		}, nil
+	})
+}
+
+func (e *Engine) moveInternal(name string, direction string) (*moveResultInternal, error) {
+	name, err := e.resolveItemName(name)
+	if err != nil {
+		return nil, err
+	}
+	item, err := e.CurrentRoom.GetItem(name)
+	if err != nil {
+		return nil, err
+	}
+	if !item.IsMoveable() {
+		return nil, world.Errorf(ErrInvalidTarget, "the %s won't budge", name)
+	}
+	if item.Moveable.Moved {
+		return nil, world.Errorf(ErrAlreadyMoved, "the %s has already been moved", name)
+	}
+
+	revealedItem, err := item.Moveable.Move(direction)
+	if err != nil {
+		return nil, err
+	}
+	e.playSound(item.Name, item.SoundCues, world.SoundMove)
+
+	result := &moveResultInternal{
+		Name:      item.Name,
+		Direction: direction,
+		Narrative: e.localize(item.Moveable.Narrative),
+	}
+	e.learnCodes(item.Moveable.Narrative)
+
+	// Whatever was behind the furniture is now in the room, at the furniture's sub-location
+	if revealedItem != nil {
+		revealedItem.SubLocation = item.SubLocation
+		e.CurrentRoom.Items = append(e.CurrentRoom.Items, revealedItem)
+		e.recordSecretFound(revealedItem)
+		itemInfo := e.createItemInfo(revealedItem)
+		result.RevealedItem = &itemInfo
+	}
+	if stateChange := e.revealDoor(item.Moveable.RevealsDoor); stateChange != nil {
+		e.notify(stateChange)
+		result.RevealedDoor = item.Moveable.RevealsDoor
+	}
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMove_RevealsPassage(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "moveable.json"))
+
+	if _, err := engine.Traverse(ctx, "north"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the hidden passage to be out of reach, got %v", err)
+	}
+	if _, err := engine.Move(ctx, "bookcase", "pull"); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("Expected the bookcase not to move that way, got %v", err)
+	}
+
+	move, err := engine.Move(ctx, "bookcase", "push")
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if move.Result.RevealedItem == nil || move.Result.RevealedItem.Name != "letter" || move.Result.RevealedDoor != "passage" || move.Result.Narrative != "It grinds across the floor" {
+		t.Errorf("Expected the letter and the passage to be revealed, got %+v", move.Result)
+	}
+	if engine.Stats.SecretsFound != 2 {
+		t.Errorf("Expected the letter and the passage to count as secrets, got %d secrets", engine.Stats.SecretsFound)
+	}
+	if notification := move.EngineStateInfo.EngineStateChangeNotification; notification == nil || *notification != EngineStateChangeSecretFound {
+		t.Errorf("Expected a secret discovered notification, got %v", notification)
+	}
+	if _, err := engine.Move(ctx, "bookcase", ""); !errors.Is(err, ErrAlreadyMoved) {
+		t.Errorf("Expected the bookcase to move only once, got %v", err)
+	}
+
+	observe, err := engine.Observe(ctx, ObserveFilter{})
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if len(observe.Result.Doors) != 1 || observe.Result.Doors[0].Name != "passage" || len(observe.Result.VisibleItems) != 2 {
+		t.Errorf("Expected the passage and the letter to be seen, got %+v", observe.Result)
+	}
+	if _, err := engine.Traverse(ctx, "north"); err != nil {
+		t.Errorf("Expected the passage to be passable, got %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"adventure-engine/pkg/world"
+)
+
+// AmbiguousNameError is returned when a name the player used matches more than one
+// item or door they can refer to.
+type AmbiguousNameError struct {
+	Name       string
+	Candidates []string
+}
+
+func (err *AmbiguousNameError) Unwrap() error { return ErrAmbiguousName }
+
+func (err *AmbiguousNameError) Error() string {
+	return fmt.Sprintf("%s is ambiguous, did you mean the %s?", err.Name, strings.Join(err.Candidates, " or the "))
+}
+
+// namedEntity is an item or door the player can refer to by its name or an alias.
+type namedEntity struct {
+	name    string
+	aliases []string
+}
+
+// ReferableNames returns the names of everything the player can currently refer to:
+// items in the current room and its searched containers, inventory items, the room's doors
+// with their locations and directions, aliases of those items and doors, the room's
+// sub-locations, the travel nodes
+// reachable from the room, the enemy being fought and the enemies that surrendered in the
+// room. Does not reveal anything the player has not seen.
+func (e *Engine) ReferableNames() []string {
+	var names []string
+	for _, entity := range slices.Concat(e.itemEntities(), e.doorEntities(), e.enemyEntities()) {
+		names = append(names, entity.name)
+		names = append(names, entity.aliases...)
+	}
+	for _, subLocation := range e.CurrentRoom.SubLocations {
+		names = append(names, subLocation.Name)
+	}
+	for _, conn := range e.visibleConnections(e.CurrentRoom) {
+		if conn.Location != "" {
+			names = append(names, conn.Location)
+		}
+		if conn.Direction != "" && string(conn.Direction) != conn.Location {
+			names = append(names, string(conn.Direction))
+		}
+	}
+	if from := e.CurrentRoom.TravelNode; from != nil {
+		for _, node := range e.discoveredTravelNodes() {
+			if node.Network == from.Network {
+				names = append(names, node.Name)
+			}
+		}
+	}
+	if e.FightingEnemy != nil {
+		names = append(names, e.FightingEnemy.Name)
+	}
+	return names
+}
+
+// itemEntities returns the items the player can refer to: inventory items, items in the
+// current room and the contents of its searched containers, however deeply they are nested.
+func (e *Engine) itemEntities() []namedEntity {
+	var entities []namedEntity
+	for _, item := range e.Player.Inventory {
+		entities = append(entities, itemEntity(item))
+	}
+	for _, item := range e.CurrentRoom.Items {
+		entities = append(entities, roomItemEntities(item)...)
+	}
+	return entities
+}
+
+// roomItemEntities returns an item in a room and the contents of its searched containers,
+// however deeply they are nested.
+func roomItemEntities(item *world.Item) []namedEntity {
+	entities := []namedEntity{itemEntity(item)}
+	for container := item; container.IsContainer() && container.Container.Searched && !container.Container.IsEmpty(); container = container.Container.Contains {
+		contained := container.Container.Contains
+		entities = append(entities, itemEntity(contained))
+	}
+	return entities
+}
+
+// itemEntity returns an item as the player can refer to it. An item that shares its name with
+// others goes by its ID, so that naming it tells it apart, and its name becomes an alias, so
+// that naming it alone is ambiguous wherever the others are in reach too.
+func itemEntity(item *world.Item) namedEntity {
+	if item.Ref() == item.Name {
+		return namedEntity{name: item.Name, aliases: item.Aliases}
+	}
+	return namedEntity{name: item.Ref(), aliases: append([]string{item.Name}, item.Aliases...)}
+}
+
+// doorEntities returns the doors of the current room.
+func (e *Engine) doorEntities() []namedEntity {
+	var entities []namedEntity
+	for _, conn := range e.visibleConnections(e.CurrentRoom) {
+		door := e.Level.GetDoor(conn.DoorName)
+		entities = append(entities, namedEntity{name: door.Name, aliases: door.Aliases})
+	}
+	return entities
+}
+
+// resolveItemName resolves a name that refers to an item. A name can say which sub-location
+// of the current room the item is at, as in "key on the desk", to tell items apart.
+func (e *Engine) resolveItemName(name string) (string, error) {
+	if resolved, ok, err := e.resolveAtSubLocation(name); ok {
+		return resolved, err
+	}
+	return resolveName(name, e.itemEntities())
+}
+
+func (e *Engine) resolveDoorName(name string) (string, error) {
+	return resolveName(name, e.doorEntities())
+}
+
+// resolveItemOrDoorName resolves a name that may refer to either an item or a door.
+func (e *Engine) resolveItemOrDoorName(name string) (string, error) {
+	if resolved, ok, err := e.resolveAtSubLocation(name); ok {
+		return resolved, err
+	}
+	return resolveName(name, append(e.itemEntities(), e.doorEntities()...))
+}
+
+// resolveAtSubLocation resolves a name that ends with a sub-location of the current room to
+// one of the items there. Returns false if the name names no sub-location, or no item there
+// matches it, so that it can be resolved as a whole.
+func (e *Engine) resolveAtSubLocation(name string) (string, bool, error) {
+	itemName, subLocation, ok := e.splitSubLocation(name)
+	if !ok {
+		return "", false, nil
+	}
+	entities := e.subLocationEntities(subLocation)
+	resolved, err := resolveName(itemName, entities)
+	if err != nil {
+		return "", true, err
+	}
+	found := slices.ContainsFunc(entities, func(entity namedEntity) bool { return entity.name == resolved })
+	return resolved, found, nil
+}
+
+// resolveName resolves a name the player used to the name of one of the entities.
+//
+// Names are compared ignoring case and leading articles, and aliases count as names.
+// If nothing matches, each word the player used may be the start of a word of the name,
+// so "grey hood" matches "tattered grey hoodie", and failing that small typos are allowed.
+// Names that match nothing are returned unchanged, so codes, locations and directions pass
+// through and lookups report their usual errors.
+func resolveName(name string, entities []namedEntity) (string, error) {
+	query := normalizeName(name)
+	if query == "" {
+		return name, nil
+	}
+
+	for _, matches := range []func(term string) bool{
+		func(term string) bool { return term == query },
+		func(term string) bool { return len(query) >= 3 && matchesWordPrefixes(query, term) },
+		func(term string) bool { return len(query) >= 4 && editDistance(query, term) <= maxTypos(query) },
+	} {
+		var found []string
+		for _, entity := range entities {
+			if slices.Contains(found, entity.name) {
+				continue
+			}
+			for _, term := range append([]string{entity.name}, entity.aliases...) {
+				if matches(normalizeName(term)) {
+					found = append(found, entity.name)
+					break
+				}
+			}
+		}
+		switch {
+		case len(found) == 1:
+			return found[0], nil
+		case len(found) > 1:
+			slices.Sort(found)
+			return "", &AmbiguousNameError{Name: name, Candidates: found}
+		}
+	}
+	return name, nil
+}
+
+// isAmbiguous reports whether an error is an AmbiguousNameError.
+func isAmbiguous(err error) bool {
+	return errors.Is(err, ErrAmbiguousName)
+}
+
+// normalizeName lower-cases a name, collapses whitespace and drops a leading article.
+func normalizeName(name string) string {
+	words := strings.Fields(strings.ToLower(name))
+	if len(words) > 1 && (words[0] == "the" || words[0] == "a" || words[0] == "an") {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// matchesWordPrefixes reports whether each word of the query starts a word of the term,
+// in order.
+func matchesWordPrefixes(query string, term string) bool {
+	termWords := strings.Fields(term)
+	i := 0
+	for _, word := range strings.Fields(query) {
+		for i < len(termWords) && !strings.HasPrefix(termWords[i], word) {
+			i++
+		}
+		if i == len(termWords) {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// maxTypos returns the edit distance allowed between a query and a name.
+func maxTypos(query string) int {
+	if len(query) >= 8 {
+		return 2
+	}
+	return 1
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNames_Matching(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"tattered grey hoodie", "tattered grey hoodie"},
+		{"Tattered Grey Hoodie", "tattered grey hoodie"},
+		{"the tattered grey hoodie", "tattered grey hoodie"},
+		{"sweatshirt", "tattered grey hoodie"},
+		{"hoodie", "tattered grey hoodie"},
+		{"grey hood", "tattered grey hoodie"},
+		{"tatered grey hoodie", "tattered grey hoodie"},
+		{"brass", "brass key"},
+		{"front door", "oak door"},
+		{"lamp", "lamp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine(loadTestLevel(t, "aliases.json"))
+			resolved, err := engine.resolveItemOrDoorName(tt.name)
+			if err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+			if resolved != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, resolved)
+			}
+		})
+	}
+}
+
+func TestNames_Ambiguous(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "aliases.json"))
+	_, err := engine.Take(ctx, "key")
+	var ambiguous *AmbiguousNameError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Expected an ambiguous name error, got %v", err)
+	}
+	if !slices.Equal(ambiguous.Candidates, []string{"brass key", "iron key"}) {
+		t.Errorf("Expected brass key and iron key as candidates, got %v", ambiguous.Candidates)
+	}
+}
+
+func TestNames_Actions(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "aliases.json"))
+
+	take, err := engine.Take(ctx, "sweatshirt")
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if take.Result.ItemInfo.Name != "tattered grey hoodie" {
+		t.Errorf("Expected to take the hoodie, got %s", take.Result.ItemInfo.Name)
+	}
+	if _, err := engine.Inspect(ctx, "Hoodie"); err != nil {
+		t.Errorf("Expected to inspect the hoodie in the inventory: %v", err)
+	}
+	if _, err := engine.Take(ctx, "lamp"); err == nil || err.Error() != "you don't see a lamp here" {
+		t.Errorf("Expected unknown names to be reported as written, got %v", err)
+	}
+
+	traverse, err := engine.Traverse(ctx, "the front door")
+	if err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if traverse.Result.EnteredRoom.RoomName != "porch" {
+		t.Errorf("Expected to enter the porch, got %s", traverse.Result.EnteredRoom.RoomName)
+	}
+}
+
+func TestNames_SharedNames(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "shared_names.json"))
+
+	_, err := engine.Search(ctx, "crate")
+	var ambiguous *AmbiguousNameError
+	if !errors.As(err, &ambiguous) || !slices.Equal(ambiguous.Candidates, []string{"crate#1", "crate#2"}) {
+		t.Fatalf("Expected the crates to be ambiguous by ID, got %v", err)
+	}
+	search, err := engine.Search(ctx, "crate#2")
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if search.Result.ContainerName != "crate" || search.Result.ContainedItemInfo.Name != "flare" {
+		t.Errorf("Expected the flare in the second crate, got %+v", search.Result)
+	}
+
+	take, err := engine.Take(ctx, "the bandage#2")
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if take.Result.ItemInfo.ID != "bandage#2" {
+		t.Errorf("Expected to take the second bandage, got %+v", take.Result.ItemInfo)
+	}
+	if remaining, err := engine.CurrentRoom.GetItem("bandage"); err != nil || remaining.ID != "bandage#1" {
+		t.Errorf("Expected the first bandage to be left in the hold, got %+v", remaining)
+	}
+	if _, err := engine.Take(ctx, "flare"); err != nil {
+		t.Errorf("Expected an item with a name of its own to be taken by name: %v", err)
+	}
+}
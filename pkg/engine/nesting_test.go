@@ -0,0 +1,43 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNestedContainers(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "nesting.json"))
+
+	// Nothing under the tarp can be reached before it is lifted
+	if _, err := engine.Search(ctx, "safe"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for the hidden safe, got %v", err)
+	}
+	if _, err := engine.Uncover(ctx, "tarp"); err != nil {
+		t.Fatalf("Uncover failed: %v", err)
+	}
+	if _, err := engine.Unlock(ctx, "1234", "safe"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+
+	// The box can only be searched once the safe has been
+	if _, err := engine.Search(ctx, "box"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for the box in the unsearched safe, got %v", err)
+	}
+	if _, err := engine.Search(ctx, "safe"); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if _, err := engine.Search(ctx, "box"); err != nil {
+		t.Fatalf("Search failed for the box in the safe: %v", err)
+	}
+
+	// Taking the rag from the box lifts it, and the key under it can be taken
+	if _, err := engine.Take(ctx, "rag"); err != nil {
+		t.Fatalf("Take failed for the rag in the box: %v", err)
+	}
+	if _, err := engine.Take(ctx, "silver key"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if _, err := engine.Player.GetItem("silver key"); err != nil {
+		t.Errorf("Expected the silver key in the inventory: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+)
+
+// ObjectiveStatus is the player's progress on an authored objective.
+type ObjectiveStatus string
+
+const (
+	ObjectiveHidden   ObjectiveStatus = "hidden"
+	ObjectiveActive   ObjectiveStatus = "active"
+	ObjectiveComplete ObjectiveStatus = "complete"
+)
+
+// ObjectiveInfo contains an objective shown to the player and their progress on it.
+type ObjectiveInfo struct {
+	Name        string
+	Description string
+	Status      ObjectiveStatus
+}
+
+type ObjectivesResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          []ObjectiveInfo
+}
+
+// Objectives returns the objectives revealed so far, in the order the level lists them.
+// Allowed in all modes and after the level has ended.
+func (e *Engine) Objectives() (*ObjectivesResult, error) {
+	return &ObjectivesResult{
+		EngineStateInfo: *e.getEngineStateInfo(),
+		Result:          e.visibleObjectives(),
+	}, nil
+}
+
+// objectiveStatus returns the status of an objective.
+// Only changes are recorded, objectives start active unless they have a reveal event.
+func (e *Engine) objectiveStatus(objective *world.Objective) ObjectiveStatus {
+	if status, ok := e.ObjectiveStatuses[objective.Name]; ok {
+		return status
+	}
+	if objective.RevealOn != nil {
+		return ObjectiveHidden
+	}
+	return ObjectiveActive
+}
+
+// processObjectives reveals and completes objectives matching an event.
+// An objective completed before it was revealed is shown as complete.
+func (e *Engine) processObjectives(event *world.Event) {
+	for _, objective := range e.Level.Objectives {
+		status := e.objectiveStatus(objective)
+		if status == ObjectiveComplete {
+			continue
+		}
+		if e.ObjectiveStatuses == nil {
+			e.ObjectiveStatuses = make(map[string]ObjectiveStatus)
+		}
+		if objective.CompleteOn.Matches(event) {
+			e.ObjectiveStatuses[objective.Name] = ObjectiveComplete
+		} else if status == ObjectiveHidden && objective.RevealOn.Matches(event) {
+			e.ObjectiveStatuses[objective.Name] = ObjectiveActive
+		}
+	}
+}
+
+// visibleObjectives returns the revealed objectives with their status.
+func (e *Engine) visibleObjectives() []ObjectiveInfo {
+	var objectives []ObjectiveInfo
+	for _, objective := range e.Level.Objectives {
+		status := e.objectiveStatus(objective)
+		if status == ObjectiveHidden {
+			continue
+		}
+		objectives = append(objectives, ObjectiveInfo{
+			Name:        objective.Name,
+			Description: e.localize(objective.Description),
+			Status:      status,
+		})
+	}
+	return objectives
+}
@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"adventure-engine/pkg/loader"
+	"testing"
+)
+
+func objectiveStatuses(objectives []ObjectiveInfo) map[string]ObjectiveStatus {
+	statuses := make(map[string]ObjectiveStatus)
+	for _, objective := range objectives {
+		statuses[objective.Name] = objective.Status
+	}
+	return statuses
+}
+
+func TestObjectives_RevealAndComplete(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "fixture_objectives.json"))
+
+	result, err := engine.Objectives()
+	if err != nil {
+		t.Fatalf("Objectives failed: %v", err)
+	}
+	statuses := objectiveStatuses(result.Result)
+	if len(statuses) != 1 || statuses["escape"] != ObjectiveActive {
+		t.Errorf("Expected only escape to be active at the start, got %+v", result.Result)
+	}
+
+	if _, err := engine.Take(ctx, "fish hook"); err != nil {
+		t.Fatalf("Take fish hook failed: %v", err)
+	}
+	if _, err := engine.Take(ctx, "dental floss"); err != nil {
+		t.Fatalf("Take dental floss failed: %v", err)
+	}
+	statuses = objectiveStatuses(engine.getEngineStateInfo().Objectives)
+	if statuses["drain"] != ObjectiveActive {
+		t.Errorf("Expected drain to be revealed by taking the fish hook, got %+v", statuses)
+	}
+
+	if _, err := engine.Combine(ctx, "fish hook", "dental floss"); err != nil {
+		t.Fatalf("Combine failed: %v", err)
+	}
+	if _, err := engine.Use(ctx, "retrieval tool", "bathtub drain"); err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	result, err = engine.Objectives()
+	if err != nil {
+		t.Fatalf("Objectives failed: %v", err)
+	}
+	statuses = objectiveStatuses(result.Result)
+	if statuses["drain"] != ObjectiveComplete || statuses["escape"] != ObjectiveActive {
+		t.Errorf("Expected drain complete and escape active, got %+v", result.Result)
+	}
+	if result.Result[0].Name != "escape" {
+		t.Errorf("Expected objectives in level order, got %+v", result.Result)
+	}
+
+	context, err := engine.Context(ctx, ContextBrief)
+	if err != nil {
+		t.Fatalf("Context failed: %v", err)
+	}
+	if len(context.Result.Objectives) != 1 || context.Result.Objectives[0].Description != "get out onto the balcony" {
+		t.Errorf("Expected context to list the active authored objective, got %+v", context.Result.Objectives)
+	}
+}
+
+func TestObjectives_CompletedBeforeRevealed(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "fixture_objectives.json"))
+	engine.processObjectives(&engine.Level.Objectives[1].CompleteOn)
+
+	statuses := objectiveStatuses(engine.visibleObjectives())
+	if statuses["drain"] != ObjectiveComplete {
+		t.Errorf("Expected drain to show as complete, got %+v", statuses)
+	}
+
+	// Revealing a completed objective does not make it active again
+	engine.processObjectives(engine.Level.Objectives[1].RevealOn)
+	if status := engine.objectiveStatus(engine.Level.Objectives[1]); status != ObjectiveComplete {
+		t.Errorf("Expected drain to stay complete, got %s", status)
+	}
+}
+
+func TestObjectives_Export(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "fixture_objectives.json"))
+	if _, err := engine.Take(ctx, "fish hook"); err != nil {
+		t.Fatalf("Take fish hook failed: %v", err)
+	}
+
+	data, err := engine.ExportLevel()
+	if err != nil {
+		t.Fatalf("ExportLevel failed: %v", err)
+	}
+	level, err := loader.LoadGame(data)
+	if err != nil {
+		t.Fatalf("Failed to load exported level: %v", err)
+	}
+	if len(level.Objectives) != 2 {
+		t.Fatalf("Expected 2 objectives, got %d", len(level.Objectives))
+	}
+	if level.Objectives[1].RevealOn != nil {
+		t.Error("Expected revealed objective to be shown from the start")
+	}
+}
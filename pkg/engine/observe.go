@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"strconv"
+	"strings"
+
+	"adventure-engine/pkg/world"
+)
+
+// ObserveFilter narrows down the items an observation lists, for rooms too full to take in at
+// once. The zero filter lists every item. Doors are always listed in full.
+type ObserveFilter struct {
+	Portable   bool   // only items the player can take
+	Containers bool   // only containers
+	NamePrefix string // only items whose name starts with it, ignoring case
+	Limit      int    // items per page, 0 for all of them
+	Cursor     string // where the page starts, from the NextCursor of the page before; empty for the first
+}
+
+// matches returns true if the filter lets an item through.
+func (f ObserveFilter) matches(item *world.Item) bool {
+	if f.Portable && !item.IsPortable() {
+		return false
+	}
+	if f.Containers && !item.IsContainer() {
+		return false
+	}
+	return strings.HasPrefix(strings.ToLower(item.Name), strings.ToLower(f.NamePrefix))
+}
+
+// page returns the items the filter lets through on its page, along with the cursor of the
+// next page, or an empty cursor on the last page.
+// Cursors count the matching items before the page, so a page may skip or repeat an item if
+// the room changed since the page before.
+func (f ObserveFilter) page(items []*world.Item) ([]*world.Item, string, error) {
+	if f.Limit < 0 {
+		return nil, "", world.Errorf(ErrInvalidArgument, "invalid limit %d", f.Limit)
+	}
+	start := 0
+	if f.Cursor != "" {
+		parsed, err := strconv.Atoi(f.Cursor)
+		if err != nil || parsed < 0 {
+			return nil, "", world.Errorf(ErrInvalidArgument, "invalid cursor %q", f.Cursor)
+		}
+		start = parsed
+	}
+
+	var matching []*world.Item
+	for _, item := range items {
+		if f.matches(item) {
+			matching = append(matching, item)
+		}
+	}
+	if start >= len(matching) {
+		return nil, "", nil
+	}
+	matching = matching[start:]
+	if f.Limit == 0 || len(matching) <= f.Limit {
+		return matching, "", nil
+	}
+	return matching[:f.Limit], strconv.Itoa(start + f.Limit), nil
+}
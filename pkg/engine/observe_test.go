@@ -0,0 +1,74 @@
+package engine
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func observedItems(t *testing.T, engine *Engine, filter ObserveFilter) ([]string, string) {
+	t.Helper()
+	observation, err := engine.Observe(ctx, filter)
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	var names []string
+	for _, item := range observation.Result.VisibleItems {
+		names = append(names, item.Name)
+	}
+	if len(observation.Result.Doors) != 1 {
+		t.Errorf("Expected doors to be listed whatever the filter, got %d", len(observation.Result.Doors))
+	}
+	return names, observation.Result.NextCursor
+}
+
+func TestObserve_Filters(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "storeroom.json"))
+
+	tests := []struct {
+		filter   ObserveFilter
+		expected []string
+	}{
+		{ObserveFilter{}, []string{"Crate", "crowbar", "shelf", "crank", "locker", "crate lid"}},
+		{ObserveFilter{Portable: true}, []string{"crowbar", "crank", "crate lid"}},
+		{ObserveFilter{Containers: true}, []string{"Crate", "locker"}},
+		{ObserveFilter{Portable: true, Containers: true}, nil},
+		{ObserveFilter{NamePrefix: "cra"}, []string{"Crate", "crank", "crate lid"}},
+		{ObserveFilter{NamePrefix: "CRATE", Portable: true}, []string{"crate lid"}},
+		{ObserveFilter{NamePrefix: "barrel"}, nil},
+	}
+	for _, test := range tests {
+		names, cursor := observedItems(t, engine, test.filter)
+		if !slices.Equal(names, test.expected) || cursor != "" {
+			t.Errorf("Expected %+v to list %v on one page, got %v and cursor %q", test.filter, test.expected, names, cursor)
+		}
+	}
+}
+
+func TestObserve_Pages(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "storeroom.json"))
+
+	filter := ObserveFilter{NamePrefix: "c", Limit: 2}
+	var pages [][]string
+	for {
+		names, cursor := observedItems(t, engine, filter)
+		pages = append(pages, names)
+		if cursor == "" {
+			break
+		}
+		filter.Cursor = cursor
+	}
+	expected := [][]string{{"Crate", "crowbar"}, {"crank", "crate lid"}}
+	if !slices.EqualFunc(pages, expected, slices.Equal) {
+		t.Errorf("Expected pages %v, got %v", expected, pages)
+	}
+
+	if names, cursor := observedItems(t, engine, ObserveFilter{Limit: 2, Cursor: "10"}); names != nil || cursor != "" {
+		t.Errorf("Expected nothing past the last page, got %v and cursor %q", names, cursor)
+	}
+	for _, filter := range []ObserveFilter{{Limit: -1}, {Cursor: "page two"}, {Cursor: "-2"}} {
+		if _, err := engine.Observe(ctx, filter); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("Expected %+v to be rejected, got %v", filter, err)
+		}
+	}
+}
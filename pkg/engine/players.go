@@ -0,0 +1,209 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"slices"
+)
+
+// HostPlayerID is the ID of the player a session starts with.
+const HostPlayerID = "host"
+
+// TurnPolicy decides the order players of a multiplayer session may act in.
+type TurnPolicy string
+
+const (
+	// TurnsFree lets players act in any order.
+	TurnsFree TurnPolicy = "free"
+	// TurnsRoundRobin makes players take turns in the order they joined.
+	// Only actions that take a turn are restricted; looking around is always allowed.
+	TurnsRoundRobin TurnPolicy = "round_robin"
+)
+
+// PlayerState is the state of one player in a multiplayer session.
+//
+// Players share the level, so items taken, doors unlocked, triggers, objectives and the
+// win condition are common to all of them. Each has their own inventory, health and position,
+// and fights on their own. A player dying fails the level for everyone, unless the session
+// respawns players.
+type PlayerState struct {
+	ID            string
+	Player        *world.Player
+	CurrentFloor  *world.Floor
+	CurrentRoom   *world.Room
+	FightingEnemy *world.Enemy
+	Mode          Mode
+}
+
+// PlayerInfo describes a player of a multiplayer session.
+type PlayerInfo struct {
+	ID            string
+	CurrentFloor  string
+	CurrentRoom   string
+	Health        world.HealthState
+	Mode          Mode
+	InventorySize int
+	HasTurn       bool // false for everyone under free turns
+}
+
+type PlayersResult struct {
+	EngineStateInfo EngineStateInfo
+	Players         []PlayerInfo
+}
+
+// AddPlayer adds a player to the session, starting in the level's first room with an empty
+// inventory. The first player added turns a single player session into a multiplayer one,
+// with the existing player as HostPlayerID.
+// Returns a PlayersResult listing every player.
+func (e *Engine) AddPlayer(id string) (*PlayersResult, error) {
+	if id == "" {
+		return nil, world.Errorf(ErrInvalidArgument, "player ID must not be empty")
+	}
+	if e.LevelCompletionState != LevelCompletionStateInProgress {
+		return nil, world.Errorf(ErrLevelOver, "cannot join a level that is over")
+	}
+	if len(e.Players) == 0 {
+		e.rememberVersion()
+		e.Players = []*PlayerState{{ID: HostPlayerID}}
+		e.ActivePlayer = HostPlayerID
+		e.stashActivePlayer()
+	}
+	if e.getPlayerState(id) != nil {
+		return nil, world.Errorf(ErrInvalidArgument, "player %s has already joined", id)
+	}
+
+	floor := e.Level.Floors[0]
+	e.rememberVersion()
+	e.Players = append(e.Players, &PlayerState{
+		ID: id,
+		Player: &world.Player{
+			Inventory: make([]*world.Item, 0),
+			Health:    world.HealthState(world.HealthFine),
+			Ammo:      make(map[string]int),
+		},
+		CurrentFloor: floor,
+		CurrentRoom:  e.Level.GetRoom(floor.Name, floor.Rooms[0].Name),
+		Mode:         Investigation,
+	})
+	e.StateVersion++
+	return e.ListPlayers(), nil
+}
+
+// SwitchPlayer makes the player with the given ID the one that actions apply to.
+func (e *Engine) SwitchPlayer(id string) error {
+	if len(e.Players) == 0 {
+		if id == HostPlayerID {
+			return nil
+		}
+		return world.Errorf(ErrNotFound, "there is no player %s", id)
+	}
+	if e.getPlayerState(id) == nil {
+		return world.Errorf(ErrNotFound, "there is no player %s", id)
+	}
+	e.stashActivePlayer()
+	state := e.getPlayerState(id)
+	e.ActivePlayer = id
+	e.Player = state.Player
+	e.CurrentFloor = state.CurrentFloor
+	e.CurrentRoom = state.CurrentRoom
+	e.FightingEnemy = state.FightingEnemy
+	e.Mode = state.Mode
+
+	// Another player may have finished off the enemy this player was fighting
+	if e.FightingEnemy != nil && !e.FightingEnemy.IsAlive() {
+		e.Mode = Investigation
+		e.FightingEnemy = nil
+	}
+	return nil
+}
+
+// ListPlayers returns every player of a multiplayer session in join order.
+// The list is empty for single player sessions.
+func (e *Engine) ListPlayers() *PlayersResult {
+	e.stashActivePlayer()
+	players := make([]PlayerInfo, 0, len(e.Players))
+	for i, state := range e.Players {
+		players = append(players, PlayerInfo{
+			ID:            state.ID,
+			CurrentFloor:  state.CurrentFloor.Name,
+			CurrentRoom:   state.CurrentRoom.Name,
+			Health:        state.Player.Health,
+			Mode:          state.Mode,
+			InventorySize: len(state.Player.Inventory),
+			HasTurn:       e.TurnPolicy == TurnsRoundRobin && i == e.NextPlayer,
+		})
+	}
+	return &PlayersResult{
+		EngineStateInfo: *e.getEngineStateInfo(),
+		Players:         players,
+	}
+}
+
+// checkTurn returns an error if the active player may not take a turn now.
+func (e *Engine) checkTurn() error {
+	if e.TurnPolicy != TurnsRoundRobin || len(e.Players) == 0 {
+		return nil
+	}
+	if next := e.Players[e.NextPlayer].ID; next != e.ActivePlayer {
+		return world.Errorf(ErrNotYourTurn, "it is %s's turn", next)
+	}
+	return nil
+}
+
+// advanceTurn passes the turn to the next player.
+func (e *Engine) advanceTurn() {
+	if len(e.Players) > 0 {
+		e.NextPlayer = (e.NextPlayer + 1) % len(e.Players)
+	}
+}
+
+// nextPlayerID returns the ID of the player whose turn it is, or "" under free turns.
+func (e *Engine) nextPlayerID() string {
+	if e.TurnPolicy != TurnsRoundRobin || len(e.Players) == 0 {
+		return ""
+	}
+	return e.Players[e.NextPlayer].ID
+}
+
+// stashActivePlayer saves the active player's state from the engine into their PlayerState.
+func (e *Engine) stashActivePlayer() {
+	state := e.getPlayerState(e.ActivePlayer)
+	if state == nil {
+		return
+	}
+	state.Player = e.Player
+	state.CurrentFloor = e.CurrentFloor
+	state.CurrentRoom = e.CurrentRoom
+	state.FightingEnemy = e.FightingEnemy
+	state.Mode = e.Mode
+}
+
+func (e *Engine) getPlayerState(id string) *PlayerState {
+	index := slices.IndexFunc(e.Players, func(state *PlayerState) bool { return state.ID == id })
+	if index < 0 {
+		return nil
+	}
+	return e.Players[index]
+}
+
+// clonePlayers returns deep copies of the player states with world pointers remapped into level.
+func (e *Engine) clonePlayers(level *world.Level) []*PlayerState {
+	if e.Players == nil {
+		return nil
+	}
+	e.stashActivePlayer()
+	players := make([]*PlayerState, 0, len(e.Players))
+	for _, state := range e.Players {
+		stateCopy := &PlayerState{
+			ID:           state.ID,
+			Player:       state.Player.Clone(),
+			CurrentFloor: level.GetFloor(state.CurrentFloor.Name),
+			CurrentRoom:  level.GetRoom(state.CurrentFloor.Name, state.CurrentRoom.Name),
+			Mode:         state.Mode,
+		}
+		if state.FightingEnemy != nil {
+			stateCopy.FightingEnemy = level.GetEnemy(state.FightingEnemy.Name)
+		}
+		players = append(players, stateCopy)
+	}
+	return players
+}
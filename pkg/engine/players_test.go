@@ -0,0 +1,153 @@
+package engine
+
+import (
+	"adventure-engine/pkg/loader"
+	"errors"
+	"testing"
+)
+
+func TestPlayers_SeparateStateSharedWorld(t *testing.T) {
+	level, err := loader.LoadGameFromFile("../../internal/testdata/demo.json")
+	if err != nil {
+		t.Fatalf("Failed to load demo game: %v", err)
+	}
+	engine := NewEngine(level)
+
+	if len(engine.ListPlayers().Players) != 0 {
+		t.Fatal("Expected no players listed in a single player session")
+	}
+	if _, err := engine.Take(ctx, "energy drink"); err != nil {
+		t.Fatalf("Take energy drink failed: %v", err)
+	}
+
+	result, err := engine.AddPlayer("guest")
+	if err != nil {
+		t.Fatalf("AddPlayer failed: %v", err)
+	}
+	if len(result.Players) != 2 || result.Players[0].ID != HostPlayerID || result.Players[1].ID != "guest" {
+		t.Fatalf("Expected host and guest, got %+v", result.Players)
+	}
+	if result.Players[0].InventorySize != 1 {
+		t.Errorf("Expected the host to keep their inventory, got %d items", result.Players[0].InventorySize)
+	}
+	if _, err := engine.AddPlayer("guest"); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Expected ErrInvalidArgument adding a player twice, got %v", err)
+	}
+
+	// The guest has their own inventory and position
+	if err := engine.SwitchPlayer("guest"); err != nil {
+		t.Fatalf("SwitchPlayer guest failed: %v", err)
+	}
+	if engine.isItemInInventory("energy drink") {
+		t.Error("Expected the guest not to carry the host's energy drink")
+	}
+	if _, err := engine.Traverse(ctx, "left"); err != nil {
+		t.Fatalf("Traverse left failed: %v", err)
+	}
+	if _, err := engine.Take(ctx, "metal pipe"); err != nil {
+		t.Fatalf("Take metal pipe failed: %v", err)
+	}
+
+	// The host is still in the waiting room, and the pipe is gone from the shared level
+	if err := engine.SwitchPlayer(HostPlayerID); err != nil {
+		t.Fatalf("SwitchPlayer host failed: %v", err)
+	}
+	if engine.CurrentRoom.Name != "waiting room" {
+		t.Errorf("Expected the host in the waiting room, got %s", engine.CurrentRoom.Name)
+	}
+	if !engine.isItemInInventory("energy drink") || engine.isItemInInventory("metal pipe") {
+		t.Error("Expected the host to carry only the energy drink")
+	}
+	if _, err := engine.Traverse(ctx, "left"); err != nil {
+		t.Fatalf("Traverse left failed: %v", err)
+	}
+	if _, err := engine.Take(ctx, "metal pipe"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound taking the pipe the guest took, got %v", err)
+	}
+
+	if err := engine.SwitchPlayer("nobody"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound switching to an unknown player, got %v", err)
+	}
+}
+
+func TestPlayers_RoundRobinTurns(t *testing.T) {
+	level, err := loader.LoadGameFromFile("../../internal/testdata/demo.json")
+	if err != nil {
+		t.Fatalf("Failed to load demo game: %v", err)
+	}
+	engine := NewEngine(level)
+	engine.TurnPolicy = TurnsRoundRobin
+	if _, err := engine.AddPlayer("guest"); err != nil {
+		t.Fatalf("AddPlayer failed: %v", err)
+	}
+
+	// The host goes first; the guest can look around but not act
+	if err := engine.SwitchPlayer("guest"); err != nil {
+		t.Fatalf("SwitchPlayer guest failed: %v", err)
+	}
+	if _, err := engine.Observe(ctx, ObserveFilter{}); err != nil {
+		t.Errorf("Expected the guest to observe out of turn, got %v", err)
+	}
+	if _, err := engine.Take(ctx, "energy drink"); !errors.Is(err, ErrNotYourTurn) {
+		t.Fatalf("Expected ErrNotYourTurn, got %v", err)
+	}
+
+	if err := engine.SwitchPlayer(HostPlayerID); err != nil {
+		t.Fatalf("SwitchPlayer host failed: %v", err)
+	}
+	result, err := engine.Take(ctx, "energy drink")
+	if err != nil {
+		t.Fatalf("Take energy drink failed: %v", err)
+	}
+	if result.EngineStateInfo.NextPlayer != "guest" {
+		t.Errorf("Expected the guest to be next, got %q", result.EngineStateInfo.NextPlayer)
+	}
+	if _, err := engine.Traverse(ctx, "left"); !errors.Is(err, ErrNotYourTurn) {
+		t.Errorf("Expected ErrNotYourTurn for the host's second turn, got %v", err)
+	}
+
+	if err := engine.SwitchPlayer("guest"); err != nil {
+		t.Fatalf("SwitchPlayer guest failed: %v", err)
+	}
+	if _, err := engine.Traverse(ctx, "left"); err != nil {
+		t.Fatalf("Traverse left failed on the guest's turn: %v", err)
+	}
+	if engine.nextPlayerID() != HostPlayerID {
+		t.Errorf("Expected the turn to return to the host, got %q", engine.nextPlayerID())
+	}
+}
+
+func TestPlayers_SnapshotRestore(t *testing.T) {
+	level, err := loader.LoadGameFromFile("../../internal/testdata/demo.json")
+	if err != nil {
+		t.Fatalf("Failed to load demo game: %v", err)
+	}
+	engine := NewEngine(level)
+	if _, err := engine.AddPlayer("guest"); err != nil {
+		t.Fatalf("AddPlayer failed: %v", err)
+	}
+	snapshot := engine.Snapshot()
+
+	if err := engine.SwitchPlayer("guest"); err != nil {
+		t.Fatalf("SwitchPlayer guest failed: %v", err)
+	}
+	if _, err := engine.Take(ctx, "energy drink"); err != nil {
+		t.Fatalf("Take energy drink failed: %v", err)
+	}
+
+	if _, err := engine.Restore(snapshot); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if engine.ActivePlayer != HostPlayerID {
+		t.Errorf("Expected the host to be active after restore, got %s", engine.ActivePlayer)
+	}
+	if err := engine.SwitchPlayer("guest"); err != nil {
+		t.Fatalf("SwitchPlayer guest failed: %v", err)
+	}
+	if engine.isItemInInventory("energy drink") {
+		t.Error("Expected the guest's inventory to be restored")
+	}
+	if _, err := engine.CurrentRoom.GetItem("energy drink"); err != nil {
+		t.Errorf("Expected the energy drink back in the waiting room: %v", err)
+	}
+}
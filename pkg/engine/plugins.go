@@ -0,0 +1,91 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"slices"
+)
+
+// Plugin adds mechanics to an engine without changing it. A plugin implements ActionHook,
+// EffectHandler or both; the engine calls plugins in the order they are in Engine.Plugins.
+// Plugins keep their own state on the items' custom components or outside the engine, since
+// snapshots share plugins with the engine they were taken from.
+type Plugin interface {
+	// Name identifies the plugin in errors.
+	Name() string
+}
+
+// Action is an action that takes a turn, as a player asked for it.
+type Action struct {
+	Name string   // as in the API, such as take, traverse or battle
+	Args []string // the names the player gave, such as the item to take or the key and lock to unlock
+}
+
+// ActionHook is a plugin called around each action that takes a turn.
+type ActionHook interface {
+	Plugin
+	// BeforeAction is called once the engine has checked an action is allowed, before it runs.
+	// Returning an error refuses the action; an error without a kind is refused with ErrRefused.
+	BeforeAction(e *Engine, action Action) error
+	// AfterAction is called once an action has succeeded, before the end of the turn and the
+	// events the action caused are handled.
+	AfterAction(e *Engine, action Action)
+}
+
+// EffectHandler is a plugin that runs custom effects, those named with world.CustomEffectPrefix.
+type EffectHandler interface {
+	Plugin
+	// HandleEffect runs a custom effect, returning false if the plugin does not know it.
+	// Notifications raised with the engine's state change, such as entering combat, are
+	// returned for the engine to report.
+	HandleEffect(e *Engine, effect *world.Effect) (stateChange *EngineStateChangeNotification, handled bool)
+}
+
+// beforeAction lets the plugins refuse an action.
+func (e *Engine) beforeAction(action Action) error {
+	for _, plugin := range e.Plugins {
+		hook, ok := plugin.(ActionHook)
+		if !ok {
+			continue
+		}
+		if err := hook.BeforeAction(e, action); err != nil {
+			if ErrorCodeOf(err) == ErrorCodeUnknown {
+				return world.Errorf(ErrRefused, "%s: %v", plugin.Name(), err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// afterAction tells the plugins an action has succeeded.
+func (e *Engine) afterAction(action Action) {
+	for _, plugin := range e.Plugins {
+		if hook, ok := plugin.(ActionHook); ok {
+			hook.AfterAction(e, action)
+		}
+	}
+}
+
+// handlesEffects reports whether any plugin runs custom effects.
+func (e *Engine) handlesEffects() bool {
+	return slices.ContainsFunc(e.Plugins, func(plugin Plugin) bool {
+		_, ok := plugin.(EffectHandler)
+		return ok
+	})
+}
+
+// runCustomEffect runs a custom effect with the first plugin that handles it. Effects no
+// plugin handles do nothing.
+// Returns a state change notification if applicable.
+func (e *Engine) runCustomEffect(effect *world.Effect) *EngineStateChangeNotification {
+	for _, plugin := range e.Plugins {
+		handler, ok := plugin.(EffectHandler)
+		if !ok {
+			continue
+		}
+		if stateChange, handled := handler.HandleEffect(e, effect); handled {
+			return stateChange
+		}
+	}
+	return nil
+}
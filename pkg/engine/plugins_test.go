@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// cursePlugin refuses to let players take cursed items, and floods rooms on a custom effect.
+type cursePlugin struct {
+	actions []string
+	params  json.RawMessage
+}
+
+func (p *cursePlugin) Name() string { return "curses" }
+
+func (p *cursePlugin) BeforeAction(e *Engine, action Action) error {
+	if action.Name != "take" {
+		return nil
+	}
+	if item, err := e.CurrentRoom.GetItem(action.Args[0]); err == nil && item.CustomComponents["cursed"] != nil {
+		return errors.New("the idol is cursed")
+	}
+	return nil
+}
+
+func (p *cursePlugin) AfterAction(e *Engine, action Action) {
+	p.actions = append(p.actions, action.Name)
+}
+
+func (p *cursePlugin) HandleEffect(e *Engine, effect *world.Effect) (*EngineStateChangeNotification, bool) {
+	if effect.EffectType != "custom:flood" {
+		return nil, false
+	}
+	p.params = effect.Params
+	e.CurrentRoom.Airless = true
+	return nil, true
+}
+
+func TestPlugins(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "plugins.json"))
+	plugin := &cursePlugin{}
+	engine.Plugins = []Plugin{plugin}
+
+	if _, err := engine.Take(ctx, "idol"); !errors.Is(err, ErrRefused) {
+		t.Errorf("Expected the plugin to refuse taking the idol, got %v", err)
+	}
+	if engine.Stats.Turns != 0 {
+		t.Errorf("Expected a refused action not to take a turn, got %d turns", engine.Stats.Turns)
+	}
+	if _, err := engine.Take(ctx, "lever"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if _, err := engine.Use(ctx, "lever", "sluice"); err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if !slices.Equal(plugin.actions, []string{"take", "use"}) {
+		t.Errorf("Expected the plugin to see the take and the use, got %v", plugin.actions)
+	}
+	if !engine.CurrentRoom.Airless || string(plugin.params) != `{"depth":2}` {
+		t.Errorf("Expected the custom effect to flood the shrine with its params, got airless %v and params %s", engine.CurrentRoom.Airless, plugin.params)
+	}
+}
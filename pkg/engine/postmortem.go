@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"slices"
+
+	"adventure-engine/pkg/world"
+)
+
+// maxRecentActions is how many of the last actions a post-mortem lists.
+const maxRecentActions = 10
+
+// Causes of death, as given in a post-mortem.
+const (
+	CauseKilledByEnemy = "killed_by_enemy"
+	CauseSuffocated    = "suffocated"
+	CauseTrapped       = "trapped"
+)
+
+// PostMortem tells how the player died, for game over screens.
+type PostMortem struct {
+	Cause         string   // killed_by_enemy, suffocated or trapped
+	EnemyName     string   // the enemy that killed the player, if one did
+	ItemName      string   // the trapped item that killed the player, if one did
+	Room          string   // the room the player died in
+	LastActions   []Action // the last actions the player took, oldest first
+	RoomsExplored int      // percentage of the level's rooms the player visited
+}
+
+type PostMortemResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          PostMortem
+}
+
+// PostMortem returns how the player died, once the level has failed.
+func (e *Engine) PostMortem() (*PostMortemResult, error) {
+	if e.LevelCompletionState != LevelCompletionStateFailed || e.Death == nil {
+		return nil, world.Errorf(ErrWrongMode, "there is no post-mortem until you have died")
+	}
+	return &PostMortemResult{
+		EngineStateInfo: *e.getEngineStateInfo(),
+		Result:          *e.Death,
+	}, nil
+}
+
+// recordRecentAction remembers an action that took a turn, forgetting the oldest once more than
+// maxRecentActions are remembered.
+func (e *Engine) recordRecentAction(action Action) {
+	e.Telemetry.RecentActions = append(e.Telemetry.RecentActions, action)
+	if excess := len(e.Telemetry.RecentActions) - maxRecentActions; excess > 0 {
+		e.Telemetry.RecentActions = slices.Delete(e.Telemetry.RecentActions, 0, excess)
+	}
+}
+
+// recordDeath writes the post-mortem of the active player's death. Deaths without an enemy
+// or trapped item are from running out of breath.
+func (e *Engine) recordDeath(event *world.Event) {
+	death := &PostMortem{
+		Cause:         CauseSuffocated,
+		EnemyName:     event.EnemyName,
+		ItemName:      event.ItemName,
+		Room:          e.CurrentRoom.Name,
+		LastActions:   slices.Clone(e.Telemetry.RecentActions),
+		RoomsExplored: e.roomsExplored(),
+	}
+	switch {
+	case event.EnemyName != "":
+		death.Cause = CauseKilledByEnemy
+	case event.ItemName != "":
+		death.Cause = CauseTrapped
+	}
+	e.Death = death
+}
+
+// roomsExplored returns the percentage of the level's rooms that have been explored, rounded down.
+func (e *Engine) roomsExplored() int {
+	return e.explorationInfo().Percent
+}
+
+// isExplored returns true if a player has been in a room: they have looked around it, ended a turn
+// in it or are in it now.
+func (e *Engine) isExplored(room *world.Room) bool {
+	return room.Visited || e.Telemetry.RoomTurns[room.Name] > 0 || room == e.CurrentRoom
+}
@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestPostMortem_Drowned(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "flooded.json"))
+	engine.Level.FailureNarrative = "The water closes over you."
+
+	if _, err := engine.PostMortem(); !errors.Is(err, ErrWrongMode) {
+		t.Errorf("Expected no post-mortem while the player lives, got %v", err)
+	}
+	if _, err := engine.Observe(ctx, ObserveFilter{}); err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if _, err := engine.Traverse(ctx, "down"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if _, err := engine.Inspect(ctx, "pebble"); err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	take, err := engine.Take(ctx, "pebble")
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if take.EngineStateInfo.FailureNarrative != "The water closes over you." || take.EngineStateInfo.PostMortem == nil {
+		t.Fatalf("Expected the failure narrative and post-mortem with the drowning, got %+v", take.EngineStateInfo)
+	}
+
+	result, err := engine.PostMortem()
+	if err != nil {
+		t.Fatalf("PostMortem failed: %v", err)
+	}
+	death := result.Result
+	if death.Cause != CauseSuffocated || death.EnemyName != "" || death.Room != "flooded tunnel" {
+		t.Errorf("Expected the player to have drowned in the tunnel, got %+v", death)
+	}
+	expected := []Action{{Name: "traverse", Args: []string{"down"}}, {Name: "inspect", Args: []string{"pebble"}}, {Name: "take", Args: []string{"pebble"}}}
+	if !slices.EqualFunc(death.LastActions, expected, func(a, b Action) bool { return a.Name == b.Name && slices.Equal(a.Args, b.Args) }) {
+		t.Errorf("Expected the actions that led to the drowning, got %+v", death.LastActions)
+	}
+	if death.RoomsExplored != 66 {
+		t.Errorf("Expected 2 of 3 rooms explored, got %d%%", death.RoomsExplored)
+	}
+}
+
+func TestPostMortem_KilledByEnemy(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "durability.json"))
+	engine.Rng = &FakeRng{Value: 0.95}
+	if _, err := engine.Take(ctx, "knife"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	for engine.LevelCompletionState == LevelCompletionStateInProgress {
+		if _, err := engine.Battle(ctx, "fists"); err != nil {
+			t.Fatalf("Battle failed: %v", err)
+		}
+	}
+
+	result, err := engine.PostMortem()
+	if err != nil {
+		t.Fatalf("PostMortem failed: %v", err)
+	}
+	if result.Result.Cause != CauseKilledByEnemy || result.Result.EnemyName != "rat" || result.Result.Room != "workshop" {
+		t.Errorf("Expected the rat to have killed the player in the workshop, got %+v", result.Result)
+	}
+	if result.Result.RoomsExplored != 100 {
+		t.Errorf("Expected the only room to count as explored, got %d%%", result.Result.RoomsExplored)
+	}
+}
+
+func TestRecordRecentAction_KeepsTheLastTen(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "flooded.json"))
+	for i := range 12 {
+		engine.recordRecentAction(Action{Name: fmt.Sprint(i)})
+	}
+	actions := engine.Telemetry.RecentActions
+	if len(actions) != maxRecentActions || actions[0].Name != "2" || actions[9].Name != "11" {
+		t.Errorf("Expected actions 2 to 11, got %+v", actions)
+	}
+}
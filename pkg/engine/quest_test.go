@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestQuestItem_CannotBeCrafted(t *testing.T) {
+	engine := loadCraftingLevel(t)
+	hook, err := engine.Player.GetItem("hook")
+	if err != nil {
+		t.Fatalf("GetItem failed: %v", err)
+	}
+	hook.QuestItem = true
+
+	if _, err := engine.Combine(ctx, "hook", "oil"); !errors.Is(err, ErrQuestItem) {
+		t.Errorf("Expected crafting with a quest item to be refused, got %v", err)
+	}
+	if len(engine.Player.Inventory) != 4 {
+		t.Errorf("Expected the refused recipe to use up nothing, got %d items", len(engine.Player.Inventory))
+	}
+	if _, err := engine.Combine(ctx, "rope", "pole"); err != nil {
+		t.Errorf("Expected other recipes to still be made, got %v", err)
+	}
+}
+
+func TestQuestItem_CannotBeGivenAway(t *testing.T) {
+	engine := loadBridgeLevel(t)
+	coin, err := engine.Player.GetItem("gold coin")
+	if err != nil {
+		t.Fatalf("GetItem failed: %v", err)
+	}
+	coin.QuestItem = true
+
+	if _, err := engine.Bribe(ctx, "gold coin"); !errors.Is(err, ErrQuestItem) {
+		t.Errorf("Expected bribing with a quest item to be refused, got %v", err)
+	}
+	if _, err := engine.Player.GetItem("gold coin"); err != nil {
+		t.Errorf("Expected the player to keep the gold coin: %v", err)
+	}
+	if slices.Contains(engine.getEngineStateInfo().AllowedActions, "bribe") {
+		t.Error("Expected bribing not to be allowed with nothing but a quest item to offer")
+	}
+}
@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"slices"
+
+	"adventure-engine/pkg/world"
+)
+
+// RecipeInfo describes a crafting recipe. Recipes are known by their output item.
+// The output of a recipe the players have not discovered is left empty.
+type RecipeInfo struct {
+	InputNames  []string
+	FixtureName string
+	OutputName  string
+	Discovered  bool
+}
+
+type RecipesResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          []RecipeInfo
+}
+
+// Recipes returns the recipes discovered so far, in the order the level lists them.
+// Undiscovered recipes are only listed, without their output, if includeUndiscovered is set.
+// Allowed in all modes and after the level has ended.
+func (e *Engine) Recipes(includeUndiscovered bool) (*RecipesResult, error) {
+	recipes := []RecipeInfo{}
+	for _, comboItem := range e.Level.ComboItems {
+		recipe := RecipeInfo{
+			InputNames:  comboItem.InputNames(),
+			FixtureName: comboItem.FixtureName,
+			Discovered:  e.recipeKnown(comboItem),
+		}
+		if recipe.Discovered {
+			recipe.OutputName = comboItem.OutputItem.Name
+		} else if !includeUndiscovered {
+			continue
+		}
+		recipes = append(recipes, recipe)
+	}
+	return &RecipesResult{
+		EngineStateInfo: *e.getEngineStateInfo(),
+		Result:          recipes,
+	}, nil
+}
+
+// recipeKnown returns true if the level starts with the recipe known or a player has discovered it.
+func (e *Engine) recipeKnown(comboItem *world.ComboItem) bool {
+	return comboItem.Known || e.KnownRecipes[comboItem.OutputItem.Name]
+}
+
+// learnRecipes records the recipes an inspected item teaches, returning the outputs of
+// those not known before.
+func (e *Engine) learnRecipes(itemName string) []string {
+	var learned []string
+	for _, comboItem := range e.Level.ComboItems {
+		if slices.Contains(comboItem.TaughtBy, itemName) && !e.recipeKnown(comboItem) {
+			e.KnownRecipes[comboItem.OutputItem.Name] = true
+			learned = append(learned, comboItem.OutputItem.Name)
+		}
+	}
+	return learned
+}
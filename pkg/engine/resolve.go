@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"context"
+	"slices"
+
+	"adventure-engine/pkg/world"
+)
+
+// Ways of getting past an enemy without killing it, named as in Action.
+const (
+	approachIntimidate = "intimidate"
+	approachBribe      = "bribe"
+	approachSneak      = "sneak"
+)
+
+type ResolveResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          resolveResultInternal
+}
+
+// resolveResultInternal is the result of trying to get past an enemy without a fight.
+type resolveResultInternal struct {
+	EnemyName   string
+	Approach    string // intimidate, bribe or sneak
+	Succeeded   bool   // true if the fight is over
+	BribeItem   string // the item handed over, for bribes
+	PlayerAlive bool
+	Absorbed    string    // the armor that stopped the enemy's hit when the attempt failed, if any
+	BrokenArmor string    // the armor, if stopping the hit broke it
+	ArmorScrap  *ItemInfo // what the broken armor left behind, if anything
+}
+
+// Intimidate tries to scare off the enemy the player is fighting. An enemy scared off never
+// attacks again; if it stands its ground, it strikes the player.
+// Returns a ResolveResult and engine state info with state change notifications, if applicable.
+func (e *Engine) Intimidate(ctx context.Context) (*ResolveResult, error) {
+	return e.resolve(ctx, approachIntimidate, "")
+}
+
+// Bribe buys off the enemy the player is fighting with an item it wants, handing the item
+// over. A bribed enemy never attacks again.
+// Returns a ResolveResult and engine state info with state change notifications, if applicable.
+func (e *Engine) Bribe(ctx context.Context, itemName string) (*ResolveResult, error) {
+	return e.resolve(ctx, approachBribe, itemName)
+}
+
+// Sneak tries to slip away from the enemy the player is fighting, ending the fight. The enemy
+// is left where it is and its triggers can set it off again. If it notices, it strikes the
+// player.
+// Returns a ResolveResult and engine state info with state change notifications, if applicable.
+func (e *Engine) Sneak(ctx context.Context) (*ResolveResult, error) {
+	return e.resolve(ctx, approachSneak, "")
+}
+
+func (e *Engine) resolve(ctx context.Context, approach string, itemName string) (*ResolveResult, error) {
+	return act(e, ctx, approach, func() (*ResolveResult, error) {
+		action := Action{Name: approach}
+		if approach == approachBribe {
+			action.Args = []string{itemName}
+		}
+		if err := e.validateEngineStateForCombatActions(action); err != nil {
+			return nil, err
+		}
+		resolveResult, err := e.resolveInternal(approach, itemName)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		if !resolveResult.PlayerAlive {
+			e.publish(&world.Event{
+				Event:     world.EventPlayerKilled,
+				EnemyName: resolveResult.EnemyName,
+			})
+		}
+		return &ResolveResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *resolveResult,
+		}, nil
+	})
+}
+
+// resolveInternal tries to get past the enemy the player is fighting without killing it.
+// Enemies that can't be dealt with that way are refused without taking a turn.
+func (e *Engine) resolveInternal(approach string, itemName string) (*resolveResultInternal, error) {
+	enemy := e.FightingEnemy
+	if enemy == nil {
+		return nil, world.Errorf(ErrWrongMode, "there is no enemy to get past")
+	}
+	result := &resolveResultInternal{EnemyName: enemy.Name, Approach: approach}
+	switch approach {
+	case approachIntimidate:
+		if enemy.Intimidate == 0 {
+			return nil, world.Errorf(ErrInvalidTarget, "the %s won't be intimidated", enemy.Name)
+		}
+		result.Succeeded = e.chance("the "+enemy.Name+" is intimidated", enemy.Intimidate)
+	case approachSneak:
+		if enemy.Sneak == 0 {
+			return nil, world.Errorf(ErrInvalidTarget, "there is no sneaking past the %s", enemy.Name)
+		}
+		result.Succeeded = e.chance("the player sneaks past the "+enemy.Name, enemy.Sneak)
+	case approachBribe:
+		name, err := e.resolveItemName(itemName)
+		if err != nil {
+			return nil, err
+		}
+		item, err := e.Player.GetItem(name)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(enemy.Bribes, item.Name) {
+			return nil, world.Errorf(ErrInvalidTarget, "the %s has no interest in the %s", enemy.Name, item.Name)
+		}
+		if item.QuestItem {
+			return nil, world.Errorf(ErrQuestItem, "you can't give the %s away", item.Name)
+		}
+		e.Player.ConsumeItem(item.Ref())
+		result.BribeItem = item.Name
+		result.Succeeded = true
+	}
+
+	switch {
+	case !result.Succeeded:
+		result.Absorbed, result.BrokenArmor, result.ArmorScrap = e.enemyStrikes()
+	case approach == approachSneak:
+		e.Mode = Investigation
+		e.FightingEnemy = nil
+		stateChange := EngineStateChangeExitCombat
+		e.notify(&stateChange)
+	default:
+		e.Telemetry.EnemiesSpared++
+		e.notify(e.runEffect(&world.Effect{EffectType: world.EffectEndCombat, EnemyName: enemy.Name}))
+	}
+	result.PlayerAlive = e.Player.IsAlive()
+	return result, nil
+}
+
+// canBribe returns true if the player carries something other than a quest item that the enemy
+// they are fighting takes as a bribe.
+func (e *Engine) canBribe() bool {
+	return slices.ContainsFunc(e.Player.Inventory, func(item *world.Item) bool {
+		return !item.QuestItem && slices.Contains(e.FightingEnemy.Bribes, item.Name)
+	})
+}
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func loadBridgeLevel(t *testing.T) *Engine {
+	t.Helper()
+	engine := NewEngine(loadTestLevel(t, "bridge.json"))
+	if _, err := engine.Take(ctx, "gold coin"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	return engine
+}
+
+func TestResolve_IntimidateAndBribe(t *testing.T) {
+	engine := loadBridgeLevel(t)
+	engine.Rng = &FakeRng{Value: 0.9}
+
+	expected := []string{"observe", "inventory", "status", "context", "minimap", "battle", "intimidate", "bribe", "sneak"}
+	if state := engine.getEngineStateInfo(); !slices.Equal(state.AllowedActions, expected) {
+		t.Errorf("Expected every way past the troll to be allowed, got %v", state.AllowedActions)
+	}
+
+	// The troll stands its ground and strikes
+	intimidate, err := engine.Intimidate(ctx)
+	if err != nil {
+		t.Fatalf("Intimidate failed: %v", err)
+	}
+	if intimidate.Result.Succeeded || engine.Mode != Combat || engine.Player.Health != world.HealthHurt {
+		t.Errorf("Expected the troll to hurt the player and fight on, got %+v", intimidate.Result)
+	}
+
+	bribe, err := engine.Bribe(ctx, "gold coin")
+	if err != nil {
+		t.Fatalf("Bribe failed: %v", err)
+	}
+	if !bribe.Result.Succeeded || bribe.Result.BribeItem != "gold coin" || engine.Mode != Investigation || len(engine.Player.Inventory) != 0 {
+		t.Errorf("Expected the gold coin to buy the troll off, got %+v", bribe.Result)
+	}
+	if !slices.Contains(bribe.EngineStateInfo.Notifications, EngineStateChangeExitCombat) {
+		t.Errorf("Expected the bribe to end combat, got %v", bribe.EngineStateInfo.Notifications)
+	}
+	if len(engine.Level.Triggers) != 0 || engine.Level.GetEnemy("troll").HP != 3 {
+		t.Errorf("Expected the troll to leave alive and never attack again, got triggers %v", engine.Level.Triggers)
+	}
+	if stats := engine.computeStats(); stats.EnemiesSpared != 1 || stats.Stats.EnemiesDefeated != 0 {
+		t.Errorf("Expected the troll to be spared rather than defeated, got %+v", stats)
+	}
+}
+
+func TestResolve_Sneak(t *testing.T) {
+	engine := loadBridgeLevel(t)
+	engine.Rng = &FakeRng{Value: 0.1}
+	engine.FightingEnemy.Intimidate = 0
+
+	if _, err := engine.Intimidate(ctx); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("Expected a troll that can't be intimidated to refuse, got %v", err)
+	}
+	if _, err := engine.Bribe(ctx, "fists"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected bribing with something the player doesn't have to fail, got %v", err)
+	}
+
+	sneak, err := engine.Sneak(ctx)
+	if err != nil {
+		t.Fatalf("Sneak failed: %v", err)
+	}
+	if !sneak.Result.Succeeded || engine.Mode != Investigation || engine.Stats.Turns != 2 {
+		t.Errorf("Expected to slip away from the troll in one turn, got %+v", sneak.Result)
+	}
+	// The troll is left where it is, ready to be set off again
+	if len(engine.Level.Triggers) != 1 || engine.computeStats().EnemiesSpared != 0 {
+		t.Errorf("Expected the troll's trigger to stay, got %v", engine.Level.Triggers)
+	}
+}
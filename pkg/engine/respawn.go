@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"slices"
+
+	"adventure-engine/pkg/world"
+)
+
+// DeathPolicy decides what happens when a player dies.
+type DeathPolicy string
+
+const (
+	// DeathPermadeath fails the level when a player dies.
+	DeathPermadeath DeathPolicy = "permadeath"
+	// DeathRespawn brings a dead player back to life in the last save point room they entered,
+	// or the level's first room if they have entered none. Everything they carried is left
+	// behind in the room they died in.
+	DeathRespawn DeathPolicy = "respawn"
+)
+
+// respawn brings the dead active player back to life in their respawn room, at full health
+// and out of combat. The items in their inventory are dropped in the room they died in, for
+// them to come back for.
+// Returns a respawned notification.
+func (e *Engine) respawn() *EngineStateChangeNotification {
+	for _, item := range e.Player.Inventory {
+		item.Location, item.SubLocation = "", ""
+		e.CurrentRoom.Items = append(e.CurrentRoom.Items, item)
+	}
+	e.Player.Inventory = make([]*world.Item, 0)
+	e.Player.Health = world.HealthFine
+	e.Player.BreathHeld = 0
+	e.curePoison()
+	e.Mode = Investigation
+	e.FightingEnemy = nil
+	e.CurrentFloor, e.CurrentRoom = e.respawnRoom()
+	e.updateMinimapDataForCurrenRoom()
+	stateChange := EngineStateChangeRespawned
+	return &stateChange
+}
+
+// respawnRoom returns the floor and room the active player respawns in.
+func (e *Engine) respawnRoom() (*world.Floor, *world.Room) {
+	if floor, room := e.Level.FindRoom(e.Player.SavePoint); room != nil {
+		return floor, room
+	}
+	return e.Level.Floors[0], e.Level.Floors[0].Rooms[0]
+}
+
+// diedThisTurn returns true if the active player is dead, or died and respawned during the
+// action in progress.
+func (e *Engine) diedThisTurn() bool {
+	return !e.Player.IsAlive() || slices.Contains(e.notifications, EngineStateChangeRespawned)
+}
+
+type RespawnResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          observeResultInternal
+}
+
+// Respawn brings a player who died back to when a player last entered a save point room,
+// restoring the game state as it was then. Only allowed once the level has failed.
+// Returns a RespawnResult describing the room the player is back in.
+func (e *Engine) Respawn() (*RespawnResult, error) {
+	if e.LevelCompletionState != LevelCompletionStateFailed {
+		return nil, world.Errorf(ErrWrongMode, "you can only respawn once you have died")
+	}
+	checkpoint := e.Checkpoint
+	if checkpoint == nil {
+		return nil, world.Errorf(ErrNotFound, "you have not reached a save point to respawn at")
+	}
+	if _, err := e.Restore(checkpoint); err != nil {
+		return nil, err
+	}
+	e.Checkpoint = checkpoint
+	observeResult, err := e.observeInternal(ObserveFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return &RespawnResult{
+		EngineStateInfo: *e.getEngineStateInfo(),
+		Result:          *observeResult,
+	}, nil
+}
+
+// recordCheckpoint snapshots the game state for a player who dies later to respawn at.
+// The snapshot leaves out the checkpoint before it, so that checkpoints don't chain back
+// through the whole game; Respawn puts the checkpoint back once it has restored it.
+func (e *Engine) recordCheckpoint() {
+	e.Checkpoint = nil
+	e.Checkpoint = e.Snapshot()
+}
@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"errors"
+	"testing"
+)
+
+func loadRespawnLevel(t *testing.T, deathPolicy DeathPolicy) *Engine {
+	t.Helper()
+	engine := NewEngine(loadTestLevel(t, "respawn.json"))
+	engine.DeathPolicy = deathPolicy
+	engine.Rng = &FakeRng{Value: 0.9}
+	if _, err := engine.Take(ctx, "lantern"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	for _, direction := range []string{"north", "north"} {
+		if _, err := engine.Traverse(ctx, direction); err != nil {
+			t.Fatalf("Traverse failed: %v", err)
+		}
+	}
+	engine.Player.Health = world.HealthCrit
+	return engine
+}
+
+func TestBattle_Respawn(t *testing.T) {
+	engine := loadRespawnLevel(t, DeathRespawn)
+
+	battle, err := engine.Battle(ctx, "")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if notification := battle.EngineStateInfo.EngineStateChangeNotification; notification == nil || *notification != EngineStateChangeRespawned {
+		t.Fatalf("Expected the player to respawn, got %v", notification)
+	}
+	if engine.CurrentRoom.Name != "hall" || engine.Mode != Investigation || engine.Player.Health != world.HealthFine {
+		t.Errorf("Expected the player back in the hall, fine and out of combat, got %s, %s and %s", engine.CurrentRoom.Name, engine.Mode, engine.Player.Health)
+	}
+	if engine.LevelCompletionState != LevelCompletionStateInProgress || battle.EngineStateInfo.RespawnRoom != "hall" {
+		t.Errorf("Expected the level to go on with the hall as the respawn room, got %v and %q", engine.LevelCompletionState, battle.EngineStateInfo.RespawnRoom)
+	}
+	lair := engine.Level.GetRoom(engine.CurrentFloor.Name, "lair")
+	if len(engine.Player.Inventory) != 0 || len(lair.Items) != 1 || lair.Items[0].Name != "lantern" {
+		t.Errorf("Expected the lantern to be left in the lair, got %d items carried", len(engine.Player.Inventory))
+	}
+
+	if _, err := engine.Traverse(ctx, "north"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if engine.Mode != Combat || engine.FightingEnemy.Name != "troll" {
+		t.Error("Expected the troll to be waiting in the lair")
+	}
+}
+
+func TestBattle_Permadeath(t *testing.T) {
+	engine := loadRespawnLevel(t, DeathPermadeath)
+
+	battle, err := engine.Battle(ctx, "")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if notification := battle.EngineStateInfo.EngineStateChangeNotification; notification == nil || *notification != EngineStateChangeLevelFailed {
+		t.Errorf("Expected the level to fail, got %v", notification)
+	}
+	if battle.EngineStateInfo.RespawnRoom != "" {
+		t.Errorf("Expected no respawn room, got %q", battle.EngineStateInfo.RespawnRoom)
+	}
+}
+
+func TestRespawn_Checkpoint(t *testing.T) {
+	engine := loadRespawnLevel(t, DeathPermadeath)
+	if _, err := engine.Respawn(); !errors.Is(err, ErrWrongMode) {
+		t.Errorf("Expected respawning before dying to be refused, got %v", err)
+	}
+	battle, err := engine.Battle(ctx, "")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if !battle.EngineStateInfo.CanRespawn {
+		t.Error("Expected the failed level to offer respawning at the hall")
+	}
+
+	respawn, err := engine.Respawn()
+	if err != nil {
+		t.Fatalf("Respawn failed: %v", err)
+	}
+	if respawn.Result.RoomName != "hall" || respawn.EngineStateInfo.LevelCompletionState != LevelCompletionStateInProgress {
+		t.Errorf("Expected the player back in the hall with the level going on, got %s and %v", respawn.Result.RoomName, respawn.EngineStateInfo.LevelCompletionState)
+	}
+	if engine.Player.Health != world.HealthFine || len(engine.Player.Inventory) != 1 || engine.Mode != Investigation {
+		t.Errorf("Expected the player as they entered the hall, got %s with %d items", engine.Player.Health, len(engine.Player.Inventory))
+	}
+	if engine.Checkpoint == nil {
+		t.Error("Expected the player to be able to respawn at the hall again")
+	}
+
+	engine = loadRespawnLevel(t, DeathPermadeath)
+	engine.Checkpoint = nil
+	engine.LevelCompletionState = LevelCompletionStateFailed
+	if _, err := engine.Respawn(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected respawning without a checkpoint to be refused, got %v", err)
+	}
+}
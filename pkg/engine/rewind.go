@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"slices"
+
+	"adventure-engine/pkg/world"
+)
+
+// maxTurnHistory is how many past turns an engine with turn history on remembers to rewind to.
+const maxTurnHistory = 64
+
+// turnRecord is the game state as it stood once a number of turns had been taken, after
+// everything the last of them set off.
+type turnRecord struct {
+	turn     int
+	snapshot *Snapshot
+}
+
+// rememberTurn records the game state as of the turns taken so far, unless it already has or
+// turn history is off. Called before every action, so that the record holds the state the last
+// turn left behind.
+func (e *Engine) rememberTurn() {
+	if !e.TurnHistory {
+		return
+	}
+	if n := len(e.turns); n > 0 && e.turns[n-1].turn == e.Stats.Turns {
+		return
+	}
+	e.turns = append(e.turns, &turnRecord{turn: e.Stats.Turns, snapshot: e.Snapshot()})
+	if len(e.turns) > maxTurnHistory {
+		e.turns = slices.Delete(e.turns, 0, len(e.turns)-maxTurnHistory)
+	}
+}
+
+// Rewind restores the game state as it was once the given number of turns had been taken, for
+// investigating how a session got into a state it shouldn't be in. Engines only remember turns
+// taken with turn history on, and then only the last maxTurnHistory of them; rewinding further
+// fails with ErrVersionExpired, and rewinding at all with turn history off fails with
+// ErrWrongMode. The turns after the one rewound to are forgotten.
+// Returns a RestoreResult with the restored engine state info.
+func (e *Engine) Rewind(turn int) (*RestoreResult, error) {
+	if turn < 0 || turn > e.Stats.Turns {
+		return nil, world.Errorf(ErrInvalidArgument, "turn %d is not one the game has reached, which is at turn %d", turn, e.Stats.Turns)
+	}
+	if turn == e.Stats.Turns {
+		return e.Restore(e.Snapshot())
+	}
+	if !e.TurnHistory {
+		return nil, world.Errorf(ErrWrongMode, "turn history is off, so no past turns are remembered to rewind to")
+	}
+	i := slices.IndexFunc(e.turns, func(record *turnRecord) bool { return record.turn == turn })
+	if i < 0 {
+		return nil, world.Errorf(ErrVersionExpired, "turn %d is too long ago to rewind to", turn)
+	}
+	return e.Restore(e.turns[i].snapshot)
+}
+
+// turnsBefore returns the remembered turns before a turn, which are still the history of a
+// game restored to that turn.
+func (e *Engine) turnsBefore(turn int) []*turnRecord {
+	return slices.DeleteFunc(slices.Clone(e.turns), func(record *turnRecord) bool { return record.turn >= turn })
+}
+
+// EnableTurnHistory starts remembering turns to rewind to, from the current one on.
+func (e *Engine) EnableTurnHistory() {
+	e.TurnHistory = true
+}
+
+// DisableTurnHistory stops remembering turns and forgets the ones remembered so far.
+func (e *Engine) DisableTurnHistory() {
+	e.TurnHistory = false
+	e.turns = nil
+}
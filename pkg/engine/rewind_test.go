@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRewind(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "flooded.json"))
+	engine.EnableTurnHistory()
+	if _, err := engine.Traverse(ctx, "down"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if _, err := engine.Take(ctx, "pebble"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if _, err := engine.Traverse(ctx, "up"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+
+	result, err := engine.Rewind(1)
+	if err != nil {
+		t.Fatalf("Rewind failed: %v", err)
+	}
+	if engine.Stats.Turns != 1 || engine.CurrentRoom.Name != "flooded tunnel" || len(engine.Player.Inventory) != 0 {
+		t.Errorf("Expected to be back in the tunnel before taking the pebble, got turn %d in the %s", engine.Stats.Turns, engine.CurrentRoom.Name)
+	}
+	if result.EngineStateInfo.StateVersion <= 3 {
+		t.Errorf("Expected the state version to keep increasing, got %d", result.EngineStateInfo.StateVersion)
+	}
+	// The turns after the one rewound to are gone
+	if _, err := engine.Rewind(2); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Expected turn 2 to be forgotten, got %v", err)
+	}
+
+	if _, err := engine.Take(ctx, "pebble"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if _, err := engine.Rewind(0); err != nil {
+		t.Fatalf("Rewind failed: %v", err)
+	}
+	if engine.Stats.Turns != 0 || engine.CurrentRoom.Name != "dock" {
+		t.Errorf("Expected to be back at the start, got turn %d in the %s", engine.Stats.Turns, engine.CurrentRoom.Name)
+	}
+}
+
+func TestRewind_ForgetsOldTurns(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "flooded.json"))
+	engine.EnableTurnHistory()
+	for range maxTurnHistory + 1 {
+		if _, err := engine.Inspect(ctx, "air tank"); err != nil {
+			t.Fatalf("Inspect failed: %v", err)
+		}
+	}
+	if _, err := engine.Rewind(0); !errors.Is(err, ErrVersionExpired) {
+		t.Errorf("Expected the first turn to be too long ago, got %v", err)
+	}
+	if _, err := engine.Rewind(2); err != nil {
+		t.Errorf("Expected to rewind to a recent turn, got %v", err)
+	}
+}
+
+func TestRewind_TurnHistoryOff(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "flooded.json"))
+	if _, err := engine.Inspect(ctx, "air tank"); err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if len(engine.turns) != 0 {
+		t.Errorf("Expected no turns to be remembered, got %d", len(engine.turns))
+	}
+	if _, err := engine.Rewind(0); !errors.Is(err, ErrWrongMode) {
+		t.Errorf("Expected rewinding to fail without turn history, got %v", err)
+	}
+	// The current turn needs no history
+	if _, err := engine.Rewind(1); err != nil {
+		t.Errorf("Expected to rewind to the current turn, got %v", err)
+	}
+
+	engine.EnableTurnHistory()
+	if _, err := engine.Inspect(ctx, "air tank"); err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if _, err := engine.Rewind(1); err != nil {
+		t.Errorf("Expected to rewind to the turn history was turned on at, got %v", err)
+	}
+	engine.DisableTurnHistory()
+	if len(engine.turns) != 0 {
+		t.Errorf("Expected the remembered turns to be forgotten, got %d", len(engine.turns))
+	}
+}
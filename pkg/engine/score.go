@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+)
+
+// Stats contains per-session statistics used to compute the level score.
+type Stats struct {
+	Turns           int
+	EnemiesDefeated int
+	DamageTaken     int
+	SecretsFound    int
+}
+
+// BadgeInfo contains the name and description of an earned badge.
+type BadgeInfo struct {
+	Name        string
+	Description string
+}
+
+// ScoreSummary contains the statistics, score and earned badges for a session.
+// The score is provisional until the level is complete; badges are only
+// awarded once the level is complete.
+type ScoreSummary struct {
+	Stats  Stats
+	Score  int
+	Badges []BadgeInfo
+	Final  bool
+}
+
+type ScoreResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          ScoreSummary
+}
+
+// Score returns the current statistics and score for the session.
+// Allowed in all modes and after the level has ended.
+func (e *Engine) Score() (*ScoreResult, error) {
+	return &ScoreResult{
+		EngineStateInfo: *e.getEngineStateInfo(),
+		Result:          *e.computeScore(),
+	}, nil
+}
+
+// recordTurn increments the turn counter and the state version after a successful player action,
+// remembers the action for post-mortems, tells the plugins about it, lets the player breathe
+// or not depending on the room the action ended in, and lets any poison in them do its work.
+func (e *Engine) recordTurn(action Action) {
+	e.recordRecentAction(action)
+	e.Stats.Turns++
+	e.Telemetry.Actions[action.Name]++
+	e.Telemetry.RoomTurns[e.CurrentRoom.Name]++
+	e.StateVersion++
+	e.afterAction(action)
+	e.advanceTurn()
+	e.breathe()
+	e.sufferPoison()
+}
+
+// recordSecretFound counts a secret item the first time the player finds it.
+func (e *Engine) recordSecretFound(item *world.Item) {
+	if item == nil || !item.Secret || e.FoundSecrets[item.Ref()] {
+		return
+	}
+	e.FoundSecrets[item.Ref()] = true
+	e.Stats.SecretsFound++
+}
+
+// computeScore computes the score summary from the current statistics.
+func (e *Engine) computeScore() *ScoreSummary {
+	scoring := e.Level.GetScoring()
+	score := scoring.BasePoints -
+		scoring.TurnPenalty*e.Stats.Turns -
+		scoring.DamagePenalty*e.Stats.DamageTaken +
+		scoring.EnemyBonus*e.Stats.EnemiesDefeated +
+		scoring.SecretBonus*e.Stats.SecretsFound
+	if score < 0 {
+		score = 0
+	}
+
+	summary := &ScoreSummary{
+		Stats: e.Stats,
+		Score: score,
+		Final: e.LevelCompletionState == LevelCompletionStateComplete,
+	}
+	if summary.Final {
+		for _, badge := range scoring.Badges {
+			if e.meetsBadgeCondition(&badge.Condition) {
+				summary.Badges = append(summary.Badges, BadgeInfo{
+					Name:        badge.Name,
+					Description: e.localize(badge.Description),
+				})
+			}
+		}
+	}
+	return summary
+}
+
+// meetsBadgeCondition checks the current statistics against a badge's thresholds.
+func (e *Engine) meetsBadgeCondition(cond *world.BadgeCondition) bool {
+	if cond.MaxTurns != nil && e.Stats.Turns > *cond.MaxTurns {
+		return false
+	}
+	if cond.MinEnemiesDefeated != nil && e.Stats.EnemiesDefeated < *cond.MinEnemiesDefeated {
+		return false
+	}
+	if cond.MaxDamageTaken != nil && e.Stats.DamageTaken > *cond.MaxDamageTaken {
+		return false
+	}
+	if cond.MinSecretsFound != nil && e.Stats.SecretsFound < *cond.MinSecretsFound {
+		return false
+	}
+	return true
+}
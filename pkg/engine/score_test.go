@@ -0,0 +1,174 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"testing"
+)
+
+func TestScore_StatsAndBadges(t *testing.T) {
+	maxTurns := 4
+	maxDamage := 0
+	gem := &world.Item{
+		BaseEntity: world.BaseEntity{
+			Name:        "gem",
+			Description: "a hidden gem",
+		},
+		Secret:   true,
+		Portable: &world.Portable{},
+	}
+	rug := &world.Item{
+		BaseEntity: world.BaseEntity{
+			Name:        "rug",
+			Description: "a dusty rug",
+		},
+		Concealer: &world.Concealer{Hidden: gem},
+	}
+	sword := &world.Item{
+		BaseEntity: world.BaseEntity{
+			Name:        "sword",
+			Description: "a sharp sword",
+		},
+		Portable: &world.Portable{},
+		Weapon:   &world.Weapon{Damage: 1.0},
+	}
+	room := &world.Room{
+		BaseEntity: world.BaseEntity{
+			Name:        "room",
+			Description: "a test room",
+		},
+		Items: []*world.Item{rug, sword},
+	}
+	goblin := &world.Enemy{
+		BaseEntity: world.BaseEntity{
+			Name:        "goblin",
+			Description: "a goblin",
+		},
+		HP: 1,
+	}
+
+	engine := NewEngine(&world.Level{
+		Floors: []*world.Floor{{
+			Name:  "test_floor",
+			Rooms: []*world.Room{room},
+		}},
+		Enemies: []*world.Enemy{goblin},
+		Triggers: []*world.Trigger{{
+			Event:  world.Event{Event: world.EventItemTaken, ItemName: "gem"},
+			Effect: world.Effect{EffectType: world.EffectEnterCombat, EnemyName: "goblin"},
+		}},
+		WinCondition: &world.Event{Event: world.EventEnemyKilled, EnemyName: "goblin"},
+		Scoring: &world.Scoring{
+			BasePoints:  100,
+			TurnPenalty: 10,
+			EnemyBonus:  50,
+			SecretBonus: 25,
+			Badges: []*world.Badge{
+				{Name: "speedrunner", Description: "finish quickly", Condition: world.BadgeCondition{MaxTurns: &maxTurns}},
+				{Name: "untouchable", Description: "take no damage", Condition: world.BadgeCondition{MaxDamageTaken: &maxDamage}},
+			},
+		},
+	})
+	engine.Rng = &FakeRng{Value: 0.0}
+
+	// Observe does not count as a turn
+	if _, err := engine.Observe(ctx, ObserveFilter{}); err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if _, err := engine.Take(ctx, "sword"); err != nil {
+		t.Fatalf("Take sword failed: %v", err)
+	}
+	if _, err := engine.Uncover(ctx, "rug"); err != nil {
+		t.Fatalf("Uncover rug failed: %v", err)
+	}
+	if engine.Stats.SecretsFound != 1 {
+		t.Errorf("Expected 1 secret found after uncovering, got %d", engine.Stats.SecretsFound)
+	}
+
+	// Taking the secret again must not double count it
+	if _, err := engine.Take(ctx, "gem"); err != nil {
+		t.Fatalf("Take gem failed: %v", err)
+	}
+	if engine.Stats.SecretsFound != 1 {
+		t.Errorf("Expected secrets found to stay at 1, got %d", engine.Stats.SecretsFound)
+	}
+
+	// Score before completion is provisional with no badges
+	scoreResult, err := engine.Score()
+	if err != nil {
+		t.Fatalf("Score failed: %v", err)
+	}
+	if scoreResult.Result.Final {
+		t.Error("Expected provisional score before level completion")
+	}
+	if len(scoreResult.Result.Badges) != 0 {
+		t.Errorf("Expected no badges before completion, got %d", len(scoreResult.Result.Badges))
+	}
+
+	battleResult, err := engine.Battle(ctx, "sword")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	score := battleResult.EngineStateInfo.Score
+	if score == nil {
+		t.Fatal("Expected score in level complete engine state")
+	}
+	if !score.Final {
+		t.Error("Expected final score after level completion")
+	}
+	if score.Stats.Turns != 4 {
+		t.Errorf("Expected 4 turns, got %d", score.Stats.Turns)
+	}
+	if score.Stats.EnemiesDefeated != 1 {
+		t.Errorf("Expected 1 enemy defeated, got %d", score.Stats.EnemiesDefeated)
+	}
+	// 100 - 4*10 + 1*50 + 1*25
+	if score.Score != 135 {
+		t.Errorf("Expected score 135, got %d", score.Score)
+	}
+	if len(score.Badges) != 2 {
+		t.Errorf("Expected 2 badges, got %d", len(score.Badges))
+	}
+}
+
+func TestScore_DamageTakenAndDefaults(t *testing.T) {
+	room := &world.Room{
+		BaseEntity: world.BaseEntity{
+			Name:        "room",
+			Description: "a test room",
+		},
+	}
+	zombie := &world.Enemy{
+		BaseEntity: world.BaseEntity{
+			Name:        "zombie",
+			Description: "a zombie",
+		},
+		HP: 1,
+	}
+	engine := NewEngine(&world.Level{
+		Floors: []*world.Floor{{
+			Name:  "test_floor",
+			Rooms: []*world.Room{room},
+		}},
+		Enemies: []*world.Enemy{zombie},
+	})
+	engine.Rng = &FakeRng{Value: 0.99}
+	engine.Mode = Combat
+	engine.FightingEnemy = zombie
+
+	if _, err := engine.Battle(ctx, "fists"); err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if engine.Stats.DamageTaken != 1 {
+		t.Errorf("Expected 1 damage taken, got %d", engine.Stats.DamageTaken)
+	}
+
+	scoreResult, err := engine.Score()
+	if err != nil {
+		t.Fatalf("Score failed: %v", err)
+	}
+	defaults := world.DefaultScoring()
+	expected := defaults.BasePoints - defaults.TurnPenalty - defaults.DamagePenalty
+	if scoreResult.Result.Score != expected {
+		t.Errorf("Expected default score %d, got %d", expected, scoreResult.Result.Score)
+	}
+}
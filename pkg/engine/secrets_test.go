@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestTake_RevealsSecretDoor(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "secret_door.json"))
+	if len(engine.CurrentRoom.Connections) != 1 || len(engine.visibleConnections(engine.CurrentRoom)) != 0 {
+		t.Fatal("Expected the passage to start hidden")
+	}
+
+	take, err := engine.Take(ctx, "candlestick")
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if notification := take.EngineStateInfo.EngineStateChangeNotification; notification == nil || *notification != EngineStateChangeSecretFound {
+		t.Errorf("Expected a secret discovered notification, got %v", notification)
+	}
+	if !engine.RevealedDoors["passage"] || engine.Stats.SecretsFound != 1 {
+		t.Errorf("Expected the passage to be recorded as a secret, got %v and %d secrets", engine.RevealedDoors, engine.Stats.SecretsFound)
+	}
+	observe, err := engine.Observe(ctx, ObserveFilter{})
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if len(observe.Result.Doors) != 1 || observe.Result.Doors[0].Name != "passage" {
+		t.Errorf("Expected the passage to be seen, got %+v", observe.Result.Doors)
+	}
+	if _, err := engine.Traverse(ctx, "north"); err != nil {
+		t.Errorf("Expected the passage to be passable, got %v", err)
+	}
+}
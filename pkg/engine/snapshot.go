@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"maps"
+	"slices"
+)
+
+// Snapshot is a deep copy of an engine's live game state.
+// A snapshot can be restored any number of times.
+type Snapshot struct {
+	state *Engine
+}
+
+type RestoreResult struct {
+	EngineStateInfo EngineStateInfo
+}
+
+// Snapshot captures the current game state.
+// Snapshots leave out what the engine remembers of its past, which restoring doesn't rewind: the
+// state versions for diffs, the turns for rewinding and the debug log.
+func (e *Engine) Snapshot() *Snapshot {
+	state := e.clone()
+	state.versions = nil
+	state.turns = nil
+	state.debugLog = nil
+	return &Snapshot{state: state}
+}
+
+// Restore replaces the current game state with a previously captured snapshot.
+// The engine keeps its own RNG, validation, turn history and language settings, and the state version keeps
+// increasing so that clients holding the pre-restore version see their state is stale. Turns
+// remembered for rewinding from after the restored state are forgotten.
+// Returns a RestoreResult with the restored engine state info.
+func (e *Engine) Restore(snapshot *Snapshot) (*RestoreResult, error) {
+	e.rememberVersion()
+	restored := snapshot.state.clone()
+	restored.versions = e.versions
+	restored.turns = e.turnsBefore(restored.Stats.Turns)
+	restored.debugLog = e.debugLog
+	restored.Rng = e.Rng
+	restored.ValidationDisabled = e.ValidationDisabled
+	restored.TurnHistory = e.TurnHistory
+	restored.Language = e.Language
+	restored.StateVersion = e.StateVersion + 1
+	*e = *restored
+	return &RestoreResult{
+		EngineStateInfo: *e.getEngineStateInfo(),
+	}, nil
+}
+
+// clone returns a deep copy of the engine with all world pointers
+// remapped into the copied level.
+func (e *Engine) clone() *Engine {
+	level := e.Level.Clone()
+	c := *e
+	c.Level = level
+	c.Player = e.Player.Clone()
+	c.CurrentFloor = level.GetFloor(e.CurrentFloor.Name)
+	c.CurrentRoom = level.GetRoom(e.CurrentFloor.Name, e.CurrentRoom.Name)
+	if e.FightingEnemy != nil {
+		c.FightingEnemy = level.GetEnemy(e.FightingEnemy.Name)
+	}
+	c.MinimapData = make(map[string]*MinimapDoorInfo, len(e.MinimapData))
+	for doorName, info := range e.MinimapData {
+		infoCopy := *info
+		if info.Locked != nil {
+			locked := *info.Locked
+			infoCopy.Locked = &locked
+		}
+		c.MinimapData[doorName] = &infoCopy
+	}
+	c.Telemetry = e.Telemetry.clone()
+	c.Fight = e.Fight.clone()
+	c.FoundSecrets = maps.Clone(e.FoundSecrets)
+	c.TakenItems = maps.Clone(e.TakenItems)
+	c.LearnedCodes = maps.Clone(e.LearnedCodes)
+	c.KnownRecipes = maps.Clone(e.KnownRecipes)
+	c.RevealedDoors = maps.Clone(e.RevealedDoors)
+	c.EnemySightings = maps.Clone(e.EnemySightings)
+	c.ObjectiveStatuses = maps.Clone(e.ObjectiveStatuses)
+	c.RoomNotes = maps.Clone(e.RoomNotes)
+	c.DoorNotes = maps.Clone(e.DoorNotes)
+	c.versions = slices.Clone(e.versions)
+	c.turns = slices.Clone(e.turns)
+	c.debugLog = slices.Clone(e.debugLog)
+	c.Players = e.clonePlayers(level)
+	if state := c.getPlayerState(c.ActivePlayer); state != nil {
+		c.Player = state.Player
+	}
+	// The copy starts with nothing left over from an action in progress
+	c.soundCues = nil
+	c.notifications = nil
+	c.ctx = nil
+	c.interruption = nil
+	c.eventChain = nil
+	return &c
+}
@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"adventure-engine/pkg/loader"
+	"testing"
+)
+
+func TestSnapshot_RestoreBranches(t *testing.T) {
+	level, err := loader.LoadGameFromFile("../../internal/testdata/demo.json")
+	if err != nil {
+		t.Fatalf("Failed to load demo game: %v", err)
+	}
+	engine := NewEngine(level)
+
+	if _, err := engine.Take(ctx, "energy drink"); err != nil {
+		t.Fatalf("Take energy drink failed: %v", err)
+	}
+	snapshot := engine.Snapshot()
+
+	// Branch 1: go into the storage room and take the pipe
+	if _, err := engine.Traverse(ctx, "left"); err != nil {
+		t.Fatalf("Traverse left failed: %v", err)
+	}
+	if _, err := engine.Take(ctx, "metal pipe"); err != nil {
+		t.Fatalf("Take metal pipe failed: %v", err)
+	}
+
+	// Restore and verify we're back in the waiting room without the pipe
+	result, err := engine.Restore(snapshot)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if result.EngineStateInfo.CurrentRoom.Name != "waiting room" {
+		t.Errorf("Expected to be in waiting room after restore, got %s", result.EngineStateInfo.CurrentRoom.Name)
+	}
+	if engine.isItemInInventory("metal pipe") {
+		t.Error("Expected metal pipe not to be in inventory after restore")
+	}
+	if !engine.isItemInInventory("energy drink") {
+		t.Error("Expected energy drink to be in inventory after restore")
+	}
+	if engine.Stats.Turns != 1 {
+		t.Errorf("Expected 1 turn after restore, got %d", engine.Stats.Turns)
+	}
+
+	// Branch 2: the pipe must still be in the storage room
+	if _, err := engine.Traverse(ctx, "left"); err != nil {
+		t.Fatalf("Traverse left failed: %v", err)
+	}
+	if _, err := engine.CurrentRoom.GetItem("metal pipe"); err != nil {
+		t.Errorf("Expected metal pipe in storage room after restore: %v", err)
+	}
+
+	// The snapshot can be restored more than once
+	if _, err := engine.Restore(snapshot); err != nil {
+		t.Fatalf("Second restore failed: %v", err)
+	}
+	if engine.CurrentRoom.Name != "waiting room" {
+		t.Errorf("Expected to be in waiting room after second restore, got %s", engine.CurrentRoom.Name)
+	}
+	if engine.CurrentRoom != engine.Level.GetRoom(engine.CurrentFloor.Name, "waiting room") {
+		t.Error("Expected current room to point into the restored level")
+	}
+}
+
+func TestStateVersion(t *testing.T) {
+	level, err := loader.LoadGameFromFile("../../internal/testdata/demo.json")
+	if err != nil {
+		t.Fatalf("Failed to load demo game: %v", err)
+	}
+	engine := NewEngine(level)
+
+	take, err := engine.Take(ctx, "energy drink")
+	if err != nil {
+		t.Fatalf("Take energy drink failed: %v", err)
+	}
+	if take.EngineStateInfo.StateVersion != 1 {
+		t.Errorf("Expected state version 1 after an action, got %d", take.EngineStateInfo.StateVersion)
+	}
+	snapshot := engine.Snapshot()
+
+	if _, err := engine.Take(ctx, "no such item"); err == nil {
+		t.Fatal("Expected taking a missing item to fail")
+	}
+	if engine.StateVersion != 1 {
+		t.Errorf("Expected failed actions to keep the state version, got %d", engine.StateVersion)
+	}
+
+	if _, err := engine.Traverse(ctx, "left"); err != nil {
+		t.Fatalf("Traverse left failed: %v", err)
+	}
+	restore, err := engine.Restore(snapshot)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if restore.EngineStateInfo.StateVersion != 3 {
+		t.Errorf("Expected restoring to increase the state version to 3, got %d", restore.EngineStateInfo.StateVersion)
+	}
+}
+
+func TestNewEngine_SharedLevel(t *testing.T) {
+	level := loadTestLevel(t, "shared_level.json")
+	first := NewEngine(level)
+	second := NewEngine(level)
+
+	if _, err := first.Take(ctx, "crown"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if _, err := first.Search(ctx, "chest"); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if _, err := second.CurrentRoom.GetItem("crown"); err != nil {
+		t.Errorf("Expected the crown to still be in the other engine's vault, got %v", err)
+	}
+	if chest, err := second.CurrentRoom.GetItem("chest"); err != nil || chest.Container.Searched {
+		t.Errorf("Expected the chest to be unsearched in the other engine, got %v", err)
+	}
+	if _, err := level.Floors[0].Rooms[0].GetItem("crown"); err != nil {
+		t.Errorf("Expected the loaded level to be unchanged, got %v", err)
+	}
+}
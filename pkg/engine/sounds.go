@@ -0,0 +1,22 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+)
+
+// SoundCue is a sound played by an item or room in response to an action.
+// Sounds are authored in the level and passed through to frontends with audio.
+type SoundCue struct {
+	Sound  string
+	Source string // name of the item or room playing the sound
+	Event  world.SoundEvent
+}
+
+// playSound queues an item's or room's sound cue for an event, if it has one.
+// Queued cues are handed out with the engine state info until the next action starts, so
+// they must only be queued once the action can no longer fail.
+func (e *Engine) playSound(source string, cues world.SoundCues, event world.SoundEvent) {
+	if sound, ok := cues[event]; ok {
+		e.soundCues = append(e.soundCues, SoundCue{Sound: sound, Source: source, Event: event})
+	}
+}
@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"reflect"
+	"testing"
+)
+
+func TestSoundCues(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "sounds.json"))
+
+	observe, err := engine.Observe(ctx, ObserveFilter{})
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if observe.EngineStateInfo.Ambient != "clock_ticking" || observe.EngineStateInfo.SoundCues != nil {
+		t.Errorf("Expected the hall's ambient sound and no cues, got %q and %+v", observe.EngineStateInfo.Ambient, observe.EngineStateInfo.SoundCues)
+	}
+
+	if _, err := engine.Unlock(ctx, "4321", "safe"); err == nil {
+		t.Fatal("Expected the wrong code to fail")
+	}
+	unlock, err := engine.Unlock(ctx, "1234", "safe")
+	if err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	expected := []SoundCue{{Sound: "safe_click", Source: "safe", Event: world.SoundUnlock}}
+	if !reflect.DeepEqual(unlock.EngineStateInfo.SoundCues, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, unlock.EngineStateInfo.SoundCues)
+	}
+
+	search, err := engine.Search(ctx, "safe")
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	expected = []SoundCue{{Sound: "safe_creak", Source: "safe", Event: world.SoundSearch}}
+	if !reflect.DeepEqual(search.EngineStateInfo.SoundCues, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, search.EngineStateInfo.SoundCues)
+	}
+
+	take, err := engine.Take(ctx, "brass key")
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	expected = []SoundCue{{Sound: "keys_jingle", Source: "brass key", Event: world.SoundTake}}
+	if !reflect.DeepEqual(take.EngineStateInfo.SoundCues, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, take.EngineStateInfo.SoundCues)
+	}
+
+	traverse, err := engine.Traverse(ctx, "ahead")
+	if err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	expected = []SoundCue{{Sound: "door_creak", Source: "porch", Event: world.SoundEnter}}
+	if !reflect.DeepEqual(traverse.EngineStateInfo.SoundCues, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, traverse.EngineStateInfo.SoundCues)
+	}
+	if traverse.EngineStateInfo.Ambient != "wind" {
+		t.Errorf("Expected the porch's ambient sound, got %q", traverse.EngineStateInfo.Ambient)
+	}
+
+	// Reading the engine state doesn't use up the cues, but the next action starts without them
+	for range 2 {
+		score, err := engine.Score()
+		if err != nil {
+			t.Fatalf("Score failed: %v", err)
+		}
+		if !reflect.DeepEqual(score.EngineStateInfo.SoundCues, expected) {
+			t.Errorf("Expected %+v, got %+v", expected, score.EngineStateInfo.SoundCues)
+		}
+	}
+	inventory, err := engine.Inventory(ctx)
+	if err != nil {
+		t.Fatalf("Inventory failed: %v", err)
+	}
+	if inventory.EngineStateInfo.SoundCues != nil {
+		t.Errorf("Expected no cues, got %+v", inventory.EngineStateInfo.SoundCues)
+	}
+}
@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+	"errors"
+	"testing"
+)
+
+func TestTakeQuantity(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "stacks.json"))
+
+	take, err := engine.TakeQuantity(ctx, "bandage", 2)
+	if err != nil {
+		t.Fatalf("TakeQuantity failed: %v", err)
+	}
+	if take.Result.ItemInfo.Quantity != 2 {
+		t.Errorf("Expected to take 2 bandages, got %d", take.Result.ItemInfo.Quantity)
+	}
+	if left, err := engine.CurrentRoom.GetItem("bandage"); err != nil || left.Count() != 1 {
+		t.Fatalf("Expected 1 bandage left on the shelf, got %v, %v", left, err)
+	}
+
+	// Taking the rest stacks it with the bandages already taken
+	if _, err := engine.Take(ctx, "bandage"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if _, err := engine.CurrentRoom.GetItem("bandage"); err == nil {
+		t.Error("Expected no bandages left in the room")
+	}
+	inventory, err := engine.Inventory(ctx)
+	if err != nil {
+		t.Fatalf("Inventory failed: %v", err)
+	}
+	if len(inventory.Result.Items) != 1 || inventory.Result.Items[0].Quantity != 3 {
+		t.Fatalf("Expected a stack of 3 bandages, got %+v", inventory.Result.Items)
+	}
+
+	// Healing uses up one bandage
+	engine.Player.Health = world.HealthHurt
+	if _, err := engine.Heal(ctx, "bandage"); err != nil {
+		t.Fatalf("Heal failed: %v", err)
+	}
+	if bandage, err := engine.Player.GetItem("bandage"); err != nil || bandage.Count() != 2 {
+		t.Errorf("Expected 2 bandages left after healing, got %v, %v", bandage, err)
+	}
+}
+
+func TestTakeQuantity_FromContainer(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "stacks.json"))
+	if _, err := engine.Search(ctx, "cash box"); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	if _, err := engine.TakeQuantity(ctx, "coin", 6); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("Expected ErrInvalidArgument for more coins than there are, got %v", err)
+	}
+	if _, err := engine.TakeQuantity(ctx, "coin", 2); err != nil {
+		t.Fatalf("TakeQuantity failed: %v", err)
+	}
+	box, _ := engine.CurrentRoom.GetItem("cash box")
+	if box.Container.IsEmpty() || box.Container.Contains.Count() != 3 {
+		t.Fatalf("Expected 3 coins left in the cash box, got %+v", box.Container.Contains)
+	}
+	if _, err := engine.Take(ctx, "coin"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if !box.Container.IsEmpty() {
+		t.Error("Expected the cash box to be empty")
+	}
+	if coin, err := engine.Player.GetItem("coin"); err != nil || coin.Count() != 5 {
+		t.Errorf("Expected a stack of 5 coins, got %v, %v", coin, err)
+	}
+}
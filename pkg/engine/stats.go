@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"maps"
+	"slices"
+)
+
+// Telemetry contains per-session statistics kept for analytics and end screens.
+// Unlike Stats, none of it counts towards the score.
+type Telemetry struct {
+	Actions       map[string]int // action name -> times a player took it
+	DamageDealt   int
+	EnemiesSpared int            // enemies intimidated or bribed into leaving
+	RoomTurns     map[string]int // room name -> turns that ended in the room
+	RecentActions []Action       // the last actions that took a turn, oldest first, up to maxRecentActions
+}
+
+func newTelemetry() Telemetry {
+	return Telemetry{
+		Actions:   make(map[string]int),
+		RoomTurns: make(map[string]int),
+	}
+}
+
+func (t Telemetry) clone() Telemetry {
+	t.Actions = maps.Clone(t.Actions)
+	t.RoomTurns = maps.Clone(t.RoomTurns)
+	t.RecentActions = slices.Clone(t.RecentActions)
+	return t
+}
+
+// StatsSummary contains the statistics for a session. Only actions that take a turn
+// are counted, and the time spent in a room is measured in turns.
+type StatsSummary struct {
+	Stats         Stats
+	Actions       map[string]int
+	DamageDealt   int
+	EnemiesSpared int
+	RoomsVisited  int
+	ItemsTaken    int
+	RoomTurns     map[string]int
+}
+
+type StatsResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          StatsSummary
+}
+
+// Statistics returns the statistics gathered so far in the session.
+// Allowed in all modes and after the level has ended.
+func (e *Engine) Statistics() (*StatsResult, error) {
+	return &StatsResult{
+		EngineStateInfo: *e.getEngineStateInfo(),
+		Result:          *e.computeStats(),
+	}, nil
+}
+
+func (e *Engine) computeStats() *StatsSummary {
+	summary := &StatsSummary{
+		Stats:         e.Stats,
+		Actions:       maps.Clone(e.Telemetry.Actions),
+		DamageDealt:   e.Telemetry.DamageDealt,
+		EnemiesSpared: e.Telemetry.EnemiesSpared,
+		ItemsTaken:    len(e.TakenItems),
+		RoomTurns:     maps.Clone(e.Telemetry.RoomTurns),
+	}
+	for _, floor := range e.Level.Floors {
+		for _, room := range floor.Rooms {
+			if room.Visited {
+				summary.RoomsVisited++
+			}
+		}
+	}
+	return summary
+}
@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestStatistics(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "statistics.json"))
+	engine.Rng = &FakeRng{Value: 0.1}
+
+	if _, err := engine.Take(ctx, "dagger"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if _, err := engine.Search(ctx, "crate"); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if _, err := engine.Observe(ctx, ObserveFilter{}); err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	stats, err := engine.Statistics()
+	if err != nil {
+		t.Fatalf("Statistics failed: %v", err)
+	}
+	if stats.Result.Actions["take"] != 1 || stats.Result.Actions["search"] != 1 || len(stats.Result.Actions) != 2 {
+		t.Errorf("Expected a take and a search to be counted, got %v", stats.Result.Actions)
+	}
+	if stats.EngineStateInfo.Stats != nil {
+		t.Error("Expected no stats in the engine state before the level is complete")
+	}
+
+	if _, err := engine.Traverse(ctx, "east"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	battle, err := engine.Battle(ctx, "dagger")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	summary := battle.EngineStateInfo.Stats
+	if summary == nil {
+		t.Fatal("Expected the stats summary once the level is complete")
+	}
+	if summary.Stats.Turns != 4 || summary.DamageDealt != 1 || summary.ItemsTaken != 1 || summary.RoomsVisited != 2 {
+		t.Errorf("Expected 4 turns, 1 damage dealt, 1 item taken and 2 rooms visited, got %+v", summary)
+	}
+	if summary.RoomTurns["hall"] != 2 || summary.RoomTurns["cellar"] != 2 {
+		t.Errorf("Expected 2 turns in each room, got %v", summary.RoomTurns)
+	}
+}
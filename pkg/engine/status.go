@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"context"
+
+	"adventure-engine/pkg/world"
+)
+
+// Status effects, conditions the player is under that affect what they can do.
+const (
+	StatusInCombat      = "in_combat"      // fighting an enemy, and only able to take combat actions
+	StatusHoldingBreath = "holding_breath" // in an airless room
+	StatusOutOfBreath   = "out_of_breath"  // in an airless room, and drowns at the end of the next action there
+	StatusPoisoned      = "poisoned"       // hurt every few actions until healed
+)
+
+// healthDescriptions describe each health state for players asking how they are doing.
+var healthDescriptions = map[world.HealthState]string{
+	world.HealthFine: "unhurt",
+	world.HealthHurt: "hurt, and can take two more hits",
+	world.HealthCrit: "badly hurt, and the next hit will kill",
+	world.HealthDead: "dead",
+}
+
+// statusResultInternal is how the player is doing, as a whole.
+// The engine has no equipping, weight or stamina: the weapon at hand is the one the player
+// would fight with, carrying counts the items they have, and their breath is how long they
+// can keep going in airless rooms.
+type statusResultInternal struct {
+	Health            world.HealthState
+	HealthDescription string
+	Effects           []string // the status effects the player is under, in the order of the constants above
+	Weapon            string   // the weapon at hand, fistsWeaponName if the player has none
+	Armor             []string // the armor the player wears
+	Carrying          int      // the items the player carries, counting each in a stack
+	Breath            BreathInfo
+}
+
+type StatusResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          statusResultInternal
+}
+
+// Status tells the player how they are doing, without taking a turn: their health, the status
+// effects they are under, their weapon at hand and armor, how much they carry and their breath.
+// Returns a StatusResult and engine state info.
+func (e *Engine) Status(ctx context.Context) (*StatusResult, error) {
+	return act(e, ctx, "status", func() (*StatusResult, error) {
+		if !e.ValidationDisabled {
+			if err := e.validateEngineState(); err != nil {
+				return nil, err
+			}
+		}
+		return &StatusResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *e.statusInternal(),
+		}, nil
+	})
+}
+
+func (e *Engine) statusInternal() *statusResultInternal {
+	result := &statusResultInternal{
+		Health:            e.Player.Health,
+		HealthDescription: healthDescriptions[e.Player.Health],
+		Weapon:            fistsWeaponName,
+	}
+	if e.Mode == Combat {
+		result.Effects = append(result.Effects, StatusInCombat)
+	}
+	result.Breath = BreathInfo{Left: e.Level.GetBreath(), Capacity: e.Level.GetBreath()}
+	if breath := e.breathInfo(); breath != nil {
+		result.Breath = *breath
+		result.Effects = append(result.Effects, StatusHoldingBreath)
+		if breath.Left == 0 {
+			result.Effects = append(result.Effects, StatusOutOfBreath)
+		}
+	}
+	if e.Player.PoisonedBy != "" {
+		result.Effects = append(result.Effects, StatusPoisoned)
+	}
+	if weapon := e.weaponAtHand(); weapon != nil {
+		result.Weapon = weapon.Name
+	}
+	for _, armor := range e.Player.WornArmor() {
+		result.Armor = append(result.Armor, armor.Name)
+	}
+	for _, item := range e.Player.Inventory {
+		result.Carrying += item.Count()
+	}
+	return result
+}
+
+// weaponAtHand returns the weapon the player would fight with: the one they last fought with,
+// if they can still fight with it, and otherwise the most damaging they can. Returns nil if
+// they can fight with nothing but their fists.
+func (e *Engine) weaponAtHand() *world.Item {
+	if e.Fight != nil && len(e.Fight.Rounds) > 0 {
+		last := e.Fight.Rounds[len(e.Fight.Rounds)-1].Weapon
+		if weapon, err := e.Player.GetItem(last); err == nil && e.canFightWith(weapon) {
+			return weapon
+		}
+	}
+	var best *world.Item
+	for _, item := range e.Player.Inventory {
+		if e.canFightWith(item) && (best == nil || item.Weapon.Damage > best.Weapon.Damage) {
+			best = item
+		}
+	}
+	return best
+}
+
+// canFightWith returns true if an item is a weapon that isn't broken and, if it uses ammo, is
+// loaded.
+func (e *Engine) canFightWith(item *world.Item) bool {
+	if !item.IsWeapon() || item.IsBroken() {
+		return false
+	}
+	return !item.Weapon.UsesAmmo() || e.Player.Ammo[item.AmmoType()] > 0
+}
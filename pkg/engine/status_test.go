@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+
+	"adventure-engine/pkg/world"
+)
+
+func TestStatus(t *testing.T) {
+	engine := loadArmoryLevel(t)
+	result, err := engine.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	status := result.Result
+	if status.Health != world.HealthFine || status.HealthDescription != "unhurt" {
+		t.Errorf("Expected an unhurt player, got %s: %s", status.Health, status.HealthDescription)
+	}
+	if !slices.Equal(status.Effects, []string{StatusInCombat}) {
+		t.Errorf("Expected the player to be in combat, got %v", status.Effects)
+	}
+	if status.Weapon != "axe" || status.Carrying != 3 {
+		t.Errorf("Expected the axe at hand and 3 items carried, got the %s and %d", status.Weapon, status.Carrying)
+	}
+	if engine.Stats.Turns != 3 {
+		t.Errorf("Expected asking for the status to take no turn, got %d turns", engine.Stats.Turns)
+	}
+
+	// The weapon last fought with stays at hand
+	engine.Rng = &FakeRng{Value: 0.1}
+	if _, err := engine.Battle(ctx, "club"); err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if result, _ := engine.Status(ctx); result.Result.Weapon != "club" {
+		t.Errorf("Expected the club at hand, got the %s", result.Result.Weapon)
+	}
+}
+
+func TestStatus_HoldingBreath(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "flooded.json"))
+	if _, err := engine.Traverse(ctx, "down"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	result, err := engine.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if !slices.Equal(result.Result.Effects, []string{StatusHoldingBreath}) || result.Result.Breath != (BreathInfo{Left: 1, Capacity: 2}) {
+		t.Errorf("Expected the player to be holding their breath with 1 action left, got %v and %+v", result.Result.Effects, result.Result.Breath)
+	}
+	if result.Result.Weapon != fistsWeaponName {
+		t.Errorf("Expected nothing but fists at hand, got the %s", result.Result.Weapon)
+	}
+}
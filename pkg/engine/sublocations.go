@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"slices"
+	"strings"
+
+	"adventure-engine/pkg/world"
+)
+
+// subLocationWords are the words that can tie an item's name to the sub-location it is at, as
+// in "key on the desk" or "key from the desk". Any of them will do, whatever the sub-location's
+// own preposition.
+var subLocationWords = []string{"on", "in", "under", "behind", "beside", "by", "near", "above", "below", "against", "at", "from", "off"}
+
+// itemsBySubLocation returns a room's items grouped by where they are: items at no
+// sub-location first, then those at each sub-location in the order the room lists them.
+// Items keep their order within each group.
+func itemsBySubLocation(room *world.Room) []*world.Item {
+	order := func(item *world.Item) int {
+		return slices.IndexFunc(room.SubLocations, func(subLocation world.SubLocation) bool {
+			return subLocation.Name == item.SubLocation
+		})
+	}
+	items := slices.Clone(room.Items)
+	slices.SortStableFunc(items, func(a, b *world.Item) int {
+		return order(a) - order(b)
+	})
+	return items
+}
+
+// splitSubLocation splits a name such as "key on the desk" into the item part and the
+// sub-location of the current room it names. Returns false if the name doesn't end with a
+// sub-location of the current room.
+func (e *Engine) splitSubLocation(name string) (string, string, bool) {
+	words := strings.Fields(strings.ToLower(name))
+	for _, subLocation := range e.CurrentRoom.SubLocations {
+		subWords := strings.Fields(strings.ToLower(subLocation.Name))
+		end := len(words) - len(subWords)
+		if end < 2 || !slices.Equal(words[end:], subWords) {
+			continue
+		}
+		if words[end-1] == "the" {
+			end--
+		}
+		if end >= 2 && slices.Contains(subLocationWords, words[end-1]) {
+			return strings.Join(words[:end-1], " "), subLocation.Name, true
+		}
+	}
+	return "", "", false
+}
+
+// subLocationEntities returns the items the player can refer to at a sub-location of the
+// current room: the items there and the contents of their searched containers.
+func (e *Engine) subLocationEntities(subLocation string) []namedEntity {
+	var entities []namedEntity
+	for _, item := range e.CurrentRoom.Items {
+		if item.SubLocation == subLocation {
+			entities = append(entities, roomItemEntities(item)...)
+		}
+	}
+	return entities
+}
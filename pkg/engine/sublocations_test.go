@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"adventure-engine/pkg/world"
+)
+
+func TestObserve_GroupsItemsBySubLocation(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "study.json"))
+	result, err := engine.Observe(ctx, ObserveFilter{})
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	var names []string
+	for _, item := range result.Result.VisibleItems {
+		names = append(names, item.Name)
+	}
+	if len(names) != 3 || names[0] != "lamp" || names[1] != "brass key" || names[2] != "iron key" {
+		t.Errorf("Expected the lamp, then the key on the desk, then the key under the window, got %v", names)
+	}
+	brassKey := result.Result.VisibleItems[1]
+	if brassKey.SubLocation != (world.SubLocation{Name: "desk", Preposition: "on"}) || brassKey.Location != "on the desk" {
+		t.Errorf("Expected the brass key to be on the desk, got %+v", brassKey)
+	}
+}
+
+func TestTake_BySubLocation(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "study.json"))
+	if _, err := engine.Take(ctx, "key"); !errors.Is(err, ErrAmbiguousName) {
+		t.Errorf("Expected the key to be ambiguous, got %v", err)
+	}
+	result, err := engine.Take(ctx, "key by the window")
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if result.Result.ItemInfo.Name != "iron key" {
+		t.Errorf("Expected to take the iron key under the window, got the %s", result.Result.ItemInfo.Name)
+	}
+	if item, _ := engine.Player.GetItem("iron key"); item.SubLocation != "" {
+		t.Errorf("Expected a taken item to leave its sub-location, got %s", item.SubLocation)
+	}
+	if _, err := engine.Take(ctx, "lamp from the desk"); err == nil {
+		t.Error("Expected nothing called lamp on the desk")
+	}
+}
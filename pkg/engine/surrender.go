@@ -0,0 +1,105 @@
+package engine
+
+import (
+	"context"
+
+	"adventure-engine/pkg/world"
+)
+
+type InterrogateResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          interrogateResultInternal
+}
+
+// interrogateResultInternal is the result of questioning an enemy that surrendered.
+type interrogateResultInternal struct {
+	EnemyName string
+	Answer    string // what the enemy tells the player, empty if it has nothing to say
+}
+
+// surrender makes an enemy give up. It stays where the fight left it, to be searched and
+// questioned there.
+func (e *Engine) surrender(enemy *world.Enemy) {
+	enemy.Surrendered = true
+	enemy.Room = e.CurrentRoom.Name
+}
+
+// surrenderedEnemies returns the enemies that surrendered in the current room.
+func (e *Engine) surrenderedEnemies() []*world.Enemy {
+	var enemies []*world.Enemy
+	for _, enemy := range e.Level.Enemies {
+		if enemy.State() == world.EnemySurrendered && enemy.Room == e.CurrentRoom.Name {
+			enemies = append(enemies, enemy)
+		}
+	}
+	return enemies
+}
+
+// surrenderedEnemy returns the enemy with a name that surrendered in the current room, if any.
+func (e *Engine) surrenderedEnemy(name string) *world.Enemy {
+	for _, enemy := range e.surrenderedEnemies() {
+		if enemy.Name == name {
+			return enemy
+		}
+	}
+	return nil
+}
+
+// enemyEntities returns the enemies that surrendered in the current room.
+func (e *Engine) enemyEntities() []namedEntity {
+	var entities []namedEntity
+	for _, enemy := range e.surrenderedEnemies() {
+		entities = append(entities, namedEntity{name: enemy.Name})
+	}
+	return entities
+}
+
+// searchEnemy searches a surrendered enemy, which drops what it carries into the room.
+func (e *Engine) searchEnemy(enemy *world.Enemy) *searchResultInternal {
+	result := &searchResultInternal{ContainerName: enemy.Name}
+	if item := enemy.Search(); item != nil {
+		e.CurrentRoom.Items = append(e.CurrentRoom.Items, item)
+		e.recordSecretFound(item)
+		itemInfo := e.createItemInfo(item)
+		result.ContainedItemInfo = &itemInfo
+	}
+	return result
+}
+
+// Interrogate questions an enemy that surrendered in the current room. Codes it gives away
+// are learned as if the player had read them.
+// Returns an InterrogateResult and engine state info.
+func (e *Engine) Interrogate(ctx context.Context, enemyName string) (*InterrogateResult, error) {
+	return act(e, ctx, "interrogate", func() (*InterrogateResult, error) {
+		action := Action{Name: "interrogate", Args: []string{enemyName}}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		interrogateResult, err := e.interrogateInternal(enemyName)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		return &InterrogateResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *interrogateResult,
+		}, nil
+	})
+}
+
+func (e *Engine) interrogateInternal(enemyName string) (*interrogateResultInternal, error) {
+	enemyName, err := resolveName(enemyName, e.enemyEntities())
+	if err != nil {
+		return nil, err
+	}
+	enemy := e.surrenderedEnemy(enemyName)
+	if enemy == nil {
+		return nil, world.Errorf(ErrNotFound, "there is no one here called %s to question", enemyName)
+	}
+	answer := e.localize(enemy.Interrogation)
+	e.learnCodes(answer)
+	return &interrogateResultInternal{
+		EnemyName: enemy.Name,
+		Answer:    answer,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"adventure-engine/pkg/loader"
+	"adventure-engine/pkg/world"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func loadCampLevel(t *testing.T) *Engine {
+	t.Helper()
+	engine := NewEngine(loadTestLevel(t, "camp.json"))
+	engine.Rng = &FakeRng{Value: 0.1}
+	if _, err := engine.Take(ctx, "lantern"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	return engine
+}
+
+func TestBattle_Surrender(t *testing.T) {
+	engine := loadCampLevel(t)
+	bandit := engine.Level.GetEnemy("bandit")
+
+	battle, err := engine.Battle(ctx, "fists")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if battle.Result.Surrendered || bandit.State() != world.EnemyHostile {
+		t.Fatalf("Expected the bandit to fight on at 2 hp, got %+v", battle.Result)
+	}
+
+	battle, err = engine.Battle(ctx, "fists")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if !battle.Result.Surrendered || !battle.Result.EnemyAlive || bandit.State() != world.EnemySurrendered {
+		t.Errorf("Expected the bandit to surrender at its morale, got %+v", battle.Result)
+	}
+	if engine.Mode != Investigation || !slices.Contains(battle.EngineStateInfo.Notifications, EngineStateChangeExitCombat) {
+		t.Errorf("Expected the surrender to end the fight, got mode %s", engine.Mode)
+	}
+	if engine.Stats.EnemiesDefeated != 1 || bandit.Room != "camp" {
+		t.Errorf("Expected the bandit to count as defeated and stay in the camp, got %d defeated and room %q", engine.Stats.EnemiesDefeated, bandit.Room)
+	}
+	if !slices.Contains(battle.EngineStateInfo.AllowedActions, "interrogate") {
+		t.Errorf("Expected to be able to question the bandit, got %v", battle.EngineStateInfo.AllowedActions)
+	}
+
+	// The bandit's trigger no longer sets it on the player
+	engine.runEffect(&world.Effect{EffectType: world.EffectEnterCombat, EnemyName: "bandit"})
+	if engine.Mode != Investigation {
+		t.Error("Expected a surrendered bandit not to attack again")
+	}
+}
+
+func TestSearchAndInterrogate_SurrenderedEnemy(t *testing.T) {
+	engine := loadCampLevel(t)
+	if _, err := engine.Interrogate(ctx, "bandit"); !errors.Is(err, ErrWrongMode) {
+		t.Errorf("Expected questioning mid-fight to fail, got %v", err)
+	}
+	for engine.Mode == Combat {
+		if _, err := engine.Battle(ctx, "fists"); err != nil {
+			t.Fatalf("Battle failed: %v", err)
+		}
+	}
+
+	search, err := engine.Search(ctx, "the bandit")
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if search.Result.ContainedItemInfo == nil || search.Result.ContainedItemInfo.Name != "silver ring" {
+		t.Fatalf("Expected to find the silver ring on the bandit, got %+v", search.Result)
+	}
+	if _, err := engine.Take(ctx, "silver ring"); err != nil {
+		t.Errorf("Expected to take the ring the bandit dropped, got %v", err)
+	}
+	if search, err := engine.Search(ctx, "bandit"); err != nil || search.Result.ContainedItemInfo != nil {
+		t.Errorf("Expected nothing more on the bandit, got %+v, %v", search, err)
+	}
+
+	interrogate, err := engine.Interrogate(ctx, "bandit")
+	if err != nil {
+		t.Fatalf("Interrogate failed: %v", err)
+	}
+	if interrogate.Result.Answer != "The vault opens with 2468." || !engine.LearnedCodes["2468"] {
+		t.Errorf("Expected the bandit to give away the vault code, got %+v", interrogate.Result)
+	}
+	if _, err := engine.Unlock(ctx, "2468", "vault door"); err != nil {
+		t.Errorf("Expected the learned code to open the vault, got %v", err)
+	}
+	if _, err := engine.Interrogate(ctx, "troll"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected questioning someone who isn't here to fail, got %v", err)
+	}
+}
+
+func TestExportLevel_SurrenderedEnemy(t *testing.T) {
+	engine := loadCampLevel(t)
+	for engine.Mode == Combat {
+		if _, err := engine.Battle(ctx, "fists"); err != nil {
+			t.Fatalf("Battle failed: %v", err)
+		}
+	}
+	// Only the bandit gives the vault code away
+	if _, err := engine.Interrogate(ctx, "bandit"); err != nil {
+		t.Fatalf("Interrogate failed: %v", err)
+	}
+	if _, err := engine.Unlock(ctx, "2468", "vault door"); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+
+	data, err := engine.ExportLevel()
+	if err != nil {
+		t.Fatalf("ExportLevel failed: %v", err)
+	}
+	level, err := loader.LoadGame(data)
+	if err != nil {
+		t.Fatalf("Failed to load exported level: %v", err)
+	}
+	if len(level.Enemies) != 0 {
+		t.Errorf("Expected the surrendered bandit to be left out, got %d enemies", len(level.Enemies))
+	}
+	if _, err := level.GetRoom(level.Floors[0].Name, "camp").GetItem("silver ring"); err != nil {
+		t.Errorf("Expected the bandit's ring to be left in the camp: %v", err)
+	}
+}
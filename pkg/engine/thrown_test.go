@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBattle_Thrown(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "thrown.json"))
+	engine.Rng = &FakeRng{Value: 0.1}
+
+	for _, name := range []string{"molotov", "grenade"} {
+		if _, err := engine.Take(ctx, name); err != nil {
+			t.Fatalf("Take %s failed: %v", name, err)
+		}
+	}
+	if engine.Mode != Combat {
+		t.Fatalf("Expected taking the grenades to set the ghoul on the player")
+	}
+	weapons := []WeaponInfo{{Name: "fists"}, {Name: "molotov", Thrown: 1}, {Name: "grenade", Thrown: 2}}
+	if state := engine.getEngineStateInfo(); !slices.Equal(state.Weapons, weapons) {
+		t.Errorf("Expected to fight with fists or throw what is left, got %v", state.Weapons)
+	}
+
+	// The blast also kills the rat lurking in the cellar, but the fight with the ghoul goes on
+	battle, err := engine.Battle(ctx, "grenade")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if !battle.Result.Thrown || !slices.Equal(battle.Result.AlsoHit, []string{"rat"}) || !slices.Equal(battle.Result.AlsoKilled, []string{"rat"}) {
+		t.Errorf("Expected the grenade to be thrown and kill the rat, got %+v", battle.Result)
+	}
+	if engine.Mode != Combat || engine.FightingEnemy == nil || engine.FightingEnemy.HP != 2 {
+		t.Fatalf("Expected to still be fighting the ghoul with 2 HP, got mode %v and enemy %+v", engine.Mode, engine.FightingEnemy)
+	}
+	if grenade, err := engine.Player.GetItem("grenade"); err != nil || grenade.Count() != 1 {
+		t.Errorf("Expected one grenade left, got %v", err)
+	}
+
+	battle, err = engine.Battle(ctx, "molotov")
+	if err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if !battle.Result.StartedFire || !engine.CurrentRoom.Burning {
+		t.Errorf("Expected the molotov to set the cellar on fire, got %+v", battle.Result)
+	}
+	if battle.Result.AlsoHit != nil {
+		t.Errorf("Expected the molotov to only hit the ghoul, got %v", battle.Result.AlsoHit)
+	}
+	if _, err := engine.Battle(ctx, "molotov"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the molotov to be used up, got %v", err)
+	}
+
+	if _, err := engine.Battle(ctx, "grenade"); err != nil {
+		t.Fatalf("Battle failed: %v", err)
+	}
+	if engine.Mode != Investigation || engine.Stats.EnemiesDefeated != 2 || len(engine.Player.Inventory) != 0 {
+		t.Fatalf("Expected both enemies dead and nothing left to throw, got mode %v, %d defeated and %v", engine.Mode, engine.Stats.EnemiesDefeated, engine.Player.Inventory)
+	}
+
+	// The rat is dead, so taking the idol no longer sets it on the player
+	if _, err := engine.Take(ctx, "idol"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if engine.Mode != Investigation {
+		t.Errorf("Expected the dead rat not to attack")
+	}
+	observe, err := engine.Observe(ctx, ObserveFilter{})
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if !observe.Result.Burning {
+		t.Errorf("Expected the cellar to still be burning")
+	}
+}
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"adventure-engine/pkg/world"
+)
+
+// poisonTurns is how many actions a poisoned player ends between the hurts the poison does.
+const poisonTurns = 3
+
+// TrapInfo describes a trap an item sprang on the player.
+type TrapInfo struct {
+	ItemName  string
+	Narrative string
+	Effects   []EffectInfo      // what the trap did
+	Health    world.HealthState // the player's health once it had
+}
+
+// springTrap springs an item's trap, if it has one that springs on the action, TrapOnTake or
+// TrapOnUse, and has not sprung yet, running its effects.
+// Returns the trap sprung, or nil if none was.
+func (e *Engine) springTrap(item *world.Item, on string) *TrapInfo {
+	trap := item.Trap
+	if trap == nil || trap.Sprung || trap.On != on {
+		return nil
+	}
+	trap.Sprung = true
+	info := &TrapInfo{
+		ItemName:  item.Name,
+		Narrative: e.localize(trap.Narrative),
+	}
+	e.learnCodes(trap.Narrative)
+	for i := range trap.Effects {
+		effect := &trap.Effects[i]
+		e.notify(e.runEffect(effect))
+		info.Effects = append(info.Effects, EffectInfo{
+			Effect: string(effect.EffectType),
+			Target: effect.TargetName + effect.EnemyName,
+		})
+	}
+	info.Health = e.Player.Health
+	return info
+}
+
+// hurtPlayer takes a step of health from the player for a trapped item that isn't an enemy's
+// hit, killing them if it was their last.
+func (e *Engine) hurtPlayer(itemName string) {
+	if !e.Player.IsAlive() {
+		return
+	}
+	e.Player.InflictDamage()
+	e.Stats.DamageTaken++
+	if !e.Player.IsAlive() {
+		e.publish(&world.Event{Event: world.EventPlayerKilled, ItemName: itemName})
+	}
+}
+
+// poison poisons the player with a trapped item, unless they already are.
+func (e *Engine) poison(itemName string) {
+	if e.Player.PoisonedBy != "" {
+		return
+	}
+	e.Player.PoisonedBy = itemName
+	e.Player.PoisonTurns = 0
+}
+
+// curePoison rids the player of poison.
+// Returns true if they were poisoned.
+func (e *Engine) curePoison() bool {
+	poisoned := e.Player.PoisonedBy != ""
+	e.Player.PoisonedBy = ""
+	e.Player.PoisonTurns = 0
+	return poisoned
+}
+
+// sufferPoison hurts a poisoned player every poisonTurns actions they end.
+func (e *Engine) sufferPoison() {
+	if e.Player.PoisonedBy == "" || !e.Player.IsAlive() || e.LevelCompletionState != LevelCompletionStateInProgress {
+		return
+	}
+	e.Player.PoisonTurns++
+	if e.Player.PoisonTurns < poisonTurns {
+		return
+	}
+	e.Player.PoisonTurns = 0
+	e.hurtPlayer(e.Player.PoisonedBy)
+}
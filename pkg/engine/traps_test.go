@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+
+	"adventure-engine/pkg/world"
+)
+
+func TestTake_SpringsTrap(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "traps.json"))
+	take, err := engine.Take(ctx, "idol")
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	trap := take.Result.Trap
+	if trap == nil || trap.ItemName != "idol" || trap.Narrative != "darts fly from the walls" || trap.Health != world.HealthHurt {
+		t.Fatalf("Expected the idol's trap to hurt the player, got %+v", trap)
+	}
+	if engine.Player.Health != world.HealthHurt || engine.Stats.DamageTaken != 1 {
+		t.Errorf("Expected the trap to count as damage taken, got health %s and %d damage", engine.Player.Health, engine.Stats.DamageTaken)
+	}
+	if _, err := engine.Player.GetItem("idol"); err != nil {
+		t.Errorf("Expected the idol to be taken all the same: %v", err)
+	}
+
+	take, err = engine.Take(ctx, "chalice")
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if engine.Mode != Combat || engine.FightingEnemy == nil || engine.FightingEnemy.Name != "mummy" {
+		t.Errorf("Expected the chalice's trap to set the mummy on the player, got mode %v", engine.Mode)
+	}
+	if take.EngineStateInfo.EngineStateChangeNotification == nil || *take.EngineStateInfo.EngineStateChangeNotification != EngineStateChangeEnterCombat {
+		t.Errorf("Expected an enter combat notification, got %v", take.EngineStateInfo.EngineStateChangeNotification)
+	}
+}
+
+func TestTrap_SpringsOnce(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "traps.json"))
+	amulet, err := engine.CurrentRoom.GetItem("amulet")
+	if err != nil {
+		t.Fatalf("GetItem failed: %v", err)
+	}
+	take, err := engine.Take(ctx, "amulet")
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if take.Result.Trap != nil {
+		t.Errorf("Expected a trap on use not to spring on take, got %+v", take.Result.Trap)
+	}
+	use, err := engine.Use(ctx, "amulet", "altar")
+	if err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if use.Result.Trap == nil || engine.Player.Health != world.HealthHurt || !amulet.Trap.Sprung {
+		t.Errorf("Expected the amulet's trap to spring on use, got %+v", use.Result.Trap)
+	}
+	if trap := engine.springTrap(amulet, world.TrapOnUse); trap != nil || engine.Player.Health != world.HealthHurt {
+		t.Errorf("Expected a sprung trap not to spring again, got %+v", trap)
+	}
+}
+
+func TestTrap_Poison(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "traps.json"))
+	if _, err := engine.Take(ctx, "salve"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if _, err := engine.Take(ctx, "jewel box"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if engine.Player.PoisonedBy != "jewel box" || !slices.Contains(engine.statusInternal().Effects, StatusPoisoned) {
+		t.Fatalf("Expected the jewel box to poison the player, got %q", engine.Player.PoisonedBy)
+	}
+	if engine.Player.Health != world.HealthFine {
+		t.Errorf("Expected poison not to hurt at once, got %s", engine.Player.Health)
+	}
+
+	if _, err := engine.Wait(ctx, poisonTurns); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if engine.Player.Health != world.HealthHurt {
+		t.Errorf("Expected poison to hurt the player every %d turns, got %s", poisonTurns, engine.Player.Health)
+	}
+
+	heal, err := engine.Heal(ctx, "salve")
+	if err != nil {
+		t.Fatalf("Heal failed: %v", err)
+	}
+	if !heal.Result.Cured || engine.Player.PoisonedBy != "" {
+		t.Errorf("Expected healing to cure the poison, got %+v", heal.Result)
+	}
+}
+
+func TestTrap_Kills(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "traps.json"))
+	engine.Player.Health = world.HealthCrit
+	if _, err := engine.Take(ctx, "idol"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if engine.LevelCompletionState != LevelCompletionStateFailed || engine.Death == nil {
+		t.Fatalf("Expected the trap to kill the player, got %v", engine.LevelCompletionState)
+	}
+	if engine.Death.Cause != CauseTrapped || engine.Death.ItemName != "idol" || engine.Death.EnemyName != "" {
+		t.Errorf("Expected a post-mortem naming the idol, got %+v", engine.Death)
+	}
+}
@@ -0,0 +1,150 @@
+package engine
+
+import (
+	"context"
+	"strings"
+
+	"adventure-engine/pkg/world"
+)
+
+// TravelNodeInfo describes a fast travel point the player knows of.
+type TravelNodeInfo struct {
+	Name        string
+	Description string
+	Network     string
+	RoomName    string
+	FloorName   string
+	Powered     bool
+}
+
+type TravelResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          travelResultInternal
+}
+
+// travelResultInternal is the result of fast travelling between two nodes.
+type travelResultInternal struct {
+	FromNode     string
+	ToNode       string
+	EnteredRoom  observeResultInternal
+	ChangedFloor *FloorInfo // nil unless the destination is on another floor
+}
+
+// Travel jumps from the travel node in the current room to another node on its network,
+// which the player must have discovered. Both nodes must be powered.
+// Handles entering the destination room like Traverse does.
+// Returns a TravelResult and engine state info with state change notification, if applicable.
+func (e *Engine) Travel(ctx context.Context, nodeName string) (*TravelResult, error) {
+	return act(e, ctx, "travel", func() (*TravelResult, error) {
+		action := Action{Name: "travel", Args: []string{nodeName}}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		travelResult, err := e.travelInternal(nodeName)
+		if err != nil {
+			return nil, err
+		}
+		e.recordTurn(action)
+		e.publish(&world.Event{
+			Event:    world.EventRoomEntered,
+			RoomName: travelResult.EnteredRoom.RoomName,
+		})
+		return &TravelResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *travelResult,
+		}, nil
+	})
+}
+
+func (e *Engine) travelInternal(nodeName string) (*travelResultInternal, error) {
+	from := e.CurrentRoom.TravelNode
+	if from == nil {
+		return nil, world.Errorf(ErrInvalidTarget, "there is nothing to travel with here")
+	}
+	destinationFloor, destinationRoom := e.findTravelNode(nodeName)
+	if destinationRoom == nil {
+		return nil, world.Errorf(ErrNotFound, "you don't know of any %s to travel to", nodeName)
+	}
+	to := destinationRoom.TravelNode
+	if to == from {
+		return nil, world.Errorf(ErrInvalidTarget, "you are already at the %s", to.Name)
+	}
+	if to.Network != from.Network {
+		return nil, world.Errorf(ErrInvalidTarget, "the %s does not connect to the %s", from.Name, to.Name)
+	}
+	if from.Unpowered {
+		return nil, world.Errorf(ErrUnpowered, "the %s has no power", from.Name)
+	}
+	if to.Unpowered {
+		return nil, world.Errorf(ErrUnpowered, "the %s has no power at the other end", from.Name)
+	}
+
+	changedFloor := destinationFloor != e.CurrentFloor
+	e.CurrentRoom = destinationRoom
+	e.CurrentFloor = destinationFloor
+	e.updateMinimapDataForCurrenRoom()
+	e.playSound(destinationRoom.Name, destinationRoom.SoundCues, world.SoundEnter)
+
+	enteredRoomObs, err := e.observeInternal(ObserveFilter{})
+	if err != nil {
+		return nil, err
+	}
+	result := &travelResultInternal{
+		FromNode:    from.Name,
+		ToNode:      to.Name,
+		EnteredRoom: *enteredRoomObs,
+	}
+	if changedFloor {
+		result.ChangedFloor = &FloorInfo{
+			Name:        destinationFloor.Name,
+			Description: e.localize(destinationFloor.Description),
+		}
+	}
+	return result, nil
+}
+
+// findTravelNode returns the floor and room of a discovered travel node, looked up by the
+// node's name or its room's name, or nil if the player knows of no such node.
+func (e *Engine) findTravelNode(name string) (*world.Floor, *world.Room) {
+	for _, floor := range e.Level.Floors {
+		for _, room := range floor.Rooms {
+			node := room.TravelNode
+			if node == nil || !e.travelNodeDiscovered(room) {
+				continue
+			}
+			if strings.EqualFold(node.Name, name) || strings.EqualFold(room.Name, name) {
+				return floor, room
+			}
+		}
+	}
+	return nil, nil
+}
+
+// travelNodeDiscovered returns true if the player knows of the travel node in a room.
+func (e *Engine) travelNodeDiscovered(room *world.Room) bool {
+	return room.TravelNode.Discovered || room.Visited || room == e.CurrentRoom
+}
+
+// discoveredTravelNodes returns the travel nodes the player knows of, in level order.
+func (e *Engine) discoveredTravelNodes() []TravelNodeInfo {
+	var nodes []TravelNodeInfo
+	for _, floor := range e.Level.Floors {
+		for _, room := range floor.Rooms {
+			if room.TravelNode != nil && e.travelNodeDiscovered(room) {
+				nodes = append(nodes, e.createTravelNodeInfo(floor, room))
+			}
+		}
+	}
+	return nodes
+}
+
+func (e *Engine) createTravelNodeInfo(floor *world.Floor, room *world.Room) TravelNodeInfo {
+	return TravelNodeInfo{
+		Name:        room.TravelNode.Name,
+		Description: e.localize(room.TravelNode.Description),
+		Network:     room.TravelNode.Network,
+		RoomName:    room.Name,
+		FloorName:   floor.Name,
+		Powered:     !room.TravelNode.Unpowered,
+	}
+}
@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTravel(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "travel.json"))
+
+	observe, err := engine.Observe(ctx, ObserveFilter{})
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if node := observe.Result.TravelNode; node == nil || node.Name != "lobby lift" || !node.Powered {
+		t.Fatalf("Expected the lobby lift to be seen, got %+v", node)
+	}
+	if _, err := engine.Travel(ctx, "plant room"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected an unknown node to be refused, got %v", err)
+	}
+	if _, err := engine.Travel(ctx, "roof lift"); !errors.Is(err, ErrUnpowered) {
+		t.Errorf("Expected the unpowered roof lift to be refused, got %v", err)
+	}
+
+	if _, err := engine.Traverse(ctx, "east"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if _, err := engine.Travel(ctx, "roof lift"); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("Expected travel away from a node to be refused, got %v", err)
+	}
+	if _, err := engine.Take(ctx, "fuse"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if _, err := engine.Use(ctx, "fuse", "fuse box"); err != nil {
+		t.Fatalf("Use failed: %v", err)
+	}
+	if _, err := engine.Traverse(ctx, "west"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+
+	travel, err := engine.Travel(ctx, "helipad")
+	if err != nil {
+		t.Fatalf("Travel failed: %v", err)
+	}
+	if travel.Result.FromNode != "lobby lift" || travel.Result.ToNode != "roof lift" || travel.Result.EnteredRoom.RoomName != "helipad" {
+		t.Errorf("Expected to ride the lift to the helipad, got %+v", travel.Result)
+	}
+	if travel.Result.ChangedFloor == nil || travel.Result.ChangedFloor.Name != "roof" || engine.CurrentFloor.Name != "roof" {
+		t.Errorf("Expected to arrive on the roof, got %+v", travel.Result.ChangedFloor)
+	}
+
+	minimap, err := engine.Minimap(ctx)
+	if err != nil {
+		t.Fatalf("Minimap failed: %v", err)
+	}
+	if len(minimap.Result.TravelNodes) != 2 {
+		t.Errorf("Expected both lifts on the minimap, got %+v", minimap.Result.TravelNodes)
+	}
+	if _, err := engine.Travel(ctx, "lobby lift"); err != nil {
+		t.Errorf("Expected to ride back down, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package engine
+
+// VictorySummary lists what the player left undone on completing the level, for end screens
+// that invite them to play it again.
+type VictorySummary struct {
+	SecretsMissed   []string // secret items no player found
+	EnemiesAlive    []string // enemies still alive and hostile
+	RoomsUnexplored []string // rooms no player entered
+	ItemsNotFound   []string // portable items no player took, other than secrets
+}
+
+// victorySummary compares the level as it stands with what the player found in it. Names are
+// given in the order the level lists them, each once.
+func (e *Engine) victorySummary() *VictorySummary {
+	summary := &VictorySummary{}
+	listed := make(map[string]bool)
+	for _, item := range e.levelItems() {
+		if listed[item.Name] {
+			continue
+		}
+		switch {
+		case item.Secret && !e.FoundSecrets[item.Name]:
+			summary.SecretsMissed = append(summary.SecretsMissed, item.Name)
+		case !item.Secret && item.IsPortable() && !e.TakenItems[item.Name]:
+			summary.ItemsNotFound = append(summary.ItemsNotFound, item.Name)
+		default:
+			continue
+		}
+		listed[item.Name] = true
+	}
+	for _, enemy := range e.Level.Enemies {
+		if enemy.IsHostile() {
+			summary.EnemiesAlive = append(summary.EnemiesAlive, enemy.Name)
+		}
+	}
+	for _, floor := range e.Level.Floors {
+		for _, room := range floor.Rooms {
+			if !e.isExplored(room) {
+				summary.RoomsUnexplored = append(summary.RoomsUnexplored, room.Name)
+			}
+		}
+	}
+	return summary
+}
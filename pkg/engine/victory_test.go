@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestVictorySummary(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "victory.json"))
+	if _, err := engine.Take(ctx, "lamp"); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if _, err := engine.Move(ctx, "bookcase", "push"); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	traverse, err := engine.Traverse(ctx, "north")
+	if err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if engine.LevelCompletionState != LevelCompletionStateComplete {
+		t.Fatalf("Expected entering the study to win, got %s", engine.LevelCompletionState)
+	}
+
+	victory := traverse.EngineStateInfo.Victory
+	if victory == nil {
+		t.Fatal("Expected a victory summary once the level is complete")
+	}
+	// The letter was found, if not taken, and the lamp was taken
+	if !slices.Equal(victory.SecretsMissed, []string{"coin"}) {
+		t.Errorf("Expected the coin to be missed, got %v", victory.SecretsMissed)
+	}
+	if !slices.Equal(victory.ItemsNotFound, []string{"candle"}) {
+		t.Errorf("Expected the candle to be left behind, got %v", victory.ItemsNotFound)
+	}
+	if !slices.Equal(victory.EnemiesAlive, []string{"rat"}) {
+		t.Errorf("Expected the rat to be left alive, got %v", victory.EnemiesAlive)
+	}
+	if !slices.Equal(victory.RoomsUnexplored, []string{"cellar"}) {
+		t.Errorf("Expected the cellar to be unexplored, got %v", victory.RoomsUnexplored)
+	}
+}
+
+func TestVictorySummary_OnlyOnceComplete(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "moveable.json"))
+	observe, err := engine.Observe(ctx, ObserveFilter{})
+	if err != nil {
+		t.Fatalf("Observe failed: %v", err)
+	}
+	if observe.EngineStateInfo.Victory != nil {
+		t.Errorf("Expected no victory summary mid-level, got %+v", observe.EngineStateInfo.Victory)
+	}
+}
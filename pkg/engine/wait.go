@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"context"
+
+	"adventure-engine/pkg/world"
+)
+
+// maxWaitTurns is how many turns a player can wait or rest for at once.
+const maxWaitTurns = 10
+
+// restTurnsPerHeal is how many turns a player has to rest for to recover a step of health.
+const restTurnsPerHeal = 3
+
+// waitResultInternal is the result of waiting or resting.
+type waitResultInternal struct {
+	Turns     int // turns waited, fewer than asked for if something cut the wait short
+	Rested    bool
+	Health    world.HealthState
+	Healed    int    // steps of health recovered by resting
+	Encounter string // the wandering enemy that came upon the resting player, if one did
+}
+
+type WaitResult struct {
+	EngineStateInfo EngineStateInfo
+	Result          waitResultInternal
+}
+
+// Wait lets turns go by without the player doing anything, so that whatever happens each turn
+// happens: plugins act after each turn, and the player uses up breath in airless rooms. The
+// wait ends early once the player dies, the level is over, combat starts or, under round robin
+// turns, it is another player's turn.
+// Returns a WaitResult and engine state info.
+func (e *Engine) Wait(ctx context.Context, turns int) (*WaitResult, error) {
+	return e.wait(ctx, "wait", turns)
+}
+
+// Rest waits in a safe room, a room with air that isn't burning and has no hostile enemy
+// lurking in it. Every restTurnsPerHeal turns rested recover a step of health, but each turn
+// risks a wandering enemy coming upon the player, which starts a fight and ends the rest.
+// Returns a WaitResult and engine state info.
+func (e *Engine) Rest(ctx context.Context, turns int) (*WaitResult, error) {
+	return e.wait(ctx, "rest", turns)
+}
+
+func (e *Engine) wait(ctx context.Context, name string, turns int) (*WaitResult, error) {
+	return act(e, ctx, name, func() (*WaitResult, error) {
+		action := Action{Name: name}
+		if err := e.validateEngineStateForInvestigationActions(action); err != nil {
+			return nil, err
+		}
+		if turns < 1 || turns > maxWaitTurns {
+			return nil, world.Errorf(ErrInvalidArgument, "you can %s for 1 to %d turns, not %d", name, maxWaitTurns, turns)
+		}
+		rest := name == "rest"
+		if rest {
+			if err := e.checkSafeToRest(); err != nil {
+				return nil, err
+			}
+		}
+		result := &waitResultInternal{Rested: rest}
+		for result.Turns < turns && !e.interrupted() {
+			result.Turns++
+			if rest && result.Turns%restTurnsPerHeal == 0 && e.Player.Health != world.HealthFine {
+				e.Player.IncreaseHealth()
+				result.Healed++
+			}
+			e.recordTurn(action)
+			if rest && e.Player.IsAlive() {
+				if enemy := e.wanderingEnemy(); enemy != nil {
+					result.Encounter = enemy.Name
+					e.notify(e.runEffect(&world.Effect{EffectType: world.EffectEnterCombat, EnemyName: enemy.Name}))
+				}
+			}
+			if e.Mode == Combat || !e.Player.IsAlive() || e.LevelCompletionState != LevelCompletionStateInProgress || e.checkTurn() != nil {
+				break
+			}
+		}
+		result.Health = e.Player.Health
+		return &WaitResult{
+			EngineStateInfo: *e.getEngineStateInfo(),
+			Result:          *result,
+		}, nil
+	})
+}
+
+// checkSafeToRest returns an error if the current room isn't safe to rest in.
+func (e *Engine) checkSafeToRest() error {
+	switch {
+	case e.CurrentRoom.Airless:
+		return world.Errorf(ErrInvalidTarget, "you can't rest without air to breathe")
+	case e.CurrentRoom.Burning:
+		return world.Errorf(ErrInvalidTarget, "you can't rest in a burning room")
+	}
+	for _, enemy := range e.Level.Enemies {
+		if enemy.Room == e.CurrentRoom.Name && enemy.IsHostile() {
+			return world.Errorf(ErrInvalidTarget, "you can't rest with the %s about", enemy.Name)
+		}
+	}
+	return nil
+}
+
+// canRest returns true if the current room is safe to rest in.
+func (e *Engine) canRest() bool {
+	return e.checkSafeToRest() == nil
+}
+
+// wanderingEnemy rolls for whether a wandering enemy comes upon the resting player, and if one
+// does picks which. Returns nil if none does.
+func (e *Engine) wanderingEnemy() *world.Enemy {
+	var wanderers []*world.Enemy
+	for _, enemy := range e.Level.Enemies {
+		if enemy.Wanders && enemy.IsHostile() {
+			wanderers = append(wanderers, enemy)
+		}
+	}
+	if len(wanderers) == 0 || !e.chance("a wandering enemy comes upon the resting player", e.Level.GetRestEncounter()) {
+		return nil
+	}
+	return wanderers[min(int(e.Rng.Float64()*float64(len(wanderers))), len(wanderers)-1)]
+}
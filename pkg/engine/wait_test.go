@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+
+	"adventure-engine/pkg/world"
+)
+
+func TestWait_Drowning(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "flooded.json"))
+	if _, err := engine.Wait(ctx, 2); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if engine.Stats.Turns != 2 {
+		t.Errorf("Expected waiting to take 2 turns, got %d", engine.Stats.Turns)
+	}
+	if _, err := engine.Wait(ctx, maxWaitTurns+1); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("Expected waiting too long to fail, got %v", err)
+	}
+
+	if _, err := engine.Traverse(ctx, "down"); err != nil {
+		t.Fatalf("Traverse failed: %v", err)
+	}
+	if _, err := engine.Rest(ctx, 1); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("Expected resting without air to fail, got %v", err)
+	}
+	result, err := engine.Wait(ctx, 5)
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if result.Result.Turns != 2 || engine.LevelCompletionState != LevelCompletionStateFailed {
+		t.Errorf("Expected the player to drown 2 turns into the wait, waited %d turns and the level is %s", result.Result.Turns, engine.LevelCompletionState)
+	}
+}
+
+func TestRest(t *testing.T) {
+	engine := NewEngine(loadTestLevel(t, "rest.json"))
+	engine.Player.Health = world.HealthHurt
+
+	engine.Rng = &FakeRng{Value: 0.9}
+	result, err := engine.Rest(ctx, 6)
+	if err != nil {
+		t.Fatalf("Rest failed: %v", err)
+	}
+	if result.Result.Turns != 6 || result.Result.Healed != 1 || result.Result.Health != world.HealthFine {
+		t.Errorf("Expected 6 turns of rest to heal the player, got %+v", result.Result)
+	}
+
+	engine.Rng = &FakeRng{Value: 0.1}
+	result, err = engine.Rest(ctx, 6)
+	if err != nil {
+		t.Fatalf("Rest failed: %v", err)
+	}
+	if result.Result.Turns != 1 || result.Result.Encounter != "wolf" || engine.Mode != Combat {
+		t.Errorf("Expected the wolf to come upon the player on the first turn, got %+v", result.Result)
+	}
+	if _, err := engine.Rest(ctx, 1); !errors.Is(err, ErrWrongMode) {
+		t.Errorf("Expected resting in combat to fail, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// corridorLevel returns a level of rooms in a row, each door leading on to the next room.
+func corridorLevel(rooms int) json.RawMessage {
+	type connection struct {
+		Location string `json:"location"`
+		DoorName string `json:"door_name"`
+	}
+	type room struct {
+		Name        string       `json:"name"`
+		Description string       `json:"description"`
+		Connections []connection `json:"connections"`
+	}
+	type door struct {
+		Name  string `json:"name"`
+		RoomA string `json:"room_a"`
+		RoomB string `json:"room_b"`
+	}
+	level := struct {
+		Name  string `json:"name"`
+		Rooms []room `json:"rooms"`
+		Doors []door `json:"doors"`
+	}{Name: "corridor"}
+	for i := range rooms {
+		level.Rooms = append(level.Rooms, room{Name: fmt.Sprintf("room %d", i), Description: "a room"})
+	}
+	for i := range rooms - 1 {
+		name := fmt.Sprintf("door %d", i)
+		level.Doors = append(level.Doors, door{Name: name, RoomA: level.Rooms[i].Name, RoomB: level.Rooms[i+1].Name})
+		level.Rooms[i].Connections = append(level.Rooms[i].Connections, connection{Location: "ahead", DoorName: name})
+		level.Rooms[i+1].Connections = append(level.Rooms[i+1].Connections, connection{Location: "back", DoorName: name})
+	}
+	data, err := json.Marshal(level)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func BenchmarkLoadGame(b *testing.B) {
+	for _, rooms := range []int{100, 1000, 4000} {
+		data := corridorLevel(rooms)
+		b.Run(fmt.Sprintf("%d rooms", rooms), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := LoadGame(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
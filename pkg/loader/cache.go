@@ -0,0 +1,100 @@
+package loader
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+
+	"adventure-engine/pkg/world"
+)
+
+// Cache loads each distinct level document once, for servers that start many games on the
+// same levels. Documents are told apart by the SHA-256 of their bytes, and documents that roll
+// loot also by seed. The least recently used levels are dropped once the cache is full.
+//
+// Cached levels are shared by everyone loading the same document and must not be changed;
+// engines play their own copies, so they can be handed straight to engine.NewEngine.
+type Cache struct {
+	size    int
+	entries map[cacheKey]*list.Element
+	order   *list.List // of *cacheEntry, most recently used first
+	mu      sync.Mutex
+}
+
+type cacheKey struct {
+	hash [sha256.Size]byte
+	seed uint64 // 0 for documents that roll no loot
+}
+
+type cacheEntry struct {
+	key   cacheKey
+	level *world.Level
+}
+
+// NewCache returns a cache holding up to size levels.
+func NewCache(size int) *Cache {
+	return &Cache{
+		size:    max(size, 1),
+		entries: make(map[cacheKey]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Load loads a level like LoadGameWithSeed, returning the cached level if the same document
+// was loaded before. Documents that fail to load are not cached.
+func (c *Cache) Load(data json.RawMessage, seed uint64) (*world.Level, error) {
+	key := cacheKey{hash: sha256.Sum256(data)}
+	if rollsLoot(data) {
+		key.seed = seed
+	}
+
+	c.mu.Lock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		c.mu.Unlock()
+		return element.Value.(*cacheEntry).level, nil
+	}
+	c.mu.Unlock()
+
+	// Levels are loaded outside the lock, so a slow level does not hold up the others.
+	// Two loads of a new document may race; both load it and the first one is kept.
+	level, err := LoadGameWithSeed(data, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return element.Value.(*cacheEntry).level, nil
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, level: level})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+	return level, nil
+}
+
+// Size returns the number of levels the cache holds before dropping the least recently used.
+func (c *Cache) Size() int {
+	return c.size
+}
+
+// Len returns the number of levels cached.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// rollsLoot reports whether a document may roll loot, making the level it loads depend on the
+// seed. Loot is declared in loot_tables and rolled with loot_table, so a document mentioning
+// neither loads the same level whatever the seed.
+func rollsLoot(data []byte) bool {
+	return bytes.Contains(data, []byte(`"loot_table`))
+}
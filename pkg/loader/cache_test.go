@@ -0,0 +1,72 @@
+package loader
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const cacheLevel = `{
+	"name": "cache test",
+	"rooms": [{"name": "hall", "description": "a hall"}]
+}`
+
+func TestCache_Load(t *testing.T) {
+	cache := NewCache(2)
+
+	first, err := cache.Load(json.RawMessage(cacheLevel), 1)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	second, err := cache.Load(json.RawMessage(cacheLevel), 2)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if first != second {
+		t.Error("Expected a level without loot to be loaded once whatever the seed")
+	}
+
+	// Levels that roll loot are cached per seed
+	seeded, err := cache.Load(json.RawMessage(lootLevel), 42)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	again, err := cache.Load(json.RawMessage(lootLevel), 42)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if seeded != again {
+		t.Error("Expected the same seed to hit the cache")
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("Expected 2 cached levels, got %d", cache.Len())
+	}
+	other, err := cache.Load(json.RawMessage(lootLevel), 7)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if other == seeded {
+		t.Error("Expected another seed to load the level again")
+	}
+
+	// The least recently used level made room for it
+	if cache.Len() != 2 {
+		t.Errorf("Expected the cache to stay at 2 levels, got %d", cache.Len())
+	}
+	reloaded, err := cache.Load(json.RawMessage(cacheLevel), 1)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if reloaded == first {
+		t.Error("Expected the least recently used level to have been dropped")
+	}
+}
+
+func TestCache_Errors(t *testing.T) {
+	cache := NewCache(4)
+	if _, err := cache.Load(json.RawMessage(`{"name": "broken"}`), 1); err == nil {
+		t.Fatal("Expected a level without rooms to fail to load")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Expected failed loads not to be cached, got %d levels", cache.Len())
+	}
+}
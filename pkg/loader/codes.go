@@ -0,0 +1,98 @@
+package loader
+
+import (
+	"fmt"
+
+	"adventure-engine/pkg/world"
+)
+
+// validateKeypads checks that every keypad taking only learned codes has its code spelled
+// out somewhere the player can read it: an item's description or detail, a fixture's
+// completion narrative or what an enemy that surrenders tells when questioned. Without one, the lock can never be opened. It also checks that
+// lockout triggers refer to a keypad that can jam.
+func validateKeypads(level *world.Level, paths *levelPaths) Diagnostics {
+	var diagnostics Diagnostics
+	var texts []string
+	items := collectLevelItems(level)
+	for _, name := range sortedKeys(items) {
+		item := items[name]
+		texts = append(texts, item.Description, item.Detail)
+		if item.IsFixture() {
+			texts = append(texts, fixtureNarratives(item.Fixture)...)
+		}
+		if item.IsMoveable() {
+			texts = append(texts, item.Moveable.Narrative)
+		}
+	}
+	for _, enemy := range level.Enemies {
+		if enemy.Morale > 0 {
+			texts = append(texts, enemy.Interrogation)
+		}
+	}
+	revealed := func(code string) bool {
+		for _, text := range texts {
+			if world.RevealsCode(text, code) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, door := range sortedDoors(level) {
+		if door.HasCodeLock() && door.Lock.RequireLearned && !revealed(door.Lock.Code) {
+			diagnostics.addError(paths.doors[door.Name]+jsonPointer("code"),
+				fmt.Errorf("no item or narrative reveals the code of door %s, which must be learned", door.Name))
+		}
+	}
+	for _, name := range sortedKeys(items) {
+		item := items[name]
+		if item.IsContainer() && item.Container.HasCodeLock() && item.Container.Locked.RequireLearned && !revealed(item.Container.Locked.Code) {
+			diagnostics.addError(paths.items[name]+jsonPointer("code"),
+				fmt.Errorf("no item or narrative reveals the code of item %s, which must be learned", name))
+		}
+	}
+
+	for _, trigger := range level.Triggers {
+		if trigger.Event.Event == world.EventLockJammed && !canJam(level, items, trigger.Event.LockName) {
+			diagnostics.addError(paths.enemies[trigger.Effect.EnemyName]+jsonPointer("trigger", "lock_name"),
+				fmt.Errorf("trigger of enemy %s refers to %q, which is not a door or container with max_attempts", trigger.Effect.EnemyName, trigger.Event.LockName))
+		}
+	}
+	return diagnostics
+}
+
+// canJam returns true if a door or container has a keypad with a limit on wrong codes.
+func canJam(level *world.Level, items map[string]*world.Item, name string) bool {
+	if door := level.GetDoor(name); door != nil {
+		return door.HasCodeLock() && door.Lock.MaxAttempts > 0
+	}
+	item := items[name]
+	return item != nil && item.IsContainer() && item.Container.HasCodeLock() && item.Container.Locked.MaxAttempts > 0
+}
+
+// keypadOptions are the settings in door and item data that only apply to code locks.
+type keypadOptions struct {
+	requireLearned bool
+	maxAttempts    int
+	jammed         bool
+}
+
+// validate checks that keypad options are only set on something with a code lock.
+func (k keypadOptions) validate(hasKeypad bool, path string) error {
+	if k.maxAttempts < 0 {
+		return newValidationError(path+jsonPointer("max_attempts"), "max_attempts must not be negative")
+	}
+	for _, option := range []struct {
+		field string
+		set   bool
+	}{
+		{"require_learned_code", k.requireLearned},
+		{"max_attempts", k.maxAttempts > 0},
+		{"jammed", k.jammed},
+	} {
+		if option.set && !hasKeypad {
+			return newValidationError(path+jsonPointer(option.field), "%s needs a code lock", option.field)
+		}
+	}
+	return nil
+}
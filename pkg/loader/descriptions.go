@@ -0,0 +1,59 @@
+package loader
+
+import (
+	"adventure-engine/pkg/world"
+)
+
+// ConditionalDescriptionData represents a room description used while its condition holds in the JSON
+type ConditionalDescriptionData struct {
+	When        DescriptionConditionData `json:"when" schema:"required"`
+	Description string                   `json:"description" schema:"required,nonempty,localized"`
+}
+
+// DescriptionConditionData represents the state of the world a conditional description needs in the JSON
+// At least one field must be set, and all the fields that are set must hold.
+type DescriptionConditionData struct {
+	ItemTaken    string `json:"item_taken,omitempty"`
+	EnemyKilled  string `json:"enemy_killed,omitempty"`
+	DoorUnlocked string `json:"door_unlocked,omitempty"`
+	MinTurns     int    `json:"min_turns,omitempty"`
+}
+
+// createConditionalDescriptions creates a room's conditional descriptions, checking that every
+// condition refers to an item, enemy or door that exists in the level. Doors without a lock count as unlocked.
+func createConditionalDescriptions(descriptionsData []ConditionalDescriptionData, roomPath string, doorsMap map[string]*world.Door, paths *levelPaths) ([]*world.ConditionalDescription, error) {
+	var descriptions []*world.ConditionalDescription
+	for i, descriptionData := range descriptionsData {
+		path := roomPath + jsonPointer("conditional_descriptions", i)
+		when := descriptionData.When
+		if descriptionData.Description == "" {
+			return nil, newValidationError(path+jsonPointer("description"), "conditional description must not be empty")
+		}
+		if when == (DescriptionConditionData{}) {
+			return nil, newValidationError(path+jsonPointer("when"), "condition must set item_taken, enemy_killed, door_unlocked or min_turns")
+		}
+		if _, exists := paths.items[when.ItemTaken]; when.ItemTaken != "" && !exists {
+			return nil, newValidationError(path+jsonPointer("when", "item_taken"), "condition refers to unknown item %q", when.ItemTaken)
+		}
+		if _, exists := paths.enemies[when.EnemyKilled]; when.EnemyKilled != "" && !exists {
+			return nil, newValidationError(path+jsonPointer("when", "enemy_killed"), "condition refers to unknown enemy %q", when.EnemyKilled)
+		}
+		if _, exists := doorsMap[when.DoorUnlocked]; when.DoorUnlocked != "" && !exists {
+			return nil, newValidationError(path+jsonPointer("when", "door_unlocked"), "condition refers to unknown door %q", when.DoorUnlocked)
+		}
+		if when.MinTurns < 0 {
+			return nil, newValidationError(path+jsonPointer("when", "min_turns"), "min_turns must not be negative")
+		}
+
+		descriptions = append(descriptions, &world.ConditionalDescription{
+			When: world.DescriptionCondition{
+				ItemTaken:    when.ItemTaken,
+				EnemyKilled:  when.EnemyKilled,
+				DoorUnlocked: when.DoorUnlocked,
+				MinTurns:     when.MinTurns,
+			},
+			Description: descriptionData.Description,
+		})
+	}
+	return descriptions, nil
+}
@@ -0,0 +1,66 @@
+package loader
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"adventure-engine/pkg/world"
+)
+
+func TestLoadGame_ConditionalDescriptions(t *testing.T) {
+	level, err := LoadGame(json.RawMessage(`{
+		"name": "descriptions test",
+		"rooms": [
+			{"name": "hall", "description": "a hall", "connections": [],
+				"conditional_descriptions": [
+					{"when": {"item_taken": "idol", "min_turns": 3}, "description": {"en": "an empty plinth", "de": "ein leerer Sockel"}}
+				],
+				"items": [{"name": "idol", "description": "an idol", "portable": true}]}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+
+	descriptions := level.Floors[0].Rooms[0].ConditionalDescriptions
+	expected := world.DescriptionCondition{ItemTaken: "idol", MinTurns: 3}
+	if len(descriptions) != 1 || descriptions[0].When != expected || descriptions[0].Description != "an empty plinth" {
+		t.Fatalf("Unexpected conditional descriptions %+v", descriptions)
+	}
+	if level.Translations["de"]["an empty plinth"] != "ein leerer Sockel" {
+		t.Errorf("Expected the conditional description's translation, got %v", level.Translations)
+	}
+}
+
+func TestLoadGame_ConditionalDescriptionErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		when     string
+		path     string
+		contains string
+	}{
+		{"empty condition", `{}`, "/rooms/0/conditional_descriptions/0/when", "must set"},
+		{"unknown item", `{"item_taken": "crown"}`, "/rooms/0/conditional_descriptions/0/when/item_taken", "unknown item"},
+		{"unknown enemy", `{"enemy_killed": "ghoul"}`, "/rooms/0/conditional_descriptions/0/when/enemy_killed", "unknown enemy"},
+		{"unknown door", `{"door_unlocked": "gate"}`, "/rooms/0/conditional_descriptions/0/when/door_unlocked", "unknown door"},
+		{"negative turns", `{"min_turns": -1}`, "/rooms/0/conditional_descriptions/0/when/min_turns", "negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := ValidateLevel(json.RawMessage(`{
+				"name": "t",
+				"rooms": [{"name": "hall", "description": "a hall", "connections": [],
+					"conditional_descriptions": [{"when": ` + tt.when + `, "description": "a changed hall"}]}]
+			}`))
+			errors := diagnostics.Errors()
+			if len(errors) == 0 {
+				t.Fatal("Expected an error")
+			}
+			if errors[0].Path != tt.path || !strings.Contains(errors[0].Message, tt.contains) {
+				t.Errorf("Expected an error at %s containing %q, got %s: %s", tt.path, tt.contains, errors[0].Path, errors[0].Message)
+			}
+		})
+	}
+}
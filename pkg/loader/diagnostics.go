@@ -0,0 +1,123 @@
+package loader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Severity indicates whether a diagnostic prevents a level from loading.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a single problem found while validating a level.
+// Path is a JSON pointer (RFC 6901) to the offending value in the level document;
+// an empty path refers to the whole document.
+type Diagnostic struct {
+	Severity Severity
+	Path     string
+	Message  string
+	err      error
+}
+
+// Diagnostics is the list of problems found while validating a level.
+type Diagnostics []Diagnostic
+
+// ValidationError is a validation failure located at a path in the level document.
+type ValidationError struct {
+	Path string
+	Err  error
+}
+
+func (e *ValidationError) Error() string { return e.Err.Error() }
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// newValidationError creates a ValidationError with a formatted message.
+func newValidationError(path string, format string, args ...any) *ValidationError {
+	return &ValidationError{Path: path, Err: fmt.Errorf(format, args...)}
+}
+
+// ValidateLevel runs all loader validation passes on a level document without
+// creating a session, collecting every problem found instead of stopping at the first.
+// Every loot table item is validated; loot is placed as rolled with a fixed seed.
+func ValidateLevel(data json.RawMessage) Diagnostics {
+	_, diagnostics := loadGame(data, 0)
+	return diagnostics
+}
+
+// Errors returns the error diagnostics.
+func (d Diagnostics) Errors() Diagnostics {
+	return d.filter(SeverityError)
+}
+
+// Warnings returns the warning diagnostics.
+func (d Diagnostics) Warnings() Diagnostics {
+	return d.filter(SeverityWarning)
+}
+
+// HasErrors returns true if any diagnostic is an error.
+func (d Diagnostics) HasErrors() bool {
+	return len(d.Errors()) > 0
+}
+
+// Err returns the first error diagnostic as an error, or nil if there are no errors.
+func (d Diagnostics) Err() error {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityError {
+			return diagnostic.err
+		}
+	}
+	return nil
+}
+
+func (d Diagnostics) filter(severity Severity) Diagnostics {
+	var result Diagnostics
+	for _, diagnostic := range d {
+		if diagnostic.Severity == severity {
+			result = append(result, diagnostic)
+		}
+	}
+	return result
+}
+
+// addError records an error at a path.
+// If the error chain carries a ValidationError, its more specific path is used instead.
+func (d *Diagnostics) addError(path string, err error) {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		path = validationErr.Path
+	}
+	*d = append(*d, Diagnostic{
+		Severity: SeverityError,
+		Path:     path,
+		Message:  err.Error(),
+		err:      err,
+	})
+}
+
+// addWarning records a non-fatal warning at a path.
+func (d *Diagnostics) addWarning(path string, format string, args ...any) {
+	*d = append(*d, Diagnostic{
+		Severity: SeverityWarning,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// jsonPointer builds a JSON pointer from reference tokens, escaping '~' and '/'.
+func jsonPointer(tokens ...any) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		s := fmt.Sprint(token)
+		s = strings.ReplaceAll(s, "~", "~0")
+		s = strings.ReplaceAll(s, "/", "~1")
+		b.WriteString("/")
+		b.WriteString(s)
+	}
+	return b.String()
+}
@@ -0,0 +1,114 @@
+package loader
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestValidateLevel_Demo(t *testing.T) {
+	data, err := os.ReadFile("../../internal/testdata/demo.json")
+	if err != nil {
+		t.Fatalf("Failed to read demo level: %v", err)
+	}
+
+	diagnostics := ValidateLevel(data)
+	if diagnostics.HasErrors() {
+		t.Errorf("Expected demo level to be valid, got errors: %+v", diagnostics.Errors())
+	}
+}
+
+func TestValidateLevel_CollectsErrorsWithPaths(t *testing.T) {
+	jsonData := json.RawMessage(`{
+		"name": "broken level",
+		"floors": [
+			{
+				"name": "test floor",
+				"rooms": [
+					{
+						"name": "room1",
+						"description": "first room",
+						"connections": [
+							{"direction": "east", "door_name": "door1"},
+							{"direction": "north", "door_name": "missing door"}
+						],
+						"items": [
+							{"name": "fine item", "description": "an item", "portable": true},
+							{"name": "bad key", "description": "a key that is also a container", "key": true, "contains": "empty"}
+						]
+					},
+					{
+						"name": "room2",
+						"description": "second room",
+						"connections": [{"direction": "west", "door_name": "door1"}]
+					},
+					{
+						"name": "room3",
+						"description": "isolated room"
+					}
+				]
+			}
+		],
+		"doors": [{"name": "door1", "room_a": "room1", "room_b": "room2"}],
+		"scoring": {"badges": [{"name": "a"}, {"name": "a"}]}
+	}`)
+
+	diagnostics := ValidateLevel(jsonData)
+	if diagnostics.Err() == nil {
+		t.Fatal("Expected validation errors, got none")
+	}
+
+	expectedErrors := map[string]string{
+		"/floors/0/rooms/0/items/1": "invalid key",
+		"/scoring/badges/1/name":    "duplicate badge name",
+		"/floors/0/rooms/2":         "unreachable rooms found",
+	}
+	errors := diagnostics.Errors()
+	if len(errors) != len(expectedErrors) {
+		t.Fatalf("Expected %d errors, got %d: %+v", len(expectedErrors), len(errors), errors)
+	}
+	for _, diagnostic := range errors {
+		expected, ok := expectedErrors[diagnostic.Path]
+		if !ok {
+			t.Errorf("Unexpected error at %q: %s", diagnostic.Path, diagnostic.Message)
+			continue
+		}
+		if !strings.Contains(diagnostic.Message, expected) {
+			t.Errorf("Expected error at %q to contain %q, got: %s", diagnostic.Path, expected, diagnostic.Message)
+		}
+	}
+
+	warnings := diagnostics.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %d: %+v", len(warnings), warnings)
+	}
+	if warnings[0].Path != "/floors/0/rooms/0/connections/1/door_name" {
+		t.Errorf("Expected warning at unknown door connection, got %q", warnings[0].Path)
+	}
+}
+
+func TestValidateLevel_StructureErrors(t *testing.T) {
+	diagnostics := ValidateLevel(json.RawMessage(`{"name": "", "rooms": [{"name": "room", "description": "a room"}]}`))
+	errors := diagnostics.Errors()
+	if len(errors) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %+v", len(errors), errors)
+	}
+	if errors[0].Path != "/name" {
+		t.Errorf("Expected error at /name, got %q", errors[0].Path)
+	}
+
+	diagnostics = ValidateLevel(json.RawMessage(`not json`))
+	if !diagnostics.HasErrors() || diagnostics.Errors()[0].Path != "" {
+		t.Errorf("Expected document level error for invalid JSON, got %+v", diagnostics)
+	}
+}
+
+func TestJSONPointer(t *testing.T) {
+	if got := jsonPointer("rooms", 0, "a/b~c"); got != "/rooms/0/a~1b~0c" {
+		t.Errorf("Expected escaped pointer, got %q", got)
+	}
+	if got := jsonPointer(); got != "" {
+		t.Errorf("Expected empty pointer, got %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+package loader
+
+import (
+	"fmt"
+
+	"adventure-engine/pkg/world"
+)
+
+// connectionSide is a room's connection to a door, with its path in the level document.
+type connectionSide struct {
+	direction world.Direction
+	path      string
+}
+
+// validateDirections checks that connection directions are consistent, so that clients can draw maps:
+// a room may only have one door in each direction, and both sides of a door must use opposite directions.
+// Doors with a direction on one side only are reported as warnings.
+// Invalid direction values are reported by populateRoom.
+func validateDirections(gameData GameData, diagnostics *Diagnostics) {
+	sides := make(map[string]map[string]connectionSide) // door name -> room name -> side
+	for i, floorData := range gameData.Floors {
+		for j, roomData := range floorData.Rooms {
+			used := make(map[world.Direction]string) // direction -> door name
+			for k, conn := range roomData.Connections {
+				side := connectionSide{
+					direction: world.Direction(conn.Direction),
+					path:      jsonPointer("floors", i, "rooms", j, "connections", k),
+				}
+				if side.direction != "" && !side.direction.IsValid() {
+					continue
+				}
+				if side.direction != "" {
+					if other, exists := used[side.direction]; exists && other != conn.DoorName {
+						diagnostics.addError(side.path+jsonPointer("direction"),
+							fmt.Errorf("room %s has more than one door to the %s: %s and %s", roomData.Name, side.direction, other, conn.DoorName))
+						continue
+					}
+					used[side.direction] = conn.DoorName
+				}
+				if sides[conn.DoorName] == nil {
+					sides[conn.DoorName] = make(map[string]connectionSide)
+				}
+				sides[conn.DoorName][roomData.Name] = side
+			}
+		}
+	}
+
+	for _, doorData := range gameData.DoorData {
+		a, hasA := sides[doorData.Name][doorData.RoomA]
+		b, hasB := sides[doorData.Name][doorData.RoomB]
+		if !hasA || !hasB || (a.direction == "" && b.direction == "") {
+			continue
+		}
+		switch {
+		case a.direction == "":
+			diagnostics.addWarning(a.path, "door %s has a direction in room %s but not in room %s",
+				doorData.Name, doorData.RoomB, doorData.RoomA)
+		case b.direction == "":
+			diagnostics.addWarning(b.path, "door %s has a direction in room %s but not in room %s",
+				doorData.Name, doorData.RoomA, doorData.RoomB)
+		case b.direction != a.direction.Opposite():
+			diagnostics.addError(b.path+jsonPointer("direction"),
+				fmt.Errorf("door %s leads %s from %s, so it must lead %s from %s, not %s",
+					doorData.Name, a.direction, doorData.RoomA, a.direction.Opposite(), doorData.RoomB, b.direction))
+		}
+	}
+}
+
+// validatePositions checks that no two rooms on a floor share a grid position.
+func validatePositions(gameData GameData, diagnostics *Diagnostics) {
+	for i, floorData := range gameData.Floors {
+		occupied := make(map[PositionData]string) // position -> room name
+		for j, roomData := range floorData.Rooms {
+			if roomData.Position == nil {
+				continue
+			}
+			if other, exists := occupied[*roomData.Position]; exists {
+				diagnostics.addError(jsonPointer("floors", i, "rooms", j, "position"),
+					fmt.Errorf("room %s has the same position as room %s (%d, %d)", roomData.Name, other, roomData.Position.X, roomData.Position.Y))
+				continue
+			}
+			occupied[*roomData.Position] = roomData.Name
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package loader
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"adventure-engine/pkg/world"
+)
+
+func directionsLevel(hallDirection string, cellarDirection string) json.RawMessage {
+	return json.RawMessage(`{
+		"name": "directions test",
+		"rooms": [
+			{"name": "hall", "description": "a hall", "connections": [
+				{"door_name": "cellar door"` + hallDirection + `},
+				{"door_name": "garden door", "direction": "east"}
+			]},
+			{"name": "cellar", "description": "a cellar", "connections": [{"door_name": "cellar door"` + cellarDirection + `}]},
+			{"name": "garden", "description": "a garden", "connections": [{"door_name": "garden door", "direction": "west"}]}
+		],
+		"doors": [
+			{"name": "cellar door", "room_a": "hall", "room_b": "cellar"},
+			{"name": "garden door", "room_a": "hall", "room_b": "garden"}
+		]
+	}`)
+}
+
+func TestLoadGame_Directions(t *testing.T) {
+	level, err := LoadGame(directionsLevel(`, "direction": "down"`, `, "direction": "up"`))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+
+	hall := level.GetRoom(level.Floors[0].Name, "hall")
+	conn, err := hall.GetConnection("cellar door")
+	if err != nil {
+		t.Fatalf("Expected cellar door connection: %v", err)
+	}
+	if conn.Direction != world.DirectionDown {
+		t.Errorf("Expected direction down, got %q", conn.Direction)
+	}
+	if conn.Location != "down" {
+		t.Errorf("Expected location to default to the direction, got %q", conn.Location)
+	}
+
+	for _, door := range level.Doors {
+		expected := map[string]world.Direction{"cellar door": world.DirectionDown, "garden door": world.DirectionEast}[door.Name]
+		if direction := level.DoorDirection(door); direction != expected {
+			t.Errorf("Expected %s to lead %s from room A, got %q", door.Name, expected, direction)
+		}
+	}
+}
+
+func TestLoadGame_DirectionErrors(t *testing.T) {
+	tests := []struct {
+		name            string
+		hallDirection   string
+		cellarDirection string
+		path            string
+		message         string
+	}{
+		{
+			name:            "directions not opposite",
+			hallDirection:   `, "direction": "down"`,
+			cellarDirection: `, "direction": "north"`,
+			path:            "/rooms/1/connections/0/direction",
+			message:         "door cellar door leads down from hall, so it must lead up from cellar, not north",
+		},
+		{
+			name:            "two doors in the same direction",
+			hallDirection:   `, "direction": "east"`,
+			cellarDirection: `, "direction": "west"`,
+			path:            "/rooms/0/connections/1/direction",
+			message:         "room hall has more than one door to the east",
+		},
+		{
+			name:            "invalid direction",
+			hallDirection:   `, "direction": "sideways"`,
+			cellarDirection: `, "direction": "up"`,
+			path:            "/rooms/0/connections/0/direction",
+			message:         "invalid direction sideways",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := ValidateLevel(directionsLevel(tt.hallDirection, tt.cellarDirection))
+			errors := diagnostics.Errors()
+			if len(errors) == 0 {
+				t.Fatalf("Expected an error, got %+v", diagnostics)
+			}
+			if errors[0].Path != tt.path {
+				t.Errorf("Expected error at %q, got %q", tt.path, errors[0].Path)
+			}
+			if !strings.Contains(errors[0].Message, tt.message) {
+				t.Errorf("Expected error containing %q, got: %s", tt.message, errors[0].Message)
+			}
+		})
+	}
+}
+
+func TestLoadGame_DirectionOnOneSide(t *testing.T) {
+	_, warnings, err := LoadGameWithDiagnostics(directionsLevel(`, "direction": "down"`, ``), 0)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	for _, warning := range warnings {
+		if warning.Path == "/rooms/1/connections/0" && strings.Contains(warning.Message, "has a direction in room hall but not in room cellar") {
+			return
+		}
+	}
+	t.Errorf("Expected warning about the missing direction, got %+v", warnings)
+}
+
+func TestLoadGame_Positions(t *testing.T) {
+	level, err := LoadGame(json.RawMessage(`{
+		"name": "positions test",
+		"rooms": [
+			{"name": "hall", "description": "a hall", "position": {"x": 0, "y": 0}, "connections": [{"door_name": "garden door", "direction": "east"}]},
+			{"name": "garden", "description": "a garden", "position": {"x": 1, "y": 0}, "connections": [{"door_name": "garden door", "direction": "west"}]}
+		],
+		"doors": [{"name": "garden door", "room_a": "hall", "room_b": "garden"}]
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	garden := level.GetRoom(level.Floors[0].Name, "garden")
+	if garden.Position == nil || *garden.Position != (world.Position{X: 1, Y: 0}) {
+		t.Errorf("Expected garden at (1, 0), got %+v", garden.Position)
+	}
+
+	diagnostics := ValidateLevel(json.RawMessage(`{
+		"name": "positions test",
+		"rooms": [
+			{"name": "hall", "description": "a hall", "position": {"x": 0, "y": 0}, "connections": [{"door_name": "garden door"}]},
+			{"name": "garden", "description": "a garden", "position": {"x": 0, "y": 0}, "connections": [{"door_name": "garden door"}]}
+		],
+		"doors": [{"name": "garden door", "room_a": "hall", "room_b": "garden"}]
+	}`))
+	errors := diagnostics.Errors()
+	if len(errors) != 1 {
+		t.Fatalf("Expected 1 error, got %+v", diagnostics)
+	}
+	if errors[0].Path != "/rooms/1/position" {
+		t.Errorf("Expected error at /rooms/1/position, got %q", errors[0].Path)
+	}
+	if !strings.Contains(errors[0].Message, "room garden has the same position as room hall") {
+		t.Errorf("Expected error about shared position, got: %s", errors[0].Message)
+	}
+}
@@ -0,0 +1,435 @@
+package loader
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"adventure-engine/pkg/world"
+)
+
+// MarshalJSON implements custom marshaling for ContainerContents
+func (cc ContainerContents) MarshalJSON() ([]byte, error) {
+	if cc.Item == nil {
+		return json.Marshal("empty")
+	}
+	return json.Marshal(cc.Item)
+}
+
+// ExportLevel converts a level back into loader format, the inverse of LoadGame.
+// The level's current state is exported as its initial state: unlocked doors and containers
+// are exported without locks, unlatched doors without latches unless they are two-way,
+// uncovered concealers as plain items and fixtures with only their remaining required items.
+// Runtime flags such as visited rooms, searched containers and tried doors are not exported.
+func ExportLevel(level *world.Level) *GameData {
+	gameData := &GameData{
+		Name:             level.Name,
+		SchemaVersion:    CurrentSchemaVersion,
+		Theme:            level.Theme,
+		IntroNarrative:   level.IntroNarrative,
+		OutroNarrative:   level.OutroNarrative,
+		FailureNarrative: level.FailureNarrative,
+		Breath:           level.Breath,
+		Combat:           string(level.Combat),
+		RestEncounter:    level.RestEncounter,
+		Language:         level.Language,
+		Translations:     level.Translations,
+		VerbAliases:      level.VerbAliases,
+		DoorData:         []DoorData{},
+		Enemies:          []EnemyData{},
+	}
+
+	for _, floor := range level.Floors {
+		floorData := FloorData{
+			Name:        floor.Name,
+			Description: floor.Description,
+		}
+		for _, room := range floor.Rooms {
+			floorData.Rooms = append(floorData.Rooms, exportRoom(room))
+		}
+		gameData.Floors = append(gameData.Floors, floorData)
+	}
+
+	for _, door := range level.Doors {
+		doorData := exportDoor(door)
+		for _, trigger := range level.Triggers {
+			if door.Hidden && trigger.EffectType == world.EffectRevealDoor && trigger.Effect.TargetName == door.Name {
+				revealOn := exportObjectiveEvent(&trigger.Event)
+				doorData.RevealOn = &revealOn
+				break
+			}
+		}
+		gameData.DoorData = append(gameData.DoorData, doorData)
+	}
+
+	// Triggers are attached to the enemy they send the player into combat with
+	for _, enemy := range level.Enemies {
+		enemyData := EnemyData{
+			Name:          enemy.Name,
+			Description:   enemy.Description,
+			ImageRef:      enemy.ImageRef,
+			HP:            enemy.HP,
+			Room:          enemy.Room,
+			Intimidate:    enemy.Intimidate,
+			Sneak:         enemy.Sneak,
+			Bribes:        enemy.Bribes,
+			Wanders:       enemy.Wanders,
+			Morale:        enemy.Morale,
+			Interrogation: enemy.Interrogation,
+		}
+		if enemy.Carries != nil {
+			enemyData.Carries = exportItem(enemy.Carries)
+		}
+		for _, trigger := range level.Triggers {
+			if trigger.EffectType == world.EffectEnterCombat && trigger.Effect.EnemyName == enemy.Name {
+				enemyData.Trigger = &TriggerData{
+					Event:       string(trigger.Event.Event),
+					ItemName:    trigger.Event.ItemName,
+					RoomName:    trigger.Event.RoomName,
+					FixtureName: trigger.Event.FixtureName,
+					LockName:    trigger.Event.LockName,
+				}
+				break
+			}
+		}
+		gameData.Enemies = append(gameData.Enemies, enemyData)
+	}
+
+	if level.WinCondition != nil {
+		gameData.WinCondition = &EventData{
+			Event:     string(level.WinCondition.Event),
+			RoomName:  level.WinCondition.RoomName,
+			ItemName:  level.WinCondition.ItemName,
+			EnemyName: level.WinCondition.EnemyName,
+		}
+	}
+
+	for _, objective := range level.Objectives {
+		objectiveData := ObjectiveData{
+			Name:        objective.Name,
+			Description: objective.Description,
+			CompleteOn:  exportObjectiveEvent(&objective.CompleteOn),
+		}
+		if objective.RevealOn != nil {
+			revealOn := exportObjectiveEvent(objective.RevealOn)
+			objectiveData.RevealOn = &revealOn
+		}
+		gameData.Objectives = append(gameData.Objectives, objectiveData)
+	}
+
+	for _, comboItem := range level.ComboItems {
+		comboItemData := ComboItemData{
+			Fixture:    comboItem.FixtureName,
+			OutputItem: *exportItem(comboItem.OutputItem),
+			Known:      comboItem.Known,
+			TaughtBy:   comboItem.TaughtBy,
+		}
+		if len(comboItem.MoreInputNames) > 0 {
+			comboItemData.InputItemNames = comboItem.InputNames()
+		} else {
+			comboItemData.InputItemAName = comboItem.InputItemAName
+			comboItemData.InputItemBName = comboItem.InputItemBName
+		}
+		for _, byproduct := range comboItem.Byproducts {
+			comboItemData.Byproducts = append(comboItemData.Byproducts, *exportItem(byproduct))
+		}
+		gameData.ComboItems = append(gameData.ComboItems, comboItemData)
+	}
+
+	if level.Scoring != nil {
+		gameData.Scoring = exportScoring(level.Scoring)
+	}
+
+	return gameData
+}
+
+func exportRoom(room *world.Room) RoomData {
+	roomData := RoomData{
+		Name:               room.Name,
+		Description:        room.Description,
+		InitialDescription: room.InitialDescription,
+		SavePoint:          room.SavePoint,
+		Airless:            room.Airless,
+		Burning:            room.Burning,
+		ImageRef:           room.ImageRef,
+		Ambient:            room.Ambient,
+		SoundCues:          exportSoundCues(room.SoundCues),
+	}
+	for _, subLocation := range room.SubLocations {
+		roomData.SubLocations = append(roomData.SubLocations, SubLocationData{Name: subLocation.Name, Preposition: subLocation.Preposition})
+	}
+	if room.Position != nil {
+		roomData.Position = &PositionData{X: room.Position.X, Y: room.Position.Y}
+	}
+	if node := room.TravelNode; node != nil {
+		roomData.TravelNode = &TravelNodeData{
+			Name:        node.Name,
+			Description: node.Description,
+			Network:     node.Network,
+			Unpowered:   node.Unpowered,
+			Discovered:  node.Discovered,
+		}
+	}
+	for _, conn := range room.Connections {
+		roomData.Connections = append(roomData.Connections, ConnectionData{
+			Location:    conn.Location,
+			Direction:   string(conn.Direction),
+			DoorName:    conn.DoorName,
+			Description: conn.Description,
+		})
+	}
+	for _, item := range room.Items {
+		roomData.Items = append(roomData.Items, *exportItem(item))
+	}
+	for _, description := range room.ConditionalDescriptions {
+		roomData.ConditionalDescriptions = append(roomData.ConditionalDescriptions, ConditionalDescriptionData{
+			When: DescriptionConditionData{
+				ItemTaken:    description.When.ItemTaken,
+				EnemyKilled:  description.When.EnemyKilled,
+				DoorUnlocked: description.When.DoorUnlocked,
+				MinTurns:     description.When.MinTurns,
+			},
+			Description: description.Description,
+		})
+	}
+	return roomData
+}
+
+func exportDoor(door *world.Door) DoorData {
+	doorData := DoorData{
+		Name:      door.Name,
+		RoomA:     door.RoomA,
+		RoomB:     door.RoomB,
+		Stairwell: door.Stairwell,
+		Barred:    door.Barred,
+		Aliases:   door.Aliases,
+		Hidden:    door.Hidden,
+		OneWay:    door.OneWay,
+	}
+	if door.IsLocked() {
+		doorData.Locked = true
+		doorData.RequiredKeyName = door.Lock.KeyName
+		doorData.Code = door.Lock.Code
+		doorData.RequireLearned = door.Lock.RequireLearned
+		doorData.MaxAttempts = door.Lock.AttemptsLeft() // wrong codes entered so far count against the limit
+		doorData.Jammed = door.Lock.Jammed
+	}
+	if door.IsLatched() {
+		doorData.LatchedFrom = door.Latch.LockedFrom
+	}
+	if door.Latch != nil && door.Latch.TwoWay {
+		doorData.TwoWayLatch = true
+	}
+	return doorData
+}
+
+// exportItem recursively converts an item and its nested items back into loader format.
+func exportItem(item *world.Item) *ItemData {
+	itemData := &ItemData{
+		Name:        item.Name,
+		Description: item.Description,
+		ImageRef:    item.ImageRef,
+		Location:    item.Location,
+		SubLocation: item.SubLocation,
+		Detail:      item.Detail,
+		Secret:      item.Secret,
+		QuestItem:   item.QuestItem,
+		Aliases:     item.Aliases,
+		SoundCues:   exportSoundCues(item.SoundCues),
+		Portable:    item.IsPortable(),
+		Key:         item.IsKey(),
+	}
+	if item.ID != item.Name {
+		itemData.ID = item.ID
+	}
+	if item.Count() > 1 {
+		itemData.Quantity = item.Count()
+	}
+
+	if item.IsWeapon() {
+		itemData.WeaponDamage = item.Weapon.Damage
+		if item.Weapon.UsesAmmo() {
+			itemData.Ammo = item.Weapon.Ammo.Quantity
+			itemData.AmmoType = item.AmmoType()
+		}
+		if item.Weapon.IsThrown() {
+			itemData.Thrown = &ThrownData{
+				Area:       item.Weapon.Thrown.Area,
+				StartsFire: item.Weapon.Thrown.StartsFire,
+			}
+		}
+		if critical := item.Weapon.Critical; critical != nil {
+			itemData.Critical = &CriticalData{
+				Chance:          critical.Chance,
+				Damage:          critical.Damage,
+				Narrative:       critical.Narrative,
+				Fumble:          critical.Fumble,
+				FumbleNarrative: critical.FumbleNarrative,
+			}
+		}
+	}
+
+	if item.IsArmor() {
+		itemData.Armor = &ArmorData{Slot: item.Armor.Slot}
+		if item.Armor.Block < 1 {
+			itemData.Armor.Block = item.Armor.Block
+		}
+	}
+
+	if item.IsHealthItem() {
+		itemData.HealthEffect = string(item.HealthItem.HealthEffect)
+	}
+	itemData.AirSupply = item.IsAirSupply()
+	itemData.CustomComponents = maps.Clone(item.CustomComponents)
+
+	if item.IsAmmoBox() {
+		itemData.AmmoType = item.AmmoBox.AmmoType
+		itemData.Ammo = item.AmmoBox.Ammo.Quantity
+	}
+
+	if item.IsContainer() {
+		itemData.Contains = &ContainerContents{Empty: item.Container.IsEmpty()}
+		if !item.Container.IsEmpty() {
+			itemData.Contains.Item = exportItem(item.Container.Contains)
+		}
+		if item.Container.IsLocked() {
+			itemData.RequiredKeyName = item.Container.Locked.KeyName
+			itemData.Code = item.Container.Locked.Code
+			itemData.RequireLearned = item.Container.Locked.RequireLearned
+			itemData.MaxAttempts = item.Container.Locked.AttemptsLeft()
+			itemData.Jammed = item.Container.Locked.Jammed
+		}
+	}
+
+	if item.IsConcealer() && item.Concealer.Hidden != nil {
+		itemData.Conceals = exportItem(item.Concealer.Hidden)
+	}
+
+	if item.Charges != nil {
+		itemData.Charges = item.Charges.Max
+		itemData.ChargesUsed = item.Charges.Used
+	}
+	if item.Durability != nil {
+		itemData.Durability = item.Durability.Max
+		itemData.Wear = item.Durability.Used
+		if item.Durability.Scrap != nil {
+			itemData.Scrap = exportItem(item.Durability.Scrap)
+		}
+	}
+
+	if item.Trap != nil {
+		itemData.Trap = &TrapData{
+			On:        item.Trap.On,
+			Narrative: item.Trap.Narrative,
+			Sprung:    item.Trap.Sprung,
+		}
+		for _, effect := range item.Trap.Effects {
+			effectData := TrapEffectData{Effect: string(effect.EffectType), Target: effect.EnemyName, Params: effect.Params}
+			if effect.IsCustom() {
+				effectData.Target = effect.TargetName
+			}
+			itemData.Trap.Effects = append(itemData.Trap.Effects, effectData)
+		}
+	}
+
+	if item.IsMoveable() {
+		itemData.Moveable = &MoveableData{
+			Directions:  item.Moveable.Directions,
+			RevealsDoor: item.Moveable.RevealsDoor,
+			Narrative:   item.Moveable.Narrative,
+			Moved:       item.Moveable.Moved,
+		}
+		if item.Moveable.Reveals != nil {
+			itemData.Moveable.Reveals = exportItem(item.Moveable.Reveals)
+		}
+	}
+
+	if item.IsFixture() {
+		itemData.Fixture = &FixtureData{
+			RequiredItems:       []string{},
+			RevealMissing:       string(item.Fixture.RevealMissing),
+			CompletionNarrative: item.Fixture.CompletionNarrative,
+		}
+		missing := func(itemName string) bool { return !item.Fixture.RequiredItems[itemName] }
+		if len(item.Fixture.Stages) > 0 {
+			// Only the stages still to do are kept, without the items already used on them
+			for _, stage := range item.Fixture.Stages[item.Fixture.CurrentStage():] {
+				itemData.Fixture.Stages = append(itemData.Fixture.Stages, FixtureStageData{
+					RequiredItems: slices.DeleteFunc(slices.Clone(stage.RequiredItems), func(itemName string) bool { return item.Fixture.RequiredItems[itemName] }),
+					Narrative:     stage.Narrative,
+				})
+			}
+		} else {
+			for _, requiredItem := range sortedKeys(item.Fixture.RequiredItems) {
+				if missing(requiredItem) {
+					itemData.Fixture.RequiredItems = append(itemData.Fixture.RequiredItems, requiredItem)
+				}
+			}
+		}
+		for _, itemName := range sortedKeys(item.Fixture.InsertNarratives) {
+			if missing(itemName) {
+				itemData.Fixture.InsertNarratives = append(itemData.Fixture.InsertNarratives, InsertNarrativeData{
+					Item:      itemName,
+					Narrative: item.Fixture.InsertNarratives[itemName],
+				})
+			}
+		}
+		// A completed fixture has already produced its item and run its effects
+		if !item.Fixture.IsComplete() {
+			if item.Fixture.Produces != nil {
+				itemData.Fixture.Produces = exportItem(item.Fixture.Produces)
+			}
+			for _, effect := range item.Fixture.OnComplete {
+				itemData.Fixture.OnComplete = append(itemData.Fixture.OnComplete, FixtureEffectData{
+					Effect: string(effect.EffectType),
+					Target: effect.TargetName + effect.EnemyName,
+					Params: effect.Params,
+				})
+			}
+		}
+	}
+
+	return itemData
+}
+
+func exportSoundCues(soundCues world.SoundCues) map[string]string {
+	if len(soundCues) == 0 {
+		return nil
+	}
+	cues := make(map[string]string, len(soundCues))
+	for event, sound := range soundCues {
+		cues[string(event)] = sound
+	}
+	return cues
+}
+
+func exportObjectiveEvent(event *world.Event) ObjectiveEventData {
+	return ObjectiveEventData{
+		Event:       string(event.Event),
+		RoomName:    event.RoomName,
+		ItemName:    event.ItemName,
+		FixtureName: event.FixtureName,
+		EnemyName:   event.EnemyName,
+	}
+}
+
+func exportScoring(scoring *world.Scoring) *ScoringData {
+	scoringData := &ScoringData{
+		BasePoints:    &scoring.BasePoints,
+		TurnPenalty:   &scoring.TurnPenalty,
+		DamagePenalty: &scoring.DamagePenalty,
+		EnemyBonus:    &scoring.EnemyBonus,
+		SecretBonus:   &scoring.SecretBonus,
+	}
+	for _, badge := range scoring.Badges {
+		scoringData.Badges = append(scoringData.Badges, BadgeData{
+			Name:               badge.Name,
+			Description:        badge.Description,
+			MaxTurns:           badge.Condition.MaxTurns,
+			MinEnemiesDefeated: badge.Condition.MinEnemiesDefeated,
+			MaxDamageTaken:     badge.Condition.MaxDamageTaken,
+			MinSecretsFound:    badge.Condition.MinSecretsFound,
+		})
+	}
+	return scoringData
+}
@@ -0,0 +1,51 @@
+package loader
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestExportLevel_RoundTrip(t *testing.T) {
+	for _, filename := range []string{"../../internal/testdata/demo.json", "../../internal/testdata/floors.json", "../../internal/testdata/fixture.json", "../../internal/testdata/crafting.json"} {
+		t.Run(filename, func(t *testing.T) {
+			level, err := LoadGameFromFile(filename)
+			if err != nil {
+				t.Fatalf("Failed to load game: %v", err)
+			}
+
+			data, err := json.Marshal(ExportLevel(level))
+			if err != nil {
+				t.Fatalf("Failed to marshal exported level: %v", err)
+			}
+			reloaded, err := LoadGame(data)
+			if err != nil {
+				t.Fatalf("Failed to load exported level: %v\n%s", err, data)
+			}
+
+			if level.Theme != reloaded.Theme {
+				t.Errorf("Expected theme %q to survive the round trip, got %q", level.Theme, reloaded.Theme)
+			}
+			if !reflect.DeepEqual(level.Floors, reloaded.Floors) {
+				t.Error("Expected floors to survive the round trip")
+			}
+			if !reflect.DeepEqual(level.Triggers, reloaded.Triggers) {
+				t.Error("Expected triggers to survive the round trip")
+			}
+			if !reflect.DeepEqual(level.WinCondition, reloaded.WinCondition) {
+				t.Error("Expected win condition to survive the round trip")
+			}
+			if !reflect.DeepEqual(level.ComboItems, reloaded.ComboItems) {
+				t.Error("Expected combo items to survive the round trip")
+			}
+			if len(level.Doors) != len(reloaded.Doors) {
+				t.Errorf("Expected %d doors, got %d", len(level.Doors), len(reloaded.Doors))
+			}
+			for _, door := range level.Doors {
+				if !reflect.DeepEqual(door, reloaded.GetDoor(door.Name)) {
+					t.Errorf("Expected door %s to survive the round trip", door.Name)
+				}
+			}
+		})
+	}
+}
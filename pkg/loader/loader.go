@@ -0,0 +1,1764 @@
+// Package loader reads levels from JSON or YAML into the world, validating them and reporting
+// problems as diagnostics with JSON pointers. It also exports a world back to level data and
+// describes the level format as a JSON Schema.
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"adventure-engine/pkg/world"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ComboItemData represents a combination item in the JSON
+// A recipe names either two inputs as input_item_a_name and input_item_b_name,
+// or two to four inputs as input_item_names.
+type ComboItemData struct {
+	InputItemAName string     `json:"input_item_a_name,omitempty"`
+	InputItemBName string     `json:"input_item_b_name,omitempty"`
+	InputItemNames []string   `json:"input_item_names,omitempty"`
+	Fixture        string     `json:"fixture,omitempty"` // fixture the player must be at to combine the inputs
+	OutputItem     ItemData   `json:"output_item" schema:"required"`
+	Byproducts     []ItemData `json:"byproducts,omitempty"` // handed out alongside the output
+	Known          bool       `json:"known,omitempty"`      // listed by the recipes endpoint from the start
+	TaughtBy       []string   `json:"taught_by,omitempty"`  // items that teach the recipe when inspected
+}
+
+// GameData represents the top-level JSON structure
+type FloorData struct {
+	Name        string     `json:"name" schema:"required"`
+	Description string     `json:"description" schema:"localized"`
+	Rooms       []RoomData `json:"rooms" schema:"required,nonempty"`
+}
+
+type GameData struct {
+	Name             string                       `json:"name" schema:"required,nonempty"`
+	Theme            string                       `json:"system_prompt_theme,omitempty"`
+	IntroNarrative   string                       `json:"intro_narrative,omitempty" schema:"localized"`
+	OutroNarrative   string                       `json:"outro_narrative,omitempty" schema:"localized"`
+	FailureNarrative string                       `json:"failure_narrative,omitempty" schema:"localized"` // told when the player dies and the level is lost
+	WinCondition     *EventData                   `json:"win_condition"`
+	SchemaVersion    int                          `json:"schema_version,omitempty"`
+	Floors           []FloorData                  `json:"floors" schema:"required,nonempty"`
+	DoorData         []DoorData                   `json:"doors"`
+	Enemies          []EnemyData                  `json:"enemies"`
+	Objectives       []ObjectiveData              `json:"objectives,omitempty"`
+	ComboItems       []ComboItemData              `json:"combo_items,omitempty"`
+	ItemTemplates    map[string]ItemData          `json:"item_templates,omitempty"` // expanded before loading
+	LootTables       map[string]LootTableData     `json:"loot_tables,omitempty"`    // rolled before loading
+	Scoring          *ScoringData                 `json:"scoring,omitempty"`
+	Breath           int                          `json:"breath,omitempty"`                                                   // actions a player can hold their breath for in airless rooms
+	Combat           string                       `json:"combat,omitempty" schema:"enum=probabilistic|deterministic|opposed"` // how rounds of battle are won, defaults to probabilistic
+	RestEncounter    float64                      `json:"rest_encounter,omitempty"`                                           // chance per turn rested that a wandering enemy comes upon the player
+	Language         string                       `json:"language,omitempty"`                                                 // language the level is written in, defaults to English
+	Translations     map[string]map[string]string `json:"translations,omitempty"`                                             // language -> text -> translated text
+	VerbAliases      map[string]string            `json:"verb_aliases,omitempty"`                                             // words -> the command they stand for, such as "pry" -> "use crowbar on"
+}
+
+// ScoringData represents the scoring rules in the JSON
+// Omitted weights fall back to the engine defaults.
+type ScoringData struct {
+	BasePoints    *int        `json:"base_points,omitempty"`
+	TurnPenalty   *int        `json:"turn_penalty,omitempty"`
+	DamagePenalty *int        `json:"damage_penalty,omitempty"`
+	EnemyBonus    *int        `json:"enemy_bonus,omitempty"`
+	SecretBonus   *int        `json:"secret_bonus,omitempty"`
+	Badges        []BadgeData `json:"badges,omitempty"`
+}
+
+// BadgeData represents an achievable badge in the JSON
+type BadgeData struct {
+	Name               string `json:"name" schema:"required,nonempty"`
+	Description        string `json:"description" schema:"localized"`
+	MaxTurns           *int   `json:"max_turns,omitempty"`
+	MinEnemiesDefeated *int   `json:"min_enemies_defeated,omitempty"`
+	MaxDamageTaken     *int   `json:"max_damage_taken,omitempty"`
+	MinSecretsFound    *int   `json:"min_secrets_found,omitempty"`
+}
+
+// EventData represents an event in the JSON
+type EventData struct {
+	Event     string `json:"event" schema:"required,enum=room_entered|enemy_killed"`
+	RoomName  string `json:"room_name,omitempty"`
+	ItemName  string `json:"item_name,omitempty"`
+	EnemyName string `json:"enemy_name,omitempty"`
+}
+
+// RoomData represents a room in the JSON
+type RoomData struct {
+	Name               string            `json:"name" schema:"required"`
+	Description        string            `json:"description" schema:"localized"`
+	InitialDescription string            `json:"initial_description,omitempty" schema:"localized"`
+	Connections        []ConnectionData  `json:"connections,omitempty"`
+	Items              []ItemData        `json:"items,omitempty"`
+	Loot               []LootData        `json:"loot,omitempty"` // rolled into items before loading
+	Position           *PositionData     `json:"position,omitempty"`
+	SavePoint          bool              `json:"save_point,omitempty"`
+	ImageRef           string            `json:"image_ref,omitempty"`
+	Ambient            string            `json:"ambient,omitempty"`    // sound looped while the player is in the room
+	SoundCues          map[string]string `json:"sound_cues,omitempty"` // event -> sound, for frontends with audio
+	TravelNode         *TravelNodeData   `json:"travel_node,omitempty"`
+	Airless            bool              `json:"airless,omitempty"` // there is no air to breathe, like in a flooded tunnel
+	Burning            bool              `json:"burning,omitempty"` // something thrown has set the room on fire
+	SubLocations       []SubLocationData `json:"sub_locations,omitempty"`
+	// ConditionalDescriptions replace the description once the world changes, the first that holds winning
+	ConditionalDescriptions []ConditionalDescriptionData `json:"conditional_descriptions,omitempty"`
+}
+
+// SubLocationData represents a spot within a room that items can be at in the JSON, such as
+// {"name": "desk", "preposition": "on"}
+type SubLocationData struct {
+	Name        string `json:"name" schema:"required,nonempty"`
+	Preposition string `json:"preposition,omitempty" schema:"enum=on|in|under|behind|beside|by|near|above|below|against|at"` // defaults to on
+}
+
+// TravelNodeData represents a fast travel point in a room in the JSON
+type TravelNodeData struct {
+	Name        string `json:"name" schema:"required,nonempty"`
+	Description string `json:"description,omitempty" schema:"localized"`
+	Network     string `json:"network,omitempty"`    // nodes only connect to nodes on the same network
+	Unpowered   bool   `json:"unpowered,omitempty"`  // the node needs a fixture's power effect before it works
+	Discovered  bool   `json:"discovered,omitempty"` // the player knows of the node from the start
+}
+
+// PositionData represents a room's cell on its floor's grid in the JSON
+// X grows to the east and Y grows to the south.
+type PositionData struct {
+	X int `json:"x" schema:"required"`
+	Y int `json:"y" schema:"required"`
+}
+
+// ConnectionData represents a room connection in the JSON
+type ConnectionData struct {
+	Location    string `json:"location"`
+	Direction   string `json:"direction,omitempty" schema:"enum=north|south|east|west|up|down"`
+	DoorName    string `json:"door_name" schema:"required"`
+	Description string `json:"description,omitempty" schema:"localized"`
+}
+
+// ContainerContents can be either an ItemData or the string "empty"
+type ContainerContents struct {
+	Item  *ItemData
+	Empty bool
+}
+
+// UnmarshalJSON implements custom unmarshaling for ContainerContents
+func (cc *ContainerContents) UnmarshalJSON(data []byte) error {
+	// Try to unmarshal as string first
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		if str == "empty" {
+			cc.Empty = true
+			return nil
+		}
+	}
+
+	// Try to unmarshal as ItemData
+	var item ItemData
+	if err := json.Unmarshal(data, &item); err == nil {
+		cc.Item = &item
+		return nil
+	}
+
+	return fmt.Errorf("invalid container contents")
+}
+
+// FixtureData represents a fixture in the JSON
+// A fixture lists its required items either all at once or in stages that must be done in order.
+type FixtureData struct {
+	RequiredItems       []string              `json:"required_items,omitempty"`
+	Stages              []FixtureStageData    `json:"stages,omitempty"`
+	InsertNarratives    []InsertNarrativeData `json:"insert_narratives,omitempty"`
+	RevealMissing       string                `json:"reveal_missing,omitempty" schema:"enum=items|count|none"` // defaults to items
+	Produces            *ItemData             `json:"produces,omitempty"`
+	CompletionNarrative string                `json:"completion_narrative,omitempty" schema:"localized"`
+	OnComplete          []FixtureEffectData   `json:"on_complete,omitempty"` // effects run when the fixture is completed
+}
+
+// FixtureEffectData represents an effect of completing a fixture in the JSON
+type FixtureEffectData struct {
+	Effect string          `json:"effect" schema:"required,enum=unlock|reveal_door|end_combat|complete_level|power,custom"`
+	Target string          `json:"target,omitempty"` // door or container to unlock, hidden door to reveal or enemy to drive off
+	Params json.RawMessage `json:"params,omitempty"` // settings of a custom effect, passed to the plugin that runs it
+}
+
+// FixtureStageData represents a stage of a fixture in the JSON
+type FixtureStageData struct {
+	RequiredItems []string `json:"required_items" schema:"required,nonempty"`
+	Narrative     string   `json:"narrative,omitempty" schema:"localized"` // told when the stage is done
+}
+
+// InsertNarrativeData represents what a fixture tells the player when it accepts an item
+type InsertNarrativeData struct {
+	Item      string `json:"item" schema:"required"`
+	Narrative string `json:"narrative" schema:"required,localized"`
+}
+
+// ItemData represents an item in the JSON
+type ItemData struct {
+	Name            string             `json:"name"`
+	ID              string             `json:"id,omitempty"`       // tells the item apart from others with its name, generated if absent
+	Template        string             `json:"template,omitempty"` // name of an item template to base this item on
+	Description     string             `json:"description" schema:"localized"`
+	ImageRef        string             `json:"image_ref,omitempty"`
+	Location        string             `json:"location,omitempty"`
+	SubLocation     string             `json:"sub_location,omitempty"` // the sub-location of the room the item is at
+	Detail          string             `json:"detail,omitempty" schema:"localized"`
+	Secret          bool               `json:"secret,omitempty"`
+	QuestItem       bool               `json:"quest_item,omitempty"` // the level can't be won without the item, so players can't give it away, craft with it or drop it
+	Aliases         []string           `json:"aliases,omitempty"`    // other names the player can refer to the item by
+	SoundCues       map[string]string  `json:"sound_cues,omitempty"` // event -> sound, for frontends with audio
+	Portable        bool               `json:"portable,omitempty"`
+	Quantity        int                `json:"quantity,omitempty"` // how many there are of a stack of portable items, such as 3 bandages
+	Key             bool               `json:"key,omitempty"`
+	WeaponDamage    float64            `json:"weapon_damage,omitempty"`
+	Ammo            int                `json:"ammo,omitempty"`
+	AmmoType        string             `json:"ammo_type,omitempty"` // the ammo a weapon fires or an ammo box holds, such as "9mm"
+	Thrown          *ThrownData        `json:"thrown,omitempty"`    // the weapon is thrown and used up, like a grenade
+	Critical        *CriticalData      `json:"critical,omitempty"`  // the weapon's critical hits and fumbles
+	Armor           *ArmorData         `json:"armor,omitempty"`
+	HealthEffect    string             `json:"health_effect,omitempty" schema:"enum=weak|strong"`
+	AirSupply       bool               `json:"air_supply,omitempty"` // the player can breathe from the item once, or once per charge, in an airless room
+	Code            string             `json:"code,omitempty"`
+	RequireLearned  bool               `json:"require_learned_code,omitempty"` // the keypad only takes its code once the player has read it
+	MaxAttempts     int                `json:"max_attempts,omitempty"`         // wrong codes the keypad takes before it jams
+	Jammed          bool               `json:"jammed,omitempty"`
+	RequiredKeyName string             `json:"required_key_name,omitempty"`
+	Conceals        *ItemData          `json:"conceals,omitempty"`
+	Contains        *ContainerContents `json:"contains,omitempty"`
+	Fixture         *FixtureData       `json:"fixture,omitempty"`
+	Durability      int                `json:"durability,omitempty"`   // battle rounds or uses before the item breaks
+	Wear            int                `json:"wear,omitempty"`         // uses already spent
+	Scrap           *ItemData          `json:"scrap,omitempty"`        // what the item snaps into when it breaks
+	Charges         int                `json:"charges,omitempty"`      // uses before the item is used up, such as 3 doses of a medkit
+	ChargesUsed     int                `json:"charges_used,omitempty"` // charges already spent
+	Moveable        *MoveableData      `json:"moveable,omitempty"`
+	Trap            *TrapData          `json:"trap,omitempty"` // springs on the player who takes or uses the item
+	// CustomComponents are components for engine plugins, by name, carried as they are
+	CustomComponents map[string]json.RawMessage `json:"custom_components,omitempty"`
+}
+
+// ThrownData represents what a thrown weapon does besides hitting the enemy in the JSON
+type ThrownData struct {
+	Area       bool `json:"area,omitempty"`        // also hurts the other enemies in the room
+	StartsFire bool `json:"starts_fire,omitempty"` // sets the room on fire
+}
+
+// CriticalData represents a weapon's critical hits and fumbles in the JSON
+type CriticalData struct {
+	Chance          float64 `json:"chance,omitempty"`                              // chance that a round won is a critical hit
+	Damage          int     `json:"damage,omitempty"`                              // extra hit points a critical hit costs the enemy, 1 if omitted
+	Narrative       string  `json:"narrative,omitempty" schema:"localized"`        // told on a critical hit
+	Fumble          float64 `json:"fumble,omitempty"`                              // chance that a round lost is a fumble, in which the player drops the weapon
+	FumbleNarrative string  `json:"fumble_narrative,omitempty" schema:"localized"` // told on a fumble
+}
+
+// ArmorData represents armor the player wears in the JSON
+type ArmorData struct {
+	Slot  string  `json:"slot" schema:"required"` // where it is worn, such as head or body; one piece is worn per slot
+	Block float64 `json:"block,omitempty"`        // chance of stopping a hit, 1 if omitted
+}
+
+// MoveableData represents furniture the player can move in the JSON
+type MoveableData struct {
+	Directions  []string  `json:"directions,omitempty"`   // ways it can be moved, such as push, pull or left; any way if omitted
+	Reveals     *ItemData `json:"reveals,omitempty"`      // item behind it
+	RevealsDoor string    `json:"reveals_door,omitempty"` // hidden door behind it
+	Narrative   string    `json:"narrative,omitempty" schema:"localized"`
+	Moved       bool      `json:"moved,omitempty"`
+}
+
+// TrapData represents a trap on an item in the JSON
+type TrapData struct {
+	On        string           `json:"on,omitempty" schema:"enum=take|use"` // when the trap springs, take if omitted
+	Effects   []TrapEffectData `json:"effects" schema:"required,nonempty"`
+	Narrative string           `json:"narrative,omitempty" schema:"localized"` // told when the trap springs
+	Sprung    bool             `json:"sprung,omitempty"`
+}
+
+// TrapEffectData represents an effect of a trap springing in the JSON
+type TrapEffectData struct {
+	Effect string          `json:"effect" schema:"required,enum=damage|poison|enter_combat,custom"`
+	Target string          `json:"target,omitempty"` // enemy the trap sets on the player
+	Params json.RawMessage `json:"params,omitempty"` // settings of a custom effect, passed to the plugin that runs it
+}
+
+// DoorData represents a door in the JSON
+type DoorData struct {
+	Name            string              `json:"name" schema:"required"`
+	RoomA           string              `json:"room_a" schema:"required"`
+	RoomB           string              `json:"room_b" schema:"required"`
+	Locked          bool                `json:"locked,omitempty"`
+	RequiredKeyName string              `json:"required_key_name,omitempty"`
+	Code            string              `json:"code,omitempty"`
+	RequireLearned  bool                `json:"require_learned_code,omitempty"` // the keypad only takes its code once the player has read it
+	MaxAttempts     int                 `json:"max_attempts,omitempty"`         // wrong codes the keypad takes before it jams
+	Jammed          bool                `json:"jammed,omitempty"`
+	Stairwell       bool                `json:"stairwell,omitempty"`
+	Barred          bool                `json:"barred,omitempty"` // the door can be seen through
+	LatchedFrom     string              `json:"latched_from,omitempty"`
+	TwoWayLatch     bool                `json:"two_way_latch,omitempty"` // the player can latch the door from either side
+	Aliases         []string            `json:"aliases,omitempty"`       // other names the player can refer to the door by
+	Hidden          bool                `json:"hidden,omitempty"`        // a secret passage, revealed by reveal_on, a fixture's reveal_door effect or moving furniture
+	RevealOn        *ObjectiveEventData `json:"reveal_on,omitempty"`     // event that reveals a hidden door
+	OneWay          bool                `json:"one_way,omitempty"`       // the door can only be gone through from room_a to room_b
+}
+
+// EnemyData represents an enemy in the JSON
+type EnemyData struct {
+	Name          string       `json:"name" schema:"required"`
+	Description   string       `json:"description" schema:"localized"`
+	ImageRef      string       `json:"image_ref,omitempty"`
+	HP            int          `json:"hp"`
+	Room          string       `json:"room,omitempty"`                             // attacks when the player enters the room, unless a trigger says otherwise
+	Trigger       *TriggerData `json:"trigger,omitempty"`                          // room_entered triggers default to the enemy's room
+	Intimidate    float64      `json:"intimidate,omitempty"`                       // chance that intimidating the enemy drives it off
+	Sneak         float64      `json:"sneak,omitempty"`                            // chance of sneaking past the enemy
+	Bribes        []string     `json:"bribes,omitempty"`                           // items that buy the enemy off
+	Wanders       bool         `json:"wanders,omitempty"`                          // may come upon a resting player
+	Morale        int          `json:"morale,omitempty"`                           // hp at or below which the enemy surrenders
+	Carries       *ItemData    `json:"carries,omitempty"`                          // found by searching the enemy once it surrenders
+	Interrogation string       `json:"interrogation,omitempty" schema:"localized"` // what the enemy tells the player once it surrenders
+}
+
+// TriggerData represents a trigger in the JSON
+type TriggerData struct {
+	Event       string `json:"event" schema:"required,enum=item_taken|room_entered|fixture_used|lock_jammed"`
+	ItemName    string `json:"item_name,omitempty"`
+	RoomName    string `json:"room_name,omitempty"`
+	FixtureName string `json:"fixture_name,omitempty"`
+	LockName    string `json:"lock_name,omitempty"` // the door or container whose keypad jams
+}
+
+// LoadGame loads a game from JSON data
+// Loot tables are rolled with a random seed.
+// Returns the first validation error found, if any.
+func LoadGame(data json.RawMessage) (*world.Level, error) {
+	return LoadGameWithSeed(data, rand.Uint64())
+}
+
+// LoadGameWithSeed loads a game from JSON data like LoadGame,
+// rolling loot tables with the given seed so that the same seed always places the same loot.
+func LoadGameWithSeed(data json.RawMessage, seed uint64) (*world.Level, error) {
+	level, diagnostics := loadGame(data, seed)
+	if err := diagnostics.Err(); err != nil {
+		return nil, err
+	}
+	return level, nil
+}
+
+// LoadGameWithDiagnostics loads a game from JSON data like LoadGameWithSeed,
+// additionally returning non-fatal warnings about the level's content.
+// If the level fails to load, all diagnostics found are returned along with the first error.
+func LoadGameWithDiagnostics(data json.RawMessage, seed uint64) (*world.Level, Diagnostics, error) {
+	level, diagnostics := loadGame(data, seed)
+	if err := diagnostics.Err(); err != nil {
+		return nil, diagnostics, err
+	}
+	return level, diagnostics.Warnings(), nil
+}
+
+// loadGame loads a game from JSON data, collecting diagnostics along the way.
+// Returns a nil level if any error diagnostics were recorded.
+// Diagnostic paths always refer to the original, unmigrated document.
+func loadGame(data json.RawMessage, seed uint64) (*world.Level, Diagnostics) {
+	var diagnostics Diagnostics
+
+	// Sanity check the JSON structure first
+	if err := validateJSONStructure(data); err != nil {
+		diagnostics.addError("", fmt.Errorf("JSON structure validation failed: %w", err))
+		return nil, diagnostics
+	}
+
+	// Upgrade older level files to the current schema
+	migrated, rewritePath, err := migrate(data)
+	if err != nil {
+		diagnostics.addError("", fmt.Errorf("schema migration failed: %w", err))
+		return nil, diagnostics
+	}
+
+	// Expand item templates and roll loot before creating any items
+	var level *world.Level
+	if expanded, err := expandTemplates(migrated); err != nil {
+		diagnostics.addError("", fmt.Errorf("item template expansion failed: %w", err))
+	} else if rolled, err := resolveLoot(expanded, seed); err != nil {
+		diagnostics.addError("", fmt.Errorf("loot resolution failed: %w", err))
+	} else if localized, err := extractTranslations(rolled); err != nil {
+		diagnostics.addError("", fmt.Errorf("translation extraction failed: %w", err))
+	} else {
+		level, diagnostics = buildLevel(localized)
+	}
+	for i := range diagnostics {
+		diagnostics[i].Path = rewritePath(diagnostics[i].Path)
+	}
+	return level, diagnostics
+}
+
+// buildLevel creates a level from JSON data in the current schema version.
+func buildLevel(data json.RawMessage) (*world.Level, Diagnostics) {
+	var diagnostics Diagnostics
+
+	var gameData GameData
+	if err := json.Unmarshal(data, &gameData); err != nil {
+		diagnostics.addError("", fmt.Errorf("failed to parse JSON: %w", err))
+		return nil, diagnostics
+	}
+
+	paths := newLevelPaths()
+	diagnostics = append(diagnostics, validateUniqueNames(gameData)...)
+
+	// Create rooms map for easy lookup across all floors
+	roomsMap := make(map[string]*world.Room)
+
+	// Create floors
+	var floors []*world.Floor
+
+	for _, floorData := range gameData.Floors {
+		floor := &world.Floor{
+			Name:        floorData.Name,
+			Description: floorData.Description,
+			Rooms:       []*world.Room{},
+		}
+
+		// First pass: create all rooms for this floor
+		for _, roomData := range floorData.Rooms {
+			room := &world.Room{
+				BaseEntity: world.BaseEntity{
+					Name:        roomData.Name,
+					Description: roomData.Description,
+					ImageRef:    roomData.ImageRef,
+				},
+				InitialDescription: roomData.InitialDescription,
+				Connections:        []*world.Connection{},
+				Items:              []*world.Item{},
+				SavePoint:          roomData.SavePoint,
+				Ambient:            roomData.Ambient,
+				Airless:            roomData.Airless,
+				Burning:            roomData.Burning,
+			}
+			if roomData.Position != nil {
+				room.Position = &world.Position{X: roomData.Position.X, Y: roomData.Position.Y}
+			}
+			roomsMap[roomData.Name] = room
+			floor.Rooms = append(floor.Rooms, room)
+		}
+
+		floors = append(floors, floor)
+	}
+
+	// Create doors map for easy lookup
+	doorsMap := make(map[string]*world.Door)
+
+	// Second pass: create all doors
+	for i, doorData := range gameData.DoorData {
+		paths.doors[doorData.Name] = jsonPointer("doors", i)
+		var lock *world.Lock
+		if doorData.Locked {
+			lock = &world.Lock{
+				Locked:         true,
+				KeyName:        doorData.RequiredKeyName,
+				Code:           doorData.Code,
+				RequireLearned: doorData.RequireLearned,
+				MaxAttempts:    doorData.MaxAttempts,
+				Jammed:         doorData.Jammed,
+			}
+		}
+		keypad := keypadOptions{doorData.RequireLearned, doorData.MaxAttempts, doorData.Jammed}
+		if err := keypad.validate(doorData.Locked && doorData.Code != "", paths.doors[doorData.Name]); err != nil {
+			diagnostics.addError(paths.doors[doorData.Name], fmt.Errorf("invalid door %s: %w", doorData.Name, err))
+		}
+
+		var latch *world.Latch
+		if doorData.LatchedFrom != "" || doorData.TwoWayLatch {
+			latch = &world.Latch{
+				Locked:     doorData.LatchedFrom != "",
+				LockedFrom: doorData.LatchedFrom,
+				TwoWay:     doorData.TwoWayLatch,
+			}
+		}
+
+		door := &world.Door{
+			Name:      doorData.Name,
+			RoomA:     doorData.RoomA,
+			RoomB:     doorData.RoomB,
+			Lock:      lock,
+			Stairwell: doorData.Stairwell,
+			Barred:    doorData.Barred,
+			Latch:     latch,
+			Aliases:   doorData.Aliases,
+			Hidden:    doorData.Hidden,
+			OneWay:    doorData.OneWay,
+		}
+		if err := validateAliases(doorData.Aliases, paths.doors[doorData.Name]); err != nil {
+			diagnostics.addError(paths.doors[doorData.Name], fmt.Errorf("invalid door %s: %w", doorData.Name, err))
+		}
+		doorsMap[doorData.Name] = door
+	}
+
+	// Third pass: populate room connections and items
+	for i, floorData := range gameData.Floors {
+		for j, roomData := range floorData.Rooms {
+			roomPath := jsonPointer("floors", i, "rooms", j)
+			paths.rooms[roomData.Name] = roomPath
+			populateRoom(roomsMap[roomData.Name], roomData, roomPath, doorsMap, paths, &diagnostics)
+		}
+	}
+
+	validateDirections(gameData, &diagnostics)
+	validatePositions(gameData, &diagnostics)
+
+	// Create enemies
+	var enemies []*world.Enemy
+	for i, enemyData := range gameData.Enemies {
+		paths.enemies[enemyData.Name] = jsonPointer("enemies", i)
+		enemy := &world.Enemy{
+			BaseEntity: world.BaseEntity{
+				Name:        enemyData.Name,
+				Description: enemyData.Description,
+				ImageRef:    enemyData.ImageRef,
+			},
+			HP:            enemyData.HP,
+			Room:          enemyData.Room,
+			Intimidate:    enemyData.Intimidate,
+			Sneak:         enemyData.Sneak,
+			Bribes:        enemyData.Bribes,
+			Wanders:       enemyData.Wanders,
+			Morale:        enemyData.Morale,
+			Interrogation: enemyData.Interrogation,
+		}
+		if err := validateImageRef(enemyData.ImageRef, paths.enemies[enemyData.Name]); err != nil {
+			diagnostics.addError(paths.enemies[enemyData.Name], fmt.Errorf("invalid enemy %s: %w", enemyData.Name, err))
+		}
+		if _, exists := paths.rooms[enemyData.Room]; enemyData.Room != "" && !exists {
+			diagnostics.addError(paths.enemies[enemyData.Name]+jsonPointer("room"), fmt.Errorf("enemy %s is in unknown room %s", enemyData.Name, enemyData.Room))
+		}
+		if enemyData.Intimidate < 0 || enemyData.Intimidate > 1 {
+			diagnostics.addError(paths.enemies[enemyData.Name]+jsonPointer("intimidate"), fmt.Errorf("intimidate chance of enemy %s must be between 0 and 1", enemyData.Name))
+		}
+		if enemyData.Sneak < 0 || enemyData.Sneak > 1 {
+			diagnostics.addError(paths.enemies[enemyData.Name]+jsonPointer("sneak"), fmt.Errorf("sneak chance of enemy %s must be between 0 and 1", enemyData.Name))
+		}
+		for j, bribe := range enemyData.Bribes {
+			if _, exists := paths.items[bribe]; !exists {
+				diagnostics.addError(paths.enemies[enemyData.Name]+jsonPointer("bribes", j), fmt.Errorf("enemy %s is bribed with unknown item %q", enemyData.Name, bribe))
+			}
+		}
+		enemy.Carries = createSurrender(enemyData, paths, &diagnostics)
+		enemies = append(enemies, enemy)
+	}
+
+	// Create win condition event
+	var winCondition *world.Event
+	if gameData.WinCondition != nil {
+		var eventType world.EventType
+		switch gameData.WinCondition.Event {
+		case "room_entered":
+			eventType = world.EventRoomEntered
+		case "enemy_killed":
+			eventType = world.EventEnemyKilled
+		}
+
+		winCondition = &world.Event{
+			Event:     eventType,
+			RoomName:  gameData.WinCondition.RoomName,
+			EnemyName: gameData.WinCondition.EnemyName,
+		}
+	}
+
+	// Create triggers
+	// An enemy placed in a room without a trigger attacks when the player enters the room.
+	var triggers []*world.Trigger
+	for _, enemyData := range gameData.Enemies {
+		triggerData := enemyData.Trigger
+		if triggerData == nil && enemyData.Room != "" {
+			triggerData = &TriggerData{Event: "room_entered"}
+		}
+		if triggerData == nil {
+			continue
+		}
+		var eventType world.EventType
+		roomName := triggerData.RoomName
+		triggerPath := paths.enemies[enemyData.Name] + jsonPointer("trigger")
+		switch triggerData.Event {
+		case "item_taken":
+			eventType = world.EventItemTaken
+			if _, exists := paths.items[triggerData.ItemName]; !exists {
+				diagnostics.addError(triggerPath+jsonPointer("item_name"), fmt.Errorf("trigger of enemy %s refers to unknown item %q", enemyData.Name, triggerData.ItemName))
+			}
+		case "room_entered":
+			eventType = world.EventRoomEntered
+			if roomName == "" {
+				roomName = enemyData.Room
+			}
+			if _, exists := paths.rooms[roomName]; !exists {
+				diagnostics.addError(triggerPath+jsonPointer("room_name"), fmt.Errorf("trigger of enemy %s refers to unknown room %q", enemyData.Name, roomName))
+			}
+		case "fixture_used":
+			eventType = world.EventFixture
+			if _, exists := paths.items[triggerData.FixtureName]; !exists {
+				diagnostics.addError(triggerPath+jsonPointer("fixture_name"), fmt.Errorf("trigger of enemy %s refers to unknown fixture %q", enemyData.Name, triggerData.FixtureName))
+			}
+		case "lock_jammed":
+			eventType = world.EventLockJammed
+		}
+
+		trigger := world.Trigger{
+			Event: world.Event{
+				Event:       eventType,
+				ItemName:    triggerData.ItemName,
+				RoomName:    roomName,
+				FixtureName: triggerData.FixtureName,
+				LockName:    triggerData.LockName,
+			},
+			Effect: world.Effect{
+				EffectType: world.EffectEnterCombat,
+				EnemyName:  enemyData.Name,
+			},
+		}
+		triggers = append(triggers, &trigger)
+	}
+
+	// Convert doors map to slice
+	var doors []*world.Door
+	for _, door := range doorsMap {
+		doors = append(doors, door)
+	}
+
+	// Create combo items
+	var comboItems []*world.ComboItem
+	for i, comboItemData := range gameData.ComboItems {
+		paths.addItem(comboItemData.OutputItem, jsonPointer("combo_items", i, "output_item"))
+		outputItem, err := createItem(comboItemData.OutputItem, jsonPointer("combo_items", i, "output_item"))
+		if err != nil {
+			diagnostics.addError(jsonPointer("combo_items", i), fmt.Errorf("failed to create combo item output %s: %w", comboItemData.OutputItem.Name, err))
+			continue
+		}
+		inputNames, err := comboInputNames(comboItemData, jsonPointer("combo_items", i))
+		if err != nil {
+			diagnostics.addError(jsonPointer("combo_items", i), err)
+			continue
+		}
+
+		comboItem := &world.ComboItem{
+			InputItemAName: inputNames[0],
+			InputItemBName: inputNames[1],
+			MoreInputNames: inputNames[2:],
+			FixtureName:    comboItemData.Fixture,
+			OutputItem:     outputItem,
+			Known:          comboItemData.Known,
+			TaughtBy:       comboItemData.TaughtBy,
+		}
+		for j, byproductData := range comboItemData.Byproducts {
+			byproductPath := jsonPointer("combo_items", i, "byproducts", j)
+			paths.addItem(byproductData, byproductPath)
+			byproduct, err := createItem(byproductData, byproductPath)
+			if err != nil {
+				diagnostics.addError(byproductPath, fmt.Errorf("failed to create combo item byproduct %s: %w", byproductData.Name, err))
+				continue
+			}
+			comboItem.Byproducts = append(comboItem.Byproducts, byproduct)
+		}
+		comboItems = append(comboItems, comboItem)
+		paths.comboInputs = append(paths.comboInputs, comboInputPaths(comboItemData, jsonPointer("combo_items", i)))
+	}
+
+	// Create objectives
+	objectives, err := createObjectives(gameData.Objectives, paths)
+	if err != nil {
+		diagnostics.addError(jsonPointer("objectives"), fmt.Errorf("failed to create objectives: %w", err))
+	}
+
+	// Create triggers that reveal hidden doors, now that every item and enemy is known
+	for _, doorData := range gameData.DoorData {
+		if doorData.RevealOn == nil {
+			continue
+		}
+		path := paths.doors[doorData.Name] + jsonPointer("reveal_on")
+		if !doorData.Hidden {
+			diagnostics.addError(path, fmt.Errorf("door %s has reveal_on but is not hidden", doorData.Name))
+			continue
+		}
+		event, err := createEvent("reveal", *doorData.RevealOn, path, paths)
+		if err != nil {
+			diagnostics.addError(path, err)
+			continue
+		}
+		triggers = append(triggers, &world.Trigger{
+			Event: *event,
+			Effect: world.Effect{
+				EffectType: world.EffectRevealDoor,
+				TargetName: doorData.Name,
+			},
+		})
+	}
+
+	// Create conditional room descriptions, now that every item, enemy and door is known
+	for i, floorData := range gameData.Floors {
+		for j, roomData := range floorData.Rooms {
+			descriptions, err := createConditionalDescriptions(roomData.ConditionalDescriptions, jsonPointer("floors", i, "rooms", j), doorsMap, paths)
+			if err != nil {
+				diagnostics.addError(jsonPointer("floors", i, "rooms", j), fmt.Errorf("invalid room %s: %w", roomData.Name, err))
+				continue
+			}
+			roomsMap[roomData.Name].ConditionalDescriptions = descriptions
+		}
+	}
+
+	// Create scoring rules
+	scoring, err := createScoring(gameData.Scoring)
+	if err != nil {
+		diagnostics.addError(jsonPointer("scoring"), fmt.Errorf("failed to create scoring: %w", err))
+	}
+	if gameData.Breath < 0 {
+		diagnostics.addError(jsonPointer("breath"), fmt.Errorf("breath must not be negative"))
+	}
+	if gameData.RestEncounter < 0 || gameData.RestEncounter > 1 {
+		diagnostics.addError(jsonPointer("rest_encounter"), fmt.Errorf("rest_encounter must be between 0 and 1"))
+	}
+	switch world.CombatModel(gameData.Combat) {
+	case "", world.CombatProbabilistic, world.CombatDeterministic, world.CombatOpposed:
+	default:
+		diagnostics.addError(jsonPointer("combat"), fmt.Errorf("combat must be probabilistic, deterministic or opposed, not %s", gameData.Combat))
+	}
+	if err := validateVerbAliases(gameData.VerbAliases); err != nil {
+		diagnostics.addError(jsonPointer("verb_aliases"), fmt.Errorf("invalid verb aliases: %w", err))
+	}
+
+	// Create level
+	level := &world.Level{
+		Name:             gameData.Name,
+		Theme:            gameData.Theme,
+		IntroNarrative:   gameData.IntroNarrative,
+		OutroNarrative:   gameData.OutroNarrative,
+		FailureNarrative: gameData.FailureNarrative,
+		Floors:           floors,
+		Doors:            doors,
+		Enemies:          enemies,
+		Triggers:         triggers,
+		WinCondition:     winCondition,
+		Objectives:       objectives,
+		ComboItems:       comboItems,
+		Scoring:          scoring,
+		Breath:           gameData.Breath,
+		Combat:           world.CombatModel(gameData.Combat),
+		RestEncounter:    gameData.RestEncounter,
+		Language:         gameData.Language,
+		Translations:     gameData.Translations,
+		VerbAliases:      gameData.VerbAliases,
+	}
+	if level.Language == "" {
+		level.Language = world.DefaultLanguage
+	}
+	assignItemIDs(level)
+	level.Reindex()
+
+	// Validate reachability
+	if err := validateReachability(level); err != nil {
+		path := ""
+		if unreachable := findUnreachableRooms(level); len(unreachable) > 0 {
+			path = paths.rooms[unreachable[0]]
+		}
+		diagnostics.addError(path, fmt.Errorf("reachability validation failed: %w", err))
+	}
+
+	if diagnostics.HasErrors() {
+		return nil, diagnostics
+	}
+
+	// Validate that the win condition can be attained
+	diagnostics = append(diagnostics, validateKeypads(level, paths)...)
+	diagnostics = append(diagnostics, analyzeSolvability(level, paths)...)
+	diagnostics = append(diagnostics, collectWarnings(level, paths)...)
+	diagnostics = append(diagnostics, collectLocationWarnings(level, paths)...)
+	diagnostics = append(diagnostics, collectTranslationWarnings(level)...)
+	if diagnostics.HasErrors() {
+		return nil, diagnostics
+	}
+	return level, diagnostics
+}
+
+// populateRoom adds connections and items from the room data to a room.
+// Connections to unknown doors are skipped with a warning; invalid items are recorded as errors.
+func populateRoom(room *world.Room, roomData RoomData, roomPath string, doorsMap map[string]*world.Door, paths *levelPaths, diagnostics *Diagnostics) {
+	if err := validateImageRef(roomData.ImageRef, roomPath); err != nil {
+		diagnostics.addError(roomPath, fmt.Errorf("invalid room %s: %w", roomData.Name, err))
+	}
+	soundCues, err := createSoundCues(roomData.SoundCues, world.RoomSoundEvents, roomPath)
+	if err != nil {
+		diagnostics.addError(roomPath, fmt.Errorf("invalid room %s: %w", roomData.Name, err))
+	}
+	room.SoundCues = soundCues
+	room.SubLocations = createSubLocations(roomData, roomPath, diagnostics)
+
+	// Add connections
+	for i, conn := range roomData.Connections {
+		if _, exists := doorsMap[conn.DoorName]; !exists {
+			diagnostics.addWarning(roomPath+jsonPointer("connections", i, "door_name"),
+				"connection in room %s references unknown door %s", roomData.Name, conn.DoorName)
+			continue
+		}
+		direction := world.Direction(conn.Direction)
+		if direction != "" && !direction.IsValid() {
+			diagnostics.addError(roomPath+jsonPointer("connections", i, "direction"),
+				fmt.Errorf("invalid direction %s for door %s in room %s", conn.Direction, conn.DoorName, roomData.Name))
+			continue
+		}
+		// Connections with a direction can be traversed by it without an explicit location
+		location := conn.Location
+		if location == "" {
+			location = conn.Direction
+		}
+		connection := &world.Connection{
+			DoorName:    conn.DoorName,
+			Location:    location,
+			Direction:   direction,
+			Description: conn.Description,
+		}
+		room.Connections = append(room.Connections, connection)
+	}
+
+	if nodeData := roomData.TravelNode; nodeData != nil {
+		nodePath := roomPath + jsonPointer("travel_node")
+		if nodeData.Name == "" {
+			diagnostics.addError(nodePath+jsonPointer("name"), fmt.Errorf("travel node in room %s must have a name", roomData.Name))
+		} else if _, exists := paths.travelNodes[nodeData.Name]; exists {
+			diagnostics.addError(nodePath+jsonPointer("name"), fmt.Errorf("duplicate travel node %s", nodeData.Name))
+		} else {
+			paths.travelNodes[nodeData.Name] = nodePath
+			room.TravelNode = &world.TravelNode{
+				Name:        nodeData.Name,
+				Description: nodeData.Description,
+				Network:     nodeData.Network,
+				Unpowered:   nodeData.Unpowered,
+				Discovered:  nodeData.Discovered,
+			}
+		}
+	}
+
+	// Add items
+	for i, itemData := range roomData.Items {
+		itemPath := roomPath + jsonPointer("items", i)
+		paths.addItem(itemData, itemPath)
+		item, err := createItem(itemData, itemPath)
+		if err != nil {
+			diagnostics.addError(itemPath, fmt.Errorf("failed to create item %s: %w", itemData.Name, err))
+			continue
+		}
+		placeItem(room, item, itemData, itemPath, diagnostics)
+		room.Items = append(room.Items, item)
+	}
+}
+
+// createSurrender checks an enemy's morale and what it gives up once it surrenders, returning
+// the item it carries, if any. Loot and answers of an enemy that never surrenders are only
+// warned about, as they do no harm.
+func createSurrender(enemyData EnemyData, paths *levelPaths, diagnostics *Diagnostics) *world.Item {
+	enemyPath := paths.enemies[enemyData.Name]
+	if enemyData.Morale < 0 || enemyData.Morale > 0 && enemyData.Morale >= enemyData.HP {
+		diagnostics.addError(enemyPath+jsonPointer("morale"), fmt.Errorf("morale of enemy %s must be between 0 and its hp %d", enemyData.Name, enemyData.HP))
+	}
+	if enemyData.Morale == 0 && enemyData.Interrogation != "" {
+		diagnostics.addWarning(enemyPath+jsonPointer("interrogation"), "enemy %s never surrenders, so it can't be questioned", enemyData.Name)
+	}
+	if enemyData.Carries == nil {
+		return nil
+	}
+	carriesPath := enemyPath + jsonPointer("carries")
+	if enemyData.Morale == 0 {
+		diagnostics.addWarning(carriesPath, "enemy %s never surrenders, so the %s it carries can't be found", enemyData.Name, enemyData.Carries.Name)
+	}
+	paths.addItem(*enemyData.Carries, carriesPath)
+	checkNotSubLocated(*enemyData.Carries, carriesPath, diagnostics)
+	item, err := createItem(*enemyData.Carries, carriesPath)
+	if err != nil {
+		diagnostics.addError(carriesPath, fmt.Errorf("failed to create item %s carried by enemy %s: %w", enemyData.Carries.Name, enemyData.Name, err))
+		return nil
+	}
+	if !item.IsPortable() {
+		diagnostics.addError(carriesPath, fmt.Errorf("item %s carried by enemy %s must be portable", item.Name, enemyData.Name))
+		return nil
+	}
+	item.Location = nestedLocation(item.Location, "at the feet of", enemyData.Name)
+	return item
+}
+
+// LoadGameFromFile loads a game from a JSON or YAML file
+func LoadGameFromFile(filename string) (*world.Level, error) {
+	data, err := ReadLevelFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return LoadGame(data)
+}
+
+// ReadLevelFile reads a level from a JSON or YAML file, returning it as JSON
+func ReadLevelFile(filename string) (json.RawMessage, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// Check if file is YAML based on extension
+	if strings.HasSuffix(strings.ToLower(filename), ".yaml") || strings.HasSuffix(strings.ToLower(filename), ".yml") {
+		// Convert YAML to JSON
+		var v any
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+
+		// Convert to JSON
+		jsonData, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert YAML to JSON: %w", err)
+		}
+
+		return jsonData, nil
+	}
+
+	// Treat as JSON
+	return data, nil
+}
+
+// validateJSONStructure performs a sanity check on the JSON input structure
+func validateJSONStructure(data json.RawMessage) error {
+	// Parse the JSON into a generic map to check structure
+	var jsonMap map[string]interface{}
+	if err := json.Unmarshal(data, &jsonMap); err != nil {
+		return &ValidationError{Err: fmt.Errorf("invalid JSON format: %w", err)}
+	}
+
+	// Check for required fields
+	requiredFields := []string{"name"}
+	for _, field := range requiredFields {
+		if _, exists := jsonMap[field]; !exists {
+			return newValidationError(jsonPointer(field), "missing required field: %s", field)
+		}
+	}
+
+	// Check for either floors or rooms (for backward compatibility)
+	if _, hasFloors := jsonMap["floors"]; !hasFloors {
+		if _, hasRooms := jsonMap["rooms"]; !hasRooms {
+			return newValidationError("", "missing required field: either 'floors' or 'rooms'")
+		}
+	}
+
+	// Check for optional fields (these are allowed but not required)
+	optionalFields := []string{"win_condition", "doors", "enemies", "system_prompt_theme", "combo_items", "intro_narrative", "outro_narrative", "failure_narrative", "scoring", "schema_version", "item_templates", "loot_tables", "objectives", "language", "translations", "breath", "combat", "rest_encounter", "verb_aliases"}
+
+	// Check for any unexpected fields
+	allowedFields := make(map[string]bool)
+	for _, field := range requiredFields {
+		allowedFields[field] = true
+	}
+	allowedFields["floors"] = true
+	allowedFields["rooms"] = true // For backward compatibility
+	for _, field := range optionalFields {
+		allowedFields[field] = true
+	}
+
+	for field := range jsonMap {
+		if !allowedFields[field] {
+			return newValidationError(jsonPointer(field), "unexpected field: %s (allowed fields: %v)", field, getSortedKeys(allowedFields))
+		}
+	}
+
+	// Validate that required fields have the correct types
+	if name, ok := jsonMap["name"].(string); !ok || name == "" {
+		return newValidationError(jsonPointer("name"), "field 'name' must be a non-empty string")
+	}
+
+	// Validate floors field if present
+	if floors, ok := jsonMap["floors"].([]interface{}); ok {
+		if len(floors) == 0 {
+			return newValidationError(jsonPointer("floors"), "field 'floors' must be a non-empty array")
+		}
+	}
+
+	// Validate rooms field if present (for backward compatibility)
+	if rooms, ok := jsonMap["rooms"].([]interface{}); ok {
+		if len(rooms) == 0 {
+			return newValidationError(jsonPointer("rooms"), "field 'rooms' must be a non-empty array")
+		}
+	}
+
+	return nil
+}
+
+// getSortedKeys returns the keys of a map as a sorted slice
+func getSortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validateReachability checks that the level has rooms to search and that
+// all rooms in the level are reachable from each other
+func validateReachability(level *world.Level) error {
+	if len(level.Floors) == 0 {
+		return fmt.Errorf("level has no floors")
+	}
+	for _, floor := range level.Floors {
+		if len(floor.Rooms) == 0 {
+			return fmt.Errorf("floor %s has no rooms", floor.Name)
+		}
+	}
+	if unreachableRooms := findUnreachableRooms(level); len(unreachableRooms) > 0 {
+		return fmt.Errorf("unreachable rooms found: %v", unreachableRooms)
+	}
+	return nil
+}
+
+// findUnreachableRooms returns the names of rooms that cannot be reached
+// by performing a breadth-first search starting from the first room.
+// One-way doors are only followed in the direction they can be gone through, and travel
+// nodes only to discovered nodes.
+func findUnreachableRooms(level *world.Level) []string {
+	// Collect all rooms from all floors
+	var allRooms []*world.Room
+	for _, floor := range level.Floors {
+		allRooms = append(allRooms, floor.Rooms...)
+	}
+	if len(allRooms) == 0 {
+		return nil
+	}
+
+	travelNodes := level.TravelNodes()
+
+	// Use BFS to find all reachable rooms
+	visited := make(map[string]bool)
+	queue := []string{allRooms[0].Name} // Start from the first room
+	visited[allRooms[0].Name] = true
+
+	for len(queue) > 0 {
+		currentRoomName := queue[0]
+		queue = queue[1:]
+
+		_, currentRoom := level.FindRoom(currentRoomName)
+		if currentRoom == nil {
+			// Door leads to a room that doesn't exist
+			continue
+		}
+
+		// Check all connections from this room
+		for _, conn := range currentRoom.Connections {
+			if !level.HasDoor(conn.DoorName) {
+				continue
+			}
+			door := level.GetDoor(conn.DoorName)
+			if !door.CanTraverseFrom(currentRoomName) {
+				continue
+			}
+
+			// Determine the other room connected by this door
+			var otherRoomName string
+			if door.RoomA == currentRoomName {
+				otherRoomName = door.RoomB
+			} else {
+				otherRoomName = door.RoomA
+			}
+
+			// If we haven't visited this room yet, add it to the queue
+			if !visited[otherRoomName] {
+				visited[otherRoomName] = true
+				queue = append(queue, otherRoomName)
+			}
+		}
+
+		// Travel nodes lead to the nodes on their network the player knows of without visiting
+		if node := currentRoom.TravelNode; node != nil {
+			for _, room := range travelNodes {
+				if room.TravelNode.Network == node.Network && room.TravelNode.Discovered && !visited[room.Name] {
+					visited[room.Name] = true
+					queue = append(queue, room.Name)
+				}
+			}
+		}
+	}
+
+	// Check if all rooms were visited
+	var unreachableRooms []string
+	for _, room := range allRooms {
+		if !visited[room.Name] {
+			unreachableRooms = append(unreachableRooms, room.Name)
+		}
+	}
+
+	return unreachableRooms
+}
+
+// validateAliases checks that the aliases of the item or door at path are non-empty and distinct.
+func validateAliases(aliases []string, path string) error {
+	seen := make(map[string]bool)
+	for i, alias := range aliases {
+		aliasPath := path + jsonPointer("aliases", i)
+		normalized := strings.ToLower(strings.TrimSpace(alias))
+		if normalized == "" {
+			return newValidationError(aliasPath, "alias must not be empty")
+		}
+		if seen[normalized] {
+			return newValidationError(aliasPath, "duplicate alias %s", alias)
+		}
+		seen[normalized] = true
+	}
+	return nil
+}
+
+// validateVerbAliases checks that verb aliases are non-empty, distinct ignoring case and
+// stand for a command.
+func validateVerbAliases(aliases map[string]string) error {
+	seen := make(map[string]bool)
+	for _, alias := range sortedKeys(aliases) {
+		aliasPath := jsonPointer("verb_aliases", alias)
+		normalized := strings.Join(strings.Fields(strings.ToLower(alias)), " ")
+		if normalized == "" {
+			return newValidationError(aliasPath, "alias must not be empty")
+		}
+		if seen[normalized] {
+			return newValidationError(aliasPath, "duplicate alias %s", alias)
+		}
+		seen[normalized] = true
+		if strings.TrimSpace(aliases[alias]) == "" {
+			return newValidationError(aliasPath, "alias %s must stand for a command", alias)
+		}
+	}
+	return nil
+}
+
+// validateImageRef checks that an image reference is an http(s) URL or a relative path,
+// which clients resolve against wherever they host the level's art
+func validateImageRef(imageRef string, path string) error {
+	if imageRef == "" {
+		return nil
+	}
+	refPath := path + jsonPointer("image_ref")
+	if strings.TrimSpace(imageRef) != imageRef {
+		return newValidationError(refPath, "image reference %q must not have surrounding whitespace", imageRef)
+	}
+	ref, err := url.Parse(imageRef)
+	if err != nil {
+		return newValidationError(refPath, "invalid image reference %q: %v", imageRef, err)
+	}
+	switch ref.Scheme {
+	case "":
+		if ref.Path == "" {
+			return newValidationError(refPath, "image reference %q has no path", imageRef)
+		}
+	case "http", "https":
+		if ref.Host == "" {
+			return newValidationError(refPath, "image URL %q has no host", imageRef)
+		}
+	default:
+		return newValidationError(refPath, "image reference %q must be an http or https URL or a relative path", imageRef)
+	}
+	return nil
+}
+
+// createSoundCues converts sound cues, checking that each is for one of the given events
+func createSoundCues(cues map[string]string, events []world.SoundEvent, path string) (world.SoundCues, error) {
+	if len(cues) == 0 {
+		return nil, nil
+	}
+	soundCues := make(world.SoundCues, len(cues))
+	for _, event := range sortedKeys(cues) {
+		cuePath := path + jsonPointer("sound_cues", event)
+		if !slices.Contains(events, world.SoundEvent(event)) {
+			return nil, newValidationError(cuePath, "invalid sound cue event %s (allowed events: %v)", event, events)
+		}
+		if strings.TrimSpace(cues[event]) == "" {
+			return nil, newValidationError(cuePath, "sound for %s must not be empty", event)
+		}
+		soundCues[world.SoundEvent(event)] = cues[event]
+	}
+	return soundCues, nil
+}
+
+// comboInputPaths returns the paths of a recipe's inputs.
+func comboInputPaths(comboItemData ComboItemData, path string) []string {
+	if comboItemData.InputItemNames == nil {
+		return []string{path + jsonPointer("input_item_a_name"), path + jsonPointer("input_item_b_name")}
+	}
+	inputPaths := make([]string, len(comboItemData.InputItemNames))
+	for i := range inputPaths {
+		inputPaths[i] = path + jsonPointer("input_item_names", i)
+	}
+	return inputPaths
+}
+
+// comboInputNames returns the inputs a recipe names, checking there are two to four
+// different ones given one way or the other.
+func comboInputNames(comboItemData ComboItemData, path string) ([]string, error) {
+	if comboItemData.InputItemNames == nil {
+		if comboItemData.InputItemAName == "" || comboItemData.InputItemBName == "" {
+			return nil, newValidationError(path, "combo item %s needs input_item_a_name and input_item_b_name, or input_item_names", comboItemData.OutputItem.Name)
+		}
+		comboItemData.InputItemNames = []string{comboItemData.InputItemAName, comboItemData.InputItemBName}
+	} else if comboItemData.InputItemAName != "" || comboItemData.InputItemBName != "" {
+		return nil, newValidationError(path+jsonPointer("input_item_names"), "combo item %s names its inputs twice, use either input_item_names or input_item_a_name and input_item_b_name", comboItemData.OutputItem.Name)
+	}
+
+	names := comboItemData.InputItemNames
+	if len(names) < 2 || len(names) > world.MaxComboInputs {
+		return nil, newValidationError(path+jsonPointer("input_item_names"), "combo item %s must have 2 to %d inputs, not %d", comboItemData.OutputItem.Name, world.MaxComboInputs, len(names))
+	}
+	for i, name := range names {
+		if slices.Contains(names[:i], name) {
+			return nil, newValidationError(path+jsonPointer("input_item_names", i), "combo item %s uses input %s more than once", comboItemData.OutputItem.Name, name)
+		}
+	}
+	return names, nil
+}
+
+// createItem recursively creates an item and its nested items
+// The path is the JSON pointer to the item, used to locate validation errors.
+func createItem(itemData ItemData, path string) (*world.Item, error) {
+	item := &world.Item{
+		BaseEntity: world.BaseEntity{
+			Name:        itemData.Name,
+			Description: itemData.Description,
+			ImageRef:    itemData.ImageRef,
+		},
+		ID:          itemData.ID,
+		Location:    normalizeLocation(itemData.Location),
+		SubLocation: itemData.SubLocation,
+		Detail:      itemData.Detail,
+		Secret:      itemData.Secret,
+		QuestItem:   itemData.QuestItem,
+		Aliases:     itemData.Aliases,
+	}
+	if err := validateAliases(itemData.Aliases, path); err != nil {
+		return nil, err
+	}
+	if err := validateLocation(item.Location, path); err != nil {
+		return nil, err
+	}
+	if err := validateImageRef(itemData.ImageRef, path); err != nil {
+		return nil, err
+	}
+	soundCues, err := createSoundCues(itemData.SoundCues, world.ItemSoundEvents, path)
+	if err != nil {
+		return nil, err
+	}
+	item.SoundCues = soundCues
+
+	// Handle portable items
+	if itemData.Portable {
+		item.Portable = &world.Portable{}
+	}
+
+	// Handle keys
+	if itemData.Key {
+		item.Key = &world.Key{}
+		// Keys are always portable
+		if item.Portable == nil {
+			item.Portable = &world.Portable{}
+		}
+	}
+
+	// Handle weapons
+	if itemData.WeaponDamage > 0 {
+		// Weapons that name their ammo type use ammo, even if they come unloaded, and those that
+		// don't fire ammo of their own
+		var ammo *world.Ammo
+		ammoType := itemData.AmmoType
+		if itemData.Ammo > 0 || ammoType != "" {
+			ammo = &world.Ammo{
+				Quantity: max(itemData.Ammo, 0),
+			}
+			if ammoType == "" {
+				ammoType = itemData.Name
+			}
+		}
+
+		item.Weapon = &world.Weapon{
+			Damage:   itemData.WeaponDamage,
+			Ammo:     ammo,
+			AmmoType: ammoType,
+		}
+		if itemData.Thrown != nil {
+			if ammo != nil {
+				return nil, newValidationError(path+jsonPointer("thrown"), "thrown weapon %s cannot use ammo", itemData.Name)
+			}
+			item.Weapon.Thrown = &world.Thrown{
+				Area:       itemData.Thrown.Area,
+				StartsFire: itemData.Thrown.StartsFire,
+			}
+		}
+		// Weapons are always portable
+		if item.Portable == nil {
+			item.Portable = &world.Portable{}
+		}
+	}
+
+	if itemData.Thrown != nil && item.Weapon == nil {
+		return nil, newValidationError(path+jsonPointer("thrown"), "item %s is thrown but is not a weapon, give it a weapon_damage", itemData.Name)
+	}
+
+	// Handle critical hits and fumbles, a critical hit costing the enemy one extra hit point
+	// unless it says otherwise
+	if critical := itemData.Critical; critical != nil {
+		if item.Weapon == nil {
+			return nil, newValidationError(path+jsonPointer("critical"), "item %s has critical hits but is not a weapon, give it a weapon_damage", itemData.Name)
+		}
+		if critical.Chance < 0 || critical.Chance > 1 {
+			return nil, newValidationError(path+jsonPointer("critical", "chance"), "chance must be between 0 and 1")
+		}
+		if critical.Fumble < 0 || critical.Fumble > 1 {
+			return nil, newValidationError(path+jsonPointer("critical", "fumble"), "fumble must be between 0 and 1")
+		}
+		if critical.Damage < 0 {
+			return nil, newValidationError(path+jsonPointer("critical", "damage"), "damage must not be negative")
+		}
+		if critical.Fumble > 0 && item.Weapon.IsThrown() {
+			return nil, newValidationError(path+jsonPointer("critical", "fumble"), "thrown weapon %s cannot be fumbled", itemData.Name)
+		}
+		damage := critical.Damage
+		if damage == 0 {
+			damage = 1
+		}
+		item.Weapon.Critical = &world.Critical{
+			Chance:          critical.Chance,
+			Damage:          damage,
+			Narrative:       critical.Narrative,
+			Fumble:          critical.Fumble,
+			FumbleNarrative: critical.FumbleNarrative,
+		}
+	}
+
+	// Handle armor, which stops every hit unless it gives a chance of doing so
+	if itemData.Armor != nil {
+		if itemData.Armor.Slot == "" {
+			return nil, newValidationError(path+jsonPointer("armor", "slot"), "armor %s needs a slot", itemData.Name)
+		}
+		block := itemData.Armor.Block
+		if block == 0 {
+			block = 1
+		}
+		if block < 0 || block > 1 {
+			return nil, newValidationError(path+jsonPointer("armor", "block"), "block must be between 0 and 1")
+		}
+		item.Armor = &world.Armor{
+			Slot:  itemData.Armor.Slot,
+			Block: block,
+		}
+		// Armor is always portable
+		if item.Portable == nil {
+			item.Portable = &world.Portable{}
+		}
+	}
+
+	// Handle health items
+	if itemData.HealthEffect != "" {
+		var healthEffect world.HealthEffect
+		switch itemData.HealthEffect {
+		case "weak":
+			healthEffect = world.HealthBoostWeak
+		case "strong":
+			healthEffect = world.HealthBoostStrong
+		}
+
+		item.HealthItem = &world.HealthItem{
+			HealthEffect: healthEffect,
+		}
+		// Health items are always portable
+		if item.Portable == nil {
+			item.Portable = &world.Portable{}
+		}
+	}
+
+	item.CustomComponents = maps.Clone(itemData.CustomComponents)
+
+	// Handle air supplies, which are carried to be breathed from
+	if itemData.AirSupply {
+		item.AirSupply = &world.AirSupply{}
+		if item.Portable == nil {
+			item.Portable = &world.Portable{}
+		}
+	}
+
+	// Handle ammo boxes
+	if itemData.AmmoType != "" && itemData.Ammo > 0 && item.Weapon == nil {
+		item.AmmoBox = &world.AmmoBox{
+			AmmoType: itemData.AmmoType,
+			Ammo: &world.Ammo{
+				Quantity: itemData.Ammo,
+			},
+		}
+		// Ammo boxes are always portable
+		if item.Portable == nil {
+			item.Portable = &world.Portable{}
+		}
+	}
+
+	// Handle containers
+	if itemData.Contains != nil {
+		var contains *world.Item
+		if itemData.Contains.Empty {
+			// Empty container
+			contains = nil
+		} else if itemData.Contains.Item != nil {
+			// Container with item
+			var err error
+			contains, err = createItem(*itemData.Contains.Item, path+jsonPointer("contains"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create contained item: %w", err)
+			}
+			contains.Location = nestedLocation(contains.Location, "in", item.Name)
+		}
+
+		var lock *world.Lock
+		if itemData.Code != "" || itemData.RequiredKeyName != "" {
+			lock = &world.Lock{
+				Locked:         true,
+				KeyName:        itemData.RequiredKeyName,
+				Code:           itemData.Code,
+				RequireLearned: itemData.RequireLearned,
+				MaxAttempts:    itemData.MaxAttempts,
+				Jammed:         itemData.Jammed,
+			}
+		}
+
+		item.Container = &world.Container{
+			Contains: contains,
+			Searched: false,
+			Locked:   lock,
+		}
+	}
+
+	keypad := keypadOptions{itemData.RequireLearned, itemData.MaxAttempts, itemData.Jammed}
+	if err := keypad.validate(itemData.Contains != nil && itemData.Code != "", path); err != nil {
+		return nil, fmt.Errorf("invalid item %s: %w", itemData.Name, err)
+	}
+
+	// Handle concealers
+	if itemData.Conceals != nil {
+		hidden, err := createItem(*itemData.Conceals, path+jsonPointer("conceals"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create concealed item: %w", err)
+		}
+		hidden.Location = nestedLocation(hidden.Location, "under", item.Name)
+		item.Concealer = &world.Concealer{
+			Hidden:    hidden,
+			Uncovered: false,
+		}
+	}
+
+	// Handle fixtures
+	if itemData.Fixture != nil {
+		fixture, err := createFixture(itemData, path)
+		if err != nil {
+			return nil, err
+		}
+		item.Fixture = fixture
+	}
+
+	// Handle moveable furniture
+	if itemData.Moveable != nil {
+		moveable := &world.Moveable{
+			Directions:  itemData.Moveable.Directions,
+			RevealsDoor: itemData.Moveable.RevealsDoor,
+			Narrative:   itemData.Moveable.Narrative,
+			Moved:       itemData.Moveable.Moved,
+		}
+		if itemData.Moveable.Reveals != nil {
+			revealed, err := createItem(*itemData.Moveable.Reveals, path+jsonPointer("moveable", "reveals"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create item behind %s: %w", itemData.Name, err)
+			}
+			revealed.Location = nestedLocation(revealed.Location, "behind", item.Name)
+			moveable.Reveals = revealed
+		}
+		item.Moveable = moveable
+	}
+
+	// Handle items that wear out
+	if itemData.Durability != 0 || itemData.Wear != 0 || itemData.Scrap != nil {
+		durability, err := createDurability(itemData, path)
+		if err != nil {
+			return nil, err
+		}
+		item.Durability = durability
+	}
+
+	// Handle items with several uses
+	if itemData.Charges != 0 || itemData.ChargesUsed != 0 {
+		charges, err := createCharges(item, itemData, path)
+		if err != nil {
+			return nil, err
+		}
+		item.Charges = charges
+	}
+
+	// Handle trapped items
+	if itemData.Trap != nil {
+		trap, err := createTrap(item, *itemData.Trap, path+jsonPointer("trap"))
+		if err != nil {
+			return nil, err
+		}
+		item.Trap = trap
+	}
+
+	// Handle stacks of identical items
+	if itemData.Quantity != 0 {
+		if itemData.Quantity < 1 {
+			return nil, newValidationError(path+jsonPointer("quantity"), "quantity must be positive")
+		}
+		if !item.IsStackable() {
+			return nil, newValidationError(path+jsonPointer("quantity"), "item %s cannot be stacked, only portable items that don't wear out, have no charges or trap and are not weapons, other than thrown ones, can", item.Name)
+		}
+		item.Portable.Quantity = itemData.Quantity
+	}
+
+	// Quest items are kept safe from being given away or crafted with, but not from wearing out,
+	// being thrown or being used up, so those can't be quest items
+	if item.QuestItem {
+		switch {
+		case !item.IsPortable():
+			return nil, newValidationError(path+jsonPointer("quest_item"), "quest item %s must be portable", item.Name)
+		case item.Durability != nil || item.IsWeapon() && item.Weapon.IsThrown():
+			return nil, newValidationError(path+jsonPointer("quest_item"), "quest item %s can't wear out or be thrown", item.Name)
+		case item.IsHealthItem() || item.IsAirSupply() || item.Charges != nil:
+			return nil, newValidationError(path+jsonPointer("quest_item"), "quest item %s can't be used up, so it can't heal, supply air or have charges", item.Name)
+		}
+	}
+
+	// Validate the item's initial state
+	if err := item.ValidateInitialState(); err != nil {
+		return nil, newValidationError(path, "invalid item %s: %w", item.Name, err)
+	}
+
+	return item, nil
+}
+
+// createDurability creates the durability of a weapon or tool that wears out.
+func createDurability(itemData ItemData, path string) (*world.Durability, error) {
+	if itemData.Durability <= 0 {
+		return nil, newValidationError(path+jsonPointer("durability"), "durability must be positive for an item with wear or scrap")
+	}
+	if itemData.Wear < 0 || itemData.Wear > itemData.Durability {
+		return nil, newValidationError(path+jsonPointer("wear"), "wear must be between 0 and the durability %d", itemData.Durability)
+	}
+	durability := &world.Durability{Max: itemData.Durability, Used: itemData.Wear}
+	if itemData.Scrap != nil {
+		scrap, err := createItem(*itemData.Scrap, path+jsonPointer("scrap"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create scrap of %s: %w", itemData.Name, err)
+		}
+		durability.Scrap = scrap
+	}
+	return durability, nil
+}
+
+// createCharges creates the charges of an item with several uses. Charges are for portable
+// items used up by healing, breathing or using them on fixtures, so not for weapons, keys or
+// items that wear out instead.
+func createCharges(item *world.Item, itemData ItemData, path string) (*world.Charges, error) {
+	switch {
+	case itemData.Charges <= 0:
+		return nil, newValidationError(path+jsonPointer("charges"), "charges must be positive for an item with charges used")
+	case itemData.ChargesUsed < 0 || itemData.ChargesUsed >= itemData.Charges:
+		return nil, newValidationError(path+jsonPointer("charges_used"), "charges used must be between 0 and %d, as an item with no charges left is gone", itemData.Charges-1)
+	case !item.IsPortable() || item.IsWeapon() || item.IsKey() || item.Durability != nil:
+		return nil, newValidationError(path+jsonPointer("charges"), "item %s can't have charges, only portable items that aren't weapons or keys and don't wear out can", item.Name)
+	}
+	return &world.Charges{Max: itemData.Charges, Used: itemData.ChargesUsed}, nil
+}
+
+// createTrap creates the trap on a portable item. Damage and poison effects name the trapped
+// item, for post-mortems of the players it kills.
+func createTrap(item *world.Item, trapData TrapData, path string) (*world.Trap, error) {
+	if !item.IsPortable() {
+		return nil, newValidationError(path, "item %s can't be trapped, only portable items can", item.Name)
+	}
+	trap := &world.Trap{On: trapData.On, Narrative: trapData.Narrative, Sprung: trapData.Sprung}
+	switch trap.On {
+	case "":
+		trap.On = world.TrapOnTake
+	case world.TrapOnTake, world.TrapOnUse:
+	default:
+		return nil, newValidationError(path+jsonPointer("on"), "trap must spring on take or use, not %s", trapData.On)
+	}
+	if len(trapData.Effects) == 0 {
+		return nil, newValidationError(path+jsonPointer("effects"), "trap on %s must have at least one effect", item.Name)
+	}
+	for i, effectData := range trapData.Effects {
+		effectPath := path + jsonPointer("effects", i)
+		effect := world.Effect{EffectType: world.EffectType(effectData.Effect)}
+		if effect.IsCustom() {
+			effect.TargetName = effectData.Target
+			effect.Params = effectData.Params
+			trap.Effects = append(trap.Effects, effect)
+			continue
+		}
+		if effectData.Params != nil {
+			return nil, newValidationError(effectPath+jsonPointer("params"), "only custom effects take params")
+		}
+		switch effect.EffectType {
+		case world.EffectDamage, world.EffectPoison:
+			if effectData.Target != "" {
+				return nil, newValidationError(effectPath+jsonPointer("target"), "effect %s of the trap on %s takes no target", effectData.Effect, item.Name)
+			}
+			effect.TargetName = item.Name
+		case world.EffectEnterCombat:
+			if effectData.Target == "" {
+				return nil, newValidationError(effectPath+jsonPointer("target"), "effect enter_combat of the trap on %s must name an enemy", item.Name)
+			}
+			effect.EnemyName = effectData.Target
+		default:
+			return nil, newValidationError(effectPath+jsonPointer("effect"), "trap effect must be damage, poison, enter_combat or custom:<name>, not %s", effectData.Effect)
+		}
+		trap.Effects = append(trap.Effects, effect)
+	}
+	return trap, nil
+}
+
+// createFixture creates an item's fixture, with all its required items initially missing.
+func createFixture(itemData ItemData, path string) (*world.Fixture, error) {
+	fixtureData := itemData.Fixture
+	path += jsonPointer("fixture")
+	fixture := &world.Fixture{
+		RequiredItems:       make(map[string]bool),
+		RevealMissing:       world.MissingItemsHint(fixtureData.RevealMissing),
+		CompletionNarrative: fixtureData.CompletionNarrative,
+	}
+	switch fixture.RevealMissing {
+	case "", world.MissingItemsShown, world.MissingItemsCount, world.MissingItemsHidden:
+	default:
+		return nil, newValidationError(path+jsonPointer("reveal_missing"), "reveal_missing must be items, count or none, not %s", fixtureData.RevealMissing)
+	}
+
+	addRequired := func(itemName string, itemPath string) error {
+		if _, exists := fixture.RequiredItems[itemName]; exists {
+			return newValidationError(itemPath, "fixture %s requires item %s more than once", itemData.Name, itemName)
+		}
+		fixture.RequiredItems[itemName] = false
+		return nil
+	}
+	if len(fixtureData.Stages) > 0 {
+		if len(fixtureData.RequiredItems) > 0 {
+			return nil, newValidationError(path+jsonPointer("stages"), "fixture %s lists required_items as well as stages", itemData.Name)
+		}
+		for i, stageData := range fixtureData.Stages {
+			if len(stageData.RequiredItems) == 0 {
+				return nil, newValidationError(path+jsonPointer("stages", i, "required_items"), "stage %d of fixture %s requires no items", i, itemData.Name)
+			}
+			for j, itemName := range stageData.RequiredItems {
+				if err := addRequired(itemName, path+jsonPointer("stages", i, "required_items", j)); err != nil {
+					return nil, err
+				}
+			}
+			fixture.Stages = append(fixture.Stages, world.FixtureStage{RequiredItems: stageData.RequiredItems, Narrative: stageData.Narrative})
+		}
+	}
+	for i, itemName := range fixtureData.RequiredItems {
+		if err := addRequired(itemName, path+jsonPointer("required_items", i)); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, insertData := range fixtureData.InsertNarratives {
+		if _, exists := fixture.RequiredItems[insertData.Item]; !exists {
+			return nil, newValidationError(path+jsonPointer("insert_narratives", i, "item"), "fixture %s does not require item %s", itemData.Name, insertData.Item)
+		}
+		if fixture.InsertNarratives == nil {
+			fixture.InsertNarratives = make(map[string]string)
+		}
+		fixture.InsertNarratives[insertData.Item] = insertData.Narrative
+	}
+
+	if fixtureData.Produces != nil {
+		producedItem, err := createItem(*fixtureData.Produces, path+jsonPointer("produces"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create produced item for fixture %s: %w", itemData.Name, err)
+		}
+		fixture.Produces = producedItem
+	}
+
+	for i, effectData := range fixtureData.OnComplete {
+		effectPath := path + jsonPointer("on_complete", i)
+		effect := world.Effect{EffectType: world.EffectType(effectData.Effect)}
+		if effect.IsCustom() {
+			// Custom effects are run by plugins, which decide what their target and params mean
+			effect.TargetName = effectData.Target
+			effect.Params = effectData.Params
+			fixture.OnComplete = append(fixture.OnComplete, effect)
+			continue
+		}
+		if effectData.Params != nil {
+			return nil, newValidationError(effectPath+jsonPointer("params"), "only custom effects take params")
+		}
+		switch effect.EffectType {
+		case world.EffectUnlock, world.EffectRevealDoor, world.EffectPower:
+			effect.TargetName = effectData.Target
+		case world.EffectEndCombat:
+			effect.EnemyName = effectData.Target
+		case world.EffectCompleteLevel:
+		default:
+			return nil, newValidationError(effectPath+jsonPointer("effect"), "effect must be unlock, reveal_door, end_combat, complete_level, power or custom:<name>, not %s", effectData.Effect)
+		}
+		if (effect.EffectType == world.EffectCompleteLevel) != (effectData.Target == "") {
+			return nil, newValidationError(effectPath+jsonPointer("target"), "effect %s of fixture %s must name a target unless it completes the level", effectData.Effect, itemData.Name)
+		}
+		fixture.OnComplete = append(fixture.OnComplete, effect)
+	}
+	return fixture, nil
+}
+
+// createScoring creates the level's scoring rules, filling in defaults for omitted weights.
+// Returns nil if the level does not define scoring.
+func createScoring(scoringData *ScoringData) (*world.Scoring, error) {
+	if scoringData == nil {
+		return nil, nil
+	}
+
+	scoring := world.DefaultScoring()
+	if scoringData.BasePoints != nil {
+		scoring.BasePoints = *scoringData.BasePoints
+	}
+	if scoringData.TurnPenalty != nil {
+		scoring.TurnPenalty = *scoringData.TurnPenalty
+	}
+	if scoringData.DamagePenalty != nil {
+		scoring.DamagePenalty = *scoringData.DamagePenalty
+	}
+	if scoringData.EnemyBonus != nil {
+		scoring.EnemyBonus = *scoringData.EnemyBonus
+	}
+	if scoringData.SecretBonus != nil {
+		scoring.SecretBonus = *scoringData.SecretBonus
+	}
+
+	seen := make(map[string]bool)
+	for i, badgeData := range scoringData.Badges {
+		if badgeData.Name == "" {
+			return nil, newValidationError(jsonPointer("scoring", "badges", i, "name"), "badge name must be a non-empty string")
+		}
+		if seen[badgeData.Name] {
+			return nil, newValidationError(jsonPointer("scoring", "badges", i, "name"), "duplicate badge name: %s", badgeData.Name)
+		}
+		seen[badgeData.Name] = true
+		scoring.Badges = append(scoring.Badges, &world.Badge{
+			Name:        badgeData.Name,
+			Description: badgeData.Description,
+			Condition: world.BadgeCondition{
+				MaxTurns:           badgeData.MaxTurns,
+				MinEnemiesDefeated: badgeData.MinEnemiesDefeated,
+				MaxDamageTaken:     badgeData.MaxDamageTaken,
+				MinSecretsFound:    badgeData.MinSecretsFound,
+			},
+		})
+	}
+
+	return scoring, nil
+}
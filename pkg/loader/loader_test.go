@@ -0,0 +1,2880 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	world "adventure-engine/pkg/world"
+)
+
+func TestLoadGame_Demo(t *testing.T) {
+	// Load the demo puzzle game
+	level, err := LoadGameFromFile("../../internal/testdata/demo.json")
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+
+	// Test basic game properties
+	if level.Name != "demo puzzle" {
+		t.Errorf("Expected game name 'demo puzzle', got '%s'", level.Name)
+	}
+	if level.Theme != "survival horror" {
+		t.Errorf("Expected theme 'survival horror', got '%s'", level.Theme)
+	}
+
+	// Test intro and outro narrative (should be empty for demo.json)
+	if level.IntroNarrative != "" {
+		t.Errorf("Expected intro narrative to be empty, got '%s'", level.IntroNarrative)
+	}
+	if level.OutroNarrative != "" {
+		t.Errorf("Expected outro narrative to be empty, got '%s'", level.OutroNarrative)
+	}
+
+	// Test rooms
+	allRooms := getAllRooms(level)
+	if len(allRooms) != 4 {
+		t.Errorf("Expected 4 rooms, got %d", len(allRooms))
+	}
+
+	// Test doors
+	if len(level.Doors) != 3 {
+		t.Errorf("Expected 3 doors, got %d", len(level.Doors))
+	}
+
+	// Test enemies
+	if len(level.Enemies) != 1 {
+		t.Errorf("Expected 1 enemy, got %d", len(level.Enemies))
+	}
+
+	// Test triggers
+	if len(level.Triggers) != 1 {
+		t.Errorf("Expected 1 trigger, got %d", len(level.Triggers))
+	}
+
+	// Test win condition
+	if level.WinCondition == nil {
+		t.Error("Expected win condition to be set")
+	} else {
+		if level.WinCondition.Event != world.EventRoomEntered {
+			t.Errorf("Expected win condition event to be EventRoomEntered, got %s", level.WinCondition.Event)
+		}
+		if level.WinCondition.RoomName != "stairwell to roof" {
+			t.Errorf("Expected win condition room to be 'stairwell to roof', got '%s'", level.WinCondition.RoomName)
+		}
+	}
+
+	// Test specific room: waiting room
+	waitingRoom := findRoomByName(allRooms, "waiting room")
+	if waitingRoom == nil {
+		t.Fatal("Could not find waiting room")
+	}
+
+	if waitingRoom.Description != "a dilapidated waiting room" {
+		t.Errorf("Expected waiting room description 'a dilapidated waiting room', got '%s'", waitingRoom.Description)
+	}
+
+	// Test waiting room connections
+	if len(waitingRoom.Connections) != 3 {
+		t.Errorf("Expected waiting room to have 3 connections, got %d", len(waitingRoom.Connections))
+	}
+
+	// Test waiting room items
+	if len(waitingRoom.Items) != 2 {
+		t.Errorf("Expected waiting room to have 2 items, got %d", len(waitingRoom.Items))
+	}
+
+	// Test specific item: tattered grey hoodie (concealer)
+	hoodie := findItemByName(waitingRoom.Items, "tattered grey hoodie")
+	if hoodie == nil {
+		t.Fatal("Could not find tattered grey hoodie")
+	}
+
+	if !hoodie.IsConcealer() {
+		t.Error("Expected tattered grey hoodie to be a concealer")
+	}
+
+	if hoodie.Concealer.Hidden == nil {
+		t.Error("Expected concealer to have hidden item")
+	} else {
+		if hoodie.Concealer.Hidden.Name != "ominous note" {
+			t.Errorf("Expected hidden item to be 'ominous note', got '%s'", hoodie.Concealer.Hidden.Name)
+		}
+		if !hoodie.Concealer.Hidden.IsPortable() {
+			t.Error("Expected hidden item to be portable")
+		}
+	}
+
+	// Test specific item: energy drink (health item)
+	energyDrink := findItemByName(waitingRoom.Items, "energy drink")
+	if energyDrink == nil {
+		t.Fatal("Could not find energy drink")
+	}
+
+	if !energyDrink.IsHealthItem() {
+		t.Error("Expected energy drink to be a health item")
+	}
+
+	if energyDrink.HealthItem.HealthEffect != world.HealthBoostWeak {
+		t.Errorf("Expected energy drink to have weak health effect, got %s", energyDrink.HealthItem.HealthEffect)
+	}
+
+	// Test specific room: storage room
+	storageRoom := findRoomByName(allRooms, "storage room")
+	if storageRoom == nil {
+		t.Fatal("Could not find storage room")
+	}
+
+	// Test storage room items
+	if len(storageRoom.Items) != 4 {
+		t.Errorf("Expected storage room to have 4 items, got %d", len(storageRoom.Items))
+	}
+
+	// Test specific item: dark green tarp (concealer with container)
+	tarp := findItemByName(storageRoom.Items, "dark green tarp")
+	if tarp == nil {
+		t.Fatal("Could not find dark green tarp")
+	}
+
+	if !tarp.IsConcealer() {
+		t.Error("Expected dark green tarp to be a concealer")
+	}
+
+	if tarp.Concealer.Hidden == nil {
+		t.Error("Expected concealer to have hidden item")
+	} else {
+		if tarp.Concealer.Hidden.Name != "safe" {
+			t.Errorf("Expected hidden item to be 'safe', got '%s'", tarp.Concealer.Hidden.Name)
+		}
+		if !tarp.Concealer.Hidden.IsContainer() {
+			t.Error("Expected hidden item to be a container")
+		}
+		if !tarp.Concealer.Hidden.Container.HasCodeLock() {
+			t.Error("Expected container to have code lock")
+		}
+		if tarp.Concealer.Hidden.Container.Locked.Code != "2468" {
+			t.Errorf("Expected container code to be '2468', got '%s'", tarp.Concealer.Hidden.Container.Locked.Code)
+		}
+		if tarp.Concealer.Hidden.Container.Contains == nil {
+			t.Error("Expected container to have contents")
+		} else {
+			if tarp.Concealer.Hidden.Container.Contains.Name != "iron key" {
+				t.Errorf("Expected container contents to be 'iron key', got '%s'", tarp.Concealer.Hidden.Container.Contains.Name)
+			}
+			if !tarp.Concealer.Hidden.Container.Contains.IsKey() {
+				t.Error("Expected container contents to be a key")
+			}
+		}
+	}
+
+	// Test specific item: filing cabinet (empty container)
+	filingCabinet := findItemByName(storageRoom.Items, "filing cabinet")
+	if filingCabinet == nil {
+		t.Fatal("Could not find filing cabinet")
+	}
+
+	if !filingCabinet.IsContainer() {
+		t.Error("Expected filing cabinet to be a container")
+	}
+
+	if filingCabinet.Container.Contains != nil {
+		t.Error("Expected filing cabinet to be empty")
+	}
+
+	// Test specific item: metal pipe (weapon)
+	metalPipe := findItemByName(storageRoom.Items, "metal pipe")
+	if metalPipe == nil {
+		t.Fatal("Could not find metal pipe")
+	}
+
+	if !metalPipe.IsWeapon() {
+		t.Error("Expected metal pipe to be a weapon")
+	}
+
+	if metalPipe.Weapon.Damage != 0.7 {
+		t.Errorf("Expected metal pipe damage to be 0.7, got %f", metalPipe.Weapon.Damage)
+	}
+
+	// Test specific room: office
+	office := findRoomByName(allRooms, "office")
+	if office == nil {
+		t.Fatal("Could not find office")
+	}
+
+	// Test office items
+	if len(office.Items) != 2 {
+		t.Errorf("Expected office to have 2 items, got %d", len(office.Items))
+	}
+
+	// Test specific item: desk (container with weapon)
+	desk := findItemByName(office.Items, "desk")
+	if desk == nil {
+		t.Fatal("Could not find desk")
+	}
+
+	if !desk.IsContainer() {
+		t.Error("Expected desk to be a container")
+	}
+
+	if desk.Container.Contains == nil {
+		t.Error("Expected desk to have contents")
+	} else {
+		if desk.Container.Contains.Name != "pistol" {
+			t.Errorf("Expected desk contents to be 'pistol', got '%s'", desk.Container.Contains.Name)
+		}
+		if !desk.Container.Contains.IsWeapon() {
+			t.Error("Expected desk contents to be a weapon")
+		}
+		if desk.Container.Contains.Weapon.Damage != 0.9 {
+			t.Errorf("Expected pistol damage to be 0.9, got %f", desk.Container.Contains.Weapon.Damage)
+		}
+		if !desk.Container.Contains.Weapon.UsesAmmo() {
+			t.Error("Expected pistol to use ammo")
+		}
+		if desk.Container.Contains.Weapon.Ammo.Quantity != 1 {
+			t.Errorf("Expected pistol to have 1 ammo, got %d", desk.Container.Contains.Weapon.Ammo.Quantity)
+		}
+	}
+
+	// Test specific item: cardboard box (container with ammo box)
+	cardboardBox := findItemByName(office.Items, "cardboard box")
+	if cardboardBox == nil {
+		t.Fatal("Could not find cardboard box")
+	}
+
+	if !cardboardBox.IsContainer() {
+		t.Error("Expected cardboard box to be a container")
+	}
+
+	if cardboardBox.Container.Contains == nil {
+		t.Error("Expected cardboard box to have contents")
+	} else {
+		if cardboardBox.Container.Contains.Name != "pistol ammo" {
+			t.Errorf("Expected cardboard box contents to be 'pistol ammo', got '%s'", cardboardBox.Container.Contains.Name)
+		}
+		if !cardboardBox.Container.Contains.IsAmmoBox() {
+			t.Error("Expected cardboard box contents to be an ammo box")
+		}
+		if cardboardBox.Container.Contains.AmmoBox.AmmoType != "pistol" {
+			t.Errorf("Expected ammo box ammo type to be 'pistol', got '%s'", cardboardBox.Container.Contains.AmmoBox.AmmoType)
+		}
+		if cardboardBox.Container.Contains.AmmoBox.Ammo.Quantity != 2 {
+			t.Errorf("Expected ammo box to have 2 ammo, got %d", cardboardBox.Container.Contains.AmmoBox.Ammo.Quantity)
+		}
+	}
+
+	// Test specific room: stairwell to roof
+	stairwell := findRoomByName(allRooms, "stairwell to roof")
+	if stairwell == nil {
+		t.Fatal("Could not find stairwell to roof")
+	}
+
+	if stairwell.Description != "a way out" {
+		t.Errorf("Expected stairwell description 'a way out', got '%s'", stairwell.Description)
+	}
+
+	// Test doors
+	storageDoor := findDoorByName(level.Doors, "storage room door")
+	if storageDoor == nil {
+		t.Fatal("Could not find storage room door")
+	}
+
+	if storageDoor.RoomA != "waiting room" || storageDoor.RoomB != "storage room" {
+		t.Errorf("Expected storage door to connect waiting room and storage room, got %s and %s", storageDoor.RoomA, storageDoor.RoomB)
+	}
+
+	metalDoor := findDoorByName(level.Doors, "metal stairwell door")
+	if metalDoor == nil {
+		t.Fatal("Could not find metal stairwell door")
+	}
+
+	if !metalDoor.IsLocked() {
+		t.Error("Expected metal stairwell door to be locked")
+	}
+
+	if !metalDoor.HasKeyLock() {
+		t.Error("Expected metal stairwell door to have key lock")
+	}
+
+	if metalDoor.Lock.KeyName != "iron key" {
+		t.Errorf("Expected metal door to require iron key, got '%s'", metalDoor.Lock.KeyName)
+	}
+
+	// Test enemy
+	zombie := findEnemyByName(level.Enemies, "zombie")
+	if zombie == nil {
+		t.Fatal("Could not find zombie")
+	}
+
+	if zombie.Description != "a wailing zombie" {
+		t.Errorf("Expected zombie description 'a wailing zombie', got '%s'", zombie.Description)
+	}
+
+	if zombie.HP != 1 {
+		t.Errorf("Expected zombie HP to be 1, got %d", zombie.HP)
+	}
+
+	// Test trigger
+	if len(level.Triggers) != 1 {
+		t.Errorf("Expected 1 trigger, got %d", len(level.Triggers))
+	} else {
+		trigger := level.Triggers[0]
+		if trigger.Event.Event != world.EventItemTaken {
+			t.Errorf("Expected trigger event to be EventItemTaken, got %s", trigger.Event.Event)
+		}
+		if trigger.Event.ItemName != "iron key" {
+			t.Errorf("Expected trigger item name to be 'iron key', got '%s'", trigger.Event.ItemName)
+		}
+		if trigger.Effect.EffectType != world.EffectEnterCombat {
+			t.Errorf("Expected trigger effect to be EffectEnterCombat, got %s", trigger.Effect.EffectType)
+		}
+		if trigger.Effect.EnemyName != "zombie" {
+			t.Errorf("Expected trigger enemy name to be 'zombie', got '%s'", trigger.Effect.EnemyName)
+		}
+	}
+}
+
+func TestLoadGame_FromRawMessage(t *testing.T) {
+	// Test JSON data as raw message
+	jsonData := json.RawMessage(`{
+		"name": "test game",
+		"floors": [
+			{
+				"name": "test floor",
+				"description": "a test floor",
+				"rooms": [
+					{
+						"name": "test room",
+						"description": "a test room",
+						"items": [
+							{
+								"name": "test item",
+								"description": "a test item",
+								"portable": true
+							}
+						]
+					}
+				]
+			}
+		],
+		"doors": [],
+		"enemies": []
+	}`)
+
+	level, err := LoadGame(jsonData)
+	if err != nil {
+		t.Fatalf("Failed to load game from raw message: %v", err)
+	}
+
+	// Test basic properties
+	if level.Name != "test game" {
+		t.Errorf("Expected game name 'test game', got '%s'", level.Name)
+	}
+
+	allRooms := getAllRooms(level)
+	if len(allRooms) != 1 {
+		t.Errorf("Expected 1 room, got %d", len(allRooms))
+	}
+
+	if len(allRooms[0].Items) != 1 {
+		t.Errorf("Expected 1 item in room, got %d", len(allRooms[0].Items))
+	}
+
+	if allRooms[0].Items[0].Name != "test item" {
+		t.Errorf("Expected item name 'test item', got '%s'", allRooms[0].Items[0].Name)
+	}
+}
+
+func TestLoadGame_ValidationErrors(t *testing.T) {
+	// Test invalid item configurations that should fail validation
+
+	// Test 1: Key that is also a container (should fail)
+	jsonData1 := json.RawMessage(`{
+		"name": "test game",
+		"floors": [
+			{
+				"name": "test floor",
+				"description": "a test floor",
+				"rooms": [
+					{
+						"name": "test room",
+						"description": "a test room",
+						"items": [
+							{
+								"name": "invalid key",
+								"description": "a key that is also a container",
+								"key": true,
+								"contains": "empty"
+							}
+						]
+					}
+				]
+			}
+		],
+		"doors": [],
+		"enemies": []
+	}`)
+
+	_, err := LoadGame(jsonData1)
+	if err == nil {
+		t.Error("Expected error for key that is also a container, got nil")
+	} else if !strings.Contains(err.Error(), "invalid key") {
+		t.Errorf("Expected error about invalid key, got: %v", err)
+	}
+
+	// Test 2: Weapon that is also a container (should fail)
+	jsonData2 := json.RawMessage(`{
+		"name": "test game",
+		"floors": [
+			{
+				"name": "test floor",
+				"description": "a test floor",
+				"rooms": [
+					{
+						"name": "test room",
+						"description": "a test room",
+						"items": [
+							{
+								"name": "invalid weapon",
+								"description": "a weapon that is also a container",
+								"weapon_damage": 0.8,
+								"contains": "empty"
+							}
+						]
+					}
+				]
+			}
+		],
+		"doors": [],
+		"enemies": []
+	}`)
+
+	_, err = LoadGame(jsonData2)
+	if err == nil {
+		t.Error("Expected error for weapon that is also a container, got nil")
+	} else if !strings.Contains(err.Error(), "invalid weapon") {
+		t.Errorf("Expected error about invalid weapon, got: %v", err)
+	}
+
+	// Test 3: Container that is also a key (should fail)
+	jsonData3 := json.RawMessage(`{
+		"name": "test game",
+		"floors": [
+			{
+				"name": "test floor",
+				"description": "a test floor",
+				"rooms": [
+					{
+						"name": "test room",
+						"description": "a test room",
+						"items": [
+							{
+								"name": "invalid container",
+								"description": "a container that is also a key",
+								"key": true,
+								"contains": "empty"
+							}
+						]
+					}
+				]
+			}
+		],
+		"doors": [],
+		"enemies": []
+	}`)
+
+	_, err = LoadGame(jsonData3)
+	if err == nil {
+		t.Error("Expected error for container that is also a key, got nil")
+	} else if !strings.Contains(err.Error(), "invalid container") {
+		t.Errorf("Expected error about invalid container, got: %v", err)
+	}
+
+	// Test 4: Nested containers (should pass)
+	jsonData4 := json.RawMessage(`{
+		"name": "test game",
+		"rooms": [
+			{
+				"name": "test room",
+				"description": "a test room",
+				"items": [
+					{
+						"name": "outer container",
+						"description": "a container with another container inside",
+						"contains": {
+							"name": "inner container",
+							"description": "a container inside another container",
+							"contains": "empty"
+						}
+					}
+				]
+			}
+		],
+		"doors": [],
+		"enemies": []
+	}`)
+
+	_, err = LoadGame(jsonData4)
+	if err != nil {
+		t.Errorf("Expected nested containers to load, got: %v", err)
+	}
+}
+
+func TestLoadGame_ReachabilityValidation(t *testing.T) {
+	// Test 1: Valid connected level (should pass)
+	jsonData1 := json.RawMessage(`{
+		"name": "connected level",
+		"rooms": [
+			{
+				"name": "room1",
+				"description": "first room",
+				"connections": [
+					{
+						"direction": "east",
+						"door_name": "door1"
+					}
+				]
+			},
+			{
+				"name": "room2",
+				"description": "second room",
+				"connections": [
+					{
+						"direction": "west",
+						"door_name": "door1"
+					}
+				]
+			}
+		],
+		"doors": [
+			{
+				"name": "door1",
+				"room_a": "room1",
+				"room_b": "room2"
+			}
+		],
+		"enemies": []
+	}`)
+
+	_, err := LoadGame(jsonData1)
+	if err != nil {
+		t.Errorf("Expected connected level to pass validation, got error: %v", err)
+	}
+
+	// Test 2: Disconnected level (should fail)
+	jsonData2 := json.RawMessage(`{
+		"name": "disconnected level",
+		"rooms": [
+			{
+				"name": "room1",
+				"description": "first room"
+			},
+			{
+				"name": "room2",
+				"description": "second room"
+			}
+		],
+		"doors": [],
+		"enemies": []
+	}`)
+
+	_, err = LoadGame(jsonData2)
+	if err == nil {
+		t.Error("Expected disconnected level to fail validation, got nil error")
+	} else if !strings.Contains(err.Error(), "unreachable rooms found") {
+		t.Errorf("Expected error about unreachable rooms, got: %v", err)
+	}
+
+	// Test 3: Level with isolated room (should fail)
+	jsonData3 := json.RawMessage(`{
+		"name": "level with isolated room",
+		"rooms": [
+			{
+				"name": "room1",
+				"description": "first room",
+				"connections": [
+					{
+						"direction": "east",
+						"door_name": "door1"
+					}
+				]
+			},
+			{
+				"name": "room2",
+				"description": "second room",
+				"connections": [
+					{
+						"direction": "west",
+						"door_name": "door1"
+					}
+				]
+			},
+			{
+				"name": "isolated_room",
+				"description": "room with no connections"
+			}
+		],
+		"doors": [
+			{
+				"name": "door1",
+				"room_a": "room1",
+				"room_b": "room2"
+			}
+		],
+		"enemies": []
+	}`)
+
+	_, err = LoadGame(jsonData3)
+	if err == nil {
+		t.Error("Expected level with isolated room to fail validation, got nil error")
+	} else if !strings.Contains(err.Error(), "unreachable rooms found") {
+		t.Errorf("Expected error about unreachable rooms, got: %v", err)
+	} else if !strings.Contains(err.Error(), "isolated_room") {
+		t.Errorf("Expected error to mention isolated_room, got: %v", err)
+	}
+
+	// Test 4: Complex connected level (should pass)
+	jsonData4 := json.RawMessage(`{
+		"name": "complex connected level",
+		"rooms": [
+			{
+				"name": "room1",
+				"description": "first room",
+				"connections": [
+					{
+						"direction": "east",
+						"door_name": "door1"
+					},
+					{
+						"direction": "down",
+						"door_name": "door3"
+					}
+				]
+			},
+			{
+				"name": "room2",
+				"description": "second room",
+				"connections": [
+					{
+						"direction": "west",
+						"door_name": "door1"
+					},
+					{
+						"direction": "south",
+						"door_name": "door2"
+					}
+				]
+			},
+			{
+				"name": "room3",
+				"description": "third room",
+				"connections": [
+					{
+						"direction": "north",
+						"door_name": "door2"
+					},
+					{
+						"direction": "up",
+						"door_name": "door3"
+					}
+				]
+			}
+		],
+		"doors": [
+			{
+				"name": "door1",
+				"room_a": "room1",
+				"room_b": "room2"
+			},
+			{
+				"name": "door2",
+				"room_a": "room2",
+				"room_b": "room3"
+			},
+			{
+				"name": "door3",
+				"room_a": "room1",
+				"room_b": "room3"
+			}
+		],
+		"enemies": []
+	}`)
+
+	_, err = LoadGame(jsonData4)
+	if err != nil {
+		t.Errorf("Expected complex connected level to pass validation, got error: %v", err)
+	}
+}
+
+func TestLoadGame_JSONStructureValidation(t *testing.T) {
+	// Test 1: Missing required field 'name' (should fail)
+	jsonData1 := json.RawMessage(`{
+		"rooms": [
+			{
+				"name": "test room",
+				"description": "a test room"
+			}
+		],
+		"doors": [],
+		"enemies": []
+	}`)
+
+	_, err := LoadGame(jsonData1)
+	if err == nil {
+		t.Error("Expected error for missing 'name' field, got nil")
+	} else if !strings.Contains(err.Error(), "missing required field: name") {
+		t.Errorf("Expected error about missing name field, got: %v", err)
+	}
+
+	// Test 2: Missing required field 'rooms' (should fail)
+	jsonData2 := json.RawMessage(`{
+		"name": "test game",
+		"doors": [],
+		"enemies": []
+	}`)
+
+	_, err = LoadGame(jsonData2)
+	if err == nil {
+		t.Error("Expected error for missing 'rooms' field, got nil")
+	} else if !strings.Contains(err.Error(), "missing required field: either 'floors' or 'rooms'") {
+		t.Errorf("Expected error about missing rooms field, got: %v", err)
+	}
+
+	// Test 3: Empty name field (should fail)
+	jsonData3 := json.RawMessage(`{
+		"name": "",
+		"rooms": [
+			{
+				"name": "test room",
+				"description": "a test room"
+			}
+		],
+		"doors": [],
+		"enemies": []
+	}`)
+
+	_, err = LoadGame(jsonData3)
+	if err == nil {
+		t.Error("Expected error for empty name field, got nil")
+	} else if !strings.Contains(err.Error(), "field 'name' must be a non-empty string") {
+		t.Errorf("Expected error about empty name field, got: %v", err)
+	}
+
+	// Test 4: Empty rooms array (should fail)
+	jsonData4 := json.RawMessage(`{
+		"name": "test game",
+		"rooms": [],
+		"doors": [],
+		"enemies": []
+	}`)
+
+	_, err = LoadGame(jsonData4)
+	if err == nil {
+		t.Error("Expected error for empty rooms array, got nil")
+	} else if !strings.Contains(err.Error(), "field 'rooms' must be a non-empty array") {
+		t.Errorf("Expected error about empty rooms array, got: %v", err)
+	}
+
+	// Test 5: Unexpected field (should fail)
+	jsonData5 := json.RawMessage(`{
+		"name": "test game",
+		"rooms": [
+			{
+				"name": "test room",
+				"description": "a test room"
+			}
+		],
+		"doors": [],
+		"enemies": [],
+		"unexpected_field": "this should not be here"
+	}`)
+
+	_, err = LoadGame(jsonData5)
+	if err == nil {
+		t.Error("Expected error for unexpected field, got nil")
+	} else if !strings.Contains(err.Error(), "unexpected field: unexpected_field") {
+		t.Errorf("Expected error about unexpected field, got: %v", err)
+	}
+
+	// Test 6: Valid structure with all optional fields (should pass)
+	jsonData6 := json.RawMessage(`{
+		"name": "test game",
+		"win_condition": {
+			"event": "room_entered",
+			"room_name": "test room"
+		},
+		"rooms": [
+			{
+				"name": "test room",
+				"description": "a test room"
+			}
+		],
+		"doors": [],
+		"enemies": [],
+		"system_prompt_theme": "adventure"
+	}`)
+
+	_, err = LoadGame(jsonData6)
+	if err != nil {
+		t.Errorf("Expected valid structure to pass validation, got error: %v", err)
+	}
+
+	// Test 7: Invalid JSON format (should fail)
+	jsonData7 := json.RawMessage(`{
+		"name": "test game",
+		"rooms": [
+			{
+				"name": "test room",
+				"description": "a test room"
+			}
+		],
+		"doors": [],
+		"enemies": [],
+		"unclosed": {
+	}`)
+
+	_, err = LoadGame(jsonData7)
+	if err == nil {
+		t.Error("Expected error for invalid JSON format, got nil")
+	} else if !strings.Contains(err.Error(), "invalid JSON format") {
+		t.Errorf("Expected error about invalid JSON format, got: %v", err)
+	}
+}
+
+func TestLoadGame_DemoYAML(t *testing.T) {
+	// Load the demo puzzle game from YAML
+	level, err := LoadGameFromFile("../../internal/testdata/demo.yaml")
+	if err != nil {
+		t.Fatalf("Failed to load game from YAML: %v", err)
+	}
+
+	// Test basic game properties
+	if level.Name != "demo puzzle" {
+		t.Errorf("Expected game name 'demo puzzle', got '%s'", level.Name)
+	}
+
+	// Test rooms
+	allRooms := getAllRooms(level)
+	if len(allRooms) != 4 {
+		t.Errorf("Expected 4 rooms, got %d", len(allRooms))
+	}
+
+	// Test doors
+	if len(level.Doors) != 3 {
+		t.Errorf("Expected 3 doors, got %d", len(level.Doors))
+	}
+
+	// Test enemies
+	if len(level.Enemies) != 1 {
+		t.Errorf("Expected 1 enemy, got %d", len(level.Enemies))
+	}
+
+	// Test triggers
+	if len(level.Triggers) != 1 {
+		t.Errorf("Expected 1 trigger, got %d", len(level.Triggers))
+	}
+
+	// Test win condition
+	if level.WinCondition == nil {
+		t.Error("Expected win condition to be set")
+	} else {
+		if level.WinCondition.Event != world.EventRoomEntered {
+			t.Errorf("Expected win condition event to be EventRoomEntered, got %s", level.WinCondition.Event)
+		}
+		if level.WinCondition.RoomName != "stairwell to roof" {
+			t.Errorf("Expected win condition room to be 'stairwell to roof', got '%s'", level.WinCondition.RoomName)
+		}
+	}
+
+	// Test specific room: waiting room
+	waitingRoom := findRoomByName(allRooms, "waiting room")
+	if waitingRoom == nil {
+		t.Fatal("Could not find waiting room")
+	}
+
+	if waitingRoom.Description != "a dilapidated waiting room" {
+		t.Errorf("Expected waiting room description 'a dilapidated waiting room', got '%s'", waitingRoom.Description)
+	}
+
+	// Test waiting room connections
+	if len(waitingRoom.Connections) != 3 {
+		t.Errorf("Expected waiting room to have 3 connections, got %d", len(waitingRoom.Connections))
+	}
+
+	// Test waiting room items
+	if len(waitingRoom.Items) != 2 {
+		t.Errorf("Expected waiting room to have 2 items, got %d", len(waitingRoom.Items))
+	}
+}
+
+// Helper functions
+func getAllRooms(level *world.Level) []*world.Room {
+	var allRooms []*world.Room
+	for _, floor := range level.Floors {
+		allRooms = append(allRooms, floor.Rooms...)
+	}
+	return allRooms
+}
+
+func findRoomByName(rooms []*world.Room, name string) *world.Room {
+	for _, room := range rooms {
+		if room.Name == name {
+			return room
+		}
+	}
+	return nil
+}
+
+func findItemByName(items []*world.Item, name string) *world.Item {
+	for _, item := range items {
+		if item.Name == name {
+			return item
+		}
+	}
+	return nil
+}
+
+func findDoorByName(doors []*world.Door, name string) *world.Door {
+	for _, door := range doors {
+		if door.Name == name {
+			return door
+		}
+	}
+	return nil
+}
+
+func findEnemyByName(enemies []*world.Enemy, name string) *world.Enemy {
+	for _, enemy := range enemies {
+		if enemy.Name == name {
+			return enemy
+		}
+	}
+	return nil
+}
+
+func TestLoadGame_ComboItems(t *testing.T) {
+	// Load the crafting test game
+	level, err := LoadGameFromFile("../../internal/testdata/crafting.json")
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+
+	// Test combo items
+	if len(level.ComboItems) != 1 {
+		t.Errorf("Expected 1 combo item, got %d", len(level.ComboItems))
+	}
+
+	combo := level.ComboItems[0]
+	if combo.InputItemAName != "fish hook" {
+		t.Errorf("Expected input item A to be 'fish hook', got '%s'", combo.InputItemAName)
+	}
+	if combo.InputItemBName != "dental floss" {
+		t.Errorf("Expected input item B to be 'dental floss', got '%s'", combo.InputItemBName)
+	}
+	if combo.OutputItem.Name != "retrieval tool" {
+		t.Errorf("Expected output item to be 'retrieval tool', got '%s'", combo.OutputItem.Name)
+	}
+}
+
+func TestLoadGame_Fixtures(t *testing.T) {
+	// Load the fixture test game
+	level, err := LoadGameFromFile("../../internal/testdata/fixture.json")
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+
+	// Test basic game properties
+	if level.Name != "fixture test" {
+		t.Errorf("Expected game name 'fixture test', got '%s'", level.Name)
+	}
+
+	// Test rooms
+	allRooms := getAllRooms(level)
+	if len(allRooms) != 3 {
+		t.Errorf("Expected 3 rooms, got %d", len(allRooms))
+	}
+
+	// Test doors
+	if len(level.Doors) != 2 {
+		t.Errorf("Expected 2 doors, got %d", len(level.Doors))
+	}
+
+	// Test combo items
+	if len(level.ComboItems) != 1 {
+		t.Errorf("Expected 1 combo item, got %d", len(level.ComboItems))
+	}
+
+	// Test bathroom fixtures
+	bathroom := findRoomByName(allRooms, "bathroom")
+	if bathroom == nil {
+		t.Fatal("Could not find bathroom")
+	}
+
+	// Test bathtub drain fixture
+	bathtubDrain := findItemByName(bathroom.Items, "bathtub drain")
+	if bathtubDrain == nil {
+		t.Fatal("Could not find bathtub drain")
+	}
+
+	if !bathtubDrain.IsFixture() {
+		t.Error("Expected bathtub drain to be a fixture")
+	}
+
+	if bathtubDrain.Fixture == nil {
+		t.Fatal("Expected bathtub drain to have fixture data")
+	}
+
+	if len(bathtubDrain.Fixture.RequiredItems) != 1 {
+		t.Errorf("Expected bathtub drain to require 1 item, got %d", len(bathtubDrain.Fixture.RequiredItems))
+	}
+
+	// Check that "retrieval tool" is in the required items map (value should be false initially)
+	if _, exists := bathtubDrain.Fixture.RequiredItems["retrieval tool"]; !exists {
+		t.Error("Expected bathtub drain to require 'retrieval tool'")
+	}
+
+	if bathtubDrain.Fixture.Produces == nil {
+		t.Fatal("Expected bathtub drain fixture to have produced item")
+	}
+
+	if bathtubDrain.Fixture.Produces.Name != "bedroom key" {
+		t.Errorf("Expected bathtub drain to produce 'bedroom key', got '%s'", bathtubDrain.Fixture.Produces.Name)
+	}
+
+	if !bathtubDrain.Fixture.Produces.IsKey() {
+		t.Error("Expected produced item to be a key")
+	}
+
+	// Test bedroom fixtures
+	bedroom := findRoomByName(allRooms, "bedroom")
+	if bedroom == nil {
+		t.Fatal("Could not find bedroom")
+	}
+
+	// Test altar fixture
+	altar := findItemByName(bedroom.Items, "altar")
+	if altar == nil {
+		t.Fatal("Could not find altar")
+	}
+
+	if !altar.IsFixture() {
+		t.Error("Expected altar to be a fixture")
+	}
+
+	if altar.Fixture == nil {
+		t.Fatal("Expected altar to have fixture data")
+	}
+
+	if len(altar.Fixture.RequiredItems) != 2 {
+		t.Errorf("Expected altar to require 2 items, got %d", len(altar.Fixture.RequiredItems))
+	}
+
+	// Check required items (should be false initially)
+	if _, exists := altar.Fixture.RequiredItems["stone"]; !exists {
+		t.Error("Expected altar to require 'stone'")
+	}
+
+	if _, exists := altar.Fixture.RequiredItems["candle"]; !exists {
+		t.Error("Expected altar to require 'candle'")
+	}
+
+	if altar.Fixture.Produces == nil {
+		t.Fatal("Expected altar fixture to have produced item")
+	}
+
+	if altar.Fixture.Produces.Name != "balcony key" {
+		t.Errorf("Expected altar to produce 'balcony key', got '%s'", altar.Fixture.Produces.Name)
+	}
+
+	if !altar.Fixture.Produces.IsKey() {
+		t.Error("Expected produced item to be a key")
+	}
+}
+
+func TestLoadGame_Latches(t *testing.T) {
+	// Load the latch test game
+	level, err := LoadGameFromFile("../../internal/testdata/latch.json")
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+
+	// Test basic game properties
+	if level.Name != "latch test" {
+		t.Errorf("Expected game name 'latch test', got '%s'", level.Name)
+	}
+
+	// Test rooms
+	allRooms := getAllRooms(level)
+	if len(allRooms) != 3 {
+		t.Errorf("Expected 3 rooms, got %d", len(allRooms))
+	}
+
+	// Test doors
+	if len(level.Doors) != 3 {
+		t.Errorf("Expected 3 doors, got %d", len(level.Doors))
+	}
+
+	// Test door XY - should have a latch
+	doorXY := findDoorByName(level.Doors, "door XY")
+	if doorXY == nil {
+		t.Fatal("Could not find door XY")
+	}
+
+	if !doorXY.IsLatched() {
+		t.Error("Expected door XY to be latched")
+	}
+
+	if doorXY.Latch == nil {
+		t.Fatal("Expected door XY to have latch data")
+	}
+
+	if doorXY.Latch.LockedFrom != "room Y" {
+		t.Errorf("Expected door XY to be latched from 'room Y', got '%s'", doorXY.Latch.LockedFrom)
+	}
+
+	if !doorXY.Latch.Locked {
+		t.Error("Expected door XY latch to be locked")
+	}
+
+	// Test door XZ - should not have a latch
+	doorXZ := findDoorByName(level.Doors, "door XZ")
+	if doorXZ == nil {
+		t.Fatal("Could not find door XZ")
+	}
+
+	if doorXZ.IsLatched() {
+		t.Error("Expected door XZ to not be latched")
+	}
+
+	if doorXZ.Latch != nil {
+		t.Error("Expected door XZ to not have latch data")
+	}
+
+	// Test door ZY - should not have a latch
+	doorZY := findDoorByName(level.Doors, "door ZY")
+	if doorZY == nil {
+		t.Fatal("Could not find door ZY")
+	}
+
+	if doorZY.IsLatched() {
+		t.Error("Expected door ZY to not be latched")
+	}
+
+	if doorZY.Latch != nil {
+		t.Error("Expected door ZY to not have latch data")
+	}
+
+	// Test room connections
+	roomX := findRoomByName(allRooms, "room X")
+	if roomX == nil {
+		t.Fatal("Could not find room X")
+	}
+
+	if len(roomX.Connections) != 2 {
+		t.Errorf("Expected room X to have 2 connections, got %d", len(roomX.Connections))
+	}
+
+	roomY := findRoomByName(allRooms, "room Y")
+	if roomY == nil {
+		t.Fatal("Could not find room Y")
+	}
+
+	if len(roomY.Connections) != 2 {
+		t.Errorf("Expected room Y to have 2 connections, got %d", len(roomY.Connections))
+	}
+
+	roomZ := findRoomByName(allRooms, "room Z")
+	if roomZ == nil {
+		t.Fatal("Could not find room Z")
+	}
+
+	if len(roomZ.Connections) != 2 {
+		t.Errorf("Expected room Z to have 2 connections, got %d", len(roomZ.Connections))
+	}
+}
+
+func TestLoadGame_TwoWayLatch(t *testing.T) {
+	level, err := LoadGame(json.RawMessage(`{
+		"name": "two-way latch test",
+		"rooms": [
+			{"name": "hall", "description": "a hall", "connections": [{"door_name": "oak door"}]},
+			{"name": "study", "description": "a study", "connections": [{"door_name": "oak door"}]}
+		],
+		"doors": [{"name": "oak door", "room_a": "hall", "room_b": "study", "two_way_latch": true}]
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+
+	door := findDoorByName(level.Doors, "oak door")
+	if door.Latch == nil || !door.Latch.TwoWay || door.IsLatched() {
+		t.Fatalf("Expected an open two-way latch, got %+v", door.Latch)
+	}
+	if !door.CanLatch("hall") || !door.CanLatch("study") {
+		t.Error("Expected the door to be latchable from both rooms")
+	}
+
+	// A two-way latch is kept when exported, even while it is open
+	exported := ExportLevel(level)
+	if !exported.DoorData[0].TwoWayLatch || exported.DoorData[0].LatchedFrom != "" {
+		t.Errorf("Expected the exported door to keep its open two-way latch, got %+v", exported.DoorData[0])
+	}
+}
+
+func TestLoadGame_LearnedCodes(t *testing.T) {
+	level, err := LoadGame(json.RawMessage(`{
+		"name": "learned codes test",
+		"rooms": [
+			{"name": "hall", "description": "a hall", "connections": [{"door_name": "vault door"}], "items": [
+				{"name": "note", "description": "a note", "detail": "The vault code is 2468.", "portable": true}
+			]},
+			{"name": "vault", "description": "a vault", "connections": [{"door_name": "vault door"}]}
+		],
+		"doors": [{"name": "vault door", "room_a": "hall", "room_b": "vault", "locked": true, "code": "2468", "require_learned_code": true}]
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	door := findDoorByName(level.Doors, "vault door")
+	if !door.Lock.RequireLearned {
+		t.Errorf("Expected the vault door to require a learned code, got %+v", door.Lock)
+	}
+	if exported := ExportLevel(level); !exported.DoorData[0].RequireLearned {
+		t.Errorf("Expected the exported door to keep requiring a learned code, got %+v", exported.DoorData[0])
+	}
+
+	tests := []struct {
+		name  string
+		rooms string
+		doors string
+		path  string
+	}{
+		{
+			name:  "code never revealed",
+			rooms: `{"name": "hall", "description": "a hall", "connections": [{"door_name": "vault door"}], "items": [{"name": "note", "description": "a note", "detail": "Code 24680", "portable": true}]}`,
+			doors: `{"name": "vault door", "room_a": "hall", "room_b": "vault", "locked": true, "code": "2468", "require_learned_code": true}`,
+			path:  "/doors/0/code",
+		},
+		{
+			name:  "no code lock",
+			rooms: `{"name": "hall", "description": "a hall", "connections": [{"door_name": "vault door"}]}`,
+			doors: `{"name": "vault door", "room_a": "hall", "room_b": "vault", "locked": true, "required_key_name": "key", "require_learned_code": true}`,
+			path:  "/doors/0/require_learned_code",
+		},
+		{
+			name:  "item without a code lock",
+			rooms: `{"name": "hall", "description": "a hall", "connections": [{"door_name": "vault door"}], "items": [{"name": "box", "description": "a box", "require_learned_code": true, "contains": {"empty": true}}]}`,
+			doors: `{"name": "vault door", "room_a": "hall", "room_b": "vault"}`,
+			path:  "/rooms/0/items/0/require_learned_code",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diagnostics := ValidateLevel(json.RawMessage(`{
+				"name": "learned codes test",
+				"rooms": [` + test.rooms + `, {"name": "vault", "description": "a vault", "connections": [{"door_name": "vault door"}]}],
+				"doors": [` + test.doors + `]
+			}`))
+			errs := diagnostics.Errors()
+			if len(errs) != 1 || errs[0].Path != test.path {
+				t.Errorf("Expected one error at %s, got %+v", test.path, errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_KeypadLockout(t *testing.T) {
+	const levelJSON = `{
+		"name": "lockout test",
+		"rooms": [
+			{"name": "hall", "description": "a hall", "connections": [{"door_name": "vault door"}]},
+			{"name": "vault", "description": "a vault", "connections": [{"door_name": "vault door"}]}
+		],
+		"doors": [%s],
+		"enemies": [{"name": "guard", "description": "a guard", "hp": 3, "room": "hall", "trigger": {"event": "lock_jammed", "lock_name": "vault door"}}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON,
+		`{"name": "vault door", "room_a": "hall", "room_b": "vault", "locked": true, "code": "2468", "max_attempts": 3}`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	door := findDoorByName(level.Doors, "vault door")
+	if door.Lock.MaxAttempts != 3 {
+		t.Errorf("Expected 3 attempts, got %+v", door.Lock)
+	}
+	if len(level.Triggers) != 1 || level.Triggers[0].Event.Event != world.EventLockJammed || level.Triggers[0].Event.LockName != "vault door" {
+		t.Errorf("Expected a lockout trigger on the vault door, got %+v", level.Triggers)
+	}
+
+	// Wrong codes already entered count against the exported limit
+	door.Lock.RejectCode()
+	exported := ExportLevel(level)
+	if exported.DoorData[0].MaxAttempts != 2 || exported.Enemies[0].Trigger.LockName != "vault door" {
+		t.Errorf("Expected the export to keep 2 attempts and the trigger, got %+v and %+v", exported.DoorData[0], exported.Enemies[0].Trigger)
+	}
+
+	tests := []struct {
+		name string
+		door string
+		path string
+	}{
+		{"key lock", `{"name": "vault door", "room_a": "hall", "room_b": "vault", "locked": true, "required_key_name": "key", "max_attempts": 3}`, "/doors/0/max_attempts"},
+		{"negative", `{"name": "vault door", "room_a": "hall", "room_b": "vault", "locked": true, "code": "2468", "max_attempts": -1}`, "/doors/0/max_attempts"},
+		{"no limit", `{"name": "vault door", "room_a": "hall", "room_b": "vault", "locked": true, "code": "2468"}`, "/enemies/0/trigger/lock_name"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.door))).Errors()
+			if len(errs) == 0 || errs[0].Path != test.path {
+				t.Errorf("Expected an error at %s, got %+v", test.path, errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_Durability(t *testing.T) {
+	const levelJSON = `{
+		"name": "durability test",
+		"rooms": [{"name": "workshop", "description": "a workshop", "items": [%s]}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON,
+		`{"name": "crowbar", "description": "a crowbar", "portable": true, "durability": 3, "wear": 1, "scrap": {"name": "bent bar", "description": "a bent bar", "portable": true}}`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	crowbar := findItemByName(level.Floors[0].Rooms[0].Items, "crowbar")
+	if crowbar.Durability == nil || crowbar.Durability.Remaining() != 2 || crowbar.Durability.Scrap == nil || crowbar.Durability.Scrap.Name != "bent bar" {
+		t.Fatalf("Expected a crowbar with 2 uses left that snaps into a bent bar, got %+v", crowbar.Durability)
+	}
+
+	crowbar.Durability.Wear()
+	exported := ExportLevel(level).Floors[0].Rooms[0].Items[0]
+	if exported.Durability != 3 || exported.Wear != 2 || exported.Scrap == nil {
+		t.Errorf("Expected the export to keep the wear and scrap, got %+v", exported)
+	}
+
+	tests := []struct {
+		name string
+		item string
+		path string
+	}{
+		{"negative", `{"name": "knife", "description": "a knife", "portable": true, "durability": -1}`, "/rooms/0/items/0/durability"},
+		{"worn past durability", `{"name": "knife", "description": "a knife", "portable": true, "durability": 2, "wear": 3}`, "/rooms/0/items/0/wear"},
+		{"scrap without durability", `{"name": "knife", "description": "a knife", "portable": true, "scrap": {"name": "blade", "portable": true}}`, "/rooms/0/items/0/durability"},
+		{"fixed item", `{"name": "lever", "description": "a lever", "durability": 2}`, "/rooms/0/items/0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.item))).Errors()
+			if len(errs) == 0 || errs[0].Path != test.path {
+				t.Errorf("Expected an error at %s, got %+v", test.path, errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_Charges(t *testing.T) {
+	const levelJSON = `{
+		"name": "charges test",
+		"rooms": [{"name": "bunker", "description": "a bunker", "items": [%s]}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON,
+		`{"name": "medkit", "description": "a medkit", "health_effect": "strong", "charges": 3, "charges_used": 1}`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	medkit := findItemByName(level.Floors[0].Rooms[0].Items, "medkit")
+	if medkit.Charges == nil || medkit.Charges.Remaining() != 2 || medkit.IsStackable() {
+		t.Fatalf("Expected a medkit with 2 of 3 charges left, got %+v", medkit.Charges)
+	}
+
+	medkit.Charges.Spend()
+	exported := ExportLevel(level).Floors[0].Rooms[0].Items[0]
+	if exported.Charges != 3 || exported.ChargesUsed != 2 {
+		t.Errorf("Expected the export to keep the charges used, got %+v", exported)
+	}
+
+	tests := []struct {
+		name string
+		item string
+		path string
+	}{
+		{"negative", `{"name": "medkit", "description": "a medkit", "health_effect": "weak", "charges": -1}`, "/rooms/0/items/0/charges"},
+		{"all used", `{"name": "medkit", "description": "a medkit", "health_effect": "weak", "charges": 2, "charges_used": 2}`, "/rooms/0/items/0/charges_used"},
+		{"used without charges", `{"name": "medkit", "description": "a medkit", "health_effect": "weak", "charges_used": 1}`, "/rooms/0/items/0/charges"},
+		{"weapon", `{"name": "pistol", "description": "a pistol", "portable": true, "weapon_damage": 0.5, "charges": 2}`, "/rooms/0/items/0/charges"},
+		{"fixed item", `{"name": "tap", "description": "a tap", "charges": 2}`, "/rooms/0/items/0/charges"},
+		{"stack", `{"name": "medkit", "description": "a medkit", "health_effect": "weak", "charges": 2, "quantity": 2}`, "/rooms/0/items/0/quantity"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.item))).Errors()
+			if len(errs) == 0 || errs[0].Path != test.path {
+				t.Errorf("Expected an error at %s, got %+v", test.path, errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_Traps(t *testing.T) {
+	const levelJSON = `{
+		"name": "traps test",
+		"rooms": [{"name": "crypt", "description": "a crypt", "items": [%s]}],
+		"enemies": [{"name": "mummy", "description": "a mummy", "hp": 3, "room": "crypt"}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON,
+		`{"name": "idol", "description": "an idol", "portable": true, "trap": {"effects": [{"effect": "poison"}, {"effect": "enter_combat", "target": "mummy"}]}}`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	idol := findItemByName(level.Floors[0].Rooms[0].Items, "idol")
+	if idol.Trap == nil || idol.Trap.On != world.TrapOnTake || len(idol.Trap.Effects) != 2 || idol.IsStackable() {
+		t.Fatalf("Expected a trap that springs on take, got %+v", idol.Trap)
+	}
+	if poison := idol.Trap.Effects[0]; poison.EffectType != world.EffectPoison || poison.TargetName != "idol" {
+		t.Errorf("Expected the poison to name the idol, got %+v", poison)
+	}
+	if combat := idol.Trap.Effects[1]; combat.EnemyName != "mummy" {
+		t.Errorf("Expected the trap to set the mummy on the player, got %+v", combat)
+	}
+
+	idol.Trap.Sprung = true
+	exported := ExportLevel(level).Floors[0].Rooms[0].Items[0].Trap
+	if exported == nil || !exported.Sprung || exported.Effects[0].Target != "" || exported.Effects[1].Target != "mummy" {
+		t.Errorf("Expected the export to keep the sprung trap, got %+v", exported)
+	}
+
+	tests := []struct {
+		name string
+		item string
+		path string
+	}{
+		{"fixed item", `{"name": "statue", "description": "a statue", "trap": {"effects": [{"effect": "damage"}]}}`, "/rooms/0/items/0/trap"},
+		{"bad trigger", `{"name": "idol", "description": "an idol", "portable": true, "trap": {"on": "drop", "effects": [{"effect": "damage"}]}}`, "/rooms/0/items/0/trap/on"},
+		{"no effects", `{"name": "idol", "description": "an idol", "portable": true, "trap": {"effects": []}}`, "/rooms/0/items/0/trap/effects"},
+		{"fixture effect", `{"name": "idol", "description": "an idol", "portable": true, "trap": {"effects": [{"effect": "complete_level"}]}}`, "/rooms/0/items/0/trap/effects/0/effect"},
+		{"no enemy", `{"name": "idol", "description": "an idol", "portable": true, "trap": {"effects": [{"effect": "enter_combat"}]}}`, "/rooms/0/items/0/trap/effects/0/target"},
+		{"missing enemy", `{"name": "idol", "description": "an idol", "portable": true, "trap": {"effects": [{"effect": "enter_combat", "target": "ghoul"}]}}`, "/rooms/0/items/0/trap/effects/0/target"},
+		{"stack", `{"name": "idol", "description": "an idol", "portable": true, "quantity": 2, "trap": {"effects": [{"effect": "damage"}]}}`, "/rooms/0/items/0/quantity"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diagnostics := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.item)))
+			problems := append(diagnostics.Errors(), diagnostics.Warnings()...)
+			if len(problems) == 0 || problems[0].Path != test.path {
+				t.Errorf("Expected a problem at %s, got %+v", test.path, problems)
+			}
+		})
+	}
+}
+
+func TestLoadGame_QuestItems(t *testing.T) {
+	const levelJSON = `{
+		"name": "quest items test",
+		"rooms": [
+			{"name": "hall", "description": "a hall", "connections": [{"door_name": "vault door", "direction": "north"}], "items": [
+				{"name": "brass key", "description": "a brass key", "key": true, "portable": true%s},
+				{"name": "string", "description": "a string", "portable": true},
+				{"name": "stick", "description": "a stick", "portable": true}
+			]},
+			{"name": "vault", "description": "a vault", "connections": [{"door_name": "vault door", "direction": "south"}]}
+		],
+		"doors": [{"name": "vault door", "room_a": "hall", "room_b": "vault", "locked": true, "required_key_name": "brass key"}],
+		"combo_items": [%s],
+		"win_condition": {"event": "room_entered", "room_name": "vault"}
+	}`
+	const keyOnString = `{"input_item_names": ["brass key", "string"], "output_item": {"name": "pendant", "description": "a pendant", "portable": true}}`
+	const bow = `{"input_item_names": ["stick", "string"], "output_item": {"name": "bow", "description": "a bow", "portable": true}}`
+
+	diagnostics := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, "", keyOnString)))
+	if errs := diagnostics.Errors(); len(errs) != 1 || errs[0].Path != "/rooms/0/items/0/quest_item" {
+		t.Errorf("Expected the key that could be crafted away to have to be a quest item, got %+v", errs)
+	}
+	if errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, "", bow))).Errors(); len(errs) != 0 {
+		t.Errorf("Expected items that aren't needed to win not to have to be quest items, got %+v", errs)
+	}
+
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, `, "quest_item": true`, bow)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if key := findItemByName(level.Floors[0].Rooms[0].Items, "brass key"); !key.QuestItem {
+		t.Error("Expected the brass key to be a quest item")
+	}
+	if exported := ExportLevel(level).Floors[0].Rooms[0].Items[0]; !exported.QuestItem {
+		t.Error("Expected the export to keep the quest item")
+	}
+
+	warnings := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, `, "quest_item": true`, keyOnString))).Warnings()
+	if len(warnings) == 0 || warnings[0].Path != "/combo_items/0/input_item_names/0" {
+		t.Errorf("Expected a warning that the recipe needs a quest item, got %+v", warnings)
+	}
+
+	tests := []struct {
+		name string
+		item string
+	}{
+		{"wears out", `, "durability": 2, "quest_item": true`},
+		{"thrown", `, "weapon_damage": 0.5, "thrown": {}, "quest_item": true`},
+		{"heals", `, "health_effect": "weak", "quest_item": true`},
+		{"supplies air", `, "air_supply": true, "quest_item": true`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.item, bow))).Errors()
+			if len(errs) == 0 || errs[0].Path != "/rooms/0/items/0/quest_item" {
+				t.Errorf("Expected an error at /rooms/0/items/0/quest_item, got %+v", errs)
+			}
+		})
+	}
+
+	chargedString := strings.Replace(fmt.Sprintf(levelJSON, "", bow), `"a string", "portable": true`, `"a string", "portable": true, "charges": 2, "quest_item": true`, 1)
+	if errs := ValidateLevel(json.RawMessage(chargedString)).Errors(); len(errs) == 0 || errs[0].Path != "/rooms/0/items/1/quest_item" {
+		t.Errorf("Expected an error at /rooms/0/items/1/quest_item for a quest item with charges, got %+v", errs)
+	}
+}
+
+func TestLoadGame_Quantity(t *testing.T) {
+	const levelJSON = `{
+		"name": "quantity test",
+		"rooms": [{"name": "clinic", "description": "a clinic", "items": [%s]}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON,
+		`{"name": "bandage", "description": "a bandage", "health_effect": "weak", "quantity": 3}`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	bandage := findItemByName(level.Floors[0].Rooms[0].Items, "bandage")
+	if bandage.Count() != 3 {
+		t.Fatalf("Expected a stack of 3 bandages, got %d", bandage.Count())
+	}
+	if exported := ExportLevel(level).Floors[0].Rooms[0].Items[0]; exported.Quantity != 3 {
+		t.Errorf("Expected the export to keep the quantity, got %d", exported.Quantity)
+	}
+
+	tests := []struct {
+		name string
+		item string
+	}{
+		{"negative", `{"name": "coin", "description": "a coin", "portable": true, "quantity": -2}`},
+		{"fixed item", `{"name": "lever", "description": "a lever", "quantity": 2}`},
+		{"weapon", `{"name": "knife", "description": "a knife", "weapon_damage": 1, "quantity": 2}`},
+		{"wears out", `{"name": "crowbar", "description": "a crowbar", "portable": true, "durability": 3, "quantity": 2}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.item))).Errors()
+			if len(errs) == 0 || errs[0].Path != "/rooms/0/items/0/quantity" {
+				t.Errorf("Expected an error at /rooms/0/items/0/quantity, got %+v", errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_Thrown(t *testing.T) {
+	const levelJSON = `{
+		"name": "thrown test",
+		"rooms": [{"name": "armory", "description": "an armory", "items": [%s]}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON,
+		`{"name": "grenade", "description": "a grenade", "weapon_damage": 0.9, "thrown": {"area": true}, "quantity": 2}`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	grenade := findItemByName(level.Floors[0].Rooms[0].Items, "grenade")
+	if !grenade.IsWeapon() || !grenade.Weapon.IsThrown() || !grenade.Weapon.Thrown.Area || grenade.Weapon.Thrown.StartsFire || grenade.Count() != 2 {
+		t.Fatalf("Expected a stack of 2 area grenades, got %+v", grenade)
+	}
+	if exported := ExportLevel(level).Floors[0].Rooms[0].Items[0]; exported.Thrown == nil || !exported.Thrown.Area {
+		t.Errorf("Expected the export to keep the thrown weapon, got %+v", exported.Thrown)
+	}
+
+	tests := []struct {
+		name string
+		item string
+		path string
+	}{
+		{"not a weapon", `{"name": "rock", "description": "a rock", "portable": true, "thrown": {}}`, "/rooms/0/items/0/thrown"},
+		{"uses ammo", `{"name": "launcher", "description": "a launcher", "weapon_damage": 0.9, "ammo": 2, "thrown": {}}`, "/rooms/0/items/0/thrown"},
+		{"wears out", `{"name": "axe", "description": "an axe", "weapon_damage": 0.9, "durability": 2, "thrown": {}}`, "/rooms/0/items/0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.item))).Errors()
+			if len(errs) == 0 || errs[0].Path != test.path {
+				t.Errorf("Expected an error at %s, got %+v", test.path, errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_Armor(t *testing.T) {
+	const levelJSON = `{
+		"name": "armor test",
+		"rooms": [{"name": "barracks", "description": "a barracks", "items": [%s]}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON,
+		`{"name": "helmet", "description": "a helmet", "armor": {"slot": "head"}, "durability": 3},
+		{"name": "vest", "description": "a vest", "armor": {"slot": "body", "block": 0.4}}`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	items := level.Floors[0].Rooms[0].Items
+	helmet, vest := findItemByName(items, "helmet"), findItemByName(items, "vest")
+	if !helmet.IsPortable() || helmet.Armor.Slot != "head" || helmet.Armor.Block != 1 || helmet.Durability.Max != 3 {
+		t.Errorf("Expected a portable helmet that always blocks and lasts 3 hits, got %+v", helmet)
+	}
+	if vest.Armor.Block != 0.4 {
+		t.Errorf("Expected the vest to block 40%% of hits, got %v", vest.Armor.Block)
+	}
+	exported := ExportLevel(level).Floors[0].Rooms[0].Items
+	if *exported[0].Armor != (ArmorData{Slot: "head"}) || *exported[1].Armor != (ArmorData{Slot: "body", Block: 0.4}) {
+		t.Errorf("Expected the export to keep the armor, got %+v and %+v", exported[0].Armor, exported[1].Armor)
+	}
+
+	tests := []struct {
+		name string
+		item string
+		path string
+	}{
+		{"no slot", `{"name": "vest", "description": "a vest", "armor": {}}`, "/rooms/0/items/0/armor/slot"},
+		{"block too high", `{"name": "vest", "description": "a vest", "armor": {"slot": "body", "block": 1.5}}`, "/rooms/0/items/0/armor/block"},
+		{"weapon", `{"name": "shield", "description": "a spiked shield", "weapon_damage": 0.3, "armor": {"slot": "arm"}}`, "/rooms/0/items/0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.item))).Errors()
+			if len(errs) == 0 || errs[0].Path != test.path {
+				t.Errorf("Expected an error at %s, got %+v", test.path, errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_Recipes(t *testing.T) {
+	const levelJSON = `{
+		"name": "recipe test",
+		"rooms": [{"name": "workshop", "description": "a workshop", "items": [
+			{"name": "rope", "description": "a rope", "portable": true},
+			{"name": "hook", "description": "a hook", "portable": true},
+			{"name": "oil", "description": "a bottle of oil", "portable": true},
+			{"name": "workbench", "description": "a workbench", "fixture": {"required_items": ["oil"]}}
+		]}],
+		"combo_items": [%s]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, `{
+		"input_item_names": ["rope", "hook", "oil"],
+		"fixture": "workbench",
+		"output_item": {"name": "grapple", "description": "a grapple", "portable": true},
+		"byproducts": [{"name": "empty bottle", "description": "an empty bottle", "portable": true}],
+		"known": true,
+		"taught_by": ["rope"]
+	}`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	combo := level.ComboItems[0]
+	if !slices.Equal(combo.InputNames(), []string{"rope", "hook", "oil"}) || combo.FixtureName != "workbench" || len(combo.Byproducts) != 1 {
+		t.Fatalf("Expected a three input recipe at the workbench with a byproduct, got %+v", combo)
+	}
+	exported := ExportLevel(level).ComboItems[0]
+	if len(exported.InputItemNames) != 3 || exported.InputItemAName != "" || exported.Fixture != "workbench" || len(exported.Byproducts) != 1 ||
+		!exported.Known || len(exported.TaughtBy) != 1 {
+		t.Errorf("Expected the export to keep the recipe, got %+v", exported)
+	}
+
+	const output = `"output_item": {"name": "grapple", "description": "a grapple", "portable": true}`
+	// Missing content only leaves the recipe unusable, which is a warning in a level without a win condition
+	tests := []struct {
+		name     string
+		combo    string
+		severity Severity
+		path     string
+	}{
+		{"one input", `{"input_item_names": ["rope"], ` + output + `}`, SeverityError, "/combo_items/0/input_item_names"},
+		{"five inputs", `{"input_item_names": ["rope", "hook", "oil", "a", "b"], ` + output + `}`, SeverityError, "/combo_items/0/input_item_names"},
+		{"repeated input", `{"input_item_names": ["rope", "hook", "rope"], ` + output + `}`, SeverityError, "/combo_items/0/input_item_names/2"},
+		{"inputs named twice", `{"input_item_a_name": "rope", "input_item_names": ["rope", "hook"], ` + output + `}`, SeverityError, "/combo_items/0/input_item_names"},
+		{"no inputs", `{` + output + `}`, SeverityError, "/combo_items/0"},
+		{"missing input", `{"input_item_names": ["rope", "hook", "chain"], ` + output + `}`, SeverityWarning, "/combo_items/0/input_item_names/2"},
+		{"missing fixture", `{"input_item_names": ["rope", "hook"], "fixture": "anvil", ` + output + `}`, SeverityWarning, "/combo_items/0/fixture"},
+		{"not a fixture", `{"input_item_names": ["rope", "hook"], "fixture": "oil", ` + output + `}`, SeverityWarning, "/combo_items/0/fixture"},
+		{"missing teacher", `{"input_item_names": ["rope", "hook"], "taught_by": ["manual"], ` + output + `}`, SeverityWarning, "/combo_items/0/taught_by/0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diagnostics := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.combo)))
+			if !slices.ContainsFunc(diagnostics, func(d Diagnostic) bool { return d.Severity == test.severity && d.Path == test.path }) {
+				t.Errorf("Expected a %s at %s, got %+v", test.severity, test.path, diagnostics)
+			}
+		})
+	}
+}
+
+func TestLoadGame_FixtureStages(t *testing.T) {
+	const levelJSON = `{
+		"name": "fixture test",
+		"rooms": [{"name": "generator room", "description": "a generator room", "items": [
+			{"name": "fuse", "description": "a fuse", "portable": true},
+			{"name": "crank", "description": "a crank", "portable": true},
+			{"name": "generator", "description": "a generator", "fixture": %s}
+		]}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, `{
+		"stages": [{"required_items": ["fuse"], "narrative": "It is primed"}, {"required_items": ["crank"]}],
+		"insert_narratives": [{"item": "crank", "narrative": "The crank turns"}],
+		"reveal_missing": "count"
+	}`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	fixture := findItemByName(level.Floors[0].Rooms[0].Items, "generator").Fixture
+	if len(fixture.Stages) != 2 || len(fixture.RequiredItems) != 2 || fixture.InsertNarratives["crank"] != "The crank turns" || fixture.RevealMissing != world.MissingItemsCount {
+		t.Fatalf("Expected a two stage fixture, got %+v", fixture)
+	}
+
+	fixture.RequiredItems["fuse"] = true
+	exported := ExportLevel(level).Floors[0].Rooms[0].Items[2].Fixture
+	if len(exported.Stages) != 1 || exported.Stages[0].RequiredItems[0] != "crank" || len(exported.InsertNarratives) != 1 || exported.RevealMissing != "count" {
+		t.Errorf("Expected the export to keep only the stage still to do, got %+v", exported)
+	}
+
+	tests := []struct {
+		name    string
+		fixture string
+		path    string
+	}{
+		{"stages and required items", `{"required_items": ["fuse"], "stages": [{"required_items": ["crank"]}]}`, "/rooms/0/items/2/fixture/stages"},
+		{"empty stage", `{"stages": [{"required_items": []}]}`, "/rooms/0/items/2/fixture/stages/0/required_items"},
+		{"item in two stages", `{"stages": [{"required_items": ["fuse"]}, {"required_items": ["fuse"]}]}`, "/rooms/0/items/2/fixture/stages/1/required_items/0"},
+		{"narrative for an unneeded item", `{"required_items": ["fuse"], "insert_narratives": [{"item": "crank", "narrative": "no"}]}`, "/rooms/0/items/2/fixture/insert_narratives/0/item"},
+		{"unknown hint", `{"required_items": ["fuse"], "reveal_missing": "some"}`, "/rooms/0/items/2/fixture/reveal_missing"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.fixture))).Errors()
+			if len(errs) == 0 || errs[0].Path != test.path {
+				t.Errorf("Expected an error at %s, got %+v", test.path, errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_FixtureEffects(t *testing.T) {
+	const levelJSON = `{
+		"name": "fixture effects test",
+		"win_condition": {"event": "room_entered", "room_name": "vault"},
+		"rooms": [
+			{"name": "hall", "description": "a hall", "connections": [{"location": "north", "door_name": "panel"}], "items": [
+				{"name": "crank", "description": "a crank", "portable": true},
+				{"name": "winch", "description": "a winch", "fixture": {"required_items": ["crank"], "on_complete": %s}}
+			]},
+			{"name": "vault", "description": "a vault", "connections": [{"location": "south", "door_name": "panel"}]}
+		],
+		"doors": [{"name": "panel", "room_a": "hall", "room_b": "vault", "hidden": true}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, `[{"effect": "reveal_door", "target": "panel"}]`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	winch := findItemByName(level.Floors[0].Rooms[0].Items, "winch").Fixture
+	if !level.GetDoor("panel").Hidden || len(winch.OnComplete) != 1 || winch.OnComplete[0].TargetName != "panel" {
+		t.Fatalf("Expected a hidden door revealed by the winch, got %+v", winch.OnComplete)
+	}
+	exported := ExportLevel(level)
+	if !exported.DoorData[0].Hidden || len(exported.Floors[0].Rooms[0].Items[1].Fixture.OnComplete) != 1 {
+		t.Errorf("Expected the export to keep the hidden door and the winch's effect, got %+v", exported.DoorData[0])
+	}
+
+	if _, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, `[{"effect": "complete_level"}]`))); err != nil {
+		t.Errorf("Expected completing the winch to win the level, got %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		effects string
+		path    string
+	}{
+		{"no effects", `[]`, "/doors/0"},
+		{"unknown effect", `[{"effect": "explode", "target": "panel"}]`, "/rooms/0/items/1/fixture/on_complete/0/effect"},
+		{"missing target", `[{"effect": "unlock"}]`, "/rooms/0/items/1/fixture/on_complete/0/target"},
+		{"unknown target", `[{"effect": "reveal_door", "target": "trapdoor"}]`, "/rooms/0/items/1/fixture/on_complete/0/target"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.effects))).Errors()
+			if len(errs) == 0 || errs[0].Path != test.path {
+				t.Errorf("Expected an error at %s, got %+v", test.path, errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_DoorRevealOn(t *testing.T) {
+	const levelJSON = `{
+		"name": "reveal on test",
+		"win_condition": {"event": "room_entered", "room_name": "crypt"},
+		"rooms": [
+			{"name": "library", "description": "a library", "connections": [{"location": "north", "door_name": "passage"}], "items": [
+				{"name": "candlestick", "description": "a candlestick", "portable": true}
+			]},
+			{"name": "crypt", "description": "a crypt", "connections": [{"location": "south", "door_name": "passage"}]}
+		],
+		"doors": [{"name": "passage", "room_a": "library", "room_b": "crypt", %s}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, `"hidden": true, "reveal_on": {"event": "item_taken", "item_name": "candlestick"}`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if len(level.Triggers) != 1 || level.Triggers[0].EffectType != world.EffectRevealDoor || level.Triggers[0].Effect.TargetName != "passage" {
+		t.Fatalf("Expected a trigger revealing the passage, got %+v", level.Triggers)
+	}
+	exported := ExportLevel(level)
+	if revealOn := exported.DoorData[0].RevealOn; revealOn == nil || revealOn.ItemName != "candlestick" {
+		t.Errorf("Expected the export to keep the passage's reveal event, got %+v", revealOn)
+	}
+
+	tests := []struct {
+		name string
+		door string
+		path string
+	}{
+		{"not hidden", `"reveal_on": {"event": "item_taken", "item_name": "candlestick"}`, "/doors/0/reveal_on"},
+		{"unknown item", `"hidden": true, "reveal_on": {"event": "item_taken", "item_name": "skull"}`, "/doors/0/reveal_on/item_name"},
+		{"never revealed", `"hidden": true, "reveal_on": {"event": "room_entered", "room_name": "crypt"}`, "/doors/0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.door))).Errors()
+			if len(errs) == 0 || errs[0].Path != test.path {
+				t.Errorf("Expected an error at %s, got %+v", test.path, errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_TravelNodes(t *testing.T) {
+	const levelJSON = `{
+		"name": "travel test",
+		"win_condition": {"event": "room_entered", "room_name": "helipad"},
+		"rooms": [
+			{"name": "lobby", "description": "a lobby", "travel_node": {"name": "lobby lift", "network": "lifts"}, "items": [
+				{"name": "fuse", "description": "a fuse", "portable": true},
+				{"name": "fuse box", "description": "a fuse box", "fixture": {"required_items": ["fuse"], "on_complete": [{"effect": "power", "target": "%s"}]}}
+			]},
+			{"name": "helipad", "description": "a helipad", "travel_node": {"name": "%s", "network": "lifts", "unpowered": true, "discovered": true}}
+		]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, "roof lift", "roof lift")))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	node := level.Floors[0].Rooms[1].TravelNode
+	if node == nil || node.Name != "roof lift" || node.Network != "lifts" || !node.Unpowered || !node.Discovered {
+		t.Fatalf("Expected the unpowered roof lift, got %+v", node)
+	}
+	exported := ExportLevel(level)
+	if exported.Floors[0].Rooms[1].TravelNode == nil || !exported.Floors[0].Rooms[1].TravelNode.Unpowered {
+		t.Errorf("Expected the export to keep the roof lift, got %+v", exported.Floors[0].Rooms[1].TravelNode)
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		node   string
+		path   string
+	}{
+		{"never powered", "cargo lift", "roof lift", "/rooms/0/items/1/fixture/on_complete/0/target"},
+		{"duplicate node", "lobby lift", "lobby lift", "/rooms/1/travel_node/name"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.target, test.node))).Errors()
+			if len(errs) == 0 || errs[0].Path != test.path {
+				t.Errorf("Expected an error at %s, got %+v", test.path, errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_Airless(t *testing.T) {
+	const levelJSON = `{
+		"name": "airless test",
+		"breath": %d,
+		"rooms": [
+			{"name": "dock", "description": "a dock", "connections": [{"location": "down", "door_name": "hatch"}], "items": [
+				{"name": "air tank", "description": "an air tank", "air_supply": true}
+			]},
+			{"name": "wreck", "description": "a sunken wreck", "airless": true, "connections": [{"location": "up", "door_name": "hatch"}]}
+		],
+		"doors": [{"name": "hatch", "room_a": "dock", "room_b": "wreck"}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, 3)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if level.GetBreath() != 3 || !level.Floors[0].Rooms[1].Airless {
+		t.Errorf("Expected an airless wreck and 3 breaths, got %v and %d", level.Floors[0].Rooms[1].Airless, level.GetBreath())
+	}
+	if tank := level.Floors[0].Rooms[0].Items[0]; !tank.IsAirSupply() || !tank.IsPortable() {
+		t.Errorf("Expected a portable air tank, got %+v", tank)
+	}
+	exported := ExportLevel(level)
+	if exported.Breath != 3 || !exported.Floors[0].Rooms[1].Airless || !exported.Floors[0].Rooms[0].Items[0].AirSupply {
+		t.Errorf("Expected the export to keep the breath, airless wreck and air tank, got %+v", exported)
+	}
+
+	errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, -1))).Errors()
+	if len(errs) == 0 || errs[0].Path != "/breath" {
+		t.Errorf("Expected an error at /breath, got %+v", errs)
+	}
+}
+
+func TestLoadGame_Critical(t *testing.T) {
+	const levelJSON = `{
+		"name": "critical test",
+		"rooms": [{"name": "armory", "description": "an armory", "items": [%s]}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON,
+		`{"name": "axe", "description": "an axe", "weapon_damage": 0.8, "critical": {"chance": 0.2, "narrative": "The axe bites deep.", "fumble": 0.1}}`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	critical := level.Floors[0].Rooms[0].Items[0].Weapon.Critical
+	if critical == nil || critical.Chance != 0.2 || critical.Damage != 1 || critical.Fumble != 0.1 || critical.Narrative != "The axe bites deep." {
+		t.Errorf("Expected critical hits costing 1 extra hp, got %+v", critical)
+	}
+	if exported := ExportLevel(level); *exported.Floors[0].Rooms[0].Items[0].Critical != (CriticalData{Chance: 0.2, Damage: 1, Narrative: "The axe bites deep.", Fumble: 0.1}) {
+		t.Errorf("Expected the export to keep the critical hits, got %+v", exported.Floors[0].Rooms[0].Items[0].Critical)
+	}
+
+	tests := []struct {
+		name string
+		item string
+		path string
+	}{
+		{"not a weapon", `{"name": "rock", "description": "a rock", "portable": true, "critical": {"chance": 0.2}}`, "/rooms/0/items/0/critical"},
+		{"chance too high", `{"name": "axe", "description": "an axe", "weapon_damage": 0.8, "critical": {"chance": 2}}`, "/rooms/0/items/0/critical/chance"},
+		{"thrown fumble", `{"name": "grenade", "description": "a grenade", "weapon_damage": 0.8, "thrown": {}, "critical": {"fumble": 0.1}}`, "/rooms/0/items/0/critical/fumble"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.item))).Errors()
+			if len(errs) == 0 || errs[0].Path != test.path {
+				t.Errorf("Expected an error at %s, got %+v", test.path, errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_SubLocations(t *testing.T) {
+	level, err := LoadGame(json.RawMessage(`{
+		"name": "sub-location test",
+		"rooms": [{"name": "study", "description": "a study",
+			"sub_locations": [{"name": "desk"}, {"name": "window", "preposition": "under"}],
+			"items": [
+				{"name": "key", "description": "a key", "key": true, "sub_location": "window"},
+				{"name": "pen", "description": "a pen", "portable": true, "location": "in the pen tray", "sub_location": "desk"}
+			]}]
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	room := level.Floors[0].Rooms[0]
+	if key := room.Items[0]; key.SubLocation != "window" || key.Location != "under the window" {
+		t.Errorf("Expected the key under the window to be located there, got %+v", key)
+	}
+	if pen := room.Items[1]; pen.Location != "in the pen tray" {
+		t.Errorf("Expected the pen to keep its own location, got %q", pen.Location)
+	}
+	exported := ExportLevel(level)
+	if subLocations := exported.Floors[0].Rooms[0].SubLocations; len(subLocations) != 2 || subLocations[0].Preposition != "on" {
+		t.Errorf("Expected the export to keep the sub-locations, got %+v", subLocations)
+	}
+
+	errs := ValidateLevel(json.RawMessage(`{
+		"name": "sub-location test",
+		"rooms": [{"name": "study", "description": "a study",
+			"sub_locations": [{"name": "desk"}, {"name": "desk"}],
+			"items": [
+				{"name": "key", "description": "a key", "key": true, "sub_location": "shelf"},
+				{"name": "box", "description": "a box", "contains": {"name": "pen", "description": "a pen", "portable": true, "sub_location": "desk"}}
+			]}]
+	}`)).Errors()
+	var paths []string
+	for _, err := range errs {
+		paths = append(paths, err.Path)
+	}
+	expected := []string{"/rooms/0/sub_locations/1/name", "/rooms/0/items/0/sub_location", "/rooms/0/items/1/contains/sub_location"}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("Expected errors at %v, got %+v", expected, errs)
+	}
+}
+
+func TestLoadGame_Wanders(t *testing.T) {
+	const levelJSON = `{
+		"name": "wander test",
+		"rest_encounter": %v,
+		"rooms": [{"name": "camp", "description": "a camp"}],
+		"enemies": [{"name": "wolf", "description": "a wolf", "hp": 2, "wanders": true}]
+	}`
+	diagnostics := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, 0.2)))
+	if len(diagnostics.Errors()) != 0 || len(diagnostics.Warnings()) != 0 {
+		t.Errorf("Expected a wandering enemy to be met without a room or trigger, got %+v", diagnostics)
+	}
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, 0.2)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if exported := ExportLevel(level); !exported.Enemies[0].Wanders || exported.RestEncounter != 0.2 {
+		t.Errorf("Expected the export to keep the wandering wolf and rest encounters, got %+v", exported)
+	}
+
+	errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, 1.5))).Errors()
+	if len(errs) == 0 || errs[0].Path != "/rest_encounter" {
+		t.Errorf("Expected an error at /rest_encounter, got %+v", errs)
+	}
+}
+
+func TestLoadGame_Combat(t *testing.T) {
+	const levelJSON = `{
+		"name": "combat test",
+		"combat": %q,
+		"rooms": [{"name": "arena", "description": "an arena"}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, "opposed")))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if level.Combat != world.CombatOpposed {
+		t.Errorf("Expected opposed combat, got %q", level.Combat)
+	}
+	if exported := ExportLevel(level); exported.Combat != "opposed" {
+		t.Errorf("Expected the export to keep opposed combat, got %q", exported.Combat)
+	}
+
+	errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, "dice"))).Errors()
+	if len(errs) == 0 || errs[0].Path != "/combat" {
+		t.Errorf("Expected an error at /combat, got %+v", errs)
+	}
+}
+
+func TestLoadGame_Moveable(t *testing.T) {
+	const levelJSON = `{
+		"name": "moveable test",
+		"win_condition": {"event": "room_entered", "room_name": "hideout"},
+		"rooms": [
+			{"name": "study", "description": "a study", "connections": [{"location": "north", "door_name": "passage"}], "items": [
+				{"name": "bookcase", "description": "a bookcase", "moveable": %s}
+			]},
+			{"name": "hideout", "description": "a hideout", "connections": [{"location": "south", "door_name": "passage"}]}
+		],
+		"doors": [{"name": "passage", "room_a": "study", "room_b": "hideout", "hidden": true}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, `{
+		"directions": ["push", "pull"],
+		"reveals": {"name": "letter", "description": "a letter", "portable": true},
+		"reveals_door": "passage"
+	}`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	bookcase := findItemByName(level.Floors[0].Rooms[0].Items, "bookcase")
+	if !bookcase.IsMoveable() || bookcase.Moveable.Reveals.Name != "letter" || bookcase.Moveable.RevealsDoor != "passage" {
+		t.Fatalf("Expected a moveable bookcase, got %+v", bookcase.Moveable)
+	}
+	exported := ExportLevel(level).Floors[0].Rooms[0].Items[0].Moveable
+	if exported == nil || exported.Reveals.Name != "letter" || len(exported.Directions) != 2 {
+		t.Errorf("Expected the export to keep the bookcase, got %+v", exported)
+	}
+
+	tests := []struct {
+		name     string
+		moveable string
+		path     string
+	}{
+		{"nothing revealed", `{}`, "/doors/0"},
+		{"unknown door", `{"reveals_door": "trapdoor"}`, "/rooms/0/items/0/moveable/reveals_door"},
+		{"moveable behind it", `{"reveals": {"name": "rug", "description": "a rug", "moveable": {}}}`, "/rooms/0/items/0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.moveable))).Errors()
+			if len(errs) == 0 || errs[0].Path != test.path {
+				t.Errorf("Expected an error at %s, got %+v", test.path, errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_DoorCodeLocks(t *testing.T) {
+	// Test JSON data with a door that has a code lock
+	jsonData := `{
+		"name": "code lock test",
+		"floors": [
+			{
+				"name": "main floor",
+				"description": "test floor",
+				"rooms": [
+					{
+						"name": "room A",
+						"description": "first room",
+						"connections": [
+							{
+								"location": "north",
+								"door_name": "code_door"
+							}
+						]
+					},
+					{
+						"name": "room B",
+						"description": "second room",
+						"connections": [
+							{
+								"location": "south",
+								"door_name": "code_door"
+							}
+						]
+					}
+				]
+			}
+		],
+		"doors": [
+			{
+				"name": "code_door",
+				"room_a": "room A",
+				"room_b": "room B",
+				"locked": true,
+				"code": "1234"
+			}
+		]
+	}`
+
+	level, err := LoadGame([]byte(jsonData))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+
+	// Test basic game properties
+	if level.Name != "code lock test" {
+		t.Errorf("Expected game name 'code lock test', got '%s'", level.Name)
+	}
+
+	// Test doors
+	if len(level.Doors) != 1 {
+		t.Errorf("Expected 1 door, got %d", len(level.Doors))
+	}
+
+	// Test the code door
+	codeDoor := findDoorByName(level.Doors, "code_door")
+	if codeDoor == nil {
+		t.Fatal("Could not find code_door")
+	}
+
+	if !codeDoor.IsLocked() {
+		t.Error("Expected code_door to be locked")
+	}
+
+	if !codeDoor.HasCodeLock() {
+		t.Error("Expected code_door to have a code lock")
+	}
+
+	if codeDoor.HasKeyLock() {
+		t.Error("Expected code_door to not have a key lock")
+	}
+
+	if codeDoor.Lock == nil {
+		t.Fatal("Expected code_door to have lock data")
+	}
+
+	if codeDoor.Lock.Code != "1234" {
+		t.Errorf("Expected code_door code to be '1234', got '%s'", codeDoor.Lock.Code)
+	}
+
+	if codeDoor.Lock.KeyName != "" {
+		t.Errorf("Expected code_door key name to be empty, got '%s'", codeDoor.Lock.KeyName)
+	}
+
+	// Test rooms
+	allRooms := getAllRooms(level)
+	if len(allRooms) != 2 {
+		t.Errorf("Expected 2 rooms, got %d", len(allRooms))
+	}
+
+	// Test room connections
+	roomA := findRoomByName(allRooms, "room A")
+	if roomA == nil {
+		t.Fatal("Could not find room A")
+	}
+
+	if len(roomA.Connections) != 1 {
+		t.Errorf("Expected room A to have 1 connection, got %d", len(roomA.Connections))
+	}
+
+	roomB := findRoomByName(allRooms, "room B")
+	if roomB == nil {
+		t.Fatal("Could not find room B")
+	}
+
+	if len(roomB.Connections) != 1 {
+		t.Errorf("Expected room B to have 1 connection, got %d", len(roomB.Connections))
+	}
+}
+
+func TestLoadGame_IntroOutroNarrative(t *testing.T) {
+	// Load the kill enemy win test game which has intro and outro narrative
+	level, err := LoadGameFromFile("../../internal/testdata/kill_enemy_win.json")
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+
+	// Test intro and outro narrative
+	if level.IntroNarrative != "foo" {
+		t.Errorf("Expected intro narrative to be 'foo', got '%s'", level.IntroNarrative)
+	}
+	if level.OutroNarrative != "bar" {
+		t.Errorf("Expected outro narrative to be 'bar', got '%s'", level.OutroNarrative)
+	}
+
+	// Test that other fields are still loaded correctly
+	if level.Name != "kill enemy win" {
+		t.Errorf("Expected game name 'kill enemy win', got '%s'", level.Name)
+	}
+
+	exported := ExportLevel(level)
+	if exported.IntroNarrative != "foo" || exported.OutroNarrative != "bar" {
+		t.Errorf("Expected the narratives to be exported, got %q and %q", exported.IntroNarrative, exported.OutroNarrative)
+	}
+}
+
+func TestLoadGame_FailureNarrative(t *testing.T) {
+	level, err := LoadGame(json.RawMessage(`{
+		"name": "failure test",
+		"failure_narrative": "The dark takes you.",
+		"rooms": [{"name": "cell", "description": "a cell"}]
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if level.FailureNarrative != "The dark takes you." {
+		t.Errorf("Expected the failure narrative, got %q", level.FailureNarrative)
+	}
+	if exported := ExportLevel(level); exported.FailureNarrative != level.FailureNarrative {
+		t.Errorf("Expected the failure narrative to be exported, got %q", exported.FailureNarrative)
+	}
+}
+
+func TestLoadGame_FixtureCompletionNarrative(t *testing.T) {
+	level, err := LoadGame(json.RawMessage(`{
+		"name": "completion narrative test",
+		"rooms": [{"name": "hall", "description": "a hall", "items": [
+			{"name": "fuse", "description": "a fuse", "portable": true},
+			{"name": "generator", "description": "a generator", "fixture": {"required_items": ["fuse"], "completion_narrative": "The generator roars to life"}}
+		]}]
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	generator := findItemByName(level.Floors[0].Rooms[0].Items, "generator").Fixture
+	if generator.CompletionNarrative != "The generator roars to life" {
+		t.Errorf("Expected the generator's completion narrative, got %q", generator.CompletionNarrative)
+	}
+	if exported := ExportLevel(level).Floors[0].Rooms[0].Items[1].Fixture; exported.CompletionNarrative != generator.CompletionNarrative {
+		t.Errorf("Expected the completion narrative to be exported, got %q", exported.CompletionNarrative)
+	}
+}
+
+func TestLoadGame_DoorAndInitialDescriptions(t *testing.T) {
+	data := json.RawMessage(`{
+		"name": "descriptions test",
+		"rooms": [
+			{"name": "hall", "description": "a hall", "initial_description": "a hall, dusty and long forgotten",
+				"connections": [{"location": "at the far end", "door_name": "oak door", "description": "a heavy oak door"}]},
+			{"name": "porch", "description": "a porch", "connections": [{"door_name": "oak door"}]}
+		],
+		"doors": [{"name": "oak door", "room_a": "hall", "room_b": "porch"}]
+	}`)
+	level, err := LoadGame(data)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	_, hall := level.FindRoom("hall")
+	if hall.InitialDescription != "a hall, dusty and long forgotten" {
+		t.Errorf("Expected the hall's initial description, got %q", hall.InitialDescription)
+	}
+	if hall.Connections[0].Description != "a heavy oak door" {
+		t.Errorf("Expected the oak door's description, got %q", hall.Connections[0].Description)
+	}
+
+	exported := ExportLevel(level)
+	room := exported.Floors[0].Rooms[0]
+	if room.InitialDescription != hall.InitialDescription || room.Connections[0].Description != "a heavy oak door" {
+		t.Errorf("Expected the descriptions to be exported, got %+v", room)
+	}
+}
+
+func TestLoadGame_Scoring(t *testing.T) {
+	jsonData := json.RawMessage(`{
+		"name": "scoring test",
+		"rooms": [
+			{
+				"name": "test room",
+				"description": "a test room",
+				"items": [
+					{
+						"name": "gem",
+						"description": "a hidden gem",
+						"portable": true,
+						"secret": true
+					}
+				]
+			}
+		],
+		"scoring": {
+			"base_points": 500,
+			"turn_penalty": 0,
+			"badges": [
+				{
+					"name": "pacifist",
+					"description": "defeat no enemies",
+					"max_turns": 10
+				}
+			]
+		}
+	}`)
+
+	level, err := LoadGame(jsonData)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if level.Scoring == nil {
+		t.Fatal("Expected scoring to be set")
+	}
+	if level.Scoring.BasePoints != 500 {
+		t.Errorf("Expected base points 500, got %d", level.Scoring.BasePoints)
+	}
+	if level.Scoring.TurnPenalty != 0 {
+		t.Errorf("Expected turn penalty 0, got %d", level.Scoring.TurnPenalty)
+	}
+	if level.Scoring.SecretBonus != world.DefaultScoring().SecretBonus {
+		t.Errorf("Expected default secret bonus, got %d", level.Scoring.SecretBonus)
+	}
+	if len(level.Scoring.Badges) != 1 || *level.Scoring.Badges[0].Condition.MaxTurns != 10 {
+		t.Errorf("Expected one badge with max_turns 10, got %+v", level.Scoring.Badges)
+	}
+	if !getAllRooms(level)[0].Items[0].Secret {
+		t.Error("Expected gem to be marked as secret")
+	}
+
+	// Duplicate badge names are rejected
+	jsonData = json.RawMessage(`{
+		"name": "scoring test",
+		"rooms": [{"name": "test room", "description": "a test room"}],
+		"scoring": {"badges": [{"name": "a"}, {"name": "a"}]}
+	}`)
+	if _, err := LoadGame(jsonData); err == nil || !strings.Contains(err.Error(), "duplicate badge name") {
+		t.Errorf("Expected duplicate badge error, got %v", err)
+	}
+}
+
+func TestLoadGame_Aliases(t *testing.T) {
+	aliasesLevel := func(hoodieAliases string) json.RawMessage {
+		return json.RawMessage(`{
+			"name": "aliases test",
+			"rooms": [
+				{"name": "hall", "description": "a hall", "connections": [{"door_name": "oak door"}],
+					"items": [
+						{"name": "grey hoodie", "description": "a hoodie", "detail": "it is grey", "portable": true, "aliases": ` + hoodieAliases + `},
+						{"name": "sweatshirt", "description": "a sweatshirt", "detail": "it is red", "portable": true}
+					]},
+				{"name": "porch", "description": "a porch", "connections": [{"door_name": "oak door"}]}
+			],
+			"doors": [{"name": "oak door", "room_a": "hall", "room_b": "porch", "aliases": ["front door"]}]
+		}`)
+	}
+
+	level, err := LoadGame(aliasesLevel(`["hoodie"]`))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	hoodie, err := getAllRooms(level)[0].GetItem("grey hoodie")
+	if err != nil {
+		t.Fatalf("Expected grey hoodie: %v", err)
+	}
+	if len(hoodie.Aliases) != 1 || hoodie.Aliases[0] != "hoodie" {
+		t.Errorf("Expected hoodie alias, got %v", hoodie.Aliases)
+	}
+	if door := level.GetDoor("oak door"); len(door.Aliases) != 1 || door.Aliases[0] != "front door" {
+		t.Errorf("Expected front door alias, got %v", door.Aliases)
+	}
+
+	diagnostics := ValidateLevel(aliasesLevel(`["hoodie", "Hoodie"]`))
+	if errors := diagnostics.Errors(); len(errors) == 0 || errors[0].Path != "/rooms/0/items/0/aliases/1" {
+		t.Errorf("Expected duplicate alias error, got %+v", diagnostics)
+	}
+
+	_, warnings, err := LoadGameWithDiagnostics(aliasesLevel(`["sweatshirt"]`), 0)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	for _, warning := range warnings {
+		if strings.Contains(warning.Message, "alias sweatshirt of item grey hoodie is the name of something else") {
+			return
+		}
+	}
+	t.Errorf("Expected warning about the shadowed alias, got %+v", warnings)
+}
+
+func TestLoadGame_VerbAliases(t *testing.T) {
+	verbAliasesLevel := func(verbAliases string) json.RawMessage {
+		return json.RawMessage(`{
+			"name": "verb aliases test",
+			"verb_aliases": ` + verbAliases + `,
+			"rooms": [{"name": "hall", "description": "a hall"}]
+		}`)
+	}
+
+	level, err := LoadGame(verbAliasesLevel(`{"pry": "use crowbar on", "smash": "attack"}`))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if len(level.VerbAliases) != 2 || level.VerbAliases["pry"] != "use crowbar on" {
+		t.Errorf("Expected the verb aliases, got %v", level.VerbAliases)
+	}
+	if exported := ExportLevel(level); exported.VerbAliases["smash"] != "attack" {
+		t.Errorf("Expected the verb aliases to be exported, got %v", exported.VerbAliases)
+	}
+
+	tests := []struct {
+		verbAliases string
+		path        string
+	}{
+		{`{" ": "attack"}`, "/verb_aliases/ "},
+		{`{"Pry": "use crowbar on", "pry": "use crowbar on"}`, "/verb_aliases/pry"},
+		{`{"pry": ""}`, "/verb_aliases/pry"},
+	}
+	for _, test := range tests {
+		diagnostics := ValidateLevel(verbAliasesLevel(test.verbAliases))
+		if errors := diagnostics.Errors(); len(errors) == 0 || errors[0].Path != test.path {
+			t.Errorf("Expected an error at %s for %s, got %+v", test.path, test.verbAliases, diagnostics)
+		}
+	}
+}
+
+func TestLoadGame_EnemyRooms(t *testing.T) {
+	const levelJSON = `{
+		"name": "enemy rooms test",
+		"rooms": [
+			{"name": "hall", "description": "a hall", "connections": [{"door_name": "crypt door"}],
+				"items": [{"name": "idol", "description": "an idol", "detail": "it glows", "portable": true}]},
+			{"name": "crypt", "description": "a crypt", "connections": [{"door_name": "crypt door"}]}
+		],
+		"doors": [{"name": "crypt door", "room_a": "hall", "room_b": "crypt"}],
+		"enemies": [%s]
+	}`
+	tests := []struct {
+		name    string
+		enemy   string
+		trigger world.Event
+	}{
+		{"room", `{"name": "ghoul", "hp": 1, "room": "crypt"}`, world.Event{Event: world.EventRoomEntered, RoomName: "crypt"}},
+		{"room entered in the enemy's room", `{"name": "ghoul", "hp": 1, "room": "crypt", "trigger": {"event": "room_entered"}}`, world.Event{Event: world.EventRoomEntered, RoomName: "crypt"}},
+		{"room entered elsewhere", `{"name": "ghoul", "hp": 1, "room": "crypt", "trigger": {"event": "room_entered", "room_name": "hall"}}`, world.Event{Event: world.EventRoomEntered, RoomName: "hall"}},
+		{"other trigger", `{"name": "ghoul", "hp": 1, "room": "crypt", "trigger": {"event": "item_taken", "item_name": "idol"}}`, world.Event{Event: world.EventItemTaken, ItemName: "idol"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, test.enemy)))
+			if err != nil {
+				t.Fatalf("Failed to load game: %v", err)
+			}
+			if enemy := level.GetEnemy("ghoul"); enemy.Room != "crypt" {
+				t.Errorf("Expected the ghoul in the crypt, got %q", enemy.Room)
+			}
+			if len(level.Triggers) != 1 || level.Triggers[0].Event != test.trigger || level.Triggers[0].Effect.EnemyName != "ghoul" {
+				t.Errorf("Expected the ghoul to attack on %+v, got %+v", test.trigger, level.Triggers)
+			}
+			if exported := ExportLevel(level).Enemies[0]; exported.Room != "crypt" || exported.Trigger == nil {
+				t.Errorf("Expected the ghoul's room and trigger to be exported, got %+v", exported)
+			}
+		})
+	}
+
+	invalid := []struct {
+		name  string
+		enemy string
+		path  string
+	}{
+		{"unknown room", `{"name": "ghoul", "hp": 1, "room": "attic"}`, "/enemies/0/room"},
+		{"room entered without a room", `{"name": "ghoul", "hp": 1, "trigger": {"event": "room_entered"}}`, "/enemies/0/trigger/room_name"},
+		{"room entered in an unknown room", `{"name": "ghoul", "hp": 1, "room": "crypt", "trigger": {"event": "room_entered", "room_name": "attic"}}`, "/enemies/0/trigger/room_name"},
+	}
+	for _, test := range invalid {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, test.enemy))).Errors()
+			if len(errs) == 0 || errs[0].Path != test.path {
+				t.Errorf("Expected an error at %s, got %+v", test.path, errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_ItemLocations(t *testing.T) {
+	const levelJSON = `{
+		"name": "item locations test",
+		"rooms": [
+			{"name": "study", "description": "a study", "connections": [{"door_name": "study door"}], "items": [
+				{"name": "desk", "description": "a desk", "location": "  by the   window. ", "contains": {"name": "letter", "description": "a letter", "detail": "dear sir", "portable": true}},
+				{"name": "rug", "description": "a rug", "conceals": {"name": "hatch key", "description": "a key", "key": true, "location": %q}},
+				{"name": "bookcase", "description": "a bookcase", "moveable": {"reveals": {"name": "ledger", "description": "a ledger", "detail": "debts", "portable": true}}},
+				{"name": "lamp", "description": "a lamp", "detail": "it flickers", "portable": true, "location": %q}
+			]},
+			{"name": "bedroom", "description": "a bedroom", "connections": [{"door_name": "study door"}], "items": [
+				{"name": "dresser", "description": "a dresser"}
+			]}
+		],
+		"doors": [{"name": "study door", "room_a": "study", "room_b": "bedroom", "locked": true, "required_key_name": "hatch key"}]
+	}`
+
+	level, warnings, err := LoadGameWithDiagnostics(json.RawMessage(fmt.Sprintf(levelJSON, "", "on the desk")), 0)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	items := level.Floors[0].Rooms[0].Items
+	expected := map[string]string{
+		"desk":      "by the window",
+		"letter":    "in the desk",
+		"hatch key": "under the rug",
+		"ledger":    "behind the bookcase",
+		"lamp":      "on the desk",
+	}
+	for name, location := range expected {
+		var item *world.Item
+		for _, roomItem := range items {
+			walkItem(roomItem, func(candidate *world.Item) {
+				if candidate.Name == name {
+					item = candidate
+				}
+			})
+		}
+		if item == nil || item.Location != location {
+			t.Errorf("Expected %s to be %q, got %+v", name, location, item)
+		}
+	}
+	for _, warning := range warnings {
+		if strings.Contains(warning.Message, "location") {
+			t.Errorf("Expected no location warnings, got %s", warning.Message)
+		}
+	}
+
+	_, warnings, err = LoadGameWithDiagnostics(json.RawMessage(fmt.Sprintf(levelJSON, "on the bed", "on the dresser")), 0)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	found := map[string]bool{"/rooms/0/items/1/conceals": false, "/rooms/0/items/3": false}
+	for _, warning := range warnings {
+		if _, ok := found[warning.Path]; ok && strings.Contains(warning.Message, "location") {
+			found[warning.Path] = true
+		}
+	}
+	for path, ok := range found {
+		if !ok {
+			t.Errorf("Expected a location warning at %s, got %+v", path, warnings)
+		}
+	}
+
+	errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, "", "Inventory"))).Errors()
+	if len(errs) == 0 || errs[0].Path != "/rooms/0/items/3/location" {
+		t.Errorf("Expected the reserved inventory location to be rejected, got %+v", errs)
+	}
+}
+
+func TestLoadGame_DuplicateNames(t *testing.T) {
+	const levelJSON = `{
+		"name": "duplicate names test",
+		"rooms": [
+			{"name": "hall", "description": "a hall", "connections": [{"door_name": "oak door"}], "items": [%s]},
+			{"name": %q, "description": "a study", "connections": [{"door_name": "oak door"}]}
+		],
+		"doors": [{"name": "oak door", "room_a": "hall", "room_b": "study"}%s],
+		"enemies": [{"name": "rat", "hp": 1, "room": "study"}%s]
+	}`
+	const box = `{"name": "box", "description": "a box", "contains": {"name": "key", "description": "a key", "key": true}}`
+	valid := fmt.Sprintf(levelJSON, box, "study", "", "")
+	if _, err := LoadGame(json.RawMessage(valid)); err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		level   string
+		path    string
+		message string
+	}{
+		{"room", fmt.Sprintf(levelJSON, box, "hall", "", ""), "/rooms/1/name", "duplicate room name"},
+		{"door", fmt.Sprintf(levelJSON, box, "study", `, {"name": "oak door", "room_a": "study", "room_b": "hall"}`, ""), "/doors/1/name", "duplicate door name"},
+		{"enemy", fmt.Sprintf(levelJSON, box, "study", "", `, {"name": "rat", "hp": 1, "room": "hall"}`), "/enemies/1/name", "duplicate enemy name"},
+		{"item id", fmt.Sprintf(levelJSON, box+`, {"name": "crate", "id": "c", "description": "a crate"}, {"name": "rug", "description": "a rug", "conceals": {"name": "crate", "id": "c", "description": "a crate"}}`, "study", "", ""), "/rooms/0/items/2/conceals/id", "duplicate item id"},
+		{"item id naming another item", fmt.Sprintf(levelJSON, box+`, {"name": "crate", "id": "key", "description": "a crate"}`, "study", "", ""), "/rooms/0/items/1/id", "name of another item"},
+		{"item id naming a shared name", fmt.Sprintf(levelJSON, box+`, {"name": "press", "description": "a press", "fixture": {"required_items": ["key"], "produces": {"name": "key", "id": "key", "description": "a key", "key": true}}}`, "study", "", ""), "/rooms/0/items/1/fixture/produces/id", "name of another item"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateLevel(json.RawMessage(test.level)).Errors()
+			if len(errs) == 0 || errs[0].Path != test.path || !strings.Contains(errs[0].Message, test.message) {
+				t.Errorf("Expected a %q error at %s, got %+v", test.message, test.path, errs)
+			}
+		})
+	}
+}
+
+func TestLoadGame_ItemIDs(t *testing.T) {
+	level, err := LoadGame(json.RawMessage(`{
+		"name": "item ids test",
+		"rooms": [{"name": "hold", "description": "a ship's hold", "items": [
+			{"name": "crate", "description": "a crate", "contains": {"name": "rope", "description": "a rope", "portable": true}},
+			{"name": "crate", "description": "a crate", "contains": {"name": "crate#1", "description": "a tiny crate", "portable": true}},
+			{"name": "crate", "id": "spare crate", "description": "a crate"},
+			{"name": "lamp", "description": "a lamp"}
+		]}]
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	items := level.Floors[0].Rooms[0].Items
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	expected := []string{"crate#2", "crate#3", "spare crate", "lamp"}
+	if !slices.Equal(ids, expected) || items[0].Container.Contains.ID != "rope" || items[1].Container.Contains.ID != "crate#1" {
+		t.Errorf("Expected item IDs %v, got %v", expected, ids)
+	}
+
+	exported := ExportLevel(level).Floors[0].Rooms[0].Items
+	if exported[0].ID != "crate#2" || exported[2].ID != "spare crate" || exported[3].ID != "" {
+		t.Errorf("Expected the export to keep the IDs of items sharing names, got %+v", exported)
+	}
+}
+
+func TestLoadGame_EnemyTriggerReferences(t *testing.T) {
+	diagnostics := ValidateLevel(json.RawMessage(`{
+		"name": "trigger references test",
+		"rooms": [{"name": "hall", "description": "a hall", "items": [
+			{"name": "idol", "description": "an idol", "detail": "it glows", "portable": true},
+			{"name": "altar", "description": "an altar", "fixture": {"required_items": ["idol"]}}
+		]}],
+		"enemies": [
+			{"name": "ghoul", "hp": 1, "trigger": {"event": "item_taken", "item_name": "statue"}},
+			{"name": "wraith", "hp": 1, "trigger": {"event": "fixture_used", "fixture_name": "shrine"}},
+			{"name": "bat", "hp": 1, "trigger": {"event": "room_entered", "room_name": "belfry"}},
+			{"name": "rat", "hp": 1, "trigger": {"event": "fixture_used", "fixture_name": "altar"}}
+		]
+	}`))
+
+	// Every problem is reported at once
+	var paths []string
+	for _, diagnostic := range diagnostics.Errors() {
+		paths = append(paths, diagnostic.Path)
+	}
+	expected := []string{"/enemies/0/trigger/item_name", "/enemies/1/trigger/fixture_name", "/enemies/2/trigger/room_name"}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("Expected errors at %v, got %+v", expected, diagnostics.Errors())
+	}
+}
+
+func TestLoadGame_EnemyResolution(t *testing.T) {
+	const levelJSON = `{
+		"name": "resolution test",
+		"rooms": [{"name": "bridge", "description": "a bridge", "items": [
+			{"name": "gold coin", "description": "a gold coin", "portable": true}
+		]}],
+		"enemies": [{"name": "troll", "hp": 3, "room": "bridge", %s}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, `"intimidate": 0.3, "sneak": 0.6, "bribes": ["gold coin"]`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	troll := level.GetEnemy("troll")
+	if troll.Intimidate != 0.3 || troll.Sneak != 0.6 || !slices.Equal(troll.Bribes, []string{"gold coin"}) {
+		t.Errorf("Expected the troll's weaknesses, got %+v", troll)
+	}
+	if exported := ExportLevel(level).Enemies[0]; exported.Intimidate != 0.3 || exported.Sneak != 0.6 || len(exported.Bribes) != 1 {
+		t.Errorf("Expected the export to keep the troll's weaknesses, got %+v", exported)
+	}
+
+	diagnostics := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, `"intimidate": 2, "sneak": -1, "bribes": ["gold coin", "silver coin"]`)))
+	var paths []string
+	for _, diagnostic := range diagnostics.Errors() {
+		paths = append(paths, diagnostic.Path)
+	}
+	expected := []string{"/enemies/0/intimidate", "/enemies/0/sneak", "/enemies/0/bribes/1"}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("Expected errors at %v, got %+v", expected, diagnostics.Errors())
+	}
+}
+
+func TestLoadGame_EnemySurrender(t *testing.T) {
+	const levelJSON = `{
+		"name": "surrender test",
+		"rooms": [{"name": "camp", "description": "a camp", "items": [
+			{"name": "lantern", "description": "a lantern", "portable": true}
+		]}],
+		"enemies": [{"name": "bandit", "hp": 3, "room": "camp", %s}]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, `"morale": 1, "carries": {"name": "silver ring", "portable": true}, "interrogation": "The boss sleeps upstairs."`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	bandit := level.GetEnemy("bandit")
+	if bandit.Morale != 1 || bandit.Carries == nil || bandit.Carries.Location != "at the feet of the bandit" || bandit.Interrogation != "The boss sleeps upstairs." {
+		t.Errorf("Expected the bandit's surrender, got %+v", bandit)
+	}
+	if exported := ExportLevel(level).Enemies[0]; exported.Morale != 1 || exported.Carries == nil || exported.Interrogation == "" {
+		t.Errorf("Expected the export to keep the bandit's surrender, got %+v", exported)
+	}
+
+	diagnostics := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, `"morale": 3, "carries": {"name": "lantern", "id": "lantern", "description": "a second lantern"}`)))
+	var paths []string
+	for _, diagnostic := range diagnostics.Errors() {
+		paths = append(paths, diagnostic.Path)
+	}
+	expected := []string{"/enemies/0/carries/id", "/enemies/0/morale", "/enemies/0/carries"}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("Expected errors at %v, got %+v", expected, diagnostics.Errors())
+	}
+
+	// Loot and answers of an enemy that fights to the death are never found
+	diagnostics = ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, `"carries": {"name": "silver ring", "portable": true}, "interrogation": "Nothing."`)))
+	paths = nil
+	for _, diagnostic := range diagnostics.Warnings() {
+		paths = append(paths, diagnostic.Path)
+	}
+	if !slices.Contains(paths, "/enemies/0/interrogation") || !slices.Contains(paths, "/enemies/0/carries") {
+		t.Errorf("Expected warnings about the bandit's loot and answers, got %+v", diagnostics.Warnings())
+	}
+}
+
+func TestLoadGame_SoundCues(t *testing.T) {
+	soundsLevel := func(hoodieCues string) json.RawMessage {
+		return json.RawMessage(`{
+			"name": "sounds test",
+			"rooms": [
+				{"name": "hall", "description": "a hall", "ambient": "clock_ticking", "sound_cues": {"enter": "door_creak"},
+					"connections": [{"door_name": "oak door"}],
+					"items": [
+						{"name": "grey hoodie", "description": "a hoodie", "portable": true, "sound_cues": ` + hoodieCues + `}
+					]},
+				{"name": "porch", "description": "a porch", "connections": [{"door_name": "oak door"}]}
+			],
+			"doors": [{"name": "oak door", "room_a": "hall", "room_b": "porch"}]
+		}`)
+	}
+
+	level, err := LoadGame(soundsLevel(`{"take": "cloth_rustle"}`))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	hall := getAllRooms(level)[0]
+	if hall.Ambient != "clock_ticking" || hall.SoundCues[world.SoundEnter] != "door_creak" {
+		t.Errorf("Expected the hall's sounds, got %q and %v", hall.Ambient, hall.SoundCues)
+	}
+	hoodie, err := hall.GetItem("grey hoodie")
+	if err != nil {
+		t.Fatalf("Expected grey hoodie: %v", err)
+	}
+	if hoodie.SoundCues[world.SoundTake] != "cloth_rustle" {
+		t.Errorf("Expected the hoodie's take cue, got %v", hoodie.SoundCues)
+	}
+
+	diagnostics := ValidateLevel(soundsLevel(`{"enter": "cloth_rustle"}`))
+	if errors := diagnostics.Errors(); len(errors) == 0 || errors[0].Path != "/rooms/0/items/0/sound_cues/enter" {
+		t.Errorf("Expected an error for a room event on an item, got %+v", diagnostics)
+	}
+	diagnostics = ValidateLevel(soundsLevel(`{"take": ""}`))
+	if errors := diagnostics.Errors(); len(errors) == 0 || errors[0].Path != "/rooms/0/items/0/sound_cues/take" {
+		t.Errorf("Expected an error for an empty sound, got %+v", diagnostics)
+	}
+}
+
+func TestLoadGame_ImageRefs(t *testing.T) {
+	imagesLevel := func(hoodieImage string) json.RawMessage {
+		return json.RawMessage(`{
+			"name": "images test",
+			"rooms": [
+				{"name": "hall", "description": "a hall", "image_ref": "rooms/hall.png", "connections": [{"door_name": "oak door"}],
+					"items": [
+						{"name": "grey hoodie", "description": "a hoodie", "portable": true, "image_ref": "` + hoodieImage + `"}
+					]},
+				{"name": "porch", "description": "a porch", "connections": [{"door_name": "oak door"}]}
+			],
+			"doors": [{"name": "oak door", "room_a": "hall", "room_b": "porch"}],
+			"enemies": [{"name": "zombie", "description": "a zombie", "hp": 1, "room": "porch", "image_ref": "https://example.com/zombie.png"}]
+		}`)
+	}
+
+	level, err := LoadGame(imagesLevel("items/hoodie.png"))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	hall := getAllRooms(level)[0]
+	if hall.ImageRef != "rooms/hall.png" {
+		t.Errorf("Expected the hall's image, got %q", hall.ImageRef)
+	}
+	if hoodie, _ := hall.GetItem("grey hoodie"); hoodie == nil || hoodie.ImageRef != "items/hoodie.png" {
+		t.Errorf("Expected the hoodie's image, got %+v", hoodie)
+	}
+	if zombie := level.GetEnemy("zombie"); zombie.ImageRef != "https://example.com/zombie.png" {
+		t.Errorf("Expected the zombie's image, got %q", zombie.ImageRef)
+	}
+
+	for _, image := range []string{"javascript:alert(1)", "file:///etc/passwd", "https://", " items/hoodie.png"} {
+		diagnostics := ValidateLevel(imagesLevel(image))
+		if errors := diagnostics.Errors(); len(errors) == 0 || errors[0].Path != "/rooms/0/items/0/image_ref" {
+			t.Errorf("Expected an error for image %q, got %+v", image, diagnostics)
+		}
+	}
+}
+
+func TestLoadGame_CustomEffectsAndComponents(t *testing.T) {
+	const levelJSON = `{
+		"name": "custom test",
+		"rooms": [
+			{"name": "shrine", "description": "a shrine", "items": [
+				{"name": "idol", "description": "an idol", "portable": true, "custom_components": {"cursed": {"strength": 3}}},
+				{"name": "lever", "description": "a lever", "portable": true},
+				{"name": "sluice", "description": "a sluice gate", "fixture": {
+					"required_items": ["lever"],
+					"on_complete": [%s]
+				}}
+			]}
+		]
+	}`
+	level, err := LoadGame(json.RawMessage(fmt.Sprintf(levelJSON, `{"effect": "custom:flood", "params": {"depth": 2}}`)))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	items := level.Floors[0].Rooms[0].Items
+	if string(items[0].CustomComponents["cursed"]) != `{"strength":3}` {
+		t.Errorf("Expected the idol's cursed component, got %v", items[0].CustomComponents)
+	}
+	if effect := items[2].Fixture.OnComplete[0]; !effect.IsCustom() || string(effect.Params) != `{"depth":2}` {
+		t.Errorf("Expected the custom flood effect with its params, got %+v", effect)
+	}
+	exported := ExportLevel(level)
+	exportedItems := exported.Floors[0].Rooms[0].Items
+	if exportedItems[0].CustomComponents["cursed"] == nil || exportedItems[2].Fixture.OnComplete[0].Params == nil {
+		t.Errorf("Expected the export to keep the custom component and params, got %+v", exportedItems)
+	}
+
+	errs := ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, `{"effect": "complete_level", "params": {"depth": 2}}`))).Errors()
+	if len(errs) == 0 || errs[0].Path != "/rooms/0/items/2/fixture/on_complete/0/params" {
+		t.Errorf("Expected an error at the built-in effect's params, got %+v", errs)
+	}
+	errs = ValidateLevel(json.RawMessage(fmt.Sprintf(levelJSON, `{"effect": "custom:"}`))).Errors()
+	if len(errs) == 0 || errs[0].Path != "/rooms/0/items/2/fixture/on_complete/0/effect" {
+		t.Errorf("Expected an error for a custom effect without a name, got %+v", errs)
+	}
+}
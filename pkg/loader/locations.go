@@ -0,0 +1,111 @@
+package loader
+
+import (
+	"strings"
+	"unicode"
+
+	"adventure-engine/pkg/world"
+)
+
+// inventoryLocation is the location the engine gives items the player carries.
+const inventoryLocation = "inventory"
+
+// normalizeLocation tidies an item's location as the author wrote it, so that locations read
+// the same wherever they are shown: surrounding space and a trailing full stop or comma are
+// dropped, and runs of spaces are collapsed.
+func normalizeLocation(location string) string {
+	location = strings.Join(strings.Fields(location), " ")
+	return strings.TrimRight(location, ".,;")
+}
+
+// validateLocation checks that an item's location is not the one reserved for carried items.
+func validateLocation(location string, path string) error {
+	if strings.EqualFold(location, inventoryLocation) {
+		return newValidationError(path+jsonPointer("location"), "location %s is reserved for items the player carries", location)
+	}
+	return nil
+}
+
+// nestedLocation returns the location of an item held by another, such as "in the desk" for
+// the item in a desk, unless the author gave one.
+func nestedLocation(location string, preposition string, parentName string) string {
+	if location != "" {
+		return location
+	}
+	return preposition + " the " + parentName
+}
+
+// collectLocationWarnings finds item locations that contradict where the item is: items in
+// containers, under concealers or behind furniture whose location doesn't name the item holding
+// them, and items whose location names furniture that is in another room.
+func collectLocationWarnings(level *world.Level, paths *levelPaths) Diagnostics {
+	var diagnostics Diagnostics
+
+	// Furniture is what locations are given by, portable items move around
+	landmarks := make(map[string]map[string]bool) // item name -> rooms it is in
+	for _, floor := range level.Floors {
+		for _, room := range floor.Rooms {
+			for _, item := range room.Items {
+				if item.IsPortable() {
+					continue
+				}
+				if landmarks[item.Name] == nil {
+					landmarks[item.Name] = make(map[string]bool)
+				}
+				landmarks[item.Name][room.Name] = true
+			}
+		}
+	}
+
+	for _, floor := range level.Floors {
+		for _, room := range floor.Rooms {
+			for _, item := range room.Items {
+				for _, name := range sortedKeys(landmarks) {
+					if landmarks[name][room.Name] || !mentions(item.Location, name) {
+						continue
+					}
+					diagnostics.addWarning(paths.items[item.Name], "location %q of item %s in room %s names the %s, which is in another room", item.Location, item.Name, room.Name, name)
+				}
+				walkItem(item, func(parent *world.Item) {
+					for _, held := range heldItems(parent) {
+						if !mentions(held.item.Location, parent.Name) {
+							diagnostics.addWarning(paths.items[held.item.Name], "item %s is %s the %s, but its location %q does not say so", held.item.Name, held.preposition, parent.Name, held.item.Location)
+						}
+					}
+				})
+			}
+		}
+	}
+	return diagnostics
+}
+
+// heldItem is an item held by another, and how.
+type heldItem struct {
+	item        *world.Item
+	preposition string
+}
+
+// heldItems returns the items an item holds: its contents, what it conceals and what is behind it.
+func heldItems(item *world.Item) []heldItem {
+	var held []heldItem
+	if item.IsContainer() && item.Container.Contains != nil {
+		held = append(held, heldItem{item.Container.Contains, "in"})
+	}
+	if item.IsConcealer() && item.Concealer.Hidden != nil {
+		held = append(held, heldItem{item.Concealer.Hidden, "under"})
+	}
+	if item.IsMoveable() && item.Moveable.Reveals != nil {
+		held = append(held, heldItem{item.Moveable.Reveals, "behind"})
+	}
+	return held
+}
+
+// mentions reports whether text names something, as whole words and ignoring case.
+func mentions(text string, name string) bool {
+	words := func(s string) string {
+		return " " + strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}), " ") + " "
+	}
+	return name != "" && strings.Contains(words(text), words(name))
+}
@@ -0,0 +1,262 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+)
+
+// LootTableData represents a named loot table in the JSON
+type LootTableData struct {
+	Entries []LootEntryData `json:"entries" schema:"required,nonempty"`
+}
+
+// LootEntryData represents a weighted loot table entry in the JSON
+// An entry without an item rolls nothing. Weights default to 1.
+type LootEntryData struct {
+	Item   *ItemData `json:"item,omitempty"`
+	Weight int       `json:"weight,omitempty"`
+}
+
+// LootData represents rolls on a loot table in the JSON
+// Used as container contents, or in a room's loot list to place items in the room.
+// Rolls default to 1.
+type LootData struct {
+	LootTable string `json:"loot_table" schema:"required"`
+	Rolls     int    `json:"rolls,omitempty"`
+}
+
+type lootEntry struct {
+	item   map[string]any // nil for an entry that rolls nothing
+	weight int
+	rolled bool
+}
+
+// lootRoller resolves loot table rolls.
+// Each item entry is placed at most once per level so that item names stay unique;
+// once a table's items are used up, further rolls on it produce nothing.
+type lootRoller struct {
+	tables map[string][]*lootEntry
+	rng    *rand.Rand
+}
+
+// resolveLoot replaces loot table rolls in container contents and room loot lists
+// with items rolled using the seed.
+func resolveLoot(data json.RawMessage, seed uint64) (json.RawMessage, error) {
+	var doc map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, &ValidationError{Err: fmt.Errorf("invalid JSON format: %w", err)}
+	}
+
+	tables, err := parseLootTables(doc["loot_tables"])
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 && !containsLoot(doc) {
+		return data, nil
+	}
+
+	roller := &lootRoller{
+		tables: tables,
+		rng:    rand.New(rand.NewPCG(seed, seed)),
+	}
+	for _, key := range sortedKeys(doc) {
+		if key == "loot_tables" {
+			continue
+		}
+		if err := roller.resolve(doc[key], jsonPointer(key)); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(doc)
+}
+
+// parseLootTables validates the loot tables, including every item they can roll.
+func parseLootTables(raw any) (map[string][]*lootEntry, error) {
+	tables := make(map[string][]*lootEntry)
+	if raw == nil {
+		return tables, nil
+	}
+	rawTables, ok := raw.(map[string]any)
+	if !ok {
+		return nil, newValidationError(jsonPointer("loot_tables"), "field 'loot_tables' must be an object")
+	}
+
+	for _, name := range sortedKeys(rawTables) {
+		path := jsonPointer("loot_tables", name)
+		tableJSON, err := json.Marshal(rawTables[name])
+		if err != nil {
+			return nil, err
+		}
+		var tableData LootTableData
+		if err := json.Unmarshal(tableJSON, &tableData); err != nil {
+			return nil, newValidationError(path, "invalid loot table %s: %w", name, err)
+		}
+		if len(tableData.Entries) == 0 {
+			return nil, newValidationError(path+jsonPointer("entries"), "loot table %s must have at least one entry", name)
+		}
+
+		rawEntries := rawTables[name].(map[string]any)["entries"].([]any)
+		for i, entryData := range tableData.Entries {
+			entryPath := path + jsonPointer("entries", i)
+			if entryData.Weight < 0 {
+				return nil, newValidationError(entryPath+jsonPointer("weight"), "loot entry weight must not be negative")
+			}
+			entry := &lootEntry{weight: entryData.Weight}
+			if entry.weight == 0 {
+				entry.weight = 1
+			}
+			if entryData.Item != nil {
+				if _, err := createItem(*entryData.Item, entryPath+jsonPointer("item")); err != nil {
+					return nil, fmt.Errorf("failed to create loot item %s: %w", entryData.Item.Name, err)
+				}
+				entry.item = rawEntries[i].(map[string]any)["item"].(map[string]any)
+			}
+			tables[name] = append(tables[name], entry)
+		}
+	}
+	return tables, nil
+}
+
+// resolve walks a JSON value, rolling loot in container contents and room loot lists.
+func (r *lootRoller) resolve(value any, path string) error {
+	switch v := value.(type) {
+	case map[string]any:
+		if contents, ok := v["contains"].(map[string]any); ok {
+			if _, isLoot := contents["loot_table"]; isLoot {
+				item, err := r.rollContents(contents, path+jsonPointer("contains"))
+				if err != nil {
+					return err
+				}
+				if item == nil {
+					v["contains"] = "empty"
+				} else {
+					v["contains"] = item
+				}
+			}
+		}
+		if loot, ok := v["loot"].([]any); ok {
+			items, _ := v["items"].([]any)
+			for i, lootValue := range loot {
+				rolled, err := r.rollLoot(lootValue, path+jsonPointer("loot", i))
+				if err != nil {
+					return err
+				}
+				for _, item := range rolled {
+					items = append(items, item)
+				}
+			}
+			v["items"] = items
+			delete(v, "loot")
+		}
+		for _, key := range sortedKeys(v) {
+			if err := r.resolve(v[key], path+jsonPointer(key)); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, element := range v {
+			if err := r.resolve(element, path+jsonPointer(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rollContents rolls the single item held by a container.
+func (r *lootRoller) rollContents(contents map[string]any, path string) (map[string]any, error) {
+	rolled, err := r.rollLoot(contents, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(rolled) > 1 {
+		return nil, newValidationError(path+jsonPointer("rolls"), "containers hold a single item, rolls must be 1")
+	}
+	if len(rolled) == 0 {
+		return nil, nil
+	}
+	return rolled[0], nil
+}
+
+// rollLoot rolls on a loot table, returning the items rolled.
+func (r *lootRoller) rollLoot(value any, path string) ([]map[string]any, error) {
+	lootJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var lootData LootData
+	if err := json.Unmarshal(lootJSON, &lootData); err != nil {
+		return nil, newValidationError(path, "invalid loot: %w", err)
+	}
+	entries, ok := r.tables[lootData.LootTable]
+	if !ok {
+		return nil, newValidationError(path+jsonPointer("loot_table"), "unknown loot table %s", lootData.LootTable)
+	}
+	rolls := lootData.Rolls
+	if rolls < 0 {
+		return nil, newValidationError(path+jsonPointer("rolls"), "loot rolls must not be negative")
+	}
+	if rolls == 0 {
+		rolls = 1
+	}
+
+	var items []map[string]any
+	for range rolls {
+		if entry := r.roll(entries); entry != nil && entry.item != nil {
+			entry.rolled = true
+			items = append(items, deepCopyJSON(entry.item).(map[string]any))
+		}
+	}
+	return items, nil
+}
+
+// roll picks a weighted entry among those still available, or nil if none are left.
+func (r *lootRoller) roll(entries []*lootEntry) *lootEntry {
+	total := 0
+	for _, entry := range entries {
+		if !entry.rolled {
+			total += entry.weight
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+	n := r.rng.IntN(total)
+	for _, entry := range entries {
+		if entry.rolled {
+			continue
+		}
+		if n < entry.weight {
+			return entry
+		}
+		n -= entry.weight
+	}
+	return nil
+}
+
+// containsLoot returns true if any object in the document rolls on a loot table.
+func containsLoot(value any) bool {
+	switch v := value.(type) {
+	case map[string]any:
+		if _, ok := v["loot_table"]; ok {
+			return true
+		}
+		for _, field := range v {
+			if containsLoot(field) {
+				return true
+			}
+		}
+	case []any:
+		for _, element := range v {
+			if containsLoot(element) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package loader
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"adventure-engine/pkg/world"
+)
+
+const lootLevel = `{
+	"name": "loot test",
+	"loot_tables": {
+		"medical": {
+			"entries": [
+				{"item": {"name": "bandage", "description": "a bandage", "health_effect": "weak"}, "weight": 3},
+				{"item": {"name": "first aid kit", "description": "a first aid kit", "health_effect": "strong"}},
+				{"weight": 2}
+			]
+		},
+		"junk": {
+			"entries": [
+				{"item": {"name": "bottle cap", "description": "a bottle cap", "portable": true}},
+				{"item": {"name": "rusty nail", "description": "a rusty nail", "portable": true}}
+			]
+		}
+	},
+	"rooms": [
+		{
+			"name": "clinic",
+			"description": "a clinic",
+			"items": [
+				{"name": "cabinet", "description": "a medicine cabinet", "contains": {"loot_table": "medical"}}
+			],
+			"loot": [{"loot_table": "junk", "rolls": 3}]
+		}
+	]
+}`
+
+func TestLoadGame_LootTables(t *testing.T) {
+	level, err := LoadGameWithSeed(json.RawMessage(lootLevel), 42)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	room := getAllRooms(level)[0]
+
+	// Both junk items are placed once each, the third roll finds the table used up
+	if len(room.Items) != 3 {
+		t.Fatalf("Expected cabinet and 2 junk items, got %d items", len(room.Items))
+	}
+	for _, name := range []string{"bottle cap", "rusty nail"} {
+		if _, err := room.GetItem(name); err != nil {
+			t.Errorf("Expected %s to be placed in the room", name)
+		}
+	}
+
+	cabinet, _ := room.GetItem("cabinet")
+	if cabinet == nil || !cabinet.IsContainer() {
+		t.Fatal("Expected cabinet container")
+	}
+	if contents := cabinet.Container.Contains; contents != nil && contents.Name != "bandage" && contents.Name != "first aid kit" {
+		t.Errorf("Expected cabinet to hold medical loot, got %s", contents.Name)
+	}
+
+	// The same seed always rolls the same loot
+	contentsName := func(level *world.Level) string {
+		cabinet, _ := getAllRooms(level)[0].GetItem("cabinet")
+		if cabinet.Container.Contains == nil {
+			return ""
+		}
+		return cabinet.Container.Contains.Name
+	}
+	for range 5 {
+		again, err := LoadGameWithSeed(json.RawMessage(lootLevel), 42)
+		if err != nil {
+			t.Fatalf("Failed to load game: %v", err)
+		}
+		if contentsName(again) != contentsName(level) {
+			t.Errorf("Expected seed 42 to roll %q, got %q", contentsName(level), contentsName(again))
+		}
+	}
+
+	// Different seeds vary the loot
+	rolled := make(map[string]bool)
+	for seed := range uint64(50) {
+		level, err := LoadGameWithSeed(json.RawMessage(lootLevel), seed)
+		if err != nil {
+			t.Fatalf("Failed to load game: %v", err)
+		}
+		rolled[contentsName(level)] = true
+	}
+	if len(rolled) != 3 {
+		t.Errorf("Expected bandage, first aid kit and nothing to all be rolled, got %v", rolled)
+	}
+}
+
+func TestLoadGame_LootTableErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		path    string
+		message string
+	}{
+		{
+			name: "unknown loot table",
+			level: `{"name": "test", "rooms": [{"name": "room", "items": [
+				{"name": "crate", "contains": {"loot_table": "missing"}}
+			]}]}`,
+			path:    "/rooms/0/items/0/contains/loot_table",
+			message: "unknown loot table missing",
+		},
+		{
+			name: "container with multiple rolls",
+			level: `{"name": "test",
+				"loot_tables": {"junk": {"entries": [{"item": {"name": "a", "portable": true}}, {"item": {"name": "b", "portable": true}}]}},
+				"rooms": [{"name": "room", "items": [{"name": "crate", "contains": {"loot_table": "junk", "rolls": 2}}]}]}`,
+			path:    "/rooms/0/items/0/contains/rolls",
+			message: "containers hold a single item",
+		},
+		{
+			name: "invalid loot item",
+			level: `{"name": "test",
+				"loot_tables": {"junk": {"entries": [{"item": {"name": "bad key", "key": true, "contains": "empty"}}]}},
+				"rooms": [{"name": "room"}]}`,
+			path:    "/loot_tables/junk/entries/0/item",
+			message: "invalid key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := ValidateLevel(json.RawMessage(tt.level))
+			errors := diagnostics.Errors()
+			if len(errors) != 1 {
+				t.Fatalf("Expected 1 error, got %+v", diagnostics)
+			}
+			if errors[0].Path != tt.path {
+				t.Errorf("Expected error at %q, got %q", tt.path, errors[0].Path)
+			}
+			if !strings.Contains(errors[0].Message, tt.message) {
+				t.Errorf("Expected error containing %q, got: %s", tt.message, errors[0].Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CurrentSchemaVersion is the level file schema version produced by migrations.
+// Level files without a schema_version are treated as version 1.
+const CurrentSchemaVersion = 3
+
+// migration upgrades a level document from one schema version to the next.
+// It returns a function that maps JSON pointers in the migrated document back to
+// the original document, or nil if paths are unchanged.
+type migration func(doc map[string]json.RawMessage) (rewritePath func(string) string, err error)
+
+// migrations[i] upgrades a document from version i+1 to version i+2.
+var migrations = []migration{
+	migrateV1ToV2,
+	migrateV2ToV3,
+}
+
+// migrateV1ToV2 moves the legacy top-level rooms list onto a single floor.
+// Version 1 allowed either floors or rooms; version 2 requires floors.
+func migrateV1ToV2(doc map[string]json.RawMessage) (func(string) string, error) {
+	rooms, hasRooms := doc["rooms"]
+	if !hasRooms {
+		return nil, nil
+	}
+	delete(doc, "rooms")
+	if _, hasFloors := doc["floors"]; hasFloors {
+		// Rooms were ignored when floors were present
+		return nil, nil
+	}
+
+	floors, err := json.Marshal([]map[string]json.RawMessage{{
+		"name":        json.RawMessage(`"main floor"`),
+		"description": json.RawMessage(`"the main floor"`),
+		"rooms":       rooms,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	doc["floors"] = floors
+
+	return func(path string) string {
+		if rest, ok := strings.CutPrefix(path, "/floors/0/rooms"); ok {
+			return "/rooms" + rest
+		}
+		return path
+	}, nil
+}
+
+// migrateV2ToV3 renames the weapon_name of ammo boxes to ammo_type, as boxes now hold a type of
+// ammo that any weapon firing it can use. Weapons that don't name their ammo type fire ammo
+// named after them, so the boxes still load the weapons they were for.
+func migrateV2ToV3(doc map[string]json.RawMessage) (func(string) string, error) {
+	renamed := false
+	for _, key := range sortedKeys(doc) {
+		var value any
+		decoder := json.NewDecoder(bytes.NewReader(doc[key]))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		if !renameField(value, "weapon_name", "ammo_type") {
+			continue
+		}
+		migrated, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		doc[key] = migrated
+		renamed = true
+	}
+	if !renamed {
+		return nil, nil
+	}
+
+	return func(path string) string {
+		if rest, ok := strings.CutSuffix(path, "/ammo_type"); ok {
+			return rest + "/weapon_name"
+		}
+		return path
+	}, nil
+}
+
+// renameField renames a field of every object in a decoded JSON value, except in the custom
+// components that belong to plugins. Reports whether any field was renamed.
+func renameField(value any, from string, to string) bool {
+	renamed := false
+	switch v := value.(type) {
+	case map[string]any:
+		if field, ok := v[from]; ok {
+			delete(v, from)
+			v[to] = field
+			renamed = true
+		}
+		for key, field := range v {
+			if key != "custom_components" && renameField(field, from, to) {
+				renamed = true
+			}
+		}
+	case []any:
+		for _, element := range v {
+			if renameField(element, from, to) {
+				renamed = true
+			}
+		}
+	}
+	return renamed
+}
+
+// migrate upgrades a level document to the current schema version.
+// Returns the migrated document and a function mapping JSON pointers in it back to the original.
+func migrate(data json.RawMessage) (json.RawMessage, func(string) string, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, &ValidationError{Err: fmt.Errorf("invalid JSON format: %w", err)}
+	}
+
+	version := 1
+	if raw, ok := doc["schema_version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil || version < 1 {
+			return nil, nil, newValidationError(jsonPointer("schema_version"), "field 'schema_version' must be a positive integer")
+		}
+	}
+	if version > CurrentSchemaVersion {
+		return nil, nil, newValidationError(jsonPointer("schema_version"),
+			"unsupported schema version %d (latest supported version is %d)", version, CurrentSchemaVersion)
+	}
+	if version >= 2 {
+		if _, hasRooms := doc["rooms"]; hasRooms {
+			return nil, nil, newValidationError(jsonPointer("rooms"),
+				"field 'rooms' is not supported in schema version %d, use 'floors' instead", version)
+		}
+	}
+
+	var rewrites []func(string) string
+	for ; version < CurrentSchemaVersion; version++ {
+		rewritePath, err := migrations[version-1](doc)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to migrate from schema version %d: %w", version, err)
+		}
+		if rewritePath != nil {
+			rewrites = append(rewrites, rewritePath)
+		}
+	}
+	doc["schema_version"] = json.RawMessage(fmt.Sprint(CurrentSchemaVersion))
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Map paths back through the migrations in reverse order
+	rewritePath := func(path string) string {
+		for i := len(rewrites) - 1; i >= 0; i-- {
+			path = rewrites[i](path)
+		}
+		return path
+	}
+	return migrated, rewritePath, nil
+}
@@ -0,0 +1,129 @@
+package loader
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMigrate_LegacyRooms(t *testing.T) {
+	jsonData := json.RawMessage(`{
+		"name": "legacy level",
+		"rooms": [{"name": "room1", "description": "first room"}]
+	}`)
+
+	migrated, rewritePath, err := migrate(jsonData)
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	var gameData GameData
+	if err := json.Unmarshal(migrated, &gameData); err != nil {
+		t.Fatalf("Failed to parse migrated level: %v", err)
+	}
+	if gameData.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", CurrentSchemaVersion, gameData.SchemaVersion)
+	}
+	if len(gameData.Floors) != 1 || gameData.Floors[0].Name != "main floor" {
+		t.Fatalf("Expected rooms to be moved onto the main floor, got %+v", gameData.Floors)
+	}
+	if len(gameData.Floors[0].Rooms) != 1 || gameData.Floors[0].Rooms[0].Name != "room1" {
+		t.Errorf("Expected room1 on the main floor, got %+v", gameData.Floors[0].Rooms)
+	}
+	if path := rewritePath("/floors/0/rooms/0/items/1"); path != "/rooms/0/items/1" {
+		t.Errorf("Expected path to be mapped back to the legacy document, got %q", path)
+	}
+	if path := rewritePath("/doors/0"); path != "/doors/0" {
+		t.Errorf("Expected unrelated path to be unchanged, got %q", path)
+	}
+}
+
+func TestMigrate_AmmoBoxWeaponName(t *testing.T) {
+	jsonData := json.RawMessage(`{
+		"name": "ammo level",
+		"schema_version": 2,
+		"floors": [{"name": "floor1", "rooms": [{"name": "armory", "description": "an armory", "items": [
+			{"name": "pistol", "description": "a pistol", "weapon_damage": 0.7, "ammo": 1},
+			{"name": "crate", "description": "a crate", "contains": {"name": "rounds", "description": "pistol rounds", "weapon_name": "pistol", "ammo": 4}},
+			{"name": "ledger", "description": "a ledger", "custom_components": {"note": {"weapon_name": "pistol"}}}
+		]}]}]
+	}`)
+
+	migrated, rewritePath, err := migrate(jsonData)
+	if err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if strings.Count(string(migrated), `"weapon_name"`) != 1 {
+		t.Errorf("Expected only the custom component to keep its weapon_name, got %s", migrated)
+	}
+	if path := rewritePath("/floors/0/rooms/0/items/1/contains/ammo_type"); path != "/floors/0/rooms/0/items/1/contains/weapon_name" {
+		t.Errorf("Expected ammo_type to be mapped back to weapon_name, got %q", path)
+	}
+
+	level, err := LoadGame(jsonData)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	items := level.Floors[0].Rooms[0].Items
+	rounds := findItemByName(items, "crate").Container.Contains
+	if rounds.AmmoType() != "pistol" || findItemByName(items, "pistol").AmmoType() != "pistol" {
+		t.Errorf("Expected the rounds to load the pistol, got ammo types %s and %s", rounds.AmmoType(), findItemByName(items, "pistol").AmmoType())
+	}
+}
+
+func TestMigrate_CurrentVersion(t *testing.T) {
+	jsonData := json.RawMessage(`{
+		"name": "current level",
+		"schema_version": 2,
+		"floors": [{"name": "floor1", "rooms": [{"name": "room1", "description": "first room"}]}]
+	}`)
+
+	level, err := LoadGame(jsonData)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if level.Floors[0].Name != "floor1" {
+		t.Errorf("Expected floor1, got %s", level.Floors[0].Name)
+	}
+}
+
+func TestMigrate_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		message string
+	}{
+		{
+			name:    "future version",
+			level:   `{"name": "test", "schema_version": 99, "floors": [{"name": "floor1", "rooms": [{"name": "room1"}]}]}`,
+			message: "unsupported schema version 99 (latest supported version is 3)",
+		},
+		{
+			name:    "invalid version",
+			level:   `{"name": "test", "schema_version": "two", "floors": [{"name": "floor1", "rooms": [{"name": "room1"}]}]}`,
+			message: "field 'schema_version' must be a positive integer",
+		},
+		{
+			name:    "rooms in current version",
+			level:   `{"name": "test", "schema_version": 2, "rooms": [{"name": "room1"}]}`,
+			message: "field 'rooms' is not supported in schema version 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadGame(json.RawMessage(tt.level))
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Expected error containing %q, got: %v", tt.message, err)
+			}
+		})
+	}
+
+	diagnostics := ValidateLevel(json.RawMessage(tests[0].level))
+	if errors := diagnostics.Errors(); len(errors) != 1 || errors[0].Path != "/schema_version" {
+		t.Errorf("Expected error at /schema_version, got %+v", diagnostics)
+	}
+}
@@ -0,0 +1,140 @@
+package loader
+
+import (
+	"fmt"
+
+	"adventure-engine/pkg/world"
+)
+
+// validateUniqueNames checks that no two rooms, doors or enemies share a name, since the
+// engine finds each of them by name. Items can share names, as the engine tells them apart by
+// their IDs, which validateItemIDs checks.
+// Every duplicate is reported at its own path, naming where the name was first used.
+func validateUniqueNames(gameData GameData) Diagnostics {
+	var diagnostics Diagnostics
+	seen := map[string]map[string]string{"room": {}, "door": {}, "enemy": {}} // kind -> name -> path
+	add := func(kind string, name string, path string) {
+		if first, ok := seen[kind][name]; ok {
+			diagnostics.addError(path+jsonPointer("name"), fmt.Errorf("duplicate %s name %s, first used at %s", kind, name, first))
+			return
+		}
+		seen[kind][name] = path
+	}
+
+	for i, floorData := range gameData.Floors {
+		for j, roomData := range floorData.Rooms {
+			roomPath := jsonPointer("floors", i, "rooms", j)
+			add("room", roomData.Name, roomPath)
+		}
+	}
+	for i, doorData := range gameData.DoorData {
+		add("door", doorData.Name, jsonPointer("doors", i))
+	}
+	for i, enemyData := range gameData.Enemies {
+		add("enemy", enemyData.Name, jsonPointer("enemies", i))
+	}
+	return append(diagnostics, validateItemIDs(gameData)...)
+}
+
+// validateItemIDs checks the IDs a level gives its items, wherever they are placed, what
+// fixtures produce, what items break into, what enemies carry and what recipes craft
+// included. No two items can have the same ID, and since the engine finds items by either
+// their name or their ID, an ID can't be the name of an item other than its own.
+func validateItemIDs(gameData GameData) Diagnostics {
+	var diagnostics Diagnostics
+	var items []pathedItemData
+	var addItem func(item pathedItemData)
+	addItem = func(item pathedItemData) {
+		items = append(items, item)
+		for _, nested := range nestedItemData(item.data, item.path) {
+			addItem(nested)
+		}
+	}
+	for i, floorData := range gameData.Floors {
+		for j, roomData := range floorData.Rooms {
+			for k, itemData := range roomData.Items {
+				addItem(pathedItemData{itemData, jsonPointer("floors", i, "rooms", j, "items", k)})
+			}
+		}
+	}
+	for i, enemyData := range gameData.Enemies {
+		if enemyData.Carries != nil {
+			addItem(pathedItemData{*enemyData.Carries, jsonPointer("enemies", i, "carries")})
+		}
+	}
+	for i, comboItemData := range gameData.ComboItems {
+		addItem(pathedItemData{comboItemData.OutputItem, jsonPointer("combo_items", i, "output_item")})
+		for j, byproductData := range comboItemData.Byproducts {
+			addItem(pathedItemData{byproductData, jsonPointer("combo_items", i, "byproducts", j)})
+		}
+	}
+
+	names := make(map[string]int) // name -> items with it
+	for _, item := range items {
+		names[item.data.Name]++
+	}
+	ids := make(map[string]string) // id -> path
+	for _, item := range items {
+		id := item.data.ID
+		if id == "" {
+			continue
+		}
+		path := item.path + jsonPointer("id")
+		if first, ok := ids[id]; ok {
+			diagnostics.addError(path, fmt.Errorf("duplicate item id %s, first used at %s", id, first))
+			continue
+		}
+		ids[id] = path
+		if names[id] > 1 || (names[id] == 1 && id != item.data.Name) {
+			diagnostics.addError(path, fmt.Errorf("item id %s is the name of another item", id))
+		}
+	}
+	return diagnostics
+}
+
+// assignItemIDs gives each item without an ID one: its name or, for items sharing their name
+// with others, the name numbered in the order the level lists them, as in "crate#2".
+func assignItemIDs(level *world.Level) {
+	var items []*world.Item
+	collect := func(item *world.Item) { items = append(items, item) }
+	for _, floor := range level.Floors {
+		for _, room := range floor.Rooms {
+			for _, item := range room.Items {
+				walkItem(item, collect)
+			}
+		}
+	}
+	for _, enemy := range level.Enemies {
+		walkItem(enemy.Carries, collect)
+	}
+	for _, comboItem := range level.ComboItems {
+		walkItem(comboItem.OutputItem, collect)
+		for _, byproduct := range comboItem.Byproducts {
+			walkItem(byproduct, collect)
+		}
+	}
+
+	names := make(map[string]int) // name -> items with it
+	taken := make(map[string]bool)
+	for _, item := range items {
+		names[item.Name]++
+		if item.ID != "" {
+			taken[item.ID] = true
+		}
+	}
+	numbers := make(map[string]int)
+	for _, item := range items {
+		switch {
+		case item.ID != "":
+			continue
+		case names[item.Name] == 1:
+			item.ID = item.Name
+			continue
+		}
+		for item.ID == "" || taken[item.ID] || names[item.ID] > 0 {
+			numbers[item.Name]++
+			item.ID = fmt.Sprintf("%s#%d", item.Name, numbers[item.Name])
+		}
+		taken[item.ID] = true
+	}
+}
@@ -0,0 +1,97 @@
+package loader
+
+import (
+	"adventure-engine/pkg/world"
+)
+
+// ObjectiveData represents an authored objective in the JSON
+// Objectives without a reveal event are shown from the start.
+type ObjectiveData struct {
+	Name        string              `json:"name" schema:"required,nonempty"`
+	Description string              `json:"description" schema:"required,nonempty,localized"`
+	RevealOn    *ObjectiveEventData `json:"reveal_on,omitempty"`
+	CompleteOn  ObjectiveEventData  `json:"complete_on" schema:"required"`
+}
+
+// ObjectiveEventData represents an event that reveals or completes an objective in the JSON
+type ObjectiveEventData struct {
+	Event       string `json:"event" schema:"required,enum=room_entered|item_taken|fixture_used|enemy_killed"`
+	RoomName    string `json:"room_name,omitempty"`
+	ItemName    string `json:"item_name,omitempty"`
+	FixtureName string `json:"fixture_name,omitempty"`
+	EnemyName   string `json:"enemy_name,omitempty"`
+}
+
+// createObjectives creates the level's objectives, checking that every event refers to
+// a room, item, fixture or enemy that exists in the level.
+func createObjectives(objectivesData []ObjectiveData, paths *levelPaths) ([]*world.Objective, error) {
+	var objectives []*world.Objective
+	names := make(map[string]bool)
+	for i, objectiveData := range objectivesData {
+		path := jsonPointer("objectives", i)
+		if objectiveData.Name == "" {
+			return nil, newValidationError(path+jsonPointer("name"), "objective name must not be empty")
+		}
+		if names[objectiveData.Name] {
+			return nil, newValidationError(path+jsonPointer("name"), "duplicate objective %s", objectiveData.Name)
+		}
+		names[objectiveData.Name] = true
+
+		objective := &world.Objective{
+			Name:        objectiveData.Name,
+			Description: objectiveData.Description,
+		}
+		if objectiveData.RevealOn != nil {
+			event, err := createObjectiveEvent(*objectiveData.RevealOn, path+jsonPointer("reveal_on"), paths)
+			if err != nil {
+				return nil, err
+			}
+			objective.RevealOn = event
+		}
+		event, err := createObjectiveEvent(objectiveData.CompleteOn, path+jsonPointer("complete_on"), paths)
+		if err != nil {
+			return nil, err
+		}
+		objective.CompleteOn = *event
+		objectives = append(objectives, objective)
+	}
+	return objectives, nil
+}
+
+// createObjectiveEvent creates an objective event, checking the entity it refers to exists.
+func createObjectiveEvent(eventData ObjectiveEventData, path string, paths *levelPaths) (*world.Event, error) {
+	return createEvent("objective", eventData, path, paths)
+}
+
+// createEvent creates an event from its JSON form, checking the entity it refers to exists.
+// The kind names what the event is for in error messages.
+func createEvent(kind string, eventData ObjectiveEventData, path string, paths *levelPaths) (*world.Event, error) {
+	event := &world.Event{
+		RoomName:    eventData.RoomName,
+		ItemName:    eventData.ItemName,
+		FixtureName: eventData.FixtureName,
+		EnemyName:   eventData.EnemyName,
+	}
+	var field, name string
+	var known map[string]string
+	switch eventData.Event {
+	case "room_entered":
+		event.Event = world.EventRoomEntered
+		field, name, known = "room_name", eventData.RoomName, paths.rooms
+	case "item_taken":
+		event.Event = world.EventItemTaken
+		field, name, known = "item_name", eventData.ItemName, paths.items
+	case "fixture_used":
+		event.Event = world.EventFixture
+		field, name, known = "fixture_name", eventData.FixtureName, paths.items
+	case "enemy_killed":
+		event.Event = world.EventEnemyKilled
+		field, name, known = "enemy_name", eventData.EnemyName, paths.enemies
+	default:
+		return nil, newValidationError(path+jsonPointer("event"), "invalid %s event %q", kind, eventData.Event)
+	}
+	if _, exists := known[name]; !exists {
+		return nil, newValidationError(path+jsonPointer(field), "%s event refers to unknown %s %q", kind, field[:len(field)-len("_name")], name)
+	}
+	return event, nil
+}
@@ -0,0 +1,93 @@
+package loader
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"adventure-engine/pkg/world"
+)
+
+func objectivesLevel(objectives string) json.RawMessage {
+	return json.RawMessage(`{
+		"name": "objectives test",
+		"rooms": [
+			{"name": "hall", "description": "a hall", "connections": [{"door_name": "cellar door"}],
+				"items": [{"name": "lamp", "description": "a lamp", "location": "on a table", "portable": true}]},
+			{"name": "cellar", "description": "a cellar", "connections": [{"door_name": "cellar door"}]}
+		],
+		"doors": [{"name": "cellar door", "room_a": "hall", "room_b": "cellar"}],
+		"objectives": ` + objectives + `
+	}`)
+}
+
+func TestLoadGame_Objectives(t *testing.T) {
+	level, err := LoadGame(objectivesLevel(`[
+		{"name": "light", "description": "find a light", "complete_on": {"event": "item_taken", "item_name": "lamp"}},
+		{"name": "explore", "description": "explore the cellar",
+			"reveal_on": {"event": "item_taken", "item_name": "lamp"},
+			"complete_on": {"event": "room_entered", "room_name": "cellar"}}
+	]`))
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if len(level.Objectives) != 2 {
+		t.Fatalf("Expected 2 objectives, got %d", len(level.Objectives))
+	}
+	if level.Objectives[0].RevealOn != nil {
+		t.Error("Expected light to have no reveal event")
+	}
+	explore := level.Objectives[1]
+	if explore.RevealOn == nil || explore.RevealOn.Event != world.EventItemTaken || explore.RevealOn.ItemName != "lamp" {
+		t.Errorf("Expected explore to be revealed by taking the lamp, got %+v", explore.RevealOn)
+	}
+	if explore.CompleteOn.Event != world.EventRoomEntered || explore.CompleteOn.RoomName != "cellar" {
+		t.Errorf("Expected explore to be completed by entering the cellar, got %+v", explore.CompleteOn)
+	}
+}
+
+func TestLoadGame_ObjectiveErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		objectives string
+		path       string
+		message    string
+	}{
+		{
+			name:       "unknown room",
+			objectives: `[{"name": "a", "description": "a", "complete_on": {"event": "room_entered", "room_name": "attic"}}]`,
+			path:       "/objectives/0/complete_on/room_name",
+			message:    `objective event refers to unknown room "attic"`,
+		},
+		{
+			name: "unknown item in reveal event",
+			objectives: `[{"name": "a", "description": "a", "reveal_on": {"event": "item_taken", "item_name": "torch"},
+				"complete_on": {"event": "room_entered", "room_name": "cellar"}}]`,
+			path:    "/objectives/0/reveal_on/item_name",
+			message: `objective event refers to unknown item "torch"`,
+		},
+		{
+			name: "duplicate name",
+			objectives: `[{"name": "a", "description": "a", "complete_on": {"event": "room_entered", "room_name": "cellar"}},
+				{"name": "a", "description": "b", "complete_on": {"event": "item_taken", "item_name": "lamp"}}]`,
+			path:    "/objectives/1/name",
+			message: "duplicate objective a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := ValidateLevel(objectivesLevel(tt.objectives))
+			errors := diagnostics.Errors()
+			if len(errors) == 0 {
+				t.Fatalf("Expected an error, got %+v", diagnostics)
+			}
+			if errors[0].Path != tt.path {
+				t.Errorf("Expected error at %q, got %q", tt.path, errors[0].Path)
+			}
+			if !strings.Contains(errors[0].Message, tt.message) {
+				t.Errorf("Expected error containing %q, got: %s", tt.message, errors[0].Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package loader
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"adventure-engine/pkg/world"
+)
+
+// JSONSchema returns a JSON Schema (draft 2020-12) describing the current level format.
+// The schema is derived from the loader's data structs: property names come from their
+// json tags, and constraints from their schema tags, which take a comma separated list of:
+//
+//	required      the property must be present
+//	nonempty      strings must not be empty, arrays must have at least one element
+//	enum=a|b|c    the value must be one of the listed strings
+//	custom        with enum, the value may also name a custom effect, such as custom:flood
+//	localized     the text may also be an object mapping languages to text
+//
+// Legacy level files using the top-level 'rooms' field predate the current schema
+// version and are accepted by the loader, but are not described by the schema.
+func JSONSchema() json.RawMessage {
+	b := &schemaBuilder{defs: make(map[string]any)}
+	root := b.structSchema(reflect.TypeOf(GameData{}))
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "Level"
+	root["additionalProperties"] = false
+	root["properties"].(map[string]any)["schema_version"] = map[string]any{
+		"type":    "integer",
+		"minimum": 1,
+		"maximum": CurrentSchemaVersion,
+	}
+	root["$defs"] = b.defs
+
+	data, err := json.Marshal(root)
+	if err != nil {
+		// The schema is built from plain maps and cannot fail to marshal
+		panic("failed to marshal level schema: " + err.Error())
+	}
+	return data
+}
+
+// schemaBuilder collects struct definitions while building a schema.
+type schemaBuilder struct {
+	defs map[string]any
+}
+
+// typeSchema returns the schema for a Go type, referencing structs by definition.
+func (b *schemaBuilder) typeSchema(t reflect.Type) map[string]any {
+	// Raw JSON is any value, left to whoever reads it
+	if t == reflect.TypeOf(json.RawMessage{}) {
+		return map[string]any{}
+	}
+	// Container contents are either the string "empty", an item or a loot table roll
+	if t == reflect.TypeOf(ContainerContents{}) {
+		return map[string]any{
+			"anyOf": []any{
+				map[string]any{"const": "empty"},
+				b.typeSchema(reflect.TypeOf(ItemData{})),
+				b.typeSchema(reflect.TypeOf(LootData{})),
+			},
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return b.typeSchema(t.Elem())
+	case reflect.Struct:
+		name := t.Name()
+		if _, exists := b.defs[name]; !exists {
+			b.defs[name] = nil // placeholder for recursive types
+			b.defs[name] = b.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + name}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": b.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.typeSchema(t.Elem())}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	}
+	return map[string]any{}
+}
+
+// structSchema returns the object schema for a struct type.
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		property := b.typeSchema(field.Type)
+		localized, custom := false, false
+		for _, option := range strings.Split(field.Tag.Get("schema"), ",") {
+			switch {
+			case option == "required":
+				required = append(required, name)
+			case option == "nonempty" && property["type"] == "array":
+				property["minItems"] = 1
+			case option == "nonempty":
+				property["minLength"] = 1
+			case strings.HasPrefix(option, "enum="):
+				property["enum"] = strings.Split(strings.TrimPrefix(option, "enum="), "|")
+			case option == "localized":
+				localized = true
+			case option == "custom":
+				custom = true
+			}
+		}
+		if custom {
+			property = map[string]any{
+				"anyOf": []any{
+					property,
+					map[string]any{"type": "string", "pattern": "^" + world.CustomEffectPrefix + ".+"},
+				},
+			}
+		}
+		if localized {
+			property = map[string]any{
+				"anyOf": []any{
+					property,
+					map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}, "minProperties": 1},
+				},
+			}
+		}
+		properties[name] = property
+	}
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,67 @@
+package loader
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal(JSONSchema(), &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+
+	if schema["additionalProperties"] != false {
+		t.Error("Expected top-level additional properties to be disallowed")
+	}
+
+	// Every top-level field accepted by the loader is described, except legacy rooms
+	properties := schema["properties"].(map[string]any)
+	for _, field := range []string{"name", "floors", "doors", "enemies", "win_condition", "combo_items",
+		"intro_narrative", "outro_narrative", "failure_narrative", "system_prompt_theme", "scoring", "schema_version", "item_templates", "combat", "rest_encounter"} {
+		if _, ok := properties[field]; !ok {
+			t.Errorf("Expected top-level property %s", field)
+		}
+	}
+	if _, ok := properties["rooms"]; ok {
+		t.Error("Expected legacy rooms field to be omitted from the schema")
+	}
+
+	required := schema["required"].([]any)
+	if !slices.Contains(required, any("name")) || !slices.Contains(required, any("floors")) {
+		t.Errorf("Expected name and floors to be required, got %v", required)
+	}
+
+	defs := schema["$defs"].(map[string]any)
+	item, ok := defs["ItemData"].(map[string]any)
+	if !ok {
+		t.Fatal("Expected ItemData definition")
+	}
+	itemProperties := item["properties"].(map[string]any)
+
+	// Nested items reference the item definition
+	conceals := itemProperties["conceals"].(map[string]any)
+	if conceals["$ref"] != "#/$defs/ItemData" {
+		t.Errorf("Expected conceals to reference ItemData, got %v", conceals)
+	}
+	contains := itemProperties["contains"].(map[string]any)
+	if anyOf, ok := contains["anyOf"].([]any); !ok || len(anyOf) != 3 {
+		t.Errorf("Expected contains to be empty, an item or a loot roll, got %v", contains)
+	}
+
+	healthEffect := itemProperties["health_effect"].(map[string]any)
+	if enum, ok := healthEffect["enum"].([]any); !ok || len(enum) != 2 {
+		t.Errorf("Expected health effect enum, got %v", healthEffect)
+	}
+
+	// Rooms and their connections can be described
+	room := defs["RoomData"].(map[string]any)["properties"].(map[string]any)
+	connection := defs["ConnectionData"].(map[string]any)["properties"].(map[string]any)
+	if _, ok := room["initial_description"]; !ok {
+		t.Error("Expected rooms to have an initial description")
+	}
+	if _, ok := connection["description"]; !ok {
+		t.Error("Expected connections to have a description")
+	}
+}
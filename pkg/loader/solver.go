@@ -0,0 +1,723 @@
+package loader
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"adventure-engine/pkg/world"
+)
+
+// levelPaths maps entity names to their JSON pointers in the level document,
+// so that solver diagnostics can point at the content that needs fixing.
+type levelPaths struct {
+	rooms       map[string]string
+	doors       map[string]string
+	items       map[string]string
+	enemies     map[string]string
+	travelNodes map[string]string
+	comboInputs [][]string // paths of each combo item's inputs, in level order
+}
+
+func newLevelPaths() *levelPaths {
+	return &levelPaths{
+		rooms:       make(map[string]string),
+		doors:       make(map[string]string),
+		items:       make(map[string]string),
+		enemies:     make(map[string]string),
+		travelNodes: make(map[string]string),
+	}
+}
+
+// addItem records the path of an item and its nested items.
+func (p *levelPaths) addItem(itemData ItemData, path string) {
+	p.items[itemData.Name] = path
+	for _, nested := range nestedItemData(itemData, path) {
+		p.addItem(nested.data, nested.path)
+	}
+}
+
+// solverState is the progress a player can make through a level,
+// assuming every enemy can be beaten and every code lock that has not jammed can be opened.
+// Jamming a keypad is never counted on, so enemies only encountered that way are not either.
+// Item consumption is ignored; an item counts as obtainable once it can be picked up,
+// produced by a fixture or crafted.
+type solverState struct {
+	forcedDoor    string          // door treated as open regardless of its lock or latch
+	blockedDoor   string          // door treated as impassable
+	withheldItem  string          // item treated as unobtainable
+	uncraftedItem string          // item treated as never crafted with
+	rooms         map[string]bool // rooms the player can enter
+	items         map[string]bool // items the player can obtain
+	fixtures      map[string]bool // fixtures the player can complete
+	enemies       map[string]bool // enemies the player can encounter and defeat
+	unlocked      map[string]bool // doors and containers unlocked by completing a fixture
+	revealed      map[string]bool // hidden doors revealed by a fixture, a trigger or moving furniture
+	powered       map[string]bool // travel nodes powered by completing a fixture
+	visited       map[string]bool // rooms the player was in before, whose travel nodes they know of
+	levelComplete bool            // a fixture the player can complete ends the level
+}
+
+// solve explores the level from the starting room until no more progress can be made.
+func solve(level *world.Level, forcedDoor string) *solverState {
+	s := newSolverState(level.Floors[0].Rooms[0].Name)
+	s.forcedDoor = forcedDoor
+	s.explore(level)
+	return s
+}
+
+func newSolverState(startRoom string) *solverState {
+	return &solverState{
+		rooms:    map[string]bool{startRoom: true},
+		items:    make(map[string]bool),
+		fixtures: make(map[string]bool),
+		enemies:  make(map[string]bool),
+		unlocked: make(map[string]bool),
+		revealed: make(map[string]bool),
+		powered:  make(map[string]bool),
+		visited:  make(map[string]bool),
+	}
+}
+
+// strandedBy returns true if going through a one-way door leaves the player unable to
+// get back through it or win, even having made every bit of progress possible beforehand.
+func strandedBy(level *world.Level, door *world.Door) bool {
+	before := newSolverState(level.Floors[0].Rooms[0].Name)
+	before.blockedDoor = door.Name
+	before.explore(level)
+	if !before.rooms[door.RoomA] {
+		return false
+	}
+
+	// Only what the player carries, and what they changed in the level, comes with them
+	after := &solverState{
+		rooms:    map[string]bool{door.RoomB: true},
+		items:    maps.Clone(before.items),
+		fixtures: maps.Clone(before.fixtures),
+		enemies:  maps.Clone(before.enemies),
+		unlocked: maps.Clone(before.unlocked),
+		revealed: maps.Clone(before.revealed),
+		powered:  maps.Clone(before.powered),
+		visited:  before.rooms,
+	}
+	after.explore(level)
+	return !after.rooms[door.RoomA] && (level.WinCondition == nil || !winAttainable(level.WinCondition, after))
+}
+
+// neededToWin returns true if the level can't be won without an item, though it can be without
+// crafting with it, so that giving it away or crafting with it would leave the player stuck.
+func neededToWin(level *world.Level, itemName string) bool {
+	without := newSolverState(level.Floors[0].Rooms[0].Name)
+	without.withheldItem = itemName
+	without.explore(level)
+	if won(level, without) {
+		return false
+	}
+	kept := newSolverState(level.Floors[0].Rooms[0].Name)
+	kept.uncraftedItem = itemName
+	kept.explore(level)
+	return won(level, kept)
+}
+
+// won returns true if the player can complete the level or attain its win condition.
+func won(level *world.Level, state *solverState) bool {
+	return state.levelComplete || level.WinCondition != nil && winAttainable(level.WinCondition, state)
+}
+
+// explore makes all the progress it can from the rooms already reached.
+func (s *solverState) explore(level *world.Level) {
+	doors := make(map[string]*world.Door, len(level.Doors))
+	for _, door := range level.Doors {
+		doors[door.Name] = door
+	}
+	itemRooms := collectItemRooms(level)
+
+	for changed := true; changed; {
+		changed = false
+
+		// Collect items from reachable rooms
+		for _, floor := range level.Floors {
+			for _, room := range floor.Rooms {
+				if !s.rooms[room.Name] {
+					continue
+				}
+				for _, item := range room.Items {
+					if s.visitItem(item) {
+						changed = true
+					}
+				}
+			}
+		}
+
+		// Craft combo items, at their fixture if they need one
+		for _, comboItem := range level.ComboItems {
+			if slices.ContainsFunc(comboItem.InputNames(), func(name string) bool { return !s.items[name] || name == s.uncraftedItem }) {
+				continue
+			}
+			if comboItem.FixtureName != "" && !s.rooms[itemRooms[comboItem.FixtureName]] {
+				continue
+			}
+			if s.obtain(comboItem.OutputItem.Name) {
+				changed = true
+			}
+			for _, byproduct := range comboItem.Byproducts {
+				if s.visitItem(byproduct) {
+					changed = true
+				}
+			}
+		}
+
+		// Go through doors, which can only be found through a room's connections
+		for _, floor := range level.Floors {
+			for _, room := range floor.Rooms {
+				if !s.rooms[room.Name] {
+					continue
+				}
+				for _, conn := range room.Connections {
+					door := doors[conn.DoorName]
+					if door == nil || !door.CanTraverseFrom(room.Name) || !s.canPass(door) {
+						continue
+					}
+					otherRoomName := door.RoomA
+					if door.RoomA == room.Name {
+						otherRoomName = door.RoomB
+					}
+					if !s.rooms[otherRoomName] {
+						s.rooms[otherRoomName] = true
+						changed = true
+					}
+				}
+			}
+		}
+
+		// Travel from a reachable node to the discovered nodes on its network
+		travelNodes := level.TravelNodes()
+		networks := make(map[string]bool)
+		for _, room := range travelNodes {
+			if s.rooms[room.Name] && s.canTravel(room.TravelNode) {
+				networks[room.TravelNode.Network] = true
+			}
+		}
+		for _, room := range travelNodes {
+			node := room.TravelNode
+			if !s.rooms[room.Name] && networks[node.Network] && s.canTravel(node) && (node.Discovered || s.visited[room.Name]) {
+				s.rooms[room.Name] = true
+				changed = true
+			}
+		}
+
+		// Search enemies that surrender once beaten
+		for _, enemy := range level.Enemies {
+			if s.enemies[enemy.Name] && enemy.Morale > 0 && s.visitItem(enemy.Carries) {
+				changed = true
+			}
+		}
+
+		// Fire enemy and secret door triggers
+		for _, trigger := range level.Triggers {
+			switch trigger.EffectType {
+			case world.EffectEnterCombat:
+				if !s.enemies[trigger.Effect.EnemyName] && s.fired(&trigger.Event) {
+					s.enemies[trigger.Effect.EnemyName] = true
+					changed = true
+				}
+			case world.EffectRevealDoor:
+				if !s.revealed[trigger.Effect.TargetName] && s.fired(&trigger.Event) {
+					s.revealed[trigger.Effect.TargetName] = true
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// visitItem marks an accessible item and whatever it reveals, contains or produces.
+// Returns true if any new progress was made.
+func (s *solverState) visitItem(item *world.Item) bool {
+	if item == nil {
+		return false
+	}
+	changed := false
+	if item.IsPortable() && s.obtain(item.Name) {
+		changed = true
+	}
+	if item.Durability != nil && item.Durability.Scrap != nil && s.visitItem(item.Durability.Scrap) {
+		changed = true
+	}
+	if item.IsConcealer() && s.visitItem(item.Concealer.Hidden) {
+		changed = true
+	}
+	if item.IsContainer() && s.canOpenContainer(item) && s.visitItem(item.Container.Contains) {
+		changed = true
+	}
+	if item.IsMoveable() {
+		if s.visitItem(item.Moveable.Reveals) {
+			changed = true
+		}
+		if doorName := item.Moveable.RevealsDoor; doorName != "" && !s.revealed[doorName] {
+			s.revealed[doorName] = true
+			changed = true
+		}
+	}
+	if item.IsFixture() && s.canCompleteFixture(item.Fixture) {
+		if !s.fixtures[item.Name] {
+			s.fixtures[item.Name] = true
+			s.runEffects(item.Fixture.OnComplete)
+			changed = true
+		}
+		if produced := item.Fixture.Produces; produced != nil && s.obtain(produced.Name) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// obtain marks an item as obtainable, unless it is withheld.
+// Returns true if it was not already.
+func (s *solverState) obtain(name string) bool {
+	if s.items[name] || name == s.withheldItem {
+		return false
+	}
+	s.items[name] = true
+	return true
+}
+
+// runEffects records what completing a fixture opens up.
+// Driving an enemy off changes nothing, as every enemy is assumed to be beatable.
+func (s *solverState) runEffects(effects []world.Effect) {
+	for _, effect := range effects {
+		switch effect.EffectType {
+		case world.EffectUnlock:
+			s.unlocked[effect.TargetName] = true
+		case world.EffectRevealDoor:
+			s.revealed[effect.TargetName] = true
+		case world.EffectPower:
+			s.powered[effect.TargetName] = true
+		case world.EffectCompleteLevel:
+			s.levelComplete = true
+		}
+	}
+}
+
+func (s *solverState) canTravel(node *world.TravelNode) bool {
+	return !node.Unpowered || s.powered[node.Name]
+}
+
+func (s *solverState) canOpenContainer(item *world.Item) bool {
+	container := item.Container
+	if s.unlocked[item.Name] {
+		return true
+	}
+	if container.IsLocked() && container.Locked.Jammed {
+		return false
+	}
+	return !container.IsLocked() || !container.HasKeyLock() || s.items[container.Locked.KeyName]
+}
+
+func (s *solverState) canCompleteFixture(fixture *world.Fixture) bool {
+	for itemName := range fixture.RequiredItems {
+		if !s.items[itemName] {
+			return false
+		}
+	}
+	return true
+}
+
+// canPass returns true if the player can go through the door from a reachable room.
+func (s *solverState) canPass(door *world.Door) bool {
+	if door.Name == s.blockedDoor {
+		return false
+	}
+	if door.Name == s.forcedDoor {
+		return true
+	}
+	if door.Hidden && !s.revealed[door.Name] {
+		return false
+	}
+	if s.unlocked[door.Name] {
+		return true
+	}
+	if door.IsLocked() && door.HasKeyLock() && !s.items[door.Lock.KeyName] {
+		return false
+	}
+	if door.IsLocked() && door.Lock.Jammed {
+		return false
+	}
+	if door.IsLatched() && !s.rooms[door.Latch.LockedFrom] {
+		return false
+	}
+	return true
+}
+
+// fired returns true if the player can cause the event.
+func (s *solverState) fired(event *world.Event) bool {
+	switch event.Event {
+	case world.EventItemTaken:
+		return s.items[event.ItemName]
+	case world.EventRoomEntered:
+		return s.rooms[event.RoomName]
+	case world.EventFixture:
+		return s.fixtures[event.FixtureName]
+	case world.EventEnemyKilled:
+		return s.enemies[event.EnemyName]
+	}
+	return false
+}
+
+// analyzeSolvability checks that the level's win condition can be attained.
+// Problems that make the level unwinnable are reported as errors;
+// the same problems in a winnable level only leave content unused and are reported as warnings.
+func analyzeSolvability(level *world.Level, paths *levelPaths) Diagnostics {
+	var problems []Diagnostic
+	addProblem := func(path string, format string, args ...any) {
+		problems = append(problems, Diagnostic{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	items := collectLevelItems(level)
+	itemRooms := collectItemRooms(level)
+	doors := sortedDoors(level)
+
+	// Referenced items, and the targets of fixture effects, must exist
+	checkEffectTarget := func(path string, fixtureName string, effect world.Effect) {
+		switch effect.EffectType {
+		case world.EffectUnlock:
+			if level.HasDoor(effect.TargetName) {
+				if door := level.GetDoor(effect.TargetName); !door.IsLocked() && !door.IsLatched() {
+					addProblem(path, "fixture %s unlocks door %s, which is neither locked nor latched", fixtureName, effect.TargetName)
+				}
+			} else if container, exists := items[effect.TargetName]; !exists {
+				addProblem(path, "fixture %s unlocks %s, which does not exist in the level", fixtureName, effect.TargetName)
+			} else if !container.IsContainer() || !container.Container.IsLocked() {
+				addProblem(path, "fixture %s unlocks %s, which is not a locked door or container", fixtureName, effect.TargetName)
+			}
+		case world.EffectRevealDoor:
+			if !level.HasDoor(effect.TargetName) {
+				addProblem(path, "fixture %s reveals door %s, which does not exist in the level", fixtureName, effect.TargetName)
+			} else if !level.GetDoor(effect.TargetName).Hidden {
+				addProblem(path, "fixture %s reveals door %s, which is not hidden", fixtureName, effect.TargetName)
+			}
+		case world.EffectPower:
+			if paths.travelNodes[effect.TargetName] == "" {
+				addProblem(path, "fixture %s powers %s, which is not a travel node", fixtureName, effect.TargetName)
+			}
+		case world.EffectEndCombat:
+			if paths.enemies[effect.EnemyName] == "" {
+				addProblem(path, "fixture %s drives off enemy %s, which does not exist in the level", fixtureName, effect.EnemyName)
+			}
+		}
+	}
+	keyUses := make(map[string][]string)
+	checkKey := func(path string, target string, keyName string) {
+		keyUses[keyName] = append(keyUses[keyName], target)
+		key, exists := items[keyName]
+		if !exists {
+			addProblem(path, "%s requires key %s, which does not exist in the level", target, keyName)
+		} else if !key.IsKey() {
+			addProblem(path, "%s requires key %s, which is not a key", target, keyName)
+		}
+	}
+	for _, door := range doors {
+		if door.HasKeyLock() {
+			checkKey(paths.doors[door.Name], "door "+door.Name, door.Lock.KeyName)
+		}
+	}
+	firedAmmo := make(map[string]bool) // ammo types the level's weapons fire
+	for _, item := range items {
+		if item.IsWeapon() && item.Weapon.UsesAmmo() {
+			firedAmmo[item.AmmoType()] = true
+		}
+	}
+	for _, name := range sortedKeys(items) {
+		item := items[name]
+		if item.IsContainer() && item.Container.HasKeyLock() {
+			checkKey(paths.items[name], "container "+name, item.Container.Locked.KeyName)
+		}
+		if item.IsAmmoBox() && !firedAmmo[item.AmmoType()] {
+			addProblem(paths.items[name], "ammo box %s holds %s ammo, which no weapon in the level fires", name, item.AmmoType())
+		}
+		if item.IsFixture() {
+			for _, requiredItem := range sortedKeys(item.Fixture.RequiredItems) {
+				if _, exists := items[requiredItem]; !exists {
+					addProblem(paths.items[name], "fixture %s requires item %s, which does not exist in the level", name, requiredItem)
+				}
+			}
+			for i, effect := range item.Fixture.OnComplete {
+				checkEffectTarget(paths.items[name]+jsonPointer("fixture", "on_complete", i, "target"), name, effect)
+			}
+		}
+		if item.IsMoveable() && item.Moveable.RevealsDoor != "" {
+			path := paths.items[name] + jsonPointer("moveable", "reveals_door")
+			if doorName := item.Moveable.RevealsDoor; !level.HasDoor(doorName) {
+				addProblem(path, "moveable %s reveals door %s, which does not exist in the level", name, doorName)
+			} else if door := level.GetDoor(doorName); door.RoomA != itemRooms[name] && door.RoomB != itemRooms[name] {
+				addProblem(path, "moveable %s reveals door %s, which does not lead from its room", name, doorName)
+			} else if !door.Hidden && !item.Moveable.Moved {
+				addProblem(path, "moveable %s reveals door %s, which is not hidden", name, doorName)
+			}
+		}
+		if item.Trap != nil {
+			for i, effect := range item.Trap.Effects {
+				if effect.EffectType == world.EffectEnterCombat && paths.enemies[effect.EnemyName] == "" {
+					addProblem(paths.items[name]+jsonPointer("trap", "effects", i, "target"), "trap on %s sets enemy %s on the player, which does not exist in the level", name, effect.EnemyName)
+				}
+			}
+		}
+	}
+	for _, enemy := range level.Enemies {
+		for j, bribe := range enemy.Bribes {
+			if item, exists := items[bribe]; exists && item.QuestItem {
+				addProblem(paths.enemies[enemy.Name]+jsonPointer("bribes", j), "enemy %s takes quest item %s as a bribe, which can't be given away", enemy.Name, bribe)
+			}
+		}
+	}
+	for i, comboItem := range level.ComboItems {
+		for j, inputName := range comboItem.InputNames() {
+			if input, exists := items[inputName]; !exists {
+				addProblem(paths.comboInputs[i][j],
+					"combo item %s requires item %s, which does not exist in the level", comboItem.OutputItem.Name, inputName)
+			} else if input.QuestItem {
+				addProblem(paths.comboInputs[i][j],
+					"combo item %s requires quest item %s, which can't be crafted with", comboItem.OutputItem.Name, inputName)
+			}
+		}
+		for j, teacher := range comboItem.TaughtBy {
+			if _, exists := items[teacher]; !exists {
+				addProblem(jsonPointer("combo_items", i, "taught_by", j),
+					"combo item %s is taught by item %s, which does not exist in the level", comboItem.OutputItem.Name, teacher)
+			}
+		}
+		if comboItem.FixtureName == "" {
+			continue
+		}
+		if fixture, exists := items[comboItem.FixtureName]; !exists {
+			addProblem(jsonPointer("combo_items", i, "fixture"),
+				"combo item %s must be made at %s, which does not exist in the level", comboItem.OutputItem.Name, comboItem.FixtureName)
+		} else if !fixture.IsFixture() || itemRooms[fixture.Name] == "" {
+			addProblem(jsonPointer("combo_items", i, "fixture"),
+				"combo item %s must be made at %s, which is not a fixture in a room", comboItem.OutputItem.Name, comboItem.FixtureName)
+		}
+	}
+
+	// Explain everything that blocks progress
+	state := solve(level, "")
+	for _, door := range doors {
+		if state.rooms[door.RoomA] && state.rooms[door.RoomB] {
+			continue
+		}
+		if !state.rooms[door.RoomA] && !state.rooms[door.RoomB] {
+			continue
+		}
+		path := paths.doors[door.Name]
+		if door.IsLocked() && door.HasKeyLock() && !state.items[door.Lock.KeyName] {
+			if _, exists := items[door.Lock.KeyName]; !exists {
+				continue // already reported
+			}
+			if solve(level, door.Name).items[door.Lock.KeyName] {
+				addProblem(path, "key %s for door %s can only be obtained behind that door", door.Lock.KeyName, door.Name)
+			} else {
+				addProblem(path, "key %s for door %s cannot be obtained", door.Lock.KeyName, door.Name)
+			}
+		} else if door.IsLatched() && !state.rooms[door.Latch.LockedFrom] {
+			addProblem(path, "door %s is latched from %s, which cannot be reached", door.Name, door.Latch.LockedFrom)
+		} else if door.Hidden && !state.revealed[door.Name] {
+			addProblem(path, "door %s is hidden and is never revealed", door.Name)
+		}
+	}
+	for _, room := range level.TravelNodes() {
+		if node := room.TravelNode; node.Unpowered && state.rooms[room.Name] && !state.powered[node.Name] {
+			addProblem(paths.travelNodes[node.Name], "travel node %s has no power and is never powered", node.Name)
+		}
+	}
+	for _, name := range sortedKeys(items) {
+		item := items[name]
+		if item.IsContainer() && item.Container.IsLocked() && item.Container.HasKeyLock() &&
+			state.rooms[itemRooms[name]] && !state.items[item.Container.Locked.KeyName] {
+			if _, exists := items[item.Container.Locked.KeyName]; exists {
+				addProblem(paths.items[name], "key %s for container %s cannot be obtained", item.Container.Locked.KeyName, name)
+			}
+		}
+		if item.IsFixture() && state.rooms[itemRooms[name]] && !state.fixtures[name] {
+			var missing []string
+			for _, requiredItem := range sortedKeys(item.Fixture.RequiredItems) {
+				if _, exists := items[requiredItem]; exists && !state.items[requiredItem] {
+					missing = append(missing, requiredItem)
+				}
+			}
+			if len(missing) > 0 {
+				addProblem(paths.items[name], "fixture %s cannot be completed: %s cannot be obtained", name, strings.Join(missing, ", "))
+			}
+		}
+	}
+
+	// The win condition must be attainable
+	if winCondition := level.WinCondition; winCondition != nil {
+		switch winCondition.Event {
+		case world.EventRoomEntered:
+			if paths.rooms[winCondition.RoomName] == "" {
+				addProblem(jsonPointer("win_condition", "room_name"), "win condition references unknown room %s", winCondition.RoomName)
+			} else if !state.rooms[winCondition.RoomName] {
+				addProblem(jsonPointer("win_condition"), "win condition cannot be attained: room %s cannot be reached", winCondition.RoomName)
+			}
+		case world.EventEnemyKilled:
+			if paths.enemies[winCondition.EnemyName] == "" {
+				addProblem(jsonPointer("win_condition", "enemy_name"), "win condition references unknown enemy %s", winCondition.EnemyName)
+			} else if !state.enemies[winCondition.EnemyName] {
+				addProblem(jsonPointer("win_condition"), "win condition cannot be attained: enemy %s is never encountered", winCondition.EnemyName)
+			}
+		}
+	}
+
+	winnable := level.WinCondition == nil || winAttainable(level.WinCondition, state)
+	var diagnostics Diagnostics
+	for _, problem := range problems {
+		if winnable {
+			diagnostics.addWarning(problem.Path, "%s", problem.Message)
+		} else {
+			diagnostics.addError(problem.Path, newValidationError(problem.Path, "solvability check failed: %s", problem.Message))
+		}
+	}
+
+	// Rooms the player can never enter in a winnable level are optional content that is cut off
+	if winnable {
+		for _, floor := range level.Floors {
+			for _, room := range floor.Rooms {
+				if !state.rooms[room.Name] {
+					diagnostics.addWarning(paths.rooms[room.Name], "room %s cannot be reached but is not required to win", room.Name)
+				}
+			}
+		}
+	}
+
+	// Items needed to win that could be given away or crafted with must be quest items, so that
+	// players can't lose them
+	if won(level, state) {
+		atRisk := make(map[string]bool)
+		for _, enemy := range level.Enemies {
+			for _, bribe := range enemy.Bribes {
+				atRisk[bribe] = true
+			}
+		}
+		for _, comboItem := range level.ComboItems {
+			for _, inputName := range comboItem.InputNames() {
+				atRisk[inputName] = true
+			}
+		}
+		for _, name := range sortedKeys(atRisk) {
+			if item, exists := items[name]; exists && !item.QuestItem && neededToWin(level, name) {
+				path := paths.items[name] + jsonPointer("quest_item")
+				diagnostics.addError(path, newValidationError(path, "item %s is needed to win but could be given away or crafted with, so it must be a quest item", name))
+			}
+		}
+	}
+
+	// A one-way door should not leave the player stuck where they land
+	for _, door := range doors {
+		if door.OneWay && strandedBy(level, door) {
+			diagnostics.addWarning(paths.doors[door.Name],
+				"one-way door %s strands the player in %s, from which they can neither get back nor win", door.Name, door.RoomB)
+		}
+	}
+
+	// Keys are consumed on use, so a key can only ever open one lock
+	for _, keyName := range sortedKeys(keyUses) {
+		if targets := keyUses[keyName]; len(targets) > 1 {
+			diagnostics.addWarning(paths.items[keyName],
+				"key %s is required by %s but is consumed on first use", keyName, strings.Join(targets, " and "))
+		}
+	}
+
+	return diagnostics
+}
+
+func winAttainable(winCondition *world.Event, state *solverState) bool {
+	if state.levelComplete {
+		return true
+	}
+	switch winCondition.Event {
+	case world.EventRoomEntered:
+		return state.rooms[winCondition.RoomName]
+	case world.EventEnemyKilled:
+		return state.enemies[winCondition.EnemyName]
+	}
+	return true
+}
+
+// collectLevelItems returns every item in the level by name,
+// including hidden, contained, produced and crafted items and those enemies carry.
+func collectLevelItems(level *world.Level) map[string]*world.Item {
+	items := make(map[string]*world.Item)
+	collect := func(item *world.Item) { items[item.Name] = item }
+	for _, floor := range level.Floors {
+		for _, room := range floor.Rooms {
+			for _, item := range room.Items {
+				walkItem(item, collect)
+			}
+		}
+	}
+	for _, comboItem := range level.ComboItems {
+		walkItem(comboItem.OutputItem, collect)
+		for _, byproduct := range comboItem.Byproducts {
+			walkItem(byproduct, collect)
+		}
+	}
+	for _, enemy := range level.Enemies {
+		walkItem(enemy.Carries, collect)
+	}
+	return items
+}
+
+// collectItemRooms maps each item placed in a room, directly or nested, to its room name.
+func collectItemRooms(level *world.Level) map[string]string {
+	itemRooms := make(map[string]string)
+	for _, floor := range level.Floors {
+		for _, room := range floor.Rooms {
+			for _, item := range room.Items {
+				walkItem(item, func(item *world.Item) { itemRooms[item.Name] = room.Name })
+			}
+		}
+	}
+	return itemRooms
+}
+
+// walkItem calls fn for an item and each item hidden, contained or produced by it,
+// left behind when it breaks or found behind it when it is moved.
+func walkItem(item *world.Item, fn func(*world.Item)) {
+	if item == nil {
+		return
+	}
+	fn(item)
+	if item.IsConcealer() {
+		walkItem(item.Concealer.Hidden, fn)
+	}
+	if item.IsContainer() {
+		walkItem(item.Container.Contains, fn)
+	}
+	if item.IsFixture() {
+		walkItem(item.Fixture.Produces, fn)
+	}
+	if item.Durability != nil {
+		walkItem(item.Durability.Scrap, fn)
+	}
+	if item.IsMoveable() {
+		walkItem(item.Moveable.Reveals, fn)
+	}
+}
+
+// sortedDoors returns the level's doors ordered by name.
+func sortedDoors(level *world.Level) []*world.Door {
+	doors := make([]*world.Door, len(level.Doors))
+	copy(doors, level.Doors)
+	sort.Slice(doors, func(i, j int) bool { return doors[i].Name < doors[j].Name })
+	return doors
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
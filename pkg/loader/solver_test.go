@@ -0,0 +1,226 @@
+package loader
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeSolvability(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    string
+		severity Severity
+		path     string
+		message  string
+	}{
+		{
+			name: "key locked behind the door it opens",
+			level: `{
+				"name": "test",
+				"win_condition": {"event": "room_entered", "room_name": "vault"},
+				"rooms": [
+					{"name": "hall", "description": "a hall", "connections": [{"door_name": "vault door"}]},
+					{"name": "vault", "description": "a vault", "connections": [{"door_name": "vault door"}],
+						"items": [{"name": "vault key", "description": "a key", "portable": true, "key": true}]}
+				],
+				"doors": [{"name": "vault door", "room_a": "hall", "room_b": "vault", "locked": true, "required_key_name": "vault key"}]
+			}`,
+			severity: SeverityError,
+			path:     "/doors/0",
+			message:  "key vault key for door vault door can only be obtained behind that door",
+		},
+		{
+			name: "missing fixture ingredient",
+			level: `{
+				"name": "test",
+				"win_condition": {"event": "room_entered", "room_name": "vault"},
+				"rooms": [
+					{"name": "hall", "description": "a hall", "connections": [{"door_name": "vault door"}],
+						"items": [{"name": "altar", "description": "an altar",
+							"fixture": {"required_items": ["idol"], "produces": {"name": "vault key", "description": "a key", "portable": true, "key": true}}}]},
+					{"name": "vault", "description": "a vault", "connections": [{"door_name": "vault door"}]}
+				],
+				"doors": [{"name": "vault door", "room_a": "hall", "room_b": "vault", "locked": true, "required_key_name": "vault key"}]
+			}`,
+			severity: SeverityError,
+			path:     "/rooms/0/items/0",
+			message:  "fixture altar requires item idol, which does not exist in the level",
+		},
+		{
+			name: "missing combo input",
+			level: `{
+				"name": "test",
+				"win_condition": {"event": "room_entered", "room_name": "vault"},
+				"rooms": [
+					{"name": "hall", "description": "a hall", "connections": [{"door_name": "vault door"}],
+						"items": [{"name": "key handle", "description": "half a key", "portable": true}]},
+					{"name": "vault", "description": "a vault", "connections": [{"door_name": "vault door"}]}
+				],
+				"doors": [{"name": "vault door", "room_a": "hall", "room_b": "vault", "locked": true, "required_key_name": "vault key"}],
+				"combo_items": [{"input_item_a_name": "key handle", "input_item_b_name": "key blade",
+					"output_item": {"name": "vault key", "description": "a key", "portable": true, "key": true}}]
+			}`,
+			severity: SeverityError,
+			path:     "/combo_items/0/input_item_b_name",
+			message:  "combo item vault key requires item key blade, which does not exist in the level",
+		},
+		{
+			name: "latched from an unreachable room",
+			level: `{
+				"name": "test",
+				"win_condition": {"event": "room_entered", "room_name": "vault"},
+				"rooms": [
+					{"name": "hall", "description": "a hall", "connections": [{"door_name": "vault door"}]},
+					{"name": "vault", "description": "a vault", "connections": [{"door_name": "vault door"}]}
+				],
+				"doors": [{"name": "vault door", "room_a": "hall", "room_b": "vault", "latched_from": "vault"}]
+			}`,
+			severity: SeverityError,
+			path:     "/win_condition",
+			message:  "win condition cannot be attained: room vault cannot be reached",
+		},
+		{
+			name: "win enemy never encountered",
+			level: `{
+				"name": "test",
+				"win_condition": {"event": "enemy_killed", "enemy_name": "boss"},
+				"rooms": [{"name": "hall", "description": "a hall"}],
+				"enemies": [{"name": "boss", "description": "a boss", "hp": 1}]
+			}`,
+			severity: SeverityError,
+			path:     "/win_condition",
+			message:  "enemy boss is never encountered",
+		},
+		{
+			name: "unobtainable key in a winnable level",
+			level: `{
+				"name": "test",
+				"win_condition": {"event": "room_entered", "room_name": "hall"},
+				"rooms": [
+					{"name": "hall", "description": "a hall", "items": [
+						{"name": "safe", "description": "a safe", "required_key_name": "safe key", "contains": "empty"}
+					]}
+				]
+			}`,
+			severity: SeverityWarning,
+			path:     "/rooms/0/items/0",
+			message:  "container safe requires key safe key, which does not exist in the level",
+		},
+		{
+			name: "ammo for a missing weapon",
+			level: `{
+				"name": "test",
+				"win_condition": {"event": "room_entered", "room_name": "hall"},
+				"rooms": [
+					{"name": "hall", "description": "a hall", "items": [
+						{"name": "shells", "description": "a box of shells", "weapon_name": "shotgun", "ammo": 4}
+					]}
+				]
+			}`,
+			severity: SeverityWarning,
+			path:     "/rooms/0/items/0",
+			message:  "ammo box shells holds shotgun ammo, which no weapon in the level fires",
+		},
+		{
+			name: "ammo for a weapon without ammo",
+			level: `{
+				"name": "test",
+				"win_condition": {"event": "room_entered", "room_name": "hall"},
+				"rooms": [
+					{"name": "hall", "description": "a hall", "items": [
+						{"name": "machete", "description": "a machete", "weapon_damage": 0.5},
+						{"name": "shells", "description": "a box of shells", "weapon_name": "machete", "ammo": 4}
+					]}
+				]
+			}`,
+			severity: SeverityWarning,
+			path:     "/rooms/0/items/1",
+			message:  "ammo box shells holds machete ammo, which no weapon in the level fires",
+		},
+		{
+			name: "key used by two locks",
+			level: `{
+				"name": "test",
+				"rooms": [
+					{"name": "hall", "description": "a hall", "connections": [{"door_name": "door1"}, {"door_name": "door2"}],
+						"items": [{"name": "skeleton key", "description": "a key", "portable": true, "key": true}]},
+					{"name": "study", "description": "a study", "connections": [{"door_name": "door1"}]},
+					{"name": "library", "description": "a library", "connections": [{"door_name": "door2"}]}
+				],
+				"doors": [
+					{"name": "door1", "room_a": "hall", "room_b": "study", "locked": true, "required_key_name": "skeleton key"},
+					{"name": "door2", "room_a": "hall", "room_b": "library", "locked": true, "required_key_name": "skeleton key"}
+				]
+			}`,
+			severity: SeverityWarning,
+			path:     "/rooms/0/items/0",
+			message:  "key skeleton key is required by door door1 and door door2 but is consumed on first use",
+		},
+		{
+			name: "one-way drop into a dead end",
+			level: `{
+				"name": "test",
+				"win_condition": {"event": "room_entered", "room_name": "vault"},
+				"rooms": [
+					{"name": "hall", "description": "a hall", "connections": [{"door_name": "ledge"}, {"door_name": "vault door"}]},
+					{"name": "pit", "description": "a pit", "connections": [{"door_name": "ledge"}]},
+					{"name": "vault", "description": "a vault", "connections": [{"door_name": "vault door"}]}
+				],
+				"doors": [
+					{"name": "ledge", "room_a": "hall", "room_b": "pit", "one_way": true},
+					{"name": "vault door", "room_a": "hall", "room_b": "vault"}
+				]
+			}`,
+			severity: SeverityWarning,
+			path:     "/doors/0",
+			message:  "one-way door ledge strands the player in pit, from which they can neither get back nor win",
+		},
+		{
+			name: "room only reachable against a one-way door",
+			level: `{
+				"name": "test",
+				"rooms": [
+					{"name": "hall", "description": "a hall", "connections": [{"door_name": "ledge"}]},
+					{"name": "balcony", "description": "a balcony", "connections": [{"door_name": "ledge"}]}
+				],
+				"doors": [{"name": "ledge", "room_a": "balcony", "room_b": "hall", "one_way": true}]
+			}`,
+			severity: SeverityError,
+			path:     "/rooms/1",
+			message:  "unreachable rooms found: [balcony]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := ValidateLevel(json.RawMessage(tt.level))
+			for _, diagnostic := range diagnostics {
+				if diagnostic.Severity == tt.severity && diagnostic.Path == tt.path && strings.Contains(diagnostic.Message, tt.message) {
+					return
+				}
+			}
+			t.Errorf("Expected %s at %q containing %q, got: %+v", tt.severity, tt.path, tt.message, diagnostics)
+		})
+	}
+}
+
+func TestLoadGame_Unsolvable(t *testing.T) {
+	jsonData := json.RawMessage(`{
+		"name": "test",
+		"win_condition": {"event": "room_entered", "room_name": "vault"},
+		"rooms": [
+			{"name": "hall", "description": "a hall", "connections": [{"door_name": "vault door"}]},
+			{"name": "vault", "description": "a vault", "connections": [{"door_name": "vault door"}]}
+		],
+		"doors": [{"name": "vault door", "room_a": "hall", "room_b": "vault", "locked": true, "required_key_name": "vault key"}]
+	}`)
+
+	_, err := LoadGame(jsonData)
+	if err == nil {
+		t.Fatal("Expected unsolvable level to fail loading, got nil error")
+	}
+	if !strings.Contains(err.Error(), "solvability check failed") {
+		t.Errorf("Expected solvability error, got: %v", err)
+	}
+}
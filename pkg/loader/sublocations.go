@@ -0,0 +1,96 @@
+package loader
+
+import (
+	"fmt"
+	"slices"
+
+	"adventure-engine/pkg/world"
+)
+
+// subLocationPrepositions are the ways items can be at a sub-location, as in "on the desk".
+var subLocationPrepositions = []string{"on", "in", "under", "behind", "beside", "by", "near", "above", "below", "against", "at"}
+
+// defaultSubLocationPreposition is the preposition of sub-locations that don't give one.
+const defaultSubLocationPreposition = "on"
+
+// createSubLocations creates a room's sub-locations, reporting nameless and duplicate ones and
+// unknown prepositions.
+func createSubLocations(roomData RoomData, roomPath string, diagnostics *Diagnostics) []world.SubLocation {
+	var subLocations []world.SubLocation
+	seen := make(map[string]bool)
+	for i, subLocationData := range roomData.SubLocations {
+		path := roomPath + jsonPointer("sub_locations", i)
+		preposition := subLocationData.Preposition
+		if preposition == "" {
+			preposition = defaultSubLocationPreposition
+		}
+		switch {
+		case subLocationData.Name == "":
+			diagnostics.addError(path+jsonPointer("name"), fmt.Errorf("sub-location in room %s must have a name", roomData.Name))
+		case seen[subLocationData.Name]:
+			diagnostics.addError(path+jsonPointer("name"), fmt.Errorf("duplicate sub-location %s in room %s", subLocationData.Name, roomData.Name))
+		case !slices.Contains(subLocationPrepositions, preposition):
+			diagnostics.addError(path+jsonPointer("preposition"), fmt.Errorf("invalid preposition %s of sub-location %s, must be one of %v", preposition, subLocationData.Name, subLocationPrepositions))
+		default:
+			seen[subLocationData.Name] = true
+			subLocations = append(subLocations, world.SubLocation{Name: subLocationData.Name, Preposition: preposition})
+		}
+	}
+	return subLocations
+}
+
+// placeItem checks the sub-location of an item lying in a room, which must be one of the
+// room's, and gives the item the sub-location's phrase, such as "on the desk", as its location
+// unless the author wrote one. Items held by the item can't be at sub-locations of their own.
+func placeItem(room *world.Room, item *world.Item, itemData ItemData, itemPath string, diagnostics *Diagnostics) {
+	if item.SubLocation != "" {
+		subLocation, ok := room.GetSubLocation(item.SubLocation)
+		if !ok {
+			diagnostics.addError(itemPath+jsonPointer("sub_location"), fmt.Errorf("item %s is at unknown sub-location %s of room %s", item.Name, item.SubLocation, room.Name))
+			item.SubLocation = ""
+		} else if item.Location == "" {
+			item.Location = subLocation.Phrase()
+		}
+	}
+	for _, nested := range nestedItemData(itemData, itemPath) {
+		checkNotSubLocated(nested.data, nested.path, diagnostics)
+	}
+}
+
+// checkNotSubLocated reports a sub-location on an item that doesn't lie in a room, such as one
+// in a container or carried by an enemy, along with those on the items it holds.
+func checkNotSubLocated(itemData ItemData, path string, diagnostics *Diagnostics) {
+	if itemData.SubLocation != "" {
+		diagnostics.addError(path+jsonPointer("sub_location"), fmt.Errorf("item %s doesn't lie in a room, so it can't be at sub-location %s", itemData.Name, itemData.SubLocation))
+	}
+	for _, nested := range nestedItemData(itemData, path) {
+		checkNotSubLocated(nested.data, nested.path, diagnostics)
+	}
+}
+
+// pathedItemData is an item in the JSON, with its JSON pointer.
+type pathedItemData struct {
+	data ItemData
+	path string
+}
+
+// nestedItemData returns the items an item holds, produces or breaks into in the JSON.
+func nestedItemData(itemData ItemData, path string) []pathedItemData {
+	var nested []pathedItemData
+	if itemData.Conceals != nil {
+		nested = append(nested, pathedItemData{*itemData.Conceals, path + jsonPointer("conceals")})
+	}
+	if itemData.Contains != nil && itemData.Contains.Item != nil {
+		nested = append(nested, pathedItemData{*itemData.Contains.Item, path + jsonPointer("contains")})
+	}
+	if itemData.Fixture != nil && itemData.Fixture.Produces != nil {
+		nested = append(nested, pathedItemData{*itemData.Fixture.Produces, path + jsonPointer("fixture", "produces")})
+	}
+	if itemData.Scrap != nil {
+		nested = append(nested, pathedItemData{*itemData.Scrap, path + jsonPointer("scrap")})
+	}
+	if itemData.Moveable != nil && itemData.Moveable.Reveals != nil {
+		nested = append(nested, pathedItemData{*itemData.Moveable.Reveals, path + jsonPointer("moveable", "reveals")})
+	}
+	return nested
+}
@@ -0,0 +1,142 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// expandTemplates replaces every item that names an item template with the template's
+// fields, overridden by the fields set on the item itself. Templates may be based on
+// other templates. Nested objects such as fixtures are overridden as a whole.
+func expandTemplates(data json.RawMessage) (json.RawMessage, error) {
+	var doc map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, &ValidationError{Err: fmt.Errorf("invalid JSON format: %w", err)}
+	}
+
+	templates := make(map[string]map[string]any)
+	if raw, ok := doc["item_templates"]; ok {
+		rawTemplates, ok := raw.(map[string]any)
+		if !ok {
+			return nil, newValidationError(jsonPointer("item_templates"), "field 'item_templates' must be an object")
+		}
+		for _, name := range sortedKeys(rawTemplates) {
+			template, ok := rawTemplates[name].(map[string]any)
+			if !ok {
+				return nil, newValidationError(jsonPointer("item_templates", name), "item template %s must be an object", name)
+			}
+			templates[name] = template
+		}
+	}
+
+	expander := &templateExpander{templates: templates}
+	for _, key := range sortedKeys(doc) {
+		if key == "item_templates" {
+			continue
+		}
+		expanded, err := expander.expand(doc[key], jsonPointer(key))
+		if err != nil {
+			return nil, err
+		}
+		doc[key] = expanded
+	}
+
+	return json.Marshal(doc)
+}
+
+type templateExpander struct {
+	templates map[string]map[string]any
+}
+
+// expand walks a JSON value, expanding any object with a template field.
+func (x *templateExpander) expand(value any, path string) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		if templateName, ok := v["template"]; ok {
+			merged, err := x.resolve(templateName, path+jsonPointer("template"), nil)
+			if err != nil {
+				return nil, err
+			}
+			for key, field := range v {
+				if key != "template" {
+					merged[key] = field
+				}
+			}
+			v = merged
+		}
+		for _, key := range sortedKeys(v) {
+			expanded, err := x.expand(v[key], path+jsonPointer(key))
+			if err != nil {
+				return nil, err
+			}
+			v[key] = expanded
+		}
+		return v, nil
+	case []any:
+		for i, element := range v {
+			expanded, err := x.expand(element, path+jsonPointer(i))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+		return v, nil
+	}
+	return value, nil
+}
+
+// resolve returns a copy of the named template's fields, including those of the templates it is based on.
+// The path is the location of the reference, used in error messages.
+func (x *templateExpander) resolve(templateName any, path string, seen []string) (map[string]any, error) {
+	name, ok := templateName.(string)
+	if !ok || name == "" {
+		return nil, newValidationError(path, "field 'template' must be a non-empty string")
+	}
+	if slices.Contains(seen, name) {
+		return nil, newValidationError(jsonPointer("item_templates", seen[0]),
+			"item template cycle: %s", strings.Join(append(seen, name), " -> "))
+	}
+	template, ok := x.templates[name]
+	if !ok {
+		return nil, newValidationError(path, "unknown item template %s", name)
+	}
+
+	resolved := make(map[string]any)
+	if baseName, ok := template["template"]; ok {
+		base, err := x.resolve(baseName, jsonPointer("item_templates", name, "template"), append(seen, name))
+		if err != nil {
+			return nil, err
+		}
+		resolved = base
+	}
+	for key, field := range template {
+		if key != "template" {
+			resolved[key] = deepCopyJSON(field)
+		}
+	}
+	return resolved, nil
+}
+
+// deepCopyJSON copies a decoded JSON value so that expanded items don't share nested objects.
+func deepCopyJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for key, field := range v {
+			c[key] = deepCopyJSON(field)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, element := range v {
+			c[i] = deepCopyJSON(element)
+		}
+		return c
+	}
+	return value
+}
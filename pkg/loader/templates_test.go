@@ -0,0 +1,110 @@
+package loader
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLoadGame_ItemTemplates(t *testing.T) {
+	jsonData := json.RawMessage(`{
+		"name": "templates test",
+		"item_templates": {
+			"ammo box": {"description": "a box of pistol rounds", "weapon_name": "pistol", "ammo": 6},
+			"big ammo box": {"template": "ammo box", "description": "a big box of pistol rounds", "ammo": 12},
+			"locker": {"description": "a metal locker", "contains": {"template": "ammo box", "name": "locker ammo"}}
+		},
+		"rooms": [
+			{
+				"name": "armory",
+				"description": "an armory",
+				"items": [
+					{"template": "ammo box", "name": "ammo box 1"},
+					{"template": "ammo box", "name": "ammo box 2", "location": "on the shelf", "ammo": 3},
+					{"template": "big ammo box", "name": "ammo box 3"},
+					{"template": "locker", "name": "locker"}
+				]
+			}
+		]
+	}`)
+
+	level, err := LoadGame(jsonData)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	room := getAllRooms(level)[0]
+
+	box1, _ := room.GetItem("ammo box 1")
+	box2, _ := room.GetItem("ammo box 2")
+	box3, _ := room.GetItem("ammo box 3")
+	if box1 == nil || box2 == nil || box3 == nil {
+		t.Fatalf("Expected all ammo boxes to be created, got %+v", room.Items)
+	}
+	if !box1.IsAmmoBox() || box1.AmmoBox.Ammo.Quantity != 6 || box1.Description != "a box of pistol rounds" {
+		t.Errorf("Expected ammo box 1 to match its template, got %+v", box1)
+	}
+	if box2.AmmoBox.Ammo.Quantity != 3 || box2.Location != "on the shelf" {
+		t.Errorf("Expected ammo box 2 to override ammo and location, got %+v", box2)
+	}
+	if box3.AmmoBox.Ammo.Quantity != 12 || box3.AmmoBox.AmmoType != "pistol" {
+		t.Errorf("Expected ammo box 3 to inherit from its base template, got %+v", box3)
+	}
+	if box1.AmmoBox == box2.AmmoBox {
+		t.Error("Expected template instances not to share state")
+	}
+
+	locker, _ := room.GetItem("locker")
+	if locker == nil || !locker.IsContainer() || locker.Container.Contains == nil || locker.Container.Contains.Name != "locker ammo" {
+		t.Errorf("Expected locker to contain expanded ammo, got %+v", locker)
+	}
+}
+
+func TestLoadGame_ItemTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		path    string
+		message string
+	}{
+		{
+			name: "unknown template",
+			level: `{"name": "test", "rooms": [{"name": "room", "items": [
+				{"template": "missing", "name": "thing"}
+			]}]}`,
+			path:    "/rooms/0/items/0/template",
+			message: "unknown item template missing",
+		},
+		{
+			name: "template cycle",
+			level: `{"name": "test",
+				"item_templates": {"a": {"template": "b"}, "b": {"template": "a"}},
+				"rooms": [{"name": "room", "items": [{"template": "a", "name": "thing"}]}]}`,
+			path:    "/item_templates/a",
+			message: "item template cycle: a -> b -> a",
+		},
+		{
+			name: "template is not an object",
+			level: `{"name": "test",
+				"item_templates": {"a": "not an item"},
+				"rooms": [{"name": "room"}]}`,
+			path:    "/item_templates/a",
+			message: "item template a must be an object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := ValidateLevel(json.RawMessage(tt.level))
+			errors := diagnostics.Errors()
+			if len(errors) != 1 {
+				t.Fatalf("Expected 1 error, got %+v", diagnostics)
+			}
+			if errors[0].Path != tt.path {
+				t.Errorf("Expected error at %q, got %q", tt.path, errors[0].Path)
+			}
+			if !strings.Contains(errors[0].Message, tt.message) {
+				t.Errorf("Expected error containing %q, got: %s", tt.message, errors[0].Message)
+			}
+		})
+	}
+}
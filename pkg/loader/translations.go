@@ -0,0 +1,250 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"adventure-engine/pkg/world"
+)
+
+// localizedFields are the fields whose text can be given in several languages, as an object
+// mapping languages to text instead of a string.
+var localizedFields = map[string]bool{
+	"description":          true,
+	"initial_description":  true,
+	"detail":               true,
+	"intro_narrative":      true,
+	"outro_narrative":      true,
+	"failure_narrative":    true,
+	"completion_narrative": true,
+	"narrative":            true,
+	"interrogation":        true,
+}
+
+// languagePattern matches language tags such as "en", "de" or "pt-BR".
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// extractTranslations replaces the localized text objects in a level document with their
+// text in the level's own language, moving the other languages into the document's
+// translations table. The table maps each language to the level's texts, as written in
+// its own language, and their translations; authors may also write it directly.
+func extractTranslations(data json.RawMessage) (json.RawMessage, error) {
+	var doc map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, &ValidationError{Err: fmt.Errorf("invalid JSON format: %w", err)}
+	}
+
+	x := &translationExtractor{
+		language:     world.DefaultLanguage,
+		translations: make(map[string]map[string]string),
+	}
+	if raw, ok := doc["language"]; ok {
+		language, ok := raw.(string)
+		if !ok || !languagePattern.MatchString(language) {
+			return nil, newValidationError(jsonPointer("language"), "field 'language' must be a language tag such as \"en\"")
+		}
+		x.language = language
+	}
+	if raw, ok := doc["translations"]; ok {
+		if err := x.readTable(raw); err != nil {
+			return nil, err
+		}
+		delete(doc, "translations")
+	}
+
+	if _, err := x.extract(doc, ""); err != nil {
+		return nil, err
+	}
+	if len(x.translations) > 0 {
+		doc["translations"] = x.translations
+	}
+	return json.Marshal(doc)
+}
+
+type translationExtractor struct {
+	language     string
+	translations map[string]map[string]string // language -> text -> translated text
+}
+
+// readTable reads a translations table written by the level's author.
+func (x *translationExtractor) readTable(raw any) error {
+	table, ok := raw.(map[string]any)
+	if !ok {
+		return newValidationError(jsonPointer("translations"), "field 'translations' must be an object")
+	}
+	for _, language := range sortedKeys(table) {
+		path := jsonPointer("translations", language)
+		if !languagePattern.MatchString(language) {
+			return newValidationError(path, "invalid language %s", language)
+		}
+		if language == x.language {
+			return newValidationError(path, "translations into %s, the level's own language", language)
+		}
+		texts, ok := table[language].(map[string]any)
+		if !ok {
+			return newValidationError(path, "translations into %s must be an object", language)
+		}
+		for _, text := range sortedKeys(texts) {
+			translated, ok := texts[text].(string)
+			if !ok {
+				return newValidationError(path+jsonPointer(text), "translation of %q must be a string", text)
+			}
+			x.add(language, text, translated)
+		}
+	}
+	return nil
+}
+
+// extract walks a JSON value, replacing localized text objects with their text.
+func (x *translationExtractor) extract(value any, path string) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range sortedKeys(v) {
+			fieldPath := path + jsonPointer(key)
+			var err error
+			if text, ok := v[key].(map[string]any); ok && localizedFields[key] {
+				v[key], err = x.localize(text, fieldPath)
+			} else {
+				v[key], err = x.extract(v[key], fieldPath)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	case []any:
+		for i, element := range v {
+			extracted, err := x.extract(element, path+jsonPointer(i))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = extracted
+		}
+		return v, nil
+	}
+	return value, nil
+}
+
+// localize returns the text of a localized text object in the level's language,
+// recording its translations.
+func (x *translationExtractor) localize(text map[string]any, path string) (string, error) {
+	own, ok := text[x.language].(string)
+	if !ok {
+		return "", newValidationError(path, "localized text has no text in %s, the level's language", x.language)
+	}
+	for _, language := range sortedKeys(text) {
+		languagePath := path + jsonPointer(language)
+		if !languagePattern.MatchString(language) {
+			return "", newValidationError(languagePath, "invalid language %s", language)
+		}
+		translated, ok := text[language].(string)
+		if !ok {
+			return "", newValidationError(languagePath, "localized text must be a string")
+		}
+		if language == x.language {
+			continue
+		}
+		if existing, ok := x.translations[language][own]; ok && existing != translated {
+			return "", newValidationError(languagePath, "conflicting %s translations of %q: %q and %q", language, own, existing, translated)
+		}
+		x.add(language, own, translated)
+	}
+	return own, nil
+}
+
+func (x *translationExtractor) add(language, text, translated string) {
+	if x.translations[language] == nil {
+		x.translations[language] = make(map[string]string)
+	}
+	x.translations[language][text] = translated
+}
+
+// collectTranslationWarnings finds languages missing translations of some of the level's text,
+// which is shown in the level's own language instead.
+func collectTranslationWarnings(level *world.Level) Diagnostics {
+	var diagnostics Diagnostics
+	texts := levelTexts(level)
+	for _, language := range sortedKeys(level.Translations) {
+		translations := level.Translations[language]
+		var missing []string
+		for _, text := range sortedKeys(texts) {
+			if _, ok := translations[text]; !ok {
+				missing = append(missing, text)
+			}
+		}
+		if len(missing) > 0 {
+			diagnostics.addWarning("", "%d texts have no %s translation, such as %q", len(missing), language, missing[0])
+		}
+	}
+	return diagnostics
+}
+
+// levelTexts returns the level's localizable texts.
+func levelTexts(level *world.Level) map[string]bool {
+	texts := make(map[string]bool)
+	add := func(text string) {
+		if text != "" {
+			texts[text] = true
+		}
+	}
+	add(level.IntroNarrative)
+	add(level.OutroNarrative)
+	add(level.FailureNarrative)
+	for _, floor := range level.Floors {
+		add(floor.Description)
+		for _, room := range floor.Rooms {
+			add(room.Description)
+			add(room.InitialDescription)
+			for _, description := range room.ConditionalDescriptions {
+				add(description.Description)
+			}
+			for _, conn := range room.Connections {
+				add(conn.Description)
+			}
+			if room.TravelNode != nil {
+				add(room.TravelNode.Description)
+			}
+		}
+	}
+	for _, item := range collectLevelItems(level) {
+		add(item.Description)
+		add(item.Detail)
+		if item.IsFixture() {
+			for _, text := range fixtureNarratives(item.Fixture) {
+				add(text)
+			}
+		}
+		if item.IsMoveable() {
+			add(item.Moveable.Narrative)
+		}
+	}
+	for _, enemy := range level.Enemies {
+		add(enemy.Description)
+		add(enemy.Interrogation)
+	}
+	for _, objective := range level.Objectives {
+		add(objective.Description)
+	}
+	if level.Scoring != nil {
+		for _, badge := range level.Scoring.Badges {
+			add(badge.Description)
+		}
+	}
+	return texts
+}
+
+// fixtureNarratives returns the texts a fixture tells the player as items are used on it.
+func fixtureNarratives(fixture *world.Fixture) []string {
+	texts := []string{fixture.CompletionNarrative}
+	for _, stage := range fixture.Stages {
+		texts = append(texts, stage.Narrative)
+	}
+	for _, itemName := range sortedKeys(fixture.InsertNarratives) {
+		texts = append(texts, fixture.InsertNarratives[itemName])
+	}
+	return texts
+}
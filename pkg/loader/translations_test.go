@@ -0,0 +1,118 @@
+package loader
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const translatedLevel = `{
+	"name": "translations test",
+	"language": "en",
+	"intro_narrative": {"en": "You wake up.", "de": "Du wachst auf."},
+	"rooms": [
+		{"name": "hall", "description": {"en": "a hall", "de": "ein Flur"}, "connections": [],
+			"items": [
+				{"name": "letter", "description": "a letter", "detail": {"en": "meet me at midnight", "de": "Triff mich um Mitternacht"}, "portable": true},
+				{"name": "note", "description": "a note", "portable": true}
+			]}
+	],
+	"translations": {"de": {"a letter": "ein Brief"}}
+}`
+
+func TestLoadGame_Translations(t *testing.T) {
+	level, warnings, err := LoadGameWithDiagnostics(json.RawMessage(translatedLevel), 0)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+
+	if level.Language != "en" || level.IntroNarrative != "You wake up." {
+		t.Errorf("Expected English text, got %q in %q", level.IntroNarrative, level.Language)
+	}
+	room := level.Floors[0].Rooms[0]
+	if room.Description != "a hall" || room.Items[0].Detail != "meet me at midnight" {
+		t.Errorf("Expected localized fields to hold the English text, got %q and %q", room.Description, room.Items[0].Detail)
+	}
+	expected := map[string]map[string]string{"de": {
+		"You wake up.":        "Du wachst auf.",
+		"a hall":              "ein Flur",
+		"meet me at midnight": "Triff mich um Mitternacht",
+		"a letter":            "ein Brief",
+	}}
+	if !reflect.DeepEqual(level.Translations, expected) {
+		t.Errorf("Expected translations %v, got %v", expected, level.Translations)
+	}
+
+	found := false
+	for _, warning := range warnings {
+		if strings.Contains(warning.Message, "no de translation") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a warning about the untranslated note, got %+v", warnings)
+	}
+
+	data, err := json.Marshal(ExportLevel(level))
+	if err != nil {
+		t.Fatalf("Failed to marshal exported level: %v", err)
+	}
+	reloaded, err := LoadGame(data)
+	if err != nil {
+		t.Fatalf("Failed to load exported level: %v", err)
+	}
+	if reloaded.Language != level.Language || !reflect.DeepEqual(reloaded.Translations, level.Translations) {
+		t.Error("Expected the language and translations to survive the round trip")
+	}
+}
+
+func TestLoadGame_TranslationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    string
+		path     string
+		contains string
+	}{
+		{
+			name:     "missing own language",
+			level:    `{"name": "t", "rooms": [{"name": "hall", "description": {"de": "ein Flur"}, "connections": []}]}`,
+			path:     "/rooms/0/description",
+			contains: "no text in en",
+		},
+		{
+			name:     "invalid language",
+			level:    `{"name": "t", "language": "English", "rooms": [{"name": "hall", "description": "a hall", "connections": []}]}`,
+			path:     "/language",
+			contains: "language tag",
+		},
+		{
+			name: "conflicting translations",
+			level: `{"name": "t", "rooms": [
+				{"name": "hall", "description": {"en": "a room", "de": "ein Flur"}, "connections": []},
+				{"name": "den", "description": {"en": "a room", "de": "ein Zimmer"}, "connections": []}
+			]}`,
+			path:     "/rooms/1/description/de",
+			contains: "conflicting de translations",
+		},
+		{
+			name:     "translation into the own language",
+			level:    `{"name": "t", "rooms": [{"name": "hall", "description": "a hall", "connections": []}], "translations": {"en": {"a hall": "the hall"}}}`,
+			path:     "/translations/en",
+			contains: "own language",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := ValidateLevel(json.RawMessage(tt.level))
+			errors := diagnostics.Errors()
+			if len(errors) == 0 {
+				t.Fatal("Expected an error")
+			}
+			if errors[0].Path != tt.path || !strings.Contains(errors[0].Message, tt.contains) {
+				t.Errorf("Expected an error at %s containing %q, got %s: %s", tt.path, tt.contains, errors[0].Path, errors[0].Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package loader
+
+import (
+	"strings"
+
+	"adventure-engine/pkg/world"
+)
+
+// collectWarnings finds content that loads fine but is likely a mistake in the level design.
+func collectWarnings(level *world.Level, paths *levelPaths) Diagnostics {
+	var diagnostics Diagnostics
+
+	// Doors must be referenced by a room connection for the player to find them
+	connectedDoors := make(map[string]bool)
+	for _, floor := range level.Floors {
+		for _, room := range floor.Rooms {
+			for _, conn := range room.Connections {
+				connectedDoors[conn.DoorName] = true
+			}
+		}
+	}
+	for _, door := range sortedDoors(level) {
+		if !connectedDoors[door.Name] {
+			diagnostics.addWarning(paths.doors[door.Name], "door %s is not referenced by any room connection", door.Name)
+		}
+	}
+
+	// A travel node needs another node on its network to travel to
+	networkSizes := make(map[string]int)
+	for _, room := range level.TravelNodes() {
+		networkSizes[room.TravelNode.Network]++
+	}
+	for _, room := range level.TravelNodes() {
+		if node := room.TravelNode; networkSizes[node.Network] == 1 {
+			diagnostics.addWarning(paths.travelNodes[node.Name], "travel node %s has no other node on its network to travel to", node.Name)
+		}
+	}
+
+	// Enemies only appear when a trigger sends the player into combat with them, or when they
+	// wander upon a resting player
+	triggeredEnemies := make(map[string]bool)
+	for _, trigger := range level.Triggers {
+		if trigger.EffectType == world.EffectEnterCombat {
+			triggeredEnemies[trigger.Effect.EnemyName] = true
+		}
+	}
+	for _, enemy := range level.Enemies {
+		if !triggeredEnemies[enemy.Name] && !enemy.Wanders {
+			diagnostics.addWarning(paths.enemies[enemy.Name], "enemy %s has no room or trigger and will never be encountered", enemy.Name)
+		}
+	}
+
+	// Items should have a purpose
+	usedItems := collectUsedItems(level)
+	items := collectLevelItems(level)
+	for _, name := range sortedKeys(items) {
+		item := items[name]
+		if item.IsKey() {
+			if !usedItems[name] {
+				diagnostics.addWarning(paths.items[name], "key %s does not open any lock", name)
+			}
+			continue
+		}
+		if isUnusedItem(item, usedItems) {
+			diagnostics.addWarning(paths.items[name], "item %s has no use", name)
+		}
+	}
+
+	// An alias that is the name of another item or door in the same room always refers to that one
+	for _, floor := range level.Floors {
+		for _, room := range floor.Rooms {
+			names := make(map[string]bool)
+			var roomItems []*world.Item
+			for _, item := range room.Items {
+				walkItem(item, func(item *world.Item) {
+					names[strings.ToLower(item.Name)] = true
+					roomItems = append(roomItems, item)
+				})
+			}
+			for _, conn := range room.Connections {
+				names[strings.ToLower(conn.DoorName)] = true
+			}
+			for _, item := range roomItems {
+				for _, alias := range item.Aliases {
+					if names[strings.ToLower(alias)] && !strings.EqualFold(alias, item.Name) {
+						diagnostics.addWarning(paths.items[item.Name], "alias %s of item %s is the name of something else in room %s", alias, item.Name, room.Name)
+					}
+				}
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+// collectUsedItems returns the names of items that are required by a lock,
+// fixture, combination or trigger.
+func collectUsedItems(level *world.Level) map[string]bool {
+	used := make(map[string]bool)
+	for _, door := range level.Doors {
+		if door.HasKeyLock() {
+			used[door.Lock.KeyName] = true
+		}
+	}
+	for _, item := range collectLevelItems(level) {
+		if item.IsContainer() && item.Container.HasKeyLock() {
+			used[item.Container.Locked.KeyName] = true
+		}
+		if item.IsFixture() {
+			for requiredItem := range item.Fixture.RequiredItems {
+				used[requiredItem] = true
+			}
+		}
+	}
+	for _, comboItem := range level.ComboItems {
+		for _, inputName := range comboItem.InputNames() {
+			used[inputName] = true
+		}
+		for _, teacher := range comboItem.TaughtBy {
+			used[teacher] = true
+		}
+	}
+	for _, trigger := range level.Triggers {
+		if trigger.Event.Event == world.EventItemTaken {
+			used[trigger.Event.ItemName] = true
+		}
+	}
+	return used
+}
+
+// isUnusedItem returns true for a portable item that does nothing on its own,
+// carries no text for the player to read and is required by nothing.
+func isUnusedItem(item *world.Item, usedItems map[string]bool) bool {
+	if !item.IsPortable() || usedItems[item.Name] {
+		return false
+	}
+	if item.IsWeapon() || item.IsAmmoBox() || item.IsHealthItem() || item.IsAirSupply() {
+		return false
+	}
+	return item.Detail == "" && !item.Secret
+}
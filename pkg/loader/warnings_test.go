@@ -0,0 +1,82 @@
+package loader
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLoadGameWithDiagnostics_Warnings(t *testing.T) {
+	jsonData := json.RawMessage(`{
+		"name": "warnings test",
+		"win_condition": {"event": "room_entered", "room_name": "hall"},
+		"rooms": [
+			{
+				"name": "hall",
+				"description": "a hall",
+				"connections": [{"door_name": "closet door"}],
+				"items": [
+					{"name": "pebble", "description": "a pebble", "portable": true},
+					{"name": "letter", "description": "a letter", "detail": "meet me at midnight", "portable": true},
+					{"name": "spare key", "description": "a key", "portable": true, "key": true}
+				]
+			},
+			{
+				"name": "closet",
+				"description": "a closet",
+				"connections": [{"door_name": "closet door"}]
+			}
+		],
+		"doors": [
+			{"name": "closet door", "room_a": "hall", "room_b": "closet", "locked": true, "required_key_name": "closet key"},
+			{"name": "painted door", "room_a": "hall", "room_b": "closet"}
+		],
+		"enemies": [{"name": "ghost", "description": "a ghost", "hp": 1}]
+	}`)
+
+	level, warnings, err := LoadGameWithDiagnostics(jsonData, 0)
+	if err != nil {
+		t.Fatalf("Failed to load game: %v", err)
+	}
+	if level == nil {
+		t.Fatal("Expected level to be returned")
+	}
+
+	expected := map[string]bool{
+		"/rooms/0/items/0": false, // pebble has no use
+		"/rooms/0/items/2": false, // spare key opens nothing
+		"/doors/1":         false, // painted door is not connected
+		"/enemies/0":       false, // ghost has no trigger
+		"/rooms/1":         false, // closet is optional and locked away
+	}
+	for _, warning := range warnings {
+		if warning.Severity != SeverityWarning {
+			t.Errorf("Expected only warnings, got %s: %s", warning.Severity, warning.Message)
+		}
+		if _, ok := expected[warning.Path]; ok {
+			expected[warning.Path] = true
+		}
+	}
+	for path, found := range expected {
+		if !found {
+			t.Errorf("Expected warning at %s, got: %+v", path, warnings)
+		}
+	}
+	for _, warning := range warnings {
+		if warning.Path == "/rooms/0/items/1" {
+			t.Errorf("Expected no warning for readable letter, got: %s", warning.Message)
+		}
+	}
+}
+
+func TestLoadGameWithDiagnostics_Error(t *testing.T) {
+	level, diagnostics, err := LoadGameWithDiagnostics(json.RawMessage(`{"name": "no rooms"}`), 0)
+	if err == nil {
+		t.Fatal("Expected error for level without rooms")
+	}
+	if level != nil {
+		t.Error("Expected nil level on error")
+	}
+	if !diagnostics.HasErrors() {
+		t.Error("Expected error diagnostics on error")
+	}
+}
@@ -0,0 +1,243 @@
+package world
+
+import "maps"
+
+// --- deep copies ---
+//
+// Clone methods return deep copies of world entities so that live game state
+// can be snapshotted and restored without aliasing the original.
+
+// Clone returns a deep copy of the item, including nested items.
+// Aliases and sound cues are never mutated during play and are shared with the copy.
+func (it *Item) Clone() *Item {
+	if it == nil {
+		return nil
+	}
+	c := *it
+	if it.Portable != nil {
+		portable := *it.Portable
+		c.Portable = &portable
+	}
+	if it.Key != nil {
+		c.Key = &Key{}
+	}
+	if it.Weapon != nil {
+		weapon := *it.Weapon
+		weapon.Ammo = it.Weapon.Ammo.Clone()
+		if it.Weapon.Thrown != nil {
+			thrown := *it.Weapon.Thrown
+			weapon.Thrown = &thrown
+		}
+		if it.Weapon.Critical != nil {
+			critical := *it.Weapon.Critical
+			weapon.Critical = &critical
+		}
+		c.Weapon = &weapon
+	}
+	if it.Armor != nil {
+		armor := *it.Armor
+		c.Armor = &armor
+	}
+	if it.Container != nil {
+		c.Container = &Container{
+			Contains: it.Container.Contains.Clone(),
+			Searched: it.Container.Searched,
+			Locked:   it.Container.Locked.Clone(),
+		}
+	}
+	if it.Concealer != nil {
+		c.Concealer = &Concealer{
+			Hidden:    it.Concealer.Hidden.Clone(),
+			Uncovered: it.Concealer.Uncovered,
+		}
+	}
+	if it.Moveable != nil {
+		moveable := *it.Moveable
+		moveable.Reveals = it.Moveable.Reveals.Clone()
+		c.Moveable = &moveable
+	}
+	if it.AmmoBox != nil {
+		c.AmmoBox = &AmmoBox{
+			AmmoType: it.AmmoBox.AmmoType,
+			Ammo:     it.AmmoBox.Ammo.Clone(),
+		}
+	}
+	if it.HealthItem != nil {
+		healthItem := *it.HealthItem
+		c.HealthItem = &healthItem
+	}
+	if it.AirSupply != nil {
+		c.AirSupply = &AirSupply{}
+	}
+	if it.CustomComponents != nil {
+		c.CustomComponents = maps.Clone(it.CustomComponents)
+	}
+	if it.Charges != nil {
+		charges := *it.Charges
+		c.Charges = &charges
+	}
+	if it.Trap != nil {
+		trap := *it.Trap
+		c.Trap = &trap
+	}
+	if it.Durability != nil {
+		c.Durability = &Durability{
+			Max:   it.Durability.Max,
+			Used:  it.Durability.Used,
+			Scrap: it.Durability.Scrap.Clone(),
+		}
+	}
+	if it.Fixture != nil {
+		c.Fixture = &Fixture{
+			RequiredItems:       maps.Clone(it.Fixture.RequiredItems),
+			Stages:              it.Fixture.Stages,
+			InsertNarratives:    it.Fixture.InsertNarratives,
+			RevealMissing:       it.Fixture.RevealMissing,
+			OnComplete:          it.Fixture.OnComplete,
+			Produces:            it.Fixture.Produces.Clone(),
+			CompletionNarrative: it.Fixture.CompletionNarrative,
+		}
+	}
+	return &c
+}
+
+// Clone returns a copy of the ammo.
+func (a *Ammo) Clone() *Ammo {
+	if a == nil {
+		return nil
+	}
+	c := *a
+	return &c
+}
+
+// Clone returns a copy of the lock.
+func (l *Lock) Clone() *Lock {
+	if l == nil {
+		return nil
+	}
+	c := *l
+	return &c
+}
+
+// Clone returns a copy of the latch.
+func (l *Latch) Clone() *Latch {
+	if l == nil {
+		return nil
+	}
+	c := *l
+	return &c
+}
+
+// Clone returns a deep copy of the door.
+func (d *Door) Clone() *Door {
+	c := *d
+	c.Lock = d.Lock.Clone()
+	c.Latch = d.Latch.Clone()
+	return &c
+}
+
+// Clone returns a deep copy of the room, including its items.
+// Sound cues, sub-locations and conditional descriptions are never mutated during play and are
+// shared with the copy.
+func (r *Room) Clone() *Room {
+	c := *r
+	c.Connections = make([]*Connection, len(r.Connections))
+	for i, conn := range r.Connections {
+		connCopy := *conn
+		c.Connections[i] = &connCopy
+	}
+	c.Items = cloneItems(r.Items)
+	if r.TravelNode != nil {
+		node := *r.TravelNode
+		c.TravelNode = &node
+	}
+	if r.Position != nil {
+		position := *r.Position
+		c.Position = &position
+	}
+	return &c
+}
+
+// Clone returns a deep copy of the floor, including its rooms.
+func (f *Floor) Clone() *Floor {
+	c := *f
+	c.Rooms = make([]*Room, len(f.Rooms))
+	for i, room := range f.Rooms {
+		c.Rooms[i] = room.Clone()
+	}
+	return &c
+}
+
+// Clone returns a copy of the enemy, along with what it carries. Its bribes are never changed
+// during play and are shared with the copy.
+func (e *Enemy) Clone() *Enemy {
+	c := *e
+	c.Carries = e.Carries.Clone()
+	return &c
+}
+
+// Clone returns a deep copy of the player.
+func (p *Player) Clone() *Player {
+	return &Player{
+		Inventory:   cloneItems(p.Inventory),
+		Health:      p.Health,
+		Ammo:        maps.Clone(p.Ammo),
+		BreathHeld:  p.BreathHeld,
+		SavePoint:   p.SavePoint,
+		PoisonedBy:  p.PoisonedBy,
+		PoisonTurns: p.PoisonTurns,
+	}
+}
+
+// Clone returns a deep copy of the level.
+// Scoring rules, objectives and translations are never mutated during play and are shared with the copy.
+func (l *Level) Clone() *Level {
+	c := *l
+	c.Floors = make([]*Floor, len(l.Floors))
+	for i, floor := range l.Floors {
+		c.Floors[i] = floor.Clone()
+	}
+	c.Doors = make([]*Door, len(l.Doors))
+	for i, door := range l.Doors {
+		c.Doors[i] = door.Clone()
+	}
+	c.Enemies = make([]*Enemy, len(l.Enemies))
+	for i, enemy := range l.Enemies {
+		c.Enemies[i] = enemy.Clone()
+	}
+	c.Triggers = make([]*Trigger, len(l.Triggers))
+	for i, trigger := range l.Triggers {
+		triggerCopy := *trigger
+		c.Triggers[i] = &triggerCopy
+	}
+	if l.WinCondition != nil {
+		winCondition := *l.WinCondition
+		c.WinCondition = &winCondition
+	}
+	c.ComboItems = make([]*ComboItem, len(l.ComboItems))
+	for i, comboItem := range l.ComboItems {
+		c.ComboItems[i] = &ComboItem{
+			InputItemAName: comboItem.InputItemAName,
+			InputItemBName: comboItem.InputItemBName,
+			MoreInputNames: comboItem.MoreInputNames,
+			FixtureName:    comboItem.FixtureName,
+			OutputItem:     comboItem.OutputItem.Clone(),
+			Byproducts:     cloneItems(comboItem.Byproducts),
+			Known:          comboItem.Known,
+			TaughtBy:       comboItem.TaughtBy,
+		}
+	}
+	c.Reindex()
+	return &c
+}
+
+func cloneItems(items []*Item) []*Item {
+	if items == nil {
+		return nil
+	}
+	c := make([]*Item, len(items))
+	for i, item := range items {
+		c[i] = item.Clone()
+	}
+	return c
+}
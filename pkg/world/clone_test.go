@@ -0,0 +1,97 @@
+package world
+
+import "testing"
+
+func TestLevelClone_DeepCopy(t *testing.T) {
+	key := &Item{
+		BaseEntity: BaseEntity{Name: "key", Description: "a key"},
+		Portable:   &Portable{},
+		Key:        &Key{},
+	}
+	chest := &Item{
+		BaseEntity: BaseEntity{Name: "chest", Description: "a chest"},
+		Container: &Container{
+			Contains: key,
+			Locked:   &Lock{Locked: true, Code: "1234"},
+		},
+	}
+	room := &Room{
+		BaseEntity:  BaseEntity{Name: "room", Description: "a room"},
+		Connections: []*Connection{{DoorName: "door", Location: "north"}},
+		Items:       []*Item{chest},
+	}
+	door := &Door{
+		Name:  "door",
+		RoomA: "room",
+		RoomB: "other room",
+		Lock:  &Lock{Locked: true, KeyName: "key"},
+	}
+	level := &Level{
+		Name:    "level",
+		Floors:  []*Floor{{Name: "floor", Rooms: []*Room{room}}},
+		Doors:   []*Door{door},
+		Enemies: []*Enemy{{BaseEntity: BaseEntity{Name: "rat"}, HP: 2, Carries: &Item{BaseEntity: BaseEntity{Name: "cheese"}}}},
+	}
+
+	clone := level.Clone()
+
+	// Mutate the original and make sure the clone is unaffected
+	chest.Container.Locked.Locked = false
+	chest.Container.Searched = true
+	chest.Container.Contains.Name = "renamed key"
+	door.Lock.Locked = false
+	room.Items = nil
+	room.Connections[0].Location = "south"
+	level.Enemies[0].InflictDamage()
+	level.Enemies[0].Search()
+
+	clonedRoom := clone.GetRoom("floor", "room")
+	if len(clonedRoom.Items) != 1 {
+		t.Fatalf("Expected cloned room to keep 1 item, got %d", len(clonedRoom.Items))
+	}
+	clonedChest := clonedRoom.Items[0]
+	if !clonedChest.Container.IsLocked() {
+		t.Error("Expected cloned chest to still be locked")
+	}
+	if clonedChest.Container.Searched {
+		t.Error("Expected cloned chest to still be unsearched")
+	}
+	if clonedChest.Container.Contains.Name != "key" {
+		t.Errorf("Expected cloned chest to contain 'key', got '%s'", clonedChest.Container.Contains.Name)
+	}
+	if !clone.GetDoor("door").IsLocked() {
+		t.Error("Expected cloned door to still be locked")
+	}
+	if clonedRoom.Connections[0].Location != "north" {
+		t.Errorf("Expected cloned connection location 'north', got '%s'", clonedRoom.Connections[0].Location)
+	}
+	if clone.GetEnemy("rat").HP != 2 {
+		t.Errorf("Expected cloned enemy HP 2, got %d", clone.GetEnemy("rat").HP)
+	}
+	if clone.GetEnemy("rat").Carries == nil {
+		t.Error("Expected cloned enemy to still carry the cheese")
+	}
+}
+
+func TestPlayerClone_DeepCopy(t *testing.T) {
+	player := &Player{
+		Inventory: []*Item{{BaseEntity: BaseEntity{Name: "pistol"}, Weapon: &Weapon{Damage: 0.9, Ammo: &Ammo{Quantity: 1}}}},
+		Health:    HealthFine,
+		Ammo:      map[string]int{"pistol": 3},
+	}
+
+	clone := player.Clone()
+	player.InflictDamage()
+	player.Ammo["pistol"] = 0
+	player.Inventory[0].Weapon.Ammo.Quantity = 0
+
+	if clone.Health != HealthFine {
+		t.Errorf("Expected cloned health 'fine', got '%s'", clone.Health)
+	}
+	if clone.Ammo["pistol"] != 3 {
+		t.Errorf("Expected cloned ammo 3, got %d", clone.Ammo["pistol"])
+	}
+	if clone.Inventory[0].Weapon.Ammo.Quantity != 1 {
+		t.Errorf("Expected cloned weapon ammo 1, got %d", clone.Inventory[0].Weapon.Ammo.Quantity)
+	}
+}
@@ -0,0 +1,465 @@
+package world
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// --- base entity ---
+
+// BaseEntity is anything that has a name and description.
+type BaseEntity struct {
+	Name        string
+	Description string
+	ImageRef    string // URL or path of art for graphical clients, if the level has any
+}
+
+// --- components ---
+
+// Portable marks an item that can be taken into inventory.
+// Quantity is how many of the item there are in a stack, such as 3 bandages; 0 is the same as 1.
+type Portable struct {
+	Quantity int
+}
+
+// Key marks an item that can unlock a Lock.
+type Key struct{}
+
+// Ammo is ammunition for a weapon.
+type Ammo struct {
+	Quantity int
+}
+
+// Weapon gives an item the ability to enhance win probability during combat.
+// It may or may not use ammo. Weapons that use ammo may come with zero or more rounds.
+// Weapons firing the same type of ammo, such as "9mm", share the rounds the player carries.
+type Weapon struct {
+	Damage   float64 // 0.0 to 1.0
+	Ammo     *Ammo
+	AmmoType string // the weapon's own name if empty, so it shares ammo with no other weapon
+	Thrown   *Thrown
+	Critical *Critical
+}
+
+// Critical gives a weapon critical hits, rounds won that cost the enemy extra hit points, and
+// fumbles, rounds lost in which the player drops the weapon.
+type Critical struct {
+	Chance          float64 // chance that a round won is a critical hit
+	Damage          int     // extra hit points a critical hit costs the enemy
+	Narrative       string  // told on a critical hit
+	Fumble          float64 // chance that a round lost is a fumble
+	FumbleNarrative string  // told on a fumble
+}
+
+// Thrown marks a weapon that is thrown and used up in the throw, like a grenade or a molotov.
+// Area weapons also hurt the other enemies lurking in the room when they hit, and incendiary
+// ones set the room on fire.
+type Thrown struct {
+	Area       bool
+	StartsFire bool
+}
+
+// Armor is worn in a slot, such as a helmet on the head, and stops hits meant for the player.
+// Armor that wears out absorbs as many hits as its durability before it breaks.
+type Armor struct {
+	Slot  string
+	Block float64 // chance of stopping a hit, 0.0 to 1.0
+}
+
+// Durability wears a weapon or tool down with each battle round or use until it breaks.
+// A broken item is useless, unless it snaps into a piece of scrap that takes its place.
+type Durability struct {
+	Max   int
+	Used  int
+	Scrap *Item // nil if the broken item stays behind
+}
+
+// Charges let an item be used several times before it is used up, like a medkit with three
+// doses or a flashlight battery at 40%. Healing or breathing from the item, or using it on a
+// fixture, spends a charge, and the item is gone once its last charge is spent.
+type Charges struct {
+	Max  int
+	Used int
+}
+
+// Trap springs on the player the first time they take or use an item, like the needle in a
+// poisoned jewel box or the curse on a golden idol, running its effects once.
+type Trap struct {
+	On        string // TrapOnTake or TrapOnUse
+	Effects   []Effect
+	Narrative string // told when the trap springs
+	Sprung    bool
+}
+
+// When traps spring.
+const (
+	TrapOnTake = "take"
+	TrapOnUse  = "use" // using the item on a fixture, or healing or breathing from it
+)
+
+// Box of ammunition for the weapons that fire its type of ammo.
+type AmmoBox struct {
+	AmmoType string
+	Ammo     *Ammo
+}
+
+// Container can hold exactly one item and remembers whether it’s been searched.
+type Container struct {
+	Contains *Item
+	Searched bool
+	Locked   *Lock
+}
+
+// Conceal hides exactly one item until it is uncovered.
+type Concealer struct {
+	Hidden    *Item
+	Uncovered bool
+}
+
+// Moveable is furniture the player can push or pull aside once, revealing what is behind it.
+type Moveable struct {
+	Directions  []string // ways it can be moved, such as push, pull or left; any way if empty
+	Reveals     *Item    // item behind it, put in the room once it is moved
+	RevealsDoor string   // hidden door behind it, revealed once it is moved
+	Narrative   string   // told when it is moved
+	Moved       bool
+}
+
+// HealthEffect is the strength of a health item.
+type HealthEffect string
+
+const (
+	HealthBoostWeak   HealthEffect = "weak"   // increases health to next state
+	HealthBoostStrong HealthEffect = "strong" // sets health to maximum
+)
+
+// HealthItem restores health.
+type HealthItem struct {
+	HealthEffect
+}
+
+// AirSupply lets the player breathe in an airless room, like an air tank or a bubble of
+// trapped air. It is used up when the player breathes from it.
+type AirSupply struct{}
+
+// Fixture is a type of (usually non-portable) item that other items can be "used" on.
+// A fixture requires one or more items before it produces a new item.
+// Examples include altars, vending machines, a bathtub drain with a key stuck in it, etc.
+//
+// Note on Fixtures vs Containers:
+//
+// A fixture is distinct from a Container in that it cannot be searched, does not have a
+// standard lock state, and the produced item is automatically added to the player's inventory.
+// Additionally, fixtures will also support custom messages upon player interaction.
+// Finally, unlike Containers which may be empty or contain non-essential items, completion of
+// fixtures is always required to progress through the level.
+//
+// In the future we will add event handling for fixtures, but for now they only support
+// producing items.
+// Fixture is something the player uses items on until it has all its required items.
+// With Stages, the items of a stage are only accepted once the stages before it are done.
+type Fixture struct {
+	RequiredItems       map[string]bool
+	Stages              []FixtureStage    // ordered groups of the required items, empty for a single stage
+	InsertNarratives    map[string]string // item name -> told when the fixture accepts it
+	RevealMissing       MissingItemsHint
+	Produces            *Item
+	CompletionNarrative string
+	OnComplete          []Effect // run when the fixture is completed
+}
+
+// FixtureStage is a group of a fixture's required items, all needed before the next stage.
+type FixtureStage struct {
+	RequiredItems []string
+	Narrative     string // told when the stage is done
+}
+
+// MissingItemsHint is how much a fixture tells the player about the items it still needs.
+type MissingItemsHint string
+
+const (
+	MissingItemsShown  MissingItemsHint = "items" // the missing items of the current stage are named
+	MissingItemsCount  MissingItemsHint = "count" // only how many items are missing is told
+	MissingItemsHidden MissingItemsHint = "none"
+)
+
+type FixtureUseResult struct {
+	Item            *Item
+	InsertNarrative string // told for the accepted item
+	StageNarrative  string // told if the item finished a stage
+}
+
+// --- fixture component methods ---
+
+// IsComplete checks if all required items have been applied to the fixture.
+func (f *Fixture) IsComplete() bool {
+	for _, requiredItem := range f.RequiredItems {
+		if !requiredItem {
+			return false
+		}
+	}
+	return true
+}
+
+// UseItem uses an item on a fixture.
+func (f *Fixture) UseItem(itemName string) (*FixtureUseResult, error) {
+	// Assumes no duplicate items in the level, and that the engine
+	// destroys items after successful use on a fixture.
+	if _, ok := f.RequiredItems[itemName]; !ok {
+		return nil, Errorf(ErrInvalidTarget, "you can't use a %s on this", itemName)
+	}
+	stage := f.CurrentStage()
+	if f.stageOf(itemName) > stage {
+		return nil, Errorf(ErrInvalidTarget, "this isn't ready for the %s yet", itemName)
+	}
+	f.RequiredItems[itemName] = true
+	result := &FixtureUseResult{InsertNarrative: f.InsertNarratives[itemName]}
+	if stage < len(f.Stages) && f.CurrentStage() > stage {
+		result.StageNarrative = f.Stages[stage].Narrative
+	}
+	if f.IsComplete() {
+		result.Item = f.Produces
+	}
+	return result, nil
+}
+
+// CurrentStage returns the index of the first stage with items still missing,
+// or the number of stages if they are all done.
+func (f *Fixture) CurrentStage() int {
+	for i, stage := range f.Stages {
+		for _, itemName := range stage.RequiredItems {
+			if !f.RequiredItems[itemName] {
+				return i
+			}
+		}
+	}
+	return len(f.Stages)
+}
+
+// stageOf returns the index of the stage an item belongs to, 0 without stages.
+func (f *Fixture) stageOf(itemName string) int {
+	for i, stage := range f.Stages {
+		if slices.Contains(stage.RequiredItems, itemName) {
+			return i
+		}
+	}
+	return 0
+}
+
+// MissingItems returns the items the fixture still needs before it can move on: those of
+// the current stage, or all of them without stages. Sorted by name.
+func (f *Fixture) MissingItems() []string {
+	var missing []string
+	for itemName, applied := range f.RequiredItems {
+		if !applied && (len(f.Stages) == 0 || f.stageOf(itemName) == f.CurrentStage()) {
+			missing = append(missing, itemName)
+		}
+	}
+	slices.Sort(missing)
+	return missing
+}
+
+// MissingCount returns how many required items have not been applied, in all stages.
+func (f *Fixture) MissingCount() int {
+	count := 0
+	for _, applied := range f.RequiredItems {
+		if !applied {
+			count++
+		}
+	}
+	return count
+}
+
+// Lock may secure a Portal *or* a Container.
+// If KeyName is set, it’s a key lock; if Code is set, it’s a keypad.
+// A keypad with RequireLearned only takes its code once the player has read it somewhere,
+// and one with MaxAttempts jams after that many wrong codes.
+type Lock struct {
+	Locked         bool
+	KeyName        string
+	Code           string
+	RequireLearned bool
+	MaxAttempts    int  // wrong codes the keypad takes before it jams, 0 for no limit
+	Attempts       int  // wrong codes entered so far
+	Jammed         bool // the keypad no longer opens, even with the right code
+}
+
+// --- lock component methods ---
+
+func (l *Lock) IsUnlocked() bool {
+	return l == nil || !l.Locked
+}
+
+// UnlockWithKey unlocks a lock with a key.
+func (l *Lock) UnlockWithKey(keyName string) error {
+	if l.KeyName == "" {
+		return Errorf(ErrNoLock, "lock doesnt not take a key")
+	}
+	if !l.Locked {
+		return Errorf(ErrAlreadyUnlocked, "already unlocked")
+	}
+	if keyName != l.KeyName {
+		return Errorf(ErrWrongKey, "wrong key")
+	}
+	l.Locked = false
+	return nil
+}
+
+// UnlockWithCode unlocks a lock with a code.
+func (l *Lock) UnlockWithCode(code string) error {
+	if l.Code == "" {
+		return Errorf(ErrNoLock, "lock doesnt not take a code")
+	}
+	if !l.Locked {
+		return Errorf(ErrAlreadyUnlocked, "already unlocked")
+	}
+	if l.Jammed {
+		return Errorf(ErrJammed, "the keypad is jammed")
+	}
+	if code != l.Code {
+		return l.RejectCode()
+	}
+	l.Locked = false
+	return nil
+}
+
+// AttemptsLeft returns the wrong codes a keypad takes before it jams,
+// 0 if it has no limit or has jammed already.
+func (l *Lock) AttemptsLeft() int {
+	if l == nil || l.MaxAttempts == 0 || l.Jammed {
+		return 0
+	}
+	return l.MaxAttempts - l.Attempts
+}
+
+// RejectCode counts a wrong code against the keypad, jamming it once it has taken too many.
+func (l *Lock) RejectCode() error {
+	l.Attempts++
+	if l.MaxAttempts > 0 && l.Attempts >= l.MaxAttempts {
+		l.Jammed = true
+	}
+	return Errorf(ErrWrongCode, "wrong code")
+}
+
+// RevealsCode returns true if a text, such as a note's detail, spells out a code as a whole word.
+func RevealsCode(text, code string) bool {
+	if code == "" {
+		return false
+	}
+	for start := 0; ; {
+		i := strings.Index(text[start:], code)
+		if i < 0 {
+			return false
+		}
+		i += start
+		before, _ := utf8.DecodeLastRuneInString(text[:i])
+		after, _ := utf8.DecodeRuneInString(text[i+len(code):])
+		if !continuesWord(before) && !continuesWord(after) {
+			return true
+		}
+		start = i + 1
+	}
+}
+
+// continuesWord returns true if a rune next to a match makes it part of a longer word.
+func continuesWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// --- container component methods ---
+
+func (c *Container) HasKeyLock() bool  { return c.Locked != nil && c.Locked.KeyName != "" }
+func (c *Container) HasCodeLock() bool { return c.Locked != nil && c.Locked.Code != "" }
+func (c *Container) HasLock() bool     { return c.HasKeyLock() || c.HasCodeLock() }
+func (c *Container) IsLocked() bool    { return c.HasLock() && c.Locked.Locked }
+func (c *Container) IsEmpty() bool     { return c.Contains == nil }
+
+// RemoveItem removes the contained item from the container.
+func (c *Container) RemoveItem() (*Item, error) {
+	if c.IsEmpty() {
+		return nil, Errorf(ErrNotFound, "container is empty")
+	}
+	item := c.Contains
+	c.Contains = nil
+	return item, nil
+}
+
+// Search searches a container.
+func (c *Container) Search() (*Item, error) {
+	if c.Locked != nil && c.Locked.Locked {
+		return nil, Errorf(ErrLocked, "container is locked")
+	}
+
+	c.Searched = true
+	return c.Contains, nil
+}
+
+// UnlockWithKey unlocks a container with a key.
+func (c *Container) UnlockWithKey(keyName string) error {
+	if c.Locked == nil {
+		return Errorf(ErrNoLock, "container has no lock")
+	}
+	return c.Locked.UnlockWithKey(keyName)
+}
+
+// UnlockWithCode unlocks a container with a code.
+func (c *Container) UnlockWithCode(code string) error {
+	if c.Locked == nil {
+		return Errorf(ErrNoLock, "container has no lock")
+	}
+	return c.Locked.UnlockWithCode(code)
+}
+
+// --- concealer component methods ---
+
+func (c *Concealer) Reveal() (*Item, error) {
+	revealed := c.Hidden
+	c.Hidden = nil
+	c.Uncovered = true
+	return revealed, nil
+}
+
+// --- moveable component methods ---
+
+// Move moves the furniture, returning the item that was behind it, if any.
+// An empty direction moves it whichever way it goes.
+func (m *Moveable) Move(direction string) (*Item, error) {
+	if direction != "" && len(m.Directions) > 0 && !slices.Contains(m.Directions, direction) {
+		return nil, Errorf(ErrInvalidTarget, "it won't %s", direction)
+	}
+	revealed := m.Reveals
+	m.Reveals = nil
+	m.Moved = true
+	return revealed, nil
+}
+
+// --- durability component methods ---
+
+// Remaining returns the uses left before the item breaks.
+func (d *Durability) Remaining() int { return max(d.Max-d.Used, 0) }
+
+func (d *Durability) IsBroken() bool { return d.Used >= d.Max }
+
+// Wear uses the item once. Returns true if that broke it.
+func (d *Durability) Wear() bool {
+	d.Used++
+	return d.IsBroken()
+}
+
+// --- charges component methods ---
+
+// Remaining returns the charges left.
+func (c *Charges) Remaining() int { return max(c.Max-c.Used, 0) }
+
+// Spend uses a charge. Returns true if that was the last.
+func (c *Charges) Spend() bool {
+	c.Used++
+	return c.Remaining() == 0
+}
+
+// --- weapon component methods ---
+
+func (w *Weapon) UsesAmmo() bool { return w.Ammo != nil }
+func (w *Weapon) IsThrown() bool { return w.Thrown != nil }
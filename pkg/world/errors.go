@@ -0,0 +1,35 @@
+package world
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of failure when acting on the world.
+// Errors returned to the player wrap one of these, so callers can branch on the reason
+// with errors.Is while the message stays readable.
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrInvalidTarget   = errors.New("invalid target")
+	ErrLocked          = errors.New("locked")
+	ErrNoLock          = errors.New("no lock")
+	ErrWrongKey        = errors.New("wrong key")
+	ErrWrongCode       = errors.New("wrong code")
+	ErrAlreadyUnlocked = errors.New("already unlocked")
+	ErrJammed          = errors.New("jammed")
+	ErrNoAmmo          = errors.New("no ammo")
+)
+
+// Error is an error for the player that wraps the kind of failure.
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+func (e *Error) Unwrap() error { return e.Kind }
+
+// Errorf returns an Error of a kind with a formatted message.
+func Errorf(kind error, format string, args ...any) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
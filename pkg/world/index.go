@@ -0,0 +1,79 @@
+package world
+
+// --- lookups by name ---
+//
+// Levels keep maps from names to their floors, rooms, doors and enemies, so that looking one
+// up doesn't scan the level. The loader builds them when it loads a level and Clone builds
+// them for the copy; levels put together by hand get theirs on the first lookup.
+
+type levelIndex struct {
+	floors  map[string]*Floor
+	rooms   map[string]indexedRoom
+	doors   map[string]*Door
+	enemies map[string]*Enemy
+}
+
+type indexedRoom struct {
+	floor *Floor
+	room  *Room
+}
+
+// Reindex rebuilds the level's lookups by name. Lookups find floors, rooms, doors and enemies
+// added since the last index on their own, but anything removed or renamed is only forgotten
+// once the level is reindexed.
+//
+// Lookups on an indexed level don't change it, so levels shared between goroutines must be
+// indexed before they are shared.
+func (l *Level) Reindex() {
+	index := &levelIndex{
+		floors:  make(map[string]*Floor, len(l.Floors)),
+		rooms:   make(map[string]indexedRoom),
+		doors:   make(map[string]*Door, len(l.Doors)),
+		enemies: make(map[string]*Enemy, len(l.Enemies)),
+	}
+	// The first of several entities with the same name wins, as it would in a scan
+	for _, floor := range l.Floors {
+		if _, ok := index.floors[floor.Name]; !ok {
+			index.floors[floor.Name] = floor
+		}
+		for _, room := range floor.Rooms {
+			if _, ok := index.rooms[room.Name]; !ok {
+				index.rooms[room.Name] = indexedRoom{floor: floor, room: room}
+			}
+		}
+	}
+	for _, door := range l.Doors {
+		if _, ok := index.doors[door.Name]; !ok {
+			index.doors[door.Name] = door
+		}
+	}
+	for _, enemy := range l.Enemies {
+		if _, ok := index.enemies[enemy.Name]; !ok {
+			index.enemies[enemy.Name] = enemy
+		}
+	}
+	l.index = index
+}
+
+// lookups returns the level's lookups by name, indexing the level if it has not been yet.
+func (l *Level) lookups() *levelIndex {
+	if l.index == nil {
+		l.Reindex()
+	}
+	return l.index
+}
+
+// FindRoom returns a room by name along with its floor, or nil if the level has no such room.
+func (l *Level) FindRoom(name string) (*Floor, *Room) {
+	if entry, ok := l.lookups().rooms[name]; ok && entry.room.Name == name {
+		return entry.floor, entry.room
+	}
+	for _, floor := range l.Floors {
+		for _, room := range floor.Rooms {
+			if room.Name == name {
+				return floor, room
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,81 @@
+package world
+
+import (
+	"fmt"
+	"testing"
+)
+
+// corridorLevel returns a level of rooms in a row going north, each door leading on to the next room.
+func corridorLevel(rooms int) *Level {
+	floor := &Floor{Name: "ground"}
+	level := &Level{Floors: []*Floor{floor}}
+	for i := range rooms {
+		floor.Rooms = append(floor.Rooms, &Room{BaseEntity: BaseEntity{Name: fmt.Sprintf("room %d", i)}})
+	}
+	for i := range rooms - 1 {
+		door := &Door{Name: fmt.Sprintf("door %d", i), RoomA: floor.Rooms[i].Name, RoomB: floor.Rooms[i+1].Name}
+		level.Doors = append(level.Doors, door)
+		floor.Rooms[i].Connections = append(floor.Rooms[i].Connections, &Connection{DoorName: door.Name, Location: "ahead", Direction: DirectionNorth})
+		floor.Rooms[i+1].Connections = append(floor.Rooms[i+1].Connections, &Connection{DoorName: door.Name, Location: "back", Direction: DirectionSouth})
+		level.Enemies = append(level.Enemies, &Enemy{BaseEntity: BaseEntity{Name: fmt.Sprintf("rat %d", i)}})
+	}
+	return level
+}
+
+func TestLevelLookups(t *testing.T) {
+	level := corridorLevel(3)
+
+	if room := level.GetRoom("ground", "room 2"); room != level.Floors[0].Rooms[2] {
+		t.Errorf("Expected room 2, got %v", room)
+	}
+	if floor, room := level.FindRoom("room 1"); floor != level.Floors[0] || room != level.Floors[0].Rooms[1] {
+		t.Errorf("Expected room 1 on the ground floor, got %v on %v", room, floor)
+	}
+	if _, room := level.FindRoom("attic"); room != nil {
+		t.Errorf("Expected no attic, got %v", room)
+	}
+	if door := level.GetDoor("door 1"); door != level.Doors[1] || !level.HasDoor("door 0") || level.HasDoor("door 2") {
+		t.Error("Expected the level to have doors 0 and 1 only")
+	}
+	if direction := level.DoorDirection(level.Doors[0]); direction != DirectionNorth {
+		t.Errorf("Expected door 0 to lead north, got %q", direction)
+	}
+
+	// Rooms added after the level was indexed are found all the same
+	attic := &Room{BaseEntity: BaseEntity{Name: "attic"}}
+	level.Floors[0].Rooms = append(level.Floors[0].Rooms, attic)
+	if level.GetRoom("ground", "attic") != attic {
+		t.Error("Expected to find a room added after indexing")
+	}
+
+	// Removed doors are forgotten once the level is reindexed
+	level.Doors = level.Doors[:1]
+	level.Reindex()
+	if level.HasDoor("door 1") {
+		t.Error("Expected a removed door to be gone after reindexing")
+	}
+
+	// Copies look up their own rooms
+	clone := level.Clone()
+	if clone.GetRoom("ground", "room 1") != clone.Floors[0].Rooms[1] || clone.GetEnemy("rat 0") != clone.Enemies[0] {
+		t.Error("Expected the copy's lookups to find the copy's rooms and enemies")
+	}
+}
+
+func BenchmarkLevelLookups(b *testing.B) {
+	for _, rooms := range []int{10, 1000, 10000} {
+		level := corridorLevel(rooms)
+		last := level.Floors[0].Rooms[rooms-1].Name
+		b.Run(fmt.Sprintf("GetRoom/%d rooms", rooms), func(b *testing.B) {
+			for b.Loop() {
+				level.GetRoom("ground", last)
+			}
+		})
+		b.Run(fmt.Sprintf("DoorDirection/%d rooms", rooms), func(b *testing.B) {
+			door := level.Doors[len(level.Doors)-1]
+			for b.Loop() {
+				level.DoorDirection(door)
+			}
+		})
+	}
+}
@@ -0,0 +1,1018 @@
+// Package world holds the game world a level is made of: floors, rooms, doors, items with
+// their components, enemies, triggers and the player. The engine changes the world as it is
+// played; Clone methods copy it for snapshots.
+package world
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// --- entities ---
+
+// Item is anything that can exist in a room.
+type Item struct {
+	BaseEntity
+	// ID tells the item apart from others with the same name. It is unique in the level, and is
+	// the item's name unless another item shares it. Empty for items the engine makes during
+	// play, which are found by name.
+	ID          string
+	Location    string
+	SubLocation string // the sub-location of its room the item is at, empty for none
+	Detail      string
+	Secret      bool     // true if finding this item counts as discovering a secret
+	QuestItem   bool     // true if the item can't be given away, used up in crafting or dropped, so players can't lose it
+	Aliases     []string // other names the player can refer to the item by
+
+	// SoundCues holds the sounds played by actions done to or with the item, keyed by one of ItemSoundEvents
+	SoundCues SoundCues
+
+	// Optional capabilities (nil if absent)
+	Portable   *Portable
+	Key        *Key
+	Weapon     *Weapon
+	Armor      *Armor
+	Container  *Container
+	Concealer  *Concealer
+	AmmoBox    *AmmoBox
+	HealthItem *HealthItem
+	AirSupply  *AirSupply
+	Fixture    *Fixture
+	Durability *Durability
+	Charges    *Charges
+	Moveable   *Moveable
+	Trap       *Trap
+
+	// CustomComponents holds components added by engine plugins, by name. The engine carries them
+	// without looking inside.
+	CustomComponents map[string]json.RawMessage
+}
+
+// Latch locks a door from one side only. Players can latch a door from the latch's side,
+// or from either side if the latch is two-way.
+type Latch struct {
+	Locked     bool
+	LockedFrom string // name of the room the latch is on
+	TwoWay     bool   // true if the door has a latch on both sides
+}
+
+// Door connects two rooms; it may be locked.
+type Door struct {
+	Name      string
+	RoomA     string
+	RoomB     string
+	Lock      *Lock
+	Stairwell bool // true if the door is a stairwell (connects floors)
+	Barred    bool // true if the door is bars or a grate that can be seen through, rather than solid
+	Latch     *Latch
+	Aliases   []string // other names the player can refer to the door by
+	Hidden    bool     // true for a secret passage the player cannot see or use until it is revealed
+	OneWay    bool     // true if the door can only be gone through from RoomA to RoomB, like a ledge to drop from
+	Traversed bool
+	Tried     bool
+}
+
+// Connection represents a door as seen from a specific room.
+// The location and direction are relative to the room from which it is observed.
+type Connection struct {
+	DoorName    string
+	Location    string
+	Direction   Direction // empty if the level doesn't place the door on the compass
+	Description string
+}
+
+// Direction is a compass direction used to lay out rooms on a map.
+type Direction string
+
+const (
+	DirectionNorth Direction = "north"
+	DirectionSouth Direction = "south"
+	DirectionEast  Direction = "east"
+	DirectionWest  Direction = "west"
+	DirectionUp    Direction = "up"
+	DirectionDown  Direction = "down"
+)
+
+// Directions lists all valid directions.
+var Directions = []Direction{DirectionNorth, DirectionSouth, DirectionEast, DirectionWest, DirectionUp, DirectionDown}
+
+// IsValid returns true if the direction is one of the defined directions.
+func (d Direction) IsValid() bool {
+	for _, direction := range Directions {
+		if d == direction {
+			return true
+		}
+	}
+	return false
+}
+
+// Opposite returns the direction pointing back the other way.
+func (d Direction) Opposite() Direction {
+	switch d {
+	case DirectionNorth:
+		return DirectionSouth
+	case DirectionSouth:
+		return DirectionNorth
+	case DirectionEast:
+		return DirectionWest
+	case DirectionWest:
+		return DirectionEast
+	case DirectionUp:
+		return DirectionDown
+	case DirectionDown:
+		return DirectionUp
+	}
+	return ""
+}
+
+// Room is a location the player can occupy.
+type Room struct {
+	BaseEntity
+	InitialDescription string
+	Connections        []*Connection
+	Items              []*Item
+	Position           *Position   // nil if the level doesn't place the room on the floor's grid
+	SavePoint          bool        // true if the room is a safe place to save, shown on the minimap
+	Visited            bool        // true if the player has entered this room
+	Ambient            string      // sound looped while the player is in the room
+	SoundCues          SoundCues   // keyed by one of RoomSoundEvents
+	TravelNode         *TravelNode // nil if the room has no fast travel point
+	Airless            bool        // true if there is no air to breathe, like a flooded tunnel
+	Burning            bool        // true once something thrown has set the room on fire
+	SubLocations       []SubLocation
+	// ConditionalDescriptions replace the room's description once the world changes.
+	// The first one whose condition holds is used.
+	ConditionalDescriptions []*ConditionalDescription
+}
+
+// SubLocation is a spot within a room that items can be at, such as a desk or a window, so
+// that items can be told apart and grouped by where they are.
+type SubLocation struct {
+	Name        string
+	Preposition string // how items relate to the spot, as in "on" the desk or "under" the window
+}
+
+// Phrase returns where an item at the sub-location is, such as "on the desk".
+func (s SubLocation) Phrase() string {
+	return s.Preposition + " the " + s.Name
+}
+
+// TravelNode is a fast travel point in a room, such as an elevator, a ladder hatch or a vent.
+// The player can travel from a powered node to any other discovered, powered node on the
+// same network. A node is discovered once the player has been in its room.
+type TravelNode struct {
+	Name        string
+	Description string
+	Network     string // nodes only connect to nodes on the same network
+	Unpowered   bool   // true until a fixture's power effect turns the node on
+	Discovered  bool   // true if the player knows of the node before visiting its room
+}
+
+// ConditionalDescription is a room description used while its condition holds.
+type ConditionalDescription struct {
+	When        DescriptionCondition
+	Description string
+}
+
+// DescriptionCondition is a state of the world. Only the fields that are set are checked,
+// and all of them must hold.
+type DescriptionCondition struct {
+	ItemTaken    string // an item the player has taken
+	EnemyKilled  string // an enemy that is dead
+	DoorUnlocked string // a door whose lock is open
+	MinTurns     int    // the number of turns taken
+}
+
+// Position is a room's cell on its floor's grid, used to draw maps.
+// X grows to the east and Y grows to the south, matching screen coordinates.
+type Position struct {
+	X int
+	Y int
+}
+
+// Step returns the neighboring position in a direction.
+// Up and down lead off the floor's grid and return the same position.
+func (p Position) Step(direction Direction) Position {
+	switch direction {
+	case DirectionNorth:
+		p.Y--
+	case DirectionSouth:
+		p.Y++
+	case DirectionEast:
+		p.X++
+	case DirectionWest:
+		p.X--
+	}
+	return p
+}
+
+// SoundEvent is something that happens to an item or room and can play a sound cue.
+// Item events are named after the action done to or with the item.
+type SoundEvent string
+
+const (
+	SoundInspect SoundEvent = "inspect"
+	SoundUncover SoundEvent = "uncover"
+	SoundUnlock  SoundEvent = "unlock" // the locked container and the key used
+	SoundSearch  SoundEvent = "search"
+	SoundTake    SoundEvent = "take"
+	SoundHeal    SoundEvent = "heal"
+	SoundBattle  SoundEvent = "battle" // the weapon fought with
+	SoundCombine SoundEvent = "combine"
+	SoundUse     SoundEvent = "use" // the item used and the fixture it is used on
+	SoundMove    SoundEvent = "move"
+	SoundEnter   SoundEvent = "enter"
+)
+
+// ItemSoundEvents lists the events items can have sound cues for.
+var ItemSoundEvents = []SoundEvent{SoundInspect, SoundUncover, SoundUnlock, SoundSearch, SoundTake, SoundHeal, SoundBattle, SoundCombine, SoundUse, SoundMove}
+
+// RoomSoundEvents lists the events rooms can have sound cues for.
+var RoomSoundEvents = []SoundEvent{SoundEnter}
+
+// SoundCues maps events to the sounds they play. Sounds are opaque to the engine and are
+// passed on to frontends, which decide how to play them.
+type SoundCues map[SoundEvent]string
+
+// ComboItem contains a combination item and the names of the required input items.
+// Recipes take two to four inputs, and may have to be made at a fixture, such as a workbench.
+type ComboItem struct {
+	InputItemAName string
+	InputItemBName string
+	MoreInputNames []string // the third and fourth inputs of bigger recipes
+	FixtureName    string   // fixture the player must be in the same room as, empty to combine anywhere
+	OutputItem     *Item
+	Byproducts     []*Item  // made alongside the output, such as an empty bottle
+	Known          bool     // discovered from the start
+	TaughtBy       []string // items that teach the recipe when inspected, such as a note
+}
+
+// MaxComboInputs is the most items a recipe can combine.
+const MaxComboInputs = 4
+
+// InputNames returns the names of all the recipe's inputs.
+func (c *ComboItem) InputNames() []string {
+	return append([]string{c.InputItemAName, c.InputItemBName}, c.MoreInputNames...)
+}
+
+// Enemy is an NPC that must be defeated to return to investigation mode.
+type Enemy struct {
+	BaseEntity
+	HP   int
+	Room string // the room the enemy lurks in, empty for enemies only met through a trigger
+
+	// How the enemy can be dealt with without killing it
+	Intimidate float64  // chance that intimidating the enemy drives it off, 0 if it can't be
+	Sneak      float64  // chance of sneaking past the enemy, 0 if it can't be
+	Bribes     []string // items that buy the enemy off
+	Wanders    bool     // true if the enemy may come upon a resting player
+
+	// Enemies with morale give up once beaten down far enough, and can then be searched for
+	// what they carry and questioned
+	Morale        int    // HP at or below which the enemy surrenders, 0 if it fights to the death
+	Surrendered   bool   // true once the enemy has given up
+	Carries       *Item  // what searching the enemy turns up once it has surrendered, if anything
+	Interrogation string // what the enemy tells the player when questioned
+}
+
+// EnemyState is how far an enemy is from being a threat.
+type EnemyState string
+
+const (
+	EnemyHostile     EnemyState = "hostile"
+	EnemySurrendered EnemyState = "surrendered"
+	EnemyDead        EnemyState = "dead"
+)
+
+// --- enemy methods ---
+
+// InflictDamage decrements the enemy's HP.
+func (e *Enemy) InflictDamage() {
+	e.HP--
+}
+
+func (e *Enemy) IsAlive() bool {
+	return e.HP > 0
+}
+
+// State returns whether the enemy is still hostile, has surrendered or is dead.
+func (e *Enemy) State() EnemyState {
+	switch {
+	case !e.IsAlive():
+		return EnemyDead
+	case e.Surrendered:
+		return EnemySurrendered
+	default:
+		return EnemyHostile
+	}
+}
+
+// IsHostile returns true if the enemy is alive and has not surrendered.
+func (e *Enemy) IsHostile() bool {
+	return e.State() == EnemyHostile
+}
+
+// ShouldSurrender returns true if a hostile enemy has been beaten down to its morale.
+func (e *Enemy) ShouldSurrender() bool {
+	return e.IsHostile() && e.Morale > 0 && e.HP <= e.Morale
+}
+
+// Search hands over what a surrendered enemy carries, which it then no longer has. Returns nil
+// if it carries nothing.
+func (e *Enemy) Search() *Item {
+	item := e.Carries
+	e.Carries = nil
+	return item
+}
+
+// --- room methods ---
+
+// GetSubLocation returns the room's sub-location with the given name, and false if it has none.
+func (r *Room) GetSubLocation(name string) (SubLocation, bool) {
+	for _, subLocation := range r.SubLocations {
+		if subLocation.Name == name {
+			return subLocation, true
+		}
+	}
+	return SubLocation{}, false
+}
+
+// GetConnection returns a connection from the room by door name.
+func (r *Room) GetConnection(doorName string) (*Connection, error) {
+	for _, conn := range r.Connections {
+		if conn.DoorName == doorName {
+			return conn, nil
+		}
+	}
+	return nil, Errorf(ErrNotFound, "no door named %s in this room", doorName)
+}
+
+// GetItem returns an item from the room, by name or ID.
+func (r *Room) GetItem(name string) (*Item, error) {
+	for _, item := range r.Items {
+		if item.Is(name) {
+			return item, nil
+		}
+	}
+	return nil, Errorf(ErrNotFound, "you don't see a %s here", name)
+}
+
+// RemoveItem removes an item from the room, by name or ID.
+// Used when the player picks up an item.
+func (r *Room) RemoveItem(name string) (*Item, error) {
+	items := r.Items
+	for i, it := range items {
+		if it.Is(name) {
+			copy(items[i:], items[i+1:])
+			r.Items = items[:len(items)-1]
+			return it, nil
+		}
+	}
+	return nil, Errorf(ErrNotFound, "you don't see a %s here", name)
+}
+
+// --- item methods ---
+
+// Is returns true if ref is the item's name or ID.
+func (it *Item) Is(ref string) bool {
+	return ref == it.Name || (it.ID != "" && ref == it.ID)
+}
+
+// Ref returns what to look the item up by, telling it apart from items with the same name:
+// its ID, or its name if it has none.
+func (it *Item) Ref() string {
+	if it.ID != "" {
+		return it.ID
+	}
+	return it.Name
+}
+
+func (it *Item) IsPortable() bool   { return it.Portable != nil }
+func (it *Item) IsKey() bool        { return it.Key != nil }
+func (it *Item) IsWeapon() bool     { return it.Weapon != nil }
+func (it *Item) IsArmor() bool      { return it.Armor != nil }
+func (it *Item) IsContainer() bool  { return it.Container != nil }
+func (it *Item) IsConcealer() bool  { return it.Concealer != nil }
+func (it *Item) IsAmmoBox() bool    { return it.AmmoBox != nil }
+func (it *Item) IsHealthItem() bool { return it.HealthItem != nil }
+func (it *Item) IsAirSupply() bool  { return it.AirSupply != nil }
+func (it *Item) IsFixture() bool    { return it.Fixture != nil }
+func (it *Item) IsMoveable() bool   { return it.Moveable != nil }
+func (it *Item) IsBroken() bool     { return it.Durability != nil && it.Durability.IsBroken() }
+
+// AmmoType returns the type of ammo a weapon fires or an ammo box holds, such as "9mm".
+// Weapons that don't name a type fire ammo of their own, named after them.
+func (it *Item) AmmoType() string {
+	switch {
+	case it.IsWeapon() && it.Weapon.AmmoType != "":
+		return it.Weapon.AmmoType
+	case it.IsWeapon():
+		return it.Name
+	case it.IsAmmoBox():
+		return it.AmmoBox.AmmoType
+	}
+	return ""
+}
+
+// IsStackable reports whether the item can be stacked with others like it: portable items
+// that don't wear out, have no charges or trap and are not weapons, as each of those has its
+// own ammo, wear, charges or trap. Thrown weapons are used up whole, so they stack.
+func (it *Item) IsStackable() bool {
+	return it.IsPortable() && (!it.IsWeapon() || it.Weapon.IsThrown()) && it.Durability == nil && it.Charges == nil && it.Trap == nil
+}
+
+// Count returns how many of the item there are, 1 unless it is a stack.
+func (it *Item) Count() int {
+	if it.Portable == nil || it.Portable.Quantity < 1 {
+		return 1
+	}
+	return it.Portable.Quantity
+}
+
+// SplitStack takes some of the items off a stack and returns them as a stack of their own,
+// leaving the rest. Taking the whole stack returns the item itself.
+func (it *Item) SplitStack(quantity int) (*Item, error) {
+	count := it.Count()
+	if quantity < 1 || quantity > count {
+		return nil, Errorf(ErrInvalidTarget, "there are only %d of the %s", count, it.Name)
+	}
+	if quantity == count {
+		return it, nil
+	}
+	split := it.Clone()
+	split.Portable.Quantity = quantity
+	it.Portable.Quantity = count - quantity
+	return split, nil
+}
+
+// Validate a newly created item.
+func (it *Item) ValidateInitialState() error {
+	if it.IsKey() {
+		if !it.IsPortable() || it.IsContainer() || it.IsConcealer() || it.IsWeapon() {
+			return errors.New("invalid key")
+		}
+	}
+	if it.IsWeapon() {
+		if !it.IsPortable() || it.IsContainer() || it.IsConcealer() || it.IsKey() {
+			return errors.New("invalid weapon")
+		}
+		if it.Weapon.IsThrown() && (it.Weapon.UsesAmmo() || it.Durability != nil) {
+			return errors.New("thrown weapons are used up, they cannot use ammo or wear out")
+		}
+	}
+	if it.IsArmor() {
+		if !it.IsPortable() || it.IsKey() || it.IsWeapon() || it.Armor.Slot == "" {
+			return errors.New("invalid armor")
+		}
+	}
+	if it.IsContainer() {
+		if it.IsPortable() || it.IsConcealer() || it.IsKey() || it.IsWeapon() {
+			return errors.New("invalid container")
+		}
+		if it.Container.HasLock() && !it.Container.IsLocked() {
+			return errors.New("container with lock must start in a locked state")
+		}
+	}
+	if it.IsConcealer() {
+		if it.IsPortable() || it.IsContainer() || it.IsKey() || it.IsWeapon() {
+			return errors.New("invalid concealer")
+		}
+	}
+	if it.IsFixture() {
+		if it.IsPortable() || it.IsContainer() || it.IsConcealer() || it.IsKey() || it.IsWeapon() {
+			return errors.New("invalid fixture")
+		}
+	}
+	if it.IsMoveable() {
+		if it.IsPortable() || it.IsConcealer() {
+			return errors.New("invalid moveable")
+		}
+		if it.Moveable.Reveals != nil && it.Moveable.Reveals.IsMoveable() {
+			return errors.New("moveable furniture cannot be nested")
+		}
+	}
+	if it.Durability != nil {
+		if !it.IsPortable() || it.IsKey() {
+			return errors.New("only portable items that are not keys can wear out")
+		}
+		if it.Durability.Scrap != nil && !it.Durability.Scrap.IsPortable() {
+			return errors.New("scrap must be portable")
+		}
+	}
+	return nil
+}
+
+// --- door methods ---
+
+func (d *Door) HasKeyLock() bool                { return d.Lock != nil && d.Lock.KeyName != "" }
+func (d *Door) HasCodeLock() bool               { return d.Lock != nil && d.Lock.Code != "" }
+func (d *Door) HasLock() bool                   { return d.HasKeyLock() || d.HasCodeLock() }
+func (d *Door) IsLocked() bool                  { return d.HasLock() && d.Lock.Locked }
+func (d *Door) IsLatched() bool                 { return d.Latch != nil && d.Latch.Locked }
+func (d *Door) CanUnlatch(roomName string) bool { return d.Latch.LockedFrom == roomName }
+func (d *Door) Unlatch()                        { d.Latch.Locked = false }
+
+// CanTraverseFrom returns true if the door can be gone through from a room.
+func (d *Door) CanTraverseFrom(roomName string) bool {
+	return !d.OneWay || d.RoomA == roomName
+}
+
+// CanLatch returns true if the door can be latched from a room.
+func (d *Door) CanLatch(roomName string) bool {
+	return d.Latch != nil && !d.Latch.Locked && (d.Latch.TwoWay || d.Latch.LockedFrom == roomName)
+}
+
+// LatchFrom latches the door from a room.
+func (d *Door) LatchFrom(roomName string) {
+	d.Latch.Locked = true
+	d.Latch.LockedFrom = roomName
+}
+
+// UnlockWithKey unlocks a door with a key.
+func (d *Door) UnlockWithKey(keyName string) error {
+	if d.Lock == nil {
+		return Errorf(ErrNoLock, "the %s has no lock", d.Name)
+	}
+	return d.Lock.UnlockWithKey(keyName)
+}
+
+// UnlockWithCode unlocks a door with a code.
+func (d *Door) UnlockWithCode(code string) error {
+	if d.Lock == nil {
+		return Errorf(ErrNoLock, "the %s has no lock", d.Name)
+	}
+	return d.Lock.UnlockWithCode(code)
+}
+
+// --- player ---
+
+type HealthState string
+
+const (
+	HealthFine HealthState = "fine"
+	HealthHurt HealthState = "hurt"
+	HealthCrit HealthState = "critical"
+	HealthDead HealthState = "dead"
+)
+
+func (p *Player) IsAlive() bool {
+	return p.Health != HealthDead
+}
+
+type Player struct {
+	Inventory   []*Item
+	Health      HealthState
+	Ammo        map[string]int // ammo type -> rounds, shared by the weapons firing that type
+	BreathHeld  int            // actions the player has ended in airless rooms since they last breathed
+	SavePoint   string         // name of the last save point room the player entered, where they respawn
+	PoisonedBy  string         // the trapped item that poisoned the player, empty if they are not poisoned
+	PoisonTurns int            // actions the player has ended since the poison last hurt them
+}
+
+// GetItem returns an item from the player's inventory, by name or ID.
+func (p *Player) GetItem(name string) (*Item, error) {
+	for _, item := range p.Inventory {
+		if item.Is(name) {
+			return item, nil
+		}
+	}
+	return nil, Errorf(ErrNotFound, "you don't have a %s in your inventory", name)
+}
+
+// AddItem adds an item to the player's inventory, stacking it with the items like it
+// the player already has.
+func (p *Player) AddItem(item *Item) {
+	if held, err := p.GetItem(item.Name); err == nil && held.IsStackable() && item.IsStackable() {
+		held.Portable.Quantity = held.Count() + item.Count()
+		return
+	}
+	p.Inventory = append(p.Inventory, item)
+}
+
+// ConsumeItem uses up one of an item in the player's inventory, such as a bandage from a
+// stack of them, removing the item once the last one is gone.
+func (p *Player) ConsumeItem(name string) (*Item, error) {
+	item, err := p.GetItem(name)
+	if err != nil {
+		return nil, err
+	}
+	if item.Count() > 1 {
+		item.Portable.Quantity--
+		return item, nil
+	}
+	return p.RemoveItem(item.Ref())
+}
+
+// WornArmor returns the armor the player wears: in each slot, the first piece they took that
+// is not broken. Armor is worn as soon as it is carried, and a spare piece for a slot is put
+// on once the one before it breaks.
+func (p *Player) WornArmor() []*Item {
+	var worn []*Item
+	slots := make(map[string]bool)
+	for _, item := range p.Inventory {
+		if !item.IsArmor() || item.IsBroken() || slots[item.Armor.Slot] {
+			continue
+		}
+		slots[item.Armor.Slot] = true
+		worn = append(worn, item)
+	}
+	return worn
+}
+
+// RemoveItem removes an item from the player's inventory, by name or ID.
+func (p *Player) RemoveItem(name string) (*Item, error) {
+	items := p.Inventory
+	for i, it := range items {
+		if it.Is(name) {
+			copy(items[i:], items[i+1:])
+			p.Inventory = items[:len(items)-1]
+			return it, nil
+		}
+	}
+	return nil, Errorf(ErrNotFound, "you don't have a %s in your inventory", name)
+}
+
+func (p *Player) IncreaseHealth() {
+	switch p.Health {
+	case HealthHurt:
+		p.Health = HealthFine
+	case HealthCrit:
+		p.Health = HealthHurt
+	default:
+		panic("invalid health state")
+	}
+}
+
+func (p *Player) InflictDamage() {
+	switch p.Health {
+	case HealthFine:
+		p.Health = HealthHurt
+	case HealthHurt:
+		p.Health = HealthCrit
+	case HealthCrit:
+		p.Health = HealthDead
+	default:
+		panic("invalid health state")
+	}
+}
+
+// FireWeapon uses up a round of a type of ammo.
+func (p *Player) FireWeapon(ammoType string) error {
+	if p.Ammo[ammoType] == 0 {
+		return Errorf(ErrNoAmmo, "you are out of %s ammo", ammoType)
+	}
+	p.Ammo[ammoType]--
+	return nil
+}
+
+// --- events and triggers ---
+
+type EventType string
+
+const (
+	EventEnemyKilled  EventType = "enemy_killed"
+	EventPlayerKilled EventType = "player_killed"
+	EventItemTaken    EventType = "item_taken"
+	EventRoomEntered  EventType = "room_entered"
+	EventFixture      EventType = "fixture_used" // actually: fixture completed
+	EventLockJammed   EventType = "lock_jammed"
+)
+
+type Event struct {
+	Event       EventType
+	EnemyName   string
+	RoomName    string
+	ItemName    string
+	FixtureName string
+	LockName    string // the door or container whose keypad jammed
+}
+
+// Matches returns true if the other event is of the same type and concerns the same
+// room, item, fixture or enemy.
+func (e *Event) Matches(other *Event) bool {
+	if e.Event != other.Event {
+		return false
+	}
+	switch e.Event {
+	case EventRoomEntered:
+		return e.RoomName == other.RoomName
+	case EventItemTaken:
+		return e.ItemName == other.ItemName
+	case EventFixture:
+		return e.FixtureName == other.FixtureName
+	case EventEnemyKilled:
+		return e.EnemyName == other.EnemyName
+	case EventLockJammed:
+		return e.LockName == other.LockName
+	}
+	return true
+}
+
+type EffectType string
+
+const (
+	EffectEnterCombat   EffectType = "enter_combat"
+	EffectEndCombat     EffectType = "end_combat"     // drives the enemy off: any fight with it ends and it never attacks again
+	EffectUnlock        EffectType = "unlock"         // unlocks the target door or container
+	EffectRevealDoor    EffectType = "reveal_door"    // reveals the target hidden door
+	EffectCompleteLevel EffectType = "complete_level" // wins the level
+	EffectPower         EffectType = "power"          // powers the target travel node
+	EffectDamage        EffectType = "damage"         // hurts the player a step of health
+	EffectPoison        EffectType = "poison"         // poisons the player, who is hurt every few turns until healed
+)
+
+// CustomEffectPrefix starts the names of effects run by engine plugins, such as custom:flood.
+const CustomEffectPrefix = "custom:"
+
+// IsCustom returns true if the effect is run by an engine plugin rather than the engine.
+func (t EffectType) IsCustom() bool {
+	return strings.HasPrefix(string(t), CustomEffectPrefix) && len(t) > len(CustomEffectPrefix)
+}
+
+type Effect struct {
+	EffectType
+	EnemyName  string
+	TargetName string          // the door, container or travel node the effect acts on, the trapped item that hurts or poisons the player, or anything a custom effect names
+	Params     json.RawMessage // settings of a custom effect, left to the plugin that runs it
+}
+
+type Trigger struct {
+	Event
+	Effect
+}
+
+// Objective is an authored goal shown to the player once revealed.
+type Objective struct {
+	Name        string
+	Description string
+	RevealOn    *Event // nil if the objective is shown from the start
+	CompleteOn  Event
+}
+
+// --- level ---
+
+type Floor struct {
+	Name        string
+	Description string
+	Rooms       []*Room
+}
+
+type Level struct {
+	Name             string
+	Theme            string // the level's setting, such as "survival horror", used for narration
+	Floors           []*Floor
+	Doors            []*Door
+	Enemies          []*Enemy
+	Triggers         []*Trigger
+	WinCondition     *Event
+	Objectives       []*Objective
+	ComboItems       []*ComboItem
+	IntroNarrative   string
+	OutroNarrative   string
+	FailureNarrative string // told when the player dies and the level is lost
+	Scoring          *Scoring
+	Breath           int                          // actions a player can hold their breath for, 0 for DefaultBreath
+	Combat           CombatModel                  // how rounds of battle are won, empty for CombatProbabilistic
+	RestEncounter    float64                      // chance per turn rested that a wandering enemy comes upon the player, 0 for DefaultRestEncounter
+	Language         string                       // language the level's text is written in
+	Translations     map[string]map[string]string // language -> text in Language -> translated text
+	VerbAliases      map[string]string            // words in the level's vocabulary -> the command they stand for
+
+	index *levelIndex // lookups by name, see Reindex
+}
+
+// DefaultLanguage is the language of levels that don't declare one.
+const DefaultLanguage = "en"
+
+// Languages returns the languages the level's text can be read in, its own language first.
+func (l *Level) Languages() []string {
+	own := l.Language
+	if own == "" {
+		own = DefaultLanguage
+	}
+	languages := []string{own}
+	for _, language := range slices.Sorted(maps.Keys(l.Translations)) {
+		if language != own {
+			languages = append(languages, language)
+		}
+	}
+	return languages
+}
+
+// HasLanguage returns true if the level's text can be read in the language.
+func (l *Level) HasLanguage(language string) bool {
+	return slices.Contains(l.Languages(), language)
+}
+
+// Localize returns a text of the level in another language. Texts are looked up as written
+// in the level's own language, and are returned unchanged when they have no translation.
+func (l *Level) Localize(text string, language string) string {
+	if translated, ok := l.Translations[language][text]; ok {
+		return translated
+	}
+	return text
+}
+
+// --- scoring ---
+
+// BadgeCondition holds the thresholds a run must meet to earn a badge.
+// Nil thresholds are not checked.
+type BadgeCondition struct {
+	MaxTurns           *int
+	MinEnemiesDefeated *int
+	MaxDamageTaken     *int
+	MinSecretsFound    *int
+}
+
+// Badge is an authored achievement awarded when the level is completed.
+type Badge struct {
+	Name        string
+	Description string
+	Condition   BadgeCondition
+}
+
+// Scoring configures how the end-of-level score is computed.
+// Penalties are subtracted per turn / per point of damage taken,
+// bonuses are added per enemy defeated / per secret found.
+type Scoring struct {
+	BasePoints    int
+	TurnPenalty   int
+	DamagePenalty int
+	EnemyBonus    int
+	SecretBonus   int
+	Badges        []*Badge
+}
+
+// DefaultScoring returns the scoring rules used when a level doesn't define its own.
+func DefaultScoring() *Scoring {
+	return &Scoring{
+		BasePoints:    1000,
+		TurnPenalty:   5,
+		DamagePenalty: 50,
+		EnemyBonus:    100,
+		SecretBonus:   250,
+	}
+}
+
+// DefaultRestEncounter is the chance per turn rested that a wandering enemy comes upon the player,
+// in levels that don't say.
+const DefaultRestEncounter = 0.1
+
+// GetRestEncounter returns the chance per turn rested that a wandering enemy comes upon the
+// player.
+func (l *Level) GetRestEncounter() float64 {
+	if l.RestEncounter == 0 {
+		return DefaultRestEncounter
+	}
+	return l.RestEncounter
+}
+
+// CombatModel is how a level decides who wins a round of battle.
+type CombatModel string
+
+const (
+	// CombatProbabilistic wins a round with the weapon's damage as the chance.
+	CombatProbabilistic CombatModel = "probabilistic"
+	// CombatDeterministic adds up the damage of each round fought, winning a round each time the
+	// total reaches 1, so the same weapon always wins the same rounds.
+	CombatDeterministic CombatModel = "deterministic"
+	// CombatOpposed rolls for the player and the enemy, each roll weighted by the damage they
+	// deal, and the higher roll wins the round.
+	CombatOpposed CombatModel = "opposed"
+)
+
+// DefaultBreath is how many actions a player can hold their breath for in levels that don't say.
+const DefaultBreath = 5
+
+// GetBreath returns how many actions a player can hold their breath for in airless rooms.
+func (l *Level) GetBreath() int {
+	if l.Breath == 0 {
+		return DefaultBreath
+	}
+	return l.Breath
+}
+
+// GetScoring returns the level's scoring rules, falling back to the defaults.
+func (l *Level) GetScoring() *Scoring {
+	if l.Scoring == nil {
+		return DefaultScoring()
+	}
+	return l.Scoring
+}
+
+// CombineItems finds the recipe that combines exactly the input items, in any order.
+// Returns the recipe or an error if the combination is not possible.
+// The engine is responsible for checking the recipe's fixture, removing input items from
+// the player's inventory and handing out the output and byproducts.
+func (l *Level) CombineItems(inputNames ...string) (*ComboItem, error) {
+	sorted := slices.Sorted(slices.Values(inputNames))
+	for _, comboItem := range l.ComboItems {
+		if slices.Equal(slices.Sorted(slices.Values(comboItem.InputNames())), sorted) {
+			return comboItem, nil
+		}
+	}
+	return nil, Errorf(ErrInvalidTarget, "you can't combine the %s", joinNames(inputNames))
+}
+
+// joinNames joins names into a list such as "rope, hook and pole".
+func joinNames(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// DoorDirection returns the direction of the door as seen from its room A,
+// falling back to the opposite of its direction from room B.
+// Returns an empty direction if neither room gives the door a direction.
+func (l *Level) DoorDirection(door *Door) Direction {
+	if _, room := l.FindRoom(door.RoomA); room != nil {
+		if conn, err := room.GetConnection(door.Name); err == nil && conn.Direction != "" {
+			return conn.Direction
+		}
+	}
+	if _, room := l.FindRoom(door.RoomB); room != nil {
+		if conn, err := room.GetConnection(door.Name); err == nil {
+			return conn.Direction.Opposite()
+		}
+	}
+	return ""
+}
+
+// GetEnemy returns an enemy by name.
+func (e *Level) GetEnemy(name string) *Enemy {
+	if enemy, ok := e.lookups().enemies[name]; ok && enemy.Name == name {
+		return enemy
+	}
+	for _, enemy := range e.Enemies {
+		if enemy.Name == name {
+			return enemy
+		}
+	}
+	panic(fmt.Sprintf("no enemy named %s", name))
+}
+
+// GetFloor returns a floor by name.
+func (e *Level) GetFloor(name string) *Floor {
+	if floor, ok := e.lookups().floors[name]; ok && floor.Name == name {
+		return floor
+	}
+	for _, floor := range e.Floors {
+		if floor.Name == name {
+			return floor
+		}
+	}
+	panic(fmt.Sprintf("no floor named %s", name))
+}
+
+// GetRoom returns a room by name.
+func (e *Level) GetRoom(floorName string, roomName string) *Room {
+	if floor, room := e.FindRoom(roomName); room != nil && floor.Name == floorName {
+		return room
+	}
+	floor := e.GetFloor(floorName)
+	for _, room := range floor.Rooms {
+		if room.Name == roomName {
+			return room
+		}
+	}
+	panic(fmt.Sprintf("no room named %s on floor %s", roomName, floorName))
+}
+
+// GetDoor returns a door by name.
+func (e *Level) GetDoor(name string) *Door {
+	if door, ok := e.lookups().doors[name]; ok && door.Name == name {
+		return door
+	}
+	for _, door := range e.Doors {
+		if door.Name == name {
+			return door
+		}
+	}
+	panic(fmt.Sprintf("no door named %s", name))
+}
+
+// TravelNodes returns the rooms with a fast travel point, in level order.
+func (e *Level) TravelNodes() []*Room {
+	var rooms []*Room
+	for _, floor := range e.Floors {
+		for _, room := range floor.Rooms {
+			if room.TravelNode != nil {
+				rooms = append(rooms, room)
+			}
+		}
+	}
+	return rooms
+}
+
+// HasDoor returns true if the level has a door by that name.
+func (e *Level) HasDoor(name string) bool {
+	if door, ok := e.lookups().doors[name]; ok && door.Name == name {
+		return true
+	}
+	return slices.ContainsFunc(e.Doors, func(door *Door) bool { return door.Name == name })
+}
@@ -1,6 +1,9 @@
 package world
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestRoomWithNonPortableItem(t *testing.T) {
 	// Create a non-portable item
@@ -638,3 +641,29 @@ func TestLockedDoor(t *testing.T) {
 		t.Error("Expected lock to be unlocked")
 	}
 }
+
+func TestLevelLanguages(t *testing.T) {
+	level := &Level{
+		Language: "de",
+		Translations: map[string]map[string]string{
+			"fr": {"ein Flur": "un couloir"},
+			"en": {"ein Flur": "a hall"},
+		},
+	}
+
+	if languages := level.Languages(); !reflect.DeepEqual(languages, []string{"de", "en", "fr"}) {
+		t.Errorf("Expected the level's own language first, got %v", languages)
+	}
+	if !level.HasLanguage("fr") || level.HasLanguage("es") {
+		t.Error("Expected the level to be available in French only besides German and English")
+	}
+	if text := level.Localize("ein Flur", "en"); text != "a hall" {
+		t.Errorf("Expected the English translation, got %q", text)
+	}
+	if text := level.Localize("eine Tür", "en"); text != "eine Tür" {
+		t.Errorf("Expected untranslated text unchanged, got %q", text)
+	}
+	if languages := (&Level{}).Languages(); !reflect.DeepEqual(languages, []string{DefaultLanguage}) {
+		t.Errorf("Expected levels to default to %s, got %v", DefaultLanguage, languages)
+	}
+}
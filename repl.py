@@ -12,6 +12,7 @@ import cmd
 import sys
 import shlex
 import argparse
+import os
 from typing import Optional, Dict, Any, List
 from pathlib import Path
 
@@ -19,6 +20,10 @@ from pathlib import Path
 SERVER_URL = "http://localhost:8080"
 API_BASE = f"{SERVER_URL}/api/v1"
 
+# API key sent with every request when the server has authentication enabled
+API_KEY = os.environ.get("SAGA_API_KEY")
+HEADERS = {"Authorization": f"Bearer {API_KEY}"} if API_KEY else {}
+
 
 class GameClient:
     """Client for interacting with the adventure game server."""
@@ -31,7 +36,7 @@ class GameClient:
     def create_session(self, level_data: Dict[str, Any]) -> str:
         """Create a new game session with the provided level data."""
         url = f"{self.api_base}/sessions"
-        response = requests.post(url, json={"level": level_data})
+        response = requests.post(url, json={"level": level_data}, headers=HEADERS)
         response.raise_for_status()
 
         result = response.json()
@@ -49,11 +54,11 @@ class GameClient:
         url = f"{self.api_base}/sessions/{self.session_id}/{endpoint}"
 
         if method.upper() == "GET":
-            response = requests.get(url)
+            response = requests.get(url, headers=HEADERS)
         elif method.upper() == "POST":
-            response = requests.post(url, json=data or {})
+            response = requests.post(url, json=data or {}, headers=HEADERS)
         elif method.upper() == "DELETE":
-            response = requests.delete(url)
+            response = requests.delete(url, headers=HEADERS)
         else:
             raise ValueError(f"Unsupported HTTP method: {method}")
 
@@ -82,6 +87,10 @@ class GameClient:
         """Search a container."""
         return self._make_request("POST", "search", {"target_name": target_name})
 
+    def interrogate(self, enemy_name: str) -> Dict[str, Any]:
+        """Question an enemy that surrendered."""
+        return self._make_request("POST", "interrogate", {"enemy_name": enemy_name})
+
     def take(self, target_name: str) -> Dict[str, Any]:
         """Take an item."""
         return self._make_request("POST", "take", {"target_name": target_name})
@@ -90,27 +99,76 @@ class GameClient:
         """Get player inventory."""
         return self._make_request("POST", "inventory")
 
+    def status(self) -> Dict[str, Any]:
+        """Get how the player is doing."""
+        return self._make_request("POST", "status")
+
     def heal(self, health_item_name: str) -> Dict[str, Any]:
         """Use a health item."""
         return self._make_request(
             "POST", "heal", {"health_item_name": health_item_name}
         )
 
+    def wait(self, turns: int = 1) -> Dict[str, Any]:
+        """Let turns go by."""
+        return self._make_request("POST", "wait", {"turns": turns})
+
+    def rest(self, turns: int = 1) -> Dict[str, Any]:
+        """Rest to recover health."""
+        return self._make_request("POST", "rest", {"turns": turns})
+
     def traverse(self, destination: str) -> Dict[str, Any]:
         """Move to another room."""
         return self._make_request(
             "POST", "traverse", {"door_or_direction": destination}
         )
 
+    def listen(self, door: str) -> Dict[str, Any]:
+        """Listen at a door."""
+        return self._make_request("POST", "listen", {"door_or_direction": door})
+
+    def peek(self, door: str) -> Dict[str, Any]:
+        """Look through a barred door."""
+        return self._make_request("POST", "peek", {"door_or_direction": door})
+
+    def latch(self, door: str) -> Dict[str, Any]:
+        """Latch a door."""
+        return self._make_request("POST", "latch", {"door_or_direction": door})
+
     def battle(self, weapon_name: str) -> Dict[str, Any]:
         """Battle an enemy."""
         return self._make_request("POST", "battle", {"weapon_name": weapon_name})
 
-    def combine(self, item_a_name: str, item_b_name: str) -> Dict[str, Any]:
-        """Combine two items to craft something new."""
-        return self._make_request(
-            "POST", "combine", {"item_a_name": item_a_name, "item_b_name": item_b_name}
-        )
+    def intimidate(self) -> Dict[str, Any]:
+        """Try to scare off an enemy."""
+        return self._make_request("POST", "intimidate")
+
+    def bribe(self, item_name: str) -> Dict[str, Any]:
+        """Buy off an enemy with an item."""
+        return self._make_request("POST", "bribe", {"item_name": item_name})
+
+    def sneak(self) -> Dict[str, Any]:
+        """Try to slip away from an enemy."""
+        return self._make_request("POST", "sneak")
+
+    def combine(self, *item_names: str) -> Dict[str, Any]:
+        """Combine two to four items to craft something new."""
+        return self._make_request("POST", "combine", {"item_names": list(item_names)})
+
+    def move(self, item_name: str, direction: str = "") -> Dict[str, Any]:
+        """Move a piece of furniture."""
+        body = {"item_name": item_name}
+        if direction:
+            body["direction"] = direction
+        return self._make_request("POST", "move", body)
+
+    def travel(self, node_name: str) -> Dict[str, Any]:
+        """Fast travel to another node on the current node's network."""
+        return self._make_request("POST", "travel", {"node_name": node_name})
+
+    def respawn(self) -> Dict[str, Any]:
+        """Respawn at the last save point after dying."""
+        return self._make_request("POST", "respawn")
 
     def use(self, item_name: str, target_name: str) -> Dict[str, Any]:
         """Use an item on a target (like using an item on a fixture)."""
@@ -118,14 +176,38 @@ class GameClient:
             "POST", "use", {"item_name": item_name, "target_name": target_name}
         )
 
-    def context(self) -> Dict[str, Any]:
-        """Get comprehensive game context (room info + inventory)."""
-        return self._make_request("POST", "context")
+    def command(self, text: str) -> Dict[str, Any]:
+        """Run a free text command like "take the brass key from the desk"."""
+        return self._make_request("POST", "command", {"text": text})
+
+    def context(self, verbosity: str = "full") -> Dict[str, Any]:
+        """Get comprehensive game context (room info + inventory + objectives + minimap)."""
+        return self._make_request("POST", f"context?verbosity={verbosity}")
 
     def minimap(self) -> Dict[str, Any]:
-        """Get minimap data for the current floor."""
+        """Get minimap data for every floor."""
         return self._make_request("POST", "minimap")
 
+    def annotate_room(self, room_name: str, note: str) -> Dict[str, Any]:
+        """Write a note on a room on the minimap."""
+        return self._make_request(
+            "POST", "minimap/notes", {"room_name": room_name, "note": note}
+        )
+
+    def annotate_door(self, door_name: str, note: str) -> Dict[str, Any]:
+        """Write a note on a door on the minimap."""
+        return self._make_request(
+            "POST", "minimap/notes", {"door_name": door_name, "note": note}
+        )
+
+    def objectives(self) -> Dict[str, Any]:
+        """Get the objectives revealed so far."""
+        return self._make_request("GET", "objectives")
+
+    def postmortem(self) -> Dict[str, Any]:
+        """Get how the player died, once the level has failed."""
+        return self._make_request("GET", "postmortem")
+
     def get_session_info(self) -> Dict[str, Any]:
         """Get session information."""
         return self._make_request("GET", "")
@@ -140,7 +222,7 @@ class GameClient:
             return {"message": "No active session to delete"}
 
         url = f"{self.api_base}/sessions/{self.session_id}"
-        response = requests.delete(url)
+        response = requests.delete(url, headers=HEADERS)
         response.raise_for_status()
 
         result = response.json()
@@ -160,16 +242,33 @@ class GameREPL(cmd.Cmd):
 ║    inspect <item>             - Inspect an item or door      ║
 ║    uncover <item>             - Uncover a concealed item     ║
 ║    unlock <key/code> <target> - Unlock a door or container   ║
-║    search <container>         - Search a container           ║
+║    search <container/enemy>   - Search a container           ║
+║    interrogate <enemy>        - Question a surrendered enemy ║
 ║    take <item>                - Take an item                 ║
 ║    inventory                  - Show your inventory          ║
+║    status                     - Show how you are doing       ║
 ║    heal <item>                - Use a health item            ║
+║    wait [turns]               - Let turns go by              ║
+║    rest [turns]               - Rest to recover health       ║
 ║    go <direction/room>        - Move to another room         ║
+║    listen <door/direction>    - Listen at a door             ║
+║    peek <door/direction>      - Look through a barred door   ║
+║    latch <door/direction>     - Latch a door behind you      ║
 ║    battle <weapon>            - Battle an enemy              ║
-║    combine <item1> <item2>    - Combine two items            ║
+║    intimidate                 - Try to scare off an enemy    ║
+║    bribe <item>               - Buy off an enemy             ║
+║    sneak                      - Try to slip past an enemy    ║
+║    combine <item1> <item2>    - Combine two to four items    ║
 ║    use <item> <target>        - Use an item on a target      ║
+║    move <item> [direction]    - Push or pull furniture       ║
+║    travel <node/room>         - Fast travel to another node  ║
+║    respawn                    - Respawn at last save point   ║
+║    say <command>              - Run a free text command      ║
 ║    context                    - Get comprehensive game state ║
-║    minimap                    - Show minimap of current floor║
+║    minimap                    - Show minimap of all floors   ║
+║    note <room/door> <text>    - Note something on the map    ║
+║    objectives                 - Show your objectives         ║
+║    postmortem                 - Show how you died            ║
 ║    info                       - Show session info            ║
 ║    debug                      - Show debug information       ║
 ║    quit                       - Exit the game                ║
@@ -268,6 +367,19 @@ class GameREPL(cmd.Cmd):
         except Exception as e:
             print(e.response.json().get("error"))
 
+    def do_interrogate(self, arg):
+        """Question an enemy that surrendered."""
+        args = self.parse_args(arg)
+        if not args:
+            print("Usage: interrogate <enemy_name>")
+            return
+
+        try:
+            response = self.client.interrogate(" ".join(args))
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
     def do_take(self, arg):
         """Take an item."""
         args = self.parse_args(arg)
@@ -289,6 +401,14 @@ class GameREPL(cmd.Cmd):
         except Exception as e:
             print(e.response.json().get("error"))
 
+    def do_status(self, arg):
+        """Show how you are doing."""
+        try:
+            response = self.client.status()
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
     def do_heal(self, arg):
         """Use a health item."""
         args = self.parse_args(arg)
@@ -302,6 +422,32 @@ class GameREPL(cmd.Cmd):
         except Exception as e:
             print(e.response.json().get("error"))
 
+    def do_wait(self, arg):
+        """Let turns go by."""
+        args = self.parse_args(arg)
+        if len(args) > 1 or (args and not args[0].isdigit()):
+            print("Usage: wait [turns]")
+            return
+
+        try:
+            response = self.client.wait(int(args[0]) if args else 1)
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
+    def do_rest(self, arg):
+        """Rest to recover health."""
+        args = self.parse_args(arg)
+        if len(args) > 1 or (args and not args[0].isdigit()):
+            print("Usage: rest [turns]")
+            return
+
+        try:
+            response = self.client.rest(int(args[0]) if args else 1)
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
     def do_go(self, arg):
         """Move to another room."""
         args = self.parse_args(arg)
@@ -315,6 +461,45 @@ class GameREPL(cmd.Cmd):
         except Exception as e:
             print(e.response.json().get("error"))
 
+    def do_listen(self, arg):
+        """Listen at a door."""
+        args = self.parse_args(arg)
+        if not args:
+            print("Usage: listen <door_or_direction>")
+            return
+
+        try:
+            response = self.client.listen(" ".join(args))
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
+    def do_peek(self, arg):
+        """Look through a barred door."""
+        args = self.parse_args(arg)
+        if not args:
+            print("Usage: peek <door_or_direction>")
+            return
+
+        try:
+            response = self.client.peek(" ".join(args))
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
+    def do_latch(self, arg):
+        """Latch a door."""
+        args = self.parse_args(arg)
+        if not args:
+            print("Usage: latch <door_or_direction>")
+            return
+
+        try:
+            response = self.client.latch(" ".join(args))
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
     def do_battle(self, arg):
         """Battle an enemy."""
         args = self.parse_args(arg)
@@ -328,17 +513,45 @@ class GameREPL(cmd.Cmd):
         except Exception as e:
             print(e.response.json().get("error"))
 
+    def do_intimidate(self, arg):
+        """Try to scare off the enemy you are fighting."""
+        try:
+            response = self.client.intimidate()
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
+    def do_bribe(self, arg):
+        """Buy off the enemy you are fighting with an item."""
+        args = self.parse_args(arg)
+        if not args:
+            print("Usage: bribe <item_name>")
+            return
+
+        try:
+            response = self.client.bribe(" ".join(args))
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
+    def do_sneak(self, arg):
+        """Try to slip away from the enemy you are fighting."""
+        try:
+            response = self.client.sneak()
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
     def do_combine(self, arg):
-        """Combine two items to craft something new."""
+        """Combine two to four items to craft something new."""
         args = self.parse_args(arg)
-        if len(args) != 2:
-            print("Usage: combine <item1_name> <item2_name>")
+        if not 2 <= len(args) <= 4:
+            print("Usage: combine <item1_name> <item2_name> [<item3_name> <item4_name>]")
             print('Example: combine "wire" "battery"')
             return
 
-        item1_name, item2_name = args
         try:
-            response = self.client.combine(item1_name, item2_name)
+            response = self.client.combine(*args)
             self.print_response(response)
         except Exception as e:
             print(e.response.json().get("error"))
@@ -358,22 +571,107 @@ class GameREPL(cmd.Cmd):
         except Exception as e:
             print(e.response.json().get("error"))
 
+    def do_move(self, arg):
+        """Move a piece of furniture, optionally in a direction such as push, pull or left."""
+        args = self.parse_args(arg)
+        if not 1 <= len(args) <= 2:
+            print("Usage: move <item_name> [<direction>]")
+            print('Example: move "bookcase" push')
+            return
+
+        try:
+            response = self.client.move(*args)
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
+    def do_travel(self, arg):
+        """Fast travel from the node in this room to another node on its network."""
+        args = self.parse_args(arg)
+        if len(args) != 1:
+            print("Usage: travel <node_name>")
+            print('Example: travel "roof lift"')
+            return
+
+        try:
+            response = self.client.travel(args[0])
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
+    def do_respawn(self, arg):
+        """Respawn at the last save point room after dying."""
+        try:
+            response = self.client.respawn()
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
+    def do_say(self, arg):
+        """Run a free text command: say take the brass key from the desk"""
+        if not arg.strip():
+            print("Usage: say <command>")
+            return
+
+        try:
+            response = self.client.command(arg.strip())
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
     def do_context(self, arg):
-        """Get comprehensive game context (room info + inventory)."""
+        """Get comprehensive game context: context [brief|full]"""
         try:
-            response = self.client.context()
+            response = self.client.context(arg.strip() or "full")
             self.print_response(response)
         except Exception as e:
             print(e.response.json().get("error"))
 
     def do_minimap(self, arg):
-        """Show minimap of the current floor."""
+        """Show the minimap of every floor."""
         try:
             response = self.client.minimap()
             self.print_response(response)
         except Exception as e:
             print(e.response.json().get("error"))
 
+    def do_note(self, arg):
+        """Write a note on a room or door on the map, or rub it out with no text."""
+        args = self.parse_args(arg)
+        if not args:
+            print("Usage: note <room_or_door_name> [text]")
+            return
+
+        name, note = args[0], " ".join(args[1:])
+        try:
+            response = self.client.annotate_room(name, note)
+        except Exception as e:
+            if e.response.status_code != 404:
+                print(e.response.json().get("error"))
+                return
+            try:
+                response = self.client.annotate_door(name, note)
+            except Exception as e:
+                print(e.response.json().get("error"))
+                return
+        self.print_response(response)
+
+    def do_objectives(self, arg):
+        """Show the objectives revealed so far."""
+        try:
+            response = self.client.objectives()
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
+    def do_postmortem(self, arg):
+        """Show how the player died, once the level has failed."""
+        try:
+            response = self.client.postmortem()
+            self.print_response(response)
+        except Exception as e:
+            print(e.response.json().get("error"))
+
     def do_info(self, arg):
         """Show session information."""
         try:
@@ -440,7 +738,7 @@ Examples:
 
     # Check if server is running
     try:
-        response = requests.get(f"{SERVER_URL}/api/v1/sessions", timeout=5)
+        response = requests.get(f"{SERVER_URL}/api/v1/sessions", headers=HEADERS, timeout=5)
         response.raise_for_status()
     except requests.exceptions.RequestException as e:
         print(f"Error: Could not connect to server at {SERVER_URL}")